@@ -0,0 +1,25 @@
+package bundle
+
+import (
+	"github.com/fission/fission-workflows/pkg/admission"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagAdmissionURL           = "admission.url"
+	FlagAdmissionTimeout       = "admission.timeout"
+	FlagAdmissionFailurePolicy = "admission.failure-policy"
+)
+
+// ParseAdmissionConfig returns the configuration of the admission webhook, or nil if no webhook URL was provided.
+func ParseAdmissionConfig(c *cli.Context) *admission.Config {
+	url := c.String(FlagAdmissionURL)
+	if len(url) == 0 {
+		return nil
+	}
+	return &admission.Config{
+		URL:           url,
+		Timeout:       c.Duration(FlagAdmissionTimeout),
+		FailurePolicy: c.String(FlagAdmissionFailurePolicy),
+	}
+}
@@ -0,0 +1,57 @@
+package bundle
+
+import (
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/archive"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagArchiveDir      = "archive.dir"
+	FlagArchiveAfter    = "archive.after"
+	FlagArchiveInterval = "archive.interval"
+)
+
+// ArchiveConfig configures the archival of finished invocations to cold storage.
+type ArchiveConfig struct {
+	// Dir is the directory in which the event logs of the archived invocations are stored.
+	Dir string
+
+	api.ArchiveConfig
+}
+
+// ParseArchiveConfig returns the configuration of the archival of invocations, or nil if no archive directory has been
+// provided.
+func ParseArchiveConfig(c *cli.Context) *ArchiveConfig {
+	dir := c.String(FlagArchiveDir)
+	if len(dir) == 0 {
+		return nil
+	}
+	return &ArchiveConfig{
+		Dir: dir,
+		ArchiveConfig: api.ArchiveConfig{
+			After:    c.Duration(FlagArchiveAfter),
+			Interval: c.Duration(FlagArchiveInterval),
+		},
+	}
+}
+
+// setupArchiver creates the archiver of the invocations, which archives to a file-based archive.
+func setupArchiver(config ArchiveConfig, es fes.Backend, invocations *store.Invocations) *api.Archiver {
+	if _, ok := es.(fes.EventDeleter); !ok {
+		log.Fatal("Archiving invocations requires an event store that supports deletes")
+	}
+	cold, err := archive.NewFileStore(config.Dir)
+	if err != nil {
+		log.Fatalf("Failed to setup the invocation archive: %v", err)
+	}
+	if config.After > 0 {
+		log.Infof("Archiving invocations to %s %v after they finished", config.Dir, config.After)
+	} else {
+		log.Infof("Archiving invocations to %s on demand", config.Dir)
+	}
+	return api.NewArchiver(es, invocations, cold, config.ArchiveConfig)
+}
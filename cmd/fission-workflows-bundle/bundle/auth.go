@@ -0,0 +1,86 @@
+package bundle
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/fission/fission-workflows/pkg/auth"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagAuthJWTSecret        = "auth.jwt.secret"
+	FlagAuthJWTPublicKeyFile = "auth.jwt.public-key-file"
+	FlagAuthJWTIssuer        = "auth.jwt.issuer"
+	FlagAuthJWTAudience      = "auth.jwt.audience"
+	FlagAuthJWTGroupsClaim   = "auth.jwt.groups-claim"
+	FlagAuthAllowAnonymous   = "auth.allow-anonymous"
+	FlagAuthPolicy           = "auth.policy"
+
+	AuthPolicyAllowAll = "allow-all"
+	AuthPolicyOwner    = "owner"
+)
+
+// AuthConfig configures the authentication and authorization of the workflow and invocation APIs.
+type AuthConfig struct {
+	// JWT configures the authentication of requests with JWTs. If nil, requests are not authenticated.
+	JWT *auth.JWTConfig
+
+	// Policy is the authorization policy: AuthPolicyAllowAll (default) or AuthPolicyOwner.
+	Policy string
+}
+
+// ParseAuthConfig returns the configuration of the authentication and authorization of the APIs.
+func ParseAuthConfig(c *cli.Context) (*AuthConfig, error) {
+	config := &AuthConfig{
+		Policy: c.String(FlagAuthPolicy),
+	}
+	secret := c.String(FlagAuthJWTSecret)
+	publicKeyFile := c.String(FlagAuthJWTPublicKeyFile)
+	if len(secret) > 0 || len(publicKeyFile) > 0 {
+		config.JWT = &auth.JWTConfig{
+			Secret:         []byte(secret),
+			Issuer:         c.String(FlagAuthJWTIssuer),
+			Audience:       c.String(FlagAuthJWTAudience),
+			GroupsClaim:    c.String(FlagAuthJWTGroupsClaim),
+			AllowAnonymous: c.Bool(FlagAuthAllowAnonymous),
+		}
+		if len(publicKeyFile) > 0 {
+			publicKey, err := ioutil.ReadFile(publicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read JWT public key: %v", err)
+			}
+			config.JWT.PublicKey = publicKey
+		}
+	}
+	return config, nil
+}
+
+// setupAuth creates the authenticator and authorizer of the APIs. Without configuration, requests are not
+// authenticated and all actions are allowed.
+func setupAuth(config *AuthConfig) (auth.Authenticator, auth.Authorizer, error) {
+	if config == nil {
+		return auth.NoopAuthenticator{}, auth.AllowAll{}, nil
+	}
+
+	var authenticator auth.Authenticator = auth.NoopAuthenticator{}
+	if config.JWT != nil {
+		jwtAuthenticator, err := auth.NewJWTAuthenticator(*config.JWT)
+		if err != nil {
+			return nil, nil, err
+		}
+		authenticator = jwtAuthenticator
+	}
+
+	var authorizer auth.Authorizer
+	switch config.Policy {
+	case "", AuthPolicyAllowAll:
+		authorizer = auth.AllowAll{}
+	case AuthPolicyOwner:
+		authorizer = auth.OwnerOnly{}
+	default:
+		return nil, nil, fmt.Errorf("unknown authorization policy '%s' (expected '%s' or '%s')", config.Policy,
+			AuthPolicyAllowAll, AuthPolicyOwner)
+	}
+	return authenticator, authorizer, nil
+}
@@ -10,10 +10,15 @@ import (
 	"os"
 	"time"
 
+	"github.com/fission/fission-workflows/pkg/admission"
 	"github.com/fission/fission-workflows/pkg/api"
 	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/api/store"
 	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/auth"
+	"github.com/fission/fission-workflows/pkg/callback"
+	"github.com/fission/fission-workflows/pkg/cloudevents"
+	"github.com/fission/fission-workflows/pkg/configmap"
 	"github.com/fission/fission-workflows/pkg/controller"
 	"github.com/fission/fission-workflows/pkg/controller/executor"
 	"github.com/fission/fission-workflows/pkg/controller/expr"
@@ -23,9 +28,12 @@ import (
 	"github.com/fission/fission-workflows/pkg/fes/cache"
 	"github.com/fission/fission-workflows/pkg/fnenv"
 	"github.com/fission/fission-workflows/pkg/fnenv/fission"
+	"github.com/fission/fission-workflows/pkg/fnenv/inputref"
 	"github.com/fission/fission-workflows/pkg/fnenv/native"
 	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
+	"github.com/fission/fission-workflows/pkg/fnenv/recording"
 	"github.com/fission/fission-workflows/pkg/fnenv/workflows"
+	"github.com/fission/fission-workflows/pkg/redact"
 	"github.com/fission/fission-workflows/pkg/scheduler"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/util"
@@ -39,6 +47,7 @@ import (
 	grpc_opentracing "github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 	"github.com/uber/jaeger-client-go"
@@ -60,6 +69,9 @@ const (
 	invocationStorePollInterval  = time.Second
 	workflowSubscriptionBuffer   = 50
 	invocationSubscriptionBuffer = 1000
+
+	FlagDeferredBinding = "invocation.deferred-binding"
+	FlagDefaultTimeout  = "invocation.default-timeout"
 )
 
 type App struct {
@@ -95,8 +107,25 @@ func (app *App) Close() error {
 type Options struct {
 	NATS                 *nats.Config
 	Scheduler            scheduler.Policy
+	InvocationConfig     controller.InvocationConfig
+	ControllerProfile    controller.ProfileSettings
 	Fission              *FissionOptions
 	FissionProxy         *FissionProxyConfig
+	Callback             *callback.Config
+	CloudEvents          *cloudevents.Config
+	Admission            *admission.Config
+	ConfigMaps           *configmap.Config
+	Redaction            *RedactionConfig
+	InputRefs            *inputref.Config
+	Signing              *SigningConfig
+	Recording            *recording.Config
+	Archive              *ArchiveConfig
+	OutputHash           string
+	WorkflowCacheTTL     time.Duration
+	DeferredBinding      time.Duration
+	DefaultTimeout       time.Duration
+	AdminToken           string
+	Auth                 *AuthConfig
 	InternalRuntime      bool
 	InvocationController bool
 	WorkflowController   bool
@@ -112,6 +141,17 @@ type FissionOptions struct {
 	ExecutorAddress string
 	ControllerAddr  string
 	RouterAddr      string
+
+	// EnvConcurrency contains the maximum number of concurrent calls to the functions of each Fission environment.
+	EnvConcurrency map[string]int
+
+	// MalformedOutput is the policy for handling function responses of which the body cannot be deserialized:
+	// fail-task (default), raw-string or retry.
+	MalformedOutput string
+
+	// Health configures the health probe of the router and executor, which pauses the submission of tasks to Fission
+	// while it is unreachable. If nil, the health of Fission is not probed.
+	Health *fission.HealthConfig
 }
 
 // Run serves enabled components in a blocking way
@@ -169,14 +209,21 @@ func Run(ctx context.Context, opts *Options) error {
 		otOpts = append(otOpts, grpc_opentracing.LogPayloads())
 	}
 
+	authenticator, authorizer, err := setupAuth(opts.Auth)
+	if err != nil {
+		log.Fatalf("Failed to set up API authentication: %v", err)
+	}
+
 	grpcServer := grpc.NewServer(
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
 			grpc_prometheus.StreamServerInterceptor,
 			grpc_opentracing.OpenTracingStreamServerInterceptor(tracer, otOpts...),
+			auth.StreamServerInterceptor(authenticator),
 		)),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			grpc_prometheus.UnaryServerInterceptor,
 			grpc_opentracing.OpenTracingServerInterceptor(tracer, otOpts...),
+			auth.UnaryServerInterceptor(authenticator),
 		)),
 	)
 
@@ -207,14 +254,27 @@ func Run(ctx context.Context, opts *Options) error {
 	invocationStore := getInvocationStore(app, esPub, eventStore)
 	workflowStore := getWorkflowStore(app, esPub, eventStore)
 
+	var archiver *api.Archiver
+	if opts.Archive != nil {
+		archiver = setupArchiver(*opts.Archive, es, invocationStore)
+		ps.Register(archiver)
+	}
+
 	//
 	// Function Runtimes
 	//
 	invocationAPI := api.NewInvocationAPI(es)
+	invocationAPI.SetDefaultTimeout(opts.DefaultTimeout)
 	resolvers := map[string]fnenv.RuntimeResolver{}
 	runtimes := map[string]fnenv.Runtime{}
 	reflectiveRuntime := workflows.NewRuntime(invocationAPI, invocationStore, workflowStore)
-	if opts.InternalRuntime || opts.Fission != nil {
+	reflectiveRuntime.SetDeferredBinding(opts.DeferredBinding > 0)
+	if opts.WorkflowCacheTTL > 0 {
+		workflowCache := store.NewWorkflowCache(workflowStore, opts.WorkflowCacheTTL)
+		ps.Register(workflowCache)
+		reflectiveRuntime.SetWorkflowCache(workflowCache)
+	}
+	if opts.InternalRuntime || opts.Fission != nil || opts.Recording != nil {
 		log.Infof("Using function runtime: Workflow")
 		runtimes[workflows.Name] = reflectiveRuntime
 	} else {
@@ -223,10 +283,12 @@ func Run(ctx context.Context, opts *Options) error {
 	if opts.InternalRuntime {
 		log.Infof("Using function runtime: Internal")
 		internalRuntime := setupInternalFunctionRuntime()
-		runtimes["internal"] = internalRuntime
-		resolvers["internal"] = internalRuntime
+		runtimes[builtin.Runtime] = internalRuntime
+		resolvers[builtin.Runtime] = internalRuntime
 		log.Infof("Internal runtime functions: %v", internalRuntime.Installed())
 	}
+	var inputRefs *inputref.Store
+	var fissionHealth *fission.HealthProbe
 	if opts.Fission != nil {
 		log.WithFields(log.Fields{
 			"controller": opts.Fission.ControllerAddr,
@@ -234,9 +296,50 @@ func Run(ctx context.Context, opts *Options) error {
 			"executor":   opts.Fission.ExecutorAddress,
 		}).Infof("Using function runtime: Fission")
 		fissionFnenv := setupFissionFunctionRuntime(opts.Fission)
+		if opts.InputRefs != nil {
+			if !opts.HTTPGateway && !opts.Metrics {
+				log.Fatal("Input references require the HTTP server (enable the HTTP gateway or metrics)")
+			}
+			var err error
+			inputRefs, err = inputref.NewStore(*opts.InputRefs)
+			if err != nil {
+				log.Fatalf("Failed to setup input references: %v", err)
+			}
+			fissionFnenv.SetInputReferences(inputRefs)
+			log.Infof("Passing function inputs larger than %d bytes by reference", opts.InputRefs.Threshold)
+		}
+		if opts.Signing != nil {
+			verifier, err := setupSigningVerifier(ctx, *opts.Signing)
+			if err != nil {
+				log.Fatalf("Failed to setup response signing: %v", err)
+			}
+			fissionFnenv.SetVerifier(verifier)
+			log.Infof("Verifying the signed responses of functions: %v", opts.Signing.Functions)
+		}
+		malformedOutput, err := fission.ParseMalformedOutputPolicy(opts.Fission.MalformedOutput)
+		if err != nil {
+			log.Fatalf("Failed to setup the Fission runtime: %v", err)
+		}
+		fissionFnenv.SetMalformedOutputPolicy(malformedOutput)
+		if len(opts.Fission.EnvConcurrency) > 0 {
+			opts.InvocationConfig.EnvLimits = controller.NewEnvironmentLimits(opts.Fission.EnvConcurrency,
+				fissionFnenv)
+			log.Infof("Limiting the concurrent calls to Fission environments: %v", opts.Fission.EnvConcurrency)
+		}
+		if opts.Fission.Health != nil {
+			fissionHealth = setupFissionHealthProbe(opts.Fission)
+			ps.Register(fissionHealth)
+			if opts.InvocationConfig.RuntimeHealth == nil {
+				opts.InvocationConfig.RuntimeHealth = controller.NewRuntimeHealth()
+			}
+			opts.InvocationConfig.RuntimeHealth.Register(fission.Name, fissionHealth)
+		}
 		runtimes["fission"] = fissionFnenv
 		resolvers["fission"] = fissionFnenv
 	}
+	if opts.Recording != nil {
+		setupRecording(*opts.Recording, runtimes, resolvers)
+	}
 
 	//
 	// Scheduler
@@ -246,6 +349,22 @@ func Run(ctx context.Context, opts *Options) error {
 	//
 	// Controllers
 	//
+	// The expression state, the function suspensions, the concurrency locks and the per-workflow metrics of the
+	// invocation controller are exposed through the admin API for diagnostics and maintenance.
+	var stateStore *expr.Store
+	var reevaluator apiserver.Reevaluator
+	var suspensions *controller.FunctionSuspensions
+	var locks *controller.ConcurrencyLocks
+	var workflowMetrics *controller.WorkflowMetrics
+	var vault *redact.Vault
+	if opts.InvocationController {
+		stateStore = expr.NewStore()
+		suspensions = controller.NewFunctionSuspensions()
+		opts.InvocationConfig.Suspensions = suspensions
+		locks = controller.NewConcurrencyLocks()
+		opts.InvocationConfig.Concurrency = locks
+		workflowMetrics = opts.InvocationConfig.WorkflowMetrics
+	}
 	if opts.WorkflowController {
 		log.Info("Running workflow controller")
 		workflowCtrl := setupWorkflowController(workflowStore, es, resolvers)
@@ -260,7 +379,43 @@ func Run(ctx context.Context, opts *Options) error {
 	}
 	if opts.InvocationController {
 		log.Info("Running invocation controller")
-		invocationCtrl := setupInvocationController(invocationStore, es, runtimes, resolvers, sched)
+		if opts.ConfigMaps != nil {
+			resolver, err := setupConfigMapResolver(*opts.ConfigMaps)
+			if err != nil {
+				log.Fatalf("Failed to setup config map resolver: %v", err)
+			}
+			log.Infof("Allowing tasks to reference config maps %v in namespace %s", opts.ConfigMaps.Allowed,
+				opts.ConfigMaps.Namespace)
+			opts.InvocationConfig.ConfigMaps = resolver
+		}
+		if opts.Redaction != nil {
+			var err error
+			vault, err = setupRedactionVault(*opts.Redaction)
+			if err != nil {
+				log.Fatalf("Failed to setup redaction vault: %v", err)
+			}
+			log.Info("Keeping the original values of redacted task outputs in the redaction vault")
+		}
+		if len(opts.OutputHash) > 0 {
+			log.Infof("Storing the %s content hash of each task output", opts.OutputHash)
+		}
+		invocationCtrl, err := setupInvocationController(invocationStore, es, runtimes, resolvers, sched, stateStore,
+			vault, opts.OutputHash, opts.ControllerProfile, opts.InvocationConfig)
+		if err != nil {
+			log.Fatalf("Failed to setup invocation controller: %v", err)
+		}
+		reevaluator = invocationCtrl
+		if fissionHealth != nil {
+			// Re-evaluate the invocations with tasks waiting on Fission as soon as it has recovered.
+			runtimeHealth := opts.InvocationConfig.RuntimeHealth
+			fissionHealth.OnRecover(func() {
+				for _, invocationID := range runtimeHealth.Waiting(fission.Name) {
+					if _, _, err := invocationCtrl.Reevaluate(invocationID); err != nil {
+						log.Warnf("Failed to re-evaluate invocation %s: %v", invocationID, err)
+					}
+				}
+			})
+		}
 		go invocationCtrl.Run()
 		defer func() {
 			if err := invocationCtrl.Close(); err != nil {
@@ -276,19 +431,45 @@ func Run(ctx context.Context, opts *Options) error {
 	//
 	ps.Register(opts.FissionProxy)
 
+	//
+	// Invocation callbacks
+	//
+	if opts.Callback != nil {
+		ps.Register(callback.NewSender(invocationStore, *opts.Callback))
+	}
+
+	//
+	// CloudEvents
+	//
+	if opts.CloudEvents != nil {
+		publisher, err := cloudevents.NewPublisher(invocationStore, *opts.CloudEvents)
+		if err != nil {
+			log.Fatalf("Failed to set up the CloudEvents publisher: %v", err)
+		}
+		ps.Register(publisher)
+	}
+
 	//
 	// gRPC API
 	//
 	if opts.AdminAPI {
-		serveAdminAPI(grpcServer)
+		serveAdminAPI(grpcServer, stateStore, reevaluator, suspensions, locks, workflowMetrics, vault,
+			opts.AdminToken)
 	}
 
 	if opts.WorkflowAPI {
-		serveWorkflowAPI(grpcServer, es, resolvers, workflowStore)
+		serveWorkflowAPI(grpcServer, es, resolvers, workflowStore, authorizer)
 	}
 
 	if opts.InvocationAPI {
-		serveInvocationAPI(grpcServer, es, invocationStore, workflowStore)
+		var admitter api.Admitter
+		if opts.Admission != nil {
+			log.Infof("Admitting invocations using webhook %s (failure policy: %s)", opts.Admission.URL,
+				opts.Admission.FailurePolicy)
+			admitter = admission.NewWebhook(*opts.Admission)
+		}
+		serveInvocationAPI(grpcServer, es, resolvers, invocationStore, workflowStore, admitter, authorizer,
+			opts.DeferredBinding, opts.DefaultTimeout, archiver)
 	}
 
 	if opts.AdminAPI || opts.WorkflowAPI || opts.InvocationAPI {
@@ -314,7 +495,7 @@ func Run(ctx context.Context, opts *Options) error {
 	// HTTP API
 	//
 	if opts.HTTPGateway || opts.Metrics {
-		grpcMux := grpcruntime.NewServeMux()
+		grpcMux := grpcruntime.NewServeMux(grpcruntime.WithMetadata(apiserver.ForwardQueryMetadata))
 		httpMux := http.NewServeMux()
 
 		if opts.HTTPGateway {
@@ -337,8 +518,22 @@ func Run(ctx context.Context, opts *Options) error {
 			log.Infof("Set up prometheus collector: %v/metrics", apiGatewayAddress)
 		}
 
+		var gatewayHandler http.Handler = handlers.LoggingHandler(os.Stdout, tracingWrapper(grpcMux))
+		if opts.HTTPGateway && opts.InvocationAPI {
+			// The invocation streams are served outside of the logging handler, which does not support flushing.
+			stream := apiserver.NewInvocationStream(invocationStore, apiserver.DefaultStreamHeartbeat)
+			stream.SetAuthenticator(authenticator)
+			gatewayHandler = stream.Handler(gatewayHandler)
+			log.Infof("Serving invocation streams at: %v/invocation/{id}/stream", apiGatewayAddress)
+		}
+
+		if inputRefs != nil {
+			httpMux.Handle(inputref.PathPrefix, inputRefs)
+			log.Infof("Serving input references at: %v%s{id}", apiGatewayAddress, inputref.PathPrefix)
+		}
+
 		httpApiSrv := &http.Server{Addr: apiGatewayAddress}
-		httpMux.Handle("/", handlers.LoggingHandler(os.Stdout, tracingWrapper(grpcMux)))
+		httpMux.Handle("/", gatewayHandler)
 		httpApiSrv.Handler = httpMux
 		go func() {
 			err := httpApiSrv.ListenAndServe()
@@ -380,6 +575,13 @@ func setupFissionFunctionRuntime(fissionOpts *FissionOptions) *fission.FunctionE
 	return fission.New(fissionOpts.ExecutorAddress, fissionOpts.ControllerAddr, fissionOpts.RouterAddr)
 }
 
+func setupFissionHealthProbe(fissionOpts *FissionOptions) *fission.HealthProbe {
+	probe := fission.NewHealthProbe(fissionOpts.RouterAddr, fissionOpts.ExecutorAddress, *fissionOpts.Health)
+	log.Infof("Pausing the submission of tasks to Fission after %d failed health probes (interval: %v)",
+		fissionOpts.Health.FailureThreshold, fissionOpts.Health.Interval)
+	return probe
+}
+
 func setupNatsEventStoreClient(config nats.Config) *nats.EventStore {
 	if config.Client == "" {
 		config.Client = util.UID()
@@ -440,24 +642,35 @@ func setupWorkflowCache(app *App, workflowEventPub pubsub.Publisher, backend fes
 	return c
 }
 
-func serveAdminAPI(s *grpc.Server) {
-	adminServer := &apiserver.Admin{}
+func serveAdminAPI(s *grpc.Server, stateStore *expr.Store, reevaluator apiserver.Reevaluator,
+	suspensions *controller.FunctionSuspensions, locks *controller.ConcurrencyLocks,
+	workflowMetrics *controller.WorkflowMetrics, vault *redact.Vault, token string) {
+	adminServer := apiserver.NewAdmin(stateStore, reevaluator, suspensions, locks, workflowMetrics, vault, token)
 	apiserver.RegisterAdminAPIServer(s, adminServer)
 	log.Infof("Serving admin gRPC API at %s.", gRPCAddress)
 }
 
 func serveWorkflowAPI(s *grpc.Server, es fes.Backend, resolvers map[string]fnenv.RuntimeResolver,
-	store *store.Workflows) {
+	store *store.Workflows, authorizer auth.Authorizer) {
 	workflowParser := fnenv.NewMetaResolver(resolvers)
 	workflowAPI := api.NewWorkflowAPI(es, workflowParser)
 	workflowServer := apiserver.NewWorkflow(workflowAPI, store, es)
+	workflowServer.SetAuthorizer(authorizer)
 	apiserver.RegisterWorkflowAPIServer(s, workflowServer)
 	log.Infof("Serving workflow gRPC API at %s.", gRPCAddress)
 }
 
-func serveInvocationAPI(s *grpc.Server, es fes.Backend, invocations *store.Invocations, workflows *store.Workflows) {
+func serveInvocationAPI(s *grpc.Server, es fes.Backend, resolvers map[string]fnenv.RuntimeResolver,
+	invocations *store.Invocations, workflows *store.Workflows, admitter api.Admitter, authorizer auth.Authorizer,
+	deferredBinding time.Duration, defaultTimeout time.Duration, archiver *api.Archiver) {
 	invocationAPI := api.NewInvocationAPI(es)
+	invocationAPI.SetAdmitter(admitter)
+	invocationAPI.SetDefaultTimeout(defaultTimeout)
 	invocationServer := apiserver.NewInvocation(invocationAPI, invocations, workflows, es)
+	invocationServer.SetAuthorizer(authorizer)
+	invocationServer.SetDeferredBinding(deferredBinding)
+	invocationServer.SetResolver(fnenv.NewMetaResolver(resolvers))
+	invocationServer.SetArchiver(archiver)
 	apiserver.RegisterWorkflowInvocationAPIServer(s, invocationServer)
 	log.Infof("Serving workflow invocation gRPC API at %s.", gRPCAddress)
 }
@@ -498,15 +711,27 @@ func serveHTTPGateway(ctx context.Context, mux *grpcruntime.ServeMux, adminAPIAd
 
 func setupInvocationController(invocations *store.Invocations, es fes.Backend,
 	fnRuntimes map[string]fnenv.Runtime, fnResolvers map[string]fnenv.RuntimeResolver,
-	s *scheduler.InvocationScheduler) *controller.InvocationMetaController {
+	s *scheduler.InvocationScheduler, stateStore *expr.Store, vault *redact.Vault, outputHash string,
+	profile controller.ProfileSettings, config controller.InvocationConfig) (*controller.InvocationMetaController,
+	error) {
 
 	workflowAPI := api.NewWorkflowAPI(es, fnenv.NewMetaResolver(fnResolvers))
 	invocationAPI := api.NewInvocationAPI(es)
 	dynamicAPI := api.NewDynamicApi(workflowAPI, invocationAPI)
 	taskAPI := api.NewTaskAPI(fnRuntimes, es, dynamicAPI)
-	stateStore := expr.NewStore()
-	localExec := executor.NewLocalExecutor(executorMaxParallelism, executorMaxTaskQueueSize)
-	return controller.NewInvocationMetaController(localExec, invocations, invocationAPI, taskAPI, s, stateStore, invocationStorePollInterval)
+	taskAPI.SetVault(vault)
+	taskAPI.SetOutputHash(outputHash)
+	config.Quarantines.SetQuarantiner(workflowAPI)
+	workers, pollInterval := profile.ExecutorWorkers, profile.PollInterval
+	if workers <= 0 {
+		workers = executorMaxParallelism
+	}
+	if pollInterval <= 0 {
+		pollInterval = invocationStorePollInterval
+	}
+	localExec := executor.NewLocalExecutor(workers, executorMaxTaskQueueSize)
+	return controller.NewInvocationMetaController(localExec, invocations, invocationAPI, taskAPI, s, stateStore,
+		pollInterval, config)
 }
 
 func setupWorkflowController(store *store.Workflows, es fes.Backend,
@@ -517,7 +742,10 @@ func setupWorkflowController(store *store.Workflows, es fes.Backend,
 }
 
 func setupMetricsEndpoint(apiMux *http.ServeMux) {
-	apiMux.Handle("/metrics", promhttp.Handler())
+	// Serve the OpenMetrics format to the scrapers that request it, which includes the exemplars that link the latency
+	// histograms to traces.
+	apiMux.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})))
 }
 
 var grpcGatewayTag = opentracing.Tag{Key: string(ext.Component), Value: "grpc-gateway"}
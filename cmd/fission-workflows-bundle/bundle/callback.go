@@ -0,0 +1,36 @@
+package bundle
+
+import (
+	"github.com/fission/fission-workflows/pkg/callback"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagCallbackURL         = "callback.url"
+	FlagCallbackDestination = "callback.destination"
+	FlagCallbackOrdered     = "callback.ordered"
+	FlagCallbackTimeout     = "callback.timeout"
+	FlagCallbackMaxAttempts = "callback.max-attempts"
+	FlagCallbackLenient     = "callback.lenient-events"
+	FlagCallbackWorkers     = "callback.workers"
+	FlagCallbackConcurrency = "callback.concurrency"
+)
+
+// ParseCallbackConfig returns the configuration of the invocation callbacks, or nil if no callback URL was provided.
+func ParseCallbackConfig(c *cli.Context) *callback.Config {
+	url := c.String(FlagCallbackURL)
+	destinations := c.StringSlice(FlagCallbackDestination)
+	if len(url) == 0 && len(destinations) == 0 {
+		return nil
+	}
+	return &callback.Config{
+		URL:           url,
+		Destinations:  destinations,
+		Ordered:       c.Bool(FlagCallbackOrdered),
+		Workers:       c.Int(FlagCallbackWorkers),
+		Concurrency:   c.Int(FlagCallbackConcurrency),
+		Timeout:       c.Duration(FlagCallbackTimeout),
+		MaxAttempts:   c.Int(FlagCallbackMaxAttempts),
+		LenientEvents: c.Bool(FlagCallbackLenient),
+	}
+}
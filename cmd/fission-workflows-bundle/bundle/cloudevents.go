@@ -0,0 +1,31 @@
+package bundle
+
+import (
+	"github.com/fission/fission-workflows/pkg/cloudevents"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagCloudEventsSink        = "cloudevents.sink"
+	FlagCloudEventsSource      = "cloudevents.source"
+	FlagCloudEventsType        = "cloudevents.type"
+	FlagCloudEventsTimeout     = "cloudevents.timeout"
+	FlagCloudEventsMaxAttempts = "cloudevents.max-attempts"
+	FlagCloudEventsQueueSize   = "cloudevents.queue-size"
+)
+
+// ParseCloudEventsConfig returns the configuration of the CloudEvents publisher, or nil if no sink was provided.
+func ParseCloudEventsConfig(c *cli.Context) *cloudevents.Config {
+	sink := c.String(FlagCloudEventsSink)
+	if len(sink) == 0 {
+		return nil
+	}
+	return &cloudevents.Config{
+		SinkURL:     sink,
+		Source:      c.String(FlagCloudEventsSource),
+		Types:       c.StringSlice(FlagCloudEventsType),
+		Timeout:     c.Duration(FlagCloudEventsTimeout),
+		MaxAttempts: c.Int(FlagCloudEventsMaxAttempts),
+		QueueSize:   c.Int(FlagCloudEventsQueueSize),
+	}
+}
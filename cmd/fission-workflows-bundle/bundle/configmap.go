@@ -0,0 +1,40 @@
+package bundle
+
+import (
+	"github.com/fission/fission-workflows/pkg/configmap"
+	"github.com/urfave/cli"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	FlagConfigMapNamespace = "configmap.namespace"
+	FlagConfigMapAllow     = "configmap.allow"
+	FlagConfigMapCacheTTL  = "configmap.cache-ttl"
+)
+
+// ParseConfigMapConfig returns the configuration of the config map references, or nil if no config maps are allowed
+// to be referenced.
+func ParseConfigMapConfig(c *cli.Context) *configmap.Config {
+	allowed := c.StringSlice(FlagConfigMapAllow)
+	if len(allowed) == 0 {
+		return nil
+	}
+	return &configmap.Config{
+		Namespace: c.String(FlagConfigMapNamespace),
+		Allowed:   allowed,
+		CacheTTL:  c.Duration(FlagConfigMapCacheTTL),
+	}
+}
+
+func setupConfigMapResolver(config configmap.Config) (*configmap.Resolver, error) {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return configmap.NewResolver(&configmap.KubernetesGetter{Client: client}, config), nil
+}
@@ -0,0 +1,226 @@
+package bundle
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/controller"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagControllerMemoryBudget         = "controller.memory-budget"
+	FlagControllerAwaitWorkflowTimeout = "controller.await-workflow-timeout"
+	FlagControllerMaxInFlightTasks     = "controller.max-inflight-tasks"
+	FlagControllerFairQueuing          = "controller.fair-queuing"
+	FlagControllerTenantWeight         = "controller.tenant-weight"
+	FlagControllerOutputPath           = "controller.output-path"
+	FlagControllerMaxErrors            = "controller.max-errors"
+	FlagControllerMaxTaskErrors        = "controller.max-task-errors"
+	FlagControllerFinishedRetention    = "controller.finished-retention"
+	FlagControllerLoadMaxQueueDepth    = "controller.load.max-queue-depth"
+	FlagControllerLoadMaxUtilization   = "controller.load.max-utilization"
+	FlagControllerNoopEvalThreshold    = "controller.noop-eval-threshold"
+	FlagControllerMetricsWorkflows     = "controller.metrics.workflows"
+	FlagControllerMetricsThreshold     = "controller.metrics.workflow-threshold"
+	FlagControllerMetricsLimit         = "controller.metrics.workflow-limit"
+	FlagControllerUpdates              = "controller.updates"
+	FlagControllerPollingInterval      = "controller.polling-interval"
+	FlagControllerMaxLoopIterations    = "controller.max-loop-iterations"
+	FlagControllerReplicaID            = "controller.replica-id"
+	FlagControllerDebugPinning         = "controller.debug.pinning"
+	FlagControllerOutputHash           = "controller.output-hash"
+	FlagControllerTraceSampleRate      = "controller.trace.sample-rate"
+	FlagControllerTraceForceLabel      = "controller.trace.force-label"
+	FlagControllerStateStoreTimeout    = "controller.state-store-timeout"
+	FlagControllerStandby              = "controller.standby"
+	FlagControllerEvalDebounce         = "controller.eval-debounce"
+	FlagControllerStalenessInterval    = "controller.staleness-interval"
+	FlagControllerMaxStaleness         = "controller.max-staleness"
+	FlagControllerWorkflowCacheTTL     = "controller.workflow-cache-ttl"
+	FlagControllerMemoSize             = "controller.memo-size"
+	FlagControllerClockSkewTolerance   = "controller.clock-skew-tolerance"
+	FlagControllerInputMiddleware      = "controller.input-middleware"
+	FlagControllerProfile              = "controller.profile"
+	FlagControllerSLOWindow            = "controller.slo.window"
+	FlagControllerSLOMinSamples        = "controller.slo.min-samples"
+	FlagControllerSLOWebhook           = "controller.slo.webhook"
+	FlagControllerFinalizationRetries  = "controller.finalization.retries"
+	FlagControllerFinalizationBackoff  = "controller.finalization.backoff"
+	FlagControllerQuarantineFailures   = "controller.quarantine.failures"
+	FlagControllerQuarantineWindow     = "controller.quarantine.window"
+)
+
+// ParseOutputHash returns the algorithm of the content hashes of task outputs, or an empty string if no content hashes
+// should be computed.
+func ParseOutputHash(c *cli.Context) string {
+	algorithm := c.String(FlagControllerOutputHash)
+	if len(algorithm) == 0 {
+		return ""
+	}
+	if err := typedvalues.ValidateHashAlgorithm(algorithm); err != nil {
+		log.Fatalf("Invalid --%s: %v", FlagControllerOutputHash, err)
+	}
+	return algorithm
+}
+
+// ParseControllerProfile returns the settings of the profile of the invocation controller.
+func ParseControllerProfile(c *cli.Context) controller.ProfileSettings {
+	profile, err := controller.ParseProfile(c.String(FlagControllerProfile))
+	if err != nil {
+		log.Fatalf("Invalid --%s: %v", FlagControllerProfile, err)
+	}
+	return profile.Settings()
+}
+
+func ParseInvocationControllerConfig(c *cli.Context) controller.InvocationConfig {
+	updates, err := controller.ParseUpdatesMode(c.String(FlagControllerUpdates))
+	if err != nil {
+		log.Fatalf("Invalid --%s: %v", FlagControllerUpdates, err)
+	}
+	var pinning *controller.Pinning
+	if c.Bool(FlagControllerDebugPinning) {
+		pinning = &controller.Pinning{ReplicaID: c.String(FlagControllerReplicaID)}
+		if len(pinning.ReplicaID) == 0 {
+			pinning.ReplicaID, err = os.Hostname()
+			if err != nil {
+				log.Fatalf("Failed to determine the replica ID; set --%s: %v", FlagControllerReplicaID, err)
+			}
+		}
+		log.Warnf("DEBUG: invocation pinning is enabled; this replica (%s) only evaluates the invocations that are "+
+			"not pinned to another replica. Do not use this in production.", pinning.ReplicaID)
+	}
+	var memo *controller.TaskMemo
+	if size := c.Int(FlagControllerMemoSize); size > 0 {
+		memo = controller.NewTaskMemo(size)
+	}
+	inputMiddleware, err := controller.NewInputMiddlewares(c.StringSlice(FlagControllerInputMiddleware))
+	if err != nil {
+		log.Fatalf("Invalid --%s: %v", FlagControllerInputMiddleware, err)
+	}
+	// The debounce window and the staleness settings of the profile can be overridden.
+	profile := ParseControllerProfile(c)
+	if c.IsSet(FlagControllerEvalDebounce) {
+		profile.EvalDebounce = c.Duration(FlagControllerEvalDebounce)
+	}
+	if c.IsSet(FlagControllerStalenessInterval) {
+		profile.StalenessInterval = c.Duration(FlagControllerStalenessInterval)
+	}
+	if c.IsSet(FlagControllerMaxStaleness) {
+		profile.MaxStaleness = c.Duration(FlagControllerMaxStaleness)
+	}
+	return controller.InvocationConfig{
+		MemoryBudget:         c.Int64(FlagControllerMemoryBudget),
+		AwaitWorkflowTimeout: c.Duration(FlagControllerAwaitWorkflowTimeout),
+		Admission:            controller.NewTaskAdmission(c.Int(FlagControllerMaxInFlightTasks)),
+		FairQueuing:          c.Bool(FlagControllerFairQueuing),
+		TenantWeights:        parseTenantWeights(c.StringSlice(FlagControllerTenantWeight)),
+		OutputPaths:          parseOutputPaths(c.StringSlice(FlagControllerOutputPath)),
+		InputMiddleware:      inputMiddleware,
+		Memo:                 memo,
+		ErrorBudget: controller.ErrorBudget{
+			MaxErrors:     c.Int(FlagControllerMaxErrors),
+			MaxTaskErrors: c.Int(FlagControllerMaxTaskErrors),
+		},
+		FinishedRetention: c.Duration(FlagControllerFinishedRetention),
+		EvalDebounce:      profile.EvalDebounce,
+		StalenessInterval: profile.StalenessInterval,
+		MaxStaleness:      profile.MaxStaleness,
+		Load: controller.LoadThresholds{
+			MaxQueueDepth:  c.Int(FlagControllerLoadMaxQueueDepth),
+			MaxUtilization: c.Float64(FlagControllerLoadMaxUtilization),
+		},
+		NoopEvalThreshold:  c.Int(FlagControllerNoopEvalThreshold),
+		Updates:            updates,
+		PollingInterval:    c.Duration(FlagControllerPollingInterval),
+		MaxLoopIterations:  c.Int64(FlagControllerMaxLoopIterations),
+		Pinning:            pinning,
+		TraceSampling:      parseTraceSampling(c),
+		StateStoreTimeout:  c.Duration(FlagControllerStateStoreTimeout),
+		ClockSkewTolerance: c.Duration(FlagControllerClockSkewTolerance),
+		Standby:            c.Bool(FlagControllerStandby),
+		FinalizationRetry: controller.FinalizationRetry{
+			MaxRetries: c.Int(FlagControllerFinalizationRetries),
+			Backoff:    c.Duration(FlagControllerFinalizationBackoff),
+		},
+		SLOs: controller.NewSLOMonitor(controller.SLOConfig{
+			Window:     c.Duration(FlagControllerSLOWindow),
+			MinSamples: c.Int(FlagControllerSLOMinSamples),
+			WebhookURL: c.String(FlagControllerSLOWebhook),
+		}),
+		Quarantines: controller.NewWorkflowQuarantines(controller.QuarantineConfig{
+			Failures: c.Int(FlagControllerQuarantineFailures),
+			Window:   c.Duration(FlagControllerQuarantineWindow),
+		}),
+		WorkflowMetrics: controller.NewWorkflowMetrics(controller.WorkflowMetricsConfig{
+			Workflows: c.StringSlice(FlagControllerMetricsWorkflows),
+			Threshold: c.Int(FlagControllerMetricsThreshold),
+			Limit:     c.Int(FlagControllerMetricsLimit),
+		}),
+	}
+}
+
+// parseTraceSampling returns the sampling of the traces of invocations, or nil if all decisions are left to the
+// sampler of the tracer. The labels that force a trace are formatted as '<key>=<value>' or '<key>'.
+func parseTraceSampling(c *cli.Context) *controller.TraceSampling {
+	rate := c.Float64(FlagControllerTraceSampleRate)
+	if rate < 0 || rate > 1 {
+		log.Fatalf("Invalid --%s: %v is not between 0 and 1", FlagControllerTraceSampleRate, rate)
+	}
+	labels := map[string]string{}
+	for _, flag := range c.StringSlice(FlagControllerTraceForceLabel) {
+		parts := strings.SplitN(flag, "=", 2)
+		if len(parts[0]) == 0 {
+			log.Warnf("Ignoring trace label '%s': expected format '<key>=<value>' or '<key>'", flag)
+			continue
+		}
+		if len(parts) == 2 {
+			labels[parts[0]] = parts[1]
+		} else {
+			labels[parts[0]] = ""
+		}
+	}
+	if rate == 1 && len(labels) == 0 {
+		return nil
+	}
+	return &controller.TraceSampling{
+		Rate:        rate,
+		ForceLabels: labels,
+	}
+}
+
+// parseTenantWeights parses the weights of the tenants, which are formatted as '<tenant>=<weight>'.
+func parseTenantWeights(flags []string) map[string]int {
+	weights := map[string]int{}
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, "=", 2)
+		if len(parts) != 2 {
+			log.Warnf("Ignoring tenant weight '%s': expected format '<tenant>=<weight>'", flag)
+			continue
+		}
+		weight, err := strconv.Atoi(parts[1])
+		if err != nil || weight <= 0 {
+			log.Warnf("Ignoring tenant weight '%s': weight should be a positive integer", flag)
+			continue
+		}
+		weights[parts[0]] = weight
+	}
+	return weights
+}
+
+// parseOutputPaths parses the default output paths of the functions, which are formatted as '<fnRef>=<path>'.
+func parseOutputPaths(flags []string) map[string]string {
+	paths := map[string]string{}
+	for _, flag := range flags {
+		i := strings.LastIndex(flag, "=")
+		if i <= 0 || i == len(flag)-1 {
+			log.Warnf("Ignoring output path '%s': expected format '<fnRef>=<path>'", flag)
+			continue
+		}
+		paths[flag[:i]] = flag[i+1:]
+	}
+	return paths
+}
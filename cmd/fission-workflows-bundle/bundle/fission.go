@@ -0,0 +1,50 @@
+package bundle
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/fnenv/fission"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagFissionEnvConcurrency  = "fission.env-concurrency"
+	FlagFissionMalformedOutput = "fission.malformed-output"
+	FlagFissionHealthInterval  = "fission.health-interval"
+	FlagFissionHealthThreshold = "fission.health-threshold"
+)
+
+// ParseFissionHealthConfig returns the configuration of the health probe of Fission, or nil if the probe has been
+// disabled with a probe interval of 0.
+func ParseFissionHealthConfig(c *cli.Context) *fission.HealthConfig {
+	interval := c.Duration(FlagFissionHealthInterval)
+	if interval <= 0 {
+		return nil
+	}
+	return &fission.HealthConfig{
+		Interval:         interval,
+		FailureThreshold: c.Int(FlagFissionHealthThreshold),
+	}
+}
+
+// ParseEnvConcurrency parses the concurrency limits of the Fission environments, which are formatted as
+// '<environment>=<limit>'.
+func ParseEnvConcurrency(flags []string) map[string]int {
+	limits := map[string]int{}
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			log.Warnf("Ignoring environment concurrency '%s': expected format '<environment>=<limit>'", flag)
+			continue
+		}
+		limit, err := strconv.Atoi(parts[1])
+		if err != nil || limit <= 0 {
+			log.Warnf("Ignoring environment concurrency '%s': limit should be a positive integer", flag)
+			continue
+		}
+		limits[parts[0]] = limit
+	}
+	return limits
+}
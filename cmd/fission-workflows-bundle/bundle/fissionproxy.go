@@ -8,6 +8,7 @@ import (
 
 	"github.com/fission/fission-workflows/pkg/apiserver"
 	"github.com/fission/fission-workflows/pkg/apiserver/fission"
+	"github.com/fission/fission-workflows/pkg/auth"
 	"github.com/gorilla/handlers"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -20,6 +21,9 @@ type FissionProxyConfig struct {
 	WorkflowsAddr  string
 	ExposeMetrics  bool
 
+	// Token is the bearer token with which the proxy authenticates to the APIs, if they require authentication.
+	Token string
+
 	server *http.Server
 }
 
@@ -32,6 +36,7 @@ func ParseFissionProxyConfig(ctx *cli.Context) (*FissionProxyConfig, error) {
 		ProxyAddr:      ctx.String("fission.proxy.addr"),
 		DefaultTimeout: ctx.Duration("fission.proxy.timeout"),
 		ExposeMetrics:  ctx.Bool("metrics"),
+		Token:          ctx.String("fission.proxy.token"),
 	}, nil
 }
 
@@ -40,7 +45,7 @@ func (c *FissionProxyConfig) Run() error {
 		return nil
 	}
 
-	conn, err := grpc.Dial(c.ProxyAddr, grpc.WithInsecure())
+	conn, err := grpc.Dial(c.ProxyAddr, grpc.WithInsecure(), grpc.WithPerRPCCredentials(auth.BearerToken(c.Token)))
 	if err != nil {
 		panic(err)
 	}
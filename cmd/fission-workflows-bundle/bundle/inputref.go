@@ -0,0 +1,25 @@
+package bundle
+
+import (
+	"github.com/fission/fission-workflows/pkg/fnenv/inputref"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagInputRefsURL        = "input-refs.url"
+	FlagInputRefsThreshold  = "input-refs.threshold"
+	FlagInputRefsMaxEntries = "input-refs.max-entries"
+)
+
+// ParseInputRefsConfig returns the configuration of the input references, or nil if no URL was provided.
+func ParseInputRefsConfig(c *cli.Context) *inputref.Config {
+	url := c.String(FlagInputRefsURL)
+	if len(url) == 0 {
+		return nil
+	}
+	return &inputref.Config{
+		URL:        url,
+		Threshold:  c.Int64(FlagInputRefsThreshold),
+		MaxEntries: c.Int(FlagInputRefsMaxEntries),
+	}
+}
@@ -0,0 +1,57 @@
+package bundle
+
+import (
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/recording"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagRecordingMode = "fnenv.recording.mode"
+	FlagRecordingFile = "fnenv.recording.file"
+)
+
+// ParseRecordingConfig returns the configuration of the recording of function interactions, or nil if no recording
+// mode has been provided.
+func ParseRecordingConfig(c *cli.Context) *recording.Config {
+	mode := c.String(FlagRecordingMode)
+	if len(mode) == 0 {
+		return nil
+	}
+	return &recording.Config{
+		Mode: mode,
+		File: c.String(FlagRecordingFile),
+	}
+}
+
+// setupRecording records the interactions with the Fission functions, or replaces the Fission runtime with the
+// replay of recorded interactions, depending on the mode of the config.
+func setupRecording(config recording.Config, runtimes map[string]fnenv.Runtime,
+	resolvers map[string]fnenv.RuntimeResolver) {
+	if err := config.Validate(); err != nil {
+		log.Fatalf("Invalid function recording config: %v", err)
+	}
+	switch config.Mode {
+	case recording.ModeRecord:
+		runtime, ok := runtimes["fission"]
+		if !ok {
+			log.Fatal("Recording function interactions requires the Fission function runtime")
+		}
+		recorder, err := recording.NewRecorder(runtime, resolvers["fission"], config.File)
+		if err != nil {
+			log.Fatalf("Failed to setup the recording of function interactions: %v", err)
+		}
+		runtimes["fission"] = recorder
+		resolvers["fission"] = recorder
+		log.Infof("Recording the interactions with Fission functions to %s", config.File)
+	case recording.ModeReplay:
+		replayer, err := recording.NewReplayer(config.File)
+		if err != nil {
+			log.Fatalf("Failed to load the recorded function interactions: %v", err)
+		}
+		runtimes["fission"] = replayer
+		resolvers["fission"] = replayer
+		log.Infof("Replaying the recorded interactions with Fission functions from %s", config.File)
+	}
+}
@@ -0,0 +1,43 @@
+package bundle
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/fission/fission-workflows/pkg/redact"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagRedactionKey        = "redaction.key"
+	FlagRedactionMaxEntries = "redaction.max-entries"
+)
+
+// RedactionConfig configures the vault in which the original values of redacted output fields are kept.
+type RedactionConfig struct {
+	// Key is the base64-encoded AES key (16, 24 or 32 bytes) used to encrypt the values.
+	Key string
+
+	// MaxEntries is the number of tasks of which the values are retained.
+	MaxEntries int
+}
+
+// ParseRedactionConfig returns the configuration of the redaction vault, or nil if no key was provided.
+func ParseRedactionConfig(c *cli.Context) *RedactionConfig {
+	key := c.String(FlagRedactionKey)
+	if len(key) == 0 {
+		return nil
+	}
+	return &RedactionConfig{
+		Key:        key,
+		MaxEntries: c.Int(FlagRedactionMaxEntries),
+	}
+}
+
+func setupRedactionVault(config RedactionConfig) (*redact.Vault, error) {
+	key, err := base64.StdEncoding.DecodeString(config.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction key: %v", err)
+	}
+	return redact.NewVault(key, redact.NewMemoryStore(config.MaxEntries))
+}
@@ -23,6 +23,10 @@ var schedulerPolicies = map[string]func(time.Duration) scheduler.Policy{
 		return scheduler.Policy(scheduler.NewPrewarmHorizonPolicy(coldStartModel))
 	},
 	"horizon": func(_ time.Duration) scheduler.Policy { return scheduler.Policy(scheduler.NewHorizonPolicy()) },
+	"critical-path": func(_ time.Duration) scheduler.Policy {
+		return scheduler.Policy(scheduler.NewCriticalPathPolicy(scheduler.NewTaskStats(),
+			scheduler.DefaultEstimatedTaskDuration))
+	},
 }
 
 func ParseSchedulerConfig(c *cli.Context) (scheduler.Policy, error) {
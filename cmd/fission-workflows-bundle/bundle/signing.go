@@ -0,0 +1,97 @@
+package bundle
+
+import (
+	"context"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fnenv/signing"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const (
+	FlagSigningFunction       = "signing.function"
+	FlagSigningKey            = "signing.key"
+	FlagSigningKeyFile        = "signing.key-file"
+	FlagSigningReloadInterval = "signing.reload-interval"
+)
+
+// SigningConfig configures the verification of the signatures of function responses.
+type SigningConfig struct {
+	// Functions contains the functions that are required to sign their responses.
+	Functions []string
+
+	// Keys contains the base64-encoded keys that are accepted.
+	Keys []string
+
+	// KeyFile is the path of a file with the base64-encoded keys that are accepted, one per line. The keys are
+	// accepted in addition to Keys, and are reloaded every ReloadInterval to pick up rotated keys.
+	KeyFile        string
+	ReloadInterval time.Duration
+}
+
+// ParseSigningConfig returns the configuration of the response signing, or nil if no function requires it.
+func ParseSigningConfig(c *cli.Context) *SigningConfig {
+	functions := c.StringSlice(FlagSigningFunction)
+	if len(functions) == 0 {
+		return nil
+	}
+	return &SigningConfig{
+		Functions:      functions,
+		Keys:           c.StringSlice(FlagSigningKey),
+		KeyFile:        c.String(FlagSigningKeyFile),
+		ReloadInterval: c.Duration(FlagSigningReloadInterval),
+	}
+}
+
+// setupSigningVerifier creates the verifier of the function responses. If a key file is configured, the keys are
+// reloaded periodically until the context is canceled.
+func setupSigningVerifier(ctx context.Context, config SigningConfig) (*signing.Verifier, error) {
+	loadKeys := func() ([][]byte, error) {
+		keys, err := signing.ParseKeys(config.Keys)
+		if err != nil {
+			return nil, err
+		}
+		if len(config.KeyFile) > 0 {
+			fileKeys, err := signing.LoadKeyFile(config.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, fileKeys...)
+		}
+		return keys, nil
+	}
+	keys, err := loadKeys()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := signing.NewVerifier(signing.Config{
+		Keys:      keys,
+		Functions: config.Functions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(config.KeyFile) > 0 && config.ReloadInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(config.ReloadInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					keys, err := loadKeys()
+					if err == nil {
+						err = verifier.SetKeys(keys)
+					}
+					if err != nil {
+						log.Errorf("Failed to reload signing keys; keeping the current keys: %v", err)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	return verifier, nil
+}
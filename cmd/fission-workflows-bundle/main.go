@@ -5,11 +5,24 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/fission/fission-workflows/cmd/fission-workflows-bundle/bundle"
+	"github.com/fission/fission-workflows/pkg/admission"
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/auth"
+	"github.com/fission/fission-workflows/pkg/callback"
+	"github.com/fission/fission-workflows/pkg/cloudevents"
+	"github.com/fission/fission-workflows/pkg/configmap"
+	"github.com/fission/fission-workflows/pkg/controller"
 	"github.com/fission/fission-workflows/pkg/fes/backend/nats"
+	"github.com/fission/fission-workflows/pkg/fnenv/fission"
+	"github.com/fission/fission-workflows/pkg/fnenv/inputref"
+	"github.com/fission/fission-workflows/pkg/redact"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/util"
 	natsio "github.com/nats-io/go-nats"
 	"github.com/sirupsen/logrus"
@@ -46,10 +59,17 @@ func main() {
 			logrus.Fatal("Error while parsing Fission Proxy: ", err)
 		}
 
+		authConfig, err := bundle.ParseAuthConfig(c)
+		if err != nil {
+			logrus.Fatal("Error while parsing API authentication: ", err)
+		}
+
 		return bundle.Run(ctx, &bundle.Options{
 			NATS:                 parseNatsOptions(c),
 			Fission:              parseFissionOptions(c),
 			Scheduler:            policy,
+			InvocationConfig:     bundle.ParseInvocationControllerConfig(c),
+			ControllerProfile:    bundle.ParseControllerProfile(c),
 			InternalRuntime:      c.Bool("internal"),
 			InvocationController: c.Bool("controller") || c.Bool("invocation-controller"),
 			WorkflowController:   c.Bool("controller") || c.Bool("workflow-controller"),
@@ -60,6 +80,21 @@ func main() {
 			Metrics:              c.Bool("metrics"),
 			Debug:                c.Bool("debug"),
 			FissionProxy:         proxyConfig,
+			Callback:             bundle.ParseCallbackConfig(c),
+			CloudEvents:          bundle.ParseCloudEventsConfig(c),
+			Admission:            bundle.ParseAdmissionConfig(c),
+			ConfigMaps:           bundle.ParseConfigMapConfig(c),
+			Redaction:            bundle.ParseRedactionConfig(c),
+			InputRefs:            bundle.ParseInputRefsConfig(c),
+			Signing:              bundle.ParseSigningConfig(c),
+			Recording:            bundle.ParseRecordingConfig(c),
+			Archive:              bundle.ParseArchiveConfig(c),
+			OutputHash:           bundle.ParseOutputHash(c),
+			WorkflowCacheTTL:     c.Duration(bundle.FlagControllerWorkflowCacheTTL),
+			DeferredBinding:      c.Duration(bundle.FlagDeferredBinding),
+			DefaultTimeout:       c.Duration(bundle.FlagDefaultTimeout),
+			AdminToken:           c.String("admin-token"),
+			Auth:                 authConfig,
 		})
 	}
 	cliApp.Run(os.Args)
@@ -82,6 +117,9 @@ func parseFissionOptions(c *cli.Context) *bundle.FissionOptions {
 		ExecutorAddress: c.String("fission-executor"),
 		ControllerAddr:  c.String("fission-controller"),
 		RouterAddr:      c.String("fission-router"),
+		EnvConcurrency:  bundle.ParseEnvConcurrency(c.StringSlice(bundle.FlagFissionEnvConcurrency)),
+		MalformedOutput: c.String(bundle.FlagFissionMalformedOutput),
+		Health:          bundle.ParseFissionHealthConfig(c),
 	}
 }
 
@@ -152,6 +190,11 @@ func createCli() *cli.App {
 			Usage: "The timeout assigned to workflow invocations coming from the Fission proxy",
 			Value: ":8888",
 		},
+		cli.StringFlag{
+			Name:   "fission.proxy.token",
+			Usage:  "Bearer token with which the Fission proxy authenticates to the APIs",
+			EnvVar: "WORKFLOWS_FISSION_PROXY_TOKEN",
+		},
 
 		// Fission Function Runtime
 		cli.BoolFlag{
@@ -176,6 +219,28 @@ func createCli() *cli.App {
 			Value:  "http://router.fission",
 			EnvVar: "FNENV_FISSION_ROUTER",
 		},
+		cli.StringSliceFlag{
+			Name: bundle.FlagFissionEnvConcurrency,
+			Usage: "Maximum number of concurrent calls to the functions of a Fission environment, formatted as " +
+				"'<environment>=<limit>' (default: unlimited)",
+		},
+		cli.StringFlag{
+			Name: bundle.FlagFissionMalformedOutput,
+			Usage: "Policy for function responses of which the body cannot be deserialized according to its " +
+				"content type: fail-task, raw-string (store the body as a string) or retry (treat as transient)",
+			Value: "fail-task",
+		},
+		cli.DurationFlag{
+			Name: bundle.FlagFissionHealthInterval,
+			Usage: "Interval at which the health of the Fission router and executor is probed, to pause the " +
+				"submission of tasks while Fission is unreachable (0 = disabled)",
+			Value: fission.DefaultHealthProbeInterval,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagFissionHealthThreshold,
+			Usage: "Number of consecutive failed health probes after which Fission is considered unreachable",
+			Value: fission.DefaultHealthFailureThreshold,
+		},
 
 		// Components
 		cli.BoolFlag{
@@ -186,6 +251,190 @@ func createCli() *cli.App {
 			Name:  "controller",
 			Usage: "Run the controller with all components",
 		},
+		cli.Int64Flag{
+			Name:  bundle.FlagControllerMemoryBudget,
+			Usage: "Maximum size (in bytes) of the task outputs retained by a single invocation (0 = unlimited)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerAwaitWorkflowTimeout,
+			Usage: "Maximum duration to wait for the workflow of a task to become ready",
+			Value: 10 * time.Second,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerMaxInFlightTasks,
+			Usage: "Maximum number of concurrent task executions across all invocations (0 = unlimited)",
+		},
+		cli.BoolFlag{
+			Name:  bundle.FlagControllerFairQueuing,
+			Usage: "Evaluate the invocations of tenants (identified by the 'tenant' label) in a weighted round-robin",
+		},
+		cli.StringSliceFlag{
+			Name:  bundle.FlagControllerTenantWeight,
+			Usage: "Weight of a tenant for fair queuing, formatted as '<tenant>=<weight>' (default weight = 1)",
+		},
+		cli.StringSliceFlag{
+			Name:  bundle.FlagControllerOutputPath,
+			Usage: "Path of the value to extract from the outputs of a function, formatted as '<fnRef>=<path>'",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerMaxErrors,
+			Usage: "Number of task errors across an invocation at which the invocation is failed (0 = unlimited)",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerMaxTaskErrors,
+			Usage: "Number of errors of a single task at which the invocation is failed (0 = unlimited)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerFinishedRetention,
+			Usage: "Grace period during which finished invocations are retained by the controller to ignore late events",
+			Value: 30 * time.Second,
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerEvalDebounce,
+			Usage: "Window within which the evaluations of an invocation are merged (0 = disabled; overrides the profile)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerStalenessInterval,
+			Usage: "Interval at which the controller checks for stale invocations (overrides the profile)",
+		},
+		cli.DurationFlag{
+			Name: bundle.FlagControllerMaxStaleness,
+			Usage: "Time without an evaluation after which an unfinished invocation is re-evaluated (overrides the " +
+				"profile)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerSLOWindow,
+			Usage: "Rolling window over which the SLOs of tasks are evaluated, unless the SLO has a window of its own",
+			Value: controller.DefaultSLOWindow,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerSLOMinSamples,
+			Usage: "Minimum number of runs of a task within the window before its SLO is evaluated",
+			Value: controller.DefaultSLOMinSamples,
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagControllerSLOWebhook,
+			Usage: "URL to which the breaches and recoveries of the SLOs of tasks are posted",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerFinalizationRetries,
+			Usage: "Number of retries of the finalization of an invocation after which its failures are reported",
+			Value: controller.DefaultFinalizationRetries,
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerFinalizationBackoff,
+			Usage: "Delay before the first retry of the finalization of an invocation, which doubles with every retry",
+			Value: controller.DefaultFinalizationBackoff,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerQuarantineFailures,
+			Usage: "Number of consecutive failed invocations of a workflow after which it is quarantined (0 = disabled)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerQuarantineWindow,
+			Usage: "Window within which the consecutive failed invocations of a workflow are counted",
+			Value: controller.DefaultQuarantineWindow,
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagControllerProfile,
+			Usage: "Profile of the invocation controller: default, low-latency or high-throughput",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagDeferredBinding,
+			Usage: "Max duration to wait for the workflow of an invocation to be created (0 = reject unknown workflows)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagDefaultTimeout,
+			Usage: "Timeout of the invocations of workflows that do not specify a timeout (0 = no timeout)",
+		},
+		cli.StringSliceFlag{
+			Name:  bundle.FlagControllerInputMiddleware,
+			Usage: "Input middleware to apply to the inputs of all tasks, in order (can be repeated)",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerMemoSize,
+			Usage: "Maximum number of memoized outputs of pure tasks (0 = disabled)",
+			Value: controller.DefaultMemoSize,
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerWorkflowCacheTTL,
+			Usage: "Duration for which the workflows of sub-workflow invocations are cached (0 = disabled)",
+			Value: store.DefaultWorkflowCacheTTL,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerLoadMaxQueueDepth,
+			Usage: "Evaluation queue depth above which low-priority invocations are deferred (0 = disabled)",
+		},
+		cli.Float64Flag{
+			Name:  bundle.FlagControllerLoadMaxUtilization,
+			Usage: "Fraction (0-1) of busy executor workers above which low-priority invocations are deferred (0 = disabled)",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerNoopEvalThreshold,
+			Usage: "Number of consecutive evaluations of an invocation without any action after which a warning is logged",
+			Value: controller.DefaultNoopEvalThreshold,
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagControllerUpdates,
+			Usage: "How the invocation controller learns about invocation updates: auto, push (require notifications) or polling",
+			Value: string(controller.UpdatesModeAuto),
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerPollingInterval,
+			Usage: "Interval at which the invocations are polled in the polling updates mode",
+			Value: controller.DefaultPollingInterval,
+		},
+		cli.Int64Flag{
+			Name:  bundle.FlagControllerMaxLoopIterations,
+			Usage: "Max number of loop iterations per invocation across all (nested) loops (0 = unlimited)",
+		},
+		cli.StringFlag{
+			Name:   bundle.FlagControllerReplicaID,
+			Usage:  "ID of this controller replica, which invocations can be pinned to (default: hostname)",
+			EnvVar: "WORKFLOWS_REPLICA_ID",
+		},
+		cli.BoolFlag{
+			Name:  bundle.FlagControllerDebugPinning,
+			Usage: "Debugging only: honor the 'debug.replica' label that pins an invocation to a controller replica",
+		},
+		cli.StringFlag{
+			Name: bundle.FlagControllerOutputHash,
+			Usage: "Algorithm (" + strings.Join(typedvalues.HashAlgorithms(), ", ") + ") of the content hash that is " +
+				"stored with each task output (disabled if empty)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerStateStoreTimeout,
+			Usage: "Max duration of a read of the expression state store, after which the task is deferred (0 = unbounded)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagControllerClockSkewTolerance,
+			Usage: "Max clock skew between nodes that is tolerated in timeouts, which are enforced up to this late",
+		},
+		cli.BoolFlag{
+			Name:  bundle.FlagControllerStandby,
+			Usage: "Start the invocation controller without evaluating any invocation, until another instance hands them off",
+		},
+		cli.Float64Flag{
+			Name:  bundle.FlagControllerTraceSampleRate,
+			Usage: "Fraction (0-1) of the invocations that are traced, which is applied before the sampler of the tracer",
+			Value: 1,
+		},
+		cli.StringSliceFlag{
+			Name:  bundle.FlagControllerTraceForceLabel,
+			Usage: "Label, formatted as '<key>=<value>' or '<key>', of invocations that are always traced (can be repeated)",
+		},
+		cli.StringSliceFlag{
+			Name:  bundle.FlagControllerMetricsWorkflows,
+			Usage: "ID of a workflow that has its own series in the per-workflow metrics (can be repeated)",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerMetricsThreshold,
+			Usage: "Number of finished invocations after which a workflow gets its own metrics series (0 = disabled)",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagControllerMetricsLimit,
+			Usage: "Maximum number of workflows that get their own series by exceeding the threshold (0 = unlimited)",
+		},
 		cli.BoolFlag{
 			Name:  "workflow-controller",
 			Usage: "Run the workflow controller",
@@ -218,11 +467,227 @@ func createCli() *cli.App {
 			Name:  "api",
 			Usage: "Shortcut for serving all APIs over both gRPC and HTTP",
 		},
+		cli.StringFlag{
+			Name:   "admin-token",
+			Usage:  "Bearer token required by the diagnostic functions of the admin API (disabled if empty)",
+			EnvVar: "WORKFLOWS_ADMIN_TOKEN",
+		},
+
+		// Invocation callbacks
+		cli.StringFlag{
+			Name:  bundle.FlagCallbackURL,
+			Usage: "URL to send invocation callbacks to (disabled if empty)",
+		},
+		cli.StringSliceFlag{
+			Name:  bundle.FlagCallbackDestination,
+			Usage: "Additional URL to send invocation callbacks to (repeatable)",
+		},
+		cli.BoolFlag{
+			Name: bundle.FlagCallbackOrdered,
+			Usage: "Deliver the callbacks to each destination strictly in order, blocking until each callback has " +
+				"been acknowledged",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagCallbackWorkers,
+			Usage: "Maximum number of concurrent callback requests across all destinations",
+			Value: callback.DefaultWorkers,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagCallbackConcurrency,
+			Usage: "Maximum number of callbacks that are delivered concurrently to a single destination (unordered only)",
+			Value: callback.DefaultConcurrency,
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagCallbackTimeout,
+			Usage: "Timeout of a single callback delivery attempt",
+			Value: callback.DefaultTimeout,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagCallbackMaxAttempts,
+			Usage: "Maximum number of attempts to deliver a callback (ordered callbacks are retried until delivered)",
+			Value: callback.DefaultMaxAttempts,
+		},
+		cli.BoolFlag{
+			Name:  bundle.FlagCallbackLenient,
+			Usage: "Silently ignore events of unknown types, instead of logging and metering them",
+		},
+
+		// CloudEvents
+		cli.StringFlag{
+			Name:  bundle.FlagCloudEventsSink,
+			Usage: "URL of the sink to publish invocation lifecycle transitions to as CloudEvents (disabled if empty)",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagCloudEventsSource,
+			Usage: "Source attribute of the published CloudEvents",
+			Value: cloudevents.DefaultSource,
+		},
+		cli.StringSliceFlag{
+			Name:  bundle.FlagCloudEventsType,
+			Usage: "Type of the CloudEvents to publish (repeatable; all types if not set)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagCloudEventsTimeout,
+			Usage: "Timeout of a single CloudEvent delivery attempt",
+			Value: cloudevents.DefaultTimeout,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagCloudEventsMaxAttempts,
+			Usage: "Maximum number of attempts to deliver a CloudEvent",
+			Value: cloudevents.DefaultMaxAttempts,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagCloudEventsQueueSize,
+			Usage: "Maximum number of CloudEvents awaiting delivery, beyond which events are dropped",
+			Value: cloudevents.DefaultQueueSize,
+		},
+
+		// Admission webhook
+		cli.StringFlag{
+			Name:  bundle.FlagAdmissionURL,
+			Usage: "URL of the webhook that admits invocations before they are created (disabled if empty)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagAdmissionTimeout,
+			Usage: "Timeout of an admission request",
+			Value: admission.DefaultTimeout,
+		},
+		cli.StringFlag{
+			Name: bundle.FlagAdmissionFailurePolicy,
+			Usage: "Whether to reject ('" + admission.FailurePolicyFail + "') or allow ('" +
+				admission.FailurePolicyIgnore + "') invocations if the admission webhook fails",
+			Value: admission.FailurePolicyFail,
+		},
+
+		// Output redaction
+		cli.StringFlag{
+			Name: bundle.FlagRedactionKey,
+			Usage: "Base64-encoded AES key (16, 24 or 32 bytes) to keep the original values of redacted task outputs " +
+				"encrypted for retrieval through the admin API (discarded if empty)",
+			EnvVar: "WORKFLOWS_REDACTION_KEY",
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagRedactionMaxEntries,
+			Usage: "Maximum number of tasks of which the original values of redacted outputs are kept",
+			Value: redact.DefaultMaxEntries,
+		},
+
+		// Input references
+		cli.StringFlag{
+			Name: bundle.FlagInputRefsURL,
+			Usage: "Externally reachable URL of the HTTP server, used by functions to fetch inputs passed by " +
+				"reference (inputs are always inlined if empty)",
+			EnvVar: "WORKFLOWS_INPUT_REFS_URL",
+		},
+		cli.Int64Flag{
+			Name:  bundle.FlagInputRefsThreshold,
+			Usage: "Size in bytes above which inputs are passed by reference to the functions that support it",
+			Value: inputref.DefaultThreshold,
+		},
+		cli.IntFlag{
+			Name:  bundle.FlagInputRefsMaxEntries,
+			Usage: "Maximum number of inputs passed by reference that are retained",
+			Value: inputref.DefaultMaxEntries,
+		},
+
+		// API authentication
+		cli.StringFlag{
+			Name:   bundle.FlagAuthJWTSecret,
+			Usage:  "Shared secret of the HMAC-signed JWTs that authenticate API requests",
+			EnvVar: "WORKFLOWS_AUTH_JWT_SECRET",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagAuthJWTPublicKeyFile,
+			Usage: "File with the PEM-encoded RSA or ECDSA public key of the JWTs that authenticate API requests",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagAuthJWTIssuer,
+			Usage: "Issuer that the JWTs are required to have (not checked if empty)",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagAuthJWTAudience,
+			Usage: "Audience that the JWTs are required to have (not checked if empty)",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagAuthJWTGroupsClaim,
+			Usage: "Claim of the JWTs that contains the groups of the principal",
+			Value: auth.DefaultGroupsClaim,
+		},
+		cli.BoolFlag{
+			Name:  bundle.FlagAuthAllowAnonymous,
+			Usage: "Allow API requests without a JWT, which are made by an anonymous principal",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagAuthPolicy,
+			Usage: "Authorization policy of the API requests (allow-all or owner)",
+			Value: bundle.AuthPolicyAllowAll,
+		},
+
+		// Response signing
+		cli.StringSliceFlag{
+			Name:  bundle.FlagSigningFunction,
+			Usage: "Function that is required to sign its responses (repeatable)",
+		},
+		cli.StringSliceFlag{
+			Name:   bundle.FlagSigningKey,
+			Usage:  "Base64-encoded key that is accepted for response signatures (repeatable, to rotate keys)",
+			EnvVar: "WORKFLOWS_SIGNING_KEYS",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagSigningKeyFile,
+			Usage: "File with base64-encoded keys that are accepted for response signatures, one per line",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagSigningReloadInterval,
+			Usage: "Interval at which the keys in the signing key file are reloaded (0 = never)",
+			Value: time.Minute,
+		},
+
+		// Recording of function interactions
+		cli.StringFlag{
+			Name:  bundle.FlagRecordingMode,
+			Usage: "Record the interactions with Fission functions to, or replay them from, the fixture file (record or replay)",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagRecordingFile,
+			Usage: "Fixture file of the recorded function interactions",
+		},
+
+		// Archival of finished invocations
+		cli.StringFlag{
+			Name:  bundle.FlagArchiveDir,
+			Usage: "Directory to archive the event logs of finished invocations to (enables archiving)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagArchiveAfter,
+			Usage: "Time since invocations finished after which they are archived in the background (0 = on demand only)",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagArchiveInterval,
+			Usage: "Interval at which the background archiver looks for invocations to archive",
+			Value: api.DefaultArchiveInterval,
+		},
+
+		// Config map references
+		cli.StringSliceFlag{
+			Name:  bundle.FlagConfigMapAllow,
+			Usage: "Name of a config map that tasks are allowed to reference in their inputs (repeatable)",
+		},
+		cli.StringFlag{
+			Name:  bundle.FlagConfigMapNamespace,
+			Usage: "Namespace of the config maps that can be referenced",
+			Value: "fission-function",
+		},
+		cli.DurationFlag{
+			Name:  bundle.FlagConfigMapCacheTTL,
+			Usage: "Duration to cache the contents of a config map",
+			Value: configmap.DefaultCacheTTL,
+		},
 
 		// Scheduler
 		cli.StringFlag{
 			Name:  bundle.FlagSchedulerPolicy,
-			Usage: "Policy to use for the scheduler (prewarm-all, prewarm-horizon, horizon)",
+			Usage: "Policy to use for the scheduler (prewarm-all, prewarm-horizon, horizon, critical-path)",
 			Value: "horizon",
 		},
 		cli.DurationFlag{
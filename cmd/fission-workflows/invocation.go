@@ -6,10 +6,13 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/fission/fission-workflows/pkg/apiserver"
 	"github.com/fission/fission-workflows/pkg/apiserver/httpclient"
+	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
 	"github.com/fission/fission-workflows/pkg/parse/yaml"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/golang/protobuf/jsonpb"
@@ -56,15 +59,14 @@ var cmdInvocation = cli.Command{
 				default:
 					wfiID := ctx.Args().Get(0)
 					taskID := ctx.Args().Get(1)
-					wfi, err := client.Invocation.Get(ctx, wfiID)
+					ti, err := client.Invocation.GetTask(ctx, wfiID, taskID)
 					if err != nil {
+						if strings.Contains(err.Error(), "not found") {
+							fmt.Println("Task Invocation not found.")
+							return nil
+						}
 						panic(err)
 					}
-					ti, ok := wfi.Status.Tasks[taskID]
-					if !ok {
-						fmt.Println("Task Invocation not found.")
-						return nil
-					}
 					b, err := yaml.Marshal(ti)
 					if err != nil {
 						panic(err)
@@ -88,6 +90,174 @@ var cmdInvocation = cli.Command{
 				return nil
 			}),
 		},
+		{
+			Name:  "replay",
+			Usage: "replay <invocation-id>",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "override",
+					Usage: "Execute a replayed task with another function (format: <task-id>=<function>).",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows invocation replay <invocation-id>")
+				}
+				client := getClient(ctx)
+				wfiID := ctx.Args().First()
+
+				overrides := map[string]string{}
+				for _, override := range ctx.StringSlice("override") {
+					parts := strings.SplitN(override, "=", 2)
+					if len(parts) != 2 {
+						logrus.Fatalf("Invalid override '%s' (expected <task-id>=<function>)", override)
+					}
+					overrides[parts[0]] = parts[1]
+				}
+
+				resp, err := client.Invocation.ReplayFailedTasks(ctx, wfiID, overrides)
+				if err != nil {
+					logrus.Fatalf("Failed to replay %s: %v", wfiID, err)
+				}
+				fmt.Printf("Replaying tasks of %s: %s\n", wfiID, strings.Join(resp.GetTaskIds(), ", "))
+				return nil
+			}),
+		},
+		{
+			Name:  "purge",
+			Usage: "purge --older-than <duration>",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "older-than",
+					Usage: "Only purge invocations that finished at least this long ago (e.g. 72h).",
+				},
+				cli.StringFlag{
+					Name:  "workflow",
+					Usage: "Only purge invocations of this workflow.",
+				},
+				cli.StringSliceFlag{
+					Name:  "label",
+					Usage: "Only purge invocations with this label (format: <key>=<value>).",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "List the invocations that would be purged without deleting them.",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if len(ctx.String("older-than")) == 0 {
+					logrus.Fatal("Usage: fission-workflows invocation purge --older-than <duration>")
+				}
+				client := getClient(ctx)
+				req := &apiserver.InvocationPurgeRequest{
+					WorkflowId: ctx.String("workflow"),
+					OlderThan:  ctx.String("older-than"),
+					DryRun:     ctx.Bool("dry-run"),
+				}
+				for _, label := range ctx.StringSlice("label") {
+					parts := strings.SplitN(label, "=", 2)
+					if len(parts) != 2 {
+						logrus.Fatalf("Invalid label '%s' (expected <key>=<value>)", label)
+					}
+					if req.Labels == nil {
+						req.Labels = map[string]string{}
+					}
+					req.Labels[parts[0]] = parts[1]
+				}
+
+				resp, err := client.Invocation.Purge(ctx, req)
+				if err != nil {
+					logrus.Fatalf("Failed to purge invocations: %v", err)
+				}
+				for _, id := range resp.GetInvocations() {
+					fmt.Println(id)
+				}
+				if resp.GetDryRun() {
+					fmt.Printf("Would purge %d invocations (skipped %d unfinished invocations)\n", resp.GetCount(),
+						resp.GetSkipped())
+				} else {
+					fmt.Printf("Purged %d invocations (skipped %d unfinished invocations)\n", resp.GetCount(),
+						resp.GetSkipped())
+				}
+				return nil
+			}),
+		},
+		{
+			Name:  "archive",
+			Usage: "archive --older-than <duration>",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "older-than",
+					Usage: "Only archive invocations that finished at least this long ago (e.g. 72h).",
+				},
+				cli.StringFlag{
+					Name:  "workflow",
+					Usage: "Only archive invocations of this workflow.",
+				},
+				cli.StringSliceFlag{
+					Name:  "label",
+					Usage: "Only archive invocations with this label (format: <key>=<value>).",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "List the invocations that would be archived without archiving them.",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if len(ctx.String("older-than")) == 0 {
+					logrus.Fatal("Usage: fission-workflows invocation archive --older-than <duration>")
+				}
+				client := getClient(ctx)
+				req := &apiserver.InvocationArchiveRequest{
+					WorkflowId: ctx.String("workflow"),
+					OlderThan:  ctx.String("older-than"),
+					DryRun:     ctx.Bool("dry-run"),
+				}
+				for _, label := range ctx.StringSlice("label") {
+					parts := strings.SplitN(label, "=", 2)
+					if len(parts) != 2 {
+						logrus.Fatalf("Invalid label '%s' (expected <key>=<value>)", label)
+					}
+					if req.Labels == nil {
+						req.Labels = map[string]string{}
+					}
+					req.Labels[parts[0]] = parts[1]
+				}
+
+				resp, err := client.Invocation.Archive(ctx, req)
+				if err != nil {
+					logrus.Fatalf("Failed to archive invocations: %v", err)
+				}
+				for _, id := range resp.GetInvocations() {
+					fmt.Println(id)
+				}
+				if resp.GetDryRun() {
+					fmt.Printf("Would archive %d invocations (skipped %d unfinished invocations)\n", resp.GetCount(),
+						resp.GetSkipped())
+				} else {
+					fmt.Printf("Archived %d invocations (skipped %d unfinished invocations)\n", resp.GetCount(),
+						resp.GetSkipped())
+				}
+				return nil
+			}),
+		},
+		{
+			Name:  "restore",
+			Usage: "restore <invocation-id>",
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows invocation restore <invocation-id>")
+				}
+				client := getClient(ctx)
+				wfiID := ctx.Args().First()
+				wi, err := client.Invocation.Restore(ctx, wfiID)
+				if err != nil {
+					logrus.Fatalf("Failed to restore invocation %s: %v", wfiID, err)
+				}
+				fmt.Printf("Restored invocation %s (status: %s)\n", wi.ID(), wi.GetStatus().GetStatus())
+				return nil
+			}),
+		},
 		{
 			Name:  "events",
 			Usage: "events <invocation-id>",
@@ -116,6 +286,28 @@ var cmdInvocation = cli.Command{
 				return nil
 			}),
 		},
+		{
+			Name:  "workflow",
+			Usage: "workflow <invocation-id>",
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows invocation workflow <invocation-id>")
+				}
+				client := getClient(ctx)
+				wfiID := ctx.Args().First()
+
+				spec, err := client.Invocation.GetResolvedWorkflow(ctx, wfiID)
+				if err != nil {
+					logrus.Fatalf("Failed to retrieve the resolved workflow of %s: %v", wfiID, err)
+				}
+				b, err := yaml.Marshal(spec)
+				if err != nil {
+					panic(err)
+				}
+				fmt.Printf("%v\n", string(b))
+				return nil
+			}),
+		},
 		{
 			Name:  "status",
 			Usage: "status <Workflow-Invocation-id> ",
@@ -126,7 +318,7 @@ var cmdInvocation = cli.Command{
 				client := getClient(ctx)
 				wfiID := ctx.Args().First()
 
-				wfi, err := client.Invocation.Get(ctx, wfiID)
+				wfi, err := client.Invocation.GetTruncated(ctx, wfiID, apiserver.DefaultMaxOutputBytes)
 				if err != nil {
 					logrus.Fatalf("Failed to retrieve status for %s: %v", wfiID, err)
 				}
@@ -172,7 +364,7 @@ func invocationsList(out io.Writer, wfiAPI *httpclient.InvocationAPI, since time
 
 	var invocations []*types.WorkflowInvocation
 	for _, wfiID := range wis.Invocations {
-		wi, err := wfiAPI.Get(ctx, wfiID)
+		wi, err := wfiAPI.GetTruncated(ctx, wfiID, apiserver.DefaultMaxOutputBytes)
 		if err != nil {
 			panic(err)
 		}
@@ -227,6 +419,10 @@ func collectStatus(tasks map[string]*types.TaskSpec, taskStatus map[string]*type
 		taskStatus, ok := taskStatus[id]
 		if ok {
 			status = taskStatus.Status.Status.String()
+			fnRef := taskStatus.GetSpec().GetFnRef()
+			if width, ok := builtin.ForeachWidth(fnRef, taskStatus.GetStatus().GetOutput()); ok {
+				status = fmt.Sprintf("%s (width %d)", status, width)
+			}
 			updated = ptypes.TimestampString(taskStatus.Status.UpdatedAt)
 			started = ptypes.TimestampString(taskStatus.Metadata.CreatedAt)
 		}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/urfave/cli"
 )
@@ -23,6 +24,12 @@ var cmdStatus = cli.Command{
 		//	os.Exit(1)
 		//}
 		fmt.Printf(resp.Status)
+		if len(resp.InvocationUpdates) > 0 {
+			fmt.Printf(" (invocation updates: %s)", resp.InvocationUpdates)
+		}
+		if len(resp.Degraded) > 0 {
+			fmt.Printf(" (degraded: %s)", strings.Join(resp.Degraded, ", "))
+		}
 
 		return nil
 	}),
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
 
 	"github.com/blang/semver"
+	"github.com/fission/fission-workflows/pkg/controller"
 	"github.com/fission/fission-workflows/pkg/parse"
 	"github.com/fission/fission-workflows/pkg/parse/yaml"
 	"github.com/golang/protobuf/jsonpb"
@@ -77,6 +79,42 @@ var cmdWorkflow = cli.Command{
 				return nil
 			}),
 		},
+		{
+			Name:  "clear-quarantine",
+			Usage: "clear-quarantine <workflow-id...>",
+			Description: "Clear the quarantine of workflows that were quarantined after consecutive failed invocations, " +
+				"which allows the workflows to be invoked again.",
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows workflow clear-quarantine <workflow-id...>")
+				}
+				client := getClient(ctx)
+				for _, wfID := range ctx.Args() {
+					if err := client.Workflow.ClearQuarantine(ctx, wfID); err != nil {
+						logrus.Fatalf("Failed to clear the quarantine of %s: %v", wfID, err)
+					}
+					fmt.Println(wfID)
+				}
+				return nil
+			}),
+		},
+		{
+			Name:  "dashboard",
+			Usage: "dashboard <workflow-id>",
+			Description: "Generate a Grafana dashboard of the per-workflow metrics of the workflow. The dashboard only " +
+				"shows data if the workflow has its own series in the per-workflow metrics of the controller.",
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows workflow dashboard <workflow-id>")
+				}
+				dashboard, err := json.MarshalIndent(controller.WorkflowDashboard(ctx.Args().First()), "", "  ")
+				if err != nil {
+					logrus.Fatalf("Failed to generate dashboard: %v", err)
+				}
+				fmt.Println(string(dashboard))
+				return nil
+			}),
+		},
 		{
 			Name:  "events",
 			Usage: "events <workflow-id>",
@@ -129,8 +167,11 @@ var cmdWorkflow = cli.Command{
 						updated, _ := ptypes.Timestamp(wf.Status.UpdatedAt)
 						created, _ := ptypes.Timestamp(wf.Metadata.CreatedAt)
 
-						rows = append(rows, []string{wfID, wf.Spec.Name, wf.Status.Status.String(),
-							created.String(), updated.String()})
+						status := wf.Status.Status.String()
+						if wf.Status.GetQuarantine().GetQuarantined() {
+							status += " (QUARANTINED)"
+						}
+						rows = append(rows, []string{wfID, wf.Spec.Name, status, created.String(), updated.String()})
 					}
 					table(os.Stdout, []string{"ID", "NAME", "STATUS", "CREATED", "UPDATED"}, rows)
 				case 1:
@@ -0,0 +1,218 @@
+// Package admission enforces policies on the creation of workflow invocations using an external admission webhook.
+//
+// Before an invocation is created, its spec is POSTed to the webhook, which responds whether the invocation is
+// allowed. Similar to the admission webhooks of Kubernetes, the webhook can deny the invocation with a reason, or
+// allow it with a modified (mutated) spec, for example to add required labels. This allows policies, such as quotas
+// or tagging requirements, to be implemented outside of the workflow engine.
+//
+// If the webhook cannot be reached, times out or responds with an invalid response, the failure policy decides
+// whether the invocation is allowed (FailurePolicyIgnore) or rejected (FailurePolicyFail).
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultTimeout  = 5 * time.Second
+	contentTypeJSON = "application/json"
+
+	// FailurePolicyFail rejects the invocation if the webhook fails (fail-closed).
+	FailurePolicyFail = "fail"
+
+	// FailurePolicyIgnore allows the invocation if the webhook fails (fail-open).
+	FailurePolicyIgnore = "ignore"
+)
+
+const (
+	resultAllowed = "allowed"
+	resultMutated = "mutated"
+	resultDenied  = "denied"
+	resultFailed  = "failed"
+)
+
+var log = logrus.WithField("component", "admission")
+
+var metricAdmissionReviews = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "admission",
+	Name:      "reviews_total",
+	Help:      "Number of invocation admission reviews, by whether the invocation was allowed, mutated, denied, or the webhook failed",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(metricAdmissionReviews)
+}
+
+// Config contains the configuration of the admission webhook.
+type Config struct {
+	// URL is the endpoint to which the admission requests are POSTed.
+	URL string
+
+	// Timeout is the maximum duration of an admission request. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// FailurePolicy is either FailurePolicyFail or FailurePolicyIgnore. Defaults to FailurePolicyFail.
+	FailurePolicy string
+}
+
+// Request is the JSON-encoded body of an admission request.
+type Request struct {
+	// UID uniquely identifies the admission request.
+	UID string `json:"uid"`
+
+	// Spec is the spec of the invocation to be created, excluding the embedded workflow.
+	Spec json.RawMessage `json:"spec"`
+}
+
+// Response is the expected JSON-encoded body of the response of the webhook.
+type Response struct {
+	// UID should match the UID of the request.
+	UID string `json:"uid"`
+
+	// Allowed indicates whether the invocation can be created.
+	Allowed bool `json:"allowed"`
+
+	// Reason explains why the invocation was denied.
+	Reason string `json:"reason,omitempty"`
+
+	// Spec optionally replaces the spec of an allowed invocation. The workflow of the invocation cannot be changed.
+	Spec json.RawMessage `json:"spec,omitempty"`
+}
+
+// DeniedError is returned when the webhook denied the invocation.
+type DeniedError struct {
+	Reason string
+}
+
+func (e *DeniedError) Error() string {
+	if len(e.Reason) == 0 {
+		return "invocation denied by admission webhook"
+	}
+	return fmt.Sprintf("invocation denied by admission webhook: %s", e.Reason)
+}
+
+// WebhookError is returned when the webhook failed, and the failure policy rejects the invocation.
+type WebhookError struct {
+	Err error
+}
+
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("admission webhook failed: %v", e.Err)
+}
+
+// Webhook admits invocations by calling the configured admission webhook.
+type Webhook struct {
+	config    Config
+	client    *http.Client
+	marshaler *jsonpb.Marshaler
+}
+
+func NewWebhook(config Config) *Webhook {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if len(config.FailurePolicy) == 0 {
+		config.FailurePolicy = FailurePolicyFail
+	}
+	return &Webhook{
+		config:    config,
+		client:    &http.Client{Timeout: config.Timeout},
+		marshaler: &jsonpb.Marshaler{},
+	}
+}
+
+// Admit reviews the invocation spec with the webhook. It returns the spec to create the invocation with, which is a
+// mutated copy if the webhook modified it, or an error if the invocation should not be created.
+func (w *Webhook) Admit(ctx context.Context, spec *types.WorkflowInvocationSpec) (*types.WorkflowInvocationSpec,
+	error) {
+	resp, err := w.review(ctx, spec)
+	if err != nil {
+		metricAdmissionReviews.WithLabelValues(resultFailed).Inc()
+		if w.config.FailurePolicy == FailurePolicyIgnore {
+			log.Warnf("Allowing invocation of workflow %s due to failure policy: %v", spec.GetWorkflowId(), err)
+			return spec, nil
+		}
+		return nil, &WebhookError{Err: err}
+	}
+
+	if !resp.Allowed {
+		metricAdmissionReviews.WithLabelValues(resultDenied).Inc()
+		return nil, &DeniedError{Reason: resp.Reason}
+	}
+	if len(resp.Spec) == 0 {
+		metricAdmissionReviews.WithLabelValues(resultAllowed).Inc()
+		return spec, nil
+	}
+
+	mutated := &types.WorkflowInvocationSpec{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(resp.Spec), mutated); err != nil {
+		metricAdmissionReviews.WithLabelValues(resultFailed).Inc()
+		if w.config.FailurePolicy == FailurePolicyIgnore {
+			log.Warnf("Ignoring invalid mutation of invocation of workflow %s: %v", spec.GetWorkflowId(), err)
+			return spec, nil
+		}
+		return nil, &WebhookError{Err: fmt.Errorf("invalid spec in response: %v", err)}
+	}
+	mutated.WorkflowId = spec.GetWorkflowId()
+	mutated.Workflow = spec.GetWorkflow()
+	metricAdmissionReviews.WithLabelValues(resultMutated).Inc()
+	return mutated, nil
+}
+
+func (w *Webhook) review(ctx context.Context, spec *types.WorkflowInvocationSpec) (*Response, error) {
+	// The workflow is left out of the request, as the webhook only needs the reference to it.
+	reviewed := *spec
+	reviewed.Workflow = nil
+	encodedSpec, err := w.marshaler.MarshalToString(&reviewed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %v", err)
+	}
+	req := &Request{
+		UID:  util.UID(),
+		Spec: json.RawMessage(encodedSpec),
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", contentTypeJSON)
+	httpResp, err := w.client.Do(httpReq.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected response: %s", httpResp.Status)
+	}
+
+	resp := &Response{}
+	if err := json.Unmarshal(respBody, resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	if resp.UID != req.UID {
+		return nil, fmt.Errorf("response UID '%s' does not match request UID '%s'", resp.UID, req.UID)
+	}
+	return resp, nil
+}
@@ -0,0 +1,93 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newWebhookServer creates a webhook that responds to each admission request using the provided function.
+func newWebhookServer(t *testing.T, respond func(req *Request) *Response) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &Request{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		resp := respond(req)
+		resp.UID = req.UID
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+}
+
+func newSpec() *types.WorkflowInvocationSpec {
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf")}
+	return spec
+}
+
+func TestWebhook_Allow(t *testing.T) {
+	server := newWebhookServer(t, func(req *Request) *Response {
+		// The embedded workflow is not sent to the webhook.
+		assert.NotContains(t, string(req.Spec), "workflow\"")
+		assert.Contains(t, string(req.Spec), "\"workflowId\":\"wf\"")
+		return &Response{Allowed: true}
+	})
+	defer server.Close()
+
+	spec := newSpec()
+	admitted, err := NewWebhook(Config{URL: server.URL}).Admit(context.Background(), spec)
+	assert.NoError(t, err)
+	assert.Equal(t, spec, admitted)
+}
+
+func TestWebhook_Deny(t *testing.T) {
+	server := newWebhookServer(t, func(req *Request) *Response {
+		return &Response{Allowed: false, Reason: "missing label 'team'"}
+	})
+	defer server.Close()
+
+	_, err := NewWebhook(Config{URL: server.URL}).Admit(context.Background(), newSpec())
+	assert.IsType(t, &DeniedError{}, err)
+	assert.EqualError(t, err, "invocation denied by admission webhook: missing label 'team'")
+}
+
+func TestWebhook_Mutate(t *testing.T) {
+	server := newWebhookServer(t, func(req *Request) *Response {
+		return &Response{
+			Allowed: true,
+			Spec:    json.RawMessage(`{"workflowId": "other", "labels": {"team": "payments"}}`),
+		}
+	})
+	defer server.Close()
+
+	spec := newSpec()
+	admitted, err := NewWebhook(Config{URL: server.URL}).Admit(context.Background(), spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", admitted.GetLabels()["team"])
+	// The workflow of the invocation cannot be changed by the webhook.
+	assert.Equal(t, "wf", admitted.GetWorkflowId())
+	assert.Equal(t, spec.GetWorkflow(), admitted.GetWorkflow())
+}
+
+func TestWebhook_FailurePolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// The webhook times out, which rejects the invocation by default (fail-closed).
+	spec := newSpec()
+	_, err := NewWebhook(Config{URL: server.URL, Timeout: 10 * time.Millisecond}).Admit(context.Background(), spec)
+	assert.IsType(t, &WebhookError{}, err)
+
+	// With the ignore policy the invocation is allowed (fail-open).
+	admitted, err := NewWebhook(Config{URL: server.URL, Timeout: 10 * time.Millisecond,
+		FailurePolicy: FailurePolicyIgnore}).Admit(context.Background(), spec)
+	assert.NoError(t, err)
+	assert.Equal(t, spec, admitted)
+}
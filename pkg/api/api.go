@@ -5,6 +5,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/fission/fission-workflows/pkg/auth"
+	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/types"
 )
 
@@ -12,6 +14,7 @@ type CallConfig struct {
 	ctx             context.Context
 	postTransformer func(i interface{}) error
 	awaitWorkflow   time.Duration
+	reason          string
 }
 
 type CallOption func(op *CallConfig)
@@ -51,3 +54,17 @@ func AwaitWorklow(timeout time.Duration) CallOption {
 		config.awaitWorkflow = timeout
 	}
 }
+
+// WithReason sets the reason of the call, such as the reason of a cancellation.
+func WithReason(reason string) CallOption {
+	return func(config *CallConfig) {
+		config.reason = reason
+	}
+}
+
+// setPrincipal records the authenticated principal of the context, if any, in the metadata of the event for auditing.
+func setPrincipal(ctx context.Context, event *fes.Event) {
+	if principal := auth.FromContext(ctx); principal.Authenticated() {
+		event.Metadata[auth.MetadataPrincipal] = principal.Subject
+	}
+}
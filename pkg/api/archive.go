@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/archive"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultArchiveInterval is the default interval at which the background archiver looks for invocations to archive.
+const DefaultArchiveInterval = 10 * time.Minute
+
+var (
+	// ErrArchiveNotSupported is returned when archiving invocations from an event store that does not support
+	// deletes.
+	ErrArchiveNotSupported = errors.New("event store does not support archiving invocations")
+
+	// ErrNotArchived is returned when restoring an invocation that has not been archived.
+	ErrNotArchived = errors.New("invocation has not been archived")
+
+	metricArchivedInvocations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "api",
+		Name:      "archived_invocations_total",
+		Help:      "Number of invocations matched by archivals, by result (archived, dry_run, skipped or failed)",
+	}, []string{"result"})
+
+	metricArchiveReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "api",
+		Name:      "archive_reads_total",
+		Help:      "Number of archived invocations read from the archive, by reason (hydrate or restore)",
+	}, []string{"reason"})
+)
+
+func init() {
+	prometheus.MustRegister(metricArchivedInvocations, metricArchiveReads)
+}
+
+// ArchiveConfig configures the archival of invocations.
+type ArchiveConfig struct {
+	// After is the minimum time since invocations finished before they are archived by the background archiver. If
+	// 0, invocations are only archived on demand.
+	After time.Duration
+
+	// Interval is the interval at which the background archiver looks for invocations to archive. If 0,
+	// DefaultArchiveInterval is used.
+	Interval time.Duration
+
+	// Rate is the maximum number of invocations that are archived per second. If 0, DefaultPurgeRate is used.
+	Rate int
+}
+
+// Archiver moves the event logs of finished invocations from the event store to an archive, which is cheaper cold
+// storage, such as a blob store. Unlike a purge, the invocations are retained: the status of an archived invocation
+// can be hydrated from the archive on demand, and the event log of an archived invocation can be restored to the
+// event store.
+//
+// Like purges, archivals are rate-limited, and only supported by event stores that implement fes.EventDeleter.
+type Archiver struct {
+	purger *Purger
+	cold   archive.Store
+	config ArchiveConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewArchiver(es fes.Backend, invocations *store.Invocations, cold archive.Store, config ArchiveConfig) *Archiver {
+	if config.Interval <= 0 {
+		config.Interval = DefaultArchiveInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Archiver{
+		purger: NewPurger(es, invocations, config.Rate),
+		cold:   cold,
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Archive archives the finished invocations that match the filter, and returns the archived invocations. The filter
+// selects the invocations in the same way as for purges. If the context is canceled, the archival stops, and returns
+// the invocations that have been archived so far along with the error of the context.
+func (a *Archiver) Archive(ctx context.Context, filter PurgeFilter) (*PurgeResult, error) {
+	if filter.FinishedBefore.IsZero() {
+		return nil, ErrPurgeCutoffMissing
+	}
+	deleter, ok := a.purger.es.(fes.EventDeleter)
+	if !ok && !filter.DryRun {
+		return nil, ErrArchiveNotSupported
+	}
+
+	matches, skipped, err := a.purger.match(filter)
+	if err != nil {
+		return nil, err
+	}
+	metricArchivedInvocations.WithLabelValues("skipped").Add(float64(skipped))
+	result := &PurgeResult{Skipped: skipped}
+	if filter.DryRun {
+		for _, invocation := range matches {
+			result.Purged = append(result.Purged, invocation.ID())
+		}
+		metricArchivedInvocations.WithLabelValues("dry_run").Add(float64(len(matches)))
+		return result, nil
+	}
+
+	ticker := time.NewTicker(a.purger.interval)
+	defer ticker.Stop()
+	for i, invocation := range matches {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+		if err := a.archive(deleter, invocation); err != nil {
+			metricArchivedInvocations.WithLabelValues("failed").Inc()
+			logrus.Warnf("Failed to archive invocation %s: %v", invocation.ID(), err)
+			continue
+		}
+		metricArchivedInvocations.WithLabelValues("archived").Inc()
+		result.Purged = append(result.Purged, invocation.ID())
+	}
+	logrus.Infof("Archived %d invocations that finished before %v (skipped: %d)", len(result.Purged),
+		filter.FinishedBefore, result.Skipped)
+	return result, nil
+}
+
+// Load hydrates the archived invocation from the archive, without restoring it to the event store. It returns nil if
+// the invocation has not been archived.
+func (a *Archiver) Load(invocationID string) (*types.WorkflowInvocation, error) {
+	events, err := a.cold.Get(invocationID)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	metricArchiveReads.WithLabelValues("hydrate").Inc()
+	return projectInvocation(invocationID, events)
+}
+
+// Restore moves the event log of the archived invocation back to the event store, and returns the restored
+// invocation. If the invocation has not been archived, ErrNotArchived is returned.
+func (a *Archiver) Restore(invocationID string) (*types.WorkflowInvocation, error) {
+	events, err := a.cold.Get(invocationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrNotArchived
+	}
+	metricArchiveReads.WithLabelValues("restore").Inc()
+
+	// If a previous restore failed after the events were appended, the events should not be appended again.
+	key := projectors.NewInvocationAggregate(invocationID)
+	existing, err := a.purger.es.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		for _, event := range events {
+			if err := a.purger.es.Append(event); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := a.cold.Delete(invocationID); err != nil {
+		return nil, err
+	}
+	a.purger.invocations.Invalidate(invocationID)
+	logrus.Infof("Restored archived invocation %s", invocationID)
+	return projectInvocation(invocationID, events)
+}
+
+// Run archives the invocations that finished longer than the configured threshold ago, at the configured interval,
+// until the archiver is closed. If no threshold has been configured, it returns immediately.
+func (a *Archiver) Run() error {
+	if a.config.After <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return nil
+		case <-ticker.C:
+			_, err := a.Archive(a.ctx, PurgeFilter{FinishedBefore: time.Now().Add(-a.config.After)})
+			if err != nil && err != context.Canceled {
+				logrus.Warnf("Failed to archive invocations: %v", err)
+			}
+		}
+	}
+}
+
+func (a *Archiver) Close() error {
+	a.cancel()
+	return nil
+}
+
+// archive stores the event log of the invocation, including the events of its task runs, in the archive, and only
+// then removes it from the event store and the cache.
+func (a *Archiver) archive(deleter fes.EventDeleter, invocation *types.WorkflowInvocation) error {
+	events, err := a.purger.es.Get(projectors.NewInvocationAggregate(invocation.ID()))
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return fes.ErrEntityNotFound.WithEntity(invocation)
+	}
+	if err := a.cold.Put(invocation.ID(), events); err != nil {
+		return err
+	}
+	return a.purger.delete(deleter, invocation)
+}
+
+func projectInvocation(invocationID string, events []*fes.Event) (*types.WorkflowInvocation, error) {
+	projector := projectors.NewWorkflowInvocation()
+	base, err := projector.NewProjection(projectors.NewInvocationAggregate(invocationID))
+	if err != nil {
+		return nil, err
+	}
+	entity, err := projector.Project(base, events...)
+	if err != nil {
+		return nil, err
+	}
+	return entity.(*types.WorkflowInvocation), nil
+}
@@ -0,0 +1,158 @@
+// Package archive provides the cold storage of the event logs of archived invocations.
+package archive
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/golang/protobuf/proto"
+)
+
+// ErrInvalidID is returned when the ID of an invocation cannot be used as a key in the archive.
+var ErrInvalidID = errors.New("invalid invocation ID")
+
+// Store is the cold storage of the event logs of archived invocations, such as a blob store. It trades slower reads
+// for storage that is cheaper than the event store.
+type Store interface {
+	// Put stores the event log of the invocation, replacing any existing event log of the invocation.
+	Put(invocationID string, events []*fes.Event) error
+
+	// Get returns the event log of the invocation, or nil if the invocation has not been archived.
+	Get(invocationID string) ([]*fes.Event, error)
+
+	// Delete removes the event log of the invocation. Deleting an invocation that has not been archived is not an
+	// error.
+	Delete(invocationID string) error
+}
+
+// MemStore keeps the archived event logs in memory. It is intended for testing and development, as it does not
+// reduce the memory usage compared to the event store.
+type MemStore struct {
+	logs map[string][]*fes.Event
+	mu   sync.RWMutex
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{
+		logs: map[string][]*fes.Event{},
+	}
+}
+
+func (s *MemStore) Put(invocationID string, events []*fes.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[invocationID] = append([]*fes.Event{}, events...)
+	return nil
+}
+
+func (s *MemStore) Get(invocationID string) ([]*fes.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.logs[invocationID], nil
+}
+
+func (s *MemStore) Delete(invocationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.logs, invocationID)
+	return nil
+}
+
+// FileStore stores each archived event log in a file in a directory, such as a directory on a cheap persistent
+// volume or a mounted blob store. The events are stored as length-prefixed protobuf messages.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a file store in the directory, creating the directory if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Put(invocationID string, events []*fes.Event) error {
+	path, err := s.path(invocationID)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	for _, event := range events {
+		msg, err := proto.Marshal(event)
+		if err != nil {
+			return err
+		}
+		data = append(data, proto.EncodeVarint(uint64(len(msg)))...)
+		data = append(data, msg...)
+	}
+
+	// Write the event log to a temporary file first, to avoid leaving a partial event log behind.
+	f, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *FileStore) Get(invocationID string) ([]*fes.Event, error) {
+	path, err := s.path(invocationID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var events []*fes.Event
+	for len(data) > 0 {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return nil, fmt.Errorf("corrupt event log of archived invocation %s", invocationID)
+		}
+		event := &fes.Event{}
+		if err := proto.Unmarshal(data[n:n+int(size)], event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+		data = data[n+int(size):]
+	}
+	return events, nil
+}
+
+func (s *FileStore) Delete(invocationID string) error {
+	path, err := s.path(invocationID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) path(invocationID string) (string, error) {
+	if len(invocationID) == 0 || strings.ContainsAny(invocationID, `/\`) || strings.HasPrefix(invocationID, ".") {
+		return "", ErrInvalidID
+	}
+	return filepath.Join(s.dir, invocationID), nil
+}
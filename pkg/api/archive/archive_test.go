@@ -0,0 +1,63 @@
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEvents(t *testing.T, n int) []*fes.Event {
+	var events []*fes.Event
+	for i := 0; i < n; i++ {
+		event, err := fes.NewEvent(fes.Aggregate{Type: "invocation", Id: "wi-1"}, &wrappers.Int32Value{Value: int32(i)})
+		assert.NoError(t, err)
+		events = append(events, event)
+	}
+	return events
+}
+
+func testStore(t *testing.T, store Store) {
+	events, err := store.Get("wi-1")
+	assert.NoError(t, err)
+	assert.Nil(t, events)
+
+	archived := newEvents(t, 3)
+	assert.NoError(t, store.Put("wi-1", archived))
+	events, err = store.Get("wi-1")
+	assert.NoError(t, err)
+	assert.Len(t, events, len(archived))
+	for i := range archived {
+		assert.True(t, proto.Equal(archived[i], events[i]))
+	}
+
+	assert.NoError(t, store.Delete("wi-1"))
+	events, err = store.Get("wi-1")
+	assert.NoError(t, err)
+	assert.Nil(t, events)
+
+	// Deleting an invocation that has not been archived is a no-op.
+	assert.NoError(t, store.Delete("wi-1"))
+}
+
+func TestMemStore(t *testing.T) {
+	testStore(t, NewMemStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store, err := NewFileStore(dir)
+	assert.NoError(t, err)
+	testStore(t, store)
+
+	// The invocation ID cannot escape the directory of the store.
+	assert.Equal(t, ErrInvalidID, store.Put("../wi-1", newEvents(t, 1)))
+	_, err = store.Get("")
+	assert.Equal(t, ErrInvalidID, err)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/archive"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiver(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := NewInvocationAPI(backend)
+	cache := testutil.NewCache()
+	invoke := func(cancel bool) string {
+		spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+		spec.Workflow = types.NewWorkflow("wf")
+		invocationID, err := invocationAPI.Invoke(spec)
+		assert.NoError(t, err)
+		if cancel {
+			assert.NoError(t, invocationAPI.Cancel(invocationID))
+		}
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		assert.NoError(t, cache.Put(entity))
+		return invocationID
+	}
+	finished := invoke(true)
+	running := invoke(false)
+	invocations := store.NewInvocationStore(cache)
+	cold := archive.NewMemStore()
+	archiver := NewArchiver(backend, invocations, cold, ArchiveConfig{Rate: 1000})
+	filter := PurgeFilter{FinishedBefore: time.Now().Add(time.Second)}
+
+	// Invocations that have not been archived cannot be hydrated or restored.
+	wi, err := archiver.Load(finished)
+	assert.NoError(t, err)
+	assert.Nil(t, wi)
+	_, err = archiver.Restore(finished)
+	assert.Equal(t, ErrNotArchived, err)
+
+	archivedEvents, err := backend.Get(projectors.NewInvocationAggregate(finished))
+	assert.NoError(t, err)
+	result, err := archiver.Archive(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{finished}, result.Purged)
+	assert.Equal(t, 1, result.Skipped)
+
+	// The archived invocation is removed from the event store and the cache, but can still be hydrated.
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(finished))
+	assert.NoError(t, err)
+	assert.Empty(t, invocationEvents)
+	_, err = invocations.GetInvocation(finished)
+	assert.True(t, fes.ErrEntityNotFound.Is(err))
+	wi, err = archiver.Load(finished)
+	assert.NoError(t, err)
+	assert.Equal(t, finished, wi.ID())
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, wi.GetStatus().GetStatus())
+	wi, err = invocations.GetInvocation(running)
+	assert.NoError(t, err)
+	assert.NotNil(t, wi)
+
+	// Restoring the invocation moves its event log back to the event store.
+	wi, err = archiver.Restore(finished)
+	assert.NoError(t, err)
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, wi.GetStatus().GetStatus())
+	invocationEvents, err = backend.Get(projectors.NewInvocationAggregate(finished))
+	assert.NoError(t, err)
+	assert.Len(t, invocationEvents, len(archivedEvents))
+	events, err := cold.Get(finished)
+	assert.NoError(t, err)
+	assert.Nil(t, events)
+
+	// Event stores that do not support deletes only support dry runs.
+	archiver = NewArchiver(appendOnlyBackend{backend}, invocations, cold, ArchiveConfig{})
+	_, err = archiver.Archive(context.Background(), filter)
+	assert.Equal(t, ErrArchiveNotSupported, err)
+}
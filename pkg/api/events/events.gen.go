@@ -11,19 +11,28 @@ type Event interface {
 }
 
 const (
-	EventWorkflowCreated       EventType = "WorkflowCreated"
-	EventWorkflowDeleted       EventType = "WorkflowDeleted"
-	EventWorkflowParsed        EventType = "WorkflowParsed"
-	EventWorkflowParsingFailed EventType = "WorkflowParsingFailed"
-	EventInvocationCreated     EventType = "InvocationCreated"
-	EventInvocationCompleted   EventType = "InvocationCompleted"
-	EventInvocationCanceled    EventType = "InvocationCanceled"
-	EventInvocationTaskAdded   EventType = "InvocationTaskAdded"
-	EventInvocationFailed      EventType = "InvocationFailed"
-	EventTaskStarted           EventType = "TaskStarted"
-	EventTaskSucceeded         EventType = "TaskSucceeded"
-	EventTaskSkipped           EventType = "TaskSkipped"
-	EventTaskFailed            EventType = "TaskFailed"
+	EventWorkflowCreated               EventType = "WorkflowCreated"
+	EventWorkflowDeleted               EventType = "WorkflowDeleted"
+	EventWorkflowParsed                EventType = "WorkflowParsed"
+	EventWorkflowParsingFailed         EventType = "WorkflowParsingFailed"
+	EventWorkflowQuarantined           EventType = "WorkflowQuarantined"
+	EventWorkflowQuarantineCleared     EventType = "WorkflowQuarantineCleared"
+	EventInvocationCreated             EventType = "InvocationCreated"
+	EventInvocationCompleted           EventType = "InvocationCompleted"
+	EventInvocationCanceled            EventType = "InvocationCanceled"
+	EventInvocationTaskAdded           EventType = "InvocationTaskAdded"
+	EventInvocationFailed              EventType = "InvocationFailed"
+	EventInvocationSoftTimeoutExceeded EventType = "InvocationSoftTimeoutExceeded"
+	EventInvocationBranchSelected      EventType = "InvocationBranchSelected"
+	EventInvocationTaskThrottled       EventType = "InvocationTaskThrottled"
+	EventInvocationScopeWarning        EventType = "InvocationScopeWarning"
+	EventInvocationSummary             EventType = "InvocationSummary"
+	EventInvocationTasksReplayed       EventType = "InvocationTasksReplayed"
+	EventTaskStarted                   EventType = "TaskStarted"
+	EventTaskSucceeded                 EventType = "TaskSucceeded"
+	EventTaskSkipped                   EventType = "TaskSkipped"
+	EventTaskFailed                    EventType = "TaskFailed"
+	EventTaskPolled                    EventType = "TaskPolled"
 )
 
 func (m *WorkflowCreated) Type() EventType {
@@ -42,6 +51,14 @@ func (m *WorkflowParsingFailed) Type() EventType {
 	return EventWorkflowParsingFailed
 }
 
+func (m *WorkflowQuarantined) Type() EventType {
+	return EventWorkflowQuarantined
+}
+
+func (m *WorkflowQuarantineCleared) Type() EventType {
+	return EventWorkflowQuarantineCleared
+}
+
 func (m *InvocationCreated) Type() EventType {
 	return EventInvocationCreated
 }
@@ -62,6 +79,30 @@ func (m *InvocationFailed) Type() EventType {
 	return EventInvocationFailed
 }
 
+func (m *InvocationSoftTimeoutExceeded) Type() EventType {
+	return EventInvocationSoftTimeoutExceeded
+}
+
+func (m *InvocationBranchSelected) Type() EventType {
+	return EventInvocationBranchSelected
+}
+
+func (m *InvocationTaskThrottled) Type() EventType {
+	return EventInvocationTaskThrottled
+}
+
+func (m *InvocationScopeWarning) Type() EventType {
+	return EventInvocationScopeWarning
+}
+
+func (m *InvocationSummary) Type() EventType {
+	return EventInvocationSummary
+}
+
+func (m *InvocationTasksReplayed) Type() EventType {
+	return EventInvocationTasksReplayed
+}
+
 func (m *TaskStarted) Type() EventType {
 	return EventTaskStarted
 }
@@ -77,3 +118,7 @@ func (m *TaskSkipped) Type() EventType {
 func (m *TaskFailed) Type() EventType {
 	return EventTaskFailed
 }
+
+func (m *TaskPolled) Type() EventType {
+	return EventTaskPolled
+}
@@ -12,21 +12,32 @@ It has these top-level messages:
 	WorkflowDeleted
 	WorkflowParsed
 	WorkflowParsingFailed
+	WorkflowQuarantined
+	WorkflowQuarantineCleared
 	InvocationCreated
 	InvocationCompleted
 	InvocationCanceled
 	InvocationTaskAdded
 	InvocationFailed
+	InvocationSoftTimeoutExceeded
+	InvocationBranchSelected
+	InvocationTaskThrottled
+	InvocationScopeWarning
+	InvocationSummary
+	InvocationTasksReplayed
 	TaskStarted
 	TaskSucceeded
 	TaskSkipped
 	TaskFailed
+	TaskPolled
 */
 package events
 
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/duration"
+import google_protobuf1 "github.com/golang/protobuf/ptypes/timestamp"
 import fission_workflows_types1 "github.com/fission/fission-workflows/pkg/types"
 import fission_workflows_types "github.com/fission/fission-workflows/pkg/types/typedvalues"
 
@@ -97,6 +108,30 @@ func (m *WorkflowParsingFailed) GetError() *fission_workflows_types1.Error {
 	return nil
 }
 
+type WorkflowQuarantined struct {
+	Quarantine *fission_workflows_types1.WorkflowQuarantine `protobuf:"bytes,1,opt,name=quarantine" json:"quarantine,omitempty"`
+}
+
+func (m *WorkflowQuarantined) Reset()                    { *m = WorkflowQuarantined{} }
+func (m *WorkflowQuarantined) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowQuarantined) ProtoMessage()               {}
+func (*WorkflowQuarantined) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *WorkflowQuarantined) GetQuarantine() *fission_workflows_types1.WorkflowQuarantine {
+	if m != nil {
+		return m.Quarantine
+	}
+	return nil
+}
+
+type WorkflowQuarantineCleared struct {
+}
+
+func (m *WorkflowQuarantineCleared) Reset()                    { *m = WorkflowQuarantineCleared{} }
+func (m *WorkflowQuarantineCleared) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowQuarantineCleared) ProtoMessage()               {}
+func (*WorkflowQuarantineCleared) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
 type InvocationCreated struct {
 	Spec *fission_workflows_types1.WorkflowInvocationSpec `protobuf:"bytes,1,opt,name=spec" json:"spec,omitempty"`
 }
@@ -104,7 +139,7 @@ type InvocationCreated struct {
 func (m *InvocationCreated) Reset()                    { *m = InvocationCreated{} }
 func (m *InvocationCreated) String() string            { return proto.CompactTextString(m) }
 func (*InvocationCreated) ProtoMessage()               {}
-func (*InvocationCreated) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*InvocationCreated) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *InvocationCreated) GetSpec() *fission_workflows_types1.WorkflowInvocationSpec {
 	if m != nil {
@@ -114,14 +149,17 @@ func (m *InvocationCreated) GetSpec() *fission_workflows_types1.WorkflowInvocati
 }
 
 type InvocationCompleted struct {
-	Output        *fission_workflows_types.TypedValue `protobuf:"bytes,1,opt,name=output" json:"output,omitempty"`
-	OutputHeaders *fission_workflows_types.TypedValue `protobuf:"bytes,2,opt,name=OutputHeaders" json:"OutputHeaders,omitempty"`
+	Output         *fission_workflows_types.TypedValue `protobuf:"bytes,1,opt,name=output" json:"output,omitempty"`
+	OutputHeaders  *fission_workflows_types.TypedValue `protobuf:"bytes,2,opt,name=OutputHeaders" json:"OutputHeaders,omitempty"`
+	AbandonedTasks []string                            `protobuf:"bytes,3,rep,name=abandonedTasks" json:"abandonedTasks,omitempty"`
+	CompletionMode string                              `protobuf:"bytes,4,opt,name=completionMode" json:"completionMode,omitempty"`
+	CompletedBy    []string                            `protobuf:"bytes,5,rep,name=completedBy" json:"completedBy,omitempty"`
 }
 
 func (m *InvocationCompleted) Reset()                    { *m = InvocationCompleted{} }
 func (m *InvocationCompleted) String() string            { return proto.CompactTextString(m) }
 func (*InvocationCompleted) ProtoMessage()               {}
-func (*InvocationCompleted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*InvocationCompleted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *InvocationCompleted) GetOutput() *fission_workflows_types.TypedValue {
 	if m != nil {
@@ -137,6 +175,27 @@ func (m *InvocationCompleted) GetOutputHeaders() *fission_workflows_types.TypedV
 	return nil
 }
 
+func (m *InvocationCompleted) GetAbandonedTasks() []string {
+	if m != nil {
+		return m.AbandonedTasks
+	}
+	return nil
+}
+
+func (m *InvocationCompleted) GetCompletionMode() string {
+	if m != nil {
+		return m.CompletionMode
+	}
+	return ""
+}
+
+func (m *InvocationCompleted) GetCompletedBy() []string {
+	if m != nil {
+		return m.CompletedBy
+	}
+	return nil
+}
+
 type InvocationCanceled struct {
 	Error *fission_workflows_types1.Error `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
 }
@@ -144,7 +203,7 @@ type InvocationCanceled struct {
 func (m *InvocationCanceled) Reset()                    { *m = InvocationCanceled{} }
 func (m *InvocationCanceled) String() string            { return proto.CompactTextString(m) }
 func (*InvocationCanceled) ProtoMessage()               {}
-func (*InvocationCanceled) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*InvocationCanceled) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *InvocationCanceled) GetError() *fission_workflows_types1.Error {
 	if m != nil {
@@ -160,7 +219,7 @@ type InvocationTaskAdded struct {
 func (m *InvocationTaskAdded) Reset()                    { *m = InvocationTaskAdded{} }
 func (m *InvocationTaskAdded) String() string            { return proto.CompactTextString(m) }
 func (*InvocationTaskAdded) ProtoMessage()               {}
-func (*InvocationTaskAdded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*InvocationTaskAdded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *InvocationTaskAdded) GetTask() *fission_workflows_types1.Task {
 	if m != nil {
@@ -176,7 +235,7 @@ type InvocationFailed struct {
 func (m *InvocationFailed) Reset()                    { *m = InvocationFailed{} }
 func (m *InvocationFailed) String() string            { return proto.CompactTextString(m) }
 func (*InvocationFailed) ProtoMessage()               {}
-func (*InvocationFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*InvocationFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *InvocationFailed) GetError() *fission_workflows_types1.Error {
 	if m != nil {
@@ -185,6 +244,205 @@ func (m *InvocationFailed) GetError() *fission_workflows_types1.Error {
 	return nil
 }
 
+type InvocationSoftTimeoutExceeded struct {
+}
+
+func (m *InvocationSoftTimeoutExceeded) Reset()                    { *m = InvocationSoftTimeoutExceeded{} }
+func (m *InvocationSoftTimeoutExceeded) String() string            { return proto.CompactTextString(m) }
+func (*InvocationSoftTimeoutExceeded) ProtoMessage()               {}
+func (*InvocationSoftTimeoutExceeded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+type InvocationBranchSelected struct {
+	Switch string `protobuf:"bytes,1,opt,name=switch" json:"switch,omitempty"`
+	Branch string `protobuf:"bytes,2,opt,name=branch" json:"branch,omitempty"`
+}
+
+func (m *InvocationBranchSelected) Reset()                    { *m = InvocationBranchSelected{} }
+func (m *InvocationBranchSelected) String() string            { return proto.CompactTextString(m) }
+func (*InvocationBranchSelected) ProtoMessage()               {}
+func (*InvocationBranchSelected) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *InvocationBranchSelected) GetSwitch() string {
+	if m != nil {
+		return m.Switch
+	}
+	return ""
+}
+
+func (m *InvocationBranchSelected) GetBranch() string {
+	if m != nil {
+		return m.Branch
+	}
+	return ""
+}
+
+// InvocationTaskThrottled records that the execution of a task is deferred by its rate limit.
+type InvocationTaskThrottled struct {
+	TaskId string `protobuf:"bytes,1,opt,name=taskId" json:"taskId,omitempty"`
+	// Until is the time at which the rate limit of the task allows it to be executed again.
+	Until *google_protobuf1.Timestamp `protobuf:"bytes,2,opt,name=until" json:"until,omitempty"`
+}
+
+func (m *InvocationTaskThrottled) Reset()                    { *m = InvocationTaskThrottled{} }
+func (m *InvocationTaskThrottled) String() string            { return proto.CompactTextString(m) }
+func (*InvocationTaskThrottled) ProtoMessage()               {}
+func (*InvocationTaskThrottled) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *InvocationTaskThrottled) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *InvocationTaskThrottled) GetUntil() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+// InvocationScopeWarning records that the inputs of a task reference keys that are undefined in the scope of the
+// invocation.
+type InvocationScopeWarning struct {
+	TaskId     string   `protobuf:"bytes,1,opt,name=taskId" json:"taskId,omitempty"`
+	Inputs     []string `protobuf:"bytes,2,rep,name=inputs" json:"inputs,omitempty"`
+	References []string `protobuf:"bytes,3,rep,name=references" json:"references,omitempty"`
+}
+
+func (m *InvocationScopeWarning) Reset()                    { *m = InvocationScopeWarning{} }
+func (m *InvocationScopeWarning) String() string            { return proto.CompactTextString(m) }
+func (*InvocationScopeWarning) ProtoMessage()               {}
+func (*InvocationScopeWarning) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *InvocationScopeWarning) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *InvocationScopeWarning) GetInputs() []string {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *InvocationScopeWarning) GetReferences() []string {
+	if m != nil {
+		return m.References
+	}
+	return nil
+}
+
+// InvocationSummary summarizes the outcome of a finished invocation. It is appended once, after the terminal event of
+// the invocation, and again each time that a replay of the invocation finishes.
+type InvocationSummary struct {
+	Status     fission_workflows_types1.WorkflowInvocationStatus_Status `protobuf:"varint,1,opt,name=status,enum=fission.workflows.types.WorkflowInvocationStatus_Status" json:"status,omitempty"`
+	WorkflowId string                                                   `protobuf:"bytes,2,opt,name=workflowId" json:"workflowId,omitempty"`
+	// Duration is the duration of the invocation, from its creation until its terminal event.
+	Duration        *google_protobuf.Duration `protobuf:"bytes,3,opt,name=duration" json:"duration,omitempty"`
+	TaskCount       int32                     `protobuf:"varint,4,opt,name=taskCount" json:"taskCount,omitempty"`
+	FailedTaskCount int32                     `protobuf:"varint,5,opt,name=failedTaskCount" json:"failedTaskCount,omitempty"`
+	// Error contains the reason of the failure or cancellation of the invocation.
+	Error *fission_workflows_types1.Error `protobuf:"bytes,6,opt,name=error" json:"error,omitempty"`
+	// OutputTask is the task of which the output is the output of the invocation. The output itself is not included
+	// in the summary, but can be retrieved from the status of the invocation.
+	OutputTask string `protobuf:"bytes,7,opt,name=outputTask" json:"outputTask,omitempty"`
+	// OutputSize is the size (in bytes) of the output of the invocation.
+	OutputSize int64 `protobuf:"varint,8,opt,name=outputSize" json:"outputSize,omitempty"`
+}
+
+func (m *InvocationSummary) Reset()                    { *m = InvocationSummary{} }
+func (m *InvocationSummary) String() string            { return proto.CompactTextString(m) }
+func (*InvocationSummary) ProtoMessage()               {}
+func (*InvocationSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *InvocationSummary) GetStatus() fission_workflows_types1.WorkflowInvocationStatus_Status {
+	if m != nil {
+		return m.Status
+	}
+	return fission_workflows_types1.WorkflowInvocationStatus_UNKNOWN
+}
+
+func (m *InvocationSummary) GetWorkflowId() string {
+	if m != nil {
+		return m.WorkflowId
+	}
+	return ""
+}
+
+func (m *InvocationSummary) GetDuration() *google_protobuf.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *InvocationSummary) GetTaskCount() int32 {
+	if m != nil {
+		return m.TaskCount
+	}
+	return 0
+}
+
+func (m *InvocationSummary) GetFailedTaskCount() int32 {
+	if m != nil {
+		return m.FailedTaskCount
+	}
+	return 0
+}
+
+func (m *InvocationSummary) GetError() *fission_workflows_types1.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *InvocationSummary) GetOutputTask() string {
+	if m != nil {
+		return m.OutputTask
+	}
+	return ""
+}
+
+func (m *InvocationSummary) GetOutputSize() int64 {
+	if m != nil {
+		return m.OutputSize
+	}
+	return 0
+}
+
+// InvocationTasksReplayed records that the failed tasks of a finished invocation, and the tasks that depend on them,
+// are replayed. It reopens the invocation.
+type InvocationTasksReplayed struct {
+	// TaskIds contains the IDs of the tasks that are reset to be executed again.
+	TaskIds []string `protobuf:"bytes,1,rep,name=taskIds" json:"taskIds,omitempty"`
+	// FnRefs contains the function references that override the function references of the replayed tasks.
+	FnRefs map[string]*fission_workflows_types1.FnRef `protobuf:"bytes,2,rep,name=fnRefs" json:"fnRefs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *InvocationTasksReplayed) Reset()                    { *m = InvocationTasksReplayed{} }
+func (m *InvocationTasksReplayed) String() string            { return proto.CompactTextString(m) }
+func (*InvocationTasksReplayed) ProtoMessage()               {}
+func (*InvocationTasksReplayed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *InvocationTasksReplayed) GetTaskIds() []string {
+	if m != nil {
+		return m.TaskIds
+	}
+	return nil
+}
+
+func (m *InvocationTasksReplayed) GetFnRefs() map[string]*fission_workflows_types1.FnRef {
+	if m != nil {
+		return m.FnRefs
+	}
+	return nil
+}
+
 //
 // Task
 //
@@ -196,7 +454,7 @@ type TaskStarted struct {
 func (m *TaskStarted) Reset()                    { *m = TaskStarted{} }
 func (m *TaskStarted) String() string            { return proto.CompactTextString(m) }
 func (*TaskStarted) ProtoMessage()               {}
-func (*TaskStarted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*TaskStarted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *TaskStarted) GetSpec() *fission_workflows_types1.TaskInvocationSpec {
 	if m != nil {
@@ -212,7 +470,7 @@ type TaskSucceeded struct {
 func (m *TaskSucceeded) Reset()                    { *m = TaskSucceeded{} }
 func (m *TaskSucceeded) String() string            { return proto.CompactTextString(m) }
 func (*TaskSucceeded) ProtoMessage()               {}
-func (*TaskSucceeded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*TaskSucceeded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *TaskSucceeded) GetResult() *fission_workflows_types1.TaskInvocationStatus {
 	if m != nil {
@@ -227,7 +485,7 @@ type TaskSkipped struct {
 func (m *TaskSkipped) Reset()                    { *m = TaskSkipped{} }
 func (m *TaskSkipped) String() string            { return proto.CompactTextString(m) }
 func (*TaskSkipped) ProtoMessage()               {}
-func (*TaskSkipped) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*TaskSkipped) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 type TaskFailed struct {
 	Error *fission_workflows_types1.Error `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
@@ -236,7 +494,7 @@ type TaskFailed struct {
 func (m *TaskFailed) Reset()                    { *m = TaskFailed{} }
 func (m *TaskFailed) String() string            { return proto.CompactTextString(m) }
 func (*TaskFailed) ProtoMessage()               {}
-func (*TaskFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*TaskFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *TaskFailed) GetError() *fission_workflows_types1.Error {
 	if m != nil {
@@ -245,55 +503,110 @@ func (m *TaskFailed) GetError() *fission_workflows_types1.Error {
 	return nil
 }
 
+// TaskPolled records a poll of a sensor task of which the readiness check did not pass yet.
+type TaskPolled struct {
+	Result string `protobuf:"bytes,1,opt,name=result" json:"result,omitempty"`
+}
+
+func (m *TaskPolled) Reset()                    { *m = TaskPolled{} }
+func (m *TaskPolled) String() string            { return proto.CompactTextString(m) }
+func (*TaskPolled) ProtoMessage()               {}
+func (*TaskPolled) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *TaskPolled) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*WorkflowCreated)(nil), "fission.workflows.events.WorkflowCreated")
 	proto.RegisterType((*WorkflowDeleted)(nil), "fission.workflows.events.WorkflowDeleted")
 	proto.RegisterType((*WorkflowParsed)(nil), "fission.workflows.events.WorkflowParsed")
 	proto.RegisterType((*WorkflowParsingFailed)(nil), "fission.workflows.events.WorkflowParsingFailed")
+	proto.RegisterType((*WorkflowQuarantined)(nil), "fission.workflows.events.WorkflowQuarantined")
+	proto.RegisterType((*WorkflowQuarantineCleared)(nil), "fission.workflows.events.WorkflowQuarantineCleared")
 	proto.RegisterType((*InvocationCreated)(nil), "fission.workflows.events.InvocationCreated")
 	proto.RegisterType((*InvocationCompleted)(nil), "fission.workflows.events.InvocationCompleted")
 	proto.RegisterType((*InvocationCanceled)(nil), "fission.workflows.events.InvocationCanceled")
 	proto.RegisterType((*InvocationTaskAdded)(nil), "fission.workflows.events.InvocationTaskAdded")
 	proto.RegisterType((*InvocationFailed)(nil), "fission.workflows.events.InvocationFailed")
+	proto.RegisterType((*InvocationSoftTimeoutExceeded)(nil), "fission.workflows.events.InvocationSoftTimeoutExceeded")
+	proto.RegisterType((*InvocationBranchSelected)(nil), "fission.workflows.events.InvocationBranchSelected")
+	proto.RegisterType((*InvocationTaskThrottled)(nil), "fission.workflows.events.InvocationTaskThrottled")
+	proto.RegisterType((*InvocationScopeWarning)(nil), "fission.workflows.events.InvocationScopeWarning")
+	proto.RegisterType((*InvocationSummary)(nil), "fission.workflows.events.InvocationSummary")
+	proto.RegisterType((*InvocationTasksReplayed)(nil), "fission.workflows.events.InvocationTasksReplayed")
 	proto.RegisterType((*TaskStarted)(nil), "fission.workflows.events.TaskStarted")
 	proto.RegisterType((*TaskSucceeded)(nil), "fission.workflows.events.TaskSucceeded")
 	proto.RegisterType((*TaskSkipped)(nil), "fission.workflows.events.TaskSkipped")
 	proto.RegisterType((*TaskFailed)(nil), "fission.workflows.events.TaskFailed")
+	proto.RegisterType((*TaskPolled)(nil), "fission.workflows.events.TaskPolled")
 }
 
 func init() { proto.RegisterFile("pkg/api/events/events.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 488 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x94, 0xdf, 0x6a, 0xd4, 0x40,
-	0x14, 0xc6, 0x49, 0xdb, 0x5d, 0xf4, 0x2c, 0xab, 0xed, 0x88, 0xb0, 0xac, 0x28, 0x25, 0x22, 0x14,
-	0xa4, 0x09, 0xb6, 0x5e, 0xd8, 0x7a, 0x21, 0xb6, 0xae, 0xec, 0x4a, 0xfd, 0x43, 0x2a, 0x55, 0x04,
-	0x2f, 0xa6, 0x99, 0xd3, 0x35, 0x24, 0xcd, 0x0c, 0x33, 0x93, 0x2d, 0xfb, 0x30, 0xbe, 0x88, 0x4f,
-	0x27, 0x93, 0x99, 0x98, 0x04, 0xdd, 0xad, 0x74, 0x6f, 0x36, 0x43, 0xf6, 0x7c, 0x3f, 0xce, 0xf7,
-	0x9d, 0x33, 0x81, 0x07, 0x22, 0x9d, 0x86, 0x54, 0x24, 0x21, 0xce, 0x30, 0xd7, 0xca, 0x3d, 0x02,
-	0x21, 0xb9, 0xe6, 0x64, 0x70, 0x91, 0x28, 0x95, 0xf0, 0x3c, 0xb8, 0xe2, 0x32, 0xbd, 0xc8, 0xf8,
-	0x95, 0x0a, 0xec, 0xff, 0xc3, 0xc3, 0x69, 0xa2, 0x7f, 0x14, 0xe7, 0x41, 0xcc, 0x2f, 0x43, 0x57,
-	0x54, 0x3d, 0x77, 0xff, 0x14, 0x87, 0x86, 0xad, 0xe7, 0x02, 0x95, 0xfd, 0xb5, 0xd4, 0xe1, 0xc9,
-	0x0d, 0xb4, 0x6c, 0x46, 0xb3, 0xa2, 0x7d, 0xb6, 0x34, 0xff, 0x04, 0xee, 0x7e, 0x71, 0xa2, 0x63,
-	0x89, 0x54, 0x23, 0x23, 0x07, 0xb0, 0xa1, 0x04, 0xc6, 0x03, 0x6f, 0xdb, 0xdb, 0xe9, 0xed, 0x3d,
-	0x09, 0xfe, 0x76, 0x61, 0xdb, 0xa9, 0x74, 0xa7, 0x02, 0xe3, 0xa8, 0x94, 0xf8, 0x5b, 0x35, 0xed,
-	0x0d, 0x66, 0xa8, 0x91, 0xf9, 0xbf, 0x3c, 0xb8, 0x53, 0xbd, 0xfb, 0x44, 0xa5, 0x42, 0x46, 0x26,
-	0xd0, 0xd1, 0x54, 0xa5, 0x6a, 0xe0, 0x6d, 0xaf, 0xef, 0xf4, 0xf6, 0xf6, 0x83, 0x45, 0x39, 0x05,
-	0x6d, 0x61, 0xf0, 0xd9, 0xa8, 0x46, 0xb9, 0x96, 0xf3, 0xc8, 0x12, 0x86, 0xdf, 0x01, 0xea, 0x97,
-	0x64, 0x13, 0xd6, 0x53, 0x9c, 0x97, 0x8d, 0xdf, 0x8e, 0xcc, 0x91, 0x1c, 0x40, 0xa7, 0xb4, 0x3b,
-	0x58, 0x2b, 0xcd, 0x3c, 0x5e, 0x68, 0xc6, 0x50, 0x4e, 0x35, 0xd5, 0x85, 0x8a, 0xac, 0xe2, 0x70,
-	0xed, 0x85, 0xe7, 0xbf, 0x87, 0xfb, 0xcd, 0x16, 0x92, 0x7c, 0xfa, 0x96, 0x26, 0x19, 0x32, 0xf2,
-	0x1c, 0x3a, 0x28, 0x25, 0x97, 0x2e, 0xa4, 0x47, 0x0b, 0xb9, 0x23, 0x53, 0x15, 0xd9, 0x62, 0xff,
-	0x2b, 0x6c, 0x4d, 0xf2, 0x19, 0x8f, 0xa9, 0x4e, 0x78, 0x5e, 0xc5, 0x7d, 0xdc, 0x8a, 0x3b, 0xbc,
-	0x36, 0xee, 0x9a, 0xd0, 0x08, 0xfe, 0xa7, 0x07, 0xf7, 0x1a, 0x68, 0x7e, 0x29, 0xca, 0xf4, 0xc9,
-	0x4b, 0xe8, 0xf2, 0x42, 0x8b, 0x42, 0x3b, 0xfc, 0x92, 0x00, 0xcc, 0x6a, 0x9c, 0x19, 0xe7, 0x91,
-	0x93, 0x90, 0x09, 0xf4, 0x3f, 0x96, 0xa7, 0x31, 0x52, 0x86, 0x52, 0x5d, 0x1f, 0x62, 0xcd, 0x68,
-	0x2b, 0xfd, 0x77, 0x40, 0x1a, 0xed, 0xd1, 0x3c, 0xc6, 0x9b, 0xa7, 0x38, 0x6e, 0x5a, 0x35, 0x73,
-	0x7b, 0xcd, 0x18, 0x32, 0xf2, 0x0c, 0x36, 0xcc, 0x4e, 0x38, 0xd6, 0xc3, 0xa5, 0x93, 0x8e, 0xca,
-	0x52, 0x7f, 0x0c, 0x9b, 0x35, 0x69, 0xa5, 0xc9, 0x7e, 0x80, 0x9e, 0xdb, 0x20, 0x69, 0x62, 0x7f,
-	0xd5, 0x9a, 0xe9, 0xd3, 0xa5, 0xbd, 0xfc, 0x73, 0x9e, 0x67, 0xd0, 0x2f, 0x79, 0x45, 0x1c, 0x23,
-	0x1a, 0x77, 0x23, 0xe8, 0x4a, 0x54, 0x45, 0x56, 0x0d, 0x72, 0xf7, 0x7f, 0x99, 0x76, 0xa7, 0x9d,
-	0xd8, 0xef, 0xbb, 0x3e, 0xd3, 0x44, 0x08, 0x64, 0xfe, 0x91, 0xbd, 0x3e, 0xab, 0x58, 0x3f, 0xba,
-	0xf5, 0xad, 0x6b, 0x6f, 0xeb, 0x79, 0xb7, 0xfc, 0xa4, 0xec, 0xff, 0x0e, 0x00, 0x00, 0xff, 0xff,
-	0x86, 0x61, 0xe6, 0x8d, 0x15, 0x05, 0x00, 0x00,
+	// 945 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xdb, 0x6e, 0xdb, 0x46,
+	0x10, 0x85, 0x24, 0x4b, 0xb1, 0x46, 0xb0, 0x93, 0x6c, 0x50, 0x97, 0x51, 0x1a, 0x47, 0x60, 0x2f,
+	0x10, 0x10, 0x84, 0x6a, 0x9d, 0x14, 0x48, 0x52, 0x14, 0x45, 0xed, 0x38, 0xb0, 0xd2, 0xa4, 0x75,
+	0x29, 0x35, 0x69, 0x0b, 0xf4, 0x61, 0xc5, 0x1d, 0x49, 0x84, 0x28, 0x2e, 0xbb, 0x5c, 0xda, 0x55,
+	0x7f, 0xa2, 0xff, 0xd3, 0xff, 0xe9, 0x4f, 0xf4, 0x29, 0xd8, 0x0b, 0x4d, 0x4a, 0x8e, 0xe2, 0x24,
+	0x7e, 0x21, 0xb9, 0xc3, 0x39, 0x67, 0x67, 0x67, 0xce, 0xce, 0xc0, 0xad, 0x64, 0x36, 0xe9, 0xd1,
+	0x24, 0xec, 0xe1, 0x09, 0xc6, 0x32, 0xb5, 0x2f, 0x2f, 0x11, 0x5c, 0x72, 0xe2, 0x8c, 0xc3, 0x34,
+	0x0d, 0x79, 0xec, 0x9d, 0x72, 0x31, 0x1b, 0x47, 0xfc, 0x34, 0xf5, 0xcc, 0xff, 0xf6, 0xee, 0x84,
+	0xf3, 0x49, 0x84, 0x3d, 0xed, 0x37, 0xca, 0xc6, 0x3d, 0x96, 0x09, 0x2a, 0x95, 0xab, 0xb6, 0xb4,
+	0xef, 0xac, 0xfe, 0x97, 0xe1, 0x1c, 0x53, 0x49, 0xe7, 0x89, 0x75, 0x78, 0x3c, 0x09, 0xe5, 0x34,
+	0x1b, 0x79, 0x01, 0x9f, 0xf7, 0xec, 0x2e, 0xf9, 0xfb, 0xde, 0xd9, 0x6e, 0x3d, 0x15, 0x9c, 0x5c,
+	0x24, 0x98, 0x9a, 0xa7, 0xc5, 0x3e, 0xff, 0x00, 0x2c, 0x3b, 0xa1, 0x51, 0xb6, 0xfc, 0x6d, 0xd8,
+	0xdc, 0xe7, 0x70, 0xf5, 0x95, 0x05, 0x1d, 0x08, 0xa4, 0x12, 0x19, 0x79, 0x04, 0x1b, 0x69, 0x82,
+	0x81, 0x53, 0xe9, 0x54, 0xba, 0xad, 0xbd, 0xcf, 0xbd, 0xf3, 0x69, 0x30, 0xe1, 0xe4, 0xb8, 0x41,
+	0x82, 0x81, 0xaf, 0x21, 0xee, 0xf5, 0x82, 0xed, 0x09, 0x46, 0x28, 0x91, 0xb9, 0xff, 0x56, 0x60,
+	0x3b, 0xb7, 0x1d, 0x53, 0x91, 0x22, 0x23, 0x7d, 0xa8, 0x4b, 0x9a, 0xce, 0x52, 0xa7, 0xd2, 0xa9,
+	0x75, 0x5b, 0x7b, 0xf7, 0xbd, 0x75, 0x89, 0xf6, 0x96, 0x81, 0xde, 0x50, 0xa1, 0x0e, 0x63, 0x29,
+	0x16, 0xbe, 0x61, 0x68, 0xff, 0x01, 0x50, 0x18, 0xc9, 0x35, 0xa8, 0xcd, 0x70, 0xa1, 0x03, 0x6f,
+	0xfa, 0xea, 0x93, 0x3c, 0x82, 0xba, 0x3e, 0xae, 0x53, 0xd5, 0x87, 0xf9, 0x74, 0xed, 0x61, 0x14,
+	0xcb, 0x40, 0x52, 0x99, 0xa5, 0xbe, 0x41, 0x3c, 0xae, 0x3e, 0xac, 0xb8, 0x2f, 0xe0, 0xa3, 0x72,
+	0x08, 0x61, 0x3c, 0x79, 0x4a, 0xc3, 0x08, 0x19, 0x79, 0x00, 0x75, 0x14, 0x82, 0x0b, 0x9b, 0xa4,
+	0xdd, 0xb5, 0xbc, 0x87, 0xca, 0xcb, 0x37, 0xce, 0xee, 0x08, 0x6e, 0xe4, 0x74, 0x3f, 0x67, 0x54,
+	0xd0, 0x58, 0x86, 0x31, 0x32, 0xf2, 0x03, 0xc0, 0x9f, 0x67, 0x4b, 0xcb, 0x78, 0xf7, 0xc2, 0xb4,
+	0x17, 0x0c, 0x7e, 0x09, 0xee, 0xde, 0x82, 0x9b, 0xe7, 0x3d, 0x0e, 0x22, 0xa4, 0x02, 0x99, 0xfb,
+	0x2b, 0x5c, 0xef, 0xc7, 0x27, 0x3c, 0xd0, 0x62, 0xcd, 0xeb, 0x7d, 0xb0, 0x54, 0xef, 0xde, 0x85,
+	0x1b, 0x17, 0x0c, 0xa5, 0xca, 0xff, 0x53, 0x85, 0x1b, 0x25, 0x6a, 0x3e, 0x4f, 0x74, 0xf9, 0xc9,
+	0x37, 0xd0, 0xe0, 0x99, 0x4c, 0x32, 0xe9, 0x54, 0x2e, 0xaa, 0x80, 0xd2, 0xe6, 0x4b, 0x95, 0x7a,
+	0xdf, 0x42, 0x48, 0x1f, 0xb6, 0x7e, 0xd2, 0x5f, 0x47, 0x48, 0x19, 0x8a, 0xd4, 0xa9, 0xbe, 0x3b,
+	0xc7, 0x32, 0x92, 0x7c, 0x01, 0xdb, 0x74, 0x44, 0x63, 0xc6, 0x63, 0x64, 0x5a, 0x31, 0x4e, 0xad,
+	0x53, 0xeb, 0x36, 0xfd, 0x15, 0xab, 0xf2, 0x0b, 0x4c, 0xf0, 0x21, 0x8f, 0x5f, 0x70, 0x86, 0xce,
+	0x86, 0x56, 0xd3, 0x8a, 0x95, 0x74, 0xa0, 0x15, 0xe4, 0x87, 0xdc, 0x5f, 0x38, 0x75, 0x4d, 0x56,
+	0x36, 0xb9, 0xcf, 0x80, 0x94, 0x12, 0x42, 0xe3, 0x00, 0x3f, 0x5c, 0x38, 0x47, 0xe5, 0xe4, 0xaa,
+	0x40, 0xbf, 0x67, 0x0c, 0x19, 0xf9, 0x0a, 0x36, 0xd4, 0x35, 0xb0, 0x5c, 0xb7, 0xdf, 0x2a, 0x6e,
+	0x5f, 0xbb, 0xba, 0x47, 0x70, 0xad, 0x60, 0xba, 0x94, 0x98, 0xef, 0xc0, 0xed, 0x92, 0x12, 0xf8,
+	0x58, 0x0e, 0xc3, 0x39, 0xf2, 0x4c, 0x1e, 0xfe, 0x15, 0x20, 0x32, 0x64, 0xee, 0x33, 0x70, 0x0a,
+	0x87, 0x7d, 0x41, 0xe3, 0x60, 0x3a, 0xc0, 0x08, 0x03, 0x25, 0x8b, 0x1d, 0x68, 0xa4, 0xa7, 0xa1,
+	0x0c, 0xa6, 0xf6, 0xb2, 0xda, 0x95, 0xb2, 0x8f, 0xb4, 0xa7, 0x2e, 0x75, 0xd3, 0xb7, 0x2b, 0x37,
+	0x80, 0x8f, 0x97, 0x13, 0x30, 0x9c, 0x0a, 0x2e, 0x65, 0x64, 0xa8, 0xd4, 0xc9, 0xfa, 0x2c, 0xa7,
+	0x32, 0x2b, 0xf2, 0x25, 0xd4, 0xb3, 0x58, 0x86, 0x91, 0x15, 0x4d, 0xdb, 0x33, 0x4d, 0xd9, 0xcb,
+	0x9b, 0xb2, 0x37, 0xcc, 0x9b, 0xb2, 0x6f, 0x1c, 0xdd, 0x29, 0xec, 0x94, 0x4e, 0x14, 0xf0, 0x04,
+	0x5f, 0x51, 0x11, 0x87, 0xf1, 0x64, 0xed, 0x1e, 0x3b, 0xd0, 0x08, 0xe3, 0x24, 0x93, 0x4a, 0x99,
+	0x4a, 0x00, 0x76, 0x45, 0x76, 0x01, 0x04, 0x8e, 0x51, 0x60, 0x1c, 0x60, 0xae, 0xb4, 0x92, 0xc5,
+	0xfd, 0xbf, 0x5a, 0xbe, 0x88, 0x83, 0x6c, 0x3e, 0xa7, 0x62, 0x41, 0x8e, 0xa1, 0x91, 0xea, 0x16,
+	0xa4, 0x77, 0xd9, 0xde, 0x7b, 0xf8, 0x3e, 0x57, 0x51, 0x03, 0x3d, 0xf3, 0xf2, 0x2d, 0x8f, 0x8a,
+	0x23, 0x87, 0xf6, 0x99, 0x4d, 0x69, 0xc9, 0x42, 0xbe, 0x86, 0xcd, 0x7c, 0x74, 0x39, 0x35, 0x9d,
+	0xa6, 0x9b, 0xe7, 0xd2, 0xf4, 0xc4, 0x3a, 0xf8, 0x67, 0xae, 0xe4, 0x13, 0x68, 0xaa, 0x04, 0x1c,
+	0xf0, 0x2c, 0x96, 0xfa, 0x7e, 0xd4, 0xfd, 0xc2, 0x40, 0xba, 0x70, 0x75, 0xac, 0x85, 0x35, 0x3c,
+	0xf3, 0xa9, 0x6b, 0x9f, 0x55, 0x73, 0x21, 0xbc, 0xc6, 0x7b, 0x08, 0x4f, 0x1d, 0xca, 0xf4, 0x07,
+	0x45, 0xe4, 0x5c, 0x31, 0x87, 0x2a, 0x2c, 0xc5, 0xff, 0x41, 0xf8, 0x37, 0x3a, 0x9b, 0x9d, 0x4a,
+	0xb7, 0xe6, 0x97, 0x2c, 0xee, 0x7f, 0x95, 0x55, 0x31, 0xa5, 0x3e, 0x26, 0x11, 0x5d, 0x20, 0x23,
+	0x0e, 0x5c, 0x31, 0xa5, 0x35, 0xc3, 0xa9, 0xe9, 0xe7, 0x4b, 0xf2, 0x0b, 0x34, 0xc6, 0xb1, 0x8f,
+	0x63, 0x53, 0xea, 0xd6, 0xde, 0xb7, 0xeb, 0xa7, 0xd6, 0x1a, 0x72, 0xef, 0xa9, 0xc6, 0x9b, 0xf9,
+	0x65, 0xc9, 0xda, 0xbf, 0x41, 0xab, 0x64, 0x7e, 0xc3, 0x04, 0x7b, 0xb0, 0x3c, 0xc1, 0xd6, 0xe7,
+	0x48, 0xd3, 0x94, 0x87, 0xd7, 0x8f, 0xd0, 0xb2, 0x53, 0x4d, 0xa8, 0x2b, 0xf7, 0xdd, 0x52, 0x9b,
+	0xbf, 0xfb, 0xd6, 0x66, 0xf1, 0xc6, 0x16, 0xff, 0x12, 0xb6, 0x34, 0x5f, 0x16, 0x98, 0x0b, 0x4e,
+	0x0e, 0xa1, 0x21, 0x30, 0xcd, 0xa2, 0xbc, 0xb7, 0xdf, 0x7b, 0x57, 0x4e, 0x2b, 0x52, 0x03, 0x76,
+	0xb7, 0x6c, 0x9c, 0xb3, 0x30, 0x49, 0x90, 0xb9, 0xfb, 0x66, 0xa4, 0x5f, 0xaa, 0x37, 0x7d, 0x66,
+	0x38, 0x8e, 0x79, 0x64, 0x3b, 0x44, 0x29, 0xce, 0x66, 0xbe, 0xf1, 0xfe, 0xe6, 0xef, 0x0d, 0x53,
+	0xb1, 0x51, 0x43, 0xab, 0xfd, 0xfe, 0xeb, 0x01, 0x00, 0xda, 0xe2, 0x73, 0xc5, 0x10, 0x0a, 0x00,
+	0x00,
 }
@@ -1,8 +1,12 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/api/events"
 	"github.com/fission/fission-workflows/pkg/api/projectors"
@@ -11,21 +15,70 @@ import (
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 )
 
-const ErrInvocationCanceled = "workflow invocation was canceled"
+const (
+	ErrInvocationCanceled = "workflow invocation was canceled"
+
+	// ErrInvocationCallerCanceled is the reason of the cancellation of an invocation of which the synchronous caller
+	// went away, because its context was canceled or hit its deadline.
+	ErrInvocationCallerCanceled = "workflow invocation was canceled by its caller"
+)
+
+var (
+	// ErrInvocationNotFinished is returned when replaying the tasks of an invocation that is still running.
+	ErrInvocationNotFinished = errors.New("invocation has not finished")
+
+	// ErrNoFailedTasks is returned when replaying the tasks of an invocation that has no failed tasks.
+	ErrNoFailedTasks = errors.New("invocation has no failed tasks")
+)
 
 // Invocation contains the API functionality for controlling (workflow) invocations.
 // This includes starting, stopping, and completing invocations.
 type Invocation struct {
-	es fes.Backend
+	es       fes.Backend
+	admitter Admitter
+
+	// defaultTimeout is the timeout of the invocations of workflows that do not specify a timeout of their own.
+	defaultTimeout time.Duration
+
+	// summaryLock serializes the summaries, to ensure that concurrent terminal events do not both append a summary.
+	summaryLock *sync.Mutex
+
+	// replayLock serializes the replays, to ensure that an invocation is not replayed again before it has finished.
+	replayLock *sync.Mutex
+}
+
+// Admitter decides whether an invocation can be created. It returns the spec to create the invocation with, which
+// can be a modified version of the provided spec, or an error if the invocation should not be created.
+type Admitter interface {
+	Admit(ctx context.Context, spec *types.WorkflowInvocationSpec) (*types.WorkflowInvocationSpec, error)
 }
 
 // NewInvocationAPI creates the Invocation API.
 func NewInvocationAPI(esClient fes.Backend) *Invocation {
-	return &Invocation{esClient}
+	return &Invocation{
+		es:          esClient,
+		summaryLock: &sync.Mutex{},
+		replayLock:  &sync.Mutex{},
+	}
+}
+
+// SetAdmitter sets the admitter that reviews the invocations before they are created. If nil, all valid invocations
+// are admitted.
+func (ia *Invocation) SetAdmitter(admitter Admitter) {
+	ia.admitter = admitter
+}
+
+// SetDefaultTimeout sets the time that invocations are given to complete if neither the invocation specifies a
+// deadline nor its workflow a timeout. If 0, these invocations do not time out.
+func (ia *Invocation) SetDefaultTimeout(timeout time.Duration) {
+	ia.defaultTimeout = timeout
 }
 
 // Invoke triggers the start of the invocation using the provided specification.
@@ -38,6 +91,23 @@ func (ia *Invocation) Invoke(spec *types.WorkflowInvocationSpec, opts ...CallOpt
 		return "", err
 	}
 
+	// Reject the invocations of quarantined workflows.
+	if quarantine := spec.GetWorkflow().GetStatus().GetQuarantine(); quarantine.GetQuarantined() {
+		return "", ErrWorkflowQuarantined{WorkflowID: spec.GetWorkflow().ID(), Quarantine: quarantine}
+	}
+
+	// Enforce the admission policies; a mutated spec is validated again.
+	if ia.admitter != nil {
+		spec, err = ia.admitter.Admit(cfg.ctx, spec)
+		if err != nil {
+			return "", err
+		}
+		err = validate.WorkflowInvocationSpec(spec)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// Ensure that te body input is also accessible on the default parameter
 	// TODO remove once default input field is removed
 	if spec.Inputs != nil && spec.Inputs[types.InputMain] == nil {
@@ -46,6 +116,11 @@ func (ia *Invocation) Invoke(spec *types.WorkflowInvocationSpec, opts ...CallOpt
 		}
 	}
 
+	// Resolve the deadline once, on creation, so that the invocation keeps the same deadline across restarts.
+	if spec.Deadline == nil {
+		spec.Deadline = ia.resolveDeadline(spec)
+	}
+
 	invocationID := fmt.Sprintf("wi-%s", util.UID())
 
 	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID),
@@ -65,6 +140,7 @@ func (ia *Invocation) Invoke(spec *types.WorkflowInvocationSpec, opts ...CallOpt
 			logrus.Warnf("Failed to inject tracer context into event: %v", err)
 		}
 	}
+	setPrincipal(cfg.ctx, event)
 
 	err = ia.es.Append(event)
 	if err != nil {
@@ -74,49 +150,105 @@ func (ia *Invocation) Invoke(spec *types.WorkflowInvocationSpec, opts ...CallOpt
 	return invocationID, nil
 }
 
+// resolveDeadline returns the deadline of an invocation that does not specify one, based on the timeout of its
+// workflow or otherwise the default timeout. If the timeout is not positive, nil is returned, which means that the
+// invocation does not time out. The timeout of the workflow has been validated when the workflow was created (see
+// validate.WorkflowSpec).
+func (ia *Invocation) resolveDeadline(spec *types.WorkflowInvocationSpec) *timestamp.Timestamp {
+	timeout := ia.defaultTimeout
+	if wfTimeout := spec.GetWorkflow().GetSpec().GetTimeout(); wfTimeout != nil {
+		if d, err := ptypes.Duration(wfTimeout); err == nil {
+			timeout = d
+		}
+	}
+	if timeout <= 0 {
+		return nil
+	}
+	deadline, err := ptypes.TimestampProto(time.Now().Add(timeout))
+	if err != nil {
+		return nil
+	}
+	return deadline
+}
+
 // Cancel halts an invocation. This does not guarantee that tasks currently running are halted,
 // but beyond the invocation will not progress any further than those tasks. The state of the invocation will
-// become ABORTED. If the API fails to append the event to the event store, it will return an error.
-func (ia *Invocation) Cancel(invocationID string) error {
+// become ABORTED. The reason of the cancellation can be provided with WithReason; it defaults to
+// ErrInvocationCanceled. If the API fails to append the event to the event store, it will return an error.
+func (ia *Invocation) Cancel(invocationID string, opts ...CallOption) error {
+	cfg := parseCallOptions(opts)
 	if len(invocationID) == 0 {
 		return validate.NewError("invocationID", errors.New("id should not be empty"))
 	}
+	reason := cfg.reason
+	if len(reason) == 0 {
+		reason = ErrInvocationCanceled
+	}
 
 	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID),
 		&events.InvocationCanceled{
 			Error: &types.Error{
-				Message: ErrInvocationCanceled,
+				Message: reason,
 			},
 		})
 	if err != nil {
 		return err
 	}
 	event.Hints = &fes.EventHints{Completed: true}
+	setPrincipal(cfg.ctx, event)
 	err = ia.es.Append(event)
 	if err != nil {
 		return err
 	}
+	ia.summarize(invocationID)
 	return nil
 }
 
-// Complete forces the completion of an invocation. This function - used by the controller - is the only way
-// to ensure that a workflow invocation turns into the COMPLETED state.
+// Complete forces the completion of an invocation. This function and CompleteEarly - used by the controller - are
+// the only ways to ensure that a workflow invocation turns into the COMPLETED state.
 // If the API fails to append the event to the event store, it will return an error.
 func (ia *Invocation) Complete(invocationID string, output *typedvalues.TypedValue, outputHeaders *typedvalues.TypedValue) error {
+	return ia.CompleteEarly(invocationID, output, outputHeaders, Completion{Mode: types.CompletionModeAll})
+}
+
+// Completion describes how an invocation was completed.
+type Completion struct {
+	// Mode is the completion mode that completed the invocation, such as types.CompletionModeAny.
+	Mode string
+
+	// CompletedBy contains the IDs of the tasks that triggered the completion.
+	CompletedBy []string
+
+	// AbandonedTasks contains the IDs of the tasks that had not finished at the time of the completion.
+	AbandonedTasks []string
+}
+
+// CompleteEarly changes the state of the invocation to SUCCEEDED before all of its tasks have finished.
+// The unfinished tasks are recorded as abandoned in the status of the invocation.
+// If the API fails to append the event to the event store, it will return an error.
+func (ia *Invocation) CompleteEarly(invocationID string, output *typedvalues.TypedValue,
+	outputHeaders *typedvalues.TypedValue, completion Completion) error {
 	if len(invocationID) == 0 {
 		return validate.NewError("invocationID", errors.New("id should not be empty"))
 	}
 
 	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID),
 		&events.InvocationCompleted{
-			Output:        output,
-			OutputHeaders: outputHeaders,
+			Output:         output,
+			OutputHeaders:  outputHeaders,
+			AbandonedTasks: completion.AbandonedTasks,
+			CompletionMode: completion.Mode,
+			CompletedBy:    completion.CompletedBy,
 		})
 	if err != nil {
 		return err
 	}
 	event.Hints = &fes.EventHints{Completed: true}
-	return ia.es.Append(event)
+	if err := ia.es.Append(event); err != nil {
+		return err
+	}
+	ia.summarize(invocationID)
+	return nil
 }
 
 // Fail changes the state of the invocation to FAILED.
@@ -127,23 +259,227 @@ func (ia *Invocation) Fail(invocationID string, errMsg error) error {
 		return validate.NewError("invocationID", errors.New("id should not be empty"))
 	}
 
-	var msg string
-	if errMsg != nil {
-		msg = errMsg.Error()
+	// Errors that carry a code, such as timeouts, are stored as-is to preserve the code.
+	failure, ok := errMsg.(*types.Error)
+	if !ok {
+		failure = &types.Error{}
+		if errMsg != nil {
+			failure.Message = errMsg.Error()
+		}
 	}
 	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID),
 		&events.InvocationFailed{
-			Error: &types.Error{
-				Message: msg,
-			},
+			Error: failure,
 		})
 	if err != nil {
 		return err
 	}
 	event.Hints = &fes.EventHints{Completed: true}
+	if err := ia.es.Append(event); err != nil {
+		return err
+	}
+	ia.summarize(invocationID)
+	return nil
+}
+
+// ExceedSoftTimeout records that the invocation has exceeded the soft timeout of its workflow. It does not change the
+// state of the invocation.
+func (ia *Invocation) ExceedSoftTimeout(invocationID string) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+
+	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID),
+		&events.InvocationSoftTimeoutExceeded{})
+	if err != nil {
+		return err
+	}
 	return ia.es.Append(event)
 }
 
+// SelectBranch records the branch that was selected by the switch of the invocation. The tasks of the other branches of
+// the switch should be skipped.
+func (ia *Invocation) SelectBranch(invocationID string, switchID string, branch string) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	if len(switchID) == 0 {
+		return validate.NewError("switchID", errors.New("id should not be empty"))
+	}
+
+	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID), &events.InvocationBranchSelected{
+		Switch: switchID,
+		Branch: branch,
+	})
+	if err != nil {
+		return err
+	}
+	return ia.es.Append(event)
+}
+
+// ThrottleTask records that the execution of the task of the invocation is deferred by its rate limit until the
+// provided time.
+func (ia *Invocation) ThrottleTask(invocationID string, taskID string, until time.Time) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	if len(taskID) == 0 {
+		return validate.NewError("taskID", errors.New("id should not be empty"))
+	}
+	ts, err := ptypes.TimestampProto(until)
+	if err != nil {
+		return err
+	}
+
+	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID), &events.InvocationTaskThrottled{
+		TaskId: taskID,
+		Until:  ts,
+	})
+	if err != nil {
+		return err
+	}
+	return ia.es.Append(event)
+}
+
+// WarnUndefinedReferences records that the inputs of the task of the invocation reference keys that are undefined in
+// the scope of the invocation. It does not change the state of the invocation or the task.
+func (ia *Invocation) WarnUndefinedReferences(invocationID string, taskID string, inputs []string,
+	refs []string) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	if len(taskID) == 0 {
+		return validate.NewError("taskID", errors.New("id should not be empty"))
+	}
+
+	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID), &events.InvocationScopeWarning{
+		TaskId:     taskID,
+		Inputs:     inputs,
+		References: refs,
+	})
+	if err != nil {
+		return err
+	}
+	return ia.es.Append(event)
+}
+
+// ReplayFailedTasks reopens the finished invocation to execute its failed tasks again, along with the tasks that
+// depend on them. The outputs of the other tasks are preserved. The function references of the replayed tasks can be
+// overridden with fnRefs, with the key being the task id. It returns the IDs of the replayed tasks.
+//
+// An invocation can only be replayed once it has finished (ErrInvocationNotFinished), and only if it has failed tasks
+// (ErrNoFailedTasks). The replayed invocation is given its original runtime again, measured from the replay.
+func (ia *Invocation) ReplayFailedTasks(invocationID string, fnRefs map[string]*types.FnRef,
+	opts ...CallOption) ([]string, error) {
+	cfg := parseCallOptions(opts)
+	if len(invocationID) == 0 {
+		return nil, validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	ia.replayLock.Lock()
+	defer ia.replayLock.Unlock()
+
+	aggregate := projectors.NewInvocationAggregate(invocationID)
+	invocationEvents, err := ia.es.Get(aggregate)
+	if err != nil {
+		return nil, err
+	}
+	if len(invocationEvents) == 0 {
+		return nil, fes.ErrEntityNotFound.WithAggregate(&aggregate)
+	}
+	entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+	if err != nil {
+		return nil, err
+	}
+	invocation := entity.(*types.WorkflowInvocation)
+	if !invocation.GetStatus().Finished() {
+		return nil, ErrInvocationNotFinished
+	}
+
+	taskIDs := replayedTasks(invocation)
+	if len(taskIDs) == 0 {
+		return nil, ErrNoFailedTasks
+	}
+	for taskID, fnRef := range fnRefs {
+		if !containsString(taskIDs, taskID) {
+			return nil, validate.NewError("fnRefs", fmt.Errorf("task '%s' is not replayed", taskID))
+		}
+		if fnRef == nil || !fnRef.IsValid() {
+			return nil, validate.NewError("fnRefs", fmt.Errorf("invalid function reference for task '%s'", taskID))
+		}
+	}
+
+	event, err := fes.NewEvent(aggregate, &events.InvocationTasksReplayed{
+		TaskIds: taskIDs,
+		FnRefs:  fnRefs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	event.Hints = &fes.EventHints{Reopened: true}
+	setPrincipal(cfg.ctx, event)
+	if err := ia.es.Append(event); err != nil {
+		return nil, err
+	}
+	return taskIDs, nil
+}
+
+// replayedTasks returns the sorted IDs of the tasks that are replayed: the failed tasks of the invocation, and the
+// tasks that (transitively) depend on them. Tasks that have not run are not included, as they are pending already.
+func replayedTasks(invocation *types.WorkflowInvocation) []string {
+	// The results of tasks that finished after the invocation are the latest state of those tasks.
+	runs := map[string]*types.TaskInvocation{}
+	for taskID, run := range invocation.GetStatus().GetTasks() {
+		runs[taskID] = run
+	}
+	for taskID, run := range invocation.GetStatus().GetLateTasks() {
+		runs[taskID] = run
+	}
+
+	replayed := map[string]bool{}
+	for taskID, run := range runs {
+		if run.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED {
+			replayed[taskID] = true
+		}
+	}
+	if len(replayed) == 0 {
+		return nil
+	}
+	tasks := invocation.Tasks()
+	for changed := true; changed; {
+		changed = false
+		for taskID, task := range tasks {
+			if replayed[taskID] {
+				continue
+			}
+			for dep := range task.GetSpec().GetRequires() {
+				if replayed[dep] {
+					replayed[taskID] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var taskIDs []string
+	for taskID := range replayed {
+		if _, ok := runs[taskID]; ok {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	sort.Strings(taskIDs)
+	return taskIDs
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
 // AddTask provides functionality to add a task to a specific invocation (instead of a workflow).
 // This allows users to modify specific invocations (see dynamic API).
 // The error can be a validate.Err, proto marshall error, or a fes error.
@@ -164,3 +500,84 @@ func (ia *Invocation) AddTask(invocationID string, task *types.Task) error {
 	}
 	return ia.es.Append(event)
 }
+
+// summarize appends the InvocationSummary of the finished invocation, unless the invocation already has a summary
+// since it was last replayed.
+//
+// The invocation can receive more than one terminal event, for example if the controller completes an invocation that
+// was canceled concurrently. The summary is based on the events of the invocation at the time of its first terminal
+// event that is summarized, and is appended only once. A failure to summarize the invocation does not affect the
+// outcome of the invocation, so it is only logged.
+func (ia *Invocation) summarize(invocationID string) {
+	ia.summaryLock.Lock()
+	defer ia.summaryLock.Unlock()
+	aggregate := projectors.NewInvocationAggregate(invocationID)
+	invocationEvents, err := ia.es.Get(aggregate)
+	if err != nil {
+		logrus.Warnf("Failed to summarize invocation %s: %v", invocationID, err)
+		return
+	}
+	// Only the summaries since the last replay of the invocation summarize its current outcome.
+	var summarized bool
+	for _, event := range invocationEvents {
+		switch event.GetType() {
+		case events.EventInvocationSummary:
+			summarized = true
+		case events.EventInvocationTasksReplayed:
+			summarized = false
+		}
+	}
+	if summarized {
+		return
+	}
+	entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+	if err != nil {
+		logrus.Warnf("Failed to summarize invocation %s: %v", invocationID, err)
+		return
+	}
+	invocation := entity.(*types.WorkflowInvocation)
+	if invocation.GetStatus() == nil || !invocation.GetStatus().Finished() {
+		return
+	}
+
+	event, err := fes.NewEvent(aggregate, NewInvocationSummary(invocation))
+	if err != nil {
+		logrus.Warnf("Failed to summarize invocation %s: %v", invocationID, err)
+		return
+	}
+	event.Hints = &fes.EventHints{Completed: true}
+	if err := ia.es.Append(event); err != nil {
+		logrus.Warnf("Failed to summarize invocation %s: %v", invocationID, err)
+	}
+}
+
+// NewInvocationSummary summarizes the outcome of the finished invocation.
+func NewInvocationSummary(invocation *types.WorkflowInvocation) *events.InvocationSummary {
+	status := invocation.GetStatus()
+	summary := &events.InvocationSummary{
+		Status:     status.GetStatus(),
+		WorkflowId: invocation.GetSpec().GetWorkflowId(),
+		TaskCount:  int32(len(status.GetTasks())),
+		Error:      status.GetError(),
+	}
+	if len(summary.WorkflowId) == 0 {
+		summary.WorkflowId = invocation.GetSpec().GetWorkflow().ID()
+	}
+	for _, task := range status.GetTasks() {
+		if task.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED {
+			summary.FailedTaskCount++
+		}
+	}
+	createdAt, err := ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt())
+	finishedAt, err2 := ptypes.Timestamp(status.GetUpdatedAt())
+	if err == nil && err2 == nil {
+		summary.Duration = ptypes.DurationProto(finishedAt.Sub(createdAt))
+	}
+	if status.Successful() {
+		summary.OutputTask = invocation.GetSpec().GetWorkflow().GetSpec().GetOutputTask()
+		if output := status.GetOutput(); output != nil {
+			summary.OutputSize = int64(proto.Size(output))
+		}
+	}
+	return summary
+}
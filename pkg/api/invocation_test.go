@@ -0,0 +1,314 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func invocationSummaries(t *testing.T, backend fes.Backend, invocationID string) []*events.InvocationSummary {
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+	assert.NoError(t, err)
+	var summaries []*events.InvocationSummary
+	for _, event := range invocationEvents {
+		if event.GetType() != events.EventInvocationSummary {
+			continue
+		}
+		summary := &events.InvocationSummary{}
+		assert.NoError(t, ptypes.UnmarshalAny(event.GetData(), summary))
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func TestInvocation_SummaryIsAppendedOnce(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := NewInvocationAPI(backend)
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{
+		Metadata: types.NewObjectMetadata("wf"),
+		Spec:     &types.WorkflowSpec{OutputTask: "last"},
+	}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	assert.Empty(t, invocationSummaries(t, backend, invocationID))
+
+	output := typedvalues.MustWrap("done")
+	assert.NoError(t, invocationAPI.Complete(invocationID, output, nil))
+	// Duplicate terminal events do not result in additional summaries.
+	assert.NoError(t, invocationAPI.Complete(invocationID, output, nil))
+	assert.NoError(t, invocationAPI.Fail(invocationID, errors.New("late failure")))
+
+	summaries := invocationSummaries(t, backend, invocationID)
+	assert.Len(t, summaries, 1)
+	summary := summaries[0]
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, summary.GetStatus())
+	assert.Equal(t, "wf", summary.GetWorkflowId())
+	assert.Equal(t, "last", summary.GetOutputTask())
+	assert.True(t, summary.GetOutputSize() > 0)
+	assert.NotNil(t, summary.GetDuration())
+	assert.Nil(t, summary.GetError())
+
+	// The summary does not change the invocation.
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+	assert.NoError(t, err)
+	entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+	assert.NoError(t, err)
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, entity.(*types.WorkflowInvocation).GetStatus().GetStatus())
+}
+
+func TestNewInvocationSummary(t *testing.T) {
+	createdAt := time.Now().Add(-time.Minute)
+	invocation := &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{Id: "wi-1"},
+		Spec:     &types.WorkflowInvocationSpec{WorkflowId: "wf"},
+		Status: &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_FAILED,
+			Error:  &types.Error{Message: "boom"},
+			Tasks: map[string]*types.TaskInvocation{
+				"a": {Status: &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_SUCCEEDED}},
+				"b": {Status: &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_FAILED}},
+				"c": {Status: &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_SKIPPED}},
+			},
+			Output: typedvalues.MustWrap("partial"),
+		},
+	}
+	invocation.Metadata.CreatedAt, _ = ptypes.TimestampProto(createdAt)
+	invocation.Status.UpdatedAt, _ = ptypes.TimestampProto(createdAt.Add(3 * time.Second))
+
+	summary := NewInvocationSummary(invocation)
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, summary.GetStatus())
+	assert.EqualValues(t, 3, summary.GetTaskCount())
+	assert.EqualValues(t, 1, summary.GetFailedTaskCount())
+	assert.Equal(t, "boom", summary.GetError().GetMessage())
+	duration, err := ptypes.Duration(summary.GetDuration())
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Second, duration)
+	// The output of a failed invocation is not referenced.
+	assert.Empty(t, summary.GetOutputTask())
+	assert.Zero(t, summary.GetOutputSize())
+}
+
+func TestInvocation_ThrottleTask(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := NewInvocationAPI(backend)
+	taskAPI := NewTaskAPI(nil, backend, nil)
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{
+		Metadata: types.NewObjectMetadata("wf"),
+		Spec:     &types.WorkflowSpec{OutputTask: "call"},
+	}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+
+	until := time.Now().Add(time.Second)
+	assert.NoError(t, invocationAPI.ThrottleTask(invocationID, "call", until))
+	throttle := project().GetStatus().GetThrottledTasks()["call"]
+	assert.NotNil(t, throttle.GetSince())
+	assert.Equal(t, until.Unix(), throttle.GetUntil().GetSeconds())
+
+	// Throttling the task again extends the deferral, but retains the time at which the task was first throttled.
+	assert.NoError(t, invocationAPI.ThrottleTask(invocationID, "call", until.Add(time.Minute)))
+	rethrottle := project().GetStatus().GetThrottledTasks()["call"]
+	assert.Equal(t, throttle.GetSince(), rethrottle.GetSince())
+	assert.Equal(t, until.Add(time.Minute).Unix(), rethrottle.GetUntil().GetSeconds())
+
+	// Once the task has started, it is no longer throttled.
+	invocation := project()
+	task := types.NewTask("call", "api")
+	task.Status.FnRef = &types.FnRef{Runtime: "mock", ID: "api"}
+	assert.NoError(t, taskAPI.Start(types.NewTaskInvocationSpec(invocation, task, time.Now())))
+	assert.Empty(t, project().GetStatus().GetThrottledTasks())
+}
+
+func TestInvocation_ReplayFailedTasks(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := NewInvocationAPI(backend)
+	taskAPI := NewTaskAPI(nil, backend, nil)
+
+	// c is independent of a, b depends on a, and d depends on b.
+	wf := &types.Workflow{
+		Metadata: types.NewObjectMetadata("wf"),
+		Spec:     &types.WorkflowSpec{OutputTask: "d"},
+		Status:   &types.WorkflowStatus{Tasks: map[string]*types.Task{}},
+	}
+	for _, task := range []*types.Task{
+		types.NewTask("a", "fn"),
+		types.NewTask("b", "fn"),
+		types.NewTask("c", "fn"),
+		types.NewTask("d", "fn"),
+	} {
+		task.Status.FnRef = &types.FnRef{Runtime: "mock", ID: "fn"}
+		wf.Status.Tasks[task.ID()] = task
+	}
+	wf.Status.Tasks["b"].Spec.Require("a")
+	wf.Status.Tasks["d"].Spec.Require("b")
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = wf
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	for _, taskID := range []string{"a", "b", "c"} {
+		task, _ := project().Task(taskID)
+		assert.NoError(t, taskAPI.Start(types.NewTaskInvocationSpec(project(), task, time.Now())))
+	}
+	assert.NoError(t, taskAPI.Succeed(invocationID, "c", typedvalues.MustWrap("c")))
+	assert.NoError(t, taskAPI.Fail(invocationID, "a", "a failed"))
+
+	// The invocation cannot be replayed while it is still running.
+	_, err = invocationAPI.ReplayFailedTasks(invocationID, nil)
+	assert.Equal(t, ErrInvocationNotFinished, err)
+
+	assert.NoError(t, invocationAPI.Fail(invocationID, errors.New("a failed")))
+	// A late result of b is the latest state of b.
+	assert.NoError(t, taskAPI.Succeed(invocationID, "b", typedvalues.MustWrap("b")))
+
+	// Only the function references of the replayed tasks can be overridden.
+	override := &types.FnRef{Runtime: "mock", ID: "fixed"}
+	_, err = invocationAPI.ReplayFailedTasks(invocationID, map[string]*types.FnRef{"c": override})
+	assert.Error(t, err)
+	_, err = invocationAPI.ReplayFailedTasks(invocationID, map[string]*types.FnRef{"a": {}})
+	assert.Error(t, err)
+
+	taskIDs, err := invocationAPI.ReplayFailedTasks(invocationID, map[string]*types.FnRef{"a": override})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, taskIDs)
+
+	invocation := project()
+	assert.Equal(t, types.WorkflowInvocationStatus_IN_PROGRESS, invocation.GetStatus().GetStatus())
+	assert.Nil(t, invocation.GetStatus().GetError())
+	assert.NotNil(t, invocation.GetStatus().GetReplayedAt())
+	assert.Empty(t, invocation.GetStatus().GetLateTasks())
+	assert.Len(t, invocation.GetStatus().GetTasks(), 1)
+	assert.Equal(t, "c", typedvalues.MustUnwrap(invocation.GetStatus().GetTasks()["c"].GetStatus().GetOutput()))
+	task, _ := invocation.Task("a")
+	assert.Equal(t, override, task.GetStatus().GetFnRef())
+
+	// The replayed invocation cannot be replayed again until it has finished.
+	_, err = invocationAPI.ReplayFailedTasks(invocationID, nil)
+	assert.Equal(t, ErrInvocationNotFinished, err)
+
+	// The outcome of the replay is summarized as well.
+	assert.NoError(t, invocationAPI.Complete(invocationID, typedvalues.MustWrap("done"), nil))
+	summaries := invocationSummaries(t, backend, invocationID)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, summaries[1].GetStatus())
+
+	// Without failed tasks, there is nothing to replay.
+	_, err = invocationAPI.ReplayFailedTasks(invocationID, nil)
+	assert.Equal(t, ErrNoFailedTasks, err)
+}
+
+func TestInvocation_RejectQuarantinedWorkflow(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := NewInvocationAPI(backend)
+	workflowAPI := NewWorkflowAPI(backend, nil)
+	wfID, err := workflowAPI.Create(&types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "main",
+		Tasks:      types.Tasks{"main": {FunctionRef: "noop"}},
+	})
+	assert.NoError(t, err)
+	project := func() *types.Workflow {
+		wfEvents, err := backend.Get(projectors.NewWorkflowAggregate(wfID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflow().Project(nil, wfEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.Workflow)
+	}
+	invoke := func() error {
+		spec := types.NewWorkflowInvocationSpec(wfID, time.Now().Add(time.Minute))
+		spec.Workflow = project()
+		_, err := invocationAPI.Invoke(spec)
+		return err
+	}
+
+	assert.NoError(t, workflowAPI.Quarantine(wfID, &types.WorkflowQuarantine{
+		Reason:              "3 consecutive invocations failed",
+		ConsecutiveFailures: 3,
+	}))
+	quarantine := project().GetStatus().GetQuarantine()
+	assert.True(t, quarantine.GetQuarantined())
+	assert.Equal(t, int32(3), quarantine.GetConsecutiveFailures())
+	assert.Equal(t, int32(1), quarantine.GetQuarantines())
+	assert.NotNil(t, quarantine.GetQuarantinedAt())
+	err = invoke()
+	assert.IsType(t, ErrWorkflowQuarantined{}, err)
+	assert.Contains(t, err.Error(), "3 consecutive invocations failed")
+
+	// Once the quarantine is cleared, the workflow can be invoked again.
+	assert.NoError(t, workflowAPI.ClearQuarantine(wfID))
+	quarantine = project().GetStatus().GetQuarantine()
+	assert.False(t, quarantine.GetQuarantined())
+	assert.NotNil(t, quarantine.GetClearedAt())
+	assert.NoError(t, invoke())
+
+	// The number of quarantines is retained across quarantines.
+	assert.NoError(t, workflowAPI.Quarantine(wfID, &types.WorkflowQuarantine{Reason: "again"}))
+	quarantine = project().GetStatus().GetQuarantine()
+	assert.Equal(t, int32(2), quarantine.GetQuarantines())
+	assert.NotNil(t, quarantine.GetClearedAt())
+}
+
+func TestInvocation_ResolveDeadline(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := NewInvocationAPI(backend)
+	withTimeout := func(timeout time.Duration) *types.WorkflowSpec {
+		return &types.WorkflowSpec{Timeout: ptypes.DurationProto(timeout)}
+	}
+	invoke := func(deadline *time.Time, wfSpec *types.WorkflowSpec) *types.WorkflowInvocation {
+		spec := &types.WorkflowInvocationSpec{WorkflowId: "wf", Workflow: types.NewWorkflow("wf")}
+		spec.Workflow.Spec = wfSpec
+		if deadline != nil {
+			spec.Deadline, _ = ptypes.TimestampProto(*deadline)
+		}
+		invocationID, err := invocationAPI.Invoke(spec)
+		assert.NoError(t, err)
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	assertDeadline := func(invocation *types.WorkflowInvocation, timeout time.Duration) {
+		deadline, err := ptypes.Timestamp(invocation.GetSpec().GetDeadline())
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(timeout), deadline, time.Second)
+	}
+
+	// Without a timeout, the invocation does not time out.
+	assert.Nil(t, invoke(nil, nil).GetSpec().GetDeadline())
+
+	// The timeout of the workflow takes precedence over the default timeout, but not over the deadline of the
+	// invocation itself.
+	invocationAPI.SetDefaultTimeout(time.Minute)
+	assertDeadline(invoke(nil, nil), time.Minute)
+	assertDeadline(invoke(nil, withTimeout(time.Hour)), time.Hour)
+	deadline := time.Now().Add(time.Second)
+	assertDeadline(invoke(&deadline, withTimeout(time.Hour)), time.Second)
+
+	// A zero timeout of the workflow disables the default timeout.
+	assert.Nil(t, invoke(nil, withTimeout(0)).GetSpec().GetDeadline())
+}
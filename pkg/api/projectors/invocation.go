@@ -5,7 +5,9 @@ import (
 
 	"github.com/fission/fission-workflows/pkg/api/events"
 	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 )
 
@@ -63,9 +65,11 @@ func (i *WorkflowInvocation) project(wi *types.WorkflowInvocation, event *fes.Ev
 		}
 		wi.Spec = m.GetSpec()
 		wi.Status = &types.WorkflowInvocationStatus{
-			Status:       types.WorkflowInvocationStatus_IN_PROGRESS,
-			Tasks:        map[string]*types.TaskInvocation{},
-			DynamicTasks: map[string]*types.Task{},
+			Status:            types.WorkflowInvocationStatus_IN_PROGRESS,
+			Tasks:             map[string]*types.TaskInvocation{},
+			DynamicTasks:      map[string]*types.Task{},
+			RetryBudget:       retryBudget(wi, 0),
+			EffectivePriority: m.GetSpec().EffectivePriority(),
 		}
 	case *events.InvocationCanceled:
 		wi.Status.Status = types.WorkflowInvocationStatus_ABORTED
@@ -74,6 +78,9 @@ func (i *WorkflowInvocation) project(wi *types.WorkflowInvocation, event *fes.Ev
 		wi.Status.Status = types.WorkflowInvocationStatus_SUCCEEDED
 		wi.Status.Output = m.GetOutput()
 		wi.Status.OutputHeaders = m.GetOutputHeaders()
+		wi.Status.AbandonedTasks = m.GetAbandonedTasks()
+		wi.Status.CompletionMode = m.GetCompletionMode()
+		wi.Status.CompletedBy = m.GetCompletedBy()
 	case *events.InvocationTaskAdded:
 		task := m.GetTask()
 		if wi.Status.DynamicTasks == nil {
@@ -83,6 +90,68 @@ func (i *WorkflowInvocation) project(wi *types.WorkflowInvocation, event *fes.Ev
 	case *events.InvocationFailed:
 		wi.Status.Error = m.GetError()
 		wi.Status.Status = types.WorkflowInvocationStatus_FAILED
+	case *events.InvocationSoftTimeoutExceeded:
+		wi.Status.SoftTimeoutExceeded = true
+	case *events.InvocationBranchSelected:
+		if wi.Status.Branches == nil {
+			wi.Status.Branches = map[string]string{}
+		}
+		wi.Status.Branches[m.GetSwitch()] = m.GetBranch()
+	case *events.InvocationTaskThrottled:
+		if wi.Status.ThrottledTasks == nil {
+			wi.Status.ThrottledTasks = map[string]*types.TaskThrottle{}
+		}
+		since := event.GetTimestamp()
+		if throttle, ok := wi.Status.ThrottledTasks[m.GetTaskId()]; ok {
+			since = throttle.GetSince()
+		}
+		wi.Status.ThrottledTasks[m.GetTaskId()] = &types.TaskThrottle{
+			Since: since,
+			Until: m.GetUntil(),
+		}
+	case *events.InvocationScopeWarning:
+		if wi.Status.ScopeWarnings == nil {
+			wi.Status.ScopeWarnings = map[string]*types.ScopeWarning{}
+		}
+		wi.Status.ScopeWarnings[m.GetTaskId()] = &types.ScopeWarning{
+			Inputs:     m.GetInputs(),
+			References: m.GetReferences(),
+			RecordedAt: event.GetTimestamp(),
+		}
+	case *events.InvocationTasksReplayed:
+		wi.Status.Status = types.WorkflowInvocationStatus_IN_PROGRESS
+		wi.Status.Error = nil
+		wi.Status.Output = nil
+		wi.Status.OutputHeaders = nil
+		wi.Status.AbandonedTasks = nil
+		wi.Status.CompletionMode = ""
+		wi.Status.CompletedBy = nil
+		wi.Status.ReplayedAt = event.GetTimestamp()
+
+		// Results that arrived after the invocation finished are the latest state of their tasks.
+		if wi.Status.Tasks == nil {
+			wi.Status.Tasks = map[string]*types.TaskInvocation{}
+		}
+		for taskID, task := range wi.Status.LateTasks {
+			wi.Status.Tasks[taskID] = task
+		}
+		wi.Status.LateTasks = nil
+
+		// The replayed tasks are reset to pending, which causes the controller to execute them again.
+		for _, taskID := range m.GetTaskIds() {
+			delete(wi.Status.Tasks, taskID)
+			delete(wi.Status.ThrottledTasks, taskID)
+		}
+		for taskID, fnRef := range m.GetFnRefs() {
+			overrideFnRef(wi, taskID, fnRef)
+		}
+		wi.Status.PayloadSize = payloadSize(wi.Status.Tasks)
+		wi.Status.Retries = retries(wi.Status.Tasks)
+		wi.Status.RetryBudget = retryBudget(wi, wi.Status.Retries)
+		wi.Status.LoopIterations = loopIterations(wi.Status.Tasks)
+	case *events.InvocationSummary:
+		// The summary is derived from the preceding events; it does not change the invocation.
+		return nil
 	default:
 		//key := wi.Aggregate()
 		return fes.ErrUnsupportedEntityEvent.WithEvent(event)
@@ -124,7 +193,16 @@ func (i *WorkflowInvocation) applyTaskEvent(invocation *types.WorkflowInvocation
 		return fmt.Errorf("event does not belong to invocation: (expected: %v, value: %v)", wiAggregate, *event.Parent)
 	}
 	taskID := event.Aggregate.Id
-	task, ok := invocation.Status.Tasks[taskID]
+
+	// A task can finish after the invocation has already reached a terminal state, for example when a sibling task
+	// failed the invocation. These late results are kept separately to ensure that they do not alter the (final)
+	// state of the invocation.
+	if invocation.GetStatus().Finished() {
+		return i.applyLateTaskEvent(invocation, event)
+	}
+
+	prev, ok := invocation.Status.Tasks[taskID]
+	task := prev
 	if !ok {
 		entity, _ := i.taskRunProjector.NewProjection(*event.Aggregate)
 		task, _ = entity.(*types.TaskInvocation)
@@ -140,9 +218,134 @@ func (i *WorkflowInvocation) applyTaskEvent(invocation *types.WorkflowInvocation
 		invocation.Status.Tasks = map[string]*types.TaskInvocation{}
 	}
 	invocation.Status.Tasks[taskID] = task
+	// A throttled task is no longer throttled once it has started.
+	delete(invocation.Status.ThrottledTasks, taskID)
+	// Only the outputs of the updated task have changed, so the payload size is updated rather than recomputed.
+	invocation.Status.PayloadSize += taskPayloadSize(task) - taskPayloadSize(prev)
+	invocation.Status.Retries = retries(invocation.Status.Tasks)
+	invocation.Status.RetryBudget = retryBudget(invocation, invocation.Status.Retries)
+	invocation.Status.LoopIterations = loopIterations(invocation.Status.Tasks)
+	return nil
+}
+
+func (i *WorkflowInvocation) applyLateTaskEvent(invocation *types.WorkflowInvocation, event *fes.Event) error {
+	taskID := event.Aggregate.Id
+	task, ok := invocation.Status.LateTasks[taskID]
+	if !ok {
+		// Start from the state of the task at the time that the invocation finished.
+		if task, ok = invocation.Status.Tasks[taskID]; !ok {
+			entity, _ := i.taskRunProjector.NewProjection(*event.Aggregate)
+			task, _ = entity.(*types.TaskInvocation)
+		}
+	}
+	task = task.Copy()
+
+	err := i.taskRunProjector.project(task, event)
+	if err != nil {
+		return err
+	}
+
+	if invocation.Status.LateTasks == nil {
+		invocation.Status.LateTasks = map[string]*types.TaskInvocation{}
+	}
+	invocation.Status.LateTasks[taskID] = task
 	return nil
 }
 
+// overrideFnRef replaces the resolved function reference of the task of the invocation, which is either a dynamic
+// task or a task of the workflow.
+func overrideFnRef(invocation *types.WorkflowInvocation, taskID string, fnRef *types.FnRef) {
+	task, ok := invocation.GetStatus().GetDynamicTasks()[taskID]
+	if !ok {
+		wf := invocation.Workflow()
+		if wf == nil {
+			return
+		}
+		if wf.Status == nil {
+			wf.Status = &types.WorkflowStatus{}
+		}
+		if wf.Status.Tasks == nil {
+			wf.Status.Tasks = map[string]*types.Task{}
+		}
+		task, ok = wf.Status.Tasks[taskID]
+		if !ok {
+			task = &types.Task{
+				Metadata: &types.ObjectMetadata{Id: taskID},
+				Spec:     wf.GetSpec().TaskSpec(taskID),
+			}
+			wf.Status.Tasks[taskID] = task
+		}
+	}
+	if task.Status == nil {
+		task.Status = &types.TaskStatus{}
+	}
+	task.Status.FnRef = fnRef
+}
+
+// payloadSize computes the cumulative size (in bytes) of the outputs retained in the task invocations.
+func payloadSize(tasks map[string]*types.TaskInvocation) int64 {
+	var size int64
+	for _, task := range tasks {
+		size += taskPayloadSize(task)
+	}
+	return size
+}
+
+// taskPayloadSize computes the size (in bytes) of the outputs retained in the task invocation, which may be nil.
+func taskPayloadSize(task *types.TaskInvocation) int64 {
+	var size int
+	if output := task.GetStatus().GetOutput(); output != nil {
+		size += proto.Size(output)
+	}
+	if outputHeaders := task.GetStatus().GetOutputHeaders(); outputHeaders != nil {
+		size += proto.Size(outputHeaders)
+	}
+	return int64(size)
+}
+
+// retries computes the number of times that the tasks have been retried, based on the attempts of the task runs.
+func retries(tasks map[string]*types.TaskInvocation) int32 {
+	var count int32
+	for _, task := range tasks {
+		count += task.GetSpec().AttemptNumber() - 1
+	}
+	return count
+}
+
+// loopIterations computes the number of iterations that the loops have started, based on the succeeded task runs of
+// the loop functions.
+func loopIterations(tasks map[string]*types.TaskInvocation) map[string]int64 {
+	var iterations map[string]int64
+	for taskID, task := range tasks {
+		if task.GetStatus().GetStatus() != types.TaskInvocationStatus_SUCCEEDED {
+			continue
+		}
+		if n, ok := builtin.LoopIterations(task.GetSpec().GetFnRef(), task.GetStatus().GetOutput()); ok {
+			if iterations == nil {
+				iterations = map[string]int64{}
+			}
+			iterations[taskID] = n
+		}
+	}
+	return iterations
+}
+
+// retryBudget computes the status of the retry budget of the invocation, or nil if the workflow does not have one.
+func retryBudget(invocation *types.WorkflowInvocation, retries int32) *types.RetryBudgetStatus {
+	limit := invocation.Workflow().GetSpec().GetRetryBudget()
+	if limit <= 0 {
+		return nil
+	}
+	remaining := limit - retries
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &types.RetryBudgetStatus{
+		Limit:     limit,
+		Remaining: remaining,
+	}
+}
+
 func NewInvocationAggregate(invocationID string) fes.Aggregate {
 	return fes.Aggregate{
 		Id:   invocationID,
@@ -0,0 +1,93 @@
+package projectors
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newInvocationEvent(t *testing.T, invocationID string, payload proto.Message) *fes.Event {
+	event, err := fes.NewEvent(NewInvocationAggregate(invocationID), payload)
+	assert.NoError(t, err)
+	return event
+}
+
+func newTaskEvent(t *testing.T, invocationID string, taskID string, payload proto.Message) *fes.Event {
+	event, err := fes.NewEvent(NewTaskRunAggregate(taskID), payload)
+	assert.NoError(t, err)
+	parent := NewInvocationAggregate(invocationID)
+	event.Parent = &parent
+	return event
+}
+
+func TestWorkflowInvocation_LateTaskResult(t *testing.T) {
+	projector := NewWorkflowInvocation()
+	spec := &types.TaskInvocationSpec{TaskId: "task-1", InvocationId: "wi-1"}
+
+	// The invocation fails (e.g. due to a sibling task) while task-1 is still running.
+	entity, err := projector.Project(nil,
+		newInvocationEvent(t, "wi-1", &events.InvocationCreated{Spec: &types.WorkflowInvocationSpec{}}),
+		newTaskEvent(t, "wi-1", "task-1", &events.TaskStarted{Spec: spec}),
+		newInvocationEvent(t, "wi-1", &events.InvocationFailed{Error: &types.Error{Message: "sibling failed"}}))
+	assert.NoError(t, err)
+	failed := entity.(*types.WorkflowInvocation)
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, failed.GetStatus().GetStatus())
+
+	// Afterwards, task-1 succeeds.
+	entity, err = projector.Project(failed, newTaskEvent(t, "wi-1", "task-1", &events.TaskSucceeded{
+		Result: &types.TaskInvocationStatus{Output: typedvalues.MustWrap("late")},
+	}))
+	assert.NoError(t, err)
+	invocation := entity.(*types.WorkflowInvocation)
+
+	// The late result should be recorded, without altering the terminal state of the invocation.
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, invocation.GetStatus().GetStatus())
+	assert.Equal(t, "sibling failed", invocation.GetStatus().GetError().GetMessage())
+	task := invocation.GetStatus().GetTasks()["task-1"]
+	assert.Equal(t, types.TaskInvocationStatus_IN_PROGRESS, task.GetStatus().GetStatus())
+	lateTask, ok := invocation.GetStatus().GetLateTasks()["task-1"]
+	assert.True(t, ok)
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED, lateTask.GetStatus().GetStatus())
+	assert.Equal(t, "late", typedvalues.MustUnwrap(lateTask.GetStatus().GetOutput()))
+}
+
+func TestWorkflowInvocation_PayloadSize(t *testing.T) {
+	projector := NewWorkflowInvocation()
+	spec := func(taskID string) *types.TaskInvocationSpec {
+		return &types.TaskInvocationSpec{TaskId: taskID, InvocationId: "wi-1"}
+	}
+	small := typedvalues.MustWrap("small")
+	large := typedvalues.MustWrap(map[string]interface{}{"items": []interface{}{"a", "b", "c", "d"}})
+
+	entity, err := projector.Project(nil,
+		newInvocationEvent(t, "wi-1", &events.InvocationCreated{Spec: &types.WorkflowInvocationSpec{}}),
+		newTaskEvent(t, "wi-1", "task-1", &events.TaskStarted{Spec: spec("task-1")}),
+		newTaskEvent(t, "wi-1", "task-2", &events.TaskStarted{Spec: spec("task-2")}),
+		newTaskEvent(t, "wi-1", "task-1", &events.TaskSucceeded{Result: &types.TaskInvocationStatus{Output: small}}),
+		newTaskEvent(t, "wi-1", "task-2", &events.TaskSucceeded{Result: &types.TaskInvocationStatus{Output: large}}))
+	assert.NoError(t, err)
+	invocation := entity.(*types.WorkflowInvocation)
+	assert.Equal(t, int64(proto.Size(small)+proto.Size(large)), invocation.GetStatus().GetPayloadSize())
+	assert.Equal(t, payloadSize(invocation.GetStatus().GetTasks()), invocation.GetStatus().GetPayloadSize())
+
+	// The output of a task that is executed again replaces its previous output.
+	entity, err = projector.Project(invocation,
+		newTaskEvent(t, "wi-1", "task-2", &events.TaskStarted{Spec: spec("task-2")}),
+		newTaskEvent(t, "wi-1", "task-2", &events.TaskSucceeded{Result: &types.TaskInvocationStatus{Output: small}}))
+	assert.NoError(t, err)
+	invocation = entity.(*types.WorkflowInvocation)
+	assert.Equal(t, int64(2*proto.Size(small)), invocation.GetStatus().GetPayloadSize())
+
+	// Replayed tasks no longer retain their outputs.
+	entity, err = projector.Project(invocation,
+		newInvocationEvent(t, "wi-1", &events.InvocationFailed{Error: &types.Error{Message: "failed"}}),
+		newInvocationEvent(t, "wi-1", &events.InvocationTasksReplayed{TaskIds: []string{"task-1"}}))
+	assert.NoError(t, err)
+	invocation = entity.(*types.WorkflowInvocation)
+	assert.Equal(t, int64(proto.Size(small)), invocation.GetStatus().GetPayloadSize())
+}
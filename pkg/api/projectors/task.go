@@ -56,11 +56,14 @@ func (t *TaskRun) project(taskRun *types.TaskInvocation, event *fes.Event) error
 		}
 		taskRun.Spec = m.GetSpec()
 		taskRun.Status = &types.TaskInvocationStatus{
-			Status: types.TaskInvocationStatus_IN_PROGRESS,
+			Status:        types.TaskInvocationStatus_IN_PROGRESS,
+			Deadline:      m.GetSpec().GetDeadline(),
+			RetryDeadline: types.RetryDeadline(m.GetSpec().GetTask(), m.GetSpec().GetFirstAttemptAt()),
 		}
 	case *events.TaskSucceeded:
 		taskRun.Status.Output = m.GetResult().Output
 		taskRun.Status.OutputHeaders = m.GetResult().OutputHeaders
+		taskRun.Status.OutputHash = m.GetResult().OutputHash
 		taskRun.Status.Status = types.TaskInvocationStatus_SUCCEEDED
 	case *events.TaskFailed:
 		taskRun.Status.Error = m.GetError()
@@ -68,6 +71,10 @@ func (t *TaskRun) project(taskRun *types.TaskInvocation, event *fes.Event) error
 	case *events.TaskSkipped:
 		// TODO ensure that object (spec/status) is present
 		taskRun.Status.Status = types.TaskInvocationStatus_SKIPPED
+	case *events.TaskPolled:
+		taskRun.Status.Polls++
+		taskRun.Status.LastPollResult = m.GetResult()
+		taskRun.Status.LastPolledAt = event.GetTimestamp()
 	default:
 		key := fes.GetAggregate(taskRun)
 		return fes.ErrUnsupportedEntityEvent.WithAggregate(&key).WithEvent(event)
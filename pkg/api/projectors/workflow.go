@@ -6,6 +6,7 @@ import (
 	"github.com/fission/fission-workflows/pkg/api/events"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 )
 
@@ -63,8 +64,10 @@ func (w *Workflow) project(wf *types.Workflow, event *fes.Event) error {
 	case *events.WorkflowParsingFailed:
 		wf.Status.Error = m.GetError()
 		wf.Status.Status = types.WorkflowStatus_FAILED
+		wf.Status.ParseAttempts++
 	case *events.WorkflowParsed:
 		wf.Status.Status = types.WorkflowStatus_READY
+		wf.Status.Contract = wf.GetSpec().GetContract()
 		//wf.Status.Tasks = m.GetTasks()
 		for taskID, status := range m.GetTasks() {
 			spec := wf.GetSpec().TaskSpec(taskID)
@@ -81,6 +84,23 @@ func (w *Workflow) project(wf *types.Workflow, event *fes.Event) error {
 		}
 	case *events.WorkflowDeleted:
 		wf.Status.Status = types.WorkflowStatus_DELETED
+	case *events.WorkflowQuarantined:
+		quarantine := &types.WorkflowQuarantine{}
+		if m.GetQuarantine() != nil {
+			quarantine = proto.Clone(m.GetQuarantine()).(*types.WorkflowQuarantine)
+		}
+		quarantine.Quarantined = true
+		quarantine.Quarantines = wf.Status.GetQuarantine().GetQuarantines() + 1
+		quarantine.ClearedAt = wf.Status.GetQuarantine().GetClearedAt()
+		if quarantine.QuarantinedAt == nil {
+			quarantine.QuarantinedAt = event.GetTimestamp()
+		}
+		wf.Status.Quarantine = quarantine
+	case *events.WorkflowQuarantineCleared:
+		if quarantine := wf.Status.GetQuarantine(); quarantine.GetQuarantined() {
+			quarantine.Quarantined = false
+			quarantine.ClearedAt = event.GetTimestamp()
+		}
 	default:
 		return fes.ErrUnsupportedEntityEvent.WithEvent(event)
 	}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPurgeRate is the default maximum number of invocations that are deleted per second by a purge.
+const DefaultPurgeRate = 50
+
+var (
+	// ErrPurgeNotSupported is returned when purging invocations from an event store that does not support deletes.
+	ErrPurgeNotSupported = errors.New("event store does not support deleting invocations")
+
+	// ErrPurgeCutoffMissing is returned when purging invocations without a cutoff.
+	ErrPurgeCutoffMissing = errors.New("purge filter requires a cutoff")
+
+	metricPurgedInvocations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "api",
+		Name:      "purged_invocations_total",
+		Help:      "Number of invocations matched by purges, by result (purged, dry_run, skipped or failed)",
+	}, []string{"result"})
+
+	metricPurgeDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "workflows",
+		Subsystem: "api",
+		Name:      "purge_duration_seconds",
+		Help:      "Duration of purges of finished invocations",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 10),
+	})
+)
+
+func init() {
+	prometheus.MustRegister(metricPurgedInvocations, metricPurgeDuration)
+}
+
+// PurgeFilter selects the invocations to purge.
+type PurgeFilter struct {
+	// WorkflowID limits the purge to the invocations of the workflow, if set.
+	WorkflowID string
+
+	// Labels limits the purge to the invocations that have all of the labels, if set.
+	Labels map[string]string
+
+	// FinishedBefore is the cutoff of the purge: only the invocations that finished before it are purged.
+	FinishedBefore time.Time
+
+	// DryRun reports the invocations that match the filter without deleting them.
+	DryRun bool
+
+	// Authorize limits the purge to the invocations for which it returns true, such as the invocations that the
+	// principal of the request is allowed to purge. If nil, all invocations are considered.
+	Authorize func(invocation *types.WorkflowInvocation) bool
+}
+
+// PurgeResult contains the outcome of a purge.
+type PurgeResult struct {
+	// Purged contains the IDs of the invocations that were deleted, or that would be deleted in a dry run.
+	Purged []string
+
+	// Skipped is the number of invocations that match the filter, but that have not finished.
+	Skipped int
+}
+
+// Purger deletes the events of finished invocations, along with their cache entries, to reclaim the space that they
+// take up in the event store and the caches. It complements the eviction of the caches with on-demand cleanup.
+//
+// The deletes are rate-limited to avoid overloading the event store. Only event stores that implement
+// fes.EventDeleter support purges; the other event stores only support dry runs.
+type Purger struct {
+	es          fes.Backend
+	invocations *store.Invocations
+	interval    time.Duration
+}
+
+// NewPurger creates a purger that deletes at most rate invocations per second. If the rate is not positive,
+// DefaultPurgeRate is used.
+func NewPurger(es fes.Backend, invocations *store.Invocations, rate int) *Purger {
+	if rate <= 0 {
+		rate = DefaultPurgeRate
+	}
+	return &Purger{
+		es:          es,
+		invocations: invocations,
+		interval:    time.Second / time.Duration(rate),
+	}
+}
+
+// Purge deletes the finished invocations that match the filter, and returns the purged invocations. The invocations
+// that match the filter, but have not finished, are skipped. If the context is canceled, the purge stops, and returns
+// the invocations that have been purged so far along with the error of the context.
+func (p *Purger) Purge(ctx context.Context, filter PurgeFilter) (*PurgeResult, error) {
+	if filter.FinishedBefore.IsZero() {
+		return nil, ErrPurgeCutoffMissing
+	}
+	deleter, ok := p.es.(fes.EventDeleter)
+	if !ok && !filter.DryRun {
+		return nil, ErrPurgeNotSupported
+	}
+	start := time.Now()
+	defer func() {
+		metricPurgeDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	matches, skipped, err := p.match(filter)
+	if err != nil {
+		return nil, err
+	}
+	metricPurgedInvocations.WithLabelValues("skipped").Add(float64(skipped))
+	result := &PurgeResult{Skipped: skipped}
+	if filter.DryRun {
+		for _, invocation := range matches {
+			result.Purged = append(result.Purged, invocation.ID())
+		}
+		metricPurgedInvocations.WithLabelValues("dry_run").Add(float64(len(matches)))
+		return result, nil
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for i, invocation := range matches {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+		if err := p.delete(deleter, invocation); err != nil {
+			metricPurgedInvocations.WithLabelValues("failed").Inc()
+			logrus.Warnf("Failed to purge invocation %s: %v", invocation.ID(), err)
+			continue
+		}
+		metricPurgedInvocations.WithLabelValues("purged").Inc()
+		result.Purged = append(result.Purged, invocation.ID())
+	}
+	logrus.Infof("Purged %d invocations that finished before %v (skipped: %d)", len(result.Purged),
+		filter.FinishedBefore, result.Skipped)
+	return result, nil
+}
+
+// match returns the finished invocations that match the filter, ordered by their ID, along with the number of
+// matching invocations that have not finished.
+func (p *Purger) match(filter PurgeFilter) ([]*types.WorkflowInvocation, int, error) {
+	var matches []*types.WorkflowInvocation
+	var skipped int
+	for _, key := range p.invocations.List() {
+		if key.Type != types.TypeInvocation {
+			continue
+		}
+		invocation, err := p.invocations.GetInvocation(key.Id)
+		if fes.ErrEntityNotFound.Is(err) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if invocation == nil || !matchesPurgeFilter(invocation, filter) {
+			continue
+		}
+		if filter.Authorize != nil && !filter.Authorize(invocation) {
+			continue
+		}
+		if !invocation.GetStatus().Finished() {
+			skipped++
+			continue
+		}
+		finishedAt, err := ptypes.Timestamp(invocation.GetStatus().GetUpdatedAt())
+		if err != nil || !finishedAt.Before(filter.FinishedBefore) {
+			continue
+		}
+		matches = append(matches, invocation)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID() < matches[j].ID()
+	})
+	return matches, skipped, nil
+}
+
+// delete removes the events of the invocation and its task runs from the event store, and the invocation from the
+// cache.
+func (p *Purger) delete(deleter fes.EventDeleter, invocation *types.WorkflowInvocation) error {
+	for _, task := range invocation.GetStatus().GetTasks() {
+		if err := deleter.Delete(projectors.NewTaskRunAggregate(task.ID())); err != nil {
+			return err
+		}
+	}
+	if err := deleter.Delete(projectors.NewInvocationAggregate(invocation.ID())); err != nil {
+		return err
+	}
+	p.invocations.Invalidate(invocation.ID())
+	return nil
+}
+
+func matchesPurgeFilter(invocation *types.WorkflowInvocation, filter PurgeFilter) bool {
+	if len(filter.WorkflowID) > 0 && invocation.GetSpec().GetWorkflowId() != filter.WorkflowID {
+		return false
+	}
+	labels := invocation.GetSpec().GetLabels()
+	for k, v := range filter.Labels {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type appendOnlyBackend struct {
+	fes.Backend
+}
+
+func TestPurger_Purge(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := NewInvocationAPI(backend)
+	cache := testutil.NewCache()
+	invoke := func(workflowID string, labels map[string]string, cancel bool) string {
+		spec := types.NewWorkflowInvocationSpec(workflowID, time.Now().Add(time.Minute))
+		spec.Workflow = types.NewWorkflow(workflowID)
+		spec.Labels = labels
+		invocationID, err := invocationAPI.Invoke(spec)
+		assert.NoError(t, err)
+		if cancel {
+			assert.NoError(t, invocationAPI.Cancel(invocationID))
+		}
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		assert.NoError(t, cache.Put(entity))
+		return invocationID
+	}
+	finished := invoke("wf", map[string]string{"team": "a"}, true)
+	otherLabel := invoke("wf", map[string]string{"team": "b"}, true)
+	otherWorkflow := invoke("other", map[string]string{"team": "a"}, true)
+	running := invoke("wf", map[string]string{"team": "a"}, false)
+	invocations := store.NewInvocationStore(cache)
+	purger := NewPurger(backend, invocations, 1000)
+	filter := PurgeFilter{
+		WorkflowID:     "wf",
+		Labels:         map[string]string{"team": "a"},
+		FinishedBefore: time.Now().Add(time.Second),
+		DryRun:         true,
+	}
+
+	// A purge requires a cutoff.
+	_, err := purger.Purge(context.Background(), PurgeFilter{WorkflowID: "wf"})
+	assert.Equal(t, ErrPurgeCutoffMissing, err)
+
+	// Invocations that finished after the cutoff are not purged.
+	result, err := purger.Purge(context.Background(), PurgeFilter{FinishedBefore: time.Now().Add(-time.Hour),
+		DryRun: true})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Purged)
+
+	// A dry run does not delete the invocations.
+	result, err = purger.Purge(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{finished}, result.Purged)
+	assert.Equal(t, 1, result.Skipped)
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(finished))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, invocationEvents)
+
+	filter.DryRun = false
+	result, err = purger.Purge(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{finished}, result.Purged)
+	assert.Equal(t, 1, result.Skipped)
+	invocationEvents, err = backend.Get(projectors.NewInvocationAggregate(finished))
+	assert.NoError(t, err)
+	assert.Empty(t, invocationEvents)
+	_, err = invocations.GetInvocation(finished)
+	assert.True(t, fes.ErrEntityNotFound.Is(err))
+	for _, id := range []string{otherLabel, otherWorkflow, running} {
+		wi, err := invocations.GetInvocation(id)
+		assert.NoError(t, err)
+		assert.NotNil(t, wi)
+	}
+
+	// Event stores that do not support deletes only support dry runs.
+	purger = NewPurger(appendOnlyBackend{backend}, invocations, 0)
+	_, err = purger.Purge(context.Background(), filter)
+	assert.Equal(t, ErrPurgeNotSupported, err)
+	filter.DryRun = true
+	filter.WorkflowID = ""
+	result, err = purger.Purge(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{otherWorkflow}, result.Purged)
+}
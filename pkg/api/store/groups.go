@@ -0,0 +1,94 @@
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types"
+)
+
+// groupIndex indexes the invocations in the cache by the ID of their invocation group.
+//
+// The group of an invocation is set when the invocation is created, and does not change afterwards. So, rather than
+// fetching every invocation on each lookup, the index only fetches the invocations that were added to the cache since
+// the previous lookup, and drops the invocations that were removed from it.
+type groupIndex struct {
+	lock    sync.Mutex
+	groups  map[string]map[string]struct{} // group ID -> invocation IDs
+	indexed map[string]string              // invocation ID -> group ID, which is empty if it is not in a group
+}
+
+func newGroupIndex() *groupIndex {
+	return &groupIndex{
+		groups:  map[string]map[string]struct{}{},
+		indexed: map[string]string{},
+	}
+}
+
+// members returns the sorted IDs of the invocations in the store that are a member of the group.
+func (gi *groupIndex) members(s *Invocations, groupID string) ([]string, error) {
+	gi.lock.Lock()
+	defer gi.lock.Unlock()
+	if err := gi.update(s); err != nil {
+		return nil, err
+	}
+	var ids []string
+	for id := range gi.groups[groupID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// update brings the index in line with the invocations currently in the store.
+func (gi *groupIndex) update(s *Invocations) error {
+	listed := map[string]struct{}{}
+	for _, key := range s.List() {
+		if key.Type != types.TypeInvocation {
+			continue
+		}
+		listed[key.Id] = struct{}{}
+		if _, ok := gi.indexed[key.Id]; ok {
+			continue
+		}
+		invocation, err := s.GetInvocation(key.Id)
+		if err != nil {
+			return err
+		}
+		if invocation == nil {
+			// The invocation was removed in the meantime; if it reappears, it is indexed on a later lookup.
+			continue
+		}
+		gi.add(key.Id, invocation.GetSpec().GetGroupId())
+	}
+	for id := range gi.indexed {
+		if _, ok := listed[id]; !ok {
+			gi.remove(id)
+		}
+	}
+	return nil
+}
+
+func (gi *groupIndex) add(invocationID, groupID string) {
+	gi.indexed[invocationID] = groupID
+	if len(groupID) == 0 {
+		return
+	}
+	members, ok := gi.groups[groupID]
+	if !ok {
+		members = map[string]struct{}{}
+		gi.groups[groupID] = members
+	}
+	members[invocationID] = struct{}{}
+}
+
+func (gi *groupIndex) remove(invocationID string) {
+	groupID := gi.indexed[invocationID]
+	delete(gi.indexed, invocationID)
+	if members, ok := gi.groups[groupID]; ok {
+		delete(members, invocationID)
+		if len(members) == 0 {
+			delete(gi.groups, groupID)
+		}
+	}
+}
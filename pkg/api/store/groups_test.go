@@ -0,0 +1,59 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGroupMember(id string, groupID string) *types.WorkflowInvocation {
+	return &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{Id: id},
+		Spec:     &types.WorkflowInvocationSpec{WorkflowId: "wf", GroupId: groupID},
+	}
+}
+
+func memberIDs(members []*types.WorkflowInvocation) []string {
+	var ids []string
+	for _, member := range members {
+		ids = append(ids, member.ID())
+	}
+	return ids
+}
+
+func TestInvocations_GetGroupMembers(t *testing.T) {
+	c := &blockingCache{Cache: testutil.NewCache(), release: make(chan struct{})}
+	close(c.release)
+	assert.NoError(t, c.Put(newGroupMember("a", "g1")))
+	assert.NoError(t, c.Put(newGroupMember("b", "g1")))
+	assert.NoError(t, c.Put(newGroupMember("c", "")))
+	assert.NoError(t, c.Put(newGroupMember("d", "g2")))
+	invocations := NewInvocationStore(c)
+
+	// The first lookup indexes all invocations, and then fetches the members.
+	members, err := invocations.GetGroupMembers("g1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, memberIDs(members))
+	assert.Equal(t, 6, c.lookups)
+
+	// Subsequent lookups only fetch the members.
+	members, err = invocations.GetGroupMembers("g2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"d"}, memberIDs(members))
+	assert.Equal(t, 7, c.lookups)
+
+	// Added invocations are indexed, and removed invocations are dropped from the index.
+	c.Invalidate(fes.Aggregate{Type: types.TypeInvocation, Id: "a"})
+	assert.NoError(t, c.Put(newGroupMember("e", "g1")))
+	members, err = invocations.GetGroupMembers("g1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "e"}, memberIDs(members))
+	assert.Equal(t, 10, c.lookups)
+
+	members, err = invocations.GetGroupMembers("unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, members)
+}
@@ -69,11 +69,13 @@ func (s *Workflows) GetWorkflowUpdates() *WorkflowSubscription {
 
 type Invocations struct {
 	fes.CacheReader
+	groups *groupIndex
 }
 
 func NewInvocationStore(invocations fes.CacheReader) *Invocations {
 	return &Invocations{
-		invocations,
+		CacheReader: invocations,
+		groups:      newGroupIndex(),
 	}
 }
 
@@ -98,6 +100,59 @@ func (s *Invocations) GetInvocation(invocationID string) (*types.WorkflowInvocat
 	return wfi, nil
 }
 
+// GetGroupMembers returns the invocations that are a member of the invocation group.
+// If the group does not have any members, an empty slice is returned.
+func (s *Invocations) GetGroupMembers(groupID string) ([]*types.WorkflowInvocation, error) {
+	memberIDs, err := s.groups.members(s, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var members []*types.WorkflowInvocation
+	for _, invocationID := range memberIDs {
+		invocation, err := s.GetInvocation(invocationID)
+		if err != nil {
+			return nil, err
+		}
+		if invocation != nil {
+			members = append(members, invocation)
+		}
+	}
+	return members, nil
+}
+
+// GetSubInvocations returns the sub-workflow invocations of which the invocation is the parent.
+// If the invocation does not have any sub-invocations, an empty slice is returned.
+func (s *Invocations) GetSubInvocations(parentID string) ([]*types.WorkflowInvocation, error) {
+	var children []*types.WorkflowInvocation
+	for _, key := range s.List() {
+		if key.Type != types.TypeInvocation {
+			continue
+		}
+		invocation, err := s.GetInvocation(key.Id)
+		if err != nil {
+			return nil, err
+		}
+		if invocation != nil && invocation.GetSpec().GetParentId() == parentID {
+			children = append(children, invocation)
+		}
+	}
+	return children, nil
+}
+
+// Invalidate removes the invocation from the invocation cache, such as after its events have been deleted.
+// It is a no-op if the cache does not support invalidation.
+func (s *Invocations) Invalidate(invocationID string) {
+	if cache, ok := s.CacheReader.(fes.CacheWriter); ok {
+		cache.Invalidate(fes.Aggregate{Type: types.TypeInvocation, Id: invocationID})
+	}
+}
+
+// SupportsUpdates returns whether the invocation cache supports subscribing to updates (see GetInvocationUpdates).
+func (s *Invocations) SupportsUpdates() bool {
+	_, ok := s.CacheReader.(pubsub.Publisher)
+	return ok
+}
+
 // GetInvocationSubscription returns a subscription to the updates of the invocation cache.
 // Returns nil if the cache does not support pubsub.
 //
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultWorkflowCacheTTL is the default duration for which a resolved workflow is cached.
+const DefaultWorkflowCacheTTL = 5 * time.Second
+
+var metricWorkflowCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "store",
+	Name:      "workflow_cache_lookups_total",
+	Help:      "Number of lookups of resolved workflows by result (hit, miss or shared)",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(metricWorkflowCacheLookups)
+}
+
+// WorkflowCache is a short-lived cache of the ready workflows, in front of the workflow store. It prevents the
+// concurrent invocations of the same workflow, such as the sub-workflow invocations of a large fan-out, from each
+// looking up (and possibly projecting) the workflow: concurrent lookups of a workflow that is not cached share a
+// single lookup of the store.
+//
+// The cached workflows are invalidated when the workflow is updated, which is detected with the workflow updates of
+// the store (see Run). The TTL bounds the staleness of the cache if updates are missed.
+type WorkflowCache struct {
+	workflows *Workflows
+	ttl       time.Duration
+	entries   map[string]*workflowCacheEntry
+	inflight  map[string]*workflowLookup
+	mu        *sync.Mutex
+	done      func()
+	closeC    <-chan struct{}
+}
+
+type workflowCacheEntry struct {
+	workflow  *types.Workflow
+	expiresAt time.Time
+}
+
+// workflowLookup is a lookup of the store that is shared by concurrent lookups of the same workflow.
+type workflowLookup struct {
+	done     chan struct{}
+	workflow *types.Workflow
+	err      error
+}
+
+// NewWorkflowCache creates a cache of the workflows of the store. If the TTL is not positive, DefaultWorkflowCacheTTL
+// is used.
+func NewWorkflowCache(workflows *Workflows, ttl time.Duration) *WorkflowCache {
+	if ttl <= 0 {
+		ttl = DefaultWorkflowCacheTTL
+	}
+	ctx, done := context.WithCancel(context.Background())
+	return &WorkflowCache{
+		workflows: workflows,
+		ttl:       ttl,
+		entries:   map[string]*workflowCacheEntry{},
+		inflight:  map[string]*workflowLookup{},
+		mu:        &sync.Mutex{},
+		done:      done,
+		closeC:    ctx.Done(),
+	}
+}
+
+// GetWorkflow returns the workflow, similar to Workflows.GetWorkflow. Only ready workflows are cached; the workflows
+// that are not ready yet are looked up in the store every time.
+func (c *WorkflowCache) GetWorkflow(workflowID string) (*types.Workflow, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[workflowID]; ok {
+		if time.Now().Before(entry.expiresAt) {
+			c.mu.Unlock()
+			metricWorkflowCacheLookups.WithLabelValues("hit").Inc()
+			return entry.workflow, nil
+		}
+		delete(c.entries, workflowID)
+	}
+	if lookup, ok := c.inflight[workflowID]; ok {
+		c.mu.Unlock()
+		metricWorkflowCacheLookups.WithLabelValues("shared").Inc()
+		<-lookup.done
+		return lookup.workflow, lookup.err
+	}
+	lookup := &workflowLookup{done: make(chan struct{})}
+	c.inflight[workflowID] = lookup
+	c.mu.Unlock()
+	metricWorkflowCacheLookups.WithLabelValues("miss").Inc()
+
+	lookup.workflow, lookup.err = c.workflows.GetWorkflow(workflowID)
+
+	c.mu.Lock()
+	delete(c.inflight, workflowID)
+	if lookup.err == nil && lookup.workflow.GetStatus().Ready() {
+		c.entries[workflowID] = &workflowCacheEntry{
+			workflow:  lookup.workflow,
+			expiresAt: time.Now().Add(c.ttl),
+		}
+	}
+	c.mu.Unlock()
+	close(lookup.done)
+	return lookup.workflow, lookup.err
+}
+
+// Invalidate removes the workflow from the cache.
+func (c *WorkflowCache) Invalidate(workflowID string) {
+	c.mu.Lock()
+	delete(c.entries, workflowID)
+	c.mu.Unlock()
+}
+
+// Len returns the number of cached workflows, including the workflows of which the TTL has expired.
+func (c *WorkflowCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Run invalidates the cached workflows of which a newer version is published by the store, until the cache is
+// closed. If the store does not support updates, the cache solely relies on the TTL.
+func (c *WorkflowCache) Run() error {
+	sub := c.workflows.GetWorkflowUpdates()
+	if sub == nil {
+		logrus.Warn("Workflow store does not support updates; cached workflows are only refreshed after their TTL")
+		<-c.closeC
+		return nil
+	}
+	for {
+		select {
+		case msg := <-sub.Ch:
+			notification, err := sub.ToNotification(msg)
+			if err != nil {
+				logrus.Warnf("Failed to convert pubsub message to notification: %v", err)
+				continue
+			}
+			wf, err := ParseNotificationToWorkflow(notification)
+			if err != nil {
+				continue
+			}
+			c.invalidateOutdated(wf)
+		case <-c.closeC:
+			return sub.Close()
+		}
+	}
+}
+
+// invalidateOutdated removes the cached version of the workflow if it differs from the updated version.
+func (c *WorkflowCache) invalidateOutdated(updated *types.Workflow) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[updated.ID()]
+	if ok && entry.workflow.GetMetadata().GetGeneration() != updated.GetMetadata().GetGeneration() {
+		delete(c.entries, updated.ID())
+	}
+}
+
+func (c *WorkflowCache) Close() error {
+	c.done()
+	return nil
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingCache counts the lookups of aggregates, which block until the cache is released.
+type blockingCache struct {
+	*testutil.Cache
+	release chan struct{}
+	lookups int
+	mu      sync.Mutex
+}
+
+func (c *blockingCache) GetAggregate(a fes.Aggregate) (fes.Entity, error) {
+	c.mu.Lock()
+	c.lookups++
+	c.mu.Unlock()
+	<-c.release
+	return c.Cache.GetAggregate(a)
+}
+
+func newWorkflow(id string, generation int64, status types.WorkflowStatus_Status) *types.Workflow {
+	return &types.Workflow{
+		Metadata: &types.ObjectMetadata{Id: id, Generation: generation},
+		Status:   &types.WorkflowStatus{Status: status},
+	}
+}
+
+func TestWorkflowCache_GetWorkflow(t *testing.T) {
+	c := &blockingCache{Cache: testutil.NewCache(), release: make(chan struct{})}
+	assert.NoError(t, c.Put(newWorkflow("wf", 1, types.WorkflowStatus_READY)))
+	cache := NewWorkflowCache(NewWorkflowsStore(c), time.Minute)
+
+	// Concurrent lookups of a workflow share a single lookup of the store.
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wf, err := cache.GetWorkflow("wf")
+			assert.NoError(t, err)
+			assert.Equal(t, "wf", wf.ID())
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(c.release)
+	wg.Wait()
+	assert.Equal(t, 1, c.lookups)
+
+	// Subsequent lookups hit the cache.
+	_, err := cache.GetWorkflow("wf")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, c.lookups)
+
+	// A newer version of the workflow invalidates the cached version.
+	cache.invalidateOutdated(newWorkflow("wf", 1, types.WorkflowStatus_READY))
+	assert.Equal(t, 1, cache.Len())
+	cache.invalidateOutdated(newWorkflow("wf", 2, types.WorkflowStatus_READY))
+	assert.Equal(t, 0, cache.Len())
+
+	// Workflows that are not ready are not cached.
+	assert.NoError(t, c.Put(newWorkflow("pending", 1, types.WorkflowStatus_QUEUED)))
+	_, err = cache.GetWorkflow("pending")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestWorkflowCache_TTL(t *testing.T) {
+	c := &blockingCache{Cache: testutil.NewCache(), release: make(chan struct{})}
+	close(c.release)
+	assert.NoError(t, c.Put(newWorkflow("wf", 1, types.WorkflowStatus_READY)))
+	cache := NewWorkflowCache(NewWorkflowsStore(c), 10*time.Millisecond)
+	_, err := cache.GetWorkflow("wf")
+	assert.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	_, err = cache.GetWorkflow("wf")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, c.lookups)
+}
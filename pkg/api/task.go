@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -9,7 +10,9 @@ import (
 	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/redact"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/golang/protobuf/ptypes"
@@ -22,6 +25,8 @@ type Task struct {
 	runtime    map[string]fnenv.Runtime
 	es         fes.Backend
 	dynamicAPI *Dynamic
+	vault      *redact.Vault
+	outputHash string
 }
 
 // NewTaskAPI creates the Task API.
@@ -33,6 +38,18 @@ func NewTaskAPI(runtime map[string]fnenv.Runtime, esClient fes.Backend, api *Dyn
 	}
 }
 
+// SetVault sets the vault in which the original values of redacted output fields are kept. If nil, the original
+// values are discarded.
+func (ap *Task) SetVault(vault *redact.Vault) {
+	ap.vault = vault
+}
+
+// SetOutputHash sets the algorithm (see typedvalues.HashAlgorithms) of the content hash that is computed of the
+// output of each succeeded task run and stored along with the output. If empty, no content hashes are computed.
+func (ap *Task) SetOutputHash(algorithm string) {
+	ap.outputHash = algorithm
+}
+
 // Invoke starts the execution of a task, changing the state of the task into RUNNING.
 // Currently it executes the underlying function synchronously and manage the execution until completion.
 func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*types.TaskInvocation, error) {
@@ -69,6 +86,12 @@ func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*typ
 	if err != nil {
 		return nil, err
 	}
+	// Record that the task is in progress, which allows a recovering controller to identify the tasks that were
+	// in-flight at the time of a crash.
+	err = ap.es.Append(event)
+	if err != nil {
+		return nil, err
+	}
 
 	fnResult, err := ap.runtime[spec.FnRef.Runtime].Invoke(spec, fnenv.WithContext(cfg.ctx),
 		fnenv.AwaitWorkflow(cfg.awaitWorkflow))
@@ -78,7 +101,12 @@ func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*typ
 	if err != nil {
 		// TODO improve error handling here (retries? internal or task related error?)
 		log.Infof("Failed to invoke task: %v", err)
-		esErr := ap.Fail(spec.InvocationId, taskID, err.Error())
+		code := types.ErrorCodeRuntime
+		if cfg.ctx.Err() == context.DeadlineExceeded {
+			code = types.ErrorCodeTimeout
+		}
+		esErr := ap.FailWithError(spec.InvocationId, types.NewTaskError(&types.Error{Message: err.Error()}, taskID,
+			spec.AttemptNumber(), code))
 		if esErr != nil {
 			return nil, esErr
 		}
@@ -106,7 +134,14 @@ func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*typ
 		}
 	}
 
+	// Redact the sensitive fields of the output before it is stored or returned.
+	rules := spec.GetTask().GetSpec().GetRedact()
+	if len(rules) > 0 && fnResult.Status == types.TaskInvocationStatus_SUCCEEDED {
+		ap.redactOutput(spec, fnResult, rules)
+	}
+
 	if fnResult.Status == types.TaskInvocationStatus_SUCCEEDED {
+		ap.hashOutput(taskID, fnResult)
 		event, err := fes.NewEvent(projectors.NewTaskRunAggregate(taskID), &events.TaskSucceeded{
 			Result: fnResult,
 		})
@@ -116,7 +151,8 @@ func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*typ
 		event.Parent = &aggregate
 		err = ap.es.Append(event)
 	} else {
-		err = ap.Fail(spec.InvocationId, taskID, fnResult.Error.GetMessage())
+		fnResult.Error = types.NewTaskError(fnResult.Error, taskID, spec.AttemptNumber(), types.ErrorCodeFunction)
+		err = ap.FailWithError(spec.InvocationId, fnResult.Error)
 	}
 	if err != nil {
 		return nil, err
@@ -124,9 +160,52 @@ func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*typ
 	return task, nil
 }
 
+// hashOutput sets the content hash of the output of the task run, if content hashing is enabled. The hash is computed
+// of the output as it is stored, so after it has been redacted. A failure to compute the hash does not fail the task
+// run; the output is stored without a hash instead.
+func (ap *Task) hashOutput(taskID string, result *types.TaskInvocationStatus) {
+	if len(ap.outputHash) == 0 || result.GetOutput() == nil {
+		return
+	}
+	hash, err := typedvalues.ContentHash(result.GetOutput(), ap.outputHash)
+	if err != nil {
+		logrus.Warnf("Failed to compute the content hash of the output of task %s: %v", taskID, err)
+		return
+	}
+	result.OutputHash = hash
+}
+
+// redactOutput redacts the output of the task run in place. If the output cannot be redacted, the task run is failed
+// without an output, as the output cannot be stored safely.
+func (ap *Task) redactOutput(spec *types.TaskInvocationSpec, result *types.TaskInvocationStatus,
+	rules []*types.RedactionRule) {
+	redacted, originals, err := redact.Apply(result.GetOutput(), rules)
+	if err != nil {
+		result.Status = types.TaskInvocationStatus_FAILED
+		result.Output = nil
+		result.Error = types.NewTaskError(&types.Error{Message: err.Error()}, spec.TaskId, spec.AttemptNumber(),
+			types.ErrorCodeOutput)
+		return
+	}
+	result.Output = redacted
+	if ap.vault != nil && len(originals) > 0 {
+		if err := ap.vault.Store(spec.InvocationId, spec.TaskId, originals); err != nil {
+			logrus.WithField("wi", spec.InvocationId).WithField("task", spec.TaskId).
+				Errorf("Failed to store the redacted output values: %v", err)
+		}
+	}
+}
+
 // Fail forces the failure of a task. This turns the state of a task into FAILED.
 // If the API fails to append the event to the event store, it will return an error.
 func (ap *Task) Fail(invocationID string, taskID string, errMsg string) error {
+	return ap.FailWithError(invocationID, types.NewTaskError(&types.Error{Message: errMsg}, taskID, 0,
+		types.ErrorCodeAborted))
+}
+
+// FailWithError fails the task identified by the TaskId of the error, recording the error as the cause.
+func (ap *Task) FailWithError(invocationID string, taskErr *types.Error) error {
+	taskID := taskErr.GetTaskId()
 	if len(invocationID) == 0 {
 		return validate.NewError("invocationID", errors.New("id should not be empty"))
 	}
@@ -135,7 +214,7 @@ func (ap *Task) Fail(invocationID string, taskID string, errMsg string) error {
 	}
 
 	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(taskID), &events.TaskFailed{
-		Error: &types.Error{Message: errMsg},
+		Error: taskErr,
 	})
 	if err != nil {
 		return err
@@ -145,6 +224,111 @@ func (ap *Task) Fail(invocationID string, taskID string, errMsg string) error {
 	return ap.es.Append(event)
 }
 
+// Skip skips the task, which has not been started, for example because it belongs to a branch that was not selected.
+// This turns the state of the task into SKIPPED.
+func (ap *Task) Skip(invocationID string, taskID string) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	if len(taskID) == 0 {
+		return validate.NewError("taskID", errors.New("id should not be empty"))
+	}
+
+	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(taskID), &events.TaskSkipped{})
+	if err != nil {
+		return err
+	}
+	aggregate := projectors.NewInvocationAggregate(invocationID)
+	event.Parent = &aggregate
+	return ap.es.Append(event)
+}
+
+// Start records that the task run has started, without invoking its function. This is used for the task runs of which
+// the execution is managed by the controller rather than by a function runtime, such as the polls of sensors.
+func (ap *Task) Start(spec *types.TaskInvocationSpec) error {
+	if err := validate.TaskInvocationSpec(spec); err != nil {
+		return err
+	}
+	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(spec.TaskId), &events.TaskStarted{
+		Spec: spec,
+	})
+	if err != nil {
+		return err
+	}
+	aggregate := projectors.NewInvocationAggregate(spec.InvocationId)
+	event.Parent = &aggregate
+	return ap.es.Append(event)
+}
+
+// Poll records a poll of the started task run of a sensor, of which the readiness check did not pass. The task run
+// remains in progress.
+func (ap *Task) Poll(invocationID string, taskID string, result string) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	if len(taskID) == 0 {
+		return validate.NewError("taskID", errors.New("id should not be empty"))
+	}
+
+	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(taskID), &events.TaskPolled{
+		Result: result,
+	})
+	if err != nil {
+		return err
+	}
+	aggregate := projectors.NewInvocationAggregate(invocationID)
+	event.Parent = &aggregate
+	return ap.es.Append(event)
+}
+
+// Succeed completes the started task run with the output. This turns the state of the task into SUCCEEDED.
+func (ap *Task) Succeed(invocationID string, taskID string, output *typedvalues.TypedValue) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	if len(taskID) == 0 {
+		return validate.NewError("taskID", errors.New("id should not be empty"))
+	}
+
+	result := &types.TaskInvocationStatus{
+		Status: types.TaskInvocationStatus_SUCCEEDED,
+		Output: output,
+	}
+	ap.hashOutput(taskID, result)
+	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(taskID), &events.TaskSucceeded{
+		Result: result,
+	})
+	if err != nil {
+		return err
+	}
+	aggregate := projectors.NewInvocationAggregate(invocationID)
+	event.Parent = &aggregate
+	return ap.es.Append(event)
+}
+
+// Reuse records the result of an earlier, identical run as the result of the task run, without invoking its function.
+// This is used for the memoized outputs of pure tasks. The task run is started and succeeded at once.
+func (ap *Task) Reuse(spec *types.TaskInvocationSpec, result *types.TaskInvocationStatus) error {
+	if err := ap.Start(spec); err != nil {
+		return err
+	}
+	reused := &types.TaskInvocationStatus{
+		Status:        types.TaskInvocationStatus_SUCCEEDED,
+		Output:        result.GetOutput(),
+		OutputHeaders: result.GetOutputHeaders(),
+	}
+	ap.hashOutput(spec.TaskId, reused)
+	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(spec.TaskId), &events.TaskSucceeded{
+		Result: reused,
+	})
+	if err != nil {
+		return err
+	}
+	aggregate := projectors.NewInvocationAggregate(spec.InvocationId)
+	event.Parent = &aggregate
+	return ap.es.Append(event)
+}
+
 func (ap *Task) Prepare(spec *types.TaskInvocationSpec, expectedAt time.Time, opts ...CallOption) error {
 	runtime, ok := ap.runtime[spec.GetFnRef().GetRuntime()]
 	if !ok {
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/redact"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_InvokeRedactsOutput(t *testing.T) {
+	const ssn = "123-45-6789"
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["lookup"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap(map[string]interface{}{"name": "Jane", "ssn": ssn}), nil
+	}
+	backend := mem.NewBackend()
+	vault, err := redact.NewVault([]byte("0123456789abcdef"), redact.NewMemoryStore(0))
+	assert.NoError(t, err)
+	taskAPI := NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	taskAPI.SetVault(vault)
+
+	task := &types.Task{
+		Metadata: types.NewObjectMetadata("lookup"),
+		Spec: &types.TaskSpec{
+			FunctionRef: "lookup",
+			Redact:      []*types.RedactionRule{{Path: "ssn"}},
+		},
+		Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "lookup"}},
+	}
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec:     types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute)),
+	}
+	run, err := taskAPI.Invoke(types.NewTaskInvocationSpec(invocation, task, time.Now()))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "Jane", "ssn": redact.Mask},
+		typedvalues.MustUnwrap(run.GetStatus().GetOutput()))
+
+	// The raw value never ends up in the event log...
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate("wi"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, invocationEvents)
+	marshaler := &jsonpb.Marshaler{}
+	for _, event := range invocationEvents {
+		assert.NotContains(t, string(event.GetData().GetValue()), ssn)
+		data, err := marshaler.MarshalToString(event)
+		assert.NoError(t, err)
+		assert.NotContains(t, data, ssn)
+	}
+
+	// ...but can be retrieved from the vault.
+	values, ok, err := vault.Retrieve("wi", "lookup")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"ssn": ssn}, values)
+}
+
+func TestTask_InvokeFailsUnredactableOutput(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["lookup"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("ssn=123-45-6789"), nil
+	}
+	backend := mem.NewBackend()
+	taskAPI := NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+
+	task := &types.Task{
+		Metadata: types.NewObjectMetadata("lookup"),
+		Spec: &types.TaskSpec{
+			FunctionRef: "lookup",
+			Redact:      []*types.RedactionRule{{Path: "ssn"}},
+		},
+		Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "lookup"}},
+	}
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec:     types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute)),
+	}
+	run, err := taskAPI.Invoke(types.NewTaskInvocationSpec(invocation, task, time.Now()))
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, run.GetStatus().GetStatus())
+	assert.Nil(t, run.GetStatus().GetOutput())
+
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate("wi"))
+	assert.NoError(t, err)
+	for _, event := range invocationEvents {
+		assert.NotContains(t, string(event.GetData().GetValue()), "123-45-6789")
+	}
+}
+
+func TestTask_InvokeHashesOutput(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["lookup"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap(map[string]interface{}{"name": "Jane", "age": 42}), nil
+	}
+	backend := mem.NewBackend()
+	taskAPI := NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	taskAPI.SetOutputHash(typedvalues.HashSHA256)
+
+	task := &types.Task{
+		Metadata: types.NewObjectMetadata("lookup"),
+		Spec:     &types.TaskSpec{FunctionRef: "lookup"},
+		Status:   &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "lookup"}},
+	}
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec:     types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute)),
+	}
+	run, err := taskAPI.Invoke(types.NewTaskInvocationSpec(invocation, task, time.Now()))
+	assert.NoError(t, err)
+	expected, err := typedvalues.ContentHash(typedvalues.MustWrap(map[string]interface{}{"age": 42, "name": "Jane"}),
+		typedvalues.HashSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, run.GetStatus().GetOutputHash())
+
+	// The hash is stored along with the output.
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate("wi"))
+	assert.NoError(t, err)
+	entity, err := projectors.NewTaskRun().Project(nil, invocationEvents...)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, entity.(*types.TaskInvocation).GetStatus().GetOutputHash())
+}
@@ -16,6 +16,17 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ErrWorkflowQuarantined is returned when an invocation of a quarantined workflow is rejected.
+type ErrWorkflowQuarantined struct {
+	WorkflowID string
+	Quarantine *types.WorkflowQuarantine
+}
+
+func (e ErrWorkflowQuarantined) Error() string {
+	return fmt.Sprintf("workflow %s is quarantined (%s); invocations are rejected until the quarantine is cleared",
+		e.WorkflowID, e.Quarantine.GetReason())
+}
+
 // Workflow contains the API functionality for controlling workflow definitions.
 // This includes creating and parsing workflows.
 type Workflow struct {
@@ -61,6 +72,7 @@ func (wa *Workflow) Create(workflow *types.WorkflowSpec, opts ...CallOption) (st
 			logrus.Warnf("Failed to inject tracer context into event: %v", err)
 		}
 	}
+	setPrincipal(cfg.ctx, event)
 
 	err = wa.es.Append(event)
 	if err != nil {
@@ -73,7 +85,8 @@ func (wa *Workflow) Create(workflow *types.WorkflowSpec, opts ...CallOption) (st
 // Delete marks a workflow as deleted, making it unavailable to any future interactions.
 // This also means that subsequent invocations for this workflow will fail.
 // If the API fails to append the event to the event store, it will return an error.
-func (wa *Workflow) Delete(workflowID string) error {
+func (wa *Workflow) Delete(workflowID string, opts ...CallOption) error {
+	cfg := parseCallOptions(opts)
 	if len(workflowID) == 0 {
 		return validate.NewError("workflowID", errors.New("id should not be empty"))
 	}
@@ -83,6 +96,7 @@ func (wa *Workflow) Delete(workflowID string) error {
 		return err
 	}
 	event.Hints = &fes.EventHints{Completed: true}
+	setPrincipal(cfg.ctx, event)
 	return wa.es.Append(event)
 }
 
@@ -95,9 +109,22 @@ func (wa *Workflow) Parse(workflow *types.Workflow) (map[string]*types.TaskStatu
 		return nil, err
 	}
 
-	resolvedFns, err := fnenv.ResolveTasks(wa.resolver, workflow.Spec.Tasks)
+	// Resolve the failover functions along with the primary functions, to ensure that both exist.
+	tasks := make([]*types.TaskSpec, 0, len(workflow.Spec.Tasks))
+	for _, t := range workflow.Spec.Tasks {
+		tasks = append(tasks, t)
+		if failover := t.GetFailover(); failover != nil {
+			tasks = append(tasks, &types.TaskSpec{FunctionRef: failover.GetFunctionRef()})
+		}
+	}
+	resolvedFns, err := fnenv.ResolveTask(wa.resolver, tasks...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve tasks in workflow: %v", err)
+		err = fmt.Errorf("failed to resolve tasks in workflow: %v", err)
+		// Record the failed attempt, which allows the controller to back off durably from retrying.
+		if appendErr := wa.failParsing(workflow.ID(), err); appendErr != nil {
+			logrus.Warnf("Failed to record failed parsing attempt of workflow %s: %v", workflow.ID(), appendErr)
+		}
+		return nil, err
 	}
 
 	taskStatuses := map[string]*types.TaskStatus{}
@@ -107,6 +134,9 @@ func (wa *Workflow) Parse(workflow *types.Workflow) (map[string]*types.TaskStatu
 			FnRef:     resolvedFns[t.FunctionRef],
 			Status:    types.TaskStatus_READY,
 		}
+		if failover := t.GetFailover(); failover != nil {
+			taskStatuses[id].FailoverFnRef = resolvedFns[failover.GetFunctionRef()]
+		}
 	}
 
 	event, err := fes.NewEvent(projectors.NewWorkflowAggregate(workflow.ID()), &events.WorkflowParsed{
@@ -122,3 +152,49 @@ func (wa *Workflow) Parse(workflow *types.Workflow) (map[string]*types.TaskStatu
 
 	return taskStatuses, nil
 }
+
+// failParsing records a failed attempt to parse the workflow.
+func (wa *Workflow) failParsing(workflowID string, err error) error {
+	event, eventErr := fes.NewEvent(projectors.NewWorkflowAggregate(workflowID), &events.WorkflowParsingFailed{
+		Error: &types.Error{
+			Message: err.Error(),
+		},
+	})
+	if eventErr != nil {
+		return eventErr
+	}
+	return wa.es.Append(event)
+}
+
+// Quarantine quarantines the workflow, after which new invocations of the workflow are rejected until the quarantine
+// is cleared. If the API fails to append the event to the event store, it will return an error.
+func (wa *Workflow) Quarantine(workflowID string, quarantine *types.WorkflowQuarantine) error {
+	if len(workflowID) == 0 {
+		return validate.NewError("workflowID", errors.New("id should not be empty"))
+	}
+
+	event, err := fes.NewEvent(projectors.NewWorkflowAggregate(workflowID), &events.WorkflowQuarantined{
+		Quarantine: quarantine,
+	})
+	if err != nil {
+		return err
+	}
+	return wa.es.Append(event)
+}
+
+// ClearQuarantine clears the quarantine of the workflow, which allows the workflow to be invoked again. Clearing the
+// quarantine of a workflow that is not quarantined has no effect. If the API fails to append the event to the event
+// store, it will return an error.
+func (wa *Workflow) ClearQuarantine(workflowID string, opts ...CallOption) error {
+	cfg := parseCallOptions(opts)
+	if len(workflowID) == 0 {
+		return validate.NewError("workflowID", errors.New("id should not be empty"))
+	}
+
+	event, err := fes.NewEvent(projectors.NewWorkflowAggregate(workflowID), &events.WorkflowQuarantineCleared{})
+	if err != nil {
+		return err
+	}
+	setPrincipal(cfg.ctx, event)
+	return wa.es.Append(event)
+}
@@ -1,24 +1,454 @@
 package apiserver
 
 import (
+	"crypto/subtle"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/redact"
+	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/version"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	StatusOK = "OK!"
+
+	// StatusDegraded indicates that the workflow engine is functional, but that some of its subsystems are slow.
+	StatusDegraded = "DEGRADED"
+
+	// HealthStateStore identifies the expression state store of the invocation controller in the degraded subsystems.
+	HealthStateStore = "state-store"
+
+	// HealthRuntimePrefix prefixes the unhealthy function runtimes in the degraded subsystems, such as
+	// 'runtime:fission'.
+	HealthRuntimePrefix = "runtime:"
+
+	authorizationKey    = "authorization"
+	authorizationPrefix = "Bearer "
 )
 
-const StatusOK = "OK!"
+// Reevaluator forces the evaluation of invocations, such as the invocation controller.
+type Reevaluator interface {
+	Reevaluate(invocationID string) (found bool, enqueued bool, err error)
+
+	// EvaluationStats returns the evaluation statistics of the invocation, if it has been evaluated before.
+	EvaluationStats(invocationID string) (ctrl.ControllerStats, bool)
+
+	// TraceDecision returns the sampling decision of the trace of the invocation, if it is being evaluated.
+	TraceDecision(invocationID string) (controller.TraceDecision, bool)
+
+	// UpdatesMode returns how the invocation controller learns about updates of invocations.
+	UpdatesMode() controller.UpdatesMode
+
+	// StateStoreDegraded returns whether the reads of the expression state store exceed the timeout.
+	StateStoreDegraded() bool
+
+	// UnhealthyRuntimes returns the function runtimes that are unhealthy, of which the tasks are deferred.
+	UnhealthyRuntimes() []string
+
+	// HandoffStatus returns whether the invocation controller owns the invocations.
+	HandoffStatus() controller.HandoffStatus
+
+	// ExportState releases the invocations, and returns the state of the invocation controller.
+	ExportState(drainTimeout time.Duration) (*controller.Snapshot, error)
+
+	// ImportState resumes the invocations of the snapshot of another invocation controller.
+	ImportState(snapshot *controller.Snapshot) error
+
+	// ReclaimState makes the invocation controller the owner of the invocations again.
+	ReclaimState(handoffID string) error
+
+	// InvocationHierarchy returns the invocation along with its sub-workflow invocations, or nil if the invocation
+	// does not exist.
+	InvocationHierarchy(invocationID string) (*controller.InvocationHierarchy, error)
+}
 
 // Admin is responsible for all administrative functions related to managing the workflow engine.
 type Admin struct {
+	exprStore   *expr.Store
+	reevaluator Reevaluator
+	suspensions *controller.FunctionSuspensions
+	locks       *controller.ConcurrencyLocks
+	metrics     *controller.WorkflowMetrics
+	vault       *redact.Vault
+	token       string
+}
+
+// NewAdmin creates the admin API. The diagnostic and recovery functions of the API require the token to be provided
+// as a bearer token; if the token is empty, these functions are disabled. The exprStore, reevaluator, suspensions,
+// locks and metrics are nil if no invocation controller is running. The vault is nil if redacted output values are not
+// kept.
+func NewAdmin(exprStore *expr.Store, reevaluator Reevaluator, suspensions *controller.FunctionSuspensions,
+	locks *controller.ConcurrencyLocks, metrics *controller.WorkflowMetrics, vault *redact.Vault,
+	token string) *Admin {
+	return &Admin{
+		exprStore:   exprStore,
+		reevaluator: reevaluator,
+		suspensions: suspensions,
+		locks:       locks,
+		metrics:     metrics,
+		vault:       vault,
+		token:       token,
+	}
+}
+
+// AuthFuncOverride opts the admin API out of the authentication of the other APIs: its diagnostic and recovery
+// functions are authenticated with the admin token instead, and its health and version functions are public.
+func (as *Admin) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
+	return ctx, nil
 }
 
 func (as *Admin) Status(ctx context.Context, _ *empty.Empty) (*Health, error) {
-	return &Health{
+	health := &Health{
 		Status: StatusOK,
-	}, nil
+	}
+	if as.reevaluator != nil {
+		health.InvocationUpdates = string(as.reevaluator.UpdatesMode())
+		if as.reevaluator.StateStoreDegraded() {
+			health.Status = StatusDegraded
+			health.Degraded = append(health.Degraded, HealthStateStore)
+		}
+		for _, runtime := range as.reevaluator.UnhealthyRuntimes() {
+			health.Status = StatusDegraded
+			health.Degraded = append(health.Degraded, HealthRuntimePrefix+runtime)
+		}
+	}
+	return health, nil
 }
 
 func (as *Admin) Version(ctx context.Context, _ *empty.Empty) (*version.Info, error) {
 	v := version.VersionInfo()
 	return &v, nil
 }
+
+func (as *Admin) GetExpressionState(ctx context.Context, md *types.ObjectMetadata) (*ExpressionState, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.exprStore == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	scope, ok := as.exprStore.Get(md.GetId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no expression state for invocation %s", md.GetId())
+	}
+	data, err := json.Marshal(scope)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode expression state: %v", err)
+	}
+	return &ExpressionState{
+		Id:    md.GetId(),
+		Scope: string(data),
+	}, nil
+}
+
+// ClearExpressionState removes the expression state of the invocation from the store.
+//
+// This is safe to do while the invocation is being evaluated: the state is derived from the invocation itself, so an
+// in-flight evaluation at most stores a freshly derived state again.
+func (as *Admin) ClearExpressionState(ctx context.Context, md *types.ObjectMetadata) (*empty.Empty, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.exprStore == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	as.exprStore.Delete(md.GetId())
+	logrus.WithField("invocation", md.GetId()).Warn("Cleared the expression state of the invocation (admin API)")
+	return &empty.Empty{}, nil
+}
+
+// Reevaluate forces the invocation controller to evaluate the invocation immediately.
+func (as *Admin) Reevaluate(ctx context.Context, md *types.ObjectMetadata) (*ReevaluateResult, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.reevaluator == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	found, enqueued, err := as.reevaluator.Reevaluate(md.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to re-evaluate invocation %s: %v", md.GetId(), err)
+	}
+	logrus.WithField("invocation", md.GetId()).Warnf("Forced re-evaluation of the invocation (admin API): "+
+		"found=%v, enqueued=%v", found, enqueued)
+	return &ReevaluateResult{
+		Id:       md.GetId(),
+		Found:    found,
+		Enqueued: enqueued,
+	}, nil
+}
+
+// SuspendFunction suspends the scheduling of tasks that reference the function.
+func (as *Admin) SuspendFunction(ctx context.Context, req *FunctionSuspension) (*empty.Empty, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.suspensions == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	if len(req.GetFnRef()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "function reference is required")
+	}
+	as.suspensions.Suspend(req.GetFnRef())
+	logrus.WithField("fnRef", req.GetFnRef()).Warn("Suspended scheduling of tasks of the function (admin API)")
+	return &empty.Empty{}, nil
+}
+
+// ResumeFunction resumes the scheduling of tasks that reference the function. The invocations with tasks waiting on
+// the function are re-evaluated immediately.
+func (as *Admin) ResumeFunction(ctx context.Context, req *FunctionSuspension) (*empty.Empty, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.suspensions == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	invocationIDs := as.suspensions.Resume(req.GetFnRef())
+	logrus.WithField("fnRef", req.GetFnRef()).Warnf("Resumed scheduling of tasks of the function (admin API); "+
+		"%d invocation(s) were waiting", len(invocationIDs))
+	if as.reevaluator != nil {
+		for _, invocationID := range invocationIDs {
+			if _, _, err := as.reevaluator.Reevaluate(invocationID); err != nil {
+				logrus.Warnf("Failed to re-evaluate invocation %s: %v", invocationID, err)
+			}
+		}
+	}
+	return &empty.Empty{}, nil
+}
+
+// ListSuspendedFunctions lists the suspended functions, along with the number of tasks waiting on each.
+func (as *Admin) ListSuspendedFunctions(ctx context.Context, _ *empty.Empty) (*SuspendedFunctionList, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.suspensions == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	result := &SuspendedFunctionList{}
+	for _, fn := range as.suspensions.List() {
+		result.Functions = append(result.Functions, &SuspendedFunction{
+			FnRef:        fn.FnRef,
+			Since:        fn.Since.Format(time.RFC3339),
+			WaitingTasks: int32(fn.WaitingTasks),
+		})
+	}
+	return result, nil
+}
+
+// GetConcurrencyLock returns the invocation that holds the concurrency key of the workflow, and the invocations that
+// are queued for it.
+func (as *Admin) GetConcurrencyLock(ctx context.Context, req *ConcurrencyKey) (*ConcurrencyLock, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.locks == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	lock, ok := as.locks.Get(req.GetWorkflowId(), req.GetKey())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "concurrency key '%s' of workflow %s is not held", req.GetKey(),
+			req.GetWorkflowId())
+	}
+	return &ConcurrencyLock{
+		WorkflowId: lock.WorkflowID,
+		Key:        lock.Key,
+		Holder:     lock.Holder,
+		Queued:     lock.Queued,
+	}, nil
+}
+
+// GetEvaluationStats returns the evaluation statistics of the invocation, which allow distinguishing a backlog of the
+// invocation controller from slow functions.
+func (as *Admin) GetEvaluationStats(ctx context.Context, md *types.ObjectMetadata) (*EvaluationStats, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.reevaluator == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	stats, ok := as.reevaluator.EvaluationStats(md.GetId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no evaluations of invocation %s", md.GetId())
+	}
+	result := &EvaluationStats{
+		Id:                    md.GetId(),
+		EvalCount:             stats.EvalCount,
+		LastEvaluatedAt:       stats.LastEvaluatedAt.Format(time.RFC3339),
+		LastQueueWaitSeconds:  stats.LastQueueWait.Seconds(),
+		TotalQueueWaitSeconds: stats.TotalQueueWait.Seconds(),
+	}
+	if trace, ok := as.reevaluator.TraceDecision(md.GetId()); ok {
+		result.TraceSampled = trace.Sampled
+		result.TraceId = trace.TraceID
+		result.TraceSamplingReason = trace.Reason
+	}
+	return result, nil
+}
+
+// GetInvocationHierarchy returns the invocation along with the hierarchy of its sub-workflow invocations, which shows
+// whether the cancellation of the invocations was cascaded to, or detached from, their sub-workflow invocations.
+func (as *Admin) GetInvocationHierarchy(ctx context.Context, md *types.ObjectMetadata) (*InvocationHierarchy,
+	error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.reevaluator == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	hierarchy, err := as.reevaluator.InvocationHierarchy(md.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get the hierarchy of invocation %s: %v", md.GetId(), err)
+	}
+	if hierarchy == nil {
+		return nil, status.Errorf(codes.NotFound, "invocation %s not found", md.GetId())
+	}
+	return toInvocationHierarchy(hierarchy), nil
+}
+
+func toInvocationHierarchy(hierarchy *controller.InvocationHierarchy) *InvocationHierarchy {
+	result := &InvocationHierarchy{
+		Id:                hierarchy.ID,
+		Status:            hierarchy.Status.String(),
+		ParentTaskId:      hierarchy.ParentTaskID,
+		CancelPropagation: hierarchy.CancelPropagation,
+	}
+	if decision := hierarchy.Decision; decision != nil {
+		result.CancelDecision = &CancelDecision{
+			ParentId:     decision.ParentID,
+			ParentTaskId: decision.ParentTaskID,
+			Mode:         decision.Mode,
+			Canceled:     decision.Canceled,
+			Error:        decision.Err,
+			DecidedAt:    decision.DecidedAt.Format(time.RFC3339),
+		}
+	}
+	for _, child := range hierarchy.Children {
+		result.Children = append(result.Children, toInvocationHierarchy(child))
+	}
+	return result
+}
+
+// GetRedactedOutput returns the original values of the redacted output fields of a task, which are kept encrypted in
+// the vault rather than in the event store.
+func (as *Admin) GetRedactedOutput(ctx context.Context, req *RedactedOutputRequest) (*RedactedOutput, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.vault == nil {
+		return nil, status.Error(codes.Unavailable, "redacted output values are not kept")
+	}
+	values, ok, err := as.vault.Retrieve(req.GetId(), req.GetTaskId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to retrieve redacted output values: %v", err)
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no redacted output values of task %s of invocation %s",
+			req.GetTaskId(), req.GetId())
+	}
+	result := &RedactedOutput{
+		Id:     req.GetId(),
+		TaskId: req.GetTaskId(),
+	}
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		value, err := json.Marshal(values[path])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode redacted value of '%s': %v", path, err)
+		}
+		result.Fields = append(result.Fields, &RedactedField{
+			Path:  path,
+			Value: string(value),
+		})
+	}
+	return result, nil
+}
+
+// GetWorkflowMetrics returns which workflows have their own series in the per-workflow metrics.
+func (as *Admin) GetWorkflowMetrics(ctx context.Context, _ *empty.Empty) (*WorkflowMetricsConfig, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.metrics == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	return toWorkflowMetricsConfig(as.metrics.Config()), nil
+}
+
+// SetWorkflowMetrics changes which workflows have their own series in the per-workflow metrics. The series of the
+// workflows that no longer qualify are removed.
+func (as *Admin) SetWorkflowMetrics(ctx context.Context, req *WorkflowMetricsConfig) (*WorkflowMetricsConfig, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.metrics == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	if req.GetThreshold() < 0 || req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "threshold and limit cannot be negative")
+	}
+	as.metrics.Configure(controller.WorkflowMetricsConfig{
+		Workflows: req.GetWorkflows(),
+		Threshold: int(req.GetThreshold()),
+		Limit:     int(req.GetLimit()),
+	})
+	logrus.Warnf("Changed the per-workflow metrics (admin API): workflows=%v, threshold=%d, limit=%d",
+		req.GetWorkflows(), req.GetThreshold(), req.GetLimit())
+	return toWorkflowMetricsConfig(as.metrics.Config()), nil
+}
+
+// GetWorkflowDashboard generates a Grafana dashboard of the per-workflow metrics of the workflow. The dashboard only
+// shows data if the workflow has its own series in the per-workflow metrics.
+func (as *Admin) GetWorkflowDashboard(ctx context.Context, md *types.ObjectMetadata) (*WorkflowDashboard, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if len(md.GetId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no workflow ID provided")
+	}
+	dashboard, err := json.MarshalIndent(controller.WorkflowDashboard(md.GetId()), "", "  ")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode the dashboard of workflow %s: %v", md.GetId(), err)
+	}
+	return &WorkflowDashboard{
+		WorkflowId: md.GetId(),
+		Dashboard:  string(dashboard),
+	}, nil
+}
+
+func toWorkflowMetricsConfig(config controller.WorkflowMetricsConfig) *WorkflowMetricsConfig {
+	return &WorkflowMetricsConfig{
+		Workflows: config.Workflows,
+		Threshold: int32(config.Threshold),
+		Limit:     int32(config.Limit),
+	}
+}
+
+func (as *Admin) authorize(ctx context.Context) error {
+	if len(as.token) == 0 {
+		return status.Error(codes.PermissionDenied, "admin functions are disabled: no admin token configured")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, auth := range md[authorizationKey] {
+		token := strings.TrimPrefix(auth, authorizationPrefix)
+		if token != auth && subtle.ConstantTimeCompare([]byte(token), []byte(as.token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid or missing admin token")
+}
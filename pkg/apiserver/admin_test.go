@@ -0,0 +1,410 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/redact"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationKey, "Bearer "+token))
+}
+
+func errorCode(err error) codes.Code {
+	st, _ := status.FromError(err)
+	return st.Code()
+}
+
+func TestAdmin_ExpressionState(t *testing.T) {
+	store := expr.NewStore()
+	store.Set("wi-1", &expr.Scope{})
+	admin := NewAdmin(store, nil, nil, nil, nil, nil, "secret")
+	md := &types.ObjectMetadata{Id: "wi-1"}
+
+	state, err := admin.GetExpressionState(withToken("secret"), md)
+	assert.NoError(t, err)
+	assert.Equal(t, "wi-1", state.Id)
+	assert.NotEmpty(t, state.Scope)
+
+	_, err = admin.ClearExpressionState(withToken("secret"), md)
+	assert.NoError(t, err)
+	_, ok := store.Get("wi-1")
+	assert.False(t, ok)
+
+	_, err = admin.GetExpressionState(withToken("secret"), md)
+	assert.Equal(t, codes.NotFound, errorCode(err))
+}
+
+func TestAdmin_ExpressionStateUnauthorized(t *testing.T) {
+	store := expr.NewStore()
+	store.Set("wi-1", &expr.Scope{})
+	md := &types.ObjectMetadata{Id: "wi-1"}
+
+	_, err := NewAdmin(store, nil, nil, nil, nil, nil, "secret").ClearExpressionState(withToken("wrong"), md)
+	assert.Equal(t, codes.Unauthenticated, errorCode(err))
+	_, err = NewAdmin(store, nil, nil, nil, nil, nil, "secret").ClearExpressionState(context.Background(), md)
+	assert.Equal(t, codes.Unauthenticated, errorCode(err))
+	_, err = NewAdmin(store, nil, nil, nil, nil, nil, "").ClearExpressionState(withToken(""), md)
+	assert.Equal(t, codes.PermissionDenied, errorCode(err))
+
+	_, ok := store.Get("wi-1")
+	assert.True(t, ok)
+}
+
+type fakeReevaluator struct {
+	invocations map[string]bool
+	degraded    bool
+	unhealthy   []string
+	handoff     controller.HandoffStatus
+	snapshot    *controller.Snapshot
+	hierarchy   *controller.InvocationHierarchy
+}
+
+func (r *fakeReevaluator) Reevaluate(invocationID string) (found bool, enqueued bool, err error) {
+	if !r.invocations[invocationID] {
+		return false, false, nil
+	}
+	return true, true, nil
+}
+
+func (r *fakeReevaluator) EvaluationStats(invocationID string) (ctrl.ControllerStats, bool) {
+	if !r.invocations[invocationID] {
+		return ctrl.ControllerStats{}, false
+	}
+	return ctrl.ControllerStats{
+		LastEvaluatedAt: time.Now(),
+		EvalCount:       2,
+		LastQueueWait:   500 * time.Millisecond,
+		TotalQueueWait:  1500 * time.Millisecond,
+	}, true
+}
+
+func (r *fakeReevaluator) TraceDecision(invocationID string) (controller.TraceDecision, bool) {
+	if !r.invocations[invocationID] {
+		return controller.TraceDecision{}, false
+	}
+	return controller.TraceDecision{Sampled: true, Reason: controller.SamplingReasonDebug, TraceID: "abc"}, true
+}
+
+func (r *fakeReevaluator) UpdatesMode() controller.UpdatesMode {
+	return controller.UpdatesModePolling
+}
+
+func (r *fakeReevaluator) StateStoreDegraded() bool {
+	return r.degraded
+}
+
+func (r *fakeReevaluator) UnhealthyRuntimes() []string {
+	return r.unhealthy
+}
+
+func (r *fakeReevaluator) HandoffStatus() controller.HandoffStatus {
+	return r.handoff
+}
+
+func (r *fakeReevaluator) ExportState(drainTimeout time.Duration) (*controller.Snapshot, error) {
+	if r.handoff.State != controller.HandoffActive {
+		return nil, controller.ErrHandoffState
+	}
+	r.handoff = controller.HandoffStatus{
+		State:     controller.HandoffReleased,
+		HandoffID: r.snapshot.HandoffID,
+		Epoch:     r.snapshot.Epoch,
+	}
+	return r.snapshot, nil
+}
+
+func (r *fakeReevaluator) ImportState(snapshot *controller.Snapshot) error {
+	if r.handoff.State != controller.HandoffStandby {
+		return controller.ErrHandoffState
+	}
+	r.snapshot = snapshot
+	r.handoff = controller.HandoffStatus{
+		State:     controller.HandoffActive,
+		HandoffID: snapshot.HandoffID,
+		Epoch:     snapshot.Epoch,
+	}
+	return nil
+}
+
+func (r *fakeReevaluator) ReclaimState(handoffID string) error {
+	if handoffID != r.handoff.HandoffID {
+		return controller.ErrHandoffMismatch
+	}
+	r.handoff.State = controller.HandoffActive
+	return nil
+}
+
+func (r *fakeReevaluator) InvocationHierarchy(invocationID string) (*controller.InvocationHierarchy, error) {
+	if r.hierarchy == nil || r.hierarchy.ID != invocationID {
+		return nil, nil
+	}
+	return r.hierarchy, nil
+}
+
+func TestAdmin_Status(t *testing.T) {
+	health, err := NewAdmin(nil, nil, nil, nil, nil, nil, "").Status(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusOK, health.Status)
+	assert.Empty(t, health.InvocationUpdates)
+
+	health, err = NewAdmin(nil, &fakeReevaluator{}, nil, nil, nil, nil, "").Status(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, "polling", health.InvocationUpdates)
+	assert.Equal(t, StatusOK, health.Status)
+
+	health, err = NewAdmin(nil, &fakeReevaluator{degraded: true}, nil, nil, nil, nil, "").Status(context.Background(),
+		&empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusDegraded, health.Status)
+	assert.Equal(t, []string{HealthStateStore}, health.Degraded)
+
+	health, err = NewAdmin(nil, &fakeReevaluator{unhealthy: []string{"fission"}}, nil, nil, nil, nil, "").
+		Status(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusDegraded, health.Status)
+	assert.Equal(t, []string{"runtime:fission"}, health.Degraded)
+}
+
+func TestAdmin_Reevaluate(t *testing.T) {
+	admin := NewAdmin(nil, &fakeReevaluator{invocations: map[string]bool{"wi-1": true}}, nil, nil, nil, nil, "secret")
+
+	result, err := admin.Reevaluate(withToken("secret"), &types.ObjectMetadata{Id: "wi-1"})
+	assert.NoError(t, err)
+	assert.True(t, result.Found)
+	assert.True(t, result.Enqueued)
+
+	result, err = admin.Reevaluate(withToken("secret"), &types.ObjectMetadata{Id: "wi-2"})
+	assert.NoError(t, err)
+	assert.False(t, result.Found)
+	assert.False(t, result.Enqueued)
+
+	_, err = admin.Reevaluate(withToken("wrong"), &types.ObjectMetadata{Id: "wi-1"})
+	assert.Equal(t, codes.Unauthenticated, errorCode(err))
+	_, err = NewAdmin(nil, nil, nil, nil, nil, nil, "secret").Reevaluate(withToken("secret"),
+		&types.ObjectMetadata{Id: "wi-1"})
+	assert.Equal(t, codes.Unavailable, errorCode(err))
+}
+
+func TestAdmin_GetEvaluationStats(t *testing.T) {
+	admin := NewAdmin(nil, &fakeReevaluator{invocations: map[string]bool{"wi-1": true}}, nil, nil, nil, nil, "secret")
+
+	stats, err := admin.GetEvaluationStats(withToken("secret"), &types.ObjectMetadata{Id: "wi-1"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, stats.EvalCount)
+	assert.Equal(t, 0.5, stats.LastQueueWaitSeconds)
+	assert.Equal(t, 1.5, stats.TotalQueueWaitSeconds)
+	assert.True(t, stats.TraceSampled)
+	assert.Equal(t, "abc", stats.TraceId)
+	assert.Equal(t, controller.SamplingReasonDebug, stats.TraceSamplingReason)
+
+	_, err = admin.GetEvaluationStats(withToken("secret"), &types.ObjectMetadata{Id: "wi-2"})
+	assert.Equal(t, codes.NotFound, errorCode(err))
+}
+
+func TestAdmin_SuspendFunction(t *testing.T) {
+	suspensions := controller.NewFunctionSuspensions()
+	admin := NewAdmin(nil, nil, suspensions, nil, nil, nil, "secret")
+	req := &FunctionSuspension{FnRef: "payments"}
+
+	_, err := admin.SuspendFunction(withToken("secret"), req)
+	assert.NoError(t, err)
+	suspensions.SetWaiting("wi-1", map[string]string{"charge": "payments"})
+	list, err := admin.ListSuspendedFunctions(withToken("secret"), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Functions, 1)
+	assert.Equal(t, "payments", list.Functions[0].FnRef)
+	assert.EqualValues(t, 1, list.Functions[0].WaitingTasks)
+
+	_, err = admin.ResumeFunction(withToken("secret"), req)
+	assert.NoError(t, err)
+	list, err = admin.ListSuspendedFunctions(withToken("secret"), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Empty(t, list.Functions)
+
+	_, err = admin.SuspendFunction(withToken("secret"), &FunctionSuspension{})
+	assert.Equal(t, codes.InvalidArgument, errorCode(err))
+	_, err = admin.SuspendFunction(withToken("wrong"), req)
+	assert.Equal(t, codes.Unauthenticated, errorCode(err))
+}
+
+func TestAdmin_GetConcurrencyLock(t *testing.T) {
+	locks := controller.NewConcurrencyLocks()
+	locks.Acquire("deploy", "service-a", "wi-1", true)
+	locks.Acquire("deploy", "service-a", "wi-2", true)
+	admin := NewAdmin(nil, nil, nil, locks, nil, nil, "secret")
+
+	lock, err := admin.GetConcurrencyLock(withToken("secret"), &ConcurrencyKey{WorkflowId: "deploy", Key: "service-a"})
+	assert.NoError(t, err)
+	assert.Equal(t, "wi-1", lock.Holder)
+	assert.Equal(t, []string{"wi-2"}, lock.Queued)
+
+	_, err = admin.GetConcurrencyLock(withToken("secret"), &ConcurrencyKey{WorkflowId: "deploy", Key: "service-b"})
+	assert.Equal(t, codes.NotFound, errorCode(err))
+}
+
+func TestAdmin_GetRedactedOutput(t *testing.T) {
+	vault, err := redact.NewVault([]byte("0123456789abcdef"), redact.NewMemoryStore(0))
+	assert.NoError(t, err)
+	assert.NoError(t, vault.Store("wi-1", "lookup", map[string]interface{}{"ssn": "123-45-6789"}))
+	admin := NewAdmin(nil, nil, nil, nil, nil, vault, "secret")
+
+	output, err := admin.GetRedactedOutput(withToken("secret"), &RedactedOutputRequest{Id: "wi-1", TaskId: "lookup"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*RedactedField{{Path: "ssn", Value: `"123-45-6789"`}}, output.Fields)
+
+	_, err = admin.GetRedactedOutput(withToken("secret"), &RedactedOutputRequest{Id: "wi-1", TaskId: "other"})
+	assert.Equal(t, codes.NotFound, errorCode(err))
+	_, err = admin.GetRedactedOutput(withToken("wrong"), &RedactedOutputRequest{Id: "wi-1", TaskId: "lookup"})
+	assert.Equal(t, codes.Unauthenticated, errorCode(err))
+	_, err = NewAdmin(nil, nil, nil, nil, nil, nil, "secret").GetRedactedOutput(withToken("secret"),
+		&RedactedOutputRequest{Id: "wi-1", TaskId: "lookup"})
+	assert.Equal(t, codes.Unavailable, errorCode(err))
+}
+
+func TestAdmin_SetWorkflowMetrics(t *testing.T) {
+	admin := NewAdmin(nil, nil, nil, nil, controller.NewWorkflowMetrics(controller.WorkflowMetricsConfig{}), nil,
+		"secret")
+
+	config, err := admin.SetWorkflowMetrics(withToken("secret"), &WorkflowMetricsConfig{
+		Workflows: []string{"checkout"},
+		Threshold: 100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"checkout"}, config.Workflows)
+	config, err = admin.GetWorkflowMetrics(withToken("secret"), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 100, config.Threshold)
+
+	_, err = admin.SetWorkflowMetrics(withToken("secret"), &WorkflowMetricsConfig{Limit: -1})
+	assert.Equal(t, codes.InvalidArgument, errorCode(err))
+	_, err = admin.SetWorkflowMetrics(withToken("wrong"), &WorkflowMetricsConfig{})
+	assert.Equal(t, codes.Unauthenticated, errorCode(err))
+}
+
+func TestAdmin_GetWorkflowDashboard(t *testing.T) {
+	admin := NewAdmin(nil, nil, nil, nil, nil, nil, "secret")
+
+	dashboard, err := admin.GetWorkflowDashboard(withToken("secret"), &types.ObjectMetadata{Id: "checkout"})
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout", dashboard.WorkflowId)
+	result := &controller.Dashboard{}
+	assert.NoError(t, json.Unmarshal([]byte(dashboard.Dashboard), result))
+	assert.Equal(t, controller.WorkflowDashboard("checkout"), result)
+
+	_, err = admin.GetWorkflowDashboard(withToken("secret"), &types.ObjectMetadata{})
+	assert.Equal(t, codes.InvalidArgument, errorCode(err))
+	_, err = admin.GetWorkflowDashboard(withToken("wrong"), &types.ObjectMetadata{Id: "checkout"})
+	assert.Equal(t, codes.Unauthenticated, errorCode(err))
+}
+
+func TestAdmin_Handoff(t *testing.T) {
+	now := time.Now()
+	snapshot := &controller.Snapshot{
+		HandoffID: "h-1",
+		Epoch:     1,
+		CreatedAt: now,
+		Queued:    []string{"wi-2"},
+		Invocations: []controller.InvocationSnapshot{
+			{
+				ID:           "wi-1",
+				StartedTasks: []string{"a", "b"},
+				ErrorCount:   1,
+				TaskErrors:   map[string]int{"b": 1},
+				Prewarmed:    true,
+				LockKey:      "key",
+				PendingPolls: map[string]time.Time{"sensor": now.Add(time.Second)},
+				Stats:        ctrl.ControllerStats{EvalCount: 3, LastEvaluatedAt: now, TotalQueueWait: time.Second},
+			},
+		},
+		Locks: []controller.ConcurrencyLock{{WorkflowID: "wf", Key: "key", Holder: "wi-1", Queued: []string{"wi-2"}}},
+	}
+	old := &fakeReevaluator{snapshot: snapshot, handoff: controller.HandoffStatus{State: controller.HandoffActive}}
+	next := &fakeReevaluator{handoff: controller.HandoffStatus{State: controller.HandoffStandby}}
+	oldAdmin := NewAdmin(nil, old, nil, nil, nil, nil, "secret")
+	nextAdmin := NewAdmin(nil, next, nil, nil, nil, nil, "secret")
+
+	_, err := oldAdmin.ExportControllerState(context.Background(), &HandoffRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	exported, err := oldAdmin.ExportControllerState(withToken("secret"), &HandoffRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "h-1", exported.HandoffId)
+
+	// The invocations have been released, so they cannot be exported again.
+	_, err = oldAdmin.ExportControllerState(withToken("secret"), &HandoffRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	handoff, err := nextAdmin.ImportControllerState(withToken("secret"), exported)
+	assert.NoError(t, err)
+	assert.Equal(t, string(controller.HandoffActive), handoff.State)
+	assert.EqualValues(t, 1, handoff.Epoch)
+	assert.Equal(t, snapshot.Invocations[0].PendingPolls["sensor"].UnixNano(),
+		next.snapshot.Invocations[0].PendingPolls["sensor"].UnixNano())
+	next.snapshot.Invocations[0].PendingPolls = snapshot.Invocations[0].PendingPolls
+	next.snapshot.Invocations[0].Stats.LastEvaluatedAt = now
+	next.snapshot.CreatedAt = now
+	assert.Equal(t, snapshot, next.snapshot)
+
+	_, err = oldAdmin.ReclaimControllerState(withToken("secret"), &HandoffReclaim{HandoffId: "h-2"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	handoff, err = oldAdmin.ReclaimControllerState(withToken("secret"), &HandoffReclaim{HandoffId: "h-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, string(controller.HandoffActive), handoff.State)
+}
+
+func TestAdmin_GetInvocationHierarchy(t *testing.T) {
+	decidedAt := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	reevaluator := &fakeReevaluator{hierarchy: &controller.InvocationHierarchy{
+		ID:     "wi",
+		Status: types.WorkflowInvocationStatus_ABORTED,
+		Children: []*controller.InvocationHierarchy{
+			{
+				ID:                "child",
+				Status:            types.WorkflowInvocationStatus_IN_PROGRESS,
+				ParentTaskID:      "notify",
+				CancelPropagation: types.CancelPropagationDetach,
+				Decision: &controller.CancelDecision{
+					InvocationID: "child",
+					ParentID:     "wi",
+					ParentTaskID: "notify",
+					Mode:         types.CancelPropagationDetach,
+					DecidedAt:    decidedAt,
+				},
+			},
+		},
+	}}
+	admin := NewAdmin(nil, reevaluator, nil, nil, nil, nil, "secret")
+
+	hierarchy, err := admin.GetInvocationHierarchy(withToken("secret"), &types.ObjectMetadata{Id: "wi"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ABORTED", hierarchy.Status)
+	assert.Len(t, hierarchy.Children, 1)
+	assert.Equal(t, &InvocationHierarchy{
+		Id:                "child",
+		Status:            "IN_PROGRESS",
+		ParentTaskId:      "notify",
+		CancelPropagation: types.CancelPropagationDetach,
+		CancelDecision: &CancelDecision{
+			ParentId:     "wi",
+			ParentTaskId: "notify",
+			Mode:         types.CancelPropagationDetach,
+			DecidedAt:    decidedAt.Format(time.RFC3339),
+		},
+	}, hierarchy.Children[0])
+
+	_, err = admin.GetInvocationHierarchy(withToken("secret"), &types.ObjectMetadata{Id: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -14,6 +14,11 @@ packages that are responsible for the business logic, such as `api`.
 package apiserver
 
 import (
+	"context"
+
+	"github.com/fission/fission-workflows/pkg/admission"
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/fnenv/workflows"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
@@ -24,10 +29,28 @@ import (
 type Empty = empty.Empty
 
 func toErrorStatus(err error) error {
+	switch err {
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
 	switch err.(type) {
 	case validate.Error:
 		logrus.Errorf("Request error: %v", validate.FormatConcise(err))
 		return status.Error(codes.InvalidArgument, validate.Format(err))
+	case *admission.DeniedError:
+		logrus.Infof("Request denied: %v", err)
+		return status.Error(codes.PermissionDenied, err.Error())
+	case *admission.WebhookError:
+		logrus.Errorf("Request error: %v", err)
+		return status.Error(codes.Unavailable, err.Error())
+	case workflows.ErrWorkflowNotFound:
+		logrus.Infof("Request error: %v", err)
+		return status.Error(codes.NotFound, err.Error())
+	case api.ErrWorkflowQuarantined:
+		logrus.Infof("Request error: %v", err)
+		return status.Error(codes.FailedPrecondition, err.Error())
 	default:
 		logrus.Errorf("Request error: %v", err)
 		return err
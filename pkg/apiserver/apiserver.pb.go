@@ -9,11 +9,47 @@ It is generated from these files:
 
 It has these top-level messages:
 	WorkflowList
+	WorkflowDiffRequest
+	WorkflowDiff
+	TaskDiff
 	AddTaskRequest
+	ReplayFailedTasksRequest
+	ReplayFailedTasksResponse
+	InvocationPurgeRequest
+	InvocationPurgeResult
+	InvocationArchiveRequest
+	InvocationArchiveResult
 	InvocationListQuery
 	WorkflowInvocationList
+	InvocationStatusQuery
+	InvocationStatusList
+	InvocationStatusResult
+	TaskRequest
+	InvocationGroup
+	InvocationTimeline
+	TaskTiming
 	ObjectEvents
 	Health
+	ExpressionState
+	ReevaluateResult
+	FunctionSuspension
+	SuspendedFunction
+	SuspendedFunctionList
+	ConcurrencyKey
+	ConcurrencyLock
+	EvaluationStats
+	RedactedOutputRequest
+	RedactedField
+	RedactedOutput
+	WorkflowMetricsConfig
+	HandoffStatus
+	HandoffRequest
+	ControllerSnapshot
+	InvocationControllerState
+	HandoffReclaim
+	InvocationHierarchy
+	CancelDecision
+	WorkflowDashboard
 */
 package apiserver
 
@@ -58,6 +94,127 @@ func (m *WorkflowList) GetWorkflows() []string {
 	return nil
 }
 
+type WorkflowDiffRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// versionA is the version to compare against. If 0, the version preceding versionB is used.
+	VersionA int32 `protobuf:"varint,2,opt,name=versionA" json:"versionA,omitempty"`
+	// versionB is the version to compare. If 0, the latest version is used.
+	VersionB int32 `protobuf:"varint,3,opt,name=versionB" json:"versionB,omitempty"`
+}
+
+func (m *WorkflowDiffRequest) Reset()                    { *m = WorkflowDiffRequest{} }
+func (m *WorkflowDiffRequest) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowDiffRequest) ProtoMessage()               {}
+func (*WorkflowDiffRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *WorkflowDiffRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WorkflowDiffRequest) GetVersionA() int32 {
+	if m != nil {
+		return m.VersionA
+	}
+	return 0
+}
+
+func (m *WorkflowDiffRequest) GetVersionB() int32 {
+	if m != nil {
+		return m.VersionB
+	}
+	return 0
+}
+
+// WorkflowDiff describes the changes between two versions of a workflow.
+//
+// The changes are ordered deterministically to allow the diff to be compared as is.
+type WorkflowDiff struct {
+	Id       string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	VersionA int32  `protobuf:"varint,2,opt,name=versionA" json:"versionA,omitempty"`
+	VersionB int32  `protobuf:"varint,3,opt,name=versionB" json:"versionB,omitempty"`
+	// fields contains the names of the workflow-level fields (e.g. outputTask) that changed.
+	Fields []string `protobuf:"bytes,4,rep,name=fields" json:"fields,omitempty"`
+	// tasks contains the tasks that were added, removed or modified, ordered by task ID.
+	Tasks []*TaskDiff `protobuf:"bytes,5,rep,name=tasks" json:"tasks,omitempty"`
+}
+
+func (m *WorkflowDiff) Reset()                    { *m = WorkflowDiff{} }
+func (m *WorkflowDiff) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowDiff) ProtoMessage()               {}
+func (*WorkflowDiff) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *WorkflowDiff) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WorkflowDiff) GetVersionA() int32 {
+	if m != nil {
+		return m.VersionA
+	}
+	return 0
+}
+
+func (m *WorkflowDiff) GetVersionB() int32 {
+	if m != nil {
+		return m.VersionB
+	}
+	return 0
+}
+
+func (m *WorkflowDiff) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+func (m *WorkflowDiff) GetTasks() []*TaskDiff {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
+type TaskDiff struct {
+	TaskId string `protobuf:"bytes,1,opt,name=taskId" json:"taskId,omitempty"`
+	// change is one of ADDED, REMOVED, or MODIFIED.
+	Change string `protobuf:"bytes,2,opt,name=change" json:"change,omitempty"`
+	// fields contains the fields of the task that changed, such as 'functionRef', 'requires' or 'inputs.<key>'.
+	Fields []string `protobuf:"bytes,3,rep,name=fields" json:"fields,omitempty"`
+}
+
+func (m *TaskDiff) Reset()                    { *m = TaskDiff{} }
+func (m *TaskDiff) String() string            { return proto.CompactTextString(m) }
+func (*TaskDiff) ProtoMessage()               {}
+func (*TaskDiff) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *TaskDiff) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *TaskDiff) GetChange() string {
+	if m != nil {
+		return m.Change
+	}
+	return ""
+}
+
+func (m *TaskDiff) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 type AddTaskRequest struct {
 	InvocationID string                         `protobuf:"bytes,1,opt,name=invocationID" json:"invocationID,omitempty"`
 	Task         *fission_workflows_types1.Task `protobuf:"bytes,2,opt,name=task" json:"task,omitempty"`
@@ -66,7 +223,7 @@ type AddTaskRequest struct {
 func (m *AddTaskRequest) Reset()                    { *m = AddTaskRequest{} }
 func (m *AddTaskRequest) String() string            { return proto.CompactTextString(m) }
 func (*AddTaskRequest) ProtoMessage()               {}
-func (*AddTaskRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*AddTaskRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *AddTaskRequest) GetInvocationID() string {
 	if m != nil {
@@ -82,752 +239,3134 @@ func (m *AddTaskRequest) GetTask() *fission_workflows_types1.Task {
 	return nil
 }
 
-type InvocationListQuery struct {
-	Workflows []string `protobuf:"bytes,1,rep,name=workflows" json:"workflows,omitempty"`
+type ReplayFailedTasksRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// FunctionOverrides contains the functions to execute the replayed tasks with instead of their original functions,
+	// with the key being the task id. The functions are resolved like the functions of a workflow.
+	FunctionOverrides map[string]string `protobuf:"bytes,2,rep,name=functionOverrides" json:"functionOverrides,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
-func (m *InvocationListQuery) Reset()                    { *m = InvocationListQuery{} }
-func (m *InvocationListQuery) String() string            { return proto.CompactTextString(m) }
-func (*InvocationListQuery) ProtoMessage()               {}
-func (*InvocationListQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (m *ReplayFailedTasksRequest) Reset()                    { *m = ReplayFailedTasksRequest{} }
+func (m *ReplayFailedTasksRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReplayFailedTasksRequest) ProtoMessage()               {}
+func (*ReplayFailedTasksRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
-func (m *InvocationListQuery) GetWorkflows() []string {
+func (m *ReplayFailedTasksRequest) GetId() string {
 	if m != nil {
-		return m.Workflows
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReplayFailedTasksRequest) GetFunctionOverrides() map[string]string {
+	if m != nil {
+		return m.FunctionOverrides
 	}
 	return nil
 }
 
-type WorkflowInvocationList struct {
-	Invocations []string `protobuf:"bytes,1,rep,name=invocations" json:"invocations,omitempty"`
+type ReplayFailedTasksResponse struct {
+	// TaskIds contains the IDs of the replayed tasks.
+	TaskIds []string `protobuf:"bytes,1,rep,name=taskIds" json:"taskIds,omitempty"`
 }
 
-func (m *WorkflowInvocationList) Reset()                    { *m = WorkflowInvocationList{} }
-func (m *WorkflowInvocationList) String() string            { return proto.CompactTextString(m) }
-func (*WorkflowInvocationList) ProtoMessage()               {}
-func (*WorkflowInvocationList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (m *ReplayFailedTasksResponse) Reset()                    { *m = ReplayFailedTasksResponse{} }
+func (m *ReplayFailedTasksResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReplayFailedTasksResponse) ProtoMessage()               {}
+func (*ReplayFailedTasksResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
-func (m *WorkflowInvocationList) GetInvocations() []string {
+func (m *ReplayFailedTasksResponse) GetTaskIds() []string {
 	if m != nil {
-		return m.Invocations
+		return m.TaskIds
 	}
 	return nil
 }
 
-type ObjectEvents struct {
-	Metadata *fission_workflows_types1.ObjectMetadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata,omitempty"`
-	Events   []*fission_workflows_eventstore.Event    `protobuf:"bytes,2,rep,name=events" json:"events,omitempty"`
+type InvocationPurgeRequest struct {
+	// WorkflowId limits the purge to the invocations of the workflow, if set.
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflowId" json:"workflowId,omitempty"`
+	// Labels limits the purge to the invocations that have all of the labels, if set.
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// OlderThan is the minimum time (e.g. 72h) since the invocations finished. It is required.
+	OlderThan string `protobuf:"bytes,3,opt,name=olderThan" json:"olderThan,omitempty"`
+	// DryRun reports the invocations that would be purged without deleting them.
+	DryRun bool `protobuf:"varint,4,opt,name=dryRun" json:"dryRun,omitempty"`
 }
 
-func (m *ObjectEvents) Reset()                    { *m = ObjectEvents{} }
-func (m *ObjectEvents) String() string            { return proto.CompactTextString(m) }
-func (*ObjectEvents) ProtoMessage()               {}
-func (*ObjectEvents) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (m *InvocationPurgeRequest) Reset()                    { *m = InvocationPurgeRequest{} }
+func (m *InvocationPurgeRequest) String() string            { return proto.CompactTextString(m) }
+func (*InvocationPurgeRequest) ProtoMessage()               {}
+func (*InvocationPurgeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
-func (m *ObjectEvents) GetMetadata() *fission_workflows_types1.ObjectMetadata {
+func (m *InvocationPurgeRequest) GetWorkflowId() string {
 	if m != nil {
-		return m.Metadata
+		return m.WorkflowId
+	}
+	return ""
+}
+
+func (m *InvocationPurgeRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
 	}
 	return nil
 }
 
-func (m *ObjectEvents) GetEvents() []*fission_workflows_eventstore.Event {
+func (m *InvocationPurgeRequest) GetOlderThan() string {
 	if m != nil {
-		return m.Events
+		return m.OlderThan
+	}
+	return ""
+}
+
+func (m *InvocationPurgeRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type InvocationPurgeResult struct {
+	// Invocations contains the IDs of the purged invocations, or the invocations that would be purged in a dry run.
+	Invocations []string `protobuf:"bytes,1,rep,name=invocations" json:"invocations,omitempty"`
+	Count       int32    `protobuf:"varint,2,opt,name=count" json:"count,omitempty"`
+	// Skipped is the number of invocations that match the filter, but have not finished.
+	Skipped int32 `protobuf:"varint,3,opt,name=skipped" json:"skipped,omitempty"`
+	DryRun  bool  `protobuf:"varint,4,opt,name=dryRun" json:"dryRun,omitempty"`
+}
+
+func (m *InvocationPurgeResult) Reset()                    { *m = InvocationPurgeResult{} }
+func (m *InvocationPurgeResult) String() string            { return proto.CompactTextString(m) }
+func (*InvocationPurgeResult) ProtoMessage()               {}
+func (*InvocationPurgeResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *InvocationPurgeResult) GetInvocations() []string {
+	if m != nil {
+		return m.Invocations
 	}
 	return nil
 }
 
-type Health struct {
-	Status string `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+func (m *InvocationPurgeResult) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
 }
 
-func (m *Health) Reset()                    { *m = Health{} }
-func (m *Health) String() string            { return proto.CompactTextString(m) }
-func (*Health) ProtoMessage()               {}
-func (*Health) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (m *InvocationPurgeResult) GetSkipped() int32 {
+	if m != nil {
+		return m.Skipped
+	}
+	return 0
+}
 
-func (m *Health) GetStatus() string {
+func (m *InvocationPurgeResult) GetDryRun() bool {
 	if m != nil {
-		return m.Status
+		return m.DryRun
 	}
-	return ""
+	return false
 }
 
-func init() {
-	proto.RegisterType((*WorkflowList)(nil), "fission.workflows.apiserver.WorkflowList")
-	proto.RegisterType((*AddTaskRequest)(nil), "fission.workflows.apiserver.AddTaskRequest")
-	proto.RegisterType((*InvocationListQuery)(nil), "fission.workflows.apiserver.InvocationListQuery")
-	proto.RegisterType((*WorkflowInvocationList)(nil), "fission.workflows.apiserver.WorkflowInvocationList")
-	proto.RegisterType((*ObjectEvents)(nil), "fission.workflows.apiserver.ObjectEvents")
-	proto.RegisterType((*Health)(nil), "fission.workflows.apiserver.Health")
+type InvocationArchiveRequest struct {
+	// WorkflowId limits the archival to the invocations of the workflow, if set.
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflowId" json:"workflowId,omitempty"`
+	// Labels limits the archival to the invocations that have all of the labels, if set.
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// OlderThan is the minimum time (e.g. 72h) since the invocations finished. It is required.
+	OlderThan string `protobuf:"bytes,3,opt,name=olderThan" json:"olderThan,omitempty"`
+	// DryRun reports the invocations that would be archived without archiving them.
+	DryRun bool `protobuf:"varint,4,opt,name=dryRun" json:"dryRun,omitempty"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *InvocationArchiveRequest) Reset()                    { *m = InvocationArchiveRequest{} }
+func (m *InvocationArchiveRequest) String() string            { return proto.CompactTextString(m) }
+func (*InvocationArchiveRequest) ProtoMessage()               {}
+func (*InvocationArchiveRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *InvocationArchiveRequest) GetWorkflowId() string {
+	if m != nil {
+		return m.WorkflowId
+	}
+	return ""
+}
 
-// Client API for WorkflowAPI service
+func (m *InvocationArchiveRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
 
-type WorkflowAPIClient interface {
-	Create(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error)
-	CreateSync(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error)
-	List(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*WorkflowList, error)
-	Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error)
-	Delete(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
-	Validate(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
-	Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error)
+func (m *InvocationArchiveRequest) GetOlderThan() string {
+	if m != nil {
+		return m.OlderThan
+	}
+	return ""
 }
 
-type workflowAPIClient struct {
-	cc *grpc.ClientConn
+func (m *InvocationArchiveRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
 }
 
-func NewWorkflowAPIClient(cc *grpc.ClientConn) WorkflowAPIClient {
-	return &workflowAPIClient{cc}
+type InvocationArchiveResult struct {
+	// Invocations contains the IDs of the archived invocations, or the invocations that would be archived in a dry
+	// run.
+	Invocations []string `protobuf:"bytes,1,rep,name=invocations" json:"invocations,omitempty"`
+	Count       int32    `protobuf:"varint,2,opt,name=count" json:"count,omitempty"`
+	// Skipped is the number of invocations that match the filter, but have not finished.
+	Skipped int32 `protobuf:"varint,3,opt,name=skipped" json:"skipped,omitempty"`
+	DryRun  bool  `protobuf:"varint,4,opt,name=dryRun" json:"dryRun,omitempty"`
 }
 
-func (c *workflowAPIClient) Create(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error) {
-	out := new(fission_workflows_types1.ObjectMetadata)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Create", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *InvocationArchiveResult) Reset()                    { *m = InvocationArchiveResult{} }
+func (m *InvocationArchiveResult) String() string            { return proto.CompactTextString(m) }
+func (*InvocationArchiveResult) ProtoMessage()               {}
+func (*InvocationArchiveResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *InvocationArchiveResult) GetInvocations() []string {
+	if m != nil {
+		return m.Invocations
 	}
-	return out, nil
+	return nil
 }
 
-func (c *workflowAPIClient) CreateSync(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error) {
-	out := new(fission_workflows_types1.Workflow)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/CreateSync", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *InvocationArchiveResult) GetCount() int32 {
+	if m != nil {
+		return m.Count
 	}
-	return out, nil
+	return 0
 }
 
-func (c *workflowAPIClient) List(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*WorkflowList, error) {
-	out := new(WorkflowList)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/List", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *InvocationArchiveResult) GetSkipped() int32 {
+	if m != nil {
+		return m.Skipped
 	}
-	return out, nil
+	return 0
 }
 
-func (c *workflowAPIClient) Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error) {
-	out := new(fission_workflows_types1.Workflow)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Get", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *InvocationArchiveResult) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
 	}
-	return out, nil
+	return false
 }
 
-func (c *workflowAPIClient) Delete(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
-	out := new(google_protobuf3.Empty)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Delete", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+type InvocationListQuery struct {
+	Workflows []string `protobuf:"bytes,1,rep,name=workflows" json:"workflows,omitempty"`
+}
+
+func (m *InvocationListQuery) Reset()                    { *m = InvocationListQuery{} }
+func (m *InvocationListQuery) String() string            { return proto.CompactTextString(m) }
+func (*InvocationListQuery) ProtoMessage()               {}
+func (*InvocationListQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *InvocationListQuery) GetWorkflows() []string {
+	if m != nil {
+		return m.Workflows
 	}
-	return out, nil
+	return nil
 }
 
-func (c *workflowAPIClient) Validate(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
-	out := new(google_protobuf3.Empty)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Validate", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+type WorkflowInvocationList struct {
+	Invocations []string `protobuf:"bytes,1,rep,name=invocations" json:"invocations,omitempty"`
+}
+
+func (m *WorkflowInvocationList) Reset()                    { *m = WorkflowInvocationList{} }
+func (m *WorkflowInvocationList) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowInvocationList) ProtoMessage()               {}
+func (*WorkflowInvocationList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *WorkflowInvocationList) GetInvocations() []string {
+	if m != nil {
+		return m.Invocations
 	}
-	return out, nil
+	return nil
 }
 
-func (c *workflowAPIClient) Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error) {
-	out := new(ObjectEvents)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Events", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+type InvocationStatusQuery struct {
+	Ids []string `protobuf:"bytes,1,rep,name=ids" json:"ids,omitempty"`
+	// MaxOutputBytes is the maximum size (in bytes) of each task output in the statuses; larger outputs are truncated.
+	// If 0, the outputs are truncated to 4 KiB. If negative, the outputs are not truncated.
+	MaxOutputBytes int64 `protobuf:"varint,2,opt,name=maxOutputBytes" json:"maxOutputBytes,omitempty"`
+}
+
+func (m *InvocationStatusQuery) Reset()                    { *m = InvocationStatusQuery{} }
+func (m *InvocationStatusQuery) String() string            { return proto.CompactTextString(m) }
+func (*InvocationStatusQuery) ProtoMessage()               {}
+func (*InvocationStatusQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *InvocationStatusQuery) GetIds() []string {
+	if m != nil {
+		return m.Ids
 	}
-	return out, nil
+	return nil
 }
 
-// Server API for WorkflowAPI service
+func (m *InvocationStatusQuery) GetMaxOutputBytes() int64 {
+	if m != nil {
+		return m.MaxOutputBytes
+	}
+	return 0
+}
 
-type WorkflowAPIServer interface {
-	Create(context.Context, *fission_workflows_types1.WorkflowSpec) (*fission_workflows_types1.ObjectMetadata, error)
-	CreateSync(context.Context, *fission_workflows_types1.WorkflowSpec) (*fission_workflows_types1.Workflow, error)
-	List(context.Context, *google_protobuf3.Empty) (*WorkflowList, error)
-	Get(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_types1.Workflow, error)
-	Delete(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
-	Validate(context.Context, *fission_workflows_types1.WorkflowSpec) (*google_protobuf3.Empty, error)
-	Events(context.Context, *fission_workflows_types1.ObjectMetadata) (*ObjectEvents, error)
+type InvocationStatusList struct {
+	Statuses []*InvocationStatusResult `protobuf:"bytes,1,rep,name=statuses" json:"statuses,omitempty"`
 }
 
-func RegisterWorkflowAPIServer(s *grpc.Server, srv WorkflowAPIServer) {
-	s.RegisterService(&_WorkflowAPI_serviceDesc, srv)
+func (m *InvocationStatusList) Reset()                    { *m = InvocationStatusList{} }
+func (m *InvocationStatusList) String() string            { return proto.CompactTextString(m) }
+func (*InvocationStatusList) ProtoMessage()               {}
+func (*InvocationStatusList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *InvocationStatusList) GetStatuses() []*InvocationStatusResult {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+type InvocationStatusResult struct {
+	Id     string                                             `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Status *fission_workflows_types1.WorkflowInvocationStatus `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+	// Error contains the reason why the status of the invocation could not be retrieved.
+	Error string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *InvocationStatusResult) Reset()                    { *m = InvocationStatusResult{} }
+func (m *InvocationStatusResult) String() string            { return proto.CompactTextString(m) }
+func (*InvocationStatusResult) ProtoMessage()               {}
+func (*InvocationStatusResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *InvocationStatusResult) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *InvocationStatusResult) GetStatus() *fission_workflows_types1.WorkflowInvocationStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *InvocationStatusResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// TaskRequest identifies a task of an invocation.
+type TaskRequest struct {
+	Id     string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	TaskId string `protobuf:"bytes,2,opt,name=taskId" json:"taskId,omitempty"`
+}
+
+func (m *TaskRequest) Reset()                    { *m = TaskRequest{} }
+func (m *TaskRequest) String() string            { return proto.CompactTextString(m) }
+func (*TaskRequest) ProtoMessage()               {}
+func (*TaskRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *TaskRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+type InvocationGroup struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// Invocations contains the IDs of the member invocations of the group.
+	Invocations []string `protobuf:"bytes,2,rep,name=invocations" json:"invocations,omitempty"`
+	// Progress contains the number of member invocations for each invocation status.
+	Progress map[string]int32 `protobuf:"bytes,3,rep,name=progress" json:"progress,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Finished indicates whether all member invocations have reached a terminal state.
+	Finished bool `protobuf:"varint,4,opt,name=finished" json:"finished,omitempty"`
+	// Successful indicates whether all member invocations have completed successfully.
+	Successful bool `protobuf:"varint,5,opt,name=successful" json:"successful,omitempty"`
+}
+
+func (m *InvocationGroup) Reset()                    { *m = InvocationGroup{} }
+func (m *InvocationGroup) String() string            { return proto.CompactTextString(m) }
+func (*InvocationGroup) ProtoMessage()               {}
+func (*InvocationGroup) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *InvocationGroup) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *InvocationGroup) GetInvocations() []string {
+	if m != nil {
+		return m.Invocations
+	}
+	return nil
+}
+
+func (m *InvocationGroup) GetProgress() map[string]int32 {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+func (m *InvocationGroup) GetFinished() bool {
+	if m != nil {
+		return m.Finished
+	}
+	return false
+}
+
+func (m *InvocationGroup) GetSuccessful() bool {
+	if m != nil {
+		return m.Successful
+	}
+	return false
+}
+
+// InvocationTimeline contains the timing breakdown of the tasks of an invocation.
+type InvocationTimeline struct {
+	Id     string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Status string `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+	// StartedAt is the time (RFC 3339) at which the invocation was created.
+	StartedAt string `protobuf:"bytes,3,opt,name=startedAt" json:"startedAt,omitempty"`
+	// FinishedAt is the time (RFC 3339) at which the invocation finished, if it has finished.
+	FinishedAt string `protobuf:"bytes,4,opt,name=finishedAt" json:"finishedAt,omitempty"`
+	// Tasks contains the timings of the tasks that have been started, ordered by their start time.
+	Tasks []*TaskTiming `protobuf:"bytes,5,rep,name=tasks" json:"tasks,omitempty"`
+	// CriticalPath contains the IDs of the tasks on the critical path, in order of execution.
+	CriticalPath []string `protobuf:"bytes,6,rep,name=criticalPath" json:"criticalPath,omitempty"`
+}
+
+func (m *InvocationTimeline) Reset()                    { *m = InvocationTimeline{} }
+func (m *InvocationTimeline) String() string            { return proto.CompactTextString(m) }
+func (*InvocationTimeline) ProtoMessage()               {}
+func (*InvocationTimeline) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *InvocationTimeline) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *InvocationTimeline) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *InvocationTimeline) GetStartedAt() string {
+	if m != nil {
+		return m.StartedAt
+	}
+	return ""
+}
+
+func (m *InvocationTimeline) GetFinishedAt() string {
+	if m != nil {
+		return m.FinishedAt
+	}
+	return ""
+}
+
+func (m *InvocationTimeline) GetTasks() []*TaskTiming {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
+func (m *InvocationTimeline) GetCriticalPath() []string {
+	if m != nil {
+		return m.CriticalPath
+	}
+	return nil
+}
+
+// TaskTiming contains the timing breakdown of a task, suitable for rendering a Gantt chart.
+type TaskTiming struct {
+	TaskId string `protobuf:"bytes,1,opt,name=taskId" json:"taskId,omitempty"`
+	Status string `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+	// ReadyAt is the time (RFC 3339) at which the dependencies of the task had finished.
+	ReadyAt    string `protobuf:"bytes,3,opt,name=readyAt" json:"readyAt,omitempty"`
+	StartedAt  string `protobuf:"bytes,4,opt,name=startedAt" json:"startedAt,omitempty"`
+	FinishedAt string `protobuf:"bytes,5,opt,name=finishedAt" json:"finishedAt,omitempty"`
+	// DependencyWaitSeconds is the time between the start of the invocation and the task becoming ready.
+	DependencyWaitSeconds float64 `protobuf:"fixed64,6,opt,name=dependencyWaitSeconds" json:"dependencyWaitSeconds,omitempty"`
+	// QueueWaitSeconds is the time between the task becoming ready and being started.
+	QueueWaitSeconds float64 `protobuf:"fixed64,7,opt,name=queueWaitSeconds" json:"queueWaitSeconds,omitempty"`
+	// ExecutionSeconds is the time between the task being started and finishing.
+	ExecutionSeconds float64 `protobuf:"fixed64,8,opt,name=executionSeconds" json:"executionSeconds,omitempty"`
+	// Critical is true if the task is on the critical path of the invocation.
+	Critical bool `protobuf:"varint,9,opt,name=critical" json:"critical,omitempty"`
+}
+
+func (m *TaskTiming) Reset()                    { *m = TaskTiming{} }
+func (m *TaskTiming) String() string            { return proto.CompactTextString(m) }
+func (*TaskTiming) ProtoMessage()               {}
+func (*TaskTiming) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *TaskTiming) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *TaskTiming) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *TaskTiming) GetReadyAt() string {
+	if m != nil {
+		return m.ReadyAt
+	}
+	return ""
+}
+
+func (m *TaskTiming) GetStartedAt() string {
+	if m != nil {
+		return m.StartedAt
+	}
+	return ""
+}
+
+func (m *TaskTiming) GetFinishedAt() string {
+	if m != nil {
+		return m.FinishedAt
+	}
+	return ""
+}
+
+func (m *TaskTiming) GetDependencyWaitSeconds() float64 {
+	if m != nil {
+		return m.DependencyWaitSeconds
+	}
+	return 0
+}
+
+func (m *TaskTiming) GetQueueWaitSeconds() float64 {
+	if m != nil {
+		return m.QueueWaitSeconds
+	}
+	return 0
+}
+
+func (m *TaskTiming) GetExecutionSeconds() float64 {
+	if m != nil {
+		return m.ExecutionSeconds
+	}
+	return 0
+}
+
+func (m *TaskTiming) GetCritical() bool {
+	if m != nil {
+		return m.Critical
+	}
+	return false
+}
+
+type ObjectEvents struct {
+	Metadata *fission_workflows_types1.ObjectMetadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata,omitempty"`
+	Events   []*fission_workflows_eventstore.Event    `protobuf:"bytes,2,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *ObjectEvents) Reset()                    { *m = ObjectEvents{} }
+func (m *ObjectEvents) String() string            { return proto.CompactTextString(m) }
+func (*ObjectEvents) ProtoMessage()               {}
+func (*ObjectEvents) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *ObjectEvents) GetMetadata() *fission_workflows_types1.ObjectMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *ObjectEvents) GetEvents() []*fission_workflows_eventstore.Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type Health struct {
+	Status string `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	// InvocationUpdates is how the invocation controller learns about invocation updates (push or polling), if it is
+	// running.
+	InvocationUpdates string `protobuf:"bytes,2,opt,name=invocationUpdates" json:"invocationUpdates,omitempty"`
+	// Degraded lists the subsystems that are degraded, such as the expression state store (state-store) or an
+	// unhealthy function runtime (runtime:<name>).
+	Degraded []string `protobuf:"bytes,3,rep,name=degraded" json:"degraded,omitempty"`
+}
+
+func (m *Health) Reset()                    { *m = Health{} }
+func (m *Health) String() string            { return proto.CompactTextString(m) }
+func (*Health) ProtoMessage()               {}
+func (*Health) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *Health) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Health) GetInvocationUpdates() string {
+	if m != nil {
+		return m.InvocationUpdates
+	}
+	return ""
+}
+
+func (m *Health) GetDegraded() []string {
+	if m != nil {
+		return m.Degraded
+	}
+	return nil
+}
+
+// ExpressionState contains the expression state of an invocation, as cached by the invocation controller.
+type ExpressionState struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// Scope is the JSON-encoded expression scope of the invocation.
+	Scope string `protobuf:"bytes,2,opt,name=scope" json:"scope,omitempty"`
+}
+
+func (m *ExpressionState) Reset()                    { *m = ExpressionState{} }
+func (m *ExpressionState) String() string            { return proto.CompactTextString(m) }
+func (*ExpressionState) ProtoMessage()               {}
+func (*ExpressionState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *ExpressionState) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ExpressionState) GetScope() string {
+	if m != nil {
+		return m.Scope
+	}
+	return ""
+}
+
+// ReevaluateResult reports the outcome of a forced re-evaluation of an invocation.
+type ReevaluateResult struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// Found is true if the invocation exists.
+	Found bool `protobuf:"varint,2,opt,name=found" json:"found,omitempty"`
+	// Enqueued is true if an evaluation of the invocation was added to the queue of the invocation controller.
+	Enqueued bool `protobuf:"varint,3,opt,name=enqueued" json:"enqueued,omitempty"`
+}
+
+func (m *ReevaluateResult) Reset()                    { *m = ReevaluateResult{} }
+func (m *ReevaluateResult) String() string            { return proto.CompactTextString(m) }
+func (*ReevaluateResult) ProtoMessage()               {}
+func (*ReevaluateResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *ReevaluateResult) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReevaluateResult) GetFound() bool {
+	if m != nil {
+		return m.Found
+	}
+	return false
+}
+
+func (m *ReevaluateResult) GetEnqueued() bool {
+	if m != nil {
+		return m.Enqueued
+	}
+	return false
+}
+
+type FunctionSuspension struct {
+	// FnRef is the function reference, either as used in the workflow definitions or fully resolved.
+	FnRef string `protobuf:"bytes,1,opt,name=fnRef" json:"fnRef,omitempty"`
+}
+
+func (m *FunctionSuspension) Reset()                    { *m = FunctionSuspension{} }
+func (m *FunctionSuspension) String() string            { return proto.CompactTextString(m) }
+func (*FunctionSuspension) ProtoMessage()               {}
+func (*FunctionSuspension) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *FunctionSuspension) GetFnRef() string {
+	if m != nil {
+		return m.FnRef
+	}
+	return ""
+}
+
+type SuspendedFunction struct {
+	FnRef string `protobuf:"bytes,1,opt,name=fnRef" json:"fnRef,omitempty"`
+	// Since is the time (RFC 3339) at which the function was suspended.
+	Since string `protobuf:"bytes,2,opt,name=since" json:"since,omitempty"`
+	// WaitingTasks is the number of tasks that are waiting for the function to be resumed.
+	WaitingTasks int32 `protobuf:"varint,3,opt,name=waitingTasks" json:"waitingTasks,omitempty"`
+}
+
+func (m *SuspendedFunction) Reset()                    { *m = SuspendedFunction{} }
+func (m *SuspendedFunction) String() string            { return proto.CompactTextString(m) }
+func (*SuspendedFunction) ProtoMessage()               {}
+func (*SuspendedFunction) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *SuspendedFunction) GetFnRef() string {
+	if m != nil {
+		return m.FnRef
+	}
+	return ""
+}
+
+func (m *SuspendedFunction) GetSince() string {
+	if m != nil {
+		return m.Since
+	}
+	return ""
+}
+
+func (m *SuspendedFunction) GetWaitingTasks() int32 {
+	if m != nil {
+		return m.WaitingTasks
+	}
+	return 0
+}
+
+type SuspendedFunctionList struct {
+	Functions []*SuspendedFunction `protobuf:"bytes,1,rep,name=functions" json:"functions,omitempty"`
+}
+
+func (m *SuspendedFunctionList) Reset()                    { *m = SuspendedFunctionList{} }
+func (m *SuspendedFunctionList) String() string            { return proto.CompactTextString(m) }
+func (*SuspendedFunctionList) ProtoMessage()               {}
+func (*SuspendedFunctionList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *SuspendedFunctionList) GetFunctions() []*SuspendedFunction {
+	if m != nil {
+		return m.Functions
+	}
+	return nil
+}
+
+type ConcurrencyKey struct {
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflowId" json:"workflowId,omitempty"`
+	// Key is the evaluated concurrency key.
+	Key string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *ConcurrencyKey) Reset()                    { *m = ConcurrencyKey{} }
+func (m *ConcurrencyKey) String() string            { return proto.CompactTextString(m) }
+func (*ConcurrencyKey) ProtoMessage()               {}
+func (*ConcurrencyKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *ConcurrencyKey) GetWorkflowId() string {
+	if m != nil {
+		return m.WorkflowId
+	}
+	return ""
+}
+
+func (m *ConcurrencyKey) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type ConcurrencyLock struct {
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflowId" json:"workflowId,omitempty"`
+	Key        string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+	// Holder is the ID of the invocation that holds the key, if any.
+	Holder string `protobuf:"bytes,3,opt,name=holder" json:"holder,omitempty"`
+	// Queued contains the IDs of the invocations that are waiting for the key, in order.
+	Queued []string `protobuf:"bytes,4,rep,name=queued" json:"queued,omitempty"`
+}
+
+func (m *ConcurrencyLock) Reset()                    { *m = ConcurrencyLock{} }
+func (m *ConcurrencyLock) String() string            { return proto.CompactTextString(m) }
+func (*ConcurrencyLock) ProtoMessage()               {}
+func (*ConcurrencyLock) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *ConcurrencyLock) GetWorkflowId() string {
+	if m != nil {
+		return m.WorkflowId
+	}
+	return ""
+}
+
+func (m *ConcurrencyLock) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ConcurrencyLock) GetHolder() string {
+	if m != nil {
+		return m.Holder
+	}
+	return ""
+}
+
+func (m *ConcurrencyLock) GetQueued() []string {
+	if m != nil {
+		return m.Queued
+	}
+	return nil
+}
+
+// EvaluationStats contains the evaluation statistics of an invocation, as recorded by the invocation controller.
+type EvaluationStats struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// EvalCount is the number of evaluations of the invocation.
+	EvalCount int64 `protobuf:"varint,2,opt,name=evalCount" json:"evalCount,omitempty"`
+	// LastEvaluatedAt is the time (RFC 3339) at which the invocation was last evaluated.
+	LastEvaluatedAt string `protobuf:"bytes,3,opt,name=lastEvaluatedAt" json:"lastEvaluatedAt,omitempty"`
+	// LastQueueWaitSeconds is the time that the last evaluation waited in the evaluation queue.
+	LastQueueWaitSeconds float64 `protobuf:"fixed64,4,opt,name=lastQueueWaitSeconds" json:"lastQueueWaitSeconds,omitempty"`
+	// TotalQueueWaitSeconds is the time that all evaluations of the invocation waited in the evaluation queue.
+	TotalQueueWaitSeconds float64 `protobuf:"fixed64,5,opt,name=totalQueueWaitSeconds" json:"totalQueueWaitSeconds,omitempty"`
+	// TraceSampled indicates whether the trace of the invocation is recorded.
+	TraceSampled bool `protobuf:"varint,6,opt,name=traceSampled" json:"traceSampled,omitempty"`
+	// TraceId is the ID of the trace of the invocation, if it is sampled.
+	TraceId string `protobuf:"bytes,7,opt,name=traceId" json:"traceId,omitempty"`
+	// TraceSamplingReason is the reason of the sampling decision: debug, label or rate.
+	TraceSamplingReason string `protobuf:"bytes,8,opt,name=traceSamplingReason" json:"traceSamplingReason,omitempty"`
+}
+
+func (m *EvaluationStats) Reset()                    { *m = EvaluationStats{} }
+func (m *EvaluationStats) String() string            { return proto.CompactTextString(m) }
+func (*EvaluationStats) ProtoMessage()               {}
+func (*EvaluationStats) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *EvaluationStats) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *EvaluationStats) GetEvalCount() int64 {
+	if m != nil {
+		return m.EvalCount
+	}
+	return 0
+}
+
+func (m *EvaluationStats) GetLastEvaluatedAt() string {
+	if m != nil {
+		return m.LastEvaluatedAt
+	}
+	return ""
+}
+
+func (m *EvaluationStats) GetLastQueueWaitSeconds() float64 {
+	if m != nil {
+		return m.LastQueueWaitSeconds
+	}
+	return 0
+}
+
+func (m *EvaluationStats) GetTotalQueueWaitSeconds() float64 {
+	if m != nil {
+		return m.TotalQueueWaitSeconds
+	}
+	return 0
+}
+
+func (m *EvaluationStats) GetTraceSampled() bool {
+	if m != nil {
+		return m.TraceSampled
+	}
+	return false
+}
+
+func (m *EvaluationStats) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+func (m *EvaluationStats) GetTraceSamplingReason() string {
+	if m != nil {
+		return m.TraceSamplingReason
+	}
+	return ""
+}
+
+// RedactedOutputRequest identifies the task of which to retrieve the redacted output values.
+type RedactedOutputRequest struct {
+	Id     string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	TaskId string `protobuf:"bytes,2,opt,name=taskId" json:"taskId,omitempty"`
+}
+
+func (m *RedactedOutputRequest) Reset()                    { *m = RedactedOutputRequest{} }
+func (m *RedactedOutputRequest) String() string            { return proto.CompactTextString(m) }
+func (*RedactedOutputRequest) ProtoMessage()               {}
+func (*RedactedOutputRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *RedactedOutputRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RedactedOutputRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+// RedactedField contains the original value of a redacted output field.
+type RedactedField struct {
+	// Path is the path of the redacted field in the output of the task.
+	Path string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	// Value is the JSON-encoded original value of the field.
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *RedactedField) Reset()                    { *m = RedactedField{} }
+func (m *RedactedField) String() string            { return proto.CompactTextString(m) }
+func (*RedactedField) ProtoMessage()               {}
+func (*RedactedField) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *RedactedField) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *RedactedField) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// RedactedOutput contains the original values of the redacted output fields of a task.
+type RedactedOutput struct {
+	Id     string           `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	TaskId string           `protobuf:"bytes,2,opt,name=taskId" json:"taskId,omitempty"`
+	Fields []*RedactedField `protobuf:"bytes,3,rep,name=fields" json:"fields,omitempty"`
+}
+
+func (m *RedactedOutput) Reset()                    { *m = RedactedOutput{} }
+func (m *RedactedOutput) String() string            { return proto.CompactTextString(m) }
+func (*RedactedOutput) ProtoMessage()               {}
+func (*RedactedOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *RedactedOutput) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RedactedOutput) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *RedactedOutput) GetFields() []*RedactedField {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+// WorkflowMetricsConfig configures which workflows have their own series in the per-workflow metrics; the metrics of
+// the other workflows are rolled up into the 'other' series.
+type WorkflowMetricsConfig struct {
+	// Workflows contains the IDs of the workflows that have opted in to per-workflow metrics.
+	Workflows []string `protobuf:"bytes,1,rep,name=workflows" json:"workflows,omitempty"`
+	// Threshold is the number of finished invocations after which a workflow gets its own series (0 = disabled).
+	Threshold int32 `protobuf:"varint,2,opt,name=threshold" json:"threshold,omitempty"`
+	// Limit is the maximum number of workflows that get their own series by exceeding the threshold (0 = unlimited).
+	Limit int32 `protobuf:"varint,3,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *WorkflowMetricsConfig) Reset()                    { *m = WorkflowMetricsConfig{} }
+func (m *WorkflowMetricsConfig) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowMetricsConfig) ProtoMessage()               {}
+func (*WorkflowMetricsConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *WorkflowMetricsConfig) GetWorkflows() []string {
+	if m != nil {
+		return m.Workflows
+	}
+	return nil
+}
+
+func (m *WorkflowMetricsConfig) GetThreshold() int32 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *WorkflowMetricsConfig) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// HandoffStatus describes whether the invocation controller owns the invocations.
+type HandoffStatus struct {
+	// State is either active, standby, draining or released.
+	State string `protobuf:"bytes,1,opt,name=state" json:"state,omitempty"`
+	// HandoffId identifies the last handoff that the controller took part in, if any.
+	HandoffId string `protobuf:"bytes,2,opt,name=handoffId" json:"handoffId,omitempty"`
+	// Epoch is incremented by every handoff.
+	Epoch int64 `protobuf:"varint,3,opt,name=epoch" json:"epoch,omitempty"`
+}
+
+func (m *HandoffStatus) Reset()                    { *m = HandoffStatus{} }
+func (m *HandoffStatus) String() string            { return proto.CompactTextString(m) }
+func (*HandoffStatus) ProtoMessage()               {}
+func (*HandoffStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *HandoffStatus) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *HandoffStatus) GetHandoffId() string {
+	if m != nil {
+		return m.HandoffId
+	}
+	return ""
+}
+
+func (m *HandoffStatus) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+type HandoffRequest struct {
+	// DrainTimeoutSeconds is the maximum time to wait for the in-flight tasks to finish. If 0, a default of 30
+	// seconds is used.
+	DrainTimeoutSeconds float64 `protobuf:"fixed64,1,opt,name=drainTimeoutSeconds" json:"drainTimeoutSeconds,omitempty"`
+}
+
+func (m *HandoffRequest) Reset()                    { *m = HandoffRequest{} }
+func (m *HandoffRequest) String() string            { return proto.CompactTextString(m) }
+func (*HandoffRequest) ProtoMessage()               {}
+func (*HandoffRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *HandoffRequest) GetDrainTimeoutSeconds() float64 {
+	if m != nil {
+		return m.DrainTimeoutSeconds
+	}
+	return 0
+}
+
+// ControllerSnapshot is the live state of an invocation controller, which is handed off to another controller
+// instance.
+type ControllerSnapshot struct {
+	HandoffId string `protobuf:"bytes,1,opt,name=handoffId" json:"handoffId,omitempty"`
+	Epoch     int64  `protobuf:"varint,2,opt,name=epoch" json:"epoch,omitempty"`
+	// CreatedAt is the time (RFC 3339) at which the snapshot was taken.
+	CreatedAt   string                       `protobuf:"bytes,3,opt,name=createdAt" json:"createdAt,omitempty"`
+	Invocations []*InvocationControllerState `protobuf:"bytes,4,rep,name=invocations" json:"invocations,omitempty"`
+	// Queued contains the IDs of the invocations of which an evaluation was pending, in order.
+	Queued []string           `protobuf:"bytes,5,rep,name=queued" json:"queued,omitempty"`
+	Locks  []*ConcurrencyLock `protobuf:"bytes,6,rep,name=locks" json:"locks,omitempty"`
+}
+
+func (m *ControllerSnapshot) Reset()                    { *m = ControllerSnapshot{} }
+func (m *ControllerSnapshot) String() string            { return proto.CompactTextString(m) }
+func (*ControllerSnapshot) ProtoMessage()               {}
+func (*ControllerSnapshot) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *ControllerSnapshot) GetHandoffId() string {
+	if m != nil {
+		return m.HandoffId
+	}
+	return ""
+}
+
+func (m *ControllerSnapshot) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *ControllerSnapshot) GetCreatedAt() string {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return ""
+}
+
+func (m *ControllerSnapshot) GetInvocations() []*InvocationControllerState {
+	if m != nil {
+		return m.Invocations
+	}
+	return nil
+}
+
+func (m *ControllerSnapshot) GetQueued() []string {
+	if m != nil {
+		return m.Queued
+	}
+	return nil
+}
+
+func (m *ControllerSnapshot) GetLocks() []*ConcurrencyLock {
+	if m != nil {
+		return m.Locks
+	}
+	return nil
+}
+
+// InvocationControllerState is the state of the controller of a single invocation.
+type InvocationControllerState struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// StartedTasks contains the IDs of the tasks that were submitted by the controller.
+	StartedTasks        []string         `protobuf:"bytes,2,rep,name=startedTasks" json:"startedTasks,omitempty"`
+	ErrorCount          int32            `protobuf:"varint,3,opt,name=errorCount" json:"errorCount,omitempty"`
+	TaskErrors          map[string]int32 `protobuf:"bytes,4,rep,name=taskErrors" json:"taskErrors,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	NoopEvals           int32            `protobuf:"varint,5,opt,name=noopEvals" json:"noopEvals,omitempty"`
+	Prewarmed           bool             `protobuf:"varint,6,opt,name=prewarmed" json:"prewarmed,omitempty"`
+	CompletedEarly      bool             `protobuf:"varint,7,opt,name=completedEarly" json:"completedEarly,omitempty"`
+	SoftTimeoutExceeded bool             `protobuf:"varint,8,opt,name=softTimeoutExceeded" json:"softTimeoutExceeded,omitempty"`
+	// LockKey is the evaluated concurrency key of the invocation, if any.
+	LockKey string `protobuf:"bytes,9,opt,name=lockKey" json:"lockKey,omitempty"`
+	// PendingPolls contains the times (RFC 3339) at which the next polls of the sensors are due, by task ID.
+	PendingPolls          map[string]string `protobuf:"bytes,10,rep,name=pendingPolls" json:"pendingPolls,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	EvalCount             int64             `protobuf:"varint,11,opt,name=evalCount" json:"evalCount,omitempty"`
+	LastEvaluatedAt       string            `protobuf:"bytes,12,opt,name=lastEvaluatedAt" json:"lastEvaluatedAt,omitempty"`
+	LastQueueWaitSeconds  float64           `protobuf:"fixed64,13,opt,name=lastQueueWaitSeconds" json:"lastQueueWaitSeconds,omitempty"`
+	TotalQueueWaitSeconds float64           `protobuf:"fixed64,14,opt,name=totalQueueWaitSeconds" json:"totalQueueWaitSeconds,omitempty"`
+}
+
+func (m *InvocationControllerState) Reset()                    { *m = InvocationControllerState{} }
+func (m *InvocationControllerState) String() string            { return proto.CompactTextString(m) }
+func (*InvocationControllerState) ProtoMessage()               {}
+func (*InvocationControllerState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *InvocationControllerState) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *InvocationControllerState) GetStartedTasks() []string {
+	if m != nil {
+		return m.StartedTasks
+	}
+	return nil
+}
+
+func (m *InvocationControllerState) GetErrorCount() int32 {
+	if m != nil {
+		return m.ErrorCount
+	}
+	return 0
+}
+
+func (m *InvocationControllerState) GetTaskErrors() map[string]int32 {
+	if m != nil {
+		return m.TaskErrors
+	}
+	return nil
+}
+
+func (m *InvocationControllerState) GetNoopEvals() int32 {
+	if m != nil {
+		return m.NoopEvals
+	}
+	return 0
+}
+
+func (m *InvocationControllerState) GetPrewarmed() bool {
+	if m != nil {
+		return m.Prewarmed
+	}
+	return false
+}
+
+func (m *InvocationControllerState) GetCompletedEarly() bool {
+	if m != nil {
+		return m.CompletedEarly
+	}
+	return false
+}
+
+func (m *InvocationControllerState) GetSoftTimeoutExceeded() bool {
+	if m != nil {
+		return m.SoftTimeoutExceeded
+	}
+	return false
+}
+
+func (m *InvocationControllerState) GetLockKey() string {
+	if m != nil {
+		return m.LockKey
+	}
+	return ""
+}
+
+func (m *InvocationControllerState) GetPendingPolls() map[string]string {
+	if m != nil {
+		return m.PendingPolls
+	}
+	return nil
+}
+
+func (m *InvocationControllerState) GetEvalCount() int64 {
+	if m != nil {
+		return m.EvalCount
+	}
+	return 0
+}
+
+func (m *InvocationControllerState) GetLastEvaluatedAt() string {
+	if m != nil {
+		return m.LastEvaluatedAt
+	}
+	return ""
+}
+
+func (m *InvocationControllerState) GetLastQueueWaitSeconds() float64 {
+	if m != nil {
+		return m.LastQueueWaitSeconds
+	}
+	return 0
+}
+
+func (m *InvocationControllerState) GetTotalQueueWaitSeconds() float64 {
+	if m != nil {
+		return m.TotalQueueWaitSeconds
+	}
+	return 0
+}
+
+type HandoffReclaim struct {
+	// HandoffId is the ID of the handoff to revert. It is not needed to take over the invocations in standby.
+	HandoffId string `protobuf:"bytes,1,opt,name=handoffId" json:"handoffId,omitempty"`
+}
+
+func (m *HandoffReclaim) Reset()                    { *m = HandoffReclaim{} }
+func (m *HandoffReclaim) String() string            { return proto.CompactTextString(m) }
+func (*HandoffReclaim) ProtoMessage()               {}
+func (*HandoffReclaim) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *HandoffReclaim) GetHandoffId() string {
+	if m != nil {
+		return m.HandoffId
+	}
+	return ""
+}
+
+type InvocationHierarchy struct {
+	Id     string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Status string `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+	// ParentTaskId is the task of the parent invocation that started the invocation, if it is a sub-workflow
+	// invocation.
+	ParentTaskId string `protobuf:"bytes,3,opt,name=parentTaskId" json:"parentTaskId,omitempty"`
+	// CancelPropagation is the cancel propagation mode of the parent task: cascade or detach.
+	CancelPropagation string `protobuf:"bytes,4,opt,name=cancelPropagation" json:"cancelPropagation,omitempty"`
+	// CancelDecision is the decision to propagate the cancellation of the parent to the invocation, if the parent
+	// has been canceled.
+	CancelDecision *CancelDecision        `protobuf:"bytes,5,opt,name=cancelDecision" json:"cancelDecision,omitempty"`
+	Children       []*InvocationHierarchy `protobuf:"bytes,6,rep,name=children" json:"children,omitempty"`
+}
+
+func (m *InvocationHierarchy) Reset()                    { *m = InvocationHierarchy{} }
+func (m *InvocationHierarchy) String() string            { return proto.CompactTextString(m) }
+func (*InvocationHierarchy) ProtoMessage()               {}
+func (*InvocationHierarchy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *InvocationHierarchy) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *InvocationHierarchy) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *InvocationHierarchy) GetParentTaskId() string {
+	if m != nil {
+		return m.ParentTaskId
+	}
+	return ""
+}
+
+func (m *InvocationHierarchy) GetCancelPropagation() string {
+	if m != nil {
+		return m.CancelPropagation
+	}
+	return ""
+}
+
+func (m *InvocationHierarchy) GetCancelDecision() *CancelDecision {
+	if m != nil {
+		return m.CancelDecision
+	}
+	return nil
+}
+
+func (m *InvocationHierarchy) GetChildren() []*InvocationHierarchy {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+type CancelDecision struct {
+	ParentId     string `protobuf:"bytes,1,opt,name=parentId" json:"parentId,omitempty"`
+	ParentTaskId string `protobuf:"bytes,2,opt,name=parentTaskId" json:"parentTaskId,omitempty"`
+	Mode         string `protobuf:"bytes,3,opt,name=mode" json:"mode,omitempty"`
+	// Canceled indicates that the invocation was canceled along with its parent.
+	Canceled bool `protobuf:"varint,4,opt,name=canceled" json:"canceled,omitempty"`
+	// Error is the error that prevented the invocation from being canceled, if any.
+	Error string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+	// DecidedAt is the time of the decision, formatted as RFC 3339.
+	DecidedAt string `protobuf:"bytes,6,opt,name=decidedAt" json:"decidedAt,omitempty"`
+}
+
+func (m *CancelDecision) Reset()                    { *m = CancelDecision{} }
+func (m *CancelDecision) String() string            { return proto.CompactTextString(m) }
+func (*CancelDecision) ProtoMessage()               {}
+func (*CancelDecision) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *CancelDecision) GetParentId() string {
+	if m != nil {
+		return m.ParentId
+	}
+	return ""
+}
+
+func (m *CancelDecision) GetParentTaskId() string {
+	if m != nil {
+		return m.ParentTaskId
+	}
+	return ""
+}
+
+func (m *CancelDecision) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *CancelDecision) GetCanceled() bool {
+	if m != nil {
+		return m.Canceled
+	}
+	return false
+}
+
+func (m *CancelDecision) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *CancelDecision) GetDecidedAt() string {
+	if m != nil {
+		return m.DecidedAt
+	}
+	return ""
+}
+
+// WorkflowDashboard is a generated Grafana dashboard of the per-workflow metrics of a workflow.
+type WorkflowDashboard struct {
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflowId" json:"workflowId,omitempty"`
+	// Dashboard is the Grafana dashboard, encoded as JSON.
+	Dashboard string `protobuf:"bytes,2,opt,name=dashboard" json:"dashboard,omitempty"`
+}
+
+func (m *WorkflowDashboard) Reset()                    { *m = WorkflowDashboard{} }
+func (m *WorkflowDashboard) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowDashboard) ProtoMessage()               {}
+func (*WorkflowDashboard) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *WorkflowDashboard) GetWorkflowId() string {
+	if m != nil {
+		return m.WorkflowId
+	}
+	return ""
+}
+
+func (m *WorkflowDashboard) GetDashboard() string {
+	if m != nil {
+		return m.Dashboard
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*WorkflowList)(nil), "fission.workflows.apiserver.WorkflowList")
+	proto.RegisterType((*WorkflowDiffRequest)(nil), "fission.workflows.apiserver.WorkflowDiffRequest")
+	proto.RegisterType((*WorkflowDiff)(nil), "fission.workflows.apiserver.WorkflowDiff")
+	proto.RegisterType((*TaskDiff)(nil), "fission.workflows.apiserver.TaskDiff")
+	proto.RegisterType((*AddTaskRequest)(nil), "fission.workflows.apiserver.AddTaskRequest")
+	proto.RegisterType((*ReplayFailedTasksRequest)(nil), "fission.workflows.apiserver.ReplayFailedTasksRequest")
+	proto.RegisterType((*ReplayFailedTasksResponse)(nil), "fission.workflows.apiserver.ReplayFailedTasksResponse")
+	proto.RegisterType((*InvocationPurgeRequest)(nil), "fission.workflows.apiserver.InvocationPurgeRequest")
+	proto.RegisterType((*InvocationPurgeResult)(nil), "fission.workflows.apiserver.InvocationPurgeResult")
+	proto.RegisterType((*InvocationArchiveRequest)(nil), "fission.workflows.apiserver.InvocationArchiveRequest")
+	proto.RegisterType((*InvocationArchiveResult)(nil), "fission.workflows.apiserver.InvocationArchiveResult")
+	proto.RegisterType((*InvocationListQuery)(nil), "fission.workflows.apiserver.InvocationListQuery")
+	proto.RegisterType((*WorkflowInvocationList)(nil), "fission.workflows.apiserver.WorkflowInvocationList")
+	proto.RegisterType((*InvocationStatusQuery)(nil), "fission.workflows.apiserver.InvocationStatusQuery")
+	proto.RegisterType((*InvocationStatusList)(nil), "fission.workflows.apiserver.InvocationStatusList")
+	proto.RegisterType((*InvocationStatusResult)(nil), "fission.workflows.apiserver.InvocationStatusResult")
+	proto.RegisterType((*TaskRequest)(nil), "fission.workflows.apiserver.TaskRequest")
+	proto.RegisterType((*InvocationGroup)(nil), "fission.workflows.apiserver.InvocationGroup")
+	proto.RegisterType((*InvocationTimeline)(nil), "fission.workflows.apiserver.InvocationTimeline")
+	proto.RegisterType((*TaskTiming)(nil), "fission.workflows.apiserver.TaskTiming")
+	proto.RegisterType((*ObjectEvents)(nil), "fission.workflows.apiserver.ObjectEvents")
+	proto.RegisterType((*Health)(nil), "fission.workflows.apiserver.Health")
+	proto.RegisterType((*ExpressionState)(nil), "fission.workflows.apiserver.ExpressionState")
+	proto.RegisterType((*ReevaluateResult)(nil), "fission.workflows.apiserver.ReevaluateResult")
+	proto.RegisterType((*FunctionSuspension)(nil), "fission.workflows.apiserver.FunctionSuspension")
+	proto.RegisterType((*SuspendedFunction)(nil), "fission.workflows.apiserver.SuspendedFunction")
+	proto.RegisterType((*SuspendedFunctionList)(nil), "fission.workflows.apiserver.SuspendedFunctionList")
+	proto.RegisterType((*ConcurrencyKey)(nil), "fission.workflows.apiserver.ConcurrencyKey")
+	proto.RegisterType((*ConcurrencyLock)(nil), "fission.workflows.apiserver.ConcurrencyLock")
+	proto.RegisterType((*EvaluationStats)(nil), "fission.workflows.apiserver.EvaluationStats")
+	proto.RegisterType((*RedactedOutputRequest)(nil), "fission.workflows.apiserver.RedactedOutputRequest")
+	proto.RegisterType((*RedactedField)(nil), "fission.workflows.apiserver.RedactedField")
+	proto.RegisterType((*RedactedOutput)(nil), "fission.workflows.apiserver.RedactedOutput")
+	proto.RegisterType((*WorkflowMetricsConfig)(nil), "fission.workflows.apiserver.WorkflowMetricsConfig")
+	proto.RegisterType((*HandoffStatus)(nil), "fission.workflows.apiserver.HandoffStatus")
+	proto.RegisterType((*HandoffRequest)(nil), "fission.workflows.apiserver.HandoffRequest")
+	proto.RegisterType((*ControllerSnapshot)(nil), "fission.workflows.apiserver.ControllerSnapshot")
+	proto.RegisterType((*InvocationControllerState)(nil), "fission.workflows.apiserver.InvocationControllerState")
+	proto.RegisterType((*HandoffReclaim)(nil), "fission.workflows.apiserver.HandoffReclaim")
+	proto.RegisterType((*InvocationHierarchy)(nil), "fission.workflows.apiserver.InvocationHierarchy")
+	proto.RegisterType((*CancelDecision)(nil), "fission.workflows.apiserver.CancelDecision")
+	proto.RegisterType((*WorkflowDashboard)(nil), "fission.workflows.apiserver.WorkflowDashboard")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for WorkflowAPI service
+
+type WorkflowAPIClient interface {
+	Create(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error)
+	CreateSync(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error)
+	List(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*WorkflowList, error)
+	Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error)
+	Delete(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	Validate(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error)
+	// Diff compares two versions of a workflow.
+	//
+	// A workflow is versioned by (re)creating it with the same (forced) ID, where each version is numbered
+	// sequentially starting from 1.
+	Diff(ctx context.Context, in *WorkflowDiffRequest, opts ...grpc.CallOption) (*WorkflowDiff, error)
+	// ClearQuarantine clears the quarantine of a workflow, which allows the workflow to be invoked again.
+	ClearQuarantine(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+}
+
+type workflowAPIClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWorkflowAPIClient(cc *grpc.ClientConn) WorkflowAPIClient {
+	return &workflowAPIClient{cc}
+}
+
+func (c *workflowAPIClient) Create(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error) {
+	out := new(fission_workflows_types1.ObjectMetadata)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Create", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) CreateSync(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error) {
+	out := new(fission_workflows_types1.Workflow)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/CreateSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) List(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*WorkflowList, error) {
+	out := new(WorkflowList)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error) {
+	out := new(fission_workflows_types1.Workflow)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Get", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) Delete(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Delete", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) Validate(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Validate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error) {
+	out := new(ObjectEvents)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Events", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) Diff(ctx context.Context, in *WorkflowDiffRequest, opts ...grpc.CallOption) (*WorkflowDiff, error) {
+	out := new(WorkflowDiff)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Diff", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) ClearQuarantine(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/ClearQuarantine", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for WorkflowAPI service
+
+type WorkflowAPIServer interface {
+	Create(context.Context, *fission_workflows_types1.WorkflowSpec) (*fission_workflows_types1.ObjectMetadata, error)
+	CreateSync(context.Context, *fission_workflows_types1.WorkflowSpec) (*fission_workflows_types1.Workflow, error)
+	List(context.Context, *google_protobuf3.Empty) (*WorkflowList, error)
+	Get(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_types1.Workflow, error)
+	Delete(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
+	Validate(context.Context, *fission_workflows_types1.WorkflowSpec) (*google_protobuf3.Empty, error)
+	Events(context.Context, *fission_workflows_types1.ObjectMetadata) (*ObjectEvents, error)
+	// Diff compares two versions of a workflow.
+	//
+	// A workflow is versioned by (re)creating it with the same (forced) ID, where each version is numbered
+	// sequentially starting from 1.
+	Diff(context.Context, *WorkflowDiffRequest) (*WorkflowDiff, error)
+	// ClearQuarantine clears the quarantine of a workflow, which allows the workflow to be invoked again.
+	ClearQuarantine(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
+}
+
+func RegisterWorkflowAPIServer(s *grpc.Server, srv WorkflowAPIServer) {
+	s.RegisterService(&_WorkflowAPI_serviceDesc, srv)
+}
+
+func _WorkflowAPI_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.WorkflowSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).Create(ctx, req.(*fission_workflows_types1.WorkflowSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowAPI_CreateSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.WorkflowSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).CreateSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/CreateSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).CreateSync(ctx, req.(*fission_workflows_types1.WorkflowSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowAPI_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).List(ctx, req.(*google_protobuf3.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowAPI_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).Get(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowAPI_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).Delete(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowAPI_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.WorkflowSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).Validate(ctx, req.(*fission_workflows_types1.WorkflowSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowAPI_Events_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).Events(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Events",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).Events(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowAPI_Diff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).Diff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Diff",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).Diff(ctx, req.(*WorkflowDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowAPI_ClearQuarantine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).ClearQuarantine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/ClearQuarantine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).ClearQuarantine(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WorkflowAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "fission.workflows.apiserver.WorkflowAPI",
+	HandlerType: (*WorkflowAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _WorkflowAPI_Create_Handler,
+		},
+		{
+			MethodName: "CreateSync",
+			Handler:    _WorkflowAPI_CreateSync_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _WorkflowAPI_List_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _WorkflowAPI_Get_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _WorkflowAPI_Delete_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _WorkflowAPI_Validate_Handler,
+		},
+		{
+			MethodName: "Events",
+			Handler:    _WorkflowAPI_Events_Handler,
+		},
+		{
+			MethodName: "Diff",
+			Handler:    _WorkflowAPI_Diff_Handler,
+		},
+		{
+			MethodName: "ClearQuarantine",
+			Handler:    _WorkflowAPI_ClearQuarantine_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/apiserver/apiserver.proto",
+}
+
+// Client API for WorkflowInvocationAPI service
+
+type WorkflowInvocationAPIClient interface {
+	// Create a new workflow invocation
+	//
+	// In case the invocation specification is missing fields or contains invalid fields, a HTTP 400 is returned.
+	Invoke(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error)
+	InvokeSync(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error)
+	AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// Cancel a workflow invocation
+	//
+	// This action is irreverisble. A canceled invocation cannot be resumed or restarted.
+	// In case that an invocation already is canceled, has failed or has completed, nothing happens.
+	// In case that an invocation does not exist a HTTP 404 error status is returned.
+	Cancel(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	List(ctx context.Context, in *InvocationListQuery, opts ...grpc.CallOption) (*WorkflowInvocationList, error)
+	// Get the specification and status of a workflow invocation
+	//
+	// Get returns three different aspects of the workflow invocation, namely the spec (specification), status and logs.
+	// To lighten the request load, consider using a more specific request.
+	Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error)
+	Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error)
+	Validate(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// Get the statuses of multiple workflow invocations
+	//
+	// GetStatuses returns a snapshot of the status of each requested invocation, in the order of the request.
+	// Invocations that could not be retrieved (e.g. because they do not exist) are reported in the error field of
+	// the corresponding result, rather than failing the entire request.
+	GetStatuses(ctx context.Context, in *InvocationStatusQuery, opts ...grpc.CallOption) (*InvocationStatusList, error)
+	// Get the members and progress of a group of workflow invocations
+	//
+	// In case that the group does not have any member invocations, a HTTP 404 error status is returned.
+	GetGroup(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*InvocationGroup, error)
+	// Cancel all unfinished workflow invocations in a group
+	CancelGroup(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// GetTimeline returns the timing breakdown of the tasks of the invocation, along with its critical path.
+	GetTimeline(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*InvocationTimeline, error)
+	// GetResolvedWorkflow returns the workflow definition that is effectively executed for the invocation.
+	//
+	// The definition includes the dynamically added tasks, and has the defaults and the settings inherited from the
+	// invocation applied, such as the resolved function references, the effective retry attempts and the timeouts
+	// that the tasks inherit from the invocation deadline.
+	GetResolvedWorkflow(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowSpec, error)
+	// ReplayFailedTasks executes the failed tasks of a finished invocation again, along with the tasks that depend on them.
+	//
+	// The outputs of the other tasks are preserved. Optionally, the replayed tasks are executed with other functions.
+	ReplayFailedTasks(ctx context.Context, in *ReplayFailedTasksRequest, opts ...grpc.CallOption) (*ReplayFailedTasksResponse, error)
+	// Purge deletes the events of the finished invocations that match the filter, along with their cache entries.
+	//
+	// Invocations that have not finished are never purged.
+	Purge(ctx context.Context, in *InvocationPurgeRequest, opts ...grpc.CallOption) (*InvocationPurgeResult, error)
+	// Get a task of a workflow invocation, including its full output
+	//
+	// GetTask returns the task as stored, regardless of its size, to complement the responses that truncate the
+	// outputs of tasks, such as GetStatuses.
+	GetTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*fission_workflows_types1.TaskInvocation, error)
+	// Archive moves the event logs of the finished invocations that match the filter to the cold storage, and removes
+	// them from the event store and the caches.
+	//
+	// The status of an archived invocation can still be retrieved, although slower. Invocations that have not
+	// finished are never archived.
+	Archive(ctx context.Context, in *InvocationArchiveRequest, opts ...grpc.CallOption) (*InvocationArchiveResult, error)
+	// Restore moves the event log of an archived invocation from the cold storage back to the event store.
+	Restore(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error)
+}
+
+type workflowInvocationAPIClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWorkflowInvocationAPIClient(cc *grpc.ClientConn) WorkflowInvocationAPIClient {
+	return &workflowInvocationAPIClient{cc}
+}
+
+func (c *workflowInvocationAPIClient) Invoke(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error) {
+	out := new(fission_workflows_types1.ObjectMetadata)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) InvokeSync(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error) {
+	out := new(fission_workflows_types1.WorkflowInvocation)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/AddTask", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) Cancel(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) List(ctx context.Context, in *InvocationListQuery, opts ...grpc.CallOption) (*WorkflowInvocationList, error) {
+	out := new(WorkflowInvocationList)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error) {
+	out := new(fission_workflows_types1.WorkflowInvocation)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Get", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error) {
+	out := new(ObjectEvents)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Events", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) Validate(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Validate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) GetStatuses(ctx context.Context, in *InvocationStatusQuery, opts ...grpc.CallOption) (*InvocationStatusList, error) {
+	out := new(InvocationStatusList)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/GetStatuses", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) GetGroup(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*InvocationGroup, error) {
+	out := new(InvocationGroup)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/GetGroup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) CancelGroup(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/CancelGroup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) GetTimeline(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*InvocationTimeline, error) {
+	out := new(InvocationTimeline)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/GetTimeline", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) GetResolvedWorkflow(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowSpec, error) {
+	out := new(fission_workflows_types1.WorkflowSpec)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/GetResolvedWorkflow", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) ReplayFailedTasks(ctx context.Context, in *ReplayFailedTasksRequest, opts ...grpc.CallOption) (*ReplayFailedTasksResponse, error) {
+	out := new(ReplayFailedTasksResponse)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/ReplayFailedTasks", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) Purge(ctx context.Context, in *InvocationPurgeRequest, opts ...grpc.CallOption) (*InvocationPurgeResult, error) {
+	out := new(InvocationPurgeResult)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Purge", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) GetTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*fission_workflows_types1.TaskInvocation, error) {
+	out := new(fission_workflows_types1.TaskInvocation)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/GetTask", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) Archive(ctx context.Context, in *InvocationArchiveRequest, opts ...grpc.CallOption) (*InvocationArchiveResult, error) {
+	out := new(InvocationArchiveResult)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Archive", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) Restore(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error) {
+	out := new(fission_workflows_types1.WorkflowInvocation)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Restore", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for WorkflowInvocationAPI service
+
+type WorkflowInvocationAPIServer interface {
+	// Create a new workflow invocation
+	//
+	// In case the invocation specification is missing fields or contains invalid fields, a HTTP 400 is returned.
+	Invoke(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*fission_workflows_types1.ObjectMetadata, error)
+	InvokeSync(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*fission_workflows_types1.WorkflowInvocation, error)
+	AddTask(context.Context, *AddTaskRequest) (*google_protobuf3.Empty, error)
+	// Cancel a workflow invocation
+	//
+	// This action is irreverisble. A canceled invocation cannot be resumed or restarted.
+	// In case that an invocation already is canceled, has failed or has completed, nothing happens.
+	// In case that an invocation does not exist a HTTP 404 error status is returned.
+	Cancel(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
+	List(context.Context, *InvocationListQuery) (*WorkflowInvocationList, error)
+	// Get the specification and status of a workflow invocation
+	//
+	// Get returns three different aspects of the workflow invocation, namely the spec (specification), status and logs.
+	// To lighten the request load, consider using a more specific request.
+	Get(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_types1.WorkflowInvocation, error)
+	Events(context.Context, *fission_workflows_types1.ObjectMetadata) (*ObjectEvents, error)
+	Validate(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*google_protobuf3.Empty, error)
+	// Get the statuses of multiple workflow invocations
+	//
+	// GetStatuses returns a snapshot of the status of each requested invocation, in the order of the request.
+	// Invocations that could not be retrieved (e.g. because they do not exist) are reported in the error field of
+	// the corresponding result, rather than failing the entire request.
+	GetStatuses(context.Context, *InvocationStatusQuery) (*InvocationStatusList, error)
+	// Get the members and progress of a group of workflow invocations
+	//
+	// In case that the group does not have any member invocations, a HTTP 404 error status is returned.
+	GetGroup(context.Context, *fission_workflows_types1.ObjectMetadata) (*InvocationGroup, error)
+	// Cancel all unfinished workflow invocations in a group
+	CancelGroup(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
+	// GetTimeline returns the timing breakdown of the tasks of the invocation, along with its critical path.
+	GetTimeline(context.Context, *fission_workflows_types1.ObjectMetadata) (*InvocationTimeline, error)
+	// GetResolvedWorkflow returns the workflow definition that is effectively executed for the invocation.
+	//
+	// The definition includes the dynamically added tasks, and has the defaults and the settings inherited from the
+	// invocation applied, such as the resolved function references, the effective retry attempts and the timeouts
+	// that the tasks inherit from the invocation deadline.
+	GetResolvedWorkflow(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_types1.WorkflowSpec, error)
+	// ReplayFailedTasks executes the failed tasks of a finished invocation again, along with the tasks that depend on them.
+	//
+	// The outputs of the other tasks are preserved. Optionally, the replayed tasks are executed with other functions.
+	ReplayFailedTasks(context.Context, *ReplayFailedTasksRequest) (*ReplayFailedTasksResponse, error)
+	// Purge deletes the events of the finished invocations that match the filter, along with their cache entries.
+	//
+	// Invocations that have not finished are never purged.
+	Purge(context.Context, *InvocationPurgeRequest) (*InvocationPurgeResult, error)
+	// Get a task of a workflow invocation, including its full output
+	//
+	// GetTask returns the task as stored, regardless of its size, to complement the responses that truncate the
+	// outputs of tasks, such as GetStatuses.
+	GetTask(context.Context, *TaskRequest) (*fission_workflows_types1.TaskInvocation, error)
+	// Archive moves the event logs of the finished invocations that match the filter to the cold storage, and removes
+	// them from the event store and the caches.
+	//
+	// The status of an archived invocation can still be retrieved, although slower. Invocations that have not
+	// finished are never archived.
+	Archive(context.Context, *InvocationArchiveRequest) (*InvocationArchiveResult, error)
+	// Restore moves the event log of an archived invocation from the cold storage back to the event store.
+	Restore(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_types1.WorkflowInvocation, error)
+}
+
+func RegisterWorkflowInvocationAPIServer(s *grpc.Server, srv WorkflowInvocationAPIServer) {
+	s.RegisterService(&_WorkflowInvocationAPI_serviceDesc, srv)
+}
+
+func _WorkflowInvocationAPI_Invoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.WorkflowInvocationSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).Invoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).Invoke(ctx, req.(*fission_workflows_types1.WorkflowInvocationSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_InvokeSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.WorkflowInvocationSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).InvokeSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).InvokeSync(ctx, req.(*fission_workflows_types1.WorkflowInvocationSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_AddTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).AddTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/AddTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).AddTask(ctx, req.(*AddTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).Cancel(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvocationListQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).List(ctx, req.(*InvocationListQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).Get(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_Events_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).Events(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Events",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).Events(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.WorkflowInvocationSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).Validate(ctx, req.(*fission_workflows_types1.WorkflowInvocationSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_GetStatuses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvocationStatusQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).GetStatuses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/GetStatuses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).GetStatuses(ctx, req.(*InvocationStatusQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/GetGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).GetGroup(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_CancelGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).CancelGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/CancelGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).CancelGroup(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowAPI_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.WorkflowSpec)
+func _WorkflowInvocationAPI_GetTimeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowAPIServer).Create(ctx, in)
+		return srv.(WorkflowInvocationAPIServer).GetTimeline(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Create",
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/GetTimeline",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowAPIServer).Create(ctx, req.(*fission_workflows_types1.WorkflowSpec))
+		return srv.(WorkflowInvocationAPIServer).GetTimeline(ctx, req.(*fission_workflows_types1.ObjectMetadata))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowAPI_CreateSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.WorkflowSpec)
+func _WorkflowInvocationAPI_GetResolvedWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowAPIServer).CreateSync(ctx, in)
+		return srv.(WorkflowInvocationAPIServer).GetResolvedWorkflow(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/CreateSync",
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/GetResolvedWorkflow",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowAPIServer).CreateSync(ctx, req.(*fission_workflows_types1.WorkflowSpec))
+		return srv.(WorkflowInvocationAPIServer).GetResolvedWorkflow(ctx, req.(*fission_workflows_types1.ObjectMetadata))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowAPI_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(google_protobuf3.Empty)
+func _WorkflowInvocationAPI_ReplayFailedTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayFailedTasksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowAPIServer).List(ctx, in)
+		return srv.(WorkflowInvocationAPIServer).ReplayFailedTasks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/List",
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/ReplayFailedTasks",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowAPIServer).List(ctx, req.(*google_protobuf3.Empty))
+		return srv.(WorkflowInvocationAPIServer).ReplayFailedTasks(ctx, req.(*ReplayFailedTasksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowAPI_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.ObjectMetadata)
+func _WorkflowInvocationAPI_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvocationPurgeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowAPIServer).Get(ctx, in)
+		return srv.(WorkflowInvocationAPIServer).Purge(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Get",
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Purge",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowAPIServer).Get(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+		return srv.(WorkflowInvocationAPIServer).Purge(ctx, req.(*InvocationPurgeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowAPI_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.ObjectMetadata)
+func _WorkflowInvocationAPI_GetTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowAPIServer).Delete(ctx, in)
+		return srv.(WorkflowInvocationAPIServer).GetTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Delete",
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/GetTask",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowAPIServer).Delete(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+		return srv.(WorkflowInvocationAPIServer).GetTask(ctx, req.(*TaskRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowAPI_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.WorkflowSpec)
+func _WorkflowInvocationAPI_Archive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvocationArchiveRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowAPIServer).Validate(ctx, in)
+		return srv.(WorkflowInvocationAPIServer).Archive(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Validate",
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Archive",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowAPIServer).Validate(ctx, req.(*fission_workflows_types1.WorkflowSpec))
+		return srv.(WorkflowInvocationAPIServer).Archive(ctx, req.(*InvocationArchiveRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowAPI_Events_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _WorkflowInvocationAPI_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowAPIServer).Events(ctx, in)
+		return srv.(WorkflowInvocationAPIServer).Restore(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Events",
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Restore",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowAPIServer).Events(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+		return srv.(WorkflowInvocationAPIServer).Restore(ctx, req.(*fission_workflows_types1.ObjectMetadata))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _WorkflowAPI_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "fission.workflows.apiserver.WorkflowAPI",
-	HandlerType: (*WorkflowAPIServer)(nil),
+var _WorkflowInvocationAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "fission.workflows.apiserver.WorkflowInvocationAPI",
+	HandlerType: (*WorkflowInvocationAPIServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Create",
-			Handler:    _WorkflowAPI_Create_Handler,
+			MethodName: "Invoke",
+			Handler:    _WorkflowInvocationAPI_Invoke_Handler,
 		},
 		{
-			MethodName: "CreateSync",
-			Handler:    _WorkflowAPI_CreateSync_Handler,
+			MethodName: "InvokeSync",
+			Handler:    _WorkflowInvocationAPI_InvokeSync_Handler,
+		},
+		{
+			MethodName: "AddTask",
+			Handler:    _WorkflowInvocationAPI_AddTask_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _WorkflowInvocationAPI_Cancel_Handler,
 		},
 		{
 			MethodName: "List",
-			Handler:    _WorkflowAPI_List_Handler,
+			Handler:    _WorkflowInvocationAPI_List_Handler,
 		},
 		{
 			MethodName: "Get",
-			Handler:    _WorkflowAPI_Get_Handler,
+			Handler:    _WorkflowInvocationAPI_Get_Handler,
 		},
 		{
-			MethodName: "Delete",
-			Handler:    _WorkflowAPI_Delete_Handler,
+			MethodName: "Events",
+			Handler:    _WorkflowInvocationAPI_Events_Handler,
 		},
 		{
 			MethodName: "Validate",
-			Handler:    _WorkflowAPI_Validate_Handler,
+			Handler:    _WorkflowInvocationAPI_Validate_Handler,
 		},
 		{
-			MethodName: "Events",
-			Handler:    _WorkflowAPI_Events_Handler,
+			MethodName: "GetStatuses",
+			Handler:    _WorkflowInvocationAPI_GetStatuses_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _WorkflowInvocationAPI_GetGroup_Handler,
+		},
+		{
+			MethodName: "CancelGroup",
+			Handler:    _WorkflowInvocationAPI_CancelGroup_Handler,
+		},
+		{
+			MethodName: "GetTimeline",
+			Handler:    _WorkflowInvocationAPI_GetTimeline_Handler,
+		},
+		{
+			MethodName: "GetResolvedWorkflow",
+			Handler:    _WorkflowInvocationAPI_GetResolvedWorkflow_Handler,
+		},
+		{
+			MethodName: "ReplayFailedTasks",
+			Handler:    _WorkflowInvocationAPI_ReplayFailedTasks_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _WorkflowInvocationAPI_Purge_Handler,
+		},
+		{
+			MethodName: "GetTask",
+			Handler:    _WorkflowInvocationAPI_GetTask_Handler,
+		},
+		{
+			MethodName: "Archive",
+			Handler:    _WorkflowInvocationAPI_Archive_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _WorkflowInvocationAPI_Restore_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/apiserver/apiserver.proto",
 }
 
-// Client API for WorkflowInvocationAPI service
+// Client API for AdminAPI service
 
-type WorkflowInvocationAPIClient interface {
-	// Create a new workflow invocation
-	//
-	// In case the invocation specification is missing fields or contains invalid fields, a HTTP 400 is returned.
-	Invoke(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error)
-	InvokeSync(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error)
-	AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
-	// Cancel a workflow invocation
-	//
-	// This action is irreverisble. A canceled invocation cannot be resumed or restarted.
-	// In case that an invocation already is canceled, has failed or has completed, nothing happens.
-	// In case that an invocation does not exist a HTTP 404 error status is returned.
-	Cancel(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
-	List(ctx context.Context, in *InvocationListQuery, opts ...grpc.CallOption) (*WorkflowInvocationList, error)
-	// Get the specification and status of a workflow invocation
-	//
-	// Get returns three different aspects of the workflow invocation, namely the spec (specification), status and logs.
-	// To lighten the request load, consider using a more specific request.
-	Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error)
-	Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error)
-	Validate(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+type AdminAPIClient interface {
+	Status(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Health, error)
+	Version(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*fission_workflows_version.Info, error)
+	// GetExpressionState returns the cached expression state of an invocation.
+	GetExpressionState(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ExpressionState, error)
+	// ClearExpressionState clears the cached expression state of an invocation, forcing it to be re-derived from
+	// the invocation on the next evaluation.
+	ClearExpressionState(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// Reevaluate forces the invocation controller to evaluate an invocation immediately, rather than waiting for
+	// the next notification or poll.
+	Reevaluate(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ReevaluateResult, error)
+	// SuspendFunction suspends the scheduling of tasks that reference the function, for example during planned
+	// maintenance. The tasks wait until the function is resumed, or until their invocation exceeds its deadline.
+	SuspendFunction(ctx context.Context, in *FunctionSuspension, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// ResumeFunction resumes the scheduling of tasks that reference the function.
+	ResumeFunction(ctx context.Context, in *FunctionSuspension, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// ListSuspendedFunctions lists the suspended functions, along with the number of tasks waiting on each.
+	ListSuspendedFunctions(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*SuspendedFunctionList, error)
+	// GetConcurrencyLock returns the invocation that holds the concurrency key of a workflow, and the invocations
+	// that are queued for it.
+	GetConcurrencyLock(ctx context.Context, in *ConcurrencyKey, opts ...grpc.CallOption) (*ConcurrencyLock, error)
+	// GetEvaluationStats returns the evaluation statistics of an invocation, such as the time that its evaluations
+	// waited in the evaluation queue of the invocation controller.
+	GetEvaluationStats(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*EvaluationStats, error)
+	// GetRedactedOutput returns the original values of the redacted output fields of a task, if they have been kept.
+	GetRedactedOutput(ctx context.Context, in *RedactedOutputRequest, opts ...grpc.CallOption) (*RedactedOutput, error)
+	// GetWorkflowMetrics returns which workflows have their own series in the per-workflow metrics.
+	GetWorkflowMetrics(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*WorkflowMetricsConfig, error)
+	// SetWorkflowMetrics changes which workflows have their own series in the per-workflow metrics.
+	SetWorkflowMetrics(ctx context.Context, in *WorkflowMetricsConfig, opts ...grpc.CallOption) (*WorkflowMetricsConfig, error)
+	// GetHandoffStatus returns whether the invocation controller owns the invocations, or has handed them off.
+	GetHandoffStatus(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*HandoffStatus, error)
+	// ExportControllerState drains the invocation controller, and releases its invocations. It returns the state of
+	// the controller, to be imported by the controller instance that takes over the invocations.
+	ExportControllerState(ctx context.Context, in *HandoffRequest, opts ...grpc.CallOption) (*ControllerSnapshot, error)
+	// ImportControllerState resumes the invocations of another controller instance in an invocation controller that
+	// is in standby.
+	ImportControllerState(ctx context.Context, in *ControllerSnapshot, opts ...grpc.CallOption) (*HandoffStatus, error)
+	// ReclaimControllerState makes the invocation controller the owner of the invocations again, after a failed
+	// handoff, or takes over the invocations in a controller that is in standby without importing a snapshot.
+	ReclaimControllerState(ctx context.Context, in *HandoffReclaim, opts ...grpc.CallOption) (*HandoffStatus, error)
+	// GetInvocationHierarchy returns an invocation along with the hierarchy of its sub-workflow invocations, and
+	// whether the cancellation of the invocations was propagated to their sub-workflow invocations.
+	GetInvocationHierarchy(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*InvocationHierarchy, error)
+	// GetWorkflowDashboard generates a Grafana dashboard of the per-workflow metrics of a workflow.
+	GetWorkflowDashboard(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*WorkflowDashboard, error)
 }
 
-type workflowInvocationAPIClient struct {
+type adminAPIClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewWorkflowInvocationAPIClient(cc *grpc.ClientConn) WorkflowInvocationAPIClient {
-	return &workflowInvocationAPIClient{cc}
+func NewAdminAPIClient(cc *grpc.ClientConn) AdminAPIClient {
+	return &adminAPIClient{cc}
 }
 
-func (c *workflowInvocationAPIClient) Invoke(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error) {
-	out := new(fission_workflows_types1.ObjectMetadata)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke", in, out, c.cc, opts...)
+func (c *adminAPIClient) Status(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Health, error) {
+	out := new(Health)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/Status", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *workflowInvocationAPIClient) InvokeSync(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error) {
-	out := new(fission_workflows_types1.WorkflowInvocation)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeSync", in, out, c.cc, opts...)
+func (c *adminAPIClient) Version(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*fission_workflows_version.Info, error) {
+	out := new(fission_workflows_version.Info)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/Version", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *workflowInvocationAPIClient) AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+func (c *adminAPIClient) GetExpressionState(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ExpressionState, error) {
+	out := new(ExpressionState)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/GetExpressionState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ClearExpressionState(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
 	out := new(google_protobuf3.Empty)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/AddTask", in, out, c.cc, opts...)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ClearExpressionState", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *workflowInvocationAPIClient) Cancel(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+func (c *adminAPIClient) Reevaluate(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ReevaluateResult, error) {
+	out := new(ReevaluateResult)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/Reevaluate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) SuspendFunction(ctx context.Context, in *FunctionSuspension, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
 	out := new(google_protobuf3.Empty)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel", in, out, c.cc, opts...)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/SuspendFunction", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *workflowInvocationAPIClient) List(ctx context.Context, in *InvocationListQuery, opts ...grpc.CallOption) (*WorkflowInvocationList, error) {
-	out := new(WorkflowInvocationList)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/List", in, out, c.cc, opts...)
+func (c *adminAPIClient) ResumeFunction(ctx context.Context, in *FunctionSuspension, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ResumeFunction", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *workflowInvocationAPIClient) Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error) {
-	out := new(fission_workflows_types1.WorkflowInvocation)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Get", in, out, c.cc, opts...)
+func (c *adminAPIClient) ListSuspendedFunctions(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*SuspendedFunctionList, error) {
+	out := new(SuspendedFunctionList)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ListSuspendedFunctions", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *workflowInvocationAPIClient) Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error) {
-	out := new(ObjectEvents)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Events", in, out, c.cc, opts...)
+func (c *adminAPIClient) GetConcurrencyLock(ctx context.Context, in *ConcurrencyKey, opts ...grpc.CallOption) (*ConcurrencyLock, error) {
+	out := new(ConcurrencyLock)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/GetConcurrencyLock", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *workflowInvocationAPIClient) Validate(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
-	out := new(google_protobuf3.Empty)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Validate", in, out, c.cc, opts...)
+func (c *adminAPIClient) GetEvaluationStats(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*EvaluationStats, error) {
+	out := new(EvaluationStats)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/GetEvaluationStats", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// Server API for WorkflowInvocationAPI service
+func (c *adminAPIClient) GetRedactedOutput(ctx context.Context, in *RedactedOutputRequest, opts ...grpc.CallOption) (*RedactedOutput, error) {
+	out := new(RedactedOutput)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/GetRedactedOutput", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-type WorkflowInvocationAPIServer interface {
-	// Create a new workflow invocation
-	//
-	// In case the invocation specification is missing fields or contains invalid fields, a HTTP 400 is returned.
-	Invoke(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*fission_workflows_types1.ObjectMetadata, error)
-	InvokeSync(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*fission_workflows_types1.WorkflowInvocation, error)
-	AddTask(context.Context, *AddTaskRequest) (*google_protobuf3.Empty, error)
-	// Cancel a workflow invocation
-	//
-	// This action is irreverisble. A canceled invocation cannot be resumed or restarted.
-	// In case that an invocation already is canceled, has failed or has completed, nothing happens.
-	// In case that an invocation does not exist a HTTP 404 error status is returned.
-	Cancel(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
-	List(context.Context, *InvocationListQuery) (*WorkflowInvocationList, error)
-	// Get the specification and status of a workflow invocation
-	//
-	// Get returns three different aspects of the workflow invocation, namely the spec (specification), status and logs.
-	// To lighten the request load, consider using a more specific request.
-	Get(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_types1.WorkflowInvocation, error)
-	Events(context.Context, *fission_workflows_types1.ObjectMetadata) (*ObjectEvents, error)
-	Validate(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*google_protobuf3.Empty, error)
+func (c *adminAPIClient) GetWorkflowMetrics(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*WorkflowMetricsConfig, error) {
+	out := new(WorkflowMetricsConfig)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/GetWorkflowMetrics", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func RegisterWorkflowInvocationAPIServer(s *grpc.Server, srv WorkflowInvocationAPIServer) {
-	s.RegisterService(&_WorkflowInvocationAPI_serviceDesc, srv)
+func (c *adminAPIClient) SetWorkflowMetrics(ctx context.Context, in *WorkflowMetricsConfig, opts ...grpc.CallOption) (*WorkflowMetricsConfig, error) {
+	out := new(WorkflowMetricsConfig)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/SetWorkflowMetrics", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) GetHandoffStatus(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*HandoffStatus, error) {
+	out := new(HandoffStatus)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/GetHandoffStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ExportControllerState(ctx context.Context, in *HandoffRequest, opts ...grpc.CallOption) (*ControllerSnapshot, error) {
+	out := new(ControllerSnapshot)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ExportControllerState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ImportControllerState(ctx context.Context, in *ControllerSnapshot, opts ...grpc.CallOption) (*HandoffStatus, error) {
+	out := new(HandoffStatus)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ImportControllerState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ReclaimControllerState(ctx context.Context, in *HandoffReclaim, opts ...grpc.CallOption) (*HandoffStatus, error) {
+	out := new(HandoffStatus)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ReclaimControllerState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) GetInvocationHierarchy(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*InvocationHierarchy, error) {
+	out := new(InvocationHierarchy)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/GetInvocationHierarchy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) GetWorkflowDashboard(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*WorkflowDashboard, error) {
+	out := new(WorkflowDashboard)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/GetWorkflowDashboard", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for AdminAPI service
+
+type AdminAPIServer interface {
+	Status(context.Context, *google_protobuf3.Empty) (*Health, error)
+	Version(context.Context, *google_protobuf3.Empty) (*fission_workflows_version.Info, error)
+	// GetExpressionState returns the cached expression state of an invocation.
+	GetExpressionState(context.Context, *fission_workflows_types1.ObjectMetadata) (*ExpressionState, error)
+	// ClearExpressionState clears the cached expression state of an invocation, forcing it to be re-derived from
+	// the invocation on the next evaluation.
+	ClearExpressionState(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
+	// Reevaluate forces the invocation controller to evaluate an invocation immediately, rather than waiting for
+	// the next notification or poll.
+	Reevaluate(context.Context, *fission_workflows_types1.ObjectMetadata) (*ReevaluateResult, error)
+	// SuspendFunction suspends the scheduling of tasks that reference the function, for example during planned
+	// maintenance. The tasks wait until the function is resumed, or until their invocation exceeds its deadline.
+	SuspendFunction(context.Context, *FunctionSuspension) (*google_protobuf3.Empty, error)
+	// ResumeFunction resumes the scheduling of tasks that reference the function.
+	ResumeFunction(context.Context, *FunctionSuspension) (*google_protobuf3.Empty, error)
+	// ListSuspendedFunctions lists the suspended functions, along with the number of tasks waiting on each.
+	ListSuspendedFunctions(context.Context, *google_protobuf3.Empty) (*SuspendedFunctionList, error)
+	// GetConcurrencyLock returns the invocation that holds the concurrency key of a workflow, and the invocations
+	// that are queued for it.
+	GetConcurrencyLock(context.Context, *ConcurrencyKey) (*ConcurrencyLock, error)
+	// GetEvaluationStats returns the evaluation statistics of an invocation, such as the time that its evaluations
+	// waited in the evaluation queue of the invocation controller.
+	GetEvaluationStats(context.Context, *fission_workflows_types1.ObjectMetadata) (*EvaluationStats, error)
+	// GetRedactedOutput returns the original values of the redacted output fields of a task, if they have been kept.
+	GetRedactedOutput(context.Context, *RedactedOutputRequest) (*RedactedOutput, error)
+	// GetWorkflowMetrics returns which workflows have their own series in the per-workflow metrics.
+	GetWorkflowMetrics(context.Context, *google_protobuf3.Empty) (*WorkflowMetricsConfig, error)
+	// SetWorkflowMetrics changes which workflows have their own series in the per-workflow metrics.
+	SetWorkflowMetrics(context.Context, *WorkflowMetricsConfig) (*WorkflowMetricsConfig, error)
+	// GetHandoffStatus returns whether the invocation controller owns the invocations, or has handed them off.
+	GetHandoffStatus(context.Context, *google_protobuf3.Empty) (*HandoffStatus, error)
+	// ExportControllerState drains the invocation controller, and releases its invocations. It returns the state of
+	// the controller, to be imported by the controller instance that takes over the invocations.
+	ExportControllerState(context.Context, *HandoffRequest) (*ControllerSnapshot, error)
+	// ImportControllerState resumes the invocations of another controller instance in an invocation controller that
+	// is in standby.
+	ImportControllerState(context.Context, *ControllerSnapshot) (*HandoffStatus, error)
+	// ReclaimControllerState makes the invocation controller the owner of the invocations again, after a failed
+	// handoff, or takes over the invocations in a controller that is in standby without importing a snapshot.
+	ReclaimControllerState(context.Context, *HandoffReclaim) (*HandoffStatus, error)
+	// GetInvocationHierarchy returns an invocation along with the hierarchy of its sub-workflow invocations, and
+	// whether the cancellation of the invocations was propagated to their sub-workflow invocations.
+	GetInvocationHierarchy(context.Context, *fission_workflows_types1.ObjectMetadata) (*InvocationHierarchy, error)
+	// GetWorkflowDashboard generates a Grafana dashboard of the per-workflow metrics of a workflow.
+	GetWorkflowDashboard(context.Context, *fission_workflows_types1.ObjectMetadata) (*WorkflowDashboard, error)
+}
+
+func RegisterAdminAPIServer(s *grpc.Server, srv AdminAPIServer) {
+	s.RegisterService(&_AdminAPI_serviceDesc, srv)
+}
+
+func _AdminAPI_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).Status(ctx, req.(*google_protobuf3.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowInvocationAPI_Invoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.WorkflowInvocationSpec)
+func _AdminAPI_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowInvocationAPIServer).Invoke(ctx, in)
+		return srv.(AdminAPIServer).Version(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/Version",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).Invoke(ctx, req.(*fission_workflows_types1.WorkflowInvocationSpec))
+		return srv.(AdminAPIServer).Version(ctx, req.(*google_protobuf3.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowInvocationAPI_InvokeSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.WorkflowInvocationSpec)
+func _AdminAPI_GetExpressionState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowInvocationAPIServer).InvokeSync(ctx, in)
+		return srv.(AdminAPIServer).GetExpressionState(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeSync",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/GetExpressionState",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).InvokeSync(ctx, req.(*fission_workflows_types1.WorkflowInvocationSpec))
+		return srv.(AdminAPIServer).GetExpressionState(ctx, req.(*fission_workflows_types1.ObjectMetadata))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowInvocationAPI_AddTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddTaskRequest)
+func _AdminAPI_ClearExpressionState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowInvocationAPIServer).AddTask(ctx, in)
+		return srv.(AdminAPIServer).ClearExpressionState(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/AddTask",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ClearExpressionState",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).AddTask(ctx, req.(*AddTaskRequest))
+		return srv.(AdminAPIServer).ClearExpressionState(ctx, req.(*fission_workflows_types1.ObjectMetadata))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowInvocationAPI_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _AdminAPI_Reevaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowInvocationAPIServer).Cancel(ctx, in)
+		return srv.(AdminAPIServer).Reevaluate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/Reevaluate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).Cancel(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+		return srv.(AdminAPIServer).Reevaluate(ctx, req.(*fission_workflows_types1.ObjectMetadata))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowInvocationAPI_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InvocationListQuery)
+func _AdminAPI_SuspendFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FunctionSuspension)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowInvocationAPIServer).List(ctx, in)
+		return srv.(AdminAPIServer).SuspendFunction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/List",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/SuspendFunction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).List(ctx, req.(*InvocationListQuery))
+		return srv.(AdminAPIServer).SuspendFunction(ctx, req.(*FunctionSuspension))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowInvocationAPI_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.ObjectMetadata)
+func _AdminAPI_ResumeFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FunctionSuspension)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowInvocationAPIServer).Get(ctx, in)
+		return srv.(AdminAPIServer).ResumeFunction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Get",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ResumeFunction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).Get(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+		return srv.(AdminAPIServer).ResumeFunction(ctx, req.(*FunctionSuspension))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowInvocationAPI_Events_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.ObjectMetadata)
+func _AdminAPI_ListSuspendedFunctions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowInvocationAPIServer).Events(ctx, in)
+		return srv.(AdminAPIServer).ListSuspendedFunctions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Events",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ListSuspendedFunctions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).Events(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+		return srv.(AdminAPIServer).ListSuspendedFunctions(ctx, req.(*google_protobuf3.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkflowInvocationAPI_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.WorkflowInvocationSpec)
+func _AdminAPI_GetConcurrencyLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConcurrencyKey)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkflowInvocationAPIServer).Validate(ctx, in)
+		return srv.(AdminAPIServer).GetConcurrencyLock(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Validate",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/GetConcurrencyLock",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).Validate(ctx, req.(*fission_workflows_types1.WorkflowInvocationSpec))
+		return srv.(AdminAPIServer).GetConcurrencyLock(ctx, req.(*ConcurrencyKey))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _WorkflowInvocationAPI_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "fission.workflows.apiserver.WorkflowInvocationAPI",
-	HandlerType: (*WorkflowInvocationAPIServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Invoke",
-			Handler:    _WorkflowInvocationAPI_Invoke_Handler,
-		},
-		{
-			MethodName: "InvokeSync",
-			Handler:    _WorkflowInvocationAPI_InvokeSync_Handler,
-		},
-		{
-			MethodName: "AddTask",
-			Handler:    _WorkflowInvocationAPI_AddTask_Handler,
-		},
-		{
-			MethodName: "Cancel",
-			Handler:    _WorkflowInvocationAPI_Cancel_Handler,
-		},
-		{
-			MethodName: "List",
-			Handler:    _WorkflowInvocationAPI_List_Handler,
-		},
-		{
-			MethodName: "Get",
-			Handler:    _WorkflowInvocationAPI_Get_Handler,
-		},
-		{
-			MethodName: "Events",
-			Handler:    _WorkflowInvocationAPI_Events_Handler,
-		},
-		{
-			MethodName: "Validate",
-			Handler:    _WorkflowInvocationAPI_Validate_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/apiserver/apiserver.proto",
+func _AdminAPI_GetEvaluationStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).GetEvaluationStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/GetEvaluationStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).GetEvaluationStats(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Client API for AdminAPI service
-
-type AdminAPIClient interface {
-	Status(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Health, error)
-	Version(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*fission_workflows_version.Info, error)
+func _AdminAPI_GetRedactedOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedactedOutputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).GetRedactedOutput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/GetRedactedOutput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).GetRedactedOutput(ctx, req.(*RedactedOutputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type adminAPIClient struct {
-	cc *grpc.ClientConn
+func _AdminAPI_GetWorkflowMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).GetWorkflowMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/GetWorkflowMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).GetWorkflowMetrics(ctx, req.(*google_protobuf3.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func NewAdminAPIClient(cc *grpc.ClientConn) AdminAPIClient {
-	return &adminAPIClient{cc}
+func _AdminAPI_SetWorkflowMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowMetricsConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).SetWorkflowMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/SetWorkflowMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).SetWorkflowMetrics(ctx, req.(*WorkflowMetricsConfig))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *adminAPIClient) Status(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Health, error) {
-	out := new(Health)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/Status", in, out, c.cc, opts...)
-	if err != nil {
+func _AdminAPI_GetHandoffStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(AdminAPIServer).GetHandoffStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/GetHandoffStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).GetHandoffStatus(ctx, req.(*google_protobuf3.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *adminAPIClient) Version(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*fission_workflows_version.Info, error) {
-	out := new(fission_workflows_version.Info)
-	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/Version", in, out, c.cc, opts...)
-	if err != nil {
+func _AdminAPI_ExportControllerState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandoffRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ExportControllerState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ExportControllerState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ExportControllerState(ctx, req.(*HandoffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Server API for AdminAPI service
-
-type AdminAPIServer interface {
-	Status(context.Context, *google_protobuf3.Empty) (*Health, error)
-	Version(context.Context, *google_protobuf3.Empty) (*fission_workflows_version.Info, error)
+func _AdminAPI_ImportControllerState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ControllerSnapshot)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ImportControllerState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ImportControllerState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ImportControllerState(ctx, req.(*ControllerSnapshot))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterAdminAPIServer(s *grpc.Server, srv AdminAPIServer) {
-	s.RegisterService(&_AdminAPI_serviceDesc, srv)
+func _AdminAPI_ReclaimControllerState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandoffReclaim)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ReclaimControllerState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ReclaimControllerState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ReclaimControllerState(ctx, req.(*HandoffReclaim))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _AdminAPI_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(google_protobuf3.Empty)
+func _AdminAPI_GetInvocationHierarchy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AdminAPIServer).Status(ctx, in)
+		return srv.(AdminAPIServer).GetInvocationHierarchy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.AdminAPI/Status",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/GetInvocationHierarchy",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AdminAPIServer).Status(ctx, req.(*google_protobuf3.Empty))
+		return srv.(AdminAPIServer).GetInvocationHierarchy(ctx, req.(*fission_workflows_types1.ObjectMetadata))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AdminAPI_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(google_protobuf3.Empty)
+func _AdminAPI_GetWorkflowDashboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AdminAPIServer).Version(ctx, in)
+		return srv.(AdminAPIServer).GetWorkflowDashboard(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fission.workflows.apiserver.AdminAPI/Version",
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/GetWorkflowDashboard",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AdminAPIServer).Version(ctx, req.(*google_protobuf3.Empty))
+		return srv.(AdminAPIServer).GetWorkflowDashboard(ctx, req.(*fission_workflows_types1.ObjectMetadata))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -844,6 +3383,74 @@ var _AdminAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _AdminAPI_Version_Handler,
 		},
+		{
+			MethodName: "GetExpressionState",
+			Handler:    _AdminAPI_GetExpressionState_Handler,
+		},
+		{
+			MethodName: "ClearExpressionState",
+			Handler:    _AdminAPI_ClearExpressionState_Handler,
+		},
+		{
+			MethodName: "Reevaluate",
+			Handler:    _AdminAPI_Reevaluate_Handler,
+		},
+		{
+			MethodName: "SuspendFunction",
+			Handler:    _AdminAPI_SuspendFunction_Handler,
+		},
+		{
+			MethodName: "ResumeFunction",
+			Handler:    _AdminAPI_ResumeFunction_Handler,
+		},
+		{
+			MethodName: "ListSuspendedFunctions",
+			Handler:    _AdminAPI_ListSuspendedFunctions_Handler,
+		},
+		{
+			MethodName: "GetConcurrencyLock",
+			Handler:    _AdminAPI_GetConcurrencyLock_Handler,
+		},
+		{
+			MethodName: "GetEvaluationStats",
+			Handler:    _AdminAPI_GetEvaluationStats_Handler,
+		},
+		{
+			MethodName: "GetRedactedOutput",
+			Handler:    _AdminAPI_GetRedactedOutput_Handler,
+		},
+		{
+			MethodName: "GetWorkflowMetrics",
+			Handler:    _AdminAPI_GetWorkflowMetrics_Handler,
+		},
+		{
+			MethodName: "SetWorkflowMetrics",
+			Handler:    _AdminAPI_SetWorkflowMetrics_Handler,
+		},
+		{
+			MethodName: "GetHandoffStatus",
+			Handler:    _AdminAPI_GetHandoffStatus_Handler,
+		},
+		{
+			MethodName: "ExportControllerState",
+			Handler:    _AdminAPI_ExportControllerState_Handler,
+		},
+		{
+			MethodName: "ImportControllerState",
+			Handler:    _AdminAPI_ImportControllerState_Handler,
+		},
+		{
+			MethodName: "ReclaimControllerState",
+			Handler:    _AdminAPI_ReclaimControllerState_Handler,
+		},
+		{
+			MethodName: "GetInvocationHierarchy",
+			Handler:    _AdminAPI_GetInvocationHierarchy_Handler,
+		},
+		{
+			MethodName: "GetWorkflowDashboard",
+			Handler:    _AdminAPI_GetWorkflowDashboard_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/apiserver/apiserver.proto",
@@ -852,58 +3459,209 @@ var _AdminAPI_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/apiserver/apiserver.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 835 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xd1, 0x4e, 0xe3, 0x56,
-	0x10, 0x95, 0x01, 0x99, 0x64, 0x4c, 0x51, 0x3a, 0x40, 0x08, 0x01, 0x44, 0x7a, 0x51, 0xd5, 0x00,
-	0xad, 0xdd, 0x06, 0xa9, 0x0f, 0x54, 0xaa, 0x44, 0x01, 0xb5, 0x91, 0x5a, 0xd1, 0x0d, 0x08, 0x24,
-	0xb4, 0x2f, 0x8e, 0x73, 0x93, 0x78, 0x93, 0xd8, 0xc1, 0xbe, 0x09, 0x0a, 0x28, 0x2f, 0xec, 0x0f,
-	0xac, 0xb4, 0x8f, 0xfb, 0xb0, 0xcf, 0xfb, 0x09, 0xfb, 0x1d, 0xfb, 0x0b, 0xfb, 0x21, 0x2b, 0x5f,
-	0x5f, 0x27, 0x0e, 0xc1, 0x89, 0xa3, 0xd5, 0x3e, 0x40, 0x92, 0xf1, 0xcc, 0x9c, 0x39, 0x73, 0xe7,
-	0xcc, 0x35, 0x6c, 0xb7, 0x1b, 0x35, 0x4d, 0x6f, 0x9b, 0x2e, 0x75, 0xba, 0xd4, 0x19, 0x7e, 0x53,
-	0xdb, 0x8e, 0xcd, 0x6c, 0xdc, 0xac, 0x9a, 0xae, 0x6b, 0xda, 0x96, 0x7a, 0x67, 0x3b, 0x8d, 0x6a,
-	0xd3, 0xbe, 0x73, 0xd5, 0x81, 0x4b, 0xf6, 0xa8, 0x66, 0xb2, 0x7a, 0xa7, 0xac, 0x1a, 0x76, 0x4b,
-	0x13, 0x7e, 0xc1, 0xe7, 0x2f, 0x03, 0x7f, 0xcd, 0x03, 0x60, 0xbd, 0x36, 0x75, 0xfd, 0xff, 0x7e,
-	0xe2, 0xec, 0x9f, 0xb1, 0x63, 0xbb, 0xd4, 0xe1, 0x4f, 0xc5, 0xa7, 0x88, 0xff, 0x3d, 0x76, 0x7c,
-	0x95, 0xba, 0xde, 0x9f, 0x88, 0xdb, 0xac, 0xd9, 0x76, 0xad, 0x49, 0x35, 0xfe, 0xab, 0xdc, 0xa9,
-	0x6a, 0xb4, 0xd5, 0x66, 0x3d, 0xf1, 0x70, 0x4b, 0x3c, 0xd4, 0xdb, 0xa6, 0xa6, 0x5b, 0x96, 0xcd,
-	0x74, 0x66, 0xda, 0x96, 0x08, 0x25, 0x3f, 0xc3, 0xd2, 0xb5, 0xc8, 0xfc, 0xaf, 0xe9, 0x32, 0xdc,
-	0x82, 0xe4, 0x00, 0x29, 0x23, 0xe5, 0xe6, 0xf3, 0xc9, 0xd2, 0xd0, 0x40, 0x6a, 0xb0, 0x7c, 0x5c,
-	0xa9, 0x5c, 0xea, 0x6e, 0xa3, 0x44, 0x6f, 0x3b, 0xd4, 0x65, 0x48, 0x60, 0xc9, 0xb4, 0xba, 0xb6,
-	0xc1, 0x93, 0x16, 0x4f, 0x33, 0x52, 0x4e, 0xca, 0x27, 0x4b, 0x23, 0x36, 0xfc, 0x0d, 0x16, 0x98,
-	0xee, 0x36, 0x32, 0x73, 0x39, 0x29, 0xaf, 0x14, 0xb6, 0xd5, 0xf1, 0xf6, 0xfb, 0x4d, 0xe4, 0x79,
-	0xb9, 0x2b, 0x39, 0x84, 0x95, 0xe2, 0x20, 0x85, 0x57, 0xd8, 0x8b, 0x0e, 0x75, 0x7a, 0x53, 0xaa,
-	0x3b, 0x82, 0x74, 0xc0, 0x65, 0x34, 0x18, 0x73, 0xa0, 0x0c, 0x2b, 0x0a, 0x22, 0xc3, 0x26, 0xf2,
-	0x46, 0x82, 0xa5, 0xf3, 0xf2, 0x2b, 0x6a, 0xb0, 0xb3, 0x2e, 0xb5, 0x98, 0x8b, 0x27, 0x90, 0x68,
-	0x51, 0xa6, 0x57, 0x74, 0xa6, 0x73, 0x52, 0x4a, 0xe1, 0xa7, 0xc8, 0xc2, 0xfd, 0xc0, 0xff, 0x84,
-	0x7b, 0x69, 0x10, 0x88, 0x7f, 0x80, 0x4c, 0x79, 0xba, 0xcc, 0x5c, 0x6e, 0x3e, 0xaf, 0x14, 0x76,
-	0x9f, 0x49, 0xe1, 0x3b, 0x30, 0xdb, 0xa1, 0x2a, 0x87, 0x2e, 0x89, 0x10, 0x92, 0x03, 0xf9, 0x1f,
-	0xaa, 0x37, 0x59, 0x1d, 0xd3, 0x20, 0xbb, 0x4c, 0x67, 0x1d, 0x57, 0xb4, 0x57, 0xfc, 0x2a, 0xbc,
-	0x96, 0x41, 0x09, 0x18, 0x1f, 0xff, 0x5f, 0x44, 0x0b, 0xe4, 0x13, 0x87, 0xea, 0x8c, 0xe2, 0x8f,
-	0x91, 0xb5, 0x06, 0xfe, 0x17, 0x6d, 0x6a, 0x64, 0xe3, 0x52, 0x22, 0xab, 0x8f, 0x9f, 0x3e, 0xbf,
-	0x9d, 0x5b, 0x26, 0x49, 0x2d, 0x70, 0x3c, 0x92, 0xf6, 0xf1, 0x16, 0xc0, 0xc7, 0xbb, 0xe8, 0x59,
-	0x46, 0x5c, 0xcc, 0x1f, 0xa6, 0xba, 0x91, 0x0d, 0x8e, 0xb6, 0x42, 0x96, 0x07, 0x68, 0x9a, 0xdb,
-	0xb3, 0x0c, 0x0f, 0xf2, 0x25, 0x2c, 0xf0, 0x13, 0x4d, 0xab, 0xfe, 0x58, 0xab, 0xc1, 0xcc, 0xab,
-	0x67, 0xde, 0xcc, 0x67, 0xf7, 0xd4, 0x09, 0xe2, 0x56, 0xc3, 0xa3, 0x4e, 0xbe, 0xe7, 0x28, 0x0a,
-	0x0e, 0x39, 0xa1, 0x09, 0xf3, 0x7f, 0x53, 0x86, 0x71, 0xdb, 0x12, 0x87, 0x4b, 0x9a, 0xa3, 0xa4,
-	0x30, 0xc4, 0xe5, 0xc1, 0xac, 0xf4, 0x51, 0x07, 0xf9, 0x94, 0x36, 0x29, 0xa3, 0xf1, 0xd1, 0x22,
-	0x38, 0x07, 0x10, 0xfb, 0x4f, 0x21, 0xea, 0x90, 0xb8, 0xd2, 0x9b, 0x66, 0x65, 0x86, 0x81, 0x88,
-	0x82, 0xd8, 0xe6, 0x10, 0xeb, 0x04, 0x87, 0x10, 0x5d, 0x91, 0xda, 0x3b, 0x95, 0x07, 0x90, 0x85,
-	0x6c, 0x62, 0x93, 0x99, 0x7c, 0x50, 0x61, 0x29, 0x06, 0xe0, 0xb8, 0x36, 0xca, 0x4f, 0xf3, 0x75,
-	0x52, 0xf8, 0x90, 0x80, 0xb5, 0x71, 0xdd, 0x7b, 0x7a, 0xb8, 0x07, 0xd9, 0x33, 0x34, 0x28, 0x6a,
-	0x53, 0xe9, 0x0f, 0x23, 0x67, 0x53, 0x86, 0x68, 0x3e, 0x51, 0xb4, 0xe1, 0x3a, 0xf1, 0x5a, 0xf2,
-	0x4e, 0x02, 0xf0, 0xc1, 0xb9, 0x38, 0x66, 0x2e, 0xe0, 0x60, 0x86, 0x00, 0xa2, 0xf1, 0x22, 0xf6,
-	0x48, 0x2a, 0x54, 0x44, 0x20, 0x99, 0x1b, 0xc4, 0x31, 0x33, 0xbe, 0x97, 0x60, 0x51, 0x6c, 0x72,
-	0x3c, 0x98, 0x78, 0x12, 0xa3, 0xfb, 0x3e, 0x72, 0x40, 0xce, 0x79, 0x05, 0x45, 0x92, 0x0b, 0x43,
-	0x3d, 0x84, 0xaf, 0x81, 0xbe, 0xe6, 0x6d, 0x76, 0xd7, 0xab, 0x88, 0x64, 0xa7, 0xba, 0xa1, 0x01,
-	0xf2, 0x89, 0x6e, 0x19, 0xb4, 0xf9, 0xf5, 0xfa, 0xc8, 0xf0, 0xda, 0x70, 0x3f, 0x35, 0x0a, 0x5a,
-	0xe9, 0xe3, 0xa3, 0x24, 0xd6, 0xc9, 0xaf, 0x13, 0x7b, 0xf0, 0xcc, 0x55, 0x94, 0x3d, 0x8c, 0xb5,
-	0x68, 0x46, 0x23, 0xc9, 0x0a, 0xaf, 0xe4, 0x3b, 0x0c, 0x0f, 0x0b, 0x76, 0x66, 0x5c, 0x3a, 0x33,
-	0x4d, 0x86, 0xe0, 0x8e, 0xe3, 0xdc, 0xfb, 0xdf, 0x54, 0xb3, 0x3b, 0x1c, 0x77, 0x03, 0xd7, 0x9f,
-	0xe2, 0x0a, 0xd5, 0x22, 0x0b, 0x2d, 0xa7, 0x99, 0xc5, 0x11, 0x75, 0xd2, 0x02, 0x95, 0xac, 0x86,
-	0x51, 0x43, 0x8b, 0xaa, 0xf0, 0x51, 0x82, 0xc4, 0x71, 0xa5, 0x65, 0xf2, 0xf5, 0x70, 0x0d, 0xf2,
-	0x05, 0xbf, 0x48, 0x23, 0x6f, 0x93, 0xdd, 0x89, 0x84, 0xfd, 0xdb, 0x99, 0xa4, 0x38, 0x28, 0x60,
-	0x42, 0xab, 0x73, 0xc3, 0x3d, 0x5e, 0xc2, 0xe2, 0x95, 0xff, 0x62, 0x17, 0x99, 0x79, 0xe7, 0x99,
-	0xcc, 0xc1, 0xcb, 0x60, 0xd1, 0xaa, 0xda, 0xa1, 0xac, 0xc2, 0xfc, 0x97, 0x72, 0x93, 0x1c, 0x60,
-	0x97, 0x65, 0x9e, 0xef, 0xf0, 0x4b, 0x00, 0x00, 0x00, 0xff, 0xff, 0x8f, 0xab, 0xd0, 0x49, 0xeb,
-	0x0a, 0x00, 0x00,
+	// 3249 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x3a, 0x5b, 0x6f, 0x1b, 0xc7,
+	0xd5, 0x58, 0x4a, 0xa4, 0xc8, 0x43, 0x59, 0x97, 0xd1, 0x25, 0x34, 0x6d, 0xc7, 0xf2, 0xf8, 0xcb,
+	0x67, 0x59, 0x76, 0x48, 0x87, 0x4e, 0xf2, 0x7d, 0x71, 0x90, 0x06, 0x92, 0x2c, 0xcb, 0x42, 0x1c,
+	0x58, 0x5e, 0xa9, 0x71, 0x13, 0xb4, 0x0f, 0xeb, 0xdd, 0x21, 0xb9, 0xd1, 0x72, 0x77, 0xbd, 0x3b,
+	0x94, 0xcd, 0xb8, 0x46, 0x1b, 0x07, 0x28, 0xd2, 0xa0, 0x28, 0x82, 0x36, 0x4d, 0x0b, 0xb4, 0x48,
+	0xdb, 0x97, 0xf6, 0xa1, 0x6f, 0x45, 0x9f, 0xfb, 0xd2, 0x9f, 0xd0, 0x97, 0xbe, 0xf4, 0xad, 0x8f,
+	0xfd, 0x11, 0xc5, 0x5c, 0xf6, 0xc6, 0xeb, 0xae, 0xea, 0x14, 0x7d, 0xb0, 0xc5, 0x39, 0x33, 0x73,
+	0xce, 0x99, 0x73, 0x9f, 0x39, 0x0b, 0xe7, 0xdc, 0xa3, 0x56, 0x5d, 0x73, 0x4d, 0x9f, 0x78, 0xc7,
+	0xc4, 0x8b, 0x7e, 0xd5, 0x5c, 0xcf, 0xa1, 0x0e, 0x3a, 0xd3, 0x34, 0x7d, 0xdf, 0x74, 0xec, 0xda,
+	0x23, 0xc7, 0x3b, 0x6a, 0x5a, 0xce, 0x23, 0xbf, 0x16, 0x2e, 0xa9, 0xde, 0x68, 0x99, 0xb4, 0xdd,
+	0x7d, 0x50, 0xd3, 0x9d, 0x4e, 0x5d, 0xae, 0x0b, 0xfe, 0xbe, 0x1c, 0xae, 0xaf, 0x33, 0x02, 0xb4,
+	0xe7, 0x12, 0x5f, 0xfc, 0x2f, 0x10, 0x57, 0xbf, 0x91, 0x7a, 0xef, 0x31, 0xf1, 0xf8, 0xac, 0xfc,
+	0x2b, 0xf7, 0xbf, 0x9e, 0x7a, 0x7f, 0x93, 0xf8, 0xec, 0x9f, 0xdc, 0x77, 0xa6, 0xe5, 0x38, 0x2d,
+	0x8b, 0xd4, 0xf9, 0xe8, 0x41, 0xb7, 0x59, 0x27, 0x1d, 0x97, 0xf6, 0xe4, 0xe4, 0x59, 0x39, 0xa9,
+	0xb9, 0x66, 0x5d, 0xb3, 0x6d, 0x87, 0x6a, 0xd4, 0x74, 0x6c, 0xb9, 0x15, 0x5f, 0x85, 0xd9, 0xfb,
+	0x12, 0xf3, 0x1d, 0xd3, 0xa7, 0xe8, 0x2c, 0x94, 0x42, 0x4a, 0x15, 0x65, 0x6d, 0x6a, 0xbd, 0xa4,
+	0x46, 0x00, 0xfc, 0x1d, 0x58, 0x0a, 0x56, 0xdf, 0x34, 0x9b, 0x4d, 0x95, 0x3c, 0xec, 0x12, 0x9f,
+	0xa2, 0x39, 0xc8, 0x99, 0x46, 0x45, 0x59, 0x53, 0xd6, 0x4b, 0x6a, 0xce, 0x34, 0x50, 0x15, 0x8a,
+	0xf2, 0x60, 0x9b, 0x95, 0xdc, 0x9a, 0xb2, 0x9e, 0x57, 0xc3, 0x71, 0x6c, 0x6e, 0xab, 0x32, 0x95,
+	0x98, 0xdb, 0xc2, 0x7f, 0x50, 0x60, 0x36, 0x8e, 0xff, 0x79, 0x21, 0x46, 0xab, 0x50, 0x68, 0x9a,
+	0xc4, 0x32, 0xfc, 0xca, 0x34, 0x3f, 0x92, 0x1c, 0xa1, 0x37, 0x21, 0x4f, 0x35, 0xff, 0xc8, 0xaf,
+	0xe4, 0xd7, 0xa6, 0xd6, 0xcb, 0x8d, 0x97, 0x6a, 0x63, 0x2c, 0xa3, 0x76, 0xa8, 0xf9, 0x47, 0xfc,
+	0xd4, 0x62, 0x0f, 0x56, 0xa1, 0x18, 0x80, 0x18, 0x01, 0x06, 0xdc, 0x0b, 0x98, 0x95, 0x23, 0x06,
+	0xd7, 0xdb, 0x9a, 0xdd, 0x22, 0x9c, 0xdd, 0x92, 0x2a, 0x47, 0x31, 0x86, 0xa6, 0xe2, 0x0c, 0xe1,
+	0x16, 0xcc, 0x6d, 0x1a, 0x06, 0x43, 0x1b, 0xc8, 0x16, 0xc3, 0xac, 0x69, 0x1f, 0x3b, 0x3a, 0xd7,
+	0xda, 0xde, 0x4d, 0x89, 0x3f, 0x01, 0x43, 0xaf, 0xc0, 0x34, 0xa3, 0xc7, 0x69, 0x94, 0x1b, 0xe7,
+	0x86, 0x9c, 0x42, 0x58, 0x29, 0xc7, 0xcb, 0x97, 0xe2, 0x7f, 0x2a, 0x50, 0x51, 0x89, 0x6b, 0x69,
+	0xbd, 0x5b, 0x9a, 0x69, 0x11, 0x4e, 0xd2, 0x1f, 0xa5, 0xcf, 0x8f, 0x60, 0xb1, 0xd9, 0xb5, 0x75,
+	0x46, 0xed, 0xee, 0x31, 0xf1, 0x3c, 0xd3, 0x20, 0x7e, 0x25, 0xc7, 0x45, 0x76, 0x67, 0xac, 0xc8,
+	0x46, 0x51, 0xa8, 0xdd, 0xea, 0x47, 0xb7, 0x63, 0x53, 0xaf, 0xa7, 0x0e, 0x92, 0xa9, 0xde, 0x84,
+	0xd5, 0xe1, 0x8b, 0xd1, 0x02, 0x4c, 0x1d, 0x91, 0x9e, 0x64, 0x93, 0xfd, 0x44, 0xcb, 0x90, 0x3f,
+	0xd6, 0xac, 0x6e, 0x20, 0x6c, 0x31, 0xb8, 0x91, 0xfb, 0x7f, 0x05, 0xbf, 0x06, 0xa7, 0x87, 0xf0,
+	0xe2, 0xbb, 0x8e, 0xed, 0x13, 0x54, 0x81, 0x19, 0xa1, 0xae, 0xc0, 0xe2, 0x83, 0x21, 0x7e, 0x96,
+	0x83, 0xd5, 0xbd, 0x50, 0xd2, 0xfb, 0x5d, 0xaf, 0x45, 0x02, 0x19, 0xbd, 0x08, 0x10, 0x9c, 0x38,
+	0xd4, 0x7a, 0x0c, 0x82, 0xee, 0x43, 0xc1, 0xd2, 0x1e, 0x10, 0x2b, 0x10, 0xd4, 0xdb, 0x63, 0x05,
+	0x35, 0x9c, 0x48, 0xed, 0x0e, 0xc7, 0x20, 0x64, 0x23, 0xd1, 0x31, 0x0f, 0x75, 0x2c, 0x83, 0x78,
+	0x87, 0x6d, 0xcd, 0xe6, 0x86, 0x5e, 0x52, 0x23, 0x00, 0x33, 0x2c, 0xc3, 0xeb, 0xa9, 0x5d, 0xbb,
+	0x32, 0xbd, 0xa6, 0xac, 0x17, 0x55, 0x39, 0xaa, 0xbe, 0x01, 0xe5, 0x18, 0xb2, 0x4c, 0xb2, 0xfb,
+	0x58, 0x81, 0x95, 0x01, 0xfe, 0xfc, 0xae, 0x45, 0xd1, 0x1a, 0x94, 0x23, 0x3b, 0x0c, 0x84, 0x17,
+	0x07, 0x31, 0xac, 0xba, 0xd3, 0xb5, 0xa9, 0xf4, 0x56, 0x31, 0x60, 0x02, 0xf7, 0x8f, 0x4c, 0xd7,
+	0x25, 0x86, 0xf4, 0xd4, 0x60, 0x38, 0x8a, 0x7d, 0xfc, 0x83, 0x1c, 0x54, 0x22, 0x1e, 0x36, 0x3d,
+	0xbd, 0x6d, 0x1e, 0xa7, 0x56, 0xc5, 0xfb, 0x7d, 0xaa, 0xd8, 0x4c, 0xa9, 0x8a, 0x24, 0x99, 0xff,
+	0x0e, 0x65, 0x7c, 0xa2, 0xc0, 0x0b, 0x43, 0x38, 0xfc, 0x0f, 0xab, 0xe3, 0x3a, 0x2c, 0x45, 0x4c,
+	0xb0, 0xbc, 0x71, 0xaf, 0x4b, 0xbc, 0xde, 0x84, 0xe4, 0x71, 0x03, 0x56, 0x83, 0xe0, 0x9e, 0xdc,
+	0x3c, 0x99, 0x71, 0x7c, 0x2f, 0x6e, 0x82, 0x07, 0x54, 0xa3, 0x5d, 0x5f, 0x90, 0x5c, 0x80, 0x29,
+	0x33, 0xf4, 0x5b, 0xf6, 0x13, 0xfd, 0x2f, 0xcc, 0x75, 0xb4, 0xc7, 0x77, 0xbb, 0xd4, 0xed, 0xd2,
+	0xad, 0x1e, 0xe5, 0x91, 0x4a, 0x59, 0x9f, 0x52, 0xfb, 0xa0, 0xb8, 0x05, 0xcb, 0xfd, 0x28, 0x39,
+	0x33, 0x77, 0xa1, 0xe8, 0xf3, 0x11, 0x11, 0x68, 0xcb, 0x8d, 0xeb, 0x29, 0xed, 0x45, 0x20, 0x11,
+	0xca, 0x50, 0x43, 0x24, 0xf8, 0x87, 0x0a, 0xac, 0x0e, 0x5f, 0x34, 0x10, 0x68, 0xf7, 0xa0, 0x20,
+	0xb6, 0xc9, 0x50, 0xfe, 0xca, 0xc8, 0x50, 0x3e, 0x28, 0x49, 0x89, 0x58, 0x22, 0x60, 0xaa, 0x26,
+	0x9e, 0xe7, 0x78, 0xd2, 0x2a, 0xc5, 0x00, 0xbf, 0x06, 0xe5, 0x78, 0x72, 0xe9, 0xa7, 0x1f, 0xa5,
+	0xb1, 0x5c, 0x3c, 0x8d, 0xe1, 0x2f, 0x72, 0x30, 0x1f, 0x51, 0xda, 0xf5, 0x9c, 0xae, 0x3b, 0xb0,
+	0xb7, 0x4f, 0x89, 0xb9, 0x41, 0xeb, 0x7b, 0x0f, 0x8a, 0xae, 0xe7, 0xb4, 0x3c, 0xe2, 0x8b, 0xb4,
+	0x57, 0x6e, 0xdc, 0x48, 0x29, 0x59, 0x4e, 0xb1, 0xb6, 0x2f, 0x37, 0x0b, 0x17, 0x0c, 0x71, 0xb1,
+	0xcc, 0xdf, 0x34, 0x6d, 0xd3, 0x6f, 0x13, 0x43, 0xda, 0x69, 0x38, 0x66, 0xb1, 0xc1, 0xef, 0xea,
+	0x3a, 0xf1, 0xfd, 0x66, 0xd7, 0xaa, 0xe4, 0xf9, 0x6c, 0x0c, 0x52, 0x7d, 0x13, 0x4e, 0x25, 0xd0,
+	0x4e, 0x72, 0xc6, 0x7c, 0xdc, 0x19, 0xff, 0xae, 0x00, 0x8a, 0x98, 0x3c, 0x34, 0x3b, 0xc4, 0x32,
+	0x6d, 0x32, 0x4c, 0xaa, 0x31, 0xad, 0x96, 0x42, 0x15, 0x9d, 0x85, 0x92, 0x4f, 0x35, 0x8f, 0x12,
+	0x63, 0x93, 0x06, 0xc1, 0x23, 0x04, 0x30, 0xce, 0x83, 0x53, 0x6c, 0x52, 0x7e, 0xae, 0x92, 0x1a,
+	0x83, 0xa0, 0xb7, 0x92, 0xb5, 0xcb, 0xa5, 0x89, 0xb5, 0xcb, 0xa1, 0xd9, 0x31, 0xed, 0x96, 0xac,
+	0x5e, 0x58, 0x5d, 0xa1, 0x7b, 0x26, 0x35, 0x75, 0xcd, 0xda, 0xd7, 0x68, 0xbb, 0x52, 0xe0, 0xfa,
+	0x4a, 0xc0, 0xf0, 0x9f, 0x73, 0x00, 0xd1, 0xce, 0x71, 0x45, 0xce, 0xd0, 0xf3, 0x55, 0x60, 0xc6,
+	0x23, 0x9a, 0xd1, 0x0b, 0x4f, 0x17, 0x0c, 0x93, 0x27, 0x9f, 0x1e, 0x7f, 0xf2, 0xfc, 0xc0, 0xc9,
+	0x5f, 0x85, 0x15, 0x83, 0xb8, 0xc4, 0x36, 0x88, 0xad, 0xf7, 0xee, 0x6b, 0x26, 0x3d, 0x20, 0xba,
+	0x63, 0x1b, 0x7e, 0xa5, 0xb0, 0xa6, 0xac, 0x2b, 0xea, 0xf0, 0x49, 0xb4, 0x01, 0x0b, 0x0f, 0xbb,
+	0xa4, 0x4b, 0xe2, 0x1b, 0x66, 0xf8, 0x86, 0x01, 0x38, 0x5b, 0x4b, 0x1e, 0x13, 0xbd, 0xcb, 0xfd,
+	0x4a, 0xae, 0x2d, 0x8a, 0xb5, 0xfd, 0x70, 0x66, 0x7d, 0x81, 0xd0, 0x2a, 0x25, 0x61, 0x7d, 0xc1,
+	0x18, 0x7f, 0xae, 0xc0, 0xec, 0xdd, 0x07, 0x1f, 0x12, 0x9d, 0xee, 0x1c, 0x13, 0x9b, 0xfa, 0x68,
+	0x1b, 0x8a, 0x1d, 0x42, 0x35, 0x43, 0xa3, 0x1a, 0x17, 0xe2, 0x70, 0xbd, 0x09, 0x17, 0x17, 0x1b,
+	0xdf, 0x95, 0xcb, 0xd5, 0x70, 0x23, 0x7a, 0x13, 0x0a, 0x84, 0xa3, 0x93, 0xf9, 0xec, 0xe2, 0x10,
+	0x14, 0x62, 0x01, 0x75, 0x3c, 0x52, 0xe3, 0xa4, 0x55, 0xb9, 0x05, 0x7f, 0x08, 0x85, 0xdb, 0x44,
+	0xb3, 0x68, 0x3b, 0xa6, 0x36, 0x25, 0xa1, 0xb6, 0xab, 0xb0, 0x18, 0x79, 0xed, 0x37, 0x5d, 0x43,
+	0x0b, 0x62, 0x68, 0x49, 0x1d, 0x9c, 0x60, 0xc7, 0x37, 0x48, 0xcb, 0xd3, 0x0c, 0x9e, 0x3d, 0x98,
+	0x0d, 0x85, 0x63, 0xfc, 0x7f, 0x30, 0xbf, 0xf3, 0xd8, 0x65, 0xbe, 0x25, 0xe3, 0xd3, 0xa0, 0x6f,
+	0x2c, 0x43, 0xde, 0xd7, 0x1d, 0x37, 0xcc, 0x74, 0x7c, 0x80, 0x0f, 0x61, 0x41, 0x25, 0x84, 0x39,
+	0x9a, 0x46, 0xc9, 0x88, 0x58, 0xb9, 0x0c, 0xf9, 0xa6, 0xd3, 0xb5, 0x45, 0xa8, 0x2a, 0xaa, 0x62,
+	0xc0, 0xd8, 0x21, 0x36, 0xd7, 0xa7, 0x48, 0x66, 0x45, 0x35, 0x1c, 0xe3, 0x0d, 0x40, 0x41, 0x29,
+	0x79, 0xd0, 0xf5, 0x5d, 0x62, 0x33, 0xb6, 0x38, 0x1e, 0x5b, 0x25, 0x4d, 0x89, 0x5a, 0x0c, 0xb0,
+	0x0e, 0x8b, 0x62, 0x8d, 0x41, 0x8c, 0x60, 0xd3, 0xf0, 0xa5, 0xfc, 0x08, 0xa6, 0xad, 0x47, 0x47,
+	0x60, 0x03, 0xe6, 0x5f, 0x8f, 0x34, 0x93, 0x9a, 0x76, 0x8b, 0x17, 0x9b, 0x32, 0xb3, 0x26, 0x60,
+	0x98, 0xc0, 0xca, 0x00, 0x11, 0x9e, 0x83, 0xee, 0x40, 0x29, 0xa8, 0x84, 0x83, 0x24, 0x54, 0x1b,
+	0xeb, 0xdf, 0x03, 0x68, 0xd4, 0x08, 0x01, 0xde, 0x82, 0xb9, 0x6d, 0xc7, 0xd6, 0xbb, 0x9e, 0xc7,
+	0x7c, 0xe2, 0x1d, 0xd2, 0x9b, 0x58, 0x31, 0xc9, 0x20, 0x98, 0x0b, 0x83, 0x20, 0xf6, 0x61, 0x3e,
+	0x86, 0xe3, 0x8e, 0xa3, 0x1f, 0x65, 0x47, 0xc2, 0x2c, 0xae, 0xcd, 0xab, 0x23, 0x19, 0x0f, 0xe4,
+	0x88, 0xc1, 0xa5, 0xca, 0xe4, 0xf5, 0x4c, 0x2a, 0xec, 0x2f, 0x39, 0x98, 0xdf, 0x11, 0x56, 0x20,
+	0x0d, 0xc8, 0x1f, 0x30, 0x83, 0xb3, 0x50, 0x62, 0x86, 0xb2, 0x1d, 0x96, 0x35, 0x53, 0x6a, 0x04,
+	0x40, 0xeb, 0x30, 0x6f, 0x69, 0x3e, 0x95, 0x48, 0x62, 0x81, 0xb6, 0x1f, 0x8c, 0x1a, 0xb0, 0xcc,
+	0x40, 0xf7, 0xfa, 0x43, 0xc4, 0x34, 0x77, 0xfb, 0xa1, 0x73, 0x2c, 0x10, 0x51, 0x87, 0x6a, 0xd6,
+	0xc0, 0xa6, 0xbc, 0x08, 0x44, 0x43, 0x27, 0x99, 0x65, 0x50, 0x4f, 0xd3, 0xc9, 0x81, 0xd6, 0x71,
+	0x2d, 0x62, 0xf0, 0xa8, 0x55, 0x54, 0x13, 0x30, 0x7e, 0x25, 0x61, 0xe3, 0x3d, 0x83, 0xc7, 0xa8,
+	0x92, 0x1a, 0x0c, 0xd1, 0x35, 0x58, 0x8a, 0x56, 0xb2, 0x78, 0x4e, 0x34, 0xdf, 0xb1, 0x79, 0x74,
+	0x2a, 0xa9, 0xc3, 0xa6, 0xf0, 0xdb, 0xb0, 0xa2, 0x12, 0x43, 0xd3, 0x29, 0x31, 0x44, 0xfd, 0x93,
+	0x35, 0xfb, 0xbf, 0x01, 0xa7, 0x02, 0x04, 0xb7, 0xd8, 0x35, 0x15, 0x21, 0x98, 0x76, 0x59, 0xce,
+	0x10, 0x5b, 0xf9, 0xef, 0xe1, 0x25, 0x2b, 0xfe, 0x2e, 0xcc, 0x25, 0x69, 0xa7, 0x25, 0x8a, 0xb6,
+	0x12, 0x37, 0xe4, 0x72, 0x63, 0x63, 0xc2, 0x45, 0x33, 0xc6, 0x5f, 0x78, 0x9b, 0x36, 0x61, 0x25,
+	0xa8, 0x93, 0xde, 0x25, 0xd4, 0x33, 0x75, 0x7f, 0xdb, 0xb1, 0x9b, 0x66, 0x6b, 0x7c, 0xa1, 0xca,
+	0x66, 0x69, 0xdb, 0x23, 0x3e, 0xb3, 0x4e, 0x99, 0xf4, 0x23, 0x00, 0x3b, 0xa8, 0x65, 0x76, 0x4c,
+	0x2a, 0x3d, 0x5a, 0x0c, 0xf0, 0xfb, 0x70, 0xea, 0xb6, 0x66, 0x1b, 0x4e, 0xb3, 0x79, 0x10, 0xd6,
+	0x5f, 0x2c, 0x9e, 0x92, 0x20, 0x56, 0xf0, 0x01, 0x43, 0xdd, 0x16, 0xcb, 0xc2, 0x03, 0x47, 0x00,
+	0xb6, 0x87, 0xb8, 0x8e, 0xde, 0xe6, 0xa8, 0xa7, 0x54, 0x31, 0x60, 0xee, 0x2b, 0x51, 0x07, 0x8a,
+	0xbb, 0x06, 0x4b, 0x86, 0xa7, 0x99, 0xbc, 0xe2, 0x70, 0xba, 0xa1, 0xd5, 0x29, 0xdc, 0xea, 0x86,
+	0x4d, 0xe1, 0x5f, 0xe5, 0x00, 0x6d, 0x3b, 0x36, 0xf5, 0x1c, 0xcb, 0x22, 0xde, 0x81, 0xad, 0xb9,
+	0x7e, 0xdb, 0xa1, 0x49, 0x76, 0x94, 0x91, 0xec, 0xe4, 0x62, 0xec, 0xb0, 0x3d, 0xba, 0x47, 0x12,
+	0xce, 0x14, 0x01, 0xd0, 0xb7, 0x92, 0x55, 0xe0, 0x34, 0xd7, 0xdd, 0xeb, 0x29, 0xcb, 0xbc, 0x18,
+	0x87, 0x4c, 0x5a, 0xc9, 0xea, 0x31, 0x0a, 0x12, 0xf9, 0x78, 0x90, 0x40, 0x5b, 0x90, 0xb7, 0x1c,
+	0xfd, 0xc8, 0xe7, 0x15, 0x4c, 0xb9, 0x71, 0x75, 0x2c, 0xad, 0xbe, 0x18, 0xa6, 0x8a, 0xad, 0xf8,
+	0xf7, 0x05, 0x38, 0x3d, 0x92, 0x8d, 0x01, 0x93, 0xc5, 0x30, 0x2b, 0x8b, 0x15, 0x11, 0xda, 0x45,
+	0xa9, 0x9b, 0x80, 0xb1, 0xe0, 0xc8, 0x2b, 0x6e, 0x11, 0x97, 0x84, 0xa9, 0xc4, 0x20, 0xa8, 0x09,
+	0xc0, 0x0c, 0x7d, 0x87, 0x41, 0x02, 0x31, 0xdd, 0x3a, 0x99, 0x98, 0x6a, 0x87, 0x21, 0x22, 0x51,
+	0x19, 0xc7, 0x30, 0x33, 0x6d, 0xd9, 0x8e, 0xe3, 0xb2, 0x48, 0x27, 0xc2, 0x52, 0x5e, 0x8d, 0x00,
+	0x6c, 0xd6, 0xf5, 0xc8, 0x23, 0xcd, 0xeb, 0x84, 0x71, 0x28, 0x02, 0xb0, 0x9b, 0x94, 0xee, 0xb0,
+	0x78, 0x44, 0x89, 0xb1, 0xa3, 0x79, 0x56, 0x8f, 0xc7, 0xa2, 0xa2, 0xda, 0x07, 0x65, 0xe6, 0xe8,
+	0x3b, 0x4d, 0x2a, 0x4d, 0x6e, 0xe7, 0xb1, 0x4e, 0x08, 0xab, 0x06, 0x8a, 0x7c, 0xf1, 0xb0, 0x29,
+	0x16, 0xde, 0x98, 0xe0, 0xdf, 0x21, 0x3d, 0x5e, 0x32, 0x95, 0xd4, 0x60, 0x88, 0x2c, 0x98, 0x65,
+	0x89, 0xcc, 0xb4, 0x5b, 0xfb, 0x8e, 0x65, 0xf9, 0x15, 0xe0, 0x92, 0xb9, 0x7d, 0x42, 0xc9, 0xec,
+	0xc7, 0x50, 0x09, 0xd9, 0x24, 0xb0, 0x27, 0x93, 0x47, 0x39, 0x45, 0xf2, 0x98, 0xcd, 0x96, 0x3c,
+	0x4e, 0x9d, 0x24, 0x79, 0xcc, 0x8d, 0x49, 0x1e, 0xd5, 0xb7, 0x60, 0xbe, 0x4f, 0xdd, 0x59, 0x6e,
+	0x2c, 0xd5, 0xb7, 0x61, 0x71, 0x40, 0x26, 0x99, 0xde, 0x1f, 0x6a, 0xb1, 0x60, 0xa4, 0x5b, 0x9a,
+	0xd9, 0x19, 0x1f, 0x43, 0xf0, 0x1f, 0x73, 0xf1, 0xa7, 0x82, 0xdb, 0x26, 0xf1, 0x34, 0x4f, 0x6f,
+	0xf7, 0x52, 0xdf, 0x91, 0x30, 0xcc, 0xba, 0x9a, 0x47, 0x6c, 0x7a, 0x28, 0x92, 0x84, 0x08, 0x38,
+	0x09, 0x18, 0x2b, 0x58, 0x75, 0xcd, 0xd6, 0x89, 0xb5, 0xef, 0x39, 0xae, 0xd6, 0xe2, 0x94, 0xe4,
+	0xad, 0x62, 0x70, 0x02, 0x1d, 0xc0, 0x9c, 0x00, 0xde, 0x24, 0xba, 0xc9, 0x6c, 0x8a, 0xbb, 0x45,
+	0xb9, 0x71, 0x65, 0x7c, 0xe0, 0x48, 0x6c, 0x51, 0xfb, 0x50, 0xa0, 0x3b, 0x50, 0xd4, 0xdb, 0xa6,
+	0x65, 0x78, 0xc4, 0x96, 0x71, 0xe8, 0x5a, 0x4a, 0x93, 0x0d, 0x45, 0xa2, 0x86, 0x18, 0xf0, 0x9f,
+	0x14, 0x98, 0x4b, 0x12, 0x64, 0x75, 0xad, 0x38, 0x73, 0x28, 0xe4, 0x70, 0x3c, 0x20, 0xa3, 0xdc,
+	0x10, 0x19, 0x21, 0x98, 0xee, 0x38, 0x06, 0x91, 0xf2, 0xe3, 0xbf, 0xf9, 0xcd, 0x85, 0x53, 0x89,
+	0xee, 0xcd, 0xc1, 0x38, 0x7a, 0x3e, 0xc8, 0xc7, 0x9e, 0x0f, 0x98, 0xae, 0x0d, 0xa2, 0x9b, 0x06,
+	0xf7, 0x85, 0x82, 0xd0, 0x75, 0x08, 0xc0, 0xf7, 0x60, 0x31, 0x7c, 0xbd, 0xd7, 0xfc, 0xf6, 0x03,
+	0x47, 0xf3, 0x8c, 0x89, 0x55, 0x22, 0x43, 0x19, 0x2c, 0x0e, 0x32, 0x62, 0x08, 0x68, 0x7c, 0x52,
+	0x84, 0x72, 0x80, 0x73, 0x73, 0x7f, 0x0f, 0xd9, 0x50, 0xd8, 0xe6, 0xb9, 0x06, 0xbd, 0x34, 0xf1,
+	0x69, 0xe4, 0xc0, 0x25, 0x7a, 0x35, 0xed, 0xf5, 0x0a, 0x2f, 0x3f, 0xfb, 0xeb, 0x3f, 0x7e, 0x9a,
+	0x9b, 0xc3, 0xa5, 0x7a, 0xb0, 0xf0, 0x86, 0xb2, 0x81, 0x1e, 0x02, 0x08, 0x7a, 0x07, 0x3d, 0x5b,
+	0x4f, 0x4b, 0xf3, 0xc2, 0xc4, 0x65, 0xf8, 0x34, 0xa7, 0xb6, 0x84, 0xe7, 0x42, 0x6a, 0x75, 0xbf,
+	0x67, 0xeb, 0x8c, 0xe4, 0xb7, 0x61, 0x9a, 0xdf, 0x01, 0x56, 0x6b, 0xa2, 0x71, 0x53, 0x0b, 0xba,
+	0x3a, 0xb5, 0x1d, 0xd6, 0xd5, 0xa9, 0x5e, 0x1e, 0x6b, 0x58, 0xf1, 0x66, 0x0e, 0x5e, 0xe4, 0x54,
+	0xca, 0x28, 0x3a, 0x13, 0x32, 0x61, 0x6a, 0x97, 0x50, 0x94, 0x56, 0x2c, 0x69, 0xce, 0xb2, 0xca,
+	0xa9, 0x2c, 0xa0, 0xd8, 0x59, 0x9e, 0x98, 0xc6, 0x53, 0xa4, 0x41, 0xe1, 0x26, 0x61, 0x69, 0x22,
+	0x3d, 0xb5, 0x11, 0x67, 0x0e, 0x48, 0x6c, 0xf4, 0x93, 0x68, 0x43, 0xf1, 0x3d, 0xcd, 0x32, 0x8d,
+	0x0c, 0x06, 0x31, 0x8a, 0xc4, 0x39, 0x4e, 0xe2, 0x05, 0x8c, 0x22, 0x12, 0xc7, 0x12, 0x35, 0xd3,
+	0xca, 0x13, 0x28, 0xc8, 0x2b, 0x7c, 0xea, 0xc3, 0x8c, 0x57, 0x54, 0xfc, 0x59, 0x20, 0x20, 0x8e,
+	0x56, 0x92, 0xe7, 0xab, 0x8b, 0x3b, 0x3b, 0xfa, 0xbe, 0x02, 0xd3, 0xbc, 0xcd, 0x74, 0x2d, 0x95,
+	0xee, 0x63, 0xad, 0xb9, 0xea, 0xe5, 0xd4, 0x3b, 0xf0, 0x19, 0xce, 0xc4, 0x0a, 0x5a, 0xea, 0x63,
+	0xc2, 0x60, 0x94, 0x9f, 0xc0, 0xfc, 0xb6, 0x45, 0x34, 0xef, 0x5e, 0x57, 0xf3, 0x34, 0x9b, 0x9a,
+	0xf6, 0x73, 0xd0, 0xea, 0x25, 0x4e, 0xf0, 0x02, 0x3e, 0xdf, 0x47, 0xf0, 0x61, 0x48, 0xa3, 0xae,
+	0x33, 0x9a, 0x8d, 0xcf, 0x96, 0xa2, 0x42, 0x3e, 0xf6, 0xf8, 0xbd, 0xbf, 0x87, 0x3e, 0x82, 0x02,
+	0x03, 0x1c, 0x11, 0x54, 0xcf, 0xf2, 0x54, 0x9a, 0x29, 0x32, 0x48, 0xe3, 0xc3, 0xe5, 0x7a, 0x54,
+	0x8e, 0x32, 0x93, 0xf8, 0xa5, 0x02, 0x20, 0x88, 0xf3, 0xe0, 0x90, 0x99, 0x81, 0x2b, 0x19, 0x36,
+	0xe0, 0x3a, 0x67, 0xe2, 0x32, 0x5e, 0x88, 0x31, 0x11, 0x84, 0x8c, 0x0f, 0x10, 0x1a, 0x00, 0xa3,
+	0x5f, 0x2b, 0x30, 0x23, 0x5b, 0x89, 0x68, 0x7c, 0x6a, 0x4b, 0x36, 0x1c, 0x47, 0x6a, 0xeb, 0x2e,
+	0xe7, 0x60, 0x0f, 0xaf, 0xc5, 0x49, 0x3d, 0x89, 0xf7, 0x21, 0x9f, 0xd6, 0xf9, 0xd3, 0x22, 0xe3,
+	0x08, 0x57, 0x27, 0x2e, 0x43, 0x3a, 0x14, 0x44, 0x92, 0xfb, 0xf7, 0x2d, 0xa9, 0xc2, 0x79, 0x43,
+	0x1b, 0x0b, 0x49, 0xa2, 0xc6, 0x53, 0xf4, 0x4c, 0x91, 0xe1, 0x34, 0x6d, 0x3e, 0x0e, 0xbb, 0x19,
+	0xd5, 0xeb, 0xa9, 0x5c, 0x27, 0xb9, 0x13, 0x2f, 0x71, 0x4e, 0x4e, 0xa1, 0xb8, 0xb1, 0xa0, 0x6e,
+	0xc6, 0xa0, 0x9b, 0xc9, 0x32, 0xe4, 0xd9, 0xd1, 0xe0, 0xd9, 0x9f, 0x7e, 0xad, 0x31, 0xeb, 0x3c,
+	0xa7, 0x7b, 0x1a, 0xbd, 0xd0, 0x4f, 0x37, 0x88, 0x5a, 0x34, 0x16, 0x9c, 0x33, 0x3b, 0xc7, 0x28,
+	0x4d, 0x4b, 0xaa, 0x78, 0x39, 0x4e, 0x35, 0x1e, 0xa8, 0x7f, 0xa6, 0x40, 0x79, 0x97, 0xd0, 0x03,
+	0xd9, 0x7d, 0x41, 0x8d, 0x4c, 0xcd, 0x1b, 0xa1, 0xf9, 0x57, 0x32, 0xed, 0xe1, 0x7a, 0x1f, 0xca,
+	0x57, 0xd0, 0x02, 0x62, 0x7c, 0x1d, 0x43, 0x71, 0x97, 0x50, 0xd1, 0x3a, 0x49, 0xad, 0x8e, 0xab,
+	0x59, 0xfa, 0x23, 0x31, 0xdb, 0x6b, 0xb1, 0xb1, 0x30, 0x02, 0x1d, 0xca, 0xc2, 0xcb, 0x32, 0x92,
+	0x1e, 0xa5, 0x00, 0x49, 0x64, 0x23, 0x41, 0xe4, 0x33, 0x21, 0xf4, 0xb0, 0x03, 0x92, 0x9a, 0x4a,
+	0x3d, 0xe5, 0x01, 0x03, 0xcc, 0xf8, 0x02, 0x27, 0x7f, 0x06, 0x9d, 0x1e, 0xb0, 0x3a, 0x1a, 0x10,
+	0xff, 0x54, 0x81, 0xa5, 0x5d, 0x42, 0x55, 0xe2, 0x3b, 0xd6, 0x31, 0x31, 0x02, 0x03, 0x4b, 0xcf,
+	0x54, 0xba, 0x4a, 0x62, 0x0c, 0x2b, 0xc1, 0x36, 0xf4, 0x3b, 0x05, 0x16, 0x07, 0xbe, 0x3b, 0x40,
+	0xaf, 0x9d, 0xe8, 0x9b, 0x89, 0xea, 0xeb, 0x59, 0xb7, 0x89, 0xcf, 0x1b, 0x30, 0xe6, 0x7c, 0x9e,
+	0xc5, 0x83, 0x8e, 0xea, 0xf1, 0x3d, 0xcc, 0x3a, 0x7f, 0xac, 0x40, 0x9e, 0x77, 0xf6, 0xd1, 0xf5,
+	0x13, 0x7c, 0xa7, 0x50, 0x6d, 0x64, 0xdb, 0xc4, 0xde, 0xf3, 0xf1, 0x59, 0xce, 0xd6, 0x2a, 0x5e,
+	0x8c, 0xb3, 0xe5, 0xb2, 0x05, 0xa2, 0xde, 0x9a, 0x61, 0x06, 0xc5, 0xb2, 0xd7, 0xfa, 0xc4, 0xce,
+	0x56, 0xc0, 0xc6, 0xa5, 0xb1, 0x5f, 0xbe, 0xc4, 0x62, 0x66, 0x54, 0x6f, 0x0d, 0x58, 0x11, 0xa3,
+	0xf8, 0x25, 0xcb, 0x9d, 0xa2, 0xb5, 0x3e, 0x41, 0x59, 0xa3, 0x3e, 0x16, 0xa8, 0xbe, 0x9a, 0x75,
+	0x1b, 0x97, 0xc9, 0x8b, 0x9c, 0xaf, 0xca, 0x0d, 0x65, 0x03, 0x2f, 0xc5, 0x59, 0xd3, 0x24, 0x33,
+	0x1f, 0x2b, 0x30, 0xa3, 0x12, 0xde, 0xd6, 0xf9, 0x9a, 0xb2, 0xc9, 0x1a, 0xe7, 0xa0, 0x8a, 0x2b,
+	0x43, 0x8c, 0x85, 0xd3, 0x6d, 0xfc, 0x6d, 0x19, 0x8a, 0x9b, 0x46, 0xc7, 0xe4, 0xf5, 0xd7, 0x7d,
+	0x28, 0xc8, 0xf7, 0xce, 0x51, 0xd7, 0x95, 0x8b, 0x63, 0x05, 0x21, 0x5a, 0x51, 0x78, 0x81, 0x53,
+	0x05, 0x54, 0xac, 0xb7, 0x39, 0xe0, 0x23, 0x74, 0x08, 0x33, 0xef, 0x89, 0xaf, 0xb7, 0x46, 0x62,
+	0x3e, 0x3f, 0x04, 0x73, 0xf0, 0x3d, 0xdd, 0x9e, 0xdd, 0x74, 0x62, 0x58, 0x25, 0x18, 0xfd, 0x48,
+	0x01, 0xb4, 0x4b, 0x68, 0x7f, 0x53, 0xea, 0x39, 0xc5, 0xe3, 0x3e, 0xb4, 0xb1, 0x0c, 0xa9, 0x31,
+	0x79, 0xd5, 0x49, 0x38, 0xef, 0x8b, 0xb0, 0xf9, 0x18, 0x96, 0x79, 0x51, 0x7d, 0x62, 0x7e, 0x26,
+	0x64, 0xc9, 0x8d, 0x91, 0x94, 0x3f, 0x57, 0x00, 0xa2, 0x0e, 0x5b, 0x7a, 0x82, 0x2f, 0x4f, 0x88,
+	0x41, 0xc9, 0x9e, 0x1d, 0xde, 0xe0, 0x7c, 0xfc, 0x0f, 0xc6, 0x92, 0x8f, 0xc8, 0xa6, 0xfc, 0xc0,
+	0xa8, 0x42, 0x1e, 0xbe, 0x07, 0xf3, 0xb2, 0x8b, 0x15, 0xf6, 0xdb, 0xc6, 0x67, 0x87, 0xc1, 0x5e,
+	0xde, 0x48, 0x79, 0x5c, 0xe4, 0x7c, 0x9c, 0xc3, 0x15, 0xc9, 0x47, 0xd8, 0x1b, 0xab, 0xfb, 0x82,
+	0x24, 0x0b, 0x39, 0x4f, 0x59, 0xaf, 0xc2, 0xef, 0x76, 0xc8, 0xf3, 0xa7, 0x1f, 0x85, 0xe0, 0x7e,
+	0xfa, 0x1e, 0xa7, 0xc8, 0xc8, 0x7f, 0xaa, 0xc0, 0x2a, 0x2b, 0x25, 0x06, 0x5a, 0x79, 0xa3, 0x7d,
+	0xab, 0x91, 0xad, 0x27, 0xc8, 0x0b, 0x15, 0xc9, 0x0a, 0xaa, 0x8e, 0x12, 0x05, 0x31, 0xd0, 0x2f,
+	0x84, 0x9b, 0xf4, 0x37, 0xfc, 0xae, 0xa4, 0x7d, 0x5a, 0x7f, 0x87, 0xf4, 0xaa, 0x99, 0xde, 0xe1,
+	0x83, 0xab, 0x20, 0x3a, 0x2f, 0xb9, 0xd2, 0xa3, 0xf9, 0xfa, 0x93, 0xe8, 0xb5, 0xe8, 0x29, 0xfa,
+	0x89, 0xf4, 0xe0, 0xbe, 0xae, 0xe0, 0xf3, 0xf2, 0xe0, 0x24, 0x5a, 0xfc, 0x12, 0x67, 0xeb, 0x3c,
+	0x3a, 0x37, 0xca, 0x7e, 0x7d, 0x4e, 0xfd, 0x2b, 0x05, 0x16, 0x79, 0xc5, 0x91, 0xe8, 0x74, 0x35,
+	0x52, 0x75, 0xac, 0x12, 0x2d, 0xb9, 0xea, 0x95, 0x0c, 0x7b, 0xf0, 0x3a, 0xe7, 0x0e, 0xa3, 0xb5,
+	0xd1, 0xde, 0x25, 0xd6, 0xa3, 0x67, 0x42, 0x6a, 0x7d, 0xcd, 0xb0, 0x13, 0xda, 0xd5, 0xd0, 0x96,
+	0x5a, 0x90, 0x38, 0x50, 0xe0, 0x62, 0x1d, 0x31, 0x1b, 0xd6, 0x42, 0x3e, 0xfa, 0xad, 0x02, 0xe8,
+	0x60, 0x90, 0x89, 0x13, 0x10, 0x3b, 0x11, 0x83, 0x32, 0x06, 0x54, 0x47, 0x32, 0xc8, 0x9c, 0xd0,
+	0x86, 0x85, 0x5d, 0x42, 0x93, 0x9d, 0xbc, 0x51, 0x52, 0x1a, 0xdf, 0x91, 0x4c, 0xe0, 0x88, 0xbd,
+	0x91, 0x09, 0xe2, 0xf2, 0x81, 0x1c, 0xfd, 0x5c, 0x81, 0x95, 0x9d, 0xc7, 0xae, 0xe3, 0xd1, 0xfe,
+	0xa6, 0xd3, 0x95, 0x34, 0xd8, 0x03, 0xb3, 0xa9, 0x4f, 0x72, 0xb6, 0xbe, 0xc6, 0x5f, 0x98, 0xe6,
+	0x57, 0x92, 0xfc, 0xb0, 0x44, 0xe1, 0x78, 0x94, 0x49, 0xe2, 0x4b, 0xf6, 0xd5, 0x67, 0x67, 0x18,
+	0x67, 0x59, 0x89, 0x65, 0x12, 0xd4, 0x28, 0xc6, 0xcc, 0x4e, 0xc0, 0xd8, 0x17, 0x0a, 0xac, 0xca,
+	0xde, 0xc3, 0x09, 0x65, 0xc6, 0xf7, 0x66, 0xe2, 0x4a, 0x96, 0xfa, 0x78, 0xb5, 0x8f, 0x2b, 0x4f,
+	0xe0, 0x62, 0x6c, 0x7d, 0xa5, 0xc0, 0xea, 0x2e, 0xa1, 0xc3, 0x7a, 0x1d, 0xa9, 0x83, 0x53, 0xe6,
+	0x9e, 0x01, 0xbe, 0xcc, 0x19, 0xbb, 0x88, 0x2e, 0x8c, 0x0a, 0x01, 0xed, 0x90, 0x8b, 0xdf, 0x28,
+	0xb0, 0x1c, 0x8b, 0x01, 0xd1, 0x0b, 0x7d, 0x6a, 0xf6, 0x6a, 0xe9, 0xde, 0x12, 0x03, 0xc4, 0xc1,
+	0x9b, 0x15, 0xba, 0x34, 0xca, 0xe3, 0x04, 0x8b, 0xe1, 0x6b, 0xff, 0x56, 0xf9, 0x83, 0x52, 0x88,
+	0xef, 0x41, 0x81, 0xbb, 0xdb, 0xf5, 0x7f, 0x0d, 0x00, 0xed, 0x4a, 0x0b, 0x90, 0xcd, 0x31, 0x00,
+	0x00,
 }
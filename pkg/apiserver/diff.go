@@ -0,0 +1,189 @@
+package apiserver
+
+import (
+	"sort"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	TaskAdded    = "ADDED"
+	TaskRemoved  = "REMOVED"
+	TaskModified = "MODIFIED"
+)
+
+// DiffWorkflowSpecs computes the changes from workflow spec a to workflow spec b.
+//
+// The fields and tasks in the diff are sorted, to ensure that the same two specs always result in the same diff.
+func DiffWorkflowSpecs(a, b *types.WorkflowSpec) *WorkflowDiff {
+	diff := &WorkflowDiff{}
+	if a.GetApiVersion() != b.GetApiVersion() {
+		diff.Fields = append(diff.Fields, "apiVersion")
+	}
+	if a.GetOutputTask() != b.GetOutputTask() {
+		diff.Fields = append(diff.Fields, "outputTask")
+	}
+	if a.GetDescription() != b.GetDescription() {
+		diff.Fields = append(diff.Fields, "description")
+	}
+	if a.GetName() != b.GetName() {
+		diff.Fields = append(diff.Fields, "name")
+	}
+	if a.GetInternal() != b.GetInternal() {
+		diff.Fields = append(diff.Fields, "internal")
+	}
+	if a.GetPrewarm() != b.GetPrewarm() {
+		diff.Fields = append(diff.Fields, "prewarm")
+	}
+	if a.GetSuccessCondition() != b.GetSuccessCondition() {
+		diff.Fields = append(diff.Fields, "successCondition")
+	}
+	if a.GetCancelAbandonedTasks() != b.GetCancelAbandonedTasks() {
+		diff.Fields = append(diff.Fields, "cancelAbandonedTasks")
+	}
+	if !proto.Equal(a.GetCompletion(), b.GetCompletion()) {
+		diff.Fields = append(diff.Fields, "completion")
+	}
+	if a.GetRetryBudget() != b.GetRetryBudget() {
+		diff.Fields = append(diff.Fields, "retryBudget")
+	}
+	if a.GetSoftTimeoutPercentage() != b.GetSoftTimeoutPercentage() {
+		diff.Fields = append(diff.Fields, "softTimeoutPercentage")
+	}
+	if !stringsEqual(a.GetInputMiddleware(), b.GetInputMiddleware()) {
+		diff.Fields = append(diff.Fields, "inputMiddleware")
+	}
+	if !proto.Equal(&types.WorkflowSpec{Switches: a.GetSwitches()}, &types.WorkflowSpec{Switches: b.GetSwitches()}) {
+		diff.Fields = append(diff.Fields, "switches")
+	}
+	sort.Strings(diff.Fields)
+
+	taskIDs := map[string]struct{}{}
+	for id := range a.GetTasks() {
+		taskIDs[id] = struct{}{}
+	}
+	for id := range b.GetTasks() {
+		taskIDs[id] = struct{}{}
+	}
+	for _, id := range sortedKeys(taskIDs) {
+		taskA, inA := a.GetTasks()[id]
+		taskB, inB := b.GetTasks()[id]
+		switch {
+		case !inA:
+			diff.Tasks = append(diff.Tasks, &TaskDiff{TaskId: id, Change: TaskAdded})
+		case !inB:
+			diff.Tasks = append(diff.Tasks, &TaskDiff{TaskId: id, Change: TaskRemoved})
+		default:
+			if fields := diffTaskSpecs(taskA, taskB); len(fields) > 0 {
+				diff.Tasks = append(diff.Tasks, &TaskDiff{TaskId: id, Change: TaskModified, Fields: fields})
+			}
+		}
+	}
+	return diff
+}
+
+// diffTaskSpecs returns the sorted names of the fields that differ between the task specs. Changes to the inputs and
+// dependencies are reported per key; for example 'inputs.body' or 'requires.taskA'.
+func diffTaskSpecs(a, b *types.TaskSpec) []string {
+	var fields []string
+	if a.GetFunctionRef() != b.GetFunctionRef() {
+		fields = append(fields, "functionRef")
+	}
+	if a.GetAwait() != b.GetAwait() {
+		fields = append(fields, "await")
+	}
+	if !proto.Equal(a.GetOutput(), b.GetOutput()) {
+		fields = append(fields, "output")
+	}
+	if !proto.Equal(a.GetOutputHeaders(), b.GetOutputHeaders()) {
+		fields = append(fields, "outputHeaders")
+	}
+	if !proto.Equal(a.GetTimeout(), b.GetTimeout()) {
+		fields = append(fields, "timeout")
+	}
+	if a.GetExecutorType() != b.GetExecutorType() {
+		fields = append(fields, "executorType")
+	}
+	if a.GetOutputPath() != b.GetOutputPath() {
+		fields = append(fields, "outputPath")
+	}
+	if a.GetAffinity() != b.GetAffinity() {
+		fields = append(fields, "affinity")
+	}
+	if a.GetIdempotent() != b.GetIdempotent() {
+		fields = append(fields, "idempotent")
+	}
+	if a.GetPure() != b.GetPure() {
+		fields = append(fields, "pure")
+	}
+	if !redactionRulesEqual(a.GetRedact(), b.GetRedact()) {
+		fields = append(fields, "redact")
+	}
+	if !proto.Equal(a.GetRetry(), b.GetRetry()) {
+		fields = append(fields, "retry")
+	}
+
+	inputs := map[string]struct{}{}
+	for k := range a.GetInputs() {
+		inputs[k] = struct{}{}
+	}
+	for k := range b.GetInputs() {
+		inputs[k] = struct{}{}
+	}
+	for _, k := range sortedKeys(inputs) {
+		if !proto.Equal(a.GetInputs()[k], b.GetInputs()[k]) {
+			fields = append(fields, "inputs."+k)
+		}
+	}
+
+	requires := map[string]struct{}{}
+	for k := range a.GetRequires() {
+		requires[k] = struct{}{}
+	}
+	for k := range b.GetRequires() {
+		requires[k] = struct{}{}
+	}
+	for _, k := range sortedKeys(requires) {
+		paramsA, inA := a.GetRequires()[k]
+		paramsB, inB := b.GetRequires()[k]
+		if inA != inB || !proto.Equal(paramsA, paramsB) {
+			fields = append(fields, "requires."+k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func redactionRulesEqual(a, b []*types.RedactionRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
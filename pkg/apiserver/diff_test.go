@@ -0,0 +1,69 @@
+package apiserver
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffWorkflowSpecs(t *testing.T) {
+	a := types.NewWorkflowSpec()
+	a.AddTask("fetch", &types.TaskSpec{
+		FunctionRef: "fetch",
+		Inputs: map[string]*typedvalues.TypedValue{
+			"url": typedvalues.MustWrap("http://example.com"),
+		},
+	})
+	a.AddTask("parse", &types.TaskSpec{FunctionRef: "parse", Requires: types.Require("fetch")})
+	a.AddTask("unchanged", &types.TaskSpec{FunctionRef: "noop"})
+	a.SetOutput("parse")
+
+	b := types.NewWorkflowSpec()
+	b.AddTask("fetch", &types.TaskSpec{
+		FunctionRef: "fetch",
+		Inputs: map[string]*typedvalues.TypedValue{
+			"url":     typedvalues.MustWrap("http://example.org"),
+			"headers": typedvalues.MustWrap(map[string]interface{}{"accept": "text/html"}),
+		},
+	})
+	b.AddTask("unchanged", &types.TaskSpec{FunctionRef: "noop"})
+	b.AddTask("transform", &types.TaskSpec{FunctionRef: "transform", Requires: types.Require("fetch")})
+	b.SetOutput("transform")
+
+	diff := DiffWorkflowSpecs(a, b)
+	assert.Equal(t, []string{"outputTask"}, diff.Fields)
+	assert.Equal(t, []*TaskDiff{
+		{TaskId: "fetch", Change: TaskModified, Fields: []string{"inputs.headers", "inputs.url"}},
+		{TaskId: "parse", Change: TaskRemoved},
+		{TaskId: "transform", Change: TaskAdded},
+	}, diff.Tasks)
+
+	// The diff should be stable
+	assert.Equal(t, diff, DiffWorkflowSpecs(a, b))
+}
+
+func TestDiffWorkflowSpecs_Dependencies(t *testing.T) {
+	a := types.NewWorkflowSpec()
+	a.AddTask("a", &types.TaskSpec{FunctionRef: "noop"})
+	a.AddTask("b", &types.TaskSpec{FunctionRef: "noop", Requires: types.Require("a")})
+
+	b := types.NewWorkflowSpec()
+	b.AddTask("a", &types.TaskSpec{FunctionRef: "noop"})
+	b.AddTask("b", &types.TaskSpec{FunctionRef: "other"})
+
+	diff := DiffWorkflowSpecs(a, b)
+	assert.Empty(t, diff.Fields)
+	assert.Equal(t, []*TaskDiff{
+		{TaskId: "b", Change: TaskModified, Fields: []string{"functionRef", "requires.a"}},
+	}, diff.Tasks)
+}
+
+func TestDiffWorkflowSpecs_Equal(t *testing.T) {
+	a := types.NewWorkflowSpec()
+	a.AddTask("a", &types.TaskSpec{FunctionRef: "noop"})
+	diff := DiffWorkflowSpecs(a, a)
+	assert.Empty(t, diff.Fields)
+	assert.Empty(t, diff.Tasks)
+}
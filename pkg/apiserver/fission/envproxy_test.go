@@ -49,6 +49,11 @@ func (m *mockWorkflowClient) Delete(ctx context.Context, in *types.ObjectMetadat
 	return &empty.Empty{}, args.Error(1)
 }
 
+func (m *mockWorkflowClient) ClearQuarantine(ctx context.Context, in *types.ObjectMetadata, opts ...grpc.CallOption) (*empty.Empty, error) {
+	args := m.Called(in)
+	return &empty.Empty{}, args.Error(1)
+}
+
 func (m *mockWorkflowClient) Validate(ctx context.Context, in *types.WorkflowSpec, opts ...grpc.CallOption) (*empty.Empty, error) {
 	args := m.Called(in)
 	return &empty.Empty{}, args.Error(1)
@@ -58,6 +63,10 @@ func (m *mockWorkflowClient) Events(ctx context.Context, in *types.ObjectMetadat
 	panic("implement me")
 }
 
+func (m *mockWorkflowClient) Diff(ctx context.Context, in *apiserver.WorkflowDiffRequest, opts ...grpc.CallOption) (*apiserver.WorkflowDiff, error) {
+	panic("implement me")
+}
+
 func TestProxy_Specialize(t *testing.T) {
 	workflowServer := &mockWorkflowClient{}
 	workflowServer.On("CreateSync", mock.Anything).Return(&types.Workflow{
@@ -0,0 +1,213 @@
+package apiserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultHandoffDrainTimeout is the maximum time to wait for the in-flight tasks to finish before handing off the
+// invocations, if the request does not specify one.
+const DefaultHandoffDrainTimeout = 30 * time.Second
+
+// GetHandoffStatus returns whether the invocation controller owns the invocations, or has handed them off.
+func (as *Admin) GetHandoffStatus(ctx context.Context, _ *empty.Empty) (*HandoffStatus, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.reevaluator == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	return toHandoffStatus(as.reevaluator.HandoffStatus()), nil
+}
+
+// ExportControllerState drains the invocation controller, and releases its invocations. The returned state should be
+// imported by the controller instance that takes over the invocations, after which this instance can be shut down.
+func (as *Admin) ExportControllerState(ctx context.Context, req *HandoffRequest) (*ControllerSnapshot, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.reevaluator == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	if req.GetDrainTimeoutSeconds() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "drain timeout cannot be negative")
+	}
+	drainTimeout := time.Duration(req.GetDrainTimeoutSeconds() * float64(time.Second))
+	if drainTimeout == 0 {
+		drainTimeout = DefaultHandoffDrainTimeout
+	}
+	snapshot, err := as.reevaluator.ExportState(drainTimeout)
+	if err == controller.ErrHandoffDrainTimeout {
+		return nil, status.Errorf(codes.DeadlineExceeded, "failed to export the controller state: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to export the controller state: %v", err)
+	}
+	logrus.Warnf("Exported the controller state in handoff %s (admin API): %d invocation(s)", snapshot.HandoffID,
+		len(snapshot.Invocations))
+	return toControllerSnapshot(snapshot), nil
+}
+
+// ImportControllerState resumes the invocations of another controller instance in an invocation controller that is
+// in standby.
+func (as *Admin) ImportControllerState(ctx context.Context, req *ControllerSnapshot) (*HandoffStatus, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.reevaluator == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	if len(req.GetHandoffId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "handoff ID is required")
+	}
+	snapshot, err := fromControllerSnapshot(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid controller state: %v", err)
+	}
+	if err := as.reevaluator.ImportState(snapshot); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to import the controller state: %v", err)
+	}
+	logrus.Warnf("Imported the controller state of handoff %s (admin API): %d invocation(s)", snapshot.HandoffID,
+		len(snapshot.Invocations))
+	return toHandoffStatus(as.reevaluator.HandoffStatus()), nil
+}
+
+// ReclaimControllerState makes the invocation controller the owner of the invocations again.
+func (as *Admin) ReclaimControllerState(ctx context.Context, req *HandoffReclaim) (*HandoffStatus, error) {
+	if err := as.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if as.reevaluator == nil {
+		return nil, status.Error(codes.Unavailable, "no invocation controller is running")
+	}
+	if err := as.reevaluator.ReclaimState(req.GetHandoffId()); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to reclaim the invocations: %v", err)
+	}
+	logrus.Warnf("Reclaimed the invocations (admin API): handoff=%s", req.GetHandoffId())
+	return toHandoffStatus(as.reevaluator.HandoffStatus()), nil
+}
+
+func toHandoffStatus(handoff controller.HandoffStatus) *HandoffStatus {
+	return &HandoffStatus{
+		State:     string(handoff.State),
+		HandoffId: handoff.HandoffID,
+		Epoch:     handoff.Epoch,
+	}
+}
+
+func toControllerSnapshot(snapshot *controller.Snapshot) *ControllerSnapshot {
+	result := &ControllerSnapshot{
+		HandoffId: snapshot.HandoffID,
+		Epoch:     snapshot.Epoch,
+		CreatedAt: snapshot.CreatedAt.Format(time.RFC3339Nano),
+		Queued:    snapshot.Queued,
+	}
+	for _, state := range snapshot.Invocations {
+		entry := &InvocationControllerState{
+			Id:                    state.ID,
+			StartedTasks:          state.StartedTasks,
+			ErrorCount:            int32(state.ErrorCount),
+			TaskErrors:            map[string]int32{},
+			NoopEvals:             int32(state.NoopEvals),
+			Prewarmed:             state.Prewarmed,
+			CompletedEarly:        state.CompletedEarly,
+			SoftTimeoutExceeded:   state.SoftTimeoutExceeded,
+			LockKey:               state.LockKey,
+			PendingPolls:          map[string]string{},
+			EvalCount:             state.Stats.EvalCount,
+			LastQueueWaitSeconds:  state.Stats.LastQueueWait.Seconds(),
+			TotalQueueWaitSeconds: state.Stats.TotalQueueWait.Seconds(),
+		}
+		if !state.Stats.LastEvaluatedAt.IsZero() {
+			entry.LastEvaluatedAt = state.Stats.LastEvaluatedAt.Format(time.RFC3339Nano)
+		}
+		for taskID, count := range state.TaskErrors {
+			entry.TaskErrors[taskID] = int32(count)
+		}
+		for taskID, due := range state.PendingPolls {
+			entry.PendingPolls[taskID] = due.Format(time.RFC3339Nano)
+		}
+		result.Invocations = append(result.Invocations, entry)
+	}
+	for _, lock := range snapshot.Locks {
+		result.Locks = append(result.Locks, &ConcurrencyLock{
+			WorkflowId: lock.WorkflowID,
+			Key:        lock.Key,
+			Holder:     lock.Holder,
+			Queued:     lock.Queued,
+		})
+	}
+	return result
+}
+
+func fromControllerSnapshot(snapshot *ControllerSnapshot) (*controller.Snapshot, error) {
+	result := &controller.Snapshot{
+		HandoffID: snapshot.GetHandoffId(),
+		Epoch:     snapshot.GetEpoch(),
+		Queued:    snapshot.GetQueued(),
+	}
+	if createdAt := snapshot.GetCreatedAt(); len(createdAt) > 0 {
+		ts, err := time.Parse(time.RFC3339Nano, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid createdAt: %v", err)
+		}
+		result.CreatedAt = ts
+	}
+	for _, state := range snapshot.GetInvocations() {
+		entry := controller.InvocationSnapshot{
+			ID:                  state.GetId(),
+			StartedTasks:        state.GetStartedTasks(),
+			ErrorCount:          int(state.GetErrorCount()),
+			TaskErrors:          map[string]int{},
+			NoopEvals:           int(state.GetNoopEvals()),
+			Prewarmed:           state.GetPrewarmed(),
+			CompletedEarly:      state.GetCompletedEarly(),
+			SoftTimeoutExceeded: state.GetSoftTimeoutExceeded(),
+			LockKey:             state.GetLockKey(),
+			PendingPolls:        map[string]time.Time{},
+			Stats: ctrl.ControllerStats{
+				EvalCount:      state.GetEvalCount(),
+				LastQueueWait:  time.Duration(state.GetLastQueueWaitSeconds() * float64(time.Second)),
+				TotalQueueWait: time.Duration(state.GetTotalQueueWaitSeconds() * float64(time.Second)),
+			},
+		}
+		if len(entry.ID) == 0 {
+			return nil, fmt.Errorf("invocation ID is required")
+		}
+		if lastEvaluatedAt := state.GetLastEvaluatedAt(); len(lastEvaluatedAt) > 0 {
+			ts, err := time.Parse(time.RFC3339Nano, lastEvaluatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("invalid lastEvaluatedAt of invocation %s: %v", entry.ID, err)
+			}
+			entry.Stats.LastEvaluatedAt = ts
+		}
+		for taskID, count := range state.GetTaskErrors() {
+			entry.TaskErrors[taskID] = int(count)
+		}
+		for taskID, due := range state.GetPendingPolls() {
+			ts, err := time.Parse(time.RFC3339Nano, due)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pending poll of task %s of invocation %s: %v", taskID, entry.ID, err)
+			}
+			entry.PendingPolls[taskID] = ts
+		}
+		result.Invocations = append(result.Invocations, entry)
+	}
+	for _, lock := range snapshot.GetLocks() {
+		result.Locks = append(result.Locks, controller.ConcurrencyLock{
+			WorkflowID: lock.GetWorkflowId(),
+			Key:        lock.GetKey(),
+			Holder:     lock.GetHolder(),
+			Queued:     lock.GetQueued(),
+		})
+	}
+	return result, nil
+}
@@ -3,6 +3,8 @@ package httpclient
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/fission/fission-workflows/pkg/apiserver"
 	"github.com/fission/fission-workflows/pkg/types"
@@ -39,6 +41,34 @@ func (api *InvocationAPI) Cancel(ctx context.Context, id string) error {
 	return callWithJSON(ctx, http.MethodDelete, api.formatURL("/invocation/"+id), nil, nil)
 }
 
+func (api *InvocationAPI) ReplayFailedTasks(ctx context.Context, id string,
+	functionOverrides map[string]string) (*apiserver.ReplayFailedTasksResponse, error) {
+	result := &apiserver.ReplayFailedTasksResponse{}
+	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/"+id+"/replay"),
+		&apiserver.ReplayFailedTasksRequest{Id: id, FunctionOverrides: functionOverrides}, result)
+	return result, err
+}
+
+func (api *InvocationAPI) Purge(ctx context.Context, req *apiserver.InvocationPurgeRequest) (
+	*apiserver.InvocationPurgeResult, error) {
+	result := &apiserver.InvocationPurgeResult{}
+	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/purge"), req, result)
+	return result, err
+}
+
+func (api *InvocationAPI) Archive(ctx context.Context, req *apiserver.InvocationArchiveRequest) (
+	*apiserver.InvocationArchiveResult, error) {
+	result := &apiserver.InvocationArchiveResult{}
+	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/archive"), req, result)
+	return result, err
+}
+
+func (api *InvocationAPI) Restore(ctx context.Context, id string) (*types.WorkflowInvocation, error) {
+	result := &types.WorkflowInvocation{}
+	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/"+id+"/restore"), nil, result)
+	return result, err
+}
+
 func (api *InvocationAPI) List(ctx context.Context) (*apiserver.WorkflowInvocationList, error) {
 	result := &apiserver.WorkflowInvocationList{}
 	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation"), nil, result)
@@ -51,6 +81,40 @@ func (api *InvocationAPI) Get(ctx context.Context, id string) (*types.WorkflowIn
 	return result, err
 }
 
+// GetTruncated gets the invocation, of which the outputs of the tasks that exceed maxOutputBytes are truncated.
+func (api *InvocationAPI) GetTruncated(ctx context.Context, id string, maxOutputBytes int64) (*types.WorkflowInvocation,
+	error) {
+	result := &types.WorkflowInvocation{}
+	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id+"?maxOutputBytes="+
+		strconv.FormatInt(maxOutputBytes, 10)), nil, result)
+	return result, err
+}
+
+// GetTask gets a task of the invocation, including its full output.
+func (api *InvocationAPI) GetTask(ctx context.Context, id string, taskID string) (*types.TaskInvocation, error) {
+	result := &types.TaskInvocation{}
+	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id+"/task?taskId="+url.QueryEscape(taskID)),
+		nil, result)
+	return result, err
+}
+
+func (api *InvocationAPI) GetStatuses(ctx context.Context, ids []string) (*apiserver.InvocationStatusList, error) {
+	result := &apiserver.InvocationStatusList{}
+	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/statuses"),
+		&apiserver.InvocationStatusQuery{Ids: ids}, result)
+	return result, err
+}
+
+func (api *InvocationAPI) GetGroup(ctx context.Context, groupID string) (*apiserver.InvocationGroup, error) {
+	result := &apiserver.InvocationGroup{}
+	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/group/"+groupID), nil, result)
+	return result, err
+}
+
+func (api *InvocationAPI) CancelGroup(ctx context.Context, groupID string) error {
+	return callWithJSON(ctx, http.MethodDelete, api.formatURL("/group/"+groupID), nil, nil)
+}
+
 func (api *InvocationAPI) Validate(ctx context.Context, spec *types.WorkflowInvocationSpec) error {
 	return callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/validate"), spec, nil)
 }
@@ -60,3 +124,15 @@ func (api *InvocationAPI) Events(ctx context.Context, id string) (*apiserver.Obj
 	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id+"/events"), nil, result)
 	return result, err
 }
+
+func (api *InvocationAPI) GetTimeline(ctx context.Context, id string) (*apiserver.InvocationTimeline, error) {
+	result := &apiserver.InvocationTimeline{}
+	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id+"/timeline"), nil, result)
+	return result, err
+}
+
+func (api *InvocationAPI) GetResolvedWorkflow(ctx context.Context, id string) (*types.WorkflowSpec, error) {
+	result := &types.WorkflowSpec{}
+	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id+"/workflow"), nil, result)
+	return result, err
+}
@@ -2,6 +2,7 @@ package httpclient
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/fission/fission-workflows/pkg/apiserver"
@@ -50,8 +51,19 @@ func (api *WorkflowAPI) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+func (api *WorkflowAPI) ClearQuarantine(ctx context.Context, id string) error {
+	return callWithJSON(ctx, http.MethodPost, api.formatURL("/workflow/"+id+"/quarantine/clear"), nil, nil)
+}
+
 func (api *WorkflowAPI) Events(ctx context.Context, id string) (*apiserver.ObjectEvents, error) {
 	result := &apiserver.ObjectEvents{}
 	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/workflow/"+id+"/events"), nil, result)
 	return result, err
 }
+
+func (api *WorkflowAPI) Diff(ctx context.Context, id string, versionA, versionB int32) (*apiserver.WorkflowDiff, error) {
+	result := &apiserver.WorkflowDiff{}
+	err := callWithJSON(ctx, http.MethodGet, api.formatURL(fmt.Sprintf("/workflow/%s/diff?versionA=%d&versionB=%d",
+		id, versionA, versionB)), nil, result)
+	return result, err
+}
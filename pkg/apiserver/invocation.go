@@ -3,10 +3,14 @@ package apiserver
 import (
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/api"
 	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/auth"
+	"github.com/fission/fission-workflows/pkg/controller"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/fnenv"
 	workflowFnenv "github.com/fission/fission-workflows/pkg/fnenv/workflows"
@@ -16,6 +20,8 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Invocation is responsible for all functionality related to managing invocations.
@@ -25,18 +31,54 @@ type Invocation struct {
 	workflows   *store.Workflows
 	fnenv       *workflowFnenv.Runtime
 	backend     fes.Backend
+	authorizer  auth.Authorizer
+	resolver    fnenv.Resolver
+	purger      *api.Purger
+	archiver    *api.Archiver
+
+	// awaitWorkflow is the maximum duration to wait for the workflow of an invocation to be created and to become
+	// ready. If 0, invocations of workflows that do not exist are rejected.
+	awaitWorkflow time.Duration
 }
 
-func NewInvocation(api *api.Invocation, invocations *store.Invocations, workflows *store.Workflows, backend fes.Backend) WorkflowInvocationAPIServer {
+func NewInvocation(invocationAPI *api.Invocation, invocations *store.Invocations, workflows *store.Workflows,
+	backend fes.Backend) *Invocation {
 	return &Invocation{
-		api:         api,
+		api:         invocationAPI,
 		invocations: invocations,
 		workflows:   workflows,
-		fnenv:       workflowFnenv.NewRuntime(api, invocations, workflows),
+		fnenv:       workflowFnenv.NewRuntime(invocationAPI, invocations, workflows),
 		backend:     backend,
+		purger:      api.NewPurger(backend, invocations, api.DefaultPurgeRate),
 	}
 }
 
+// SetAuthorizer sets the authorizer that decides whether the principal of a request is allowed to invoke or cancel
+// workflows. If nil, all actions are allowed.
+func (gi *Invocation) SetAuthorizer(authorizer auth.Authorizer) {
+	gi.authorizer = authorizer
+}
+
+// SetDeferredBinding allows invocations of workflows that do not exist yet: their workflows are awaited to be created
+// and to become ready for up to the timeout, before the invocations are created. If the timeout is 0, which is the
+// default, invocations of workflows that do not exist are rejected immediately with a NotFound error.
+func (gi *Invocation) SetDeferredBinding(timeout time.Duration) {
+	gi.awaitWorkflow = timeout
+	gi.fnenv.SetDeferredBinding(timeout > 0)
+}
+
+// SetArchiver sets the archiver of finished invocations, which enables the archive and restore APIs. The statuses of
+// archived invocations are hydrated from the archive. If nil, archiving invocations is not supported.
+func (gi *Invocation) SetArchiver(archiver *api.Archiver) {
+	gi.archiver = archiver
+}
+
+// SetResolver sets the resolver of the functions that override the functions of replayed tasks. If nil, the function
+// references are parsed, but not resolved.
+func (gi *Invocation) SetResolver(resolver fnenv.Resolver) {
+	gi.resolver = resolver
+}
+
 func (gi *Invocation) Validate(ctx context.Context, spec *types.WorkflowInvocationSpec) (*empty.Empty, error) {
 	err := validate.WorkflowInvocationSpec(spec)
 	if err != nil {
@@ -47,12 +89,18 @@ func (gi *Invocation) Validate(ctx context.Context, spec *types.WorkflowInvocati
 
 func (gi *Invocation) Invoke(ctx context.Context, spec *types.WorkflowInvocationSpec) (*types.ObjectMetadata, error) {
 	// TODO go through same runtime as InvokeSync
-	// Check if the workflow required by the invocation exists
+	// Check if the workflow required by the invocation exists, before the invocation is created.
 	wf, err := gi.workflows.GetWorkflow(spec.GetWorkflowId())
+	if (err != nil && fes.ErrEntityNotFound.Is(err)) || (err == nil && wf == nil) {
+		wf, err = gi.fnenv.ResolveWorkflow(ctx, spec.GetWorkflowId(), gi.awaitWorkflow)
+	}
 	if err != nil {
-		return nil, err
+		return nil, toErrorStatus(err)
 	}
 	spec.Workflow = wf
+	if err := gi.authorizeInvoke(ctx, spec); err != nil {
+		return nil, err
+	}
 
 	eventID, err := gi.api.Invoke(spec, api.WithContext(ctx))
 	if err != nil {
@@ -63,7 +111,10 @@ func (gi *Invocation) Invoke(ctx context.Context, spec *types.WorkflowInvocation
 }
 
 func (gi *Invocation) InvokeSync(ctx context.Context, spec *types.WorkflowInvocationSpec) (*types.WorkflowInvocation, error) {
-	wfi, err := gi.fnenv.InvokeWorkflow(spec, fnenv.WithContext(ctx))
+	if err := gi.authorizeInvoke(ctx, spec); err != nil {
+		return nil, err
+	}
+	wfi, err := gi.fnenv.InvokeWorkflow(spec, fnenv.WithContext(ctx), fnenv.AwaitWorkflow(gi.awaitWorkflow))
 	if err != nil {
 		return nil, toErrorStatus(err)
 	}
@@ -71,7 +122,10 @@ func (gi *Invocation) InvokeSync(ctx context.Context, spec *types.WorkflowInvoca
 }
 
 func (gi *Invocation) Cancel(ctx context.Context, objectMetadata *types.ObjectMetadata) (*empty.Empty, error) {
-	err := gi.api.Cancel(objectMetadata.GetId())
+	if err := gi.authorizeCancel(ctx, objectMetadata.GetId()); err != nil {
+		return nil, err
+	}
+	err := gi.api.Cancel(objectMetadata.GetId(), api.WithContext(ctx))
 	if err != nil {
 		return nil, toErrorStatus(err)
 	}
@@ -79,14 +133,353 @@ func (gi *Invocation) Cancel(ctx context.Context, objectMetadata *types.ObjectMe
 	return &empty.Empty{}, nil
 }
 
+// Get returns the invocation. If the max-output-bytes metadata is set, the outputs of the tasks that exceed it are
+// truncated in the response.
 func (gi *Invocation) Get(ctx context.Context, objectMetadata *types.ObjectMetadata) (*types.WorkflowInvocation, error) {
-	wi, err := gi.invocations.GetInvocation(objectMetadata.GetId())
+	maxBytes, err := maxOutputBytes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wi, err := gi.getInvocation(objectMetadata.GetId())
 	if err != nil {
 		return nil, toErrorStatus(err)
 	}
+	if truncated := truncateOutputs(wi.GetStatus(), maxBytes); truncated != wi.GetStatus() {
+		wiCopy := *wi
+		wiCopy.Status = truncated
+		return &wiCopy, nil
+	}
 	return wi, nil
 }
 
+// GetTask returns a task of the invocation, including its full output.
+func (gi *Invocation) GetTask(ctx context.Context, req *TaskRequest) (*types.TaskInvocation, error) {
+	wi, err := gi.getInvocation(req.GetId())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	task, ok := wi.GetStatus().GetTasks()[req.GetTaskId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "task %s not found in invocation %s", req.GetTaskId(), req.GetId())
+	}
+	return task, nil
+}
+
+// GetStatuses returns the statuses of the requested invocations.
+//
+// Each status is a snapshot of the invocation as stored in the cache, similar to Get. Invocations that could not be
+// retrieved are reported in the corresponding result, rather than failing the entire request. The outputs of the tasks
+// are truncated to DefaultMaxOutputBytes, unless the query specifies otherwise; use GetTask for the full output.
+func (gi *Invocation) GetStatuses(ctx context.Context, query *InvocationStatusQuery) (*InvocationStatusList, error) {
+	maxBytes := query.GetMaxOutputBytes()
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxOutputBytes
+	}
+	results := make([]*InvocationStatusResult, len(query.GetIds()))
+	for i, id := range query.GetIds() {
+		result := &InvocationStatusResult{Id: id}
+		wi, err := gi.getInvocation(id)
+		if err != nil {
+			result.Error = err.Error()
+		} else if wi == nil {
+			result.Error = fmt.Sprintf("invocation %s not found", id)
+		} else {
+			result.Status = truncateOutputs(wi.GetStatus(), maxBytes)
+		}
+		results[i] = result
+	}
+	return &InvocationStatusList{Statuses: results}, nil
+}
+
+// GetGroup returns the members of the invocation group, along with the progress of the group computed from the
+// statuses of the members.
+func (gi *Invocation) GetGroup(ctx context.Context, md *types.ObjectMetadata) (*InvocationGroup, error) {
+	members, err := gi.getGroupMembers(md.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	group := &InvocationGroup{
+		Id:         md.GetId(),
+		Progress:   map[string]int32{},
+		Finished:   true,
+		Successful: true,
+	}
+	for _, member := range members {
+		group.Invocations = append(group.Invocations, member.ID())
+		group.Progress[member.GetStatus().GetStatus().String()]++
+		group.Finished = group.Finished && member.GetStatus().Finished()
+		group.Successful = group.Successful && member.GetStatus().Successful()
+	}
+	sort.Strings(group.Invocations)
+	return group, nil
+}
+
+// CancelGroup cancels all member invocations of the group that have not finished yet. A failure to cancel a member
+// does not stop the other members from being canceled; the failures are reported together once all members have been
+// attempted.
+func (gi *Invocation) CancelGroup(ctx context.Context, md *types.ObjectMetadata) (*empty.Empty, error) {
+	members, err := gi.getGroupMembers(md.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []string
+	code := codes.OK
+	for _, member := range members {
+		if member.GetStatus().Finished() {
+			continue
+		}
+		err := gi.authorizeCancel(ctx, member.ID())
+		if err == nil {
+			if err = gi.api.Cancel(member.ID(), api.WithContext(ctx)); err != nil {
+				err = toErrorStatus(err)
+			}
+		}
+		if err == nil {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", member.ID(), status.Convert(err).Message()))
+		// The aggregated error only keeps the code of the failures if they all share it.
+		if memberCode := status.Code(err); code == codes.OK {
+			code = memberCode
+		} else if code != memberCode {
+			code = codes.Unknown
+		}
+	}
+	if len(failures) > 0 {
+		return nil, status.Errorf(code, "failed to cancel members of group %s: %s", md.GetId(),
+			strings.Join(failures, "; "))
+	}
+	return &empty.Empty{}, nil
+}
+
+// ReplayFailedTasks executes the failed tasks of the finished invocation again, along with the tasks that depend on
+// them. The function overrides are resolved before the invocation is replayed.
+func (gi *Invocation) ReplayFailedTasks(ctx context.Context, req *ReplayFailedTasksRequest) (*ReplayFailedTasksResponse,
+	error) {
+	if err := gi.authorize(ctx, auth.ActionReplay, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	var fnRefs map[string]*types.FnRef
+	for taskID, fn := range req.GetFunctionOverrides() {
+		var fnRef types.FnRef
+		var err error
+		if gi.resolver != nil {
+			fnRef, err = gi.resolver.Resolve(fn)
+		} else {
+			fnRef, err = types.ParseFnRef(fn)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to resolve function '%s' of task '%s': %v", fn,
+				taskID, err)
+		}
+		if fnRefs == nil {
+			fnRefs = map[string]*types.FnRef{}
+		}
+		fnRefs[taskID] = &fnRef
+	}
+
+	taskIDs, err := gi.api.ReplayFailedTasks(req.GetId(), fnRefs, api.WithContext(ctx))
+	if err != nil {
+		switch err {
+		case api.ErrInvocationNotFinished, api.ErrNoFailedTasks:
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot replay invocation %s: %v", req.GetId(), err)
+		}
+		if fes.ErrEntityNotFound.Is(err) {
+			return nil, status.Errorf(codes.NotFound, "invocation %s not found", req.GetId())
+		}
+		return nil, toErrorStatus(err)
+	}
+	return &ReplayFailedTasksResponse{TaskIds: taskIDs}, nil
+}
+
+// Purge deletes the finished invocations that match the filter, or reports them in case of a dry run. Besides the purge
+// itself, each matching invocation is authorized, so that the purge only deletes the invocations that the principal is
+// allowed to purge, such as its own invocations.
+func (gi *Invocation) Purge(ctx context.Context, req *InvocationPurgeRequest) (*InvocationPurgeResult, error) {
+	if err := auth.Authorize(ctx, gi.authorizer, auth.ActionPurge, auth.Resource{
+		WorkflowID: req.GetWorkflowId(),
+	}); err != nil {
+		return nil, err
+	}
+	if len(req.GetOlderThan()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "olderThan is required")
+	}
+	olderThan, err := time.ParseDuration(req.GetOlderThan())
+	if err != nil || olderThan < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid olderThan '%s'", req.GetOlderThan())
+	}
+
+	result, err := gi.purger.Purge(ctx, api.PurgeFilter{
+		WorkflowID:     req.GetWorkflowId(),
+		Labels:         req.GetLabels(),
+		FinishedBefore: time.Now().Add(-olderThan),
+		DryRun:         req.GetDryRun(),
+		Authorize:      gi.authorizeMatches(ctx, auth.ActionPurge),
+	})
+	if err == api.ErrPurgeNotSupported {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil && result == nil {
+		return nil, toErrorStatus(err)
+	}
+	if err != nil {
+		logrus.Warnf("Purge was interrupted after %d invocations: %v", len(result.Purged), err)
+	}
+	return &InvocationPurgeResult{
+		Invocations: result.Purged,
+		Count:       int32(len(result.Purged)),
+		Skipped:     int32(result.Skipped),
+		DryRun:      req.GetDryRun(),
+	}, nil
+}
+
+// Archive moves the finished invocations that match the filter to the archive, or reports them in case of a dry run.
+// Like purges, archivals only move the matching invocations that the principal is allowed to archive.
+func (gi *Invocation) Archive(ctx context.Context, req *InvocationArchiveRequest) (*InvocationArchiveResult, error) {
+	if err := auth.Authorize(ctx, gi.authorizer, auth.ActionArchive, auth.Resource{
+		WorkflowID: req.GetWorkflowId(),
+	}); err != nil {
+		return nil, err
+	}
+	if gi.archiver == nil {
+		return nil, status.Error(codes.Unimplemented, "no archive has been configured")
+	}
+	if len(req.GetOlderThan()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "olderThan is required")
+	}
+	olderThan, err := time.ParseDuration(req.GetOlderThan())
+	if err != nil || olderThan < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid olderThan '%s'", req.GetOlderThan())
+	}
+
+	result, err := gi.archiver.Archive(ctx, api.PurgeFilter{
+		WorkflowID:     req.GetWorkflowId(),
+		Labels:         req.GetLabels(),
+		FinishedBefore: time.Now().Add(-olderThan),
+		DryRun:         req.GetDryRun(),
+		Authorize:      gi.authorizeMatches(ctx, auth.ActionArchive),
+	})
+	if err == api.ErrArchiveNotSupported {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil && result == nil {
+		return nil, toErrorStatus(err)
+	}
+	if err != nil {
+		logrus.Warnf("Archival was interrupted after %d invocations: %v", len(result.Purged), err)
+	}
+	return &InvocationArchiveResult{
+		Invocations: result.Purged,
+		Count:       int32(len(result.Purged)),
+		Skipped:     int32(result.Skipped),
+		DryRun:      req.GetDryRun(),
+	}, nil
+}
+
+// Restore moves the archived invocation back to the event store.
+func (gi *Invocation) Restore(ctx context.Context, md *types.ObjectMetadata) (*types.WorkflowInvocation, error) {
+	if err := gi.authorize(ctx, auth.ActionRestore, md.GetId()); err != nil {
+		return nil, err
+	}
+	if gi.archiver == nil {
+		return nil, status.Error(codes.Unimplemented, "no archive has been configured")
+	}
+	wi, err := gi.archiver.Restore(md.GetId())
+	if err == api.ErrNotArchived {
+		return nil, status.Errorf(codes.NotFound, "invocation %s has not been archived", md.GetId())
+	}
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	return wi, nil
+}
+
+// getInvocation returns the invocation from the invocation store. If the invocation cannot be found, it is hydrated
+// from the archive, if the invocation has been archived.
+func (gi *Invocation) getInvocation(invocationID string) (*types.WorkflowInvocation, error) {
+	wi, err := gi.invocations.GetInvocation(invocationID)
+	if gi.archiver == nil || (err == nil && wi != nil) || (err != nil && !fes.ErrEntityNotFound.Is(err)) {
+		return wi, err
+	}
+	archived, archiveErr := gi.archiver.Load(invocationID)
+	if archiveErr != nil {
+		logrus.Warnf("Failed to hydrate invocation %s from the archive: %v", invocationID, archiveErr)
+		return wi, err
+	}
+	if archived == nil {
+		return wi, err
+	}
+	return archived, nil
+}
+
+// authorizeInvoke checks whether the principal of the request is allowed to invoke the workflow. If so, the
+// principal is recorded as the owner of the invocation, replacing any owner label provided by the client.
+func (gi *Invocation) authorizeInvoke(ctx context.Context, spec *types.WorkflowInvocationSpec) error {
+	if err := auth.Authorize(ctx, gi.authorizer, auth.ActionInvoke, auth.Resource{
+		WorkflowID: spec.GetWorkflowId(),
+	}); err != nil {
+		return err
+	}
+	delete(spec.Labels, auth.LabelOwner)
+	if principal := auth.FromContext(ctx); principal.Authenticated() {
+		if spec.Labels == nil {
+			spec.Labels = map[string]string{}
+		}
+		spec.Labels[auth.LabelOwner] = principal.Subject
+	}
+	return nil
+}
+
+// authorizeCancel checks whether the principal of the request is allowed to cancel the invocation.
+func (gi *Invocation) authorizeCancel(ctx context.Context, invocationID string) error {
+	return gi.authorize(ctx, auth.ActionCancel, invocationID)
+}
+
+// authorize checks whether the principal of the request is allowed to perform the action on the existing invocation.
+// If the invocation cannot be found, it is authorized without its workflow and owner; the action fails regardless.
+func (gi *Invocation) authorize(ctx context.Context, action auth.Action, invocationID string) error {
+	wi, err := gi.getInvocation(invocationID)
+	if err != nil {
+		wi = nil
+	}
+	return auth.Authorize(ctx, gi.authorizer, action, invocationResource(invocationID, wi))
+}
+
+// authorizeMatches returns a filter that only accepts the invocations on which the principal of the request is allowed
+// to perform the bulk action.
+func (gi *Invocation) authorizeMatches(ctx context.Context, action auth.Action) func(*types.WorkflowInvocation) bool {
+	return func(wi *types.WorkflowInvocation) bool {
+		return auth.Authorize(ctx, gi.authorizer, action, invocationResource(wi.ID(), wi)) == nil
+	}
+}
+
+// invocationResource returns the resource of the invocation, which is nil if the invocation could not be found.
+func invocationResource(invocationID string, wi *types.WorkflowInvocation) auth.Resource {
+	resource := auth.Resource{InvocationID: invocationID}
+	if wi != nil {
+		resource.WorkflowID = wi.GetSpec().GetWorkflowId()
+		resource.Owner = wi.GetSpec().GetLabels()[auth.LabelOwner]
+	}
+	return resource
+}
+
+func (gi *Invocation) getGroupMembers(groupID string) ([]*types.WorkflowInvocation, error) {
+	if len(groupID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no group ID provided")
+	}
+	members, err := gi.invocations.GetGroupMembers(groupID)
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	if len(members) == 0 {
+		return nil, status.Errorf(codes.NotFound, "group %s not found", groupID)
+	}
+	return members, nil
+}
+
 func (gi *Invocation) List(ctx context.Context, query *InvocationListQuery) (*WorkflowInvocationList, error) {
 	var invocations []string
 	as := gi.invocations.List()
@@ -156,6 +549,38 @@ func (gi *Invocation) Events(ctx context.Context, md *types.ObjectMetadata) (*Ob
 	}, nil
 }
 
+// GetTimeline returns the timing breakdown of the tasks of the invocation, computed from the events of the
+// invocation and its task runs.
+func (gi *Invocation) GetTimeline(ctx context.Context, md *types.ObjectMetadata) (*InvocationTimeline, error) {
+	wi, err := gi.invocations.GetInvocation(md.GetId())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	invocationEvents, err := gi.backend.Get(projectors.NewInvocationAggregate(md.GetId()))
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	taskEvents := map[string][]*fes.Event{}
+	for taskID, task := range wi.GetStatus().GetTasks() {
+		events, err := gi.taskEvents(task.ID())
+		if err != nil {
+			return nil, toErrorStatus(fmt.Errorf("failed to fetch task events: %v", err))
+		}
+		taskEvents[taskID] = events
+	}
+	return computeTimeline(wi, invocationEvents, taskEvents), nil
+}
+
+// GetResolvedWorkflow returns the workflow definition of the invocation, with the defaults and inherited settings
+// applied as the controller applies them.
+func (gi *Invocation) GetResolvedWorkflow(ctx context.Context, md *types.ObjectMetadata) (*types.WorkflowSpec, error) {
+	wi, err := gi.invocations.GetInvocation(md.GetId())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	return controller.ResolveWorkflowSpec(wi), nil
+}
+
 func (gi *Invocation) taskEvents(taskRunID string) ([]*fes.Event, error) {
 	return gi.backend.Get(projectors.NewTaskRunAggregate(taskRunID))
 }
@@ -0,0 +1,207 @@
+package apiserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/archive"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/auth"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInvocation_Invoke_UnknownWorkflow(t *testing.T) {
+	backend := mem.NewBackend()
+	workflows := testutil.NewCache()
+	server := NewInvocation(api.NewInvocationAPI(backend), store.NewInvocationStore(testutil.NewCache()),
+		store.NewWorkflowsStore(workflows), backend)
+	spec := types.NewWorkflowInvocationSpec("unknown", time.Now().Add(time.Minute))
+
+	// By default, the invocation is rejected before it is created.
+	_, err := server.Invoke(context.Background(), spec)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, 0, backend.Len())
+
+	// With deferred binding, the invocation is created once the workflow has been created.
+	server.SetDeferredBinding(time.Second)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		err := workflows.Put(&types.Workflow{
+			Metadata: &types.ObjectMetadata{Id: "unknown"},
+			Status:   &types.WorkflowStatus{Status: types.WorkflowStatus_READY},
+		})
+		if err != nil {
+			panic(err)
+		}
+	}()
+	metadata, err := server.Invoke(context.Background(), spec)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, metadata.GetId())
+	assert.Equal(t, 1, backend.Len())
+}
+
+func TestInvocation_GetArchived(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	cache := testutil.NewCache()
+	invocations := store.NewInvocationStore(cache)
+	server := NewInvocation(invocationAPI, invocations, store.NewWorkflowsStore(testutil.NewCache()), backend)
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = types.NewWorkflow("wf")
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	assert.NoError(t, invocationAPI.Cancel(invocationID))
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+	assert.NoError(t, err)
+	entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+	assert.NoError(t, err)
+	assert.NoError(t, cache.Put(entity))
+	md := &types.ObjectMetadata{Id: invocationID}
+
+	// Without an archive, invocations cannot be archived.
+	_, err = server.Archive(context.Background(), &InvocationArchiveRequest{OlderThan: "0s"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	server.SetArchiver(api.NewArchiver(backend, invocations, archive.NewMemStore(), api.ArchiveConfig{}))
+	result, err := server.Archive(context.Background(), &InvocationArchiveRequest{OlderThan: "0s"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{invocationID}, result.GetInvocations())
+
+	// The status of the archived invocation is hydrated from the archive.
+	wi, err := server.Get(context.Background(), md)
+	assert.NoError(t, err)
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, wi.GetStatus().GetStatus())
+	invocationEvents, err = backend.Get(projectors.NewInvocationAggregate(invocationID))
+	assert.NoError(t, err)
+	assert.Empty(t, invocationEvents)
+
+	wi, err = server.Restore(context.Background(), md)
+	assert.NoError(t, err)
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, wi.GetStatus().GetStatus())
+	invocationEvents, err = backend.Get(projectors.NewInvocationAggregate(invocationID))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, invocationEvents)
+	_, err = server.Restore(context.Background(), md)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestInvocation_CancelGroup(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	cache := testutil.NewCache()
+	server := NewInvocation(invocationAPI, store.NewInvocationStore(cache), store.NewWorkflowsStore(testutil.NewCache()),
+		backend)
+	server.SetAuthorizer(auth.OwnerOnly{})
+
+	// Create a group of which the first member is owned by another principal.
+	project := func(invocationID string) *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	var members []string
+	for _, owner := range []string{"alice", "", "bob"} {
+		spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+		spec.Workflow = types.NewWorkflow("wf")
+		spec.GroupId = "group"
+		if len(owner) > 0 {
+			spec.Labels = map[string]string{auth.LabelOwner: owner}
+		}
+		invocationID, err := invocationAPI.Invoke(spec)
+		assert.NoError(t, err)
+		assert.NoError(t, cache.Put(project(invocationID)))
+		members = append(members, invocationID)
+	}
+
+	// The members that could be canceled are canceled, despite the failure to cancel the first member.
+	ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "bob"})
+	_, err := server.CancelGroup(ctx, &types.ObjectMetadata{Id: "group"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), members[0])
+	assert.NotContains(t, status.Convert(err).Message(), members[1])
+	assert.False(t, project(members[0]).GetStatus().Finished())
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, project(members[1]).GetStatus().GetStatus())
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, project(members[2]).GetStatus().GetStatus())
+}
+
+// newOwnedInvocations creates a finished invocation for each of the owners, and adds them to the cache.
+func newOwnedInvocations(t *testing.T, invocationAPI *api.Invocation, backend *mem.Backend, cache *testutil.Cache,
+	owners ...string) []string {
+	var invocationIDs []string
+	for _, owner := range owners {
+		spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+		spec.Workflow = types.NewWorkflow("wf")
+		spec.Labels = map[string]string{auth.LabelOwner: owner}
+		invocationID, err := invocationAPI.Invoke(spec)
+		assert.NoError(t, err)
+		assert.NoError(t, invocationAPI.Cancel(invocationID))
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		assert.NoError(t, cache.Put(entity))
+		invocationIDs = append(invocationIDs, invocationID)
+	}
+	return invocationIDs
+}
+
+func TestInvocation_PurgeOwnerOnly(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	cache := testutil.NewCache()
+	server := NewInvocation(invocationAPI, store.NewInvocationStore(cache), store.NewWorkflowsStore(testutil.NewCache()),
+		backend)
+	server.SetAuthorizer(auth.OwnerOnly{})
+	invocationIDs := newOwnedInvocations(t, invocationAPI, backend, cache, "alice", "bob")
+
+	// Bob can only purge his own invocations.
+	ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "bob"})
+	result, err := server.Purge(ctx, &InvocationPurgeRequest{OlderThan: "0s"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{invocationIDs[1]}, result.GetInvocations())
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationIDs[0]))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, invocationEvents)
+
+	ctx = auth.NewContext(context.Background(), &auth.Principal{Subject: "alice"})
+	result, err = server.Purge(ctx, &InvocationPurgeRequest{OlderThan: "0s"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{invocationIDs[0]}, result.GetInvocations())
+}
+
+func TestInvocation_ArchiveOwnerOnly(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	cache := testutil.NewCache()
+	invocations := store.NewInvocationStore(cache)
+	server := NewInvocation(invocationAPI, invocations, store.NewWorkflowsStore(testutil.NewCache()), backend)
+	server.SetAuthorizer(auth.OwnerOnly{})
+	server.SetArchiver(api.NewArchiver(backend, invocations, archive.NewMemStore(), api.ArchiveConfig{}))
+	invocationIDs := newOwnedInvocations(t, invocationAPI, backend, cache, "alice", "bob")
+
+	// Bob can only archive his own invocations...
+	bob := auth.NewContext(context.Background(), &auth.Principal{Subject: "bob"})
+	result, err := server.Archive(bob, &InvocationArchiveRequest{OlderThan: "0s"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{invocationIDs[1]}, result.GetInvocations())
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationIDs[0]))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, invocationEvents)
+
+	// ...and only restore his own archived invocations.
+	alice := auth.NewContext(context.Background(), &auth.Principal{Subject: "alice"})
+	_, err = server.Restore(alice, &types.ObjectMetadata{Id: invocationIDs[1]})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = server.Restore(bob, &types.ObjectMetadata{Id: invocationIDs[1]})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,240 @@
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/auth"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultStreamHeartbeat = 15 * time.Second
+
+	// Server-sent event types of the invocation stream.
+	StreamEventSnapshot = "snapshot"
+	StreamEventUpdate   = "update"
+	StreamEventEnd      = "end"
+
+	streamPathPrefix = "/invocation/"
+	streamPathSuffix = "/stream"
+)
+
+// InvocationSource provides the invocations and the updates to them that are streamed to clients.
+type InvocationSource interface {
+	GetInvocation(invocationID string) (*types.WorkflowInvocation, error)
+	GetInvocationUpdates() *store.InvocationSubscription
+}
+
+// InvocationStream streams the status updates of an invocation to HTTP clients as server-sent events
+// (text/event-stream), which allows browsers to follow invocations with an EventSource.
+//
+// A stream is served at /invocation/{id}/stream. It starts with a snapshot event containing the invocation, followed
+// by an update event for each change to the invocation. Once the invocation has finished, an end event is sent and
+// the stream is closed. In the meantime, heartbeats are sent to prevent proxies from closing idle connections.
+type InvocationStream struct {
+	invocations   InvocationSource
+	heartbeat     time.Duration
+	marshaler     *jsonpb.Marshaler
+	authenticator auth.Authenticator
+}
+
+// InvocationUpdate is the data of an update event, describing a single change to the invocation.
+type InvocationUpdate struct {
+	EventType string `json:"eventType"`
+
+	// Status is the status of the invocation after the change.
+	Status string `json:"status"`
+
+	// TaskID and Task are only set if the change concerns a task of the invocation.
+	TaskID string          `json:"taskId,omitempty"`
+	Task   json.RawMessage `json:"task,omitempty"`
+
+	// Error is only set if the invocation has failed.
+	Error string `json:"error,omitempty"`
+}
+
+func NewInvocationStream(invocations InvocationSource, heartbeat time.Duration) *InvocationStream {
+	if heartbeat <= 0 {
+		heartbeat = DefaultStreamHeartbeat
+	}
+	return &InvocationStream{
+		invocations: invocations,
+		heartbeat:   heartbeat,
+		marshaler:   &jsonpb.Marshaler{},
+	}
+}
+
+// SetAuthenticator sets the authenticator of the requests for the streams that are served by the Handler, as the
+// streams are not served through the gRPC API. It should be set before the Handler is created. If nil, the requests
+// are not authenticated.
+func (s *InvocationStream) SetAuthenticator(authenticator auth.Authenticator) {
+	s.authenticator = authenticator
+}
+
+// Handler returns a handler that serves the invocation streams, and passes all other requests on to next.
+func (s *InvocationStream) Handler(next http.Handler) http.Handler {
+	var stream http.Handler = s
+	if s.authenticator != nil {
+		stream = auth.HTTPHandler(s.authenticator, s)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := parseStreamPath(r.URL.Path); ok {
+			stream.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *InvocationStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	invocationID, ok := parseStreamPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before fetching the snapshot, to avoid missing the updates in between.
+	sub := s.invocations.GetInvocationUpdates()
+	if sub == nil {
+		http.Error(w, "invocation store does not support pubsub", http.StatusNotImplemented)
+		return
+	}
+	defer sub.Close()
+
+	invocation, err := s.invocations.GetInvocation(invocationID)
+	if err != nil {
+		if fes.ErrEntityNotFound.Is(err) {
+			http.Error(w, fmt.Sprintf("invocation %s not found", invocationID), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable response buffering in nginx
+	w.WriteHeader(http.StatusOK)
+
+	var seq int
+	send := func(event string, data []byte) error {
+		seq++
+		_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", seq, event, data)
+		flusher.Flush()
+		return err
+	}
+
+	snapshot, err := s.marshal(invocation)
+	if err != nil {
+		logrus.Warnf("Failed to marshal snapshot of invocation %s: %v", invocationID, err)
+		return
+	}
+	if err := send(StreamEventSnapshot, snapshot); err != nil {
+		return
+	}
+	if finished(invocation) {
+		send(StreamEventEnd, []byte(fmt.Sprintf("%q", invocation.GetStatus().GetStatus().String())))
+		return
+	}
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case msg := <-sub.Ch:
+			notification, err := sub.ToNotification(msg)
+			if err != nil {
+				logrus.Warnf("Failed to convert pubsub message to notification: %v", err)
+				continue
+			}
+			updated, ok := notification.Updated.(*types.WorkflowInvocation)
+			if !ok || updated.ID() != invocationID {
+				continue
+			}
+			update, err := s.marshalUpdate(notification.Event, updated)
+			if err != nil {
+				logrus.Warnf("Failed to marshal update of invocation %s: %v", invocationID, err)
+				continue
+			}
+			if err := send(StreamEventUpdate, update); err != nil {
+				return
+			}
+			if finished(updated) {
+				send(StreamEventEnd, []byte(fmt.Sprintf("%q", updated.GetStatus().GetStatus().String())))
+				return
+			}
+		case <-heartbeat.C:
+			// Comments are ignored by EventSource, but keep the connection alive.
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *InvocationStream) marshalUpdate(event *fes.Event, invocation *types.WorkflowInvocation) ([]byte, error) {
+	update := &InvocationUpdate{
+		EventType: event.GetType(),
+		Status:    invocation.GetStatus().GetStatus().String(),
+		Error:     invocation.GetStatus().GetError().GetMessage(),
+	}
+	if event.GetAggregate().GetType() == types.TypeTaskRun {
+		update.TaskID = event.GetAggregate().GetId()
+		if task, ok := invocation.TaskInvocation(update.TaskID); ok {
+			data, err := s.marshal(task)
+			if err != nil {
+				return nil, err
+			}
+			update.Task = data
+		}
+	}
+	return json.Marshal(update)
+}
+
+// marshal marshals the message to JSON on a single line, as required for the data field of server-sent events.
+func (s *InvocationStream) marshal(msg proto.Message) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := s.marshaler.Marshal(buf, msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func finished(invocation *types.WorkflowInvocation) bool {
+	status := invocation.GetStatus()
+	return status != nil && status.Finished()
+}
+
+func parseStreamPath(path string) (invocationID string, ok bool) {
+	if !strings.HasPrefix(path, streamPathPrefix) || !strings.HasSuffix(path, streamPathSuffix) {
+		return "", false
+	}
+	invocationID = strings.TrimSuffix(strings.TrimPrefix(path, streamPathPrefix), streamPathSuffix)
+	if len(invocationID) == 0 || strings.Contains(invocationID, "/") {
+		return "", false
+	}
+	return invocationID, true
+}
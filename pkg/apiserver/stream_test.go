@@ -0,0 +1,108 @@
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util/pubsub"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInvocationSource struct {
+	invocations map[string]*types.WorkflowInvocation
+	updates     chan pubsub.Msg
+}
+
+func (s *fakeInvocationSource) GetInvocation(invocationID string) (*types.WorkflowInvocation, error) {
+	invocation, ok := s.invocations[invocationID]
+	if !ok {
+		return nil, fes.ErrEntityNotFound
+	}
+	return invocation, nil
+}
+
+func (s *fakeInvocationSource) GetInvocationUpdates() *store.InvocationSubscription {
+	return &store.InvocationSubscription{Subscription: &pubsub.Subscription{Ch: s.updates}}
+}
+
+func newInvocation(id string, status types.WorkflowInvocationStatus_Status) *types.WorkflowInvocation {
+	return &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{Id: id},
+		Status: &types.WorkflowInvocationStatus{
+			Status: status,
+			Tasks: map[string]*types.TaskInvocation{
+				"foo": {
+					Metadata: &types.ObjectMetadata{Id: "foo"},
+					Status:   &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_SUCCEEDED},
+				},
+			},
+		},
+	}
+}
+
+func publish(t *testing.T, ch chan pubsub.Msg, aggregate fes.Aggregate, invocation *types.WorkflowInvocation,
+	payload proto.Message) {
+	event, err := fes.NewEvent(aggregate, payload)
+	assert.NoError(t, err)
+	parent := projectors.NewInvocationAggregate(invocation.ID())
+	event.Parent = &parent
+	ch <- fes.NewNotification(nil, invocation, event)
+}
+
+func TestInvocationStream(t *testing.T) {
+	source := &fakeInvocationSource{
+		invocations: map[string]*types.WorkflowInvocation{
+			"wi-1": newInvocation("wi-1", types.WorkflowInvocationStatus_IN_PROGRESS),
+		},
+		updates: make(chan pubsub.Msg, 10),
+	}
+	publish(t, source.updates, projectors.NewInvocationAggregate("wi-2"),
+		newInvocation("wi-2", types.WorkflowInvocationStatus_SUCCEEDED), &events.InvocationCompleted{})
+	publish(t, source.updates, projectors.NewTaskRunAggregate("foo"),
+		newInvocation("wi-1", types.WorkflowInvocationStatus_IN_PROGRESS), &events.TaskSucceeded{})
+	publish(t, source.updates, projectors.NewInvocationAggregate("wi-1"),
+		newInvocation("wi-1", types.WorkflowInvocationStatus_SUCCEEDED), &events.InvocationCompleted{})
+
+	stream := NewInvocationStream(source, 0)
+	resp := httptest.NewRecorder()
+	stream.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/invocation/wi-1/stream", nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+	var eventTypes []string
+	for _, line := range strings.Split(resp.Body.String(), "\n") {
+		if strings.HasPrefix(line, "event: ") {
+			eventTypes = append(eventTypes, strings.TrimPrefix(line, "event: "))
+		}
+	}
+	// The update of the other invocation should not be streamed.
+	assert.Equal(t, []string{StreamEventSnapshot, StreamEventUpdate, StreamEventUpdate, StreamEventEnd}, eventTypes)
+	assert.Contains(t, resp.Body.String(), `"taskId":"foo"`)
+	assert.Contains(t, resp.Body.String(), "data: \"SUCCEEDED\"\n\n")
+}
+
+func TestInvocationStream_NotFound(t *testing.T) {
+	source := &fakeInvocationSource{updates: make(chan pubsub.Msg)}
+	stream := NewInvocationStream(source, 0)
+	resp := httptest.NewRecorder()
+	stream.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/invocation/wi-1/stream", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestInvocationStream_Handler(t *testing.T) {
+	var passed bool
+	handler := NewInvocationStream(&fakeInvocationSource{}, 0).Handler(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			passed = true
+		}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/invocation/wi-1/events", nil))
+	assert.True(t, passed)
+}
@@ -0,0 +1,151 @@
+package apiserver
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// taskTimes contains the points in time of a task run, as derived from its events.
+type taskTimes struct {
+	started  time.Time
+	finished time.Time
+	status   string
+}
+
+// computeTimeline computes the timing breakdown of the tasks of the invocation from the events of the invocation and
+// the events of its task runs (keyed by task ID).
+//
+// For each task, the time is split into the time spent waiting for its dependencies to finish, the time spent
+// waiting to be started once its dependencies had finished, and the execution time. The critical path is the chain of
+// dependencies that ends at the task that finished last, following at each step the dependency that finished last.
+func computeTimeline(wi *types.WorkflowInvocation, invocationEvents []*fes.Event,
+	taskEvents map[string][]*fes.Event) *InvocationTimeline {
+	timeline := &InvocationTimeline{
+		Id:     wi.ID(),
+		Status: wi.GetStatus().GetStatus().String(),
+	}
+
+	var startedAt, finishedAt time.Time
+	for _, event := range invocationEvents {
+		ts, err := ptypes.Timestamp(event.GetTimestamp())
+		if err != nil {
+			continue
+		}
+		switch event.GetType() {
+		case events.EventInvocationCreated:
+			startedAt = ts
+		case events.EventInvocationCompleted, events.EventInvocationFailed, events.EventInvocationCanceled:
+			finishedAt = ts
+		}
+	}
+	timeline.StartedAt = formatTime(startedAt)
+	timeline.FinishedAt = formatTime(finishedAt)
+
+	times := map[string]*taskTimes{}
+	for taskID, taskRunEvents := range taskEvents {
+		tt := &taskTimes{}
+		for _, event := range taskRunEvents {
+			ts, err := ptypes.Timestamp(event.GetTimestamp())
+			if err != nil {
+				continue
+			}
+			switch event.GetType() {
+			case events.EventTaskStarted:
+				tt.started = ts
+			case events.EventTaskSucceeded:
+				tt.finished, tt.status = ts, types.TaskInvocationStatus_SUCCEEDED.String()
+			case events.EventTaskFailed:
+				tt.finished, tt.status = ts, types.TaskInvocationStatus_FAILED.String()
+			case events.EventTaskSkipped:
+				tt.finished, tt.status = ts, types.TaskInvocationStatus_SKIPPED.String()
+			}
+		}
+		if len(tt.status) == 0 && !tt.started.IsZero() {
+			tt.status = types.TaskInvocationStatus_IN_PROGRESS.String()
+		}
+		times[taskID] = tt
+	}
+
+	tasks := wi.Tasks()
+	timings := map[string]*TaskTiming{}
+	for taskID, tt := range times {
+		readyAt := startedAt
+		for depID := range tasks[taskID].GetSpec().GetRequires() {
+			if dep, ok := times[depID]; ok && dep.finished.After(readyAt) {
+				readyAt = dep.finished
+			}
+		}
+		timing := &TaskTiming{
+			TaskId:     taskID,
+			Status:     tt.status,
+			ReadyAt:    formatTime(readyAt),
+			StartedAt:  formatTime(tt.started),
+			FinishedAt: formatTime(tt.finished),
+		}
+		if !startedAt.IsZero() {
+			timing.DependencyWaitSeconds = nonNegative(readyAt.Sub(startedAt)).Seconds()
+		}
+		if !tt.started.IsZero() {
+			timing.QueueWaitSeconds = nonNegative(tt.started.Sub(readyAt)).Seconds()
+			if !tt.finished.IsZero() {
+				timing.ExecutionSeconds = nonNegative(tt.finished.Sub(tt.started)).Seconds()
+			}
+		}
+		timings[taskID] = timing
+		timeline.Tasks = append(timeline.Tasks, timing)
+	}
+	sort.Slice(timeline.Tasks, func(i, j int) bool {
+		a, b := times[timeline.Tasks[i].TaskId], times[timeline.Tasks[j].TaskId]
+		if !a.started.Equal(b.started) {
+			return !a.started.IsZero() && (b.started.IsZero() || a.started.Before(b.started))
+		}
+		return timeline.Tasks[i].TaskId < timeline.Tasks[j].TaskId
+	})
+
+	// Trace the critical path back from the task that finished last.
+	var last string
+	for taskID, tt := range times {
+		if !tt.finished.IsZero() && (len(last) == 0 || tt.finished.After(times[last].finished) ||
+			(tt.finished.Equal(times[last].finished) && taskID < last)) {
+			last = taskID
+		}
+	}
+	visited := map[string]bool{}
+	for current := last; len(current) > 0 && !visited[current]; {
+		visited[current] = true
+		timings[current].Critical = true
+		timeline.CriticalPath = append([]string{current}, timeline.CriticalPath...)
+		var next string
+		for depID := range tasks[current].GetSpec().GetRequires() {
+			dep, ok := times[depID]
+			if !ok || dep.finished.IsZero() {
+				continue
+			}
+			if len(next) == 0 || dep.finished.After(times[next].finished) ||
+				(dep.finished.Equal(times[next].finished) && depID < next) {
+				next = depID
+			}
+		}
+		current = next
+	}
+	return timeline
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
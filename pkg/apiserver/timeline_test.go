@@ -0,0 +1,94 @@
+package apiserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTimedEvent(eventType string, ts time.Time) *fes.Event {
+	pts, _ := ptypes.TimestampProto(ts)
+	return &fes.Event{Type: eventType, Timestamp: pts}
+}
+
+func TestComputeTimeline(t *testing.T) {
+	// fetchA and fetchB run in parallel; merge depends on both, of which fetchB finishes last.
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("fetchA", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("fetchB", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("merge", &types.TaskSpec{FunctionRef: "noop", Requires: types.Require("fetchA", "fetchB")})
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{}}
+	for taskID := range wfSpec.Tasks {
+		wfStatus.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{}}
+	}
+	wi := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus},
+		},
+		Status: &types.WorkflowInvocationStatus{Status: types.WorkflowInvocationStatus_SUCCEEDED},
+	}
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+	timeline := computeTimeline(wi, []*fes.Event{
+		newTimedEvent(events.EventInvocationCreated, at(0)),
+		newTimedEvent(events.EventInvocationCompleted, at(1000)),
+	}, map[string][]*fes.Event{
+		"fetchA": {newTimedEvent(events.EventTaskStarted, at(100)), newTimedEvent(events.EventTaskSucceeded, at(300))},
+		"fetchB": {newTimedEvent(events.EventTaskStarted, at(50)), newTimedEvent(events.EventTaskSucceeded, at(600))},
+		"merge":  {newTimedEvent(events.EventTaskStarted, at(700)), newTimedEvent(events.EventTaskSucceeded, at(900))},
+	})
+
+	assert.Equal(t, "wi", timeline.Id)
+	assert.Equal(t, formatTime(at(1000)), timeline.FinishedAt)
+	assert.Equal(t, []string{"fetchB", "merge"}, timeline.CriticalPath)
+	assert.Len(t, timeline.Tasks, 3)
+
+	// The tasks are ordered by their start time.
+	fetchB, fetchA, merge := timeline.Tasks[0], timeline.Tasks[1], timeline.Tasks[2]
+	assert.Equal(t, "fetchB", fetchB.TaskId)
+	assert.Equal(t, "fetchA", fetchA.TaskId)
+	assert.Equal(t, "merge", merge.TaskId)
+
+	assert.Equal(t, 0.0, fetchA.DependencyWaitSeconds)
+	assert.InDelta(t, 0.1, fetchA.QueueWaitSeconds, 1e-9)
+	assert.InDelta(t, 0.2, fetchA.ExecutionSeconds, 1e-9)
+	assert.False(t, fetchA.Critical)
+
+	assert.Equal(t, formatTime(at(600)), merge.ReadyAt)
+	assert.InDelta(t, 0.6, merge.DependencyWaitSeconds, 1e-9)
+	assert.InDelta(t, 0.1, merge.QueueWaitSeconds, 1e-9)
+	assert.InDelta(t, 0.2, merge.ExecutionSeconds, 1e-9)
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED.String(), merge.Status)
+	assert.True(t, merge.Critical)
+	assert.True(t, fetchB.Critical)
+}
+
+func TestComputeTimeline_InProgress(t *testing.T) {
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("slow", &types.TaskSpec{FunctionRef: "noop"})
+	wi := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec},
+		},
+	}
+	now := time.Now()
+	timeline := computeTimeline(wi, []*fes.Event{newTimedEvent(events.EventInvocationCreated, now)},
+		map[string][]*fes.Event{"slow": {newTimedEvent(events.EventTaskStarted, now)}})
+
+	assert.Empty(t, timeline.FinishedAt)
+	assert.Empty(t, timeline.CriticalPath)
+	assert.Len(t, timeline.Tasks, 1)
+	assert.Equal(t, types.TaskInvocationStatus_IN_PROGRESS.String(), timeline.Tasks[0].Status)
+	assert.Empty(t, timeline.Tasks[0].FinishedAt)
+	assert.Equal(t, 0.0, timeline.Tasks[0].ExecutionSeconds)
+}
@@ -0,0 +1,83 @@
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMaxOutputBytes is the size (in bytes) to which the task outputs are truncated in the responses that
+	// contain the statuses of multiple invocations, unless the request specifies otherwise.
+	DefaultMaxOutputBytes = 4096
+
+	// maxOutputBytesKey is the metadata key with which callers of Get can truncate the outputs of the tasks.
+	maxOutputBytesKey = "max-output-bytes"
+
+	// maxOutputBytesParam is the query parameter of the HTTP API that is forwarded as maxOutputBytesKey.
+	maxOutputBytesParam = "maxOutputBytes"
+)
+
+// ForwardQueryMetadata forwards the query parameters of the HTTP API that are not part of the requests, such as
+// maxOutputBytes, to the gRPC API as metadata. It is intended to be used with runtime.WithMetadata of the gateway.
+func ForwardQueryMetadata(ctx context.Context, req *http.Request) metadata.MD {
+	if v := req.URL.Query().Get(maxOutputBytesParam); len(v) > 0 {
+		return metadata.Pairs(maxOutputBytesKey, v)
+	}
+	return nil
+}
+
+// maxOutputBytes returns the maximum size of the task outputs requested in the metadata of the request, or -1 if
+// the outputs should not be truncated.
+func maxOutputBytes(ctx context.Context) (int64, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md[maxOutputBytesKey]
+	if len(values) == 0 {
+		return -1, nil
+	}
+	n, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || n < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s '%s': expected a non-negative integer",
+			maxOutputBytesParam, values[0])
+	}
+	return n, nil
+}
+
+// truncateOutputs returns a copy of the status in which the outputs of the tasks that exceed maxBytes are truncated
+// (see typedvalues.Truncate). The status itself, which is shared with the cache, is not modified. If maxBytes is
+// negative, the status is returned as is.
+func truncateOutputs(s *types.WorkflowInvocationStatus, maxBytes int64) *types.WorkflowInvocationStatus {
+	if s == nil || maxBytes < 0 {
+		return s
+	}
+	var truncated map[string]*types.TaskInvocation
+	for id, task := range s.GetTasks() {
+		output, ok := typedvalues.Truncate(task.GetStatus().GetOutput(), int(maxBytes))
+		if !ok {
+			continue
+		}
+		if truncated == nil {
+			truncated = make(map[string]*types.TaskInvocation, len(s.GetTasks()))
+			for id, task := range s.GetTasks() {
+				truncated[id] = task
+			}
+		}
+		taskStatus := *task.GetStatus()
+		taskStatus.Output = output
+		taskCopy := *task
+		taskCopy.Status = &taskStatus
+		truncated[id] = &taskCopy
+	}
+	if truncated == nil {
+		return s
+	}
+	statusCopy := *s
+	statusCopy.Tasks = truncated
+	return &statusCopy
+}
@@ -0,0 +1,51 @@
+package apiserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTruncateOutputs(t *testing.T) {
+	large := typedvalues.MustWrap(strings.Repeat("a", 1000))
+	small := typedvalues.MustWrap("b")
+	status := &types.WorkflowInvocationStatus{
+		Tasks: map[string]*types.TaskInvocation{
+			"large": {Status: &types.TaskInvocationStatus{Output: large}},
+			"small": {Status: &types.TaskInvocationStatus{Output: small}},
+			"none":  {Status: &types.TaskInvocationStatus{}},
+		},
+	}
+
+	truncated := truncateOutputs(status, 100)
+	assert.True(t, typedvalues.IsTruncated(truncated.Tasks["large"].Status.Output))
+	assert.Equal(t, strings.Repeat("a", 100), typedvalues.MustUnwrap(truncated.Tasks["large"].Status.Output))
+	assert.Equal(t, small, truncated.Tasks["small"].Status.Output)
+	assert.Nil(t, truncated.Tasks["none"].Status.Output)
+
+	// The stored status is left intact.
+	assert.Equal(t, large, status.Tasks["large"].Status.Output)
+	assert.False(t, typedvalues.IsTruncated(status.Tasks["large"].Status.Output))
+
+	// Statuses without large outputs are not copied.
+	assert.True(t, status == truncateOutputs(status, 2000))
+	assert.True(t, status == truncateOutputs(status, -1))
+}
+
+func TestMaxOutputBytes(t *testing.T) {
+	n, err := maxOutputBytes(context.Background())
+	assert.NoError(t, err)
+	assert.EqualValues(t, -1, n)
+
+	n, err = maxOutputBytes(metadata.NewIncomingContext(context.Background(), metadata.Pairs(maxOutputBytesKey, "100")))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 100, n)
+
+	_, err = maxOutputBytes(metadata.NewIncomingContext(context.Background(), metadata.Pairs(maxOutputBytesKey, "-1")))
+	assert.Error(t, err)
+}
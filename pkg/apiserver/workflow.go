@@ -5,13 +5,17 @@ import (
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/events"
 	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/auth"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/golang/protobuf/ptypes/empty"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -20,9 +24,10 @@ const (
 
 // Workflow is responsible for all functionality related to managing workflows.
 type Workflow struct {
-	api     *api.Workflow
-	store   *store.Workflows
-	backend fes.Backend
+	api        *api.Workflow
+	store      *store.Workflows
+	backend    fes.Backend
+	authorizer auth.Authorizer
 }
 
 func NewWorkflow(api *api.Workflow, store *store.Workflows, backend fes.Backend) *Workflow {
@@ -33,7 +38,18 @@ func NewWorkflow(api *api.Workflow, store *store.Workflows, backend fes.Backend)
 	}
 }
 
+// SetAuthorizer sets the authorizer that decides whether the principal of a request is allowed to create or delete
+// workflows. If nil, all actions are allowed.
+func (ga *Workflow) SetAuthorizer(authorizer auth.Authorizer) {
+	ga.authorizer = authorizer
+}
+
 func (ga *Workflow) Create(ctx context.Context, spec *types.WorkflowSpec) (*types.ObjectMetadata, error) {
+	if err := auth.Authorize(ctx, ga.authorizer, auth.ActionCreateWorkflow, auth.Resource{
+		WorkflowID: spec.GetForceId(),
+	}); err != nil {
+		return nil, err
+	}
 	id, err := ga.api.Create(spec, api.WithContext(ctx))
 	if err != nil {
 		return nil, toErrorStatus(err)
@@ -91,7 +107,29 @@ func (ga *Workflow) Get(ctx context.Context, workflowID *types.ObjectMetadata) (
 }
 
 func (ga *Workflow) Delete(ctx context.Context, workflowID *types.ObjectMetadata) (*empty.Empty, error) {
-	err := ga.api.Delete(workflowID.GetId())
+	if err := auth.Authorize(ctx, ga.authorizer, auth.ActionDeleteWorkflow, auth.Resource{
+		WorkflowID: workflowID.GetId(),
+	}); err != nil {
+		return nil, err
+	}
+	err := ga.api.Delete(workflowID.GetId(), api.WithContext(ctx))
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	return &empty.Empty{}, nil
+}
+
+// ClearQuarantine clears the quarantine of the workflow, which allows the workflow to be invoked again.
+func (ga *Workflow) ClearQuarantine(ctx context.Context, workflowID *types.ObjectMetadata) (*empty.Empty, error) {
+	if err := auth.Authorize(ctx, ga.authorizer, auth.ActionClearQuarantine, auth.Resource{
+		WorkflowID: workflowID.GetId(),
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := ga.store.GetWorkflow(workflowID.GetId()); err != nil {
+		return nil, toErrorStatus(err)
+	}
+	err := ga.api.ClearQuarantine(workflowID.GetId(), api.WithContext(ctx))
 	if err != nil {
 		return nil, toErrorStatus(err)
 	}
@@ -125,3 +163,71 @@ func (ga *Workflow) Events(ctx context.Context, md *types.ObjectMetadata) (*Obje
 		Events:   events,
 	}, nil
 }
+
+// Diff compares two versions of a workflow. The versions of a workflow are derived from its event history, in which
+// each (re)creation of the workflow marks a new version.
+func (ga *Workflow) Diff(ctx context.Context, req *WorkflowDiffRequest) (*WorkflowDiff, error) {
+	if len(req.GetId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no workflow ID provided")
+	}
+	versions, err := ga.versions(req.GetId())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	if len(versions) == 0 {
+		return nil, status.Errorf(codes.NotFound, "workflow %s not found", req.GetId())
+	}
+
+	versionB := req.GetVersionB()
+	if versionB == 0 {
+		versionB = int32(len(versions))
+	}
+	versionA := req.GetVersionA()
+	if versionA == 0 {
+		versionA = versionB - 1
+	}
+	// Version 0 refers to the empty workflow, which allows the first version to be diffed.
+	specA, ok := workflowVersion(versions, versionA)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "workflow %s has no version %d", req.GetId(), versionA)
+	}
+	specB, ok := workflowVersion(versions, versionB)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "workflow %s has no version %d", req.GetId(), versionB)
+	}
+
+	diff := DiffWorkflowSpecs(specA, specB)
+	diff.Id = req.GetId()
+	diff.VersionA = versionA
+	diff.VersionB = versionB
+	return diff, nil
+}
+
+// versions returns the specs of all versions of the workflow, in order of creation.
+func (ga *Workflow) versions(workflowID string) ([]*types.WorkflowSpec, error) {
+	history, err := ga.backend.Get(projectors.NewWorkflowAggregate(workflowID))
+	if err != nil {
+		return nil, err
+	}
+	var versions []*types.WorkflowSpec
+	for _, event := range history {
+		data, err := fes.ParseEventData(event)
+		if err != nil {
+			return nil, err
+		}
+		if created, ok := data.(*events.WorkflowCreated); ok {
+			versions = append(versions, created.GetSpec())
+		}
+	}
+	return versions, nil
+}
+
+func workflowVersion(versions []*types.WorkflowSpec, version int32) (*types.WorkflowSpec, bool) {
+	if version == 0 {
+		return &types.WorkflowSpec{}, true
+	}
+	if version < 0 || int(version) > len(versions) {
+		return nil, false
+	}
+	return versions[version-1], true
+}
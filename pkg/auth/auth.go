@@ -0,0 +1,216 @@
+// Package auth provides the pluggable authentication and authorization of the workflow and invocation APIs.
+//
+// An Authenticator validates the bearer token of a request and returns the Principal that made the request, which is
+// injected into the context of the request. The principal is used to record the owner of the invocations that it
+// creates, and is added to the events that it causes for auditing. An Authorizer decides whether the principal is
+// allowed to perform an action on a resource, such as invoking or canceling a workflow.
+//
+// By default, requests are not authenticated (NoopAuthenticator), and all actions are allowed (AllowAll).
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// LabelOwner is the label of an invocation that contains the subject of the principal that created it.
+	LabelOwner = "owner"
+
+	// MetadataPrincipal is the key of the event metadata that contains the subject of the principal that caused the
+	// event.
+	MetadataPrincipal = "principal"
+
+	authorizationKey    = "authorization"
+	authorizationScheme = "bearer"
+)
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid bearer token")
+)
+
+// Action is an action of a principal on a resource that is subject to authorization.
+type Action string
+
+const (
+	ActionCreateWorkflow  Action = "workflow.create"
+	ActionDeleteWorkflow  Action = "workflow.delete"
+	ActionClearQuarantine Action = "workflow.clear-quarantine"
+	ActionInvoke          Action = "invocation.create"
+	ActionCancel          Action = "invocation.cancel"
+	ActionReplay          Action = "invocation.replay"
+	ActionPurge           Action = "invocation.purge"
+	ActionArchive         Action = "invocation.archive"
+	ActionRestore         Action = "invocation.restore"
+)
+
+// Principal is the authenticated identity that made a request.
+type Principal struct {
+	// Subject identifies the principal, such as the subject of a JWT. It is empty for anonymous principals.
+	Subject string
+
+	// Groups contains the groups that the principal is a member of.
+	Groups []string
+}
+
+// Anonymous is the principal of requests that were not authenticated.
+var Anonymous = &Principal{}
+
+// Authenticated returns whether the principal has been authenticated.
+func (p *Principal) Authenticated() bool {
+	return p != nil && len(p.Subject) > 0
+}
+
+func (p *Principal) String() string {
+	if !p.Authenticated() {
+		return "anonymous"
+	}
+	return p.Subject
+}
+
+// Resource identifies the resource that an action is performed on.
+type Resource struct {
+	// WorkflowID is the workflow that is created, deleted or invoked, or the workflow of the invocation.
+	WorkflowID string
+
+	// InvocationID is the invocation that the action is performed on, if any.
+	InvocationID string
+
+	// Owner is the subject of the principal that owns the resource, if known.
+	Owner string
+}
+
+// Authenticator validates the bearer token of a request, and returns the principal that the token identifies.
+type Authenticator interface {
+	// Authenticate returns the principal identified by the token, which is empty if the request did not provide a
+	// token. It returns an error, such as ErrMissingToken or ErrInvalidToken, if the request should be rejected.
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authorizer decides whether principals are allowed to perform actions.
+type Authorizer interface {
+	// Authorize returns nil if the principal is allowed to perform the action on the resource, or else the reason why
+	// it is not.
+	Authorize(ctx context.Context, principal *Principal, action Action, resource Resource) error
+}
+
+// NoopAuthenticator does not authenticate requests; all requests are made by the Anonymous principal.
+type NoopAuthenticator struct{}
+
+func (NoopAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	return Anonymous, nil
+}
+
+// AllowAll allows every principal to perform all actions.
+type AllowAll struct{}
+
+func (AllowAll) Authorize(ctx context.Context, principal *Principal, action Action, resource Resource) error {
+	return nil
+}
+
+// OwnerOnly allows every principal to create workflows and invocations, but only allows the owner of an invocation to
+// cancel, replay, purge, archive or restore it. Invocations without an owner are not restricted.
+type OwnerOnly struct{}
+
+func (OwnerOnly) Authorize(ctx context.Context, principal *Principal, action Action, resource Resource) error {
+	if len(resource.Owner) == 0 || resource.Owner == principal.Subject {
+		return nil
+	}
+	switch action {
+	case ActionCancel:
+		return errors.New("only the owner of the invocation can cancel it")
+	case ActionReplay:
+		return errors.New("only the owner of the invocation can replay it")
+	case ActionPurge:
+		return errors.New("only the owner of the invocation can purge it")
+	case ActionArchive:
+		return errors.New("only the owner of the invocation can archive it")
+	case ActionRestore:
+		return errors.New("only the owner of the invocation can restore it")
+	}
+	return nil
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of the context that carries the principal.
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal of the context, or Anonymous if the context does not carry a principal.
+func FromContext(ctx context.Context) *Principal {
+	if ctx != nil {
+		if principal, ok := ctx.Value(principalKey{}).(*Principal); ok && principal != nil {
+			return principal
+		}
+	}
+	return Anonymous
+}
+
+// Authorize checks whether the principal of the context is allowed to perform the action on the resource, returning
+// a PermissionDenied error if it is not. A nil authorizer allows all actions.
+func Authorize(ctx context.Context, authorizer Authorizer, action Action, resource Resource) error {
+	if authorizer == nil {
+		return nil
+	}
+	principal := FromContext(ctx)
+	if err := authorizer.Authorize(ctx, principal, action, resource); err != nil {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed to %s: %v", principal, action, err)
+	}
+	return nil
+}
+
+// authenticate authenticates the token with the authenticator, returning the context with the principal, or an
+// Unauthenticated error.
+func authenticate(ctx context.Context, authenticator Authenticator, token string) (context.Context, error) {
+	principal, err := authenticator.Authenticate(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if principal == nil {
+		principal = Anonymous
+	}
+	return NewContext(ctx, principal), nil
+}
+
+// tokenFromMetadata returns the bearer token in the authorization metadata of the incoming gRPC request, if any.
+func tokenFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md[authorizationKey] {
+		if token, ok := parseBearer(value); ok {
+			return token
+		}
+	}
+	return ""
+}
+
+// parseBearer returns the token of the value of an authorization header, if it uses the bearer scheme.
+func parseBearer(value string) (string, bool) {
+	parts := strings.SplitN(value, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], authorizationScheme) {
+		return "", false
+	}
+	return strings.TrimSpace(parts[1]), true
+}
+
+// HTTPHandler returns a handler that authenticates the bearer token of each request, before passing the request on
+// to the handler with the principal in its context.
+func HTTPHandler(authenticator Authenticator, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := parseBearer(r.Header.Get("Authorization"))
+		ctx, err := authenticate(r.Context(), authenticator, token)
+		if err != nil {
+			http.Error(w, status.Convert(err).Message(), http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// staticAuthenticator accepts a single token, and rejects all other tokens.
+type staticAuthenticator struct {
+	token     string
+	principal *Principal
+}
+
+func (a staticAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if len(token) == 0 {
+		return nil, ErrMissingToken
+	}
+	if token != a.token {
+		return nil, ErrInvalidToken
+	}
+	return a.principal, nil
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Anonymous, FromContext(context.Background()))
+	assert.False(t, FromContext(context.Background()).Authenticated())
+
+	alice := &Principal{Subject: "alice"}
+	principal := FromContext(NewContext(context.Background(), alice))
+	assert.Equal(t, alice, principal)
+	assert.True(t, principal.Authenticated())
+	assert.Equal(t, "alice", principal.String())
+}
+
+func TestAuthFunc(t *testing.T) {
+	authFunc := AuthFunc(staticAuthenticator{token: "secret", principal: &Principal{Subject: "alice"}})
+
+	md := metadata.Pairs("authorization", "Bearer secret")
+	ctx, err := authFunc(metadata.NewIncomingContext(context.Background(), md))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", FromContext(ctx).Subject)
+
+	// The scheme is case-insensitive.
+	md = metadata.Pairs("authorization", "bearer secret")
+	_, err = authFunc(metadata.NewIncomingContext(context.Background(), md))
+	assert.NoError(t, err)
+
+	md = metadata.Pairs("authorization", "Bearer other")
+	_, err = authFunc(metadata.NewIncomingContext(context.Background(), md))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = authFunc(context.Background())
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Without authentication, all requests are made by the anonymous principal.
+	ctx, err = AuthFunc(NoopAuthenticator{})(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, FromContext(ctx).Authenticated())
+}
+
+type denyAll struct{}
+
+func (denyAll) Authorize(ctx context.Context, principal *Principal, action Action, resource Resource) error {
+	return errors.New("denied")
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx := NewContext(context.Background(), &Principal{Subject: "alice"})
+	assert.NoError(t, Authorize(ctx, nil, ActionInvoke, Resource{WorkflowID: "wf"}))
+	assert.NoError(t, Authorize(ctx, AllowAll{}, ActionInvoke, Resource{WorkflowID: "wf"}))
+
+	err := Authorize(ctx, denyAll{}, ActionInvoke, Resource{WorkflowID: "wf"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestOwnerOnly(t *testing.T) {
+	ctx := NewContext(context.Background(), &Principal{Subject: "alice"})
+	assert.NoError(t, Authorize(ctx, OwnerOnly{}, ActionInvoke, Resource{WorkflowID: "wf"}))
+	assert.NoError(t, Authorize(ctx, OwnerOnly{}, ActionCancel, Resource{InvocationID: "wi", Owner: "alice"}))
+	assert.NoError(t, Authorize(ctx, OwnerOnly{}, ActionCancel, Resource{InvocationID: "wi"}))
+	assert.Error(t, Authorize(ctx, OwnerOnly{}, ActionCancel, Resource{InvocationID: "wi", Owner: "bob"}))
+	assert.Error(t, Authorize(context.Background(), OwnerOnly{}, ActionCancel, Resource{Owner: "alice"}))
+	assert.NoError(t, Authorize(ctx, OwnerOnly{}, ActionReplay, Resource{InvocationID: "wi", Owner: "alice"}))
+	assert.Error(t, Authorize(ctx, OwnerOnly{}, ActionReplay, Resource{InvocationID: "wi", Owner: "bob"}))
+	assert.NoError(t, Authorize(ctx, OwnerOnly{}, ActionPurge, Resource{WorkflowID: "wf"}))
+	assert.NoError(t, Authorize(ctx, OwnerOnly{}, ActionPurge, Resource{InvocationID: "wi", Owner: "alice"}))
+	assert.Error(t, Authorize(ctx, OwnerOnly{}, ActionPurge, Resource{InvocationID: "wi", Owner: "bob"}))
+	assert.NoError(t, Authorize(ctx, OwnerOnly{}, ActionArchive, Resource{InvocationID: "wi", Owner: "alice"}))
+	assert.Error(t, Authorize(ctx, OwnerOnly{}, ActionArchive, Resource{InvocationID: "wi", Owner: "bob"}))
+	assert.NoError(t, Authorize(ctx, OwnerOnly{}, ActionRestore, Resource{InvocationID: "wi", Owner: "alice"}))
+	assert.Error(t, Authorize(ctx, OwnerOnly{}, ActionRestore, Resource{InvocationID: "wi", Owner: "bob"}))
+}
+
+func TestHTTPHandler(t *testing.T) {
+	var principal *Principal
+	handler := HTTPHandler(staticAuthenticator{token: "secret", principal: &Principal{Subject: "alice"}},
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal = FromContext(r.Context())
+		}))
+
+	req := httptest.NewRequest(http.MethodGet, "/invocation/wi/stream", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice", principal.Subject)
+
+	req = httptest.NewRequest(http.MethodGet, "/invocation/wi/stream", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestBearerToken(t *testing.T) {
+	md, err := BearerToken("secret").GetRequestMetadata(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer secret", md["authorization"])
+
+	md, err = BearerToken("").GetRequestMetadata(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, md)
+}
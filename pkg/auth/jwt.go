@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"golang.org/x/net/context"
+)
+
+// DefaultGroupsClaim is the claim of a JWT that contains the groups of the principal, unless configured otherwise.
+const DefaultGroupsClaim = "groups"
+
+// JWTConfig contains the configuration of the JWT authenticator. Exactly one of Secret and PublicKey is required.
+type JWTConfig struct {
+	// Secret is the shared secret of the HMAC-signed tokens (HS256, HS384 and HS512).
+	Secret []byte
+
+	// PublicKey is the PEM-encoded RSA or ECDSA public key of the signed tokens (RS*, PS* or ES*).
+	PublicKey []byte
+
+	// Issuer is the issuer that the tokens are required to have (iss claim), if not empty.
+	Issuer string
+
+	// Audience is the audience that the tokens are required to have (aud claim), if not empty.
+	Audience string
+
+	// GroupsClaim is the claim that contains the groups of the principal. Defaults to DefaultGroupsClaim.
+	GroupsClaim string
+
+	// AllowAnonymous allows requests without a token, which are made by the Anonymous principal. Requests with an
+	// invalid token are rejected regardless.
+	AllowAnonymous bool
+}
+
+// JWTAuthenticator authenticates requests with a signed JSON Web Token as the bearer token. The subject of the token
+// (sub claim) identifies the principal. The expiration and not-before claims of the token are enforced if present.
+type JWTAuthenticator struct {
+	config  JWTConfig
+	parser  *jwt.Parser
+	keyFunc jwt.Keyfunc
+}
+
+func NewJWTAuthenticator(config JWTConfig) (*JWTAuthenticator, error) {
+	if (len(config.Secret) > 0) == (len(config.PublicKey) > 0) {
+		return nil, errors.New("either a secret or a public key is required to verify JWTs")
+	}
+	if len(config.GroupsClaim) == 0 {
+		config.GroupsClaim = DefaultGroupsClaim
+	}
+
+	// Only accept the signing methods that match the type of the key, to prevent tokens that are signed with the
+	// public key as an HMAC secret.
+	var key interface{}
+	var methods []string
+	if len(config.Secret) > 0 {
+		key = config.Secret
+		methods = []string{"HS256", "HS384", "HS512"}
+	} else if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(config.PublicKey); err == nil {
+		key = rsaKey
+		methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	} else if ecKey, err := jwt.ParseECPublicKeyFromPEM(config.PublicKey); err == nil {
+		key = ecKey
+		methods = []string{"ES256", "ES384", "ES512"}
+	} else {
+		return nil, errors.New("public key is not a PEM-encoded RSA or ECDSA public key")
+	}
+
+	return &JWTAuthenticator{
+		config: config,
+		parser: &jwt.Parser{ValidMethods: methods},
+		keyFunc: func(token *jwt.Token) (interface{}, error) {
+			return key, nil
+		},
+	}, nil
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if len(token) == 0 {
+		if a.config.AllowAnonymous {
+			return Anonymous, nil
+		}
+		return nil, ErrMissingToken
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(token, claims, a.keyFunc); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidToken, err)
+	}
+	if len(a.config.Issuer) > 0 && !claims.VerifyIssuer(a.config.Issuer, true) {
+		return nil, fmt.Errorf("%v: unexpected issuer", ErrInvalidToken)
+	}
+	if len(a.config.Audience) > 0 && !hasAudience(claims, a.config.Audience) {
+		return nil, fmt.Errorf("%v: unexpected audience", ErrInvalidToken)
+	}
+	subject, _ := claims["sub"].(string)
+	if len(subject) == 0 {
+		return nil, fmt.Errorf("%v: missing subject", ErrInvalidToken)
+	}
+
+	principal := &Principal{Subject: subject}
+	switch groups := claims[a.config.GroupsClaim].(type) {
+	case string:
+		principal.Groups = []string{groups}
+	case []interface{}:
+		for _, group := range groups {
+			if s, ok := group.(string); ok {
+				principal.Groups = append(principal.Groups, s)
+			}
+		}
+	}
+	return principal, nil
+}
+
+// hasAudience returns whether the aud claim, which is either a string or a list of strings, contains the audience.
+func hasAudience(claims jwt.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
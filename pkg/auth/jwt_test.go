@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return token
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	authenticator, err := NewJWTAuthenticator(JWTConfig{
+		Secret:   []byte("secret"),
+		Issuer:   "issuer",
+		Audience: "workflows",
+	})
+	assert.NoError(t, err)
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":    "alice",
+			"iss":    "issuer",
+			"aud":    []interface{}{"other", "workflows"},
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"groups": []interface{}{"admins", "devs"},
+		}
+	}
+
+	principal, err := authenticator.Authenticate(context.Background(), signHS256(t, "secret", claims()))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", principal.Subject)
+	assert.Equal(t, []string{"admins", "devs"}, principal.Groups)
+
+	invalid := map[string]func(c jwt.MapClaims) string{
+		"wrong secret": func(c jwt.MapClaims) string {
+			return signHS256(t, "other", c)
+		},
+		"expired": func(c jwt.MapClaims) string {
+			c["exp"] = time.Now().Add(-time.Minute).Unix()
+			return signHS256(t, "secret", c)
+		},
+		"wrong issuer": func(c jwt.MapClaims) string {
+			c["iss"] = "other"
+			return signHS256(t, "secret", c)
+		},
+		"wrong audience": func(c jwt.MapClaims) string {
+			c["aud"] = "other"
+			return signHS256(t, "secret", c)
+		},
+		"missing subject": func(c jwt.MapClaims) string {
+			delete(c, "sub")
+			return signHS256(t, "secret", c)
+		},
+		"unsigned": func(c jwt.MapClaims) string {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodNone, c).SignedString(jwt.UnsafeAllowNoneSignatureType)
+			assert.NoError(t, err)
+			return token
+		},
+		"malformed": func(c jwt.MapClaims) string {
+			return "not-a-jwt"
+		},
+	}
+	for name, token := range invalid {
+		_, err := authenticator.Authenticate(context.Background(), token(claims()))
+		assert.Error(t, err, name)
+	}
+
+	_, err = authenticator.Authenticate(context.Background(), "")
+	assert.Equal(t, ErrMissingToken, err)
+}
+
+func TestJWTAuthenticator_AllowAnonymous(t *testing.T) {
+	authenticator, err := NewJWTAuthenticator(JWTConfig{Secret: []byte("secret"), AllowAnonymous: true})
+	assert.NoError(t, err)
+
+	principal, err := authenticator.Authenticate(context.Background(), "")
+	assert.NoError(t, err)
+	assert.False(t, principal.Authenticated())
+
+	// Invalid tokens are rejected regardless.
+	_, err = authenticator.Authenticate(context.Background(), "not-a-jwt")
+	assert.Error(t, err)
+}
+
+func TestJWTAuthenticator_PublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	authenticator, err := NewJWTAuthenticator(JWTConfig{PublicKey: publicKey})
+	assert.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "alice"}).SignedString(key)
+	assert.NoError(t, err)
+	principal, err := authenticator.Authenticate(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", principal.Subject)
+
+	// Tokens signed with the public key as an HMAC secret are rejected.
+	_, err = authenticator.Authenticate(context.Background(), signHS256(t, string(publicKey),
+		jwt.MapClaims{"sub": "alice"}))
+	assert.Error(t, err)
+
+	_, err = NewJWTAuthenticator(JWTConfig{PublicKey: []byte("not a key")})
+	assert.Error(t, err)
+	_, err = NewJWTAuthenticator(JWTConfig{})
+	assert.Error(t, err)
+}
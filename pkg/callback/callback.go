@@ -0,0 +1,475 @@
+// Package callback notifies external consumers (webhooks) of the progress of workflow invocations.
+//
+// Callbacks are sent for failed tasks, for invocations that exceeded the soft timeout of their workflow, and for
+// invocations that reached a terminal state. Each callback payload contains a sequence number, which is incremented
+// for each callback of an invocation. This allows consumers to detect callbacks that arrived out of order.
+//
+// Callbacks can be sent to multiple destinations. Each destination has its own queue and workers, so that a slow or
+// unavailable destination, of which the callbacks are being retried, does not delay the callbacks of the other
+// destinations. The total number of concurrent requests across all destinations is bounded by a shared worker pool.
+//
+// By default callbacks are delivered best-effort and unordered, favoring throughput. Consumers that need to observe
+// the callbacks of an invocation in order can enable ordered delivery. In that case a callback is only sent to a
+// destination once all callbacks before it have been acknowledged by that destination (with a 2xx response), at the
+// cost of throughput: a single slow or unavailable destination blocks the delivery of its subsequent callbacks. An
+// ordered callback is therefore not given up on after the maximum number of attempts, as that would leave a gap in
+// the sequence; it is retried until it has been delivered or the sender is closed.
+package callback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util/backoff"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultTimeout     = 10 * time.Second
+	DefaultMaxAttempts = 5
+	DefaultWorkers     = 32
+	DefaultConcurrency = 8
+	defaultQueueSize   = 1000
+	contentTypeJSON    = "application/json"
+
+	// finalizedRetention is the duration that a finalized invocation is remembered to detect redelivered events.
+	finalizedRetention = 10 * time.Minute
+
+	// retryInterval is the interval before the first retry of a callback, which doubles with each retry up to
+	// maxRetryInterval.
+	retryInterval    = 100 * time.Millisecond
+	maxRetryInterval = 30 * time.Second
+)
+
+var log = logrus.WithField("component", "callback")
+
+var metricDuplicateEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "callback",
+	Name:      "duplicate_events_total",
+	Help:      "Number of redelivered events of finalized invocations that were ignored",
+}, []string{"eventType"})
+
+var metricUnknownEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "callback",
+	Name:      "unknown_events_total",
+	Help:      "Number of events of which the type is unknown to the callback sender",
+}, []string{"eventType"})
+
+var metricDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "callback",
+	Name:      "deliveries_total",
+	Help:      "Number of callbacks per destination by result (delivered, failed or dropped)",
+}, []string{"destination", "result"})
+
+var metricAttemptDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "workflows",
+	Subsystem: "callback",
+	Name:      "attempt_duration_seconds",
+	Help:      "Latency of the callback delivery attempts per destination by result (success or failure)",
+	Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+}, []string{"destination", "result"})
+
+var metricQueued = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "workflows",
+	Subsystem: "callback",
+	Name:      "queued",
+	Help:      "Number of callbacks waiting to be delivered per destination",
+}, []string{"destination"})
+
+var metricBlocked = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "workflows",
+	Subsystem: "callback",
+	Name:      "blocked",
+	Help: "Number of ordered callbacks per destination that exhausted their attempts, and block the subsequent " +
+		"callbacks until they have been delivered",
+}, []string{"destination"})
+
+func init() {
+	prometheus.MustRegister(metricDuplicateEvents, metricUnknownEvents, metricDeliveries, metricAttemptDuration,
+		metricQueued, metricBlocked)
+}
+
+// Config contains the configuration of the callback sender.
+type Config struct {
+	// URL is the endpoint to which the callbacks are POSTed.
+	URL string
+
+	// Destinations contains additional endpoints to which the callbacks are POSTed.
+	Destinations []string
+
+	// Ordered enables the strictly ordered delivery of callbacks per destination.
+	Ordered bool
+
+	// Workers is the maximum number of concurrent callback requests across all destinations. Defaults to
+	// DefaultWorkers.
+	Workers int
+
+	// Concurrency is the maximum number of callbacks that are delivered concurrently to a single destination,
+	// including the callbacks that are waiting to be retried. It is ignored for ordered delivery, which delivers one
+	// callback at a time. Defaults to DefaultConcurrency.
+	Concurrency int
+
+	// Timeout is the maximum duration of a single delivery attempt. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// MaxAttempts is the maximum number of attempts to deliver a single callback. Defaults to DefaultMaxAttempts.
+	// With ordered delivery, a callback that exhausted its attempts keeps being retried, as the subsequent callbacks
+	// of the destination cannot be delivered before it.
+	MaxAttempts int
+
+	// LenientEvents ignores the events of unknown types without a trace. By default, these events are metered and
+	// logged, to make version mismatches between the sender and the producers of the events visible.
+	LenientEvents bool
+}
+
+// Payload is the JSON-encoded body of a callback.
+type Payload struct {
+	// Sequence is the (1-based) sequence number of this callback within the invocation.
+	Sequence     int64     `json:"sequence"`
+	InvocationID string    `json:"invocationId"`
+	TaskID       string    `json:"taskId,omitempty"`
+	EventType    string    `json:"eventType"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// Sender listens for invocation events and delivers the relevant ones as callbacks to the configured endpoints.
+type Sender struct {
+	config       Config
+	client       *http.Client
+	invocations  *store.Invocations
+	destinations []*destination
+	workers      chan struct{}
+	sequences    map[string]int64
+	finalized    map[string]time.Time // invocation ID -> time of finalization
+	sequencesMu  *sync.Mutex
+	done         func()
+	closeC       <-chan struct{}
+}
+
+// destination is an endpoint with its own queue of callbacks.
+type destination struct {
+	url   string
+	label string // the URL without credentials or query, used as the metric label
+	queue chan *Payload
+}
+
+func NewSender(invocations *store.Invocations, config Config) *Sender {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.Ordered {
+		config.Concurrency = 1
+	}
+	var destinations []*destination
+	seen := map[string]bool{}
+	for _, u := range append([]string{config.URL}, config.Destinations...) {
+		if len(u) == 0 || seen[u] {
+			continue
+		}
+		seen[u] = true
+		destinations = append(destinations, &destination{
+			url:   u,
+			label: destinationLabel(u),
+			queue: make(chan *Payload, defaultQueueSize),
+		})
+	}
+	ctx, done := context.WithCancel(context.Background())
+	return &Sender{
+		config:       config,
+		client:       &http.Client{Timeout: config.Timeout},
+		invocations:  invocations,
+		destinations: destinations,
+		workers:      make(chan struct{}, config.Workers),
+		sequences:    map[string]int64{},
+		finalized:    map[string]time.Time{},
+		sequencesMu:  &sync.Mutex{},
+		done:         done,
+		closeC:       ctx.Done(),
+	}
+}
+
+// Run listens for invocation events until the sender is closed.
+func (s *Sender) Run() error {
+	sub := s.invocations.GetInvocationUpdates()
+	if sub == nil {
+		return fmt.Errorf("invocation store does not support pubsub")
+	}
+	s.startWorkers()
+	for {
+		select {
+		case msg := <-sub.Ch:
+			notification, err := sub.ToNotification(msg)
+			if err != nil {
+				log.Warnf("Failed to convert pubsub message to notification: %v", err)
+				continue
+			}
+			s.Notify(notification)
+		case <-s.closeC:
+			return sub.Close()
+		}
+	}
+}
+
+// startWorkers starts the workers that deliver the queued callbacks of each destination.
+func (s *Sender) startWorkers() {
+	for _, dest := range s.destinations {
+		for i := 0; i < s.config.Concurrency; i++ {
+			go s.deliverQueued(dest)
+		}
+		log.Infof("Sending invocation callbacks to %s (ordered: %v, concurrency: %d)", dest.label,
+			s.config.Ordered, s.config.Concurrency)
+	}
+}
+
+// Notify creates and queues a callback for the notification for each destination, if the event of the notification
+// is relevant to the consumers.
+//
+// If the queue of a destination is full, the callback is dropped for that destination, unless ordered delivery is
+// enabled. In that case Notify blocks until the callback has been queued, to preserve the order of the callbacks.
+func (s *Sender) Notify(notification *fes.Notification) {
+	payload, ok := s.createPayload(notification)
+	if !ok {
+		return
+	}
+
+	for _, dest := range s.destinations {
+		if s.config.Ordered {
+			select {
+			case dest.queue <- payload:
+			case <-s.closeC:
+				return
+			}
+		} else {
+			select {
+			case dest.queue <- payload:
+			default:
+				log.Errorf("Dropping callback %d of invocation %s for %s: queue is full", payload.Sequence,
+					payload.InvocationID, dest.label)
+				metricDeliveries.WithLabelValues(dest.label, "dropped").Inc()
+				continue
+			}
+		}
+		metricQueued.WithLabelValues(dest.label).Inc()
+	}
+}
+
+// observeUnknownEvent records an event of which the type is unknown, such as an event of a newer version of the
+// workflow engine.
+func (s *Sender) observeUnknownEvent(invocationID string, eventType string) {
+	if s.config.LenientEvents {
+		log.Debugf("Ignoring event of unknown type %s", eventType)
+		return
+	}
+	log.WithField("invocation", invocationID).
+		Warnf("Ignoring event of unknown type %s; the event producer might run a different version", eventType)
+	metricUnknownEvents.WithLabelValues(eventType).Inc()
+}
+
+func (s *Sender) Close() error {
+	s.done()
+	return nil
+}
+
+func (s *Sender) createPayload(notification *fes.Notification) (*Payload, bool) {
+	event := notification.Event
+	invocation, ok := notification.Updated.(*types.WorkflowInvocation)
+	if !ok || event == nil {
+		return nil, false
+	}
+
+	payload := &Payload{
+		InvocationID: invocation.ID(),
+		EventType:    event.GetType(),
+		Status:       invocation.GetStatus().GetStatus().String(),
+		Error:        invocation.GetStatus().GetError().GetMessage(),
+	}
+	var terminal, reopened bool
+	switch event.GetType() {
+	case events.EventTaskFailed:
+		payload.TaskID = event.GetAggregate().GetId()
+		if task, ok := invocation.TaskInvocation(payload.TaskID); ok {
+			payload.Status = task.GetStatus().GetStatus().String()
+			payload.Error = task.GetStatus().GetError().GetMessage()
+		}
+	case events.EventInvocationSoftTimeoutExceeded:
+		// Warn the consumer that the invocation is approaching its deadline.
+	case events.EventInvocationCompleted, events.EventInvocationCanceled, events.EventInvocationFailed:
+		terminal = true
+	case events.EventInvocationTasksReplayed:
+		// The replay reopens the finalized invocation, which starts a new sequence of callbacks.
+		reopened = true
+	case events.EventInvocationCreated, events.EventInvocationTaskAdded, events.EventInvocationBranchSelected,
+		events.EventInvocationSummary, events.EventTaskStarted, events.EventTaskSucceeded, events.EventTaskSkipped,
+		events.EventTaskPolled, events.EventInvocationTaskThrottled, events.EventInvocationScopeWarning:
+		// Not relevant to the consumer
+		return nil, false
+	default:
+		s.observeUnknownEvent(invocation.ID(), event.GetType())
+		return nil, false
+	}
+	if ts, err := ptypes.Timestamp(event.GetTimestamp()); err == nil {
+		payload.Timestamp = ts
+	}
+
+	// Assign the next sequence number of the invocation; the sequence is no longer needed after the terminal event.
+	s.sequencesMu.Lock()
+	defer s.sequencesMu.Unlock()
+	if reopened {
+		delete(s.finalized, payload.InvocationID)
+	}
+	if _, ok := s.finalized[payload.InvocationID]; ok {
+		// The pubsub delivers events at-least-once; ignore events that are redelivered after the terminal event,
+		// which would otherwise restart the sequence of the invocation.
+		log.Debugf("Ignoring duplicate %s event of finalized invocation %s", payload.EventType, payload.InvocationID)
+		metricDuplicateEvents.WithLabelValues(payload.EventType).Inc()
+		return nil, false
+	}
+	s.sequences[payload.InvocationID]++
+	payload.Sequence = s.sequences[payload.InvocationID]
+	if terminal {
+		delete(s.sequences, payload.InvocationID)
+		s.finalize(payload.InvocationID)
+	}
+	return payload, true
+}
+
+// finalize marks the invocation as finalized, and forgets the invocations that were finalized long enough ago that
+// redeliveries of their events are no longer expected. The caller should hold the sequencesMu lock.
+func (s *Sender) finalize(invocationID string) {
+	now := time.Now()
+	for id, finalizedAt := range s.finalized {
+		if now.Sub(finalizedAt) > finalizedRetention {
+			delete(s.finalized, id)
+		}
+	}
+	s.finalized[invocationID] = now
+}
+
+// deliverQueued delivers the queued callbacks of the destination one at a time, until the sender is closed.
+func (s *Sender) deliverQueued(dest *destination) {
+	for {
+		select {
+		case payload := <-dest.queue:
+			metricQueued.WithLabelValues(dest.label).Dec()
+			s.deliver(dest, payload)
+		case <-s.closeC:
+			return
+		}
+	}
+}
+
+// deliver sends the callback to the destination, retrying with a backoff if the attempt fails. A worker of the
+// shared pool is only held during an attempt, so callbacks that are waiting to be retried do not hold up the callbacks
+// of other destinations.
+//
+// Unordered callbacks are given up on after the maximum number of attempts. Ordered callbacks are retried until they
+// have been delivered or the sender is closed, blocking the subsequent callbacks of the destination in the meantime.
+func (s *Sender) deliver(dest *destination, payload *Payload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Errorf("Failed to encode callback for invocation %s: %v", payload.InvocationID, err)
+		return
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(retryBackoff(attempt - 1)):
+			case <-s.closeC:
+				return
+			}
+		}
+		select {
+		case s.workers <- struct{}{}:
+		case <-s.closeC:
+			return
+		}
+		start := time.Now()
+		err = s.post(dest.url, body)
+		<-s.workers
+		if err == nil {
+			metricAttemptDuration.WithLabelValues(dest.label, "success").Observe(time.Since(start).Seconds())
+			metricDeliveries.WithLabelValues(dest.label, "delivered").Inc()
+			log.Debugf("Delivered callback %d of invocation %s to %s", payload.Sequence, payload.InvocationID,
+				dest.label)
+			return
+		}
+		metricAttemptDuration.WithLabelValues(dest.label, "failure").Observe(time.Since(start).Seconds())
+		log.Debugf("Failed to deliver callback %d of invocation %s to %s (%d/%d): %v", payload.Sequence,
+			payload.InvocationID, dest.label, attempt+1, s.config.MaxAttempts, err)
+		if attempt+1 != s.config.MaxAttempts {
+			continue
+		}
+		if !s.config.Ordered {
+			break
+		}
+		log.Errorf("Failed to deliver callback %d of invocation %s to %s after %d attempts; retrying it until it is "+
+			"delivered, which blocks the subsequent callbacks: %v", payload.Sequence, payload.InvocationID, dest.label,
+			s.config.MaxAttempts, err)
+		blocked := metricBlocked.WithLabelValues(dest.label)
+		blocked.Inc()
+		defer blocked.Dec()
+	}
+	metricDeliveries.WithLabelValues(dest.label, "failed").Inc()
+	log.Errorf("Failed to deliver callback %d of invocation %s to %s: %v", payload.Sequence, payload.InvocationID,
+		dest.label, err)
+}
+
+// retryBackoff returns the interval before the retry that follows the failed attempt.
+func retryBackoff(attempt int) time.Duration {
+	if attempt >= 16 {
+		return maxRetryInterval
+	}
+	interval := backoff.ExponentialBackoff(attempt, retryInterval)
+	if interval > maxRetryInterval {
+		return maxRetryInterval
+	}
+	return interval
+}
+
+func (s *Sender) post(endpoint string, body []byte) error {
+	resp, err := s.client.Post(endpoint, contentTypeJSON, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback endpoint responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+// destinationLabel returns the URL without the credentials and query parameters, which might contain secrets.
+func destinationLabel(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "invalid"
+	}
+	return u.Scheme + "://" + u.Host + u.Path
+}
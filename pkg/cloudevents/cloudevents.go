@@ -0,0 +1,364 @@
+// Package cloudevents publishes the lifecycle transitions of workflow invocations as CloudEvents (v1.0) to a sink.
+//
+// The internal events of invocations and their tasks are mapped to CloudEvents with a type of the form
+// io.fission.workflows.<invocation|task>.<transition>, the configured source, and the invocation (or the task within
+// the invocation) as the subject. The events are delivered in the structured JSON mode of the HTTP binding.
+//
+// Publishing happens asynchronously from the invocation updates; it never blocks the controller. If the sink cannot
+// keep up, events are dropped rather than buffered indefinitely.
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util/backoff"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	SpecVersion = "1.0"
+
+	TypeInvocationCreated             = "io.fission.workflows.invocation.created"
+	TypeInvocationCompleted           = "io.fission.workflows.invocation.completed"
+	TypeInvocationFailed              = "io.fission.workflows.invocation.failed"
+	TypeInvocationCanceled            = "io.fission.workflows.invocation.canceled"
+	TypeInvocationSoftTimeoutExceeded = "io.fission.workflows.invocation.soft-timeout-exceeded"
+	TypeInvocationSummary             = "io.fission.workflows.invocation.summary"
+	TypeInvocationReplayed            = "io.fission.workflows.invocation.replayed"
+	TypeTaskStarted                   = "io.fission.workflows.task.started"
+	TypeTaskSucceeded                 = "io.fission.workflows.task.succeeded"
+	TypeTaskFailed                    = "io.fission.workflows.task.failed"
+	TypeTaskSkipped                   = "io.fission.workflows.task.skipped"
+
+	DefaultSource      = "/fission-workflows"
+	DefaultTimeout     = 10 * time.Second
+	DefaultMaxAttempts = 3
+	DefaultQueueSize   = 1000
+
+	contentType    = "application/cloudevents+json; charset=utf-8"
+	dataMediaType  = "application/json"
+	defaultWorkers = 4
+)
+
+// eventTypes maps the internal event types to the types of the CloudEvents.
+var eventTypes = map[string]string{
+	events.EventInvocationCreated:             TypeInvocationCreated,
+	events.EventInvocationCompleted:           TypeInvocationCompleted,
+	events.EventInvocationFailed:              TypeInvocationFailed,
+	events.EventInvocationCanceled:            TypeInvocationCanceled,
+	events.EventInvocationSoftTimeoutExceeded: TypeInvocationSoftTimeoutExceeded,
+	events.EventInvocationSummary:             TypeInvocationSummary,
+	events.EventInvocationTasksReplayed:       TypeInvocationReplayed,
+	events.EventTaskStarted:                   TypeTaskStarted,
+	events.EventTaskSucceeded:                 TypeTaskSucceeded,
+	events.EventTaskFailed:                    TypeTaskFailed,
+	events.EventTaskSkipped:                   TypeTaskSkipped,
+}
+
+var log = logrus.WithField("component", "cloudevents")
+
+var metricEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "cloudevents",
+	Name:      "events_total",
+	Help:      "Number of CloudEvents by whether they were delivered, failed to be delivered, or dropped",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(metricEvents)
+}
+
+// Config contains the configuration of the CloudEvents publisher.
+type Config struct {
+	// SinkURL is the endpoint to which the CloudEvents are POSTed.
+	SinkURL string
+
+	// Source is the source attribute of the CloudEvents. Defaults to DefaultSource.
+	Source string
+
+	// Types contains the types of the CloudEvents to publish. If empty, all types are published.
+	Types []string
+
+	// Timeout is the maximum duration of a single delivery attempt. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// MaxAttempts is the maximum number of attempts to deliver a single event. Defaults to DefaultMaxAttempts.
+	MaxAttempts int
+
+	// QueueSize is the number of events that can await delivery, after which events are dropped. Defaults to
+	// DefaultQueueSize.
+	QueueSize int
+}
+
+// Event is a CloudEvent in the JSON format.
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// Data is the data of the published CloudEvents.
+type Data struct {
+	InvocationID string       `json:"invocationId"`
+	WorkflowID   string       `json:"workflowId,omitempty"`
+	TaskID       string       `json:"taskId,omitempty"`
+	Status       string       `json:"status"`
+	Error        *types.Error `json:"error,omitempty"`
+
+	// Summary contains the InvocationSummary, in the JSON encoding of protobuf, of the summary events.
+	Summary json.RawMessage `json:"summary,omitempty"`
+}
+
+// Publisher listens for invocation updates and publishes the relevant ones as CloudEvents to the sink.
+type Publisher struct {
+	config      Config
+	client      *http.Client
+	invocations *store.Invocations
+	types       map[string]bool
+	queue       chan *Event
+	done        func()
+	closeC      <-chan struct{}
+}
+
+// NewPublisher creates a publisher, returning an error if the configuration contains unknown event types.
+func NewPublisher(invocations *store.Invocations, config Config) (*Publisher, error) {
+	if len(config.Source) == 0 {
+		config.Source = DefaultSource
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	filter := map[string]bool{}
+	for _, eventType := range config.Types {
+		if !isSupportedType(eventType) {
+			return nil, fmt.Errorf("unknown CloudEvent type '%s' (supported: %v)", eventType, SupportedTypes())
+		}
+		filter[eventType] = true
+	}
+	ctx, done := context.WithCancel(context.Background())
+	return &Publisher{
+		config:      config,
+		client:      &http.Client{Timeout: config.Timeout},
+		invocations: invocations,
+		types:       filter,
+		queue:       make(chan *Event, config.QueueSize),
+		done:        done,
+		closeC:      ctx.Done(),
+	}, nil
+}
+
+// SupportedTypes returns the sorted types of the CloudEvents that can be published.
+func SupportedTypes() []string {
+	var supported []string
+	for _, eventType := range eventTypes {
+		supported = append(supported, eventType)
+	}
+	sort.Strings(supported)
+	return supported
+}
+
+func isSupportedType(eventType string) bool {
+	for _, supported := range eventTypes {
+		if supported == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Run listens for invocation updates until the publisher is closed.
+func (p *Publisher) Run() error {
+	sub := p.invocations.GetInvocationUpdates()
+	if sub == nil {
+		return fmt.Errorf("invocation store does not support pubsub")
+	}
+	for i := 0; i < defaultWorkers; i++ {
+		go p.deliverQueued()
+	}
+	log.Infof("Publishing CloudEvents to %s", p.config.SinkURL)
+	for {
+		select {
+		case msg := <-sub.Ch:
+			notification, err := sub.ToNotification(msg)
+			if err != nil {
+				log.Warnf("Failed to convert pubsub message to notification: %v", err)
+				continue
+			}
+			p.Notify(notification)
+		case <-p.closeC:
+			return sub.Close()
+		}
+	}
+}
+
+// Notify queues the CloudEvent of the notification for delivery, if the event is relevant to the sink. It does not
+// block; if the queue is full, the event is dropped.
+func (p *Publisher) Notify(notification *fes.Notification) {
+	event, ok := p.createEvent(notification)
+	if !ok {
+		return
+	}
+	select {
+	case p.queue <- event:
+	default:
+		log.Warnf("Dropping CloudEvent %s of %s: delivery queue is full", event.Type, event.Subject)
+		metricEvents.WithLabelValues("dropped").Inc()
+	}
+}
+
+func (p *Publisher) Close() error {
+	p.done()
+	return nil
+}
+
+func (p *Publisher) createEvent(notification *fes.Notification) (*Event, bool) {
+	event := notification.Event
+	invocation, ok := notification.Updated.(*types.WorkflowInvocation)
+	if !ok || event == nil {
+		return nil, false
+	}
+	eventType, ok := eventTypes[event.GetType()]
+	if !ok || (len(p.types) > 0 && !p.types[eventType]) {
+		return nil, false
+	}
+
+	data := &Data{
+		InvocationID: invocation.ID(),
+		WorkflowID:   invocation.GetSpec().GetWorkflowId(),
+		Status:       invocation.GetStatus().GetStatus().String(),
+		Error:        invocation.GetStatus().GetError(),
+	}
+	subject := invocation.ID()
+	if event.GetAggregate().GetType() == types.TypeTaskRun {
+		data.TaskID = event.GetAggregate().GetId()
+		subject = fmt.Sprintf("%s/%s", invocation.ID(), data.TaskID)
+		task, _ := invocation.TaskInvocation(data.TaskID)
+		data.Status = task.GetStatus().GetStatus().String()
+		data.Error = task.GetStatus().GetError()
+	}
+	if event.GetType() == events.EventInvocationSummary {
+		summary, err := encodeSummary(event)
+		if err != nil {
+			log.Errorf("Failed to encode the summary of %s: %v", subject, err)
+			return nil, false
+		}
+		data.Summary = summary
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Errorf("Failed to encode CloudEvent data of %s: %v", subject, err)
+		return nil, false
+	}
+
+	ce := &Event{
+		SpecVersion:     SpecVersion,
+		Source:          p.config.Source,
+		Type:            eventType,
+		Subject:         subject,
+		DataContentType: dataMediaType,
+		Data:            encoded,
+	}
+	ts, err := ptypes.Timestamp(event.GetTimestamp())
+	if err == nil {
+		ce.Time = &ts
+	}
+	// The ID of the event is only unique within its aggregate; if absent, the ID is derived from the event instead.
+	if len(event.GetId()) > 0 {
+		ce.ID = fmt.Sprintf("%s-%s", event.GetAggregate().GetId(), event.GetId())
+	} else {
+		ce.ID = fmt.Sprintf("%s-%s-%d", event.GetAggregate().GetId(), event.GetType(), ts.UnixNano())
+	}
+	return ce, true
+}
+
+func (p *Publisher) deliverQueued() {
+	for {
+		select {
+		case event := <-p.queue:
+			p.deliver(event)
+		case <-p.closeC:
+			return
+		}
+	}
+}
+
+func (p *Publisher) deliver(event *Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("Failed to encode CloudEvent %s: %v", event.ID, err)
+		metricEvents.WithLabelValues("failed").Inc()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-p.closeC:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for attempt := range (&backoff.Instance{
+		MaxRetries:        p.config.MaxAttempts,
+		BaseRetryDuration: 100 * time.Millisecond,
+		BackoffPolicy:     backoff.ExponentialBackoff,
+	}).C(ctx) {
+		err = p.post(body)
+		if err == nil {
+			metricEvents.WithLabelValues("delivered").Inc()
+			return
+		}
+		log.Debugf("Failed to deliver CloudEvent %s (%d/%d): %v", event.ID, attempt+1, p.config.MaxAttempts, err)
+	}
+	log.Errorf("Failed to deliver CloudEvent %s: %v", event.ID, err)
+	metricEvents.WithLabelValues("failed").Inc()
+}
+
+func (p *Publisher) post(body []byte) error {
+	resp, err := p.client.Post(p.config.SinkURL, contentType, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+func encodeSummary(event *fes.Event) (json.RawMessage, error) {
+	summary := &events.InvocationSummary{}
+	if err := ptypes.UnmarshalAny(event.GetData(), summary); err != nil {
+		return nil, err
+	}
+	encoded, err := (&jsonpb.Marshaler{}).MarshalToString(summary)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(encoded), nil
+}
@@ -0,0 +1,120 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newInvocation() *types.WorkflowInvocation {
+	return &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{Id: "wi-1"},
+		Spec:     &types.WorkflowInvocationSpec{WorkflowId: "wf-1"},
+		Status: &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_IN_PROGRESS,
+			Tasks: map[string]*types.TaskInvocation{
+				"fetch": {Status: &types.TaskInvocationStatus{
+					Status: types.TaskInvocationStatus_FAILED,
+					Error:  &types.Error{Message: "boom", Code: types.ErrorCodeFunction},
+				}},
+			},
+		},
+	}
+}
+
+func newNotification(t *testing.T, aggregate fes.Aggregate, payload proto.Message) *fes.Notification {
+	event, err := fes.NewEvent(aggregate, payload)
+	assert.NoError(t, err)
+	parent := projectors.NewInvocationAggregate("wi-1")
+	event.Parent = &parent
+	return fes.NewNotification(nil, newInvocation(), event)
+}
+
+func TestPublisher_CreateEvent(t *testing.T) {
+	publisher, err := NewPublisher(nil, Config{SinkURL: "http://localhost", Source: "/test"})
+	assert.NoError(t, err)
+
+	event, ok := publisher.createEvent(newNotification(t, projectors.NewInvocationAggregate("wi-1"),
+		&events.InvocationCreated{}))
+	assert.True(t, ok)
+	assert.Equal(t, SpecVersion, event.SpecVersion)
+	assert.Equal(t, TypeInvocationCreated, event.Type)
+	assert.Equal(t, "/test", event.Source)
+	assert.Equal(t, "wi-1", event.Subject)
+	assert.NotEmpty(t, event.ID)
+	assert.NotNil(t, event.Time)
+	assert.JSONEq(t, `{"invocationId": "wi-1", "workflowId": "wf-1", "status": "IN_PROGRESS"}`, string(event.Data))
+
+	event, ok = publisher.createEvent(newNotification(t, projectors.NewTaskRunAggregate("fetch"),
+		&events.TaskFailed{}))
+	assert.True(t, ok)
+	assert.Equal(t, TypeTaskFailed, event.Type)
+	assert.Equal(t, "wi-1/fetch", event.Subject)
+	assert.JSONEq(t, `{"invocationId": "wi-1", "workflowId": "wf-1", "taskId": "fetch", "status": "FAILED",
+		"error": {"message": "boom", "code": "FUNCTION_ERROR"}}`, string(event.Data))
+
+	event, ok = publisher.createEvent(newNotification(t, projectors.NewInvocationAggregate("wi-1"),
+		&events.InvocationSummary{Status: types.WorkflowInvocationStatus_FAILED, TaskCount: 1, FailedTaskCount: 1}))
+	assert.True(t, ok)
+	assert.Equal(t, TypeInvocationSummary, event.Type)
+	assert.JSONEq(t, `{"invocationId": "wi-1", "workflowId": "wf-1", "status": "IN_PROGRESS",
+		"summary": {"status": "FAILED", "taskCount": 1, "failedTaskCount": 1}}`, string(event.Data))
+
+	// Events that do not correspond to a lifecycle transition are not published.
+	_, ok = publisher.createEvent(newNotification(t, projectors.NewInvocationAggregate("wi-1"),
+		&events.InvocationTaskAdded{}))
+	assert.False(t, ok)
+}
+
+func TestPublisher_Filter(t *testing.T) {
+	_, err := NewPublisher(nil, Config{SinkURL: "http://localhost", Types: []string{"unknown"}})
+	assert.Error(t, err)
+
+	publisher, err := NewPublisher(nil, Config{SinkURL: "http://localhost", Types: []string{TypeTaskFailed}})
+	assert.NoError(t, err)
+	_, ok := publisher.createEvent(newNotification(t, projectors.NewInvocationAggregate("wi-1"),
+		&events.InvocationCreated{}))
+	assert.False(t, ok)
+	_, ok = publisher.createEvent(newNotification(t, projectors.NewTaskRunAggregate("fetch"), &events.TaskFailed{}))
+	assert.True(t, ok)
+}
+
+func TestPublisher_NotifyDoesNotBlock(t *testing.T) {
+	publisher, err := NewPublisher(nil, Config{SinkURL: "http://localhost", QueueSize: 1})
+	assert.NoError(t, err)
+
+	// Without workers draining the queue, the events beyond the queue size are dropped.
+	publisher.Notify(newNotification(t, projectors.NewInvocationAggregate("wi-1"), &events.InvocationCreated{}))
+	publisher.Notify(newNotification(t, projectors.NewTaskRunAggregate("fetch"), &events.TaskFailed{}))
+	assert.Len(t, publisher.queue, 1)
+}
+
+func TestPublisher_Deliver(t *testing.T) {
+	received := make(chan *Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, contentType, r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		event := &Event{}
+		assert.NoError(t, json.Unmarshal(body, event))
+		received <- event
+	}))
+	defer server.Close()
+
+	publisher, err := NewPublisher(nil, Config{SinkURL: server.URL})
+	assert.NoError(t, err)
+	event, ok := publisher.createEvent(newNotification(t, projectors.NewInvocationAggregate("wi-1"),
+		&events.InvocationCreated{}))
+	assert.True(t, ok)
+	publisher.deliver(event)
+	assert.Equal(t, event.ID, (<-received).ID)
+}
@@ -0,0 +1,192 @@
+// Package configmap resolves references to Kubernetes ConfigMaps in the inputs of tasks.
+//
+// This allows operators to maintain environment-specific configuration (such as endpoints or feature flags) outside
+// of the workflow definitions. A task input references a key of a ConfigMap as follows:
+//
+//  inputs:
+//    endpoint:
+//      configMap: my-config/endpoint
+//
+// The references are resolved when the task is scheduled. Only the ConfigMaps that have been explicitly allowed by
+// the operator can be referenced. Note that, in contrast to secrets, the resolved values are not treated as
+// sensitive; they can end up in the logs and in the status of the invocation.
+package configmap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ReferenceKey is the key of the (single-entry) map that identifies a ConfigMap reference in a task input.
+	ReferenceKey = "configMap"
+
+	DefaultCacheTTL = time.Minute
+)
+
+var (
+	ErrInvalidReference = errors.New("config map reference should be of the form 'name/key'")
+	ErrNotAllowed       = errors.New("config map is not allowed to be referenced")
+	ErrKeyNotFound      = errors.New("key not found in config map")
+)
+
+var log = logrus.WithField("component", "configmap")
+
+// Config contains the configuration of the ConfigMap resolver.
+type Config struct {
+	// Namespace is the namespace from which the ConfigMaps are read.
+	Namespace string
+
+	// Allowed contains the names of the ConfigMaps that tasks are allowed to reference.
+	Allowed []string
+
+	// CacheTTL is the duration that the contents of a ConfigMap are cached. Defaults to DefaultCacheTTL.
+	CacheTTL time.Duration
+}
+
+// Getter fetches the data of a ConfigMap.
+type Getter interface {
+	Get(namespace string, name string) (map[string]string, error)
+}
+
+// KubernetesGetter fetches ConfigMaps from the Kubernetes API server.
+type KubernetesGetter struct {
+	Client kubernetes.Interface
+}
+
+func (g *KubernetesGetter) Get(namespace string, name string) (map[string]string, error) {
+	cm, err := g.Client.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return cm.Data, nil
+}
+
+type cacheEntry struct {
+	data      map[string]string
+	expiresAt time.Time
+}
+
+// Resolver resolves ConfigMap references, caching the contents of the ConfigMaps to avoid overloading the API
+// server.
+type Resolver struct {
+	config  Config
+	getter  Getter
+	allowed map[string]struct{}
+	cache   map[string]cacheEntry
+	cacheMu *sync.Mutex
+}
+
+func NewResolver(getter Getter, config Config) *Resolver {
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultCacheTTL
+	}
+	allowed := map[string]struct{}{}
+	for _, name := range config.Allowed {
+		allowed[name] = struct{}{}
+	}
+	return &Resolver{
+		config:  config,
+		getter:  getter,
+		allowed: allowed,
+		cache:   map[string]cacheEntry{},
+		cacheMu: &sync.Mutex{},
+	}
+}
+
+// Resolve returns the value of the key in the referenced ConfigMap.
+func (r *Resolver) Resolve(name string, key string) (string, error) {
+	if _, ok := r.allowed[name]; !ok {
+		return "", fmt.Errorf("%v: %s", ErrNotAllowed, name)
+	}
+
+	data, err := r.get(name)
+	if err != nil {
+		return "", err
+	}
+	val, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("%v: %s/%s", ErrKeyNotFound, name, key)
+	}
+	return val, nil
+}
+
+// ResolveInputs replaces the ConfigMap references in the inputs with the referenced values. Inputs that are not
+// references are returned as is.
+func (r *Resolver) ResolveInputs(inputs map[string]*typedvalues.TypedValue) (map[string]*typedvalues.TypedValue,
+	error) {
+	resolved := make(map[string]*typedvalues.TypedValue, len(inputs))
+	for k, input := range inputs {
+		val, err := r.ResolveInput(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve input '%s': %v", k, err)
+		}
+		resolved[k] = val
+	}
+	return resolved, nil
+}
+
+// ResolveInput replaces the input with the referenced value if it is a ConfigMap reference. Otherwise, the input is
+// returned as is.
+func (r *Resolver) ResolveInput(input *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+	ref, ok := ParseReference(input)
+	if !ok {
+		return input, nil
+	}
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return nil, fmt.Errorf("%v: '%s'", ErrInvalidReference, ref)
+	}
+	val, err := r.Resolve(parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Resolved config map reference: %s -> %s", ref, val)
+	return typedvalues.MustWrap(val), nil
+}
+
+func (r *Resolver) get(name string) (map[string]string, error) {
+	r.cacheMu.Lock()
+	entry, ok := r.cache[name]
+	r.cacheMu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.data, nil
+	}
+
+	data, err := r.getter.Get(r.config.Namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config map %s/%s: %v", r.config.Namespace, name, err)
+	}
+	r.cacheMu.Lock()
+	r.cache[name] = cacheEntry{
+		data:      data,
+		expiresAt: time.Now().Add(r.config.CacheTTL),
+	}
+	r.cacheMu.Unlock()
+	return data, nil
+}
+
+// ParseReference returns the 'name/key' ConfigMap reference of the input, if the input is a reference.
+func ParseReference(input *typedvalues.TypedValue) (string, bool) {
+	if input.ValueType() != typedvalues.TypeMap {
+		return "", false
+	}
+	i, err := typedvalues.Unwrap(input)
+	if err != nil {
+		return "", false
+	}
+	mp, ok := i.(map[string]interface{})
+	if !ok || len(mp) != 1 {
+		return "", false
+	}
+	ref, ok := mp[ReferenceKey].(string)
+	return ref, ok
+}
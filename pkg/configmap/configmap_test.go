@@ -0,0 +1,79 @@
+package configmap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockGetter struct {
+	configMaps map[string]map[string]string
+	calls      int
+}
+
+func (g *mockGetter) Get(namespace string, name string) (map[string]string, error) {
+	g.calls++
+	data, ok := g.configMaps[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func setupResolver() (*Resolver, *mockGetter) {
+	getter := &mockGetter{
+		configMaps: map[string]map[string]string{
+			"endpoints": {"api": "http://api.example.com"},
+			"secret":    {"password": "hunter2"},
+		},
+	}
+	return NewResolver(getter, Config{
+		Namespace: "default",
+		Allowed:   []string{"endpoints"},
+	}), getter
+}
+
+func TestResolver_ResolveInputs(t *testing.T) {
+	resolver, getter := setupResolver()
+	inputs := map[string]*typedvalues.TypedValue{
+		"endpoint": typedvalues.MustWrap(map[string]interface{}{
+			ReferenceKey: "endpoints/api",
+		}),
+		"other": typedvalues.MustWrap("foo"),
+	}
+
+	resolved, err := resolver.ResolveInputs(inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://api.example.com", typedvalues.MustUnwrap(resolved["endpoint"]))
+	assert.Equal(t, "foo", typedvalues.MustUnwrap(resolved["other"]))
+
+	// The second resolve should be served from the cache
+	_, err = resolver.ResolveInputs(inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, getter.calls)
+}
+
+func TestResolver_ResolveNotAllowed(t *testing.T) {
+	resolver, getter := setupResolver()
+	_, err := resolver.Resolve("secret", "password")
+	assert.Error(t, err)
+	assert.Equal(t, 0, getter.calls)
+}
+
+func TestResolver_ResolveInvalidReference(t *testing.T) {
+	resolver, _ := setupResolver()
+	_, err := resolver.ResolveInputs(map[string]*typedvalues.TypedValue{
+		"endpoint": typedvalues.MustWrap(map[string]interface{}{
+			ReferenceKey: "endpoints",
+		}),
+	})
+	assert.Error(t, err)
+}
+
+func TestResolver_ResolveMissingKey(t *testing.T) {
+	resolver, _ := setupResolver()
+	_, err := resolver.Resolve("endpoints", "nonExistent")
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	metricInFlightTasks = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "inflight_tasks",
+		Help:      "Number of task executions that have been admitted, but of which the result has not been ingested yet",
+	})
+	metricInFlightTasksLimit = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "inflight_tasks_limit",
+		Help:      "Maximum number of concurrent task executions (0 = unlimited)",
+	})
+	metricTaskAdmissionsRejected = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "task_admissions_rejected_total",
+		Help:      "Number of task executions that were deferred because the in-flight task limit was reached",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(metricInFlightTasks, metricInFlightTasksLimit, metricTaskAdmissionsRejected)
+}
+
+// TaskAdmission bounds the number of task executions that are outstanding across all invocations.
+//
+// The executor only bounds the number of worker goroutines, whereas a single worker can be blocked for a long time
+// on a slow function. To bound the actual number of concurrent function calls, a slot is acquired when a task
+// execution is submitted, and only released once the result of the execution has been ingested.
+//
+// A nil TaskAdmission admits all task executions.
+type TaskAdmission struct {
+	limit    int
+	inFlight int
+	mu       *sync.Mutex
+}
+
+// NewTaskAdmission creates a TaskAdmission that admits at most limit concurrent task executions. If the limit is 0
+// or less, the number of task executions is not bounded.
+func NewTaskAdmission(limit int) *TaskAdmission {
+	if limit < 0 {
+		limit = 0
+	}
+	metricInFlightTasksLimit.Set(float64(limit))
+	return &TaskAdmission{
+		limit: limit,
+		mu:    &sync.Mutex{},
+	}
+}
+
+// TryAcquire attempts to admit a task execution without blocking. Every successful acquire should be followed by a
+// Release once the result of the task execution has been ingested.
+func (a *TaskAdmission) TryAcquire() bool {
+	if a == nil {
+		return true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.limit > 0 && a.inFlight >= a.limit {
+		metricTaskAdmissionsRejected.Inc()
+		return false
+	}
+	a.inFlight++
+	metricInFlightTasks.Set(float64(a.inFlight))
+	return true
+}
+
+// Release releases the slot of an admitted task execution.
+func (a *TaskAdmission) Release() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.inFlight > 0 {
+		a.inFlight--
+	}
+	metricInFlightTasks.Set(float64(a.inFlight))
+}
+
+// InFlight returns the number of admitted task executions that have not been released yet.
+func (a *TaskAdmission) InFlight() int {
+	if a == nil {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.inFlight
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskAdmission(t *testing.T) {
+	admission := NewTaskAdmission(2)
+	assert.True(t, admission.TryAcquire())
+	assert.True(t, admission.TryAcquire())
+	assert.False(t, admission.TryAcquire())
+	assert.Equal(t, 2, admission.InFlight())
+
+	admission.Release()
+	assert.True(t, admission.TryAcquire())
+	assert.Equal(t, 2, admission.InFlight())
+}
+
+func TestTaskAdmission_Unlimited(t *testing.T) {
+	var admission *TaskAdmission
+	assert.True(t, admission.TryAcquire())
+	admission.Release()
+
+	admission = NewTaskAdmission(0)
+	for i := 0; i < 100; i++ {
+		assert.True(t, admission.TryAcquire())
+	}
+	assert.Equal(t, 100, admission.InFlight())
+}
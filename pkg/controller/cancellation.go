@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// maxCancelDecisions is the number of cancel propagation decisions that are kept for debugging.
+const maxCancelDecisions = 1000
+
+var metricCancelPropagations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "cancel_propagations_total",
+	Help:      "Number of sub-workflow invocations of canceled invocations, by cancel propagation mode",
+}, []string{"mode"})
+
+func init() {
+	prometheus.MustRegister(metricCancelPropagations)
+}
+
+// CancelDecision describes whether the cancellation of an invocation was propagated to one of its sub-workflow
+// invocations.
+type CancelDecision struct {
+	// InvocationID is the id of the sub-workflow invocation.
+	InvocationID string
+	ParentID     string
+	ParentTaskID string
+
+	// Mode is the cancel propagation mode of the parent task: cascade or detach.
+	Mode string
+
+	// Canceled indicates that the sub-workflow invocation was canceled along with its parent.
+	Canceled bool
+
+	// Err contains the error that prevented the sub-workflow invocation from being canceled, if any.
+	Err       string
+	DecidedAt time.Time
+}
+
+// InvocationHierarchy is an invocation along with its sub-workflow invocations, and their sub-workflow invocations.
+type InvocationHierarchy struct {
+	ID           string
+	Status       types.WorkflowInvocationStatus_Status
+	ParentTaskID string
+
+	// CancelPropagation is the cancel propagation mode of the parent task that started the invocation, if the
+	// invocation is a sub-workflow invocation.
+	CancelPropagation string
+
+	// Decision is the decision to propagate the cancellation of the parent to the invocation, if the parent has been
+	// canceled.
+	Decision *CancelDecision
+
+	Children []*InvocationHierarchy
+}
+
+// CancelPropagation propagates the cancellation of invocations to their sub-workflow invocations. Whether a
+// sub-workflow invocation is canceled along with its parent is determined by the cancel propagation mode of the task
+// of the parent that started it: cascade, which is the default, or detach.
+//
+// The cancellation is propagated through the hierarchy: the sub-workflow invocations of a canceled sub-workflow
+// invocation are canceled according to the modes of its tasks, whereas the hierarchy below a detached sub-workflow
+// invocation is left running. The decisions are kept in memory for debugging, up to maxCancelDecisions.
+type CancelPropagation struct {
+	invocations *store.Invocations
+	cancel      func(invocationID string) error
+	decisions   map[string]CancelDecision
+	order       []string
+	mu          *sync.Mutex
+}
+
+func NewCancelPropagation(invocations *store.Invocations, cancel func(invocationID string) error) *CancelPropagation {
+	return &CancelPropagation{
+		invocations: invocations,
+		cancel:      cancel,
+		decisions:   map[string]CancelDecision{},
+		mu:          &sync.Mutex{},
+	}
+}
+
+// Propagate walks the hierarchy of sub-workflow invocations of the canceled invocation, and cancels the sub-workflow
+// invocations of which the parent task cascades the cancellation. The sub-workflow invocations that have finished, or
+// that have been decided on before, are skipped.
+func (p *CancelPropagation) Propagate(invocation *types.WorkflowInvocation) []CancelDecision {
+	var decisions []CancelDecision
+	parents := []*types.WorkflowInvocation{invocation}
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+		children, err := p.invocations.GetSubInvocations(parent.ID())
+		if err != nil {
+			logrus.Warnf("Failed to list the sub-workflow invocations of canceled invocation %s: %v", parent.ID(), err)
+			continue
+		}
+		for _, child := range children {
+			if child.GetStatus().Finished() {
+				continue
+			}
+			if _, ok := p.Decision(child.ID()); ok {
+				continue
+			}
+			decision := CancelDecision{
+				InvocationID: child.ID(),
+				ParentID:     parent.ID(),
+				ParentTaskID: child.GetSpec().GetParentTaskId(),
+				Mode:         cancelPropagationMode(parent, child),
+				DecidedAt:    time.Now(),
+			}
+			if decision.Mode == types.CancelPropagationCascade {
+				if err := p.cancel(child.ID()); err != nil {
+					decision.Err = err.Error()
+					logrus.Warnf("Failed to cancel sub-workflow invocation %s of canceled invocation %s: %v",
+						child.ID(), parent.ID(), err)
+				} else {
+					decision.Canceled = true
+					parents = append(parents, child)
+					logrus.Infof("Canceled sub-workflow invocation %s of canceled invocation %s (task: %s)",
+						child.ID(), parent.ID(), decision.ParentTaskID)
+				}
+			} else {
+				logrus.Infof("Detached sub-workflow invocation %s from canceled invocation %s (task: %s)",
+					child.ID(), parent.ID(), decision.ParentTaskID)
+			}
+			metricCancelPropagations.WithLabelValues(decision.Mode).Inc()
+			p.record(decision)
+			decisions = append(decisions, decision)
+		}
+	}
+	return decisions
+}
+
+// Decision returns the decision to propagate the cancellation of its parent to the sub-workflow invocation, if any.
+func (p *CancelPropagation) Decision(invocationID string) (CancelDecision, bool) {
+	if p == nil {
+		return CancelDecision{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	decision, ok := p.decisions[invocationID]
+	return decision, ok
+}
+
+// Hierarchy returns the invocation along with the hierarchy of its sub-workflow invocations, and the decisions to
+// propagate the cancellation of the invocations to them. If the invocation does not exist, nil is returned.
+func (p *CancelPropagation) Hierarchy(invocationID string) (*InvocationHierarchy, error) {
+	invocation, err := p.invocations.GetInvocation(invocationID)
+	if fes.ErrEntityNotFound.Is(err) {
+		return nil, nil
+	}
+	if err != nil || invocation == nil {
+		return nil, err
+	}
+	return p.hierarchy(invocation, nil, map[string]bool{})
+}
+
+func (p *CancelPropagation) hierarchy(invocation *types.WorkflowInvocation, parent *types.WorkflowInvocation,
+	visited map[string]bool) (*InvocationHierarchy, error) {
+	visited[invocation.ID()] = true
+	node := &InvocationHierarchy{
+		ID:           invocation.ID(),
+		Status:       invocation.GetStatus().GetStatus(),
+		ParentTaskID: invocation.GetSpec().GetParentTaskId(),
+	}
+	if parent != nil {
+		node.CancelPropagation = cancelPropagationMode(parent, invocation)
+	}
+	if decision, ok := p.Decision(invocation.ID()); ok {
+		node.Decision = &decision
+	}
+	children, err := p.invocations.GetSubInvocations(invocation.ID())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].ID() < children[j].ID()
+	})
+	for _, child := range children {
+		if visited[child.ID()] {
+			continue
+		}
+		childNode, err := p.hierarchy(child, invocation, visited)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, childNode)
+	}
+	return node, nil
+}
+
+func (p *CancelPropagation) record(decision CancelDecision) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.decisions[decision.InvocationID]; !ok {
+		p.order = append(p.order, decision.InvocationID)
+	}
+	p.decisions[decision.InvocationID] = decision
+	for len(p.order) > maxCancelDecisions {
+		delete(p.decisions, p.order[0])
+		p.order = p.order[1:]
+	}
+}
+
+// cancelPropagationMode returns the cancel propagation mode of the task of the parent that started the sub-workflow
+// invocation. If the task is unknown, the cancellation is cascaded.
+func cancelPropagationMode(parent *types.WorkflowInvocation, child *types.WorkflowInvocation) string {
+	task, ok := parent.Task(child.GetSpec().GetParentTaskId())
+	if !ok {
+		return types.CancelPropagationCascade
+	}
+	return task.GetSpec().CancelPropagationMode()
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHierarchyInvocation(id string, parentID string, parentTaskID string, status types.WorkflowInvocationStatus_Status,
+	tasks map[string]*types.TaskSpec) *types.WorkflowInvocation {
+	wf := types.NewWorkflow("wf-" + id)
+	wf.Spec.Tasks = tasks
+	spec := types.NewWorkflowInvocationSpec(wf.ID(), time.Now().Add(time.Minute))
+	spec.Workflow = wf
+	spec.ParentId = parentID
+	spec.ParentTaskId = parentTaskID
+	return &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata(id),
+		Spec:     spec,
+		Status:   &types.WorkflowInvocationStatus{Status: status},
+	}
+}
+
+func setupCancelPropagation(t *testing.T) (*CancelPropagation, *types.WorkflowInvocation, *[]string) {
+	inProgress := types.WorkflowInvocationStatus_IN_PROGRESS
+	cache := testutil.NewCache()
+	parent := newHierarchyInvocation("parent", "", "", types.WorkflowInvocationStatus_ABORTED,
+		map[string]*types.TaskSpec{
+			"process": {FunctionRef: "process"},
+			"notify":  {FunctionRef: "notify", CancelPropagation: types.CancelPropagationDetach},
+			"audit":   {FunctionRef: "audit", CancelPropagation: types.CancelPropagationCascade},
+		})
+	invocations := []*types.WorkflowInvocation{
+		parent,
+		newHierarchyInvocation("process-child", "parent", "process", inProgress,
+			map[string]*types.TaskSpec{"step": {FunctionRef: "step"}}),
+		newHierarchyInvocation("process-grandchild", "process-child", "step", inProgress, nil),
+		newHierarchyInvocation("notify-child", "parent", "notify", inProgress,
+			map[string]*types.TaskSpec{"step": {FunctionRef: "step"}}),
+		newHierarchyInvocation("notify-grandchild", "notify-child", "step", inProgress, nil),
+		newHierarchyInvocation("audit-child", "parent", "audit", types.WorkflowInvocationStatus_SUCCEEDED, nil),
+	}
+	for _, invocation := range invocations {
+		assert.NoError(t, cache.Put(invocation))
+	}
+	var canceled []string
+	propagation := NewCancelPropagation(store.NewInvocationStore(cache), func(invocationID string) error {
+		canceled = append(canceled, invocationID)
+		return nil
+	})
+	return propagation, parent, &canceled
+}
+
+func TestCancelPropagation_Cascade(t *testing.T) {
+	propagation, parent, canceled := setupCancelPropagation(t)
+
+	propagation.Propagate(parent)
+	assert.Equal(t, []string{"process-child", "process-grandchild"}, *canceled)
+	decision, ok := propagation.Decision("process-child")
+	assert.True(t, ok)
+	assert.Equal(t, types.CancelPropagationCascade, decision.Mode)
+	assert.Equal(t, "process", decision.ParentTaskID)
+	assert.True(t, decision.Canceled)
+	decision, ok = propagation.Decision("process-grandchild")
+	assert.True(t, ok)
+	assert.Equal(t, "process-child", decision.ParentID)
+	assert.True(t, decision.Canceled)
+
+	// Finished sub-workflow invocations are not canceled.
+	_, ok = propagation.Decision("audit-child")
+	assert.False(t, ok)
+
+	// The sub-workflow invocations are only canceled once.
+	propagation.Propagate(parent)
+	assert.Len(t, *canceled, 2)
+}
+
+func TestCancelPropagation_Detach(t *testing.T) {
+	propagation, parent, canceled := setupCancelPropagation(t)
+
+	decisions := propagation.Propagate(parent)
+	assert.NotContains(t, *canceled, "notify-child")
+	assert.NotContains(t, *canceled, "notify-grandchild")
+	decision, ok := propagation.Decision("notify-child")
+	assert.True(t, ok)
+	assert.Equal(t, types.CancelPropagationDetach, decision.Mode)
+	assert.False(t, decision.Canceled)
+	assert.Contains(t, decisions, decision)
+
+	// The hierarchy below a detached sub-workflow invocation is left alone.
+	_, ok = propagation.Decision("notify-grandchild")
+	assert.False(t, ok)
+
+	hierarchy, err := propagation.Hierarchy("parent")
+	assert.NoError(t, err)
+	assert.Len(t, hierarchy.Children, 3)
+	notify := hierarchy.Children[1]
+	assert.Equal(t, "notify-child", notify.ID)
+	assert.Equal(t, types.CancelPropagationDetach, notify.CancelPropagation)
+	assert.Equal(t, &decision, notify.Decision)
+	assert.Len(t, notify.Children, 1)
+	assert.Equal(t, types.CancelPropagationCascade, notify.Children[0].CancelPropagation)
+	assert.Nil(t, notify.Children[0].Decision)
+
+	hierarchy, err = propagation.Hierarchy("unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, hierarchy)
+}
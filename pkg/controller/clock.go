@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricClockSkew = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "clock_skew_seconds",
+	Help: "Time by which the creation timestamps of invocations were ahead of the clock of the controller, " +
+		"which indicates clock skew between the nodes",
+	Buckets: []float64{.001, .01, .1, .5, 1, 5, 10, 30, 60},
+})
+
+func init() {
+	prometheus.MustRegister(metricClockSkew)
+}
+
+// The deadlines of invocations are derived from timestamps that are produced by other nodes than the one of the
+// controller; the creation time of an invocation is set by the API server that created it, and the deadline by the
+// client. If the clocks of these nodes are skewed, the controller would time out invocations prematurely, or too
+// late. The controller tolerates a skew of up to InvocationConfig.ClockSkewTolerance, by postponing the timeout
+// decisions by the tolerance: a deadline is only considered to have passed once it has passed on every clock within
+// the tolerance of the clock of the controller. Timeouts are therefore enforced up to the tolerance late, but never
+// early.
+
+// The controller reads the time from the clock in InvocationConfig.Clock rather than from time.Now, for the decisions
+// that depend on the passing of time: the deadlines of invocations and tasks, the polls of sensors and the staleness of
+// invocations. The evaluation system of the controller (see ctrl.System.SetClock), the quarantines and the suspensions
+// read the time from the same clock. The delays of the tasks submitted to the executor, such as the retries of
+// finalizations, are measured with the clock of the executor (see executor.NewLocalExecutorWithClock), which the
+// workflow controller uses as its clock. In tests, both can be set to the same fake clock, which is advanced instantly
+// instead of waiting for the time to pass. Durations that are only reported, such as the duration of a task run, are
+// measured in real time.
+
+// now returns the current time according to the clock of the controller.
+func (c *InvocationController) now() time.Time {
+	return c.config.Clock.Now()
+}
+
+// deadlineNow returns the time against which deadlines are checked: the current time minus the clock skew tolerance.
+func (c *InvocationController) deadlineNow() time.Time {
+	return c.now().Add(-c.config.ClockSkewTolerance)
+}
+
+// observeClockSkew reports the clock skew of the node that created the invocation, if its creation time is ahead of
+// now. Only the skew of clocks that are ahead can be observed this way; a clock that is behind cannot be told apart
+// from the latency of the invocation reaching the controller.
+func (c *InvocationController) observeClockSkew(invocation *types.WorkflowInvocation, now time.Time) {
+	if c.skewObserved {
+		return
+	}
+	c.skewObserved = true
+	skew := clockSkew(invocation, now)
+	if skew <= 0 {
+		return
+	}
+	metricClockSkew.Observe(skew.Seconds())
+	if skew > c.config.ClockSkewTolerance {
+		c.logger.Warnf("Invocation was created %v ahead of the clock of the controller, which exceeds the clock skew "+
+			"tolerance (%v); its timeouts may be enforced late", skew, c.config.ClockSkewTolerance)
+	}
+}
+
+// clockSkew returns the time by which the creation time of the invocation is ahead of now, or 0 if it is not.
+func clockSkew(invocation *types.WorkflowInvocation, now time.Time) time.Duration {
+	createdAt, err := ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt())
+	if err != nil || !createdAt.After(now) {
+		return 0
+	}
+	return createdAt.Sub(now)
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func TestClockSkew(t *testing.T) {
+	now := time.Now()
+	invocation := &types.WorkflowInvocation{Metadata: &types.ObjectMetadata{}}
+	assert.Equal(t, time.Duration(0), clockSkew(invocation, now))
+
+	invocation.Metadata.CreatedAt, _ = ptypes.TimestampProto(now.Add(-time.Second))
+	assert.Equal(t, time.Duration(0), clockSkew(invocation, now))
+
+	// The invocation was created by a node of which the clock is ahead.
+	invocation.Metadata.CreatedAt, _ = ptypes.TimestampProto(now.Add(3 * time.Second))
+	assert.Equal(t, 3*time.Second, clockSkew(invocation, now))
+}
+
+func TestClockSkewTolerance(t *testing.T) {
+	run := func(tolerance time.Duration) *types.WorkflowInvocation {
+		runtime := mock.NewRuntime()
+		runtime.ManualExecution = true
+		runtime.Functions["task"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+			return typedvalues.MustWrap("ok"), nil
+		}
+		backend := mem.NewBackend()
+		invocationAPI := api.NewInvocationAPI(backend)
+		taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+		exec := executor.NewLocalExecutor(1, 10)
+		exec.Start()
+		defer exec.Close()
+
+		wfSpec := types.NewWorkflowSpec()
+		wfSpec.AddTask("task", &types.TaskSpec{FunctionRef: "task"})
+		wfSpec.OutputTask = "task"
+		wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+			"task": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "task"}}},
+		}}
+		// The deadline was set by a client of which the clock is behind, so it has already passed on the clock of
+		// the controller.
+		spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(-500*time.Millisecond))
+		spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+		invocationID, err := invocationAPI.Invoke(spec)
+		assert.NoError(t, err)
+
+		c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+			scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(),
+			opentracing.StartSpan("wi"), TraceDecision{}, logrus.WithField("key", "wi"),
+			InvocationConfig{ClockSkewTolerance: tolerance})
+		var invocation *types.WorkflowInvocation
+		for i := 0; i < 3; i++ {
+			invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+			assert.NoError(t, err)
+			entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+			assert.NoError(t, err)
+			invocation = entity.(*types.WorkflowInvocation)
+			c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+			for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+		return invocation
+	}
+
+	invocation := run(0)
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, invocation.GetStatus().GetStatus())
+	assert.Contains(t, invocation.GetStatus().GetError().GetMessage(), "deadline exceeded")
+	assert.Equal(t, types.ErrorCodeTimeout, invocation.GetStatus().GetError().GetCode())
+
+	// Within the tolerance, the deadline is not considered to have passed yet.
+	invocation = run(5 * time.Second)
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, invocation.GetStatus().GetStatus())
+}
+
+// fakeClockController sets up a controller of an invocation of a workflow with a single task, of which both the
+// controller and the executor use the fake clock.
+func fakeClockController(t *testing.T, fakeClock *clock.FakeClock, backend *flakyBackend,
+	config InvocationConfig) (c *InvocationController, eval func() *types.WorkflowInvocation, closer func()) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["task"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("ok"), nil
+	}
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutorWithClock(1, 10, fakeClock)
+	exec.Start()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("task", &types.TaskSpec{FunctionRef: "task"})
+	wfSpec.OutputTask = "task"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"task": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "task"}}},
+	}}
+	spec := types.NewWorkflowInvocationSpec("wf", fakeClock.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	config.Clock = fakeClock
+	c = NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), config)
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	eval = func() *types.WorkflowInvocation {
+		c.Eval(context.Background(), &ctrl.Event{Updated: project()})
+		for i := 0; i < 20 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return project()
+	}
+	return c, eval, func() { exec.Close() }
+}
+
+func TestFakeClock_Deadline(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	backend := &flakyBackend{Backend: mem.NewBackend()}
+	_, eval, closer := fakeClockController(t, fakeClock, backend, InvocationConfig{})
+	defer closer()
+
+	invocation := eval()
+	assert.Equal(t, types.WorkflowInvocationStatus_IN_PROGRESS, invocation.GetStatus().GetStatus())
+
+	// The task has finished, but the deadline passes before the invocation is completed, without waiting for it in
+	// real time.
+	fakeClock.Step(2 * time.Minute)
+	invocation = eval()
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, invocation.GetStatus().GetStatus())
+	assert.Contains(t, invocation.GetStatus().GetError().GetMessage(), "deadline exceeded")
+}
+
+func TestFakeClock_FinalizationRetry(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	backend := &flakyBackend{Backend: mem.NewBackend(), eventType: events.EventInvocationFailed, failures: 1}
+	_, eval, closer := fakeClockController(t, fakeClock, backend,
+		InvocationConfig{FinalizationRetry: FinalizationRetry{Backoff: maxFinalizationBackoff}})
+	defer closer()
+
+	eval()
+	fakeClock.Step(2 * time.Minute)
+	invocation := eval()
+	assert.Equal(t, types.WorkflowInvocationStatus_IN_PROGRESS, invocation.GetStatus().GetStatus())
+
+	// The retry of the failed finalization is delayed by the backoff, as measured by the clock of the executor.
+	fakeClock.Step(maxFinalizationBackoff)
+	for i := 0; i < 100 && !invocation.GetStatus().Finished(); i++ {
+		time.Sleep(10 * time.Millisecond)
+		invocation = eval()
+	}
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, invocation.GetStatus().GetStatus())
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricConcurrencyQueued = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "concurrency_queued_invocations",
+	Help:      "Number of invocations that are waiting for the concurrency key held by another invocation",
+})
+
+func init() {
+	prometheus.MustRegister(metricConcurrencyQueued)
+}
+
+// ConcurrencyLock describes the invocation that holds a concurrency key of a workflow, and the invocations that are
+// queued for it.
+type ConcurrencyLock struct {
+	WorkflowID string
+	Key        string
+	Holder     string
+	Queued     []string
+}
+
+// ConcurrencyLocks provides the mutual exclusion of the invocations of a workflow that have the same concurrency key.
+// The keys are scoped to the workflow; invocations of different workflows do not conflict.
+//
+// The locks are kept in memory. After a restart of the controller, the locks are re-acquired by the invocations as they
+// are evaluated again.
+type ConcurrencyLocks struct {
+	locks map[concurrencyKey]*ConcurrencyLock
+	mu    *sync.Mutex
+}
+
+type concurrencyKey struct {
+	workflowID string
+	key        string
+}
+
+func NewConcurrencyLocks() *ConcurrencyLocks {
+	return &ConcurrencyLocks{
+		locks: map[concurrencyKey]*ConcurrencyLock{},
+		mu:    &sync.Mutex{},
+	}
+}
+
+// Acquire acquires the concurrency key of the workflow for the invocation. If the key is held by another
+// invocation, it returns that invocation. In that case the invocation is queued if enqueue is true; queued
+// invocations acquire the key in the order in which they were queued.
+//
+// Acquiring a key that is already held by the invocation succeeds.
+func (l *ConcurrencyLocks) Acquire(workflowID, key, invocationID string, enqueue bool) (holder string, acquired bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id := concurrencyKey{workflowID: workflowID, key: key}
+	lock, ok := l.locks[id]
+	if !ok {
+		lock = &ConcurrencyLock{WorkflowID: workflowID, Key: key}
+		l.locks[id] = lock
+	}
+	if lock.Holder == invocationID {
+		return invocationID, true
+	}
+	if len(lock.Holder) == 0 && (len(lock.Queued) == 0 || lock.Queued[0] == invocationID) {
+		lock.Holder = invocationID
+		lock.Queued = removeString(lock.Queued, invocationID)
+		l.updateMetrics()
+		return invocationID, true
+	}
+	if enqueue && !containsString(lock.Queued, invocationID) {
+		lock.Queued = append(lock.Queued, invocationID)
+		l.updateMetrics()
+	}
+	return lock.Holder, false
+}
+
+// Release releases the concurrency keys held by the invocation, and removes the invocation from the queues. It
+// returns the invocations that are next in line for the released keys.
+func (l *ConcurrencyLocks) Release(invocationID string) (next []string) {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id, lock := range l.locks {
+		lock.Queued = removeString(lock.Queued, invocationID)
+		if lock.Holder == invocationID {
+			lock.Holder = ""
+			if len(lock.Queued) > 0 {
+				next = append(next, lock.Queued[0])
+			}
+		}
+		if len(lock.Holder) == 0 && len(lock.Queued) == 0 {
+			delete(l.locks, id)
+		}
+	}
+	l.updateMetrics()
+	return next
+}
+
+// Get returns the lock of the concurrency key of the workflow, if the key is held or queued for.
+func (l *ConcurrencyLocks) Get(workflowID, key string) (ConcurrencyLock, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock, ok := l.locks[concurrencyKey{workflowID: workflowID, key: key}]
+	if !ok {
+		return ConcurrencyLock{}, false
+	}
+	result := *lock
+	result.Queued = append([]string(nil), lock.Queued...)
+	return result, true
+}
+
+// List returns the locks of the concurrency keys that are held or queued for.
+func (l *ConcurrencyLocks) List() []ConcurrencyLock {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var result []ConcurrencyLock
+	for _, lock := range l.locks {
+		entry := *lock
+		entry.Queued = append([]string(nil), lock.Queued...)
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].WorkflowID != result[j].WorkflowID {
+			return result[i].WorkflowID < result[j].WorkflowID
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Restore replaces the locks, such as with the locks of a controller instance that handed off its invocations. This
+// preserves the order in which the invocations are queued for the keys.
+func (l *ConcurrencyLocks) Restore(locks []ConcurrencyLock) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locks = map[concurrencyKey]*ConcurrencyLock{}
+	for _, lock := range locks {
+		entry := lock
+		entry.Queued = append([]string(nil), lock.Queued...)
+		l.locks[concurrencyKey{workflowID: lock.WorkflowID, key: lock.Key}] = &entry
+	}
+	l.updateMetrics()
+}
+
+func (l *ConcurrencyLocks) updateMetrics() {
+	var queued int
+	for _, lock := range l.locks {
+		queued += len(lock.Queued)
+	}
+	metricConcurrencyQueued.Set(float64(queued))
+}
+
+func containsString(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(vals []string, val string) []string {
+	result := vals[:0]
+	for _, v := range vals {
+		if v != val {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLocks(t *testing.T) {
+	locks := NewConcurrencyLocks()
+	holder, acquired := locks.Acquire("deploy", "service-a", "wi-1", true)
+	assert.True(t, acquired)
+	assert.Equal(t, "wi-1", holder)
+
+	// Acquiring a held key is idempotent.
+	_, acquired = locks.Acquire("deploy", "service-a", "wi-1", true)
+	assert.True(t, acquired)
+
+	// Keys are scoped to the workflow.
+	_, acquired = locks.Acquire("other", "service-a", "wi-4", true)
+	assert.True(t, acquired)
+
+	holder, acquired = locks.Acquire("deploy", "service-a", "wi-2", true)
+	assert.False(t, acquired)
+	assert.Equal(t, "wi-1", holder)
+	_, acquired = locks.Acquire("deploy", "service-a", "wi-3", true)
+	assert.False(t, acquired)
+	_, acquired = locks.Acquire("deploy", "service-a", "wi-5", false)
+	assert.False(t, acquired)
+
+	lock, ok := locks.Get("deploy", "service-a")
+	assert.True(t, ok)
+	assert.Equal(t, "wi-1", lock.Holder)
+	assert.Equal(t, []string{"wi-2", "wi-3"}, lock.Queued)
+
+	// The queued invocations acquire the key in order.
+	assert.Equal(t, []string{"wi-2"}, locks.Release("wi-1"))
+	_, acquired = locks.Acquire("deploy", "service-a", "wi-3", true)
+	assert.False(t, acquired)
+	_, acquired = locks.Acquire("deploy", "service-a", "wi-2", true)
+	assert.True(t, acquired)
+
+	// Invocations that finish while queued are removed from the queue.
+	assert.Empty(t, locks.Release("wi-3"))
+	assert.Empty(t, locks.Release("wi-2"))
+	_, ok = locks.Get("deploy", "service-a")
+	assert.False(t, ok)
+}
@@ -4,14 +4,37 @@ import (
 	"context"
 	"io"
 	"runtime/debug"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/util/workqueue"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
 )
 
+var metricQueueWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "eval_queue_wait_seconds",
+	Help:      "Time that evaluations waited in the evaluation queue before being evaluated",
+	Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30},
+})
+
+var metricDebouncedEvals = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "debounced_evals_total",
+	Help: "Number of evaluations that were submitted within the debounce window of the controller, by whether they " +
+		"were deferred to the end of the window or merged with an evaluation deferred before",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(metricQueueWait, metricDebouncedEvals)
+}
+
 // Future: decouple from fes.
 type Event = fes.Notification
 
@@ -62,37 +85,60 @@ func (r Success) Apply(s *System, event *Event) {
 	}
 }
 
-// Done removes the controller for this evaluation, which prevents any further evaluations
+// Done finishes the controller for this evaluation, which prevents any further evaluations
 type Done struct {
 	Msg string
 }
 
 func (r Done) Apply(s *System, event *Event) {
 	if len(r.Msg) == 0 {
-		s.LoggerFor(event.Aggregate.Id).Debug("Removing finished controller")
+		s.LoggerFor(event.Aggregate.Id).Debug("Finishing controller")
 	} else {
-		s.LoggerFor(event.Aggregate.Id).Debugf("Removing finished controller: %v", r.Msg)
+		s.LoggerFor(event.Aggregate.Id).Debugf("Finishing controller: %v", r.Msg)
 	}
-	s.DeleteController(event.Aggregate.Id)
+	s.FinishController(event.Aggregate.Id)
 }
 
 type ControllerStats struct {
 	LastEvaluatedAt time.Time
 	EvalCount       int64
+
+	// LastQueueWait is the time that the last evaluation waited in the evaluation queue.
+	LastQueueWait time.Duration
+
+	// TotalQueueWait is the time that all evaluations of the controller waited in the evaluation queue.
+	TotalQueueWait time.Duration
 }
 
-func (c ControllerStats) RecordEval() ControllerStats {
-	c.LastEvaluatedAt = time.Now()
+// RecordEval records an evaluation of the controller at the time now.
+func (c ControllerStats) RecordEval(now time.Time) ControllerStats {
+	c.LastEvaluatedAt = now
 	c.EvalCount++
 	return c
 }
 
+// RecordQueueWait records the time that an evaluation waited in the evaluation queue.
+func (c ControllerStats) RecordQueueWait(wait time.Duration) ControllerStats {
+	c.LastQueueWait = wait
+	c.TotalQueueWait += wait
+	return c
+}
+
 // Future: support parallel executions in evaluator
 type System struct {
 	ctrls       map[string]Controller
 	ctrlsMu     *sync.RWMutex
+	finished    map[string]time.Time // guarded by ctrlsMu
+	retention   time.Duration
 	ctrlStats   map[string]ControllerStats
 	ctrlStatsMu *sync.RWMutex
+	enqueuedAt  map[*Event]time.Time
+	enqueuedMu  *sync.Mutex
+	debounce    time.Duration
+	submittedAt map[string]time.Time // guarded by debounceMu
+	deferred    map[string]*Event    // guarded by debounceMu
+	debounceMu  *sync.Mutex
+	clock       clock.Clock
 	factory     ControllerFactory
 	evalQueue   workqueue.Interface
 	close       func()
@@ -101,22 +147,103 @@ type System struct {
 }
 
 func NewSystem(factory ControllerFactory) *System {
+	return NewSystemWithQueue(factory, workqueue.NewWorkQueue(workqueue.DefaultMaxSize, true))
+}
+
+// NewSystemWithQueue creates a system that uses the provided queue to order the evaluations, such as a
+// workqueue.FairQueue.
+func NewSystemWithQueue(factory ControllerFactory, evalQueue workqueue.Interface) *System {
 	return &System{
 		factory:     factory,
 		ctrlsMu:     &sync.RWMutex{},
 		ctrls:       make(map[string]Controller),
-		evalQueue:   workqueue.NewWorkQueue(workqueue.DefaultMaxSize, true),
+		finished:    make(map[string]time.Time),
+		evalQueue:   evalQueue,
 		runOnce:     &sync.Once{},
 		logger:      log.StandardLogger(),
 		ctrlStats:   make(map[string]ControllerStats),
 		ctrlStatsMu: &sync.RWMutex{},
+		enqueuedAt:  make(map[*Event]time.Time),
+		enqueuedMu:  &sync.Mutex{},
+		submittedAt: make(map[string]time.Time),
+		deferred:    make(map[string]*Event),
+		debounceMu:  &sync.Mutex{},
+		clock:       clock.RealClock{},
 	}
 }
 
+// SetClock sets the clock from which the system reads the time, such as the time of the evaluations of the
+// controllers, and the times that the retention period and the debounce window are measured from. It should be set
+// before the system is run. By default, the real clock is used.
+func (s *System) SetClock(clock clock.Clock) {
+	s.clock = clock
+}
+
+// SetRetention sets the grace period during which finished controllers are retained. During this period, the events
+// of a finished controller are ignored rather than creating a new controller for them. If 0, finished controllers are
+// deleted immediately.
+func (s *System) SetRetention(retention time.Duration) {
+	s.retention = retention
+}
+
+// SetDebounce sets the debounce window of the evaluations of a controller. An evaluation that is submitted within the
+// window after the previous evaluation of the controller was submitted is deferred to the end of the window, and
+// merged with the other evaluations submitted in the meantime, of which only the last one is evaluated. If 0, the
+// evaluations are submitted immediately.
+func (s *System) SetDebounce(window time.Duration) {
+	s.debounce = window
+}
+
 func (s *System) DeleteController(key string) {
 	s.ctrlsMu.Lock()
 	delete(s.ctrls, key)
+	delete(s.finished, key)
 	s.ctrlsMu.Unlock()
+	s.debounceMu.Lock()
+	delete(s.submittedAt, key)
+	s.debounceMu.Unlock()
+}
+
+// FinishController marks the controller as finished. The controller is retained for the retention period of the
+// system, after which it is deleted.
+func (s *System) FinishController(key string) {
+	if s.retention <= 0 {
+		s.DeleteController(key)
+		return
+	}
+	s.ctrlsMu.Lock()
+	if _, ok := s.ctrls[key]; ok {
+		s.finished[key] = s.clock.Now()
+	}
+	s.ctrlsMu.Unlock()
+}
+
+// IsFinished returns whether the controller has finished, but is still retained.
+func (s *System) IsFinished(key string) bool {
+	s.ctrlsMu.RLock()
+	_, ok := s.finished[key]
+	s.ctrlsMu.RUnlock()
+	return ok
+}
+
+// evictFinished deletes the finished controllers of which the retention period has expired.
+func (s *System) evictFinished() {
+	expiry := s.clock.Now().Add(-s.retention)
+	var evicted []string
+	s.ctrlsMu.Lock()
+	for key, finishedAt := range s.finished {
+		if finishedAt.Before(expiry) {
+			delete(s.ctrls, key)
+			delete(s.finished, key)
+			evicted = append(evicted, key)
+		}
+	}
+	s.ctrlsMu.Unlock()
+	s.debounceMu.Lock()
+	for _, key := range evicted {
+		delete(s.submittedAt, key)
+	}
+	s.debounceMu.Unlock()
 }
 
 func (s *System) AddController(key string, ctrl Controller) {
@@ -132,6 +259,38 @@ func (s *System) GetController(key string) (ctrl Controller, ok bool) {
 	return ctrl, ok
 }
 
+// GetControllerStats returns the evaluation statistics of the controller, if it has been evaluated before.
+func (s *System) GetControllerStats(key string) (stats ControllerStats, ok bool) {
+	s.ctrlStatsMu.RLock()
+	stats, ok = s.ctrlStats[key]
+	s.ctrlStatsMu.RUnlock()
+	return stats, ok
+}
+
+// RestoreControllerStats sets the evaluation statistics of the controller, such as the statistics of a controller that
+// was handed off by another system.
+func (s *System) RestoreControllerStats(key string, stats ControllerStats) {
+	s.ctrlStatsMu.Lock()
+	s.ctrlStats[key] = stats
+	s.ctrlStatsMu.Unlock()
+}
+
+// RangeControllers calls the consumer for each controller, including the finished controllers that are retained,
+// until the consumer returns false.
+func (s *System) RangeControllers(consumer func(k string, v Controller) bool) {
+	s.ctrlsMu.RLock()
+	ctrls := make(map[string]Controller, len(s.ctrls))
+	for k, v := range s.ctrls {
+		ctrls[k] = v
+	}
+	s.ctrlsMu.RUnlock()
+	for k, v := range ctrls {
+		if !consumer(k, v) {
+			break
+		}
+	}
+}
+
 func (s *System) RangeControllerStats(consumer func(k string, v ControllerStats) bool) {
 	s.ctrlStatsMu.RLock()
 	defer s.ctrlStatsMu.RUnlock()
@@ -150,19 +309,137 @@ func (s *System) LoggerFor(entityID string) *log.Entry {
 	return s.logger.WithField("key", entityID)
 }
 
+// Submit adds the event to the evaluation queue.
+//
+// The time at which the event was enqueued is recorded to measure the time it waits in the queue. If the event is
+// submitted again while it is still queued, the wait is measured from the first submission; if it is submitted again
+// while it is being evaluated, it is queued again and measured from the new submission.
+//
+// If the system has a debounce window, the evaluation may be deferred to the end of the window (see SetDebounce).
 func (s *System) Submit(event *Event) bool {
-	return s.evalQueue.Add(event)
+	if s.debounce > 0 && s.debounced(event) {
+		return true
+	}
+	return s.submit(event)
+}
+
+func (s *System) submit(event *Event) bool {
+	s.enqueuedMu.Lock()
+	defer s.enqueuedMu.Unlock()
+	if !s.evalQueue.Add(event) {
+		return false
+	}
+	if _, ok := s.enqueuedAt[event]; !ok {
+		s.enqueuedAt[event] = s.clock.Now()
+	}
+	return true
+}
+
+// debounced defers the evaluation to the end of the debounce window if an evaluation of the controller was submitted
+// within the window, merging it with the evaluation that is deferred already, if any. It returns false if the
+// evaluation should be submitted immediately.
+func (s *System) debounced(event *Event) bool {
+	key := event.Aggregate.Id
+	now := s.clock.Now()
+	s.debounceMu.Lock()
+	defer s.debounceMu.Unlock()
+	if _, ok := s.deferred[key]; ok {
+		s.deferred[key] = event
+		metricDebouncedEvals.WithLabelValues("merged").Inc()
+		return true
+	}
+	submittedAt, ok := s.submittedAt[key]
+	if !ok || now.Sub(submittedAt) >= s.debounce {
+		s.submittedAt[key] = now
+		return false
+	}
+	s.deferred[key] = event
+	metricDebouncedEvals.WithLabelValues("deferred").Inc()
+	timer := s.clock.NewTimer(submittedAt.Add(s.debounce).Sub(now))
+	Go("system", func() {
+		<-timer.C()
+		s.submitDeferred(key)
+	})
+	return true
+}
+
+// submitDeferred submits the deferred evaluation of the controller at the end of the debounce window.
+func (s *System) submitDeferred(key string) {
+	s.debounceMu.Lock()
+	event, ok := s.deferred[key]
+	delete(s.deferred, key)
+	if ok {
+		s.submittedAt[key] = s.clock.Now()
+	}
+	s.debounceMu.Unlock()
+	if ok && !s.submit(event) && !s.evalQueue.ShuttingDown() {
+		s.LoggerFor(key).Warn("Dropped deferred evaluation: the evaluation queue is full")
+	}
+}
+
+// Queued returns the keys of the controllers of which an evaluation is queued, in the order in which the evaluations
+// were submitted, followed by the controllers of which an evaluation is deferred by the debounce window.
+func (s *System) Queued() []string {
+	s.enqueuedMu.Lock()
+	events := make([]*Event, 0, len(s.enqueuedAt))
+	for event := range s.enqueuedAt {
+		events = append(events, event)
+	}
+	enqueuedAt := s.enqueuedAt
+	sort.Slice(events, func(i, j int) bool {
+		return enqueuedAt[events[i]].Before(enqueuedAt[events[j]])
+	})
+	s.enqueuedMu.Unlock()
+
+	var keys []string
+	seen := map[string]bool{}
+	for _, event := range events {
+		if key := event.Aggregate.Id; !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	s.debounceMu.Lock()
+	var deferred []string
+	for key := range s.deferred {
+		if !seen[key] {
+			deferred = append(deferred, key)
+		}
+	}
+	s.debounceMu.Unlock()
+	sort.Strings(deferred)
+	return append(keys, deferred...)
+}
+
+// dequeued records the time that the event waited in the evaluation queue.
+func (s *System) dequeued(ctrlKey string, event *Event) {
+	s.enqueuedMu.Lock()
+	enqueuedAt, ok := s.enqueuedAt[event]
+	delete(s.enqueuedAt, event)
+	s.enqueuedMu.Unlock()
+	if !ok {
+		return
+	}
+	wait := s.clock.Since(enqueuedAt)
+	metricQueueWait.Observe(wait.Seconds())
+	s.ctrlStatsMu.Lock()
+	s.ctrlStats[ctrlKey] = s.ctrlStats[ctrlKey].RecordQueueWait(wait)
+	s.ctrlStatsMu.Unlock()
 }
 
 func (s *System) Run() {
 	s.runOnce.Do(func() {
-		go s.run()
+		Go("system", s.run)
 	})
 }
 
 func (s *System) run() {
 	ctx, cancel := context.WithCancel(context.Background())
 	s.close = cancel
+	if s.retention > 0 {
+		Go("system", func() { s.runEviction(ctx) })
+	}
 	for {
 		item, shutdown := s.evalQueue.Get()
 		if shutdown {
@@ -176,8 +453,21 @@ func (s *System) run() {
 			continue
 		}
 		ctrlKey := event.Aggregate.Id
+		s.dequeued(ctrlKey, event)
 		s.LoggerFor(ctrlKey).Debugf("starting evaluation (reason: %v)", event.Event.GetType())
 
+		// Ignore late events of finished controllers, rather than treating them as new ones, unless the event
+		// reopens the aggregate of the controller.
+		if s.IsFinished(ctrlKey) && event.Event.GetHints().GetReopened() {
+			s.LoggerFor(ctrlKey).Debugf("reopening finished controller (reason: %v)", event.Event.GetType())
+			s.DeleteController(ctrlKey)
+		} else if s.IsFinished(ctrlKey) {
+			s.LoggerFor(ctrlKey).Debugf("ignoring event of finished controller (reason: %v)",
+				event.Event.GetType())
+			s.evalQueue.Done(item)
+			continue
+		}
+
 		// Get or create controller for item
 		ctrl, ok := s.GetController(ctrlKey)
 		if !ok {
@@ -197,6 +487,21 @@ func (s *System) run() {
 	}
 }
 
+// runEviction periodically evicts the finished controllers. The evictions are timed with the clock of the system, like
+// the retention itself.
+func (s *System) runEviction(ctx context.Context) {
+	for {
+		timer := s.clock.NewTimer(s.retention / 2)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+			s.evictFinished()
+		}
+	}
+}
+
 func (s *System) eval(ctx context.Context, ctrlKey string, ctrl Controller, event *Event) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -209,7 +514,7 @@ func (s *System) eval(ctx context.Context, ctrlKey string, ctrl Controller, even
 
 	// Record the evaluation
 	s.ctrlStatsMu.Lock()
-	s.ctrlStats[ctrlKey] = s.ctrlStats[ctrlKey].RecordEval()
+	s.ctrlStats[ctrlKey] = s.ctrlStats[ctrlKey].RecordEval(s.clock.Now())
 	s.ctrlStatsMu.Unlock()
 
 	// Trigger the evaluation
@@ -249,7 +554,7 @@ func (s *PollSensor) Close() error {
 }
 
 func (s *PollSensor) Start(evalQueue EvalQueue) error {
-	go s.Run(evalQueue)
+	Go("sensors", func() { s.Run(evalQueue) })
 	return nil
 }
 
@@ -0,0 +1,251 @@
+package ctrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/util/workqueue"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+type funcController func(ctx context.Context, event *Event) Result
+
+func (f funcController) Eval(ctx context.Context, event *Event) Result {
+	return f(ctx, event)
+}
+
+func newEvent(id string) *Event {
+	return &Event{
+		Aggregate: fes.Aggregate{Type: "test", Id: id},
+		Event:     &fes.Event{Type: "test"},
+	}
+}
+
+func TestSystem_QueueWait(t *testing.T) {
+	evaluated := make(chan *Event, 10)
+	system := NewSystemWithQueue(func(event *Event) (Controller, error) {
+		return funcController(func(ctx context.Context, event *Event) Result {
+			evaluated <- event
+			return Success{}
+		}), nil
+	}, workqueue.NewWorkQueue(10, true))
+	defer system.Close()
+
+	// Queue the events before the system runs to ensure that they wait in the queue.
+	first := newEvent("foo")
+	second := newEvent("foo")
+	assert.True(t, system.Submit(first))
+	assert.True(t, system.Submit(second))
+	assert.True(t, system.Submit(first))
+	time.Sleep(10 * time.Millisecond)
+	system.Run()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-evaluated:
+		case <-time.After(time.Second):
+			t.Fatal("event was not evaluated")
+		}
+	}
+
+	// The resubmission of the first event should have been merged with the queued event.
+	stats, ok := system.GetControllerStats("foo")
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, stats.EvalCount)
+	assert.True(t, stats.LastQueueWait >= 10*time.Millisecond)
+	assert.True(t, stats.TotalQueueWait >= 20*time.Millisecond)
+	system.enqueuedMu.Lock()
+	assert.Empty(t, system.enqueuedAt)
+	system.enqueuedMu.Unlock()
+}
+
+func TestSystem_FinishedRetention(t *testing.T) {
+	evaluated := make(chan *Event, 10)
+	var created int
+	system := NewSystem(func(event *Event) (Controller, error) {
+		created++
+		return funcController(func(ctx context.Context, event *Event) Result {
+			evaluated <- event
+			return Done{}
+		}), nil
+	})
+	system.SetRetention(200 * time.Millisecond)
+	system.Run()
+	defer system.Close()
+
+	assert.True(t, system.Submit(newEvent("foo")))
+	select {
+	case <-evaluated:
+	case <-time.After(time.Second):
+		t.Fatal("event was not evaluated")
+	}
+
+	// A late event of the finished controller should be ignored.
+	assert.True(t, system.Submit(newEvent("foo")))
+	select {
+	case <-evaluated:
+		t.Fatal("event of finished controller was evaluated")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.True(t, system.IsFinished("foo"))
+	_, ok := system.GetController("foo")
+	assert.True(t, ok)
+
+	// Once evicted, a new event creates a new controller.
+	for deadline := time.Now().Add(time.Second); system.IsFinished("foo"); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("finished controller was not evicted")
+		}
+	}
+	assert.True(t, system.Submit(newEvent("foo")))
+	select {
+	case <-evaluated:
+	case <-time.After(time.Second):
+		t.Fatal("event was not evaluated")
+	}
+	assert.Equal(t, 2, created)
+}
+
+func TestSystem_ReopenFinished(t *testing.T) {
+	evaluated := make(chan *Event, 10)
+	var created int
+	system := NewSystem(func(event *Event) (Controller, error) {
+		created++
+		return funcController(func(ctx context.Context, event *Event) Result {
+			evaluated <- event
+			return Done{}
+		}), nil
+	})
+	system.SetRetention(time.Minute)
+	system.Run()
+	defer system.Close()
+
+	assert.True(t, system.Submit(newEvent("foo")))
+	select {
+	case <-evaluated:
+	case <-time.After(time.Second):
+		t.Fatal("event was not evaluated")
+	}
+	assert.True(t, system.IsFinished("foo"))
+
+	// An event that reopens the aggregate replaces the finished controller.
+	reopen := newEvent("foo")
+	reopen.Event.Hints = &fes.EventHints{Reopened: true}
+	assert.True(t, system.Submit(reopen))
+	select {
+	case <-evaluated:
+	case <-time.After(time.Second):
+		t.Fatal("reopening event was not evaluated")
+	}
+	assert.Equal(t, 2, created)
+}
+
+func TestSystem_Clock(t *testing.T) {
+	evaluated := make(chan *Event, 10)
+	system := NewSystem(func(event *Event) (Controller, error) {
+		return funcController(func(ctx context.Context, event *Event) Result {
+			evaluated <- event
+			return Done{}
+		}), nil
+	})
+	fakeClock := clock.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	system.SetClock(fakeClock)
+	system.SetRetention(time.Minute)
+	system.Run()
+	defer system.Close()
+
+	assert.True(t, system.Submit(newEvent("foo")))
+	select {
+	case <-evaluated:
+	case <-time.After(time.Second):
+		t.Fatal("event was not evaluated")
+	}
+	stats, ok := system.GetControllerStats("foo")
+	assert.True(t, ok)
+	assert.Equal(t, fakeClock.Now(), stats.LastEvaluatedAt)
+	for i := 0; i < 100 && !system.IsFinished("foo"); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(t, system.IsFinished("foo"))
+
+	// The retention is measured with the clock of the system, as are the evictions.
+	system.evictFinished()
+	assert.True(t, system.IsFinished("foo"))
+	// Wait for the eviction to be scheduled on the fake clock, before advancing it.
+	for i := 0; i < 100 && !fakeClock.HasWaiters(); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	fakeClock.Step(2 * time.Minute)
+	for i := 0; i < 100 && system.IsFinished("foo"); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.False(t, system.IsFinished("foo"))
+}
+
+func TestSystem_Debounce(t *testing.T) {
+	evaluated := make(chan *Event, 10)
+	system := NewSystemWithQueue(func(event *Event) (Controller, error) {
+		return funcController(func(ctx context.Context, event *Event) Result {
+			evaluated <- event
+			return Success{}
+		}), nil
+	}, workqueue.NewWorkQueue(10, true))
+	system.SetDebounce(50 * time.Millisecond)
+	system.Run()
+	defer system.Close()
+
+	// The first evaluation is submitted immediately.
+	first := newEvent("foo")
+	assert.True(t, system.Submit(first))
+	select {
+	case event := <-evaluated:
+		assert.Equal(t, first, event)
+	case <-time.After(time.Second):
+		t.Fatal("event was not evaluated")
+	}
+
+	// The evaluations within the window are merged into a single evaluation of the last one at the end of the window.
+	assert.True(t, system.Submit(newEvent("foo")))
+	last := newEvent("foo")
+	assert.True(t, system.Submit(last))
+	assert.True(t, system.Submit(newEvent("bar")))
+	assert.Contains(t, system.Queued(), "foo")
+	var events []*Event
+	timeout := time.After(time.Second)
+	for len(events) < 2 {
+		select {
+		case event := <-evaluated:
+			events = append(events, event)
+		case <-timeout:
+			t.Fatal("events were not evaluated")
+		}
+	}
+	assert.Equal(t, "bar", events[0].Aggregate.Id)
+	assert.Equal(t, last, events[1])
+	select {
+	case event := <-evaluated:
+		t.Fatalf("unexpected evaluation: %v", event.Aggregate.Id)
+	case <-time.After(100 * time.Millisecond):
+	}
+	stats, _ := system.GetControllerStats("foo")
+	assert.EqualValues(t, 2, stats.EvalCount)
+}
+
+func TestGo(t *testing.T) {
+	release := make(chan struct{})
+	done := make(chan struct{})
+	Go("test", func() {
+		<-release
+		close(done)
+	})
+	assert.Equal(t, 1, Goroutines("test"))
+	close(release)
+	<-done
+	for i := 0; i < 100 && Goroutines("test") > 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, 0, Goroutines("test"))
+}
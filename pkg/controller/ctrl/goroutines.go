@@ -0,0 +1,49 @@
+package ctrl
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricGoroutines = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "goroutines",
+	Help:      "Number of goroutines owned by the controller, by the component that started them",
+}, []string{"component"})
+
+func init() {
+	prometheus.MustRegister(metricGoroutines)
+}
+
+var (
+	goroutines   = map[string]int{}
+	goroutinesMu = &sync.Mutex{}
+)
+
+// Go runs fn in a new goroutine that is accounted to the component, which makes the goroutines of the controller
+// observable with the workflows_controller_goroutines metric. Long-running goroutines of the controller, and the
+// goroutines that are started per evaluation or per task, should be started with Go rather than with a plain go
+// statement.
+func Go(component string, fn func()) {
+	trackGoroutine(component, 1)
+	go func() {
+		defer trackGoroutine(component, -1)
+		fn()
+	}()
+}
+
+// Goroutines returns the number of running goroutines that were started by the component with Go.
+func Goroutines(component string) int {
+	goroutinesMu.Lock()
+	defer goroutinesMu.Unlock()
+	return goroutines[component]
+}
+
+func trackGoroutine(component string, delta int) {
+	goroutinesMu.Lock()
+	defer goroutinesMu.Unlock()
+	goroutines[component] += delta
+	metricGoroutines.WithLabelValues(component).Set(float64(goroutines[component]))
+}
@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Dashboard is a Grafana dashboard, limited to the properties that are used by the generated dashboards.
+type Dashboard struct {
+	UID           string           `json:"uid"`
+	Title         string           `json:"title"`
+	Tags          []string         `json:"tags"`
+	Editable      bool             `json:"editable"`
+	SchemaVersion int              `json:"schemaVersion"`
+	Refresh       string           `json:"refresh"`
+	Time          DashboardTime    `json:"time"`
+	Templating    DashboardVars    `json:"templating"`
+	Panels        []DashboardPanel `json:"panels"`
+}
+
+// DashboardTime is the default time range of a dashboard.
+type DashboardTime struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DashboardVars contains the template variables of a dashboard.
+type DashboardVars struct {
+	List []DashboardVar `json:"list"`
+}
+
+type DashboardVar struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+// DashboardPanel is a panel of a dashboard, which plots the results of its queries (targets).
+type DashboardPanel struct {
+	ID          int               `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Type        string            `json:"type"`
+	Datasource  string            `json:"datasource"`
+	GridPos     DashboardGridPos  `json:"gridPos"`
+	FieldConfig DashboardFields   `json:"fieldConfig"`
+	Targets     []DashboardTarget `json:"targets"`
+}
+
+type DashboardGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type DashboardFields struct {
+	Defaults DashboardFieldDefaults `json:"defaults"`
+}
+
+type DashboardFieldDefaults struct {
+	Unit string `json:"unit"`
+}
+
+// DashboardTarget is a Prometheus query of a panel.
+type DashboardTarget struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+}
+
+// WorkflowDashboard generates a Grafana dashboard of the workflow, with panels for the rate, the final status and the
+// duration of its invocations, and the duration of its tasks. The queries reference the per-workflow metrics of the
+// controller, which are aggregated over the replicas of the controller. The data source is a variable of the dashboard.
+//
+// The metrics of a workflow are only available if the workflow has its own series (see WorkflowMetrics).
+func WorkflowDashboard(workflowID string) *Dashboard {
+	finished := prometheus.BuildFQName("workflows", "controller", workflowInvocationsFinishedName)
+	duration := prometheus.BuildFQName("workflows", "controller", workflowInvocationDurationName)
+	taskDuration := prometheus.BuildFQName("workflows", "controller", workflowTaskDurationName)
+	selector := fmt.Sprintf("{workflow=%s}", strconv.Quote(workflowID))
+
+	panels := []DashboardPanel{
+		{
+			Title:       "Invocation rate",
+			Description: "Finished invocations per second",
+			FieldConfig: DashboardFields{Defaults: DashboardFieldDefaults{Unit: "reqps"}},
+			Targets: []DashboardTarget{{
+				Expr:         fmt.Sprintf("sum(rate(%s%s[5m]))", finished, selector),
+				LegendFormat: "invocations",
+			}},
+		},
+		{
+			Title:       "Invocations by status",
+			Description: "Finished invocations per second, by their final status",
+			FieldConfig: DashboardFields{Defaults: DashboardFieldDefaults{Unit: "reqps"}},
+			Targets: []DashboardTarget{{
+				Expr:         fmt.Sprintf("sum by (status) (rate(%s%s[5m]))", finished, selector),
+				LegendFormat: "{{status}}",
+			}},
+		},
+		{
+			Title:       "Invocation duration",
+			Description: "Duration of the finished invocations, from creation until completion",
+			FieldConfig: DashboardFields{Defaults: DashboardFieldDefaults{Unit: "s"}},
+		},
+		{
+			Title:       "Task duration (p95)",
+			Description: "Duration of the task runs of the workflow, by task",
+			FieldConfig: DashboardFields{Defaults: DashboardFieldDefaults{Unit: "s"}},
+			Targets: []DashboardTarget{{
+				Expr: fmt.Sprintf("max by (task) (%s{workflow=%s, quantile=\"0.95\"})", taskDuration,
+					strconv.Quote(workflowID)),
+				LegendFormat: "{{task}}",
+			}},
+		},
+	}
+	for _, quantile := range []struct{ value, legend string }{{"0.5", "p50"}, {"0.95", "p95"}, {"0.99", "p99"}} {
+		panels[2].Targets = append(panels[2].Targets, DashboardTarget{
+			Expr: fmt.Sprintf("max(%s{workflow=%s, quantile=\"%s\"})", duration, strconv.Quote(workflowID),
+				quantile.value),
+			LegendFormat: quantile.legend,
+		})
+	}
+
+	// Lay out the panels in a grid of two columns.
+	for i := range panels {
+		panels[i].ID = i + 1
+		panels[i].Type = "timeseries"
+		panels[i].Datasource = "$datasource"
+		panels[i].GridPos = DashboardGridPos{H: 8, W: 12, X: (i % 2) * 12, Y: (i / 2) * 8}
+		for j := range panels[i].Targets {
+			panels[i].Targets[j].RefID = string(rune('A' + j))
+		}
+	}
+
+	return &Dashboard{
+		UID:           dashboardUID(workflowID),
+		Title:         "Workflow " + workflowID,
+		Tags:          []string{"fission-workflows"},
+		Editable:      true,
+		SchemaVersion: 27,
+		Refresh:       "30s",
+		Time:          DashboardTime{From: "now-1h", To: "now"},
+		Templating: DashboardVars{List: []DashboardVar{{
+			Name:  "datasource",
+			Label: "Data source",
+			Type:  "datasource",
+			Query: "prometheus",
+		}}},
+		Panels: panels,
+	}
+}
+
+// dashboardUID returns a stable UID of the dashboard of the workflow, so that importing the dashboard again replaces
+// the previous version. Grafana limits UIDs to 40 characters; longer workflow IDs are hashed.
+func dashboardUID(workflowID string) string {
+	uid := "wf-" + workflowID
+	if len(uid) > 40 {
+		uid = fmt.Sprintf("wf-%x", sha256.Sum256([]byte(workflowID)))[:40]
+	}
+	return uid
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWorkflowDashboard ensures that the queries of the generated dashboard reference the metrics and labels that are
+// exported for the workflow.
+func TestWorkflowDashboard(t *testing.T) {
+	metrics := NewWorkflowMetrics(WorkflowMetricsConfig{Workflows: []string{"dashboard"}})
+	invocation := finishedInvocation("dashboard")
+	metrics.ObserveFinished(invocation)
+	metrics.ObserveTask(invocation, "fetch", time.Second)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+	exported := map[string]map[string]bool{} // metric -> label names
+	for _, family := range families {
+		labels := map[string]bool{}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = true
+			}
+			if family.GetMetric()[0].GetSummary() != nil {
+				labels["quantile"] = true
+			}
+		}
+		exported[family.GetName()] = labels
+	}
+
+	dashboard := WorkflowDashboard("dashboard")
+	_, err = json.Marshal(dashboard)
+	assert.NoError(t, err)
+	assert.Len(t, dashboard.Panels, 4)
+
+	metricName := regexp.MustCompile(`workflows_\w+`)
+	labelName := regexp.MustCompile(`(\w+)=|by \((\w+)\)`)
+	for _, panel := range dashboard.Panels {
+		assert.NotEmpty(t, panel.Targets, panel.Title)
+		for _, target := range panel.Targets {
+			name := metricName.FindString(target.Expr)
+			labels, ok := exported[name]
+			if !assert.True(t, ok, "unknown metric in %s", target.Expr) {
+				continue
+			}
+			assert.Contains(t, target.Expr, `workflow="dashboard"`)
+			for _, match := range labelName.FindAllStringSubmatch(target.Expr, -1) {
+				label := match[1] + match[2]
+				assert.True(t, labels[label], "unknown label %s in %s", label, target.Expr)
+			}
+		}
+	}
+}
+
+func TestDashboardUID(t *testing.T) {
+	assert.Equal(t, "wf-checkout", dashboardUID("checkout"))
+	long := dashboardUID("wf-2c2f0f45-5d0a-4a0e-9b62-5c5b8f0e1d2a")
+	assert.Len(t, long, 40)
+	assert.NotEqual(t, long, dashboardUID("wf-2c2f0f45-5d0a-4a0e-9b62-5c5b8f0e1d2b"))
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricDeduplicatedTasks = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "deduplicated_tasks_total",
+	Help:      "Number of task submissions that were dropped, because an identical run of the task was in progress",
+})
+
+func init() {
+	prometheus.MustRegister(metricDeduplicatedTasks)
+}
+
+// deduplicateTask checks whether the run of the task with the inputs is a duplicate: an identical run that was
+// submitted by this controller is still running, such as when a sensor poll or a recovered task is submitted again
+// before the original run has finished. A duplicate is dropped without producing a result of its own; the result of
+// the task is recorded by the original run. If the run is not a duplicate, it is registered as running until
+// releaseTask is called.
+//
+// Runs are only identical if their inputs are identical, so runs that differ in their context (such as the iterations
+// of a loop) are not deduplicated. Runs that have already finished are not considered; the scheduler does not submit
+// tasks that have succeeded, and failed tasks can be retried.
+func (c *InvocationController) deduplicateTask(taskID string, inputsHash string) (duplicate bool) {
+	c.dedupMu.Lock()
+	defer c.dedupMu.Unlock()
+	if c.runningTasks[taskID] == inputsHash {
+		return true
+	}
+	c.runningTasks[taskID] = inputsHash
+	return false
+}
+
+// releaseTask marks the run of the task as no longer running.
+func (c *InvocationController) releaseTask(taskID string) {
+	c.dedupMu.Lock()
+	delete(c.runningTasks, taskID)
+	c.dedupMu.Unlock()
+}
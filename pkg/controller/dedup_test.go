@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicateTask(t *testing.T) {
+	c := &InvocationController{runningTasks: map[string]string{}, dedupMu: &sync.Mutex{}}
+	hash, err := typedvalues.HashInputs(map[string]*typedvalues.TypedValue{"iteration": typedvalues.MustWrap(1)})
+	assert.NoError(t, err)
+
+	assert.False(t, c.deduplicateTask("task", hash))
+
+	// A second submission with identical inputs is skipped while the first one is running...
+	assert.True(t, c.deduplicateTask("task", hash))
+
+	// ...but submissions with different inputs, such as another iteration of a loop, are not.
+	otherHash, err := typedvalues.HashInputs(map[string]*typedvalues.TypedValue{"iteration": typedvalues.MustWrap(2)})
+	assert.NoError(t, err)
+	assert.False(t, c.deduplicateTask("other", otherHash))
+	c.releaseTask("other")
+
+	// Once the run has finished, the task can be run again with identical inputs, such as when it is retried.
+	c.releaseTask("task")
+	assert.False(t, c.deduplicateTask("task", hash))
+}
+
+func TestExecTask_Duplicate(t *testing.T) {
+	// The function blocks until it is released, so that the first run is still in progress when the task is
+	// submitted again.
+	var runs int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["slow"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		atomic.AddInt32(&runs, 1)
+		close(started)
+		<-release
+		return typedvalues.MustWrap("ok"), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("slow", &types.TaskSpec{FunctionRef: "slow"})
+	wfSpec.OutputTask = "slow"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"slow": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "slow"}}},
+	}}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+	assert.NoError(t, err)
+	entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+	assert.NoError(t, err)
+	invocation := entity.(*types.WorkflowInvocation)
+
+	c := NewInvocationController(invocationID, executor.NewLocalExecutor(1, 10), invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	dropped := counterValue(t, metricDeduplicatedTasks)
+	done := make(chan error)
+	go func() {
+		done <- c.execTask(invocation, "slow")
+	}()
+	<-started
+
+	// The duplicate submission is dropped, without invoking the function again.
+	assert.NoError(t, c.execTask(invocation, "slow"))
+	assert.Equal(t, dropped+1, counterValue(t, metricDeduplicatedTasks))
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+
+	// Once the original run has finished, the task is no longer deduplicated.
+	assert.False(t, c.deduplicateTask("slow", "any"))
+}
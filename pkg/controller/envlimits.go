@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	metricEnvInFlightTasks = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "env_inflight_tasks",
+		Help:      "Number of task executions in flight per function environment with a concurrency limit",
+	}, []string{"env"})
+	metricEnvDeferredTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "env_deferred_tasks_total",
+		Help: "Number of task executions that were deferred because the concurrency limit of their environment " +
+			"was reached",
+	}, []string{"env"})
+)
+
+func init() {
+	prometheus.MustRegister(metricEnvInFlightTasks, metricEnvDeferredTasks)
+}
+
+// EnvironmentResolver resolves the environment in which a function runs, such as the Fission environment of a Fission
+// function. It returns an empty environment for functions that do not run in an environment.
+type EnvironmentResolver interface {
+	Environment(fn types.FnRef) (string, error)
+}
+
+// EnvironmentLimits bounds the number of concurrent task executions per function environment, to protect
+// environments that do not cope well with bursts of calls, while the other environments are only bounded by the
+// global in-flight task limit (see TaskAdmission).
+//
+// Like TaskAdmission, a slot is acquired when a task execution is submitted, and released once the result of the
+// execution has been ingested. Tasks of which the environment is at its limit are deferred by the invocation
+// controller until a slot is released; other tasks of the invocation are scheduled as usual.
+//
+// A nil EnvironmentLimits does not limit any environment.
+type EnvironmentLimits struct {
+	limits   map[string]int
+	inFlight map[string]int
+	resolver EnvironmentResolver
+	mu       *sync.Mutex
+}
+
+// NewEnvironmentLimits creates the concurrency limits of the environments, with the key being the name of the
+// environment. Limits of 0 or less are ignored.
+func NewEnvironmentLimits(limits map[string]int, resolver EnvironmentResolver) *EnvironmentLimits {
+	l := &EnvironmentLimits{
+		limits:   map[string]int{},
+		inFlight: map[string]int{},
+		resolver: resolver,
+		mu:       &sync.Mutex{},
+	}
+	for env, limit := range limits {
+		if limit > 0 {
+			l.limits[env] = limit
+			metricEnvInFlightTasks.WithLabelValues(env).Set(0)
+		}
+	}
+	return l
+}
+
+// TryAcquire attempts to acquire a slot in the environment of the function of the task, without blocking. It returns
+// the environment of which a slot was acquired, which is empty if the environment of the task is not limited, and
+// whether the task can be executed. Every acquired slot should be released with Release.
+//
+// If the environment of the task cannot be resolved, the task is not limited.
+func (l *EnvironmentLimits) TryAcquire(task *types.Task) (env string, ok bool) {
+	if l == nil || len(l.limits) == 0 || task.GetStatus().GetFnRef() == nil {
+		return "", true
+	}
+	env, err := l.resolver.Environment(*task.GetStatus().GetFnRef())
+	if err != nil {
+		logrus.Debugf("Failed to resolve the environment of task %s: %v", task.ID(), err)
+		return "", true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit, limited := l.limits[env]
+	if !limited {
+		return "", true
+	}
+	if l.inFlight[env] >= limit {
+		metricEnvDeferredTasks.WithLabelValues(env).Inc()
+		return env, false
+	}
+	l.inFlight[env]++
+	metricEnvInFlightTasks.WithLabelValues(env).Set(float64(l.inFlight[env]))
+	return env, true
+}
+
+// Release releases a slot of the environment. It is a no-op for an empty environment.
+func (l *EnvironmentLimits) Release(env string) {
+	if l == nil || len(env) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[env] > 0 {
+		l.inFlight[env]--
+	}
+	metricEnvInFlightTasks.WithLabelValues(env).Set(float64(l.inFlight[env]))
+}
+
+// InFlight returns the number of acquired slots of the environment.
+func (l *EnvironmentLimits) InFlight(env string) int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight[env]
+}
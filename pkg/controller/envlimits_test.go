@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEnvironmentResolver map[string]string
+
+func (r fakeEnvironmentResolver) Environment(fn types.FnRef) (string, error) {
+	env, ok := r[fn.ID]
+	if !ok {
+		return "", errors.New("unknown function")
+	}
+	return env, nil
+}
+
+func newEnvTask(fnID string) *types.Task {
+	task := types.NewTask("task-"+fnID, fnID)
+	task.Status.FnRef = &types.FnRef{Runtime: "fission", ID: fnID}
+	return task
+}
+
+func TestEnvironmentLimits(t *testing.T) {
+	limits := NewEnvironmentLimits(map[string]int{"python": 2, "ignored": 0}, fakeEnvironmentResolver{
+		"slow":   "python",
+		"slow2":  "python",
+		"robust": "go",
+	})
+
+	env, ok := limits.TryAcquire(newEnvTask("slow"))
+	assert.True(t, ok)
+	assert.Equal(t, "python", env)
+	_, ok = limits.TryAcquire(newEnvTask("slow2"))
+	assert.True(t, ok)
+	env, ok = limits.TryAcquire(newEnvTask("slow"))
+	assert.False(t, ok)
+	assert.Equal(t, "python", env)
+	assert.Equal(t, 2, limits.InFlight("python"))
+
+	// Unlimited environments, and functions of which the environment is unknown, are not limited.
+	for i := 0; i < 10; i++ {
+		env, ok = limits.TryAcquire(newEnvTask("robust"))
+		assert.True(t, ok)
+		assert.Empty(t, env)
+		env, ok = limits.TryAcquire(newEnvTask("unknown"))
+		assert.True(t, ok)
+		assert.Empty(t, env)
+	}
+	assert.Equal(t, 0, limits.InFlight("go"))
+
+	limits.Release("python")
+	limits.Release("")
+	_, ok = limits.TryAcquire(newEnvTask("slow2"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, limits.InFlight("python"))
+}
+
+func TestEnvironmentLimits_Nil(t *testing.T) {
+	var limits *EnvironmentLimits
+	env, ok := limits.TryAcquire(newEnvTask("slow"))
+	assert.True(t, ok)
+	assert.Empty(t, env)
+	limits.Release("python")
+	assert.Equal(t, 0, limits.InFlight("python"))
+}
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
 	"github.com/fission/fission-workflows/pkg/util/workqueue"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
 )
 
 type LocalExecutor struct {
@@ -23,6 +26,8 @@ type LocalExecutor struct {
 	workers  []*worker
 	groups   map[interface{}]int
 	groupsMu *sync.RWMutex
+	active   *int32
+	clock    clock.Clock
 }
 
 // Task is the unit of execution that the executor will execute.
@@ -42,6 +47,12 @@ func (t *Task) ID() interface{} {
 }
 
 func NewLocalExecutor(maxParallelism, maxQueueSize int) *LocalExecutor {
+	return NewLocalExecutorWithClock(maxParallelism, maxQueueSize, clock.RealClock{})
+}
+
+// NewLocalExecutorWithClock creates an executor of which the delays of the tasks submitted with SubmitAfter are
+// measured with the clock, which allows the delays to be skipped with a fake clock in tests.
+func NewLocalExecutorWithClock(maxParallelism, maxQueueSize int, clock clock.Clock) *LocalExecutor {
 	if maxParallelism <= 0 {
 		panic("LocalExecutor: parallelism should be larger than 0")
 	}
@@ -50,12 +61,19 @@ func NewLocalExecutor(maxParallelism, maxQueueSize int) *LocalExecutor {
 	}
 	return &LocalExecutor{
 		maxParallelism: maxParallelism,
-		queue:          workqueue.NewDelayingQueue(maxQueueSize),
+		queue:          workqueue.NewDelayingQueueWithClock(maxQueueSize, clock),
 		groups:         make(map[interface{}]int),
 		groupsMu:       &sync.RWMutex{},
+		active:         new(int32),
+		clock:          clock,
 	}
 }
 
+// Clock returns the clock with which the delays of the tasks are measured.
+func (ex *LocalExecutor) Clock() clock.Clock {
+	return ex.clock
+}
+
 func (ex *LocalExecutor) Start() {
 	if ex.maxParallelism <= 0 {
 		panic("LocalExecutor: parallelism should be larger than 0")
@@ -66,9 +84,10 @@ func (ex *LocalExecutor) Start() {
 			queue:    ex.queue,
 			groups:   ex.groups,
 			groupsMu: ex.groupsMu,
+			active:   ex.active,
 		}
 		ex.workers = append(ex.workers, worker)
-		go worker.Run()
+		ctrl.Go("executor", worker.Run)
 	}
 }
 
@@ -84,15 +103,20 @@ func (ex *LocalExecutor) GetGroupTasks(groupID interface{}) int {
 	return count
 }
 
+// Utilization returns the fraction of the workers that are executing a task.
+func (ex *LocalExecutor) Utilization() float64 {
+	return float64(atomic.LoadInt32(ex.active)) / float64(ex.maxParallelism)
+}
+
 func (ex *LocalExecutor) SubmitAfter(t *Task, after time.Duration) bool {
 	// Add to the queue
 	if after <= 0 {
-		accepted := ex.queue.TryAddAfter(t, after)
+		accepted := ex.queue.Add(t)
 		if !accepted {
 			return false
 		}
 	} else {
-		accepted := ex.queue.Add(t)
+		accepted := ex.queue.TryAddAfter(t, after)
 		if !accepted {
 			return false
 		}
@@ -115,6 +139,7 @@ type worker struct {
 	queue    workqueue.Interface
 	groups   map[interface{}]int
 	groupsMu *sync.RWMutex
+	active   *int32
 }
 
 func (w *worker) Run() {
@@ -125,7 +150,9 @@ func (w *worker) Run() {
 		}
 		task := item.(*Task)
 
+		atomic.AddInt32(w.active, 1)
 		executeTask(task)
+		atomic.AddInt32(w.active, -1)
 
 		w.queue.Done(task)
 		if task.GroupID != nil {
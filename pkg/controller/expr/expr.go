@@ -3,6 +3,7 @@ package expr
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/fatih/structs"
@@ -30,6 +31,12 @@ func Resolve(rootScope interface{}, currentTask string, expr *typedvalues.TypedV
 	return DefaultResolver.Resolve(rootScope, currentTask, expr)
 }
 
+// UndefinedReferences returns the references in the expressions of the value to keys that are undefined in the scope,
+// using the default resolver.
+func UndefinedReferences(rootScope interface{}, currentTask string, value *typedvalues.TypedValue) ([]string, error) {
+	return DefaultResolver.UndefinedReferences(rootScope, currentTask, value)
+}
+
 // resolver resolves an expression within a given context/scope.
 type Resolver interface {
 	Resolve(rootScope interface{}, currentTask string, expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error)
@@ -83,20 +90,13 @@ func (oe *JavascriptExpressionParser) resolveExpr(rootScope interface{}, current
 		}
 	}()
 
-	// Setup the JavaScript interpreter
-	scoped := oe.vm.Copy()
-	injectFunctions(scoped, BuiltinFunctions)
-	err := scoped.Set(varScope, rootScope)
-	if err != nil {
-		return nil, err
-	}
-	err = scoped.Set(varCurrentTask, currentTask)
+	scoped, err := oe.newScopedVM(rootScope, currentTask)
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		<-time.After(ResolvingTimeout)
+	// Use a timer rather than a goroutine per resolution, which would outlive the resolution until the timeout.
+	timer := time.AfterFunc(ResolvingTimeout, func() {
 		select {
 		case scoped.Interrupt <- func() {
 			panic(ErrTimeOut)
@@ -104,7 +104,8 @@ func (oe *JavascriptExpressionParser) resolveExpr(rootScope interface{}, current
 		default:
 			// evaluation has already been interrupted / quit
 		}
-	}()
+	})
+	defer timer.Stop()
 
 	e, err := typedvalues.UnwrapExpression(expr)
 	if err != nil {
@@ -133,6 +134,60 @@ func (oe *JavascriptExpressionParser) resolveExpr(rootScope interface{}, current
 	return result, nil
 }
 
+// UndefinedReferences returns the references in the expressions of the value to keys that are undefined in the
+// scope, sorted and without duplicates. Expressions nested in lists and maps are included.
+//
+// A reference is a member expression on the scope (e.g. $.Tasks.fetch), or a call of one of the built-in functions
+// that look up a task or invocation input by a literal key (e.g. output('fetch')). Only the first undefined member of
+// a reference is reported, as the members after it cannot be resolved at all. Members that are computed while the
+// expression is evaluated are not checked.
+func (oe *JavascriptExpressionParser) UndefinedReferences(rootScope interface{}, currentTask string,
+	value *typedvalues.TypedValue) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	i, err := typedvalues.Unwrap(value)
+	if err != nil {
+		return nil, err
+	}
+	var exprs []string
+	collectExpressions(i, &exprs)
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+
+	scoped, err := oe.newScopedVM(rootScope, currentTask)
+	if err != nil {
+		return nil, err
+	}
+	refs := map[string]struct{}{}
+	for _, e := range exprs {
+		collectUndefinedReferences(scoped, typedvalues.RemoveExpressionDelimiters(e), refs)
+	}
+	result := make([]string, 0, len(refs))
+	for ref := range refs {
+		result = append(result, ref)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// newScopedVM returns a copy of the JavaScript interpreter with the built-in functions, the scope and the current
+// task.
+func (oe *JavascriptExpressionParser) newScopedVM(rootScope interface{}, currentTask string) (*otto.Otto, error) {
+	scoped := oe.vm.Copy()
+	injectFunctions(scoped, BuiltinFunctions)
+	err := scoped.Set(varScope, rootScope)
+	if err != nil {
+		return nil, err
+	}
+	err = scoped.Set(varCurrentTask, currentTask)
+	if err != nil {
+		return nil, err
+	}
+	return scoped, nil
+}
+
 func (oe *JavascriptExpressionParser) resolveMap(rootScope interface{}, currentTask string,
 	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
 
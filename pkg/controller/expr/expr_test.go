@@ -93,3 +93,42 @@ func mustParseExpr(s string) *typedvalues.TypedValue {
 
 	return tv
 }
+
+func TestTaskReferences(t *testing.T) {
+	inputs := []*typedvalues.TypedValue{
+		typedvalues.MustWrap("{ $.Tasks.fetch.Output + output('audit') }"),
+		typedvalues.MustWrap(map[string]interface{}{
+			"headers": "{ outputHeaders(\"fetch\", 'Content-Type') }",
+			"list":    []interface{}{"{ $.Tasks['notify'].Status }", "{ task().Inputs }"},
+		}),
+		typedvalues.MustWrap("$.Tasks.literal.Output"),
+		nil,
+	}
+	assert.Equal(t, []string{"audit", "fetch", "notify"}, TaskReferences(inputs...))
+}
+
+func TestUndefinedReferences(t *testing.T) {
+	scope := makeTestScope()
+	value := typedvalues.MustWrap(map[string]interface{}{
+		"defined": "{ $.Tasks.TaskA.Output + output('TaskA') + param('headers') + $.Invocation.Inputs['default'] }",
+		"typo":    "{ $.Tasks.TaskB.Output }",
+		"nested":  []interface{}{"{ output(\"TaskC\") }", "{ $.Invocation.Inputz }"},
+		"inputs":  "{ input('TaskA', 'otherInput') + input('TaskA', 'missing') + param('missing') }",
+		"literal": "$.Tasks.TaskD",
+		"dynamic": "{ output(taskId) }",
+	})
+
+	refs, err := UndefinedReferences(scope, "TaskA", value)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"$.Invocation.Inputz",
+		"$.Tasks.TaskB",
+		"input('TaskA', 'missing')",
+		"output(\"TaskC\")",
+		"param('missing')",
+	}, refs)
+
+	refs, err = UndefinedReferences(scope, "TaskA", mustParseExpr("{ $.Tasks.TaskA.Output }"))
+	assert.NoError(t, err)
+	assert.Empty(t, refs)
+}
@@ -0,0 +1,179 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/robertkrimen/otto"
+	"github.com/robertkrimen/otto/ast"
+	"github.com/robertkrimen/otto/parser"
+)
+
+// taskReferenceRes match the ways in which an expression can refer to a specific task: through the Tasks of the
+// scope, or through the functions that look up a task by its ID.
+var taskReferenceRes = []*regexp.Regexp{
+	regexp.MustCompile(`\$\.Tasks\.([A-Za-z_$][\w$]*)`),
+	regexp.MustCompile(`\$\.Tasks\[\s*["']([^"']+)["']\s*\]`),
+	regexp.MustCompile(`\b(?:input|output|outputHeaders|task)\(\s*["']([^"']+)["']`),
+}
+
+// TaskReferences returns the IDs of the tasks that are referenced by the expressions in the values, sorted and
+// without duplicates. Expressions nested in lists and maps are included.
+//
+// The references are detected statically, so a task ID that is only computed while the expression is evaluated (e.g.
+// output(someVar)) is not detected.
+func TaskReferences(values ...*typedvalues.TypedValue) []string {
+	refs := map[string]struct{}{}
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		i, err := typedvalues.Unwrap(value)
+		if err != nil {
+			continue
+		}
+		collectTaskReferences(i, refs)
+	}
+	result := make([]string, 0, len(refs))
+	for ref := range refs {
+		result = append(result, ref)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func collectTaskReferences(i interface{}, refs map[string]struct{}) {
+	switch t := i.(type) {
+	case string:
+		if !typedvalues.IsExpression(t) {
+			return
+		}
+		for _, re := range taskReferenceRes {
+			for _, match := range re.FindAllStringSubmatch(t, -1) {
+				refs[match[1]] = struct{}{}
+			}
+		}
+	case []interface{}:
+		for _, v := range t {
+			collectTaskReferences(v, refs)
+		}
+	case map[string]interface{}:
+		for _, v := range t {
+			collectTaskReferences(v, refs)
+		}
+	}
+}
+
+// collectExpressions appends the expressions in the (unwrapped) value to exprs, including the expressions nested in
+// lists and maps.
+func collectExpressions(i interface{}, exprs *[]string) {
+	switch t := i.(type) {
+	case string:
+		if typedvalues.IsExpression(t) {
+			*exprs = append(*exprs, t)
+		}
+	case []interface{}:
+		for _, v := range t {
+			collectExpressions(v, exprs)
+		}
+	case map[string]interface{}:
+		for _, v := range t {
+			collectExpressions(v, exprs)
+		}
+	}
+}
+
+// collectUndefinedReferences adds the references of the JavaScript source to keys that are undefined in the scope
+// of the VM to refs. Sources that cannot be parsed are ignored; resolving them reports the syntax error.
+func collectUndefinedReferences(vm *otto.Otto, src string, refs map[string]struct{}) {
+	program, err := parser.ParseFile(nil, "", src, 0)
+	if err != nil {
+		return
+	}
+	ast.Walk(&referenceVisitor{vm: vm, src: src, refs: refs}, program)
+}
+
+// referenceVisitor checks each of the references to the scope in an expression.
+type referenceVisitor struct {
+	vm   *otto.Otto
+	src  string
+	refs map[string]struct{}
+}
+
+func (v *referenceVisitor) Enter(n ast.Node) ast.Visitor {
+	switch node := n.(type) {
+	case *ast.DotExpression, *ast.BracketExpression:
+		if isScopeMember(node) && v.undefined(v.source(node)) {
+			v.refs[v.source(node)] = struct{}{}
+		}
+	case *ast.CallExpression:
+		for _, lookup := range builtinLookups(node) {
+			if v.undefined(lookup) {
+				v.refs[v.source(node)] = struct{}{}
+				break
+			}
+		}
+	}
+	return v
+}
+
+func (v *referenceVisitor) Exit(n ast.Node) {}
+
+// undefined returns whether the lookup evaluates to undefined. A lookup that fails, such as a member of an undefined
+// value, is not considered to be undefined itself; the undefined value that it is a member of is.
+func (v *referenceVisitor) undefined(lookup string) bool {
+	result, err := v.vm.Eval(lookup)
+	return err == nil && result.IsUndefined()
+}
+
+func (v *referenceVisitor) source(n ast.Node) string {
+	// The parser numbers the positions in the source from 1.
+	return v.src[int(n.Idx0())-1 : int(n.Idx1())-1]
+}
+
+// isScopeMember returns whether the node is a chain of literal members on the scope, such as $.Tasks['fetch'].Output.
+func isScopeMember(n ast.Node) bool {
+	switch node := n.(type) {
+	case *ast.Identifier:
+		return node.Name == varScope
+	case *ast.DotExpression:
+		return isScopeMember(node.Left)
+	case *ast.BracketExpression:
+		switch node.Member.(type) {
+		case *ast.StringLiteral, *ast.NumberLiteral:
+			return isScopeMember(node.Left)
+		}
+	}
+	return false
+}
+
+// builtinLookups returns the lookups in the scope that a call of one of the built-in functions performs with literal
+// keys, in order. Calls of other functions, or with keys that are computed while the expression is evaluated, have no
+// lookups.
+func builtinLookups(call *ast.CallExpression) []string {
+	callee, ok := call.Callee.(*ast.Identifier)
+	if !ok || len(call.ArgumentList) == 0 {
+		return nil
+	}
+	key, ok := call.ArgumentList[0].(*ast.StringLiteral)
+	if !ok {
+		return nil
+	}
+	switch callee.Name {
+	case "output", "outputHeaders", "task":
+		return []string{fmt.Sprintf("$.Tasks[%q]", key.Value)}
+	case "input":
+		lookups := []string{fmt.Sprintf("$.Tasks[%q]", key.Value)}
+		if len(call.ArgumentList) > 1 {
+			if inputKey, ok := call.ArgumentList[1].(*ast.StringLiteral); ok {
+				lookups = append(lookups, fmt.Sprintf("$.Tasks[%q].Inputs[%q]", key.Value, inputKey.Value))
+			}
+		}
+		return lookups
+	case "param":
+		return []string{fmt.Sprintf("$.Invocation.Inputs[%q]", key.Value)}
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ type Scope struct {
 	Workflow   *WorkflowScope
 	Invocation *InvocationScope
 	Tasks      Tasks
+	Trigger    *TriggerScope // Only set for tasks with a partial join, such as an any-of join
 }
 
 func (s *Scope) DeepCopy() DeepCopier {
@@ -36,6 +37,7 @@ func (s *Scope) DeepCopy() DeepCopier {
 		Workflow:   s.Workflow.DeepCopy().(*WorkflowScope),
 		Invocation: s.Invocation.DeepCopy().(*InvocationScope),
 		Tasks:      s.Tasks.DeepCopy().(Tasks),
+		Trigger:    s.Trigger.DeepCopy().(*TriggerScope),
 	}
 }
 
@@ -53,7 +55,11 @@ type WorkflowScope struct {
 // InvocationScope object provides information about the current invocation.
 type InvocationScope struct {
 	*ObjectMetadata
-	Inputs map[string]interface{}
+	WorkflowId string
+	ParentId   string // ID of the parent invocation, if the invocation is a sub-invocation
+	GroupId    string // ID of the invocation group, if the invocation is a member of a group
+	Deadline   int64  // unix timestamp
+	Inputs     map[string]interface{}
 }
 
 // ObjectMetadata contains identity and meta-data about an object.
@@ -72,6 +78,39 @@ type TaskScope struct {
 	Output        interface{}
 	OutputHeaders interface{}
 	Function      string
+	Error         *ErrorScope    // Only set when the task run failed
+	Meta          *TaskMetaScope // Only set once the task run has been started
+}
+
+// TaskMetaScope describes how the run of a task was executed, which is derived from the events of the task run.
+type TaskMetaScope struct {
+	StartedAt     int64   // unix timestamp of the start of the last attempt
+	FinishedAt    int64   // unix timestamp; 0 while the task run is in progress
+	Duration      float64 // seconds that the last attempt took; 0 while the task run is in progress
+	TotalDuration float64 // seconds from the start of the first attempt until the end of the last attempt
+	Attempts      int32
+	Function      string // the resolved function that served the last attempt
+	Runtime       string
+	Executor      string // the executor type requested for the function, if any
+	Failover      bool   // whether the last attempt was served by the failover function
+	Polls         int32  // the number of polls of a sensor task
+}
+
+// ErrorScope describes why a task run failed. Its fields are the same across all runtimes.
+type ErrorScope struct {
+	Code    string // One of the types.ErrorCode* constants
+	Message string
+	TaskId  string
+	Attempt int32
+	Raw     string // Raw response of the function, if any
+}
+
+// TriggerScope identifies the dependency that triggered a task with a partial join; for an any-of join, the
+// dependency that succeeded first.
+type TriggerScope struct {
+	TaskId        string
+	Output        interface{}
+	OutputHeaders interface{}
 }
 
 func (s Tasks) DeepCopy() DeepCopier {
@@ -104,6 +143,10 @@ func (s *InvocationScope) DeepCopy() DeepCopier {
 	}
 	return &InvocationScope{
 		ObjectMetadata: s.ObjectMetadata.DeepCopy().(*ObjectMetadata),
+		WorkflowId:     s.WorkflowId,
+		ParentId:       s.ParentId,
+		GroupId:        s.GroupId,
+		Deadline:       s.Deadline,
 		Inputs:         DeepCopy(s.Inputs).(map[string]interface{}),
 	}
 }
@@ -129,6 +172,16 @@ func (s *TaskScope) DeepCopy() DeepCopier {
 			requires[k] = DeepCopy(v).(*types.TaskDependencyParameters)
 		}
 	}
+	var errScope *ErrorScope
+	if s.Error != nil {
+		copied := *s.Error
+		errScope = &copied
+	}
+	var meta *TaskMetaScope
+	if s.Meta != nil {
+		copied := *s.Meta
+		meta = &copied
+	}
 
 	return &TaskScope{
 		ObjectMetadata: s.ObjectMetadata.DeepCopy().(*ObjectMetadata),
@@ -139,6 +192,19 @@ func (s *TaskScope) DeepCopy() DeepCopier {
 		Output:         DeepCopy(s.Output),
 		OutputHeaders:  DeepCopy(s.OutputHeaders),
 		Function:       s.Function,
+		Error:          errScope,
+		Meta:           meta,
+	}
+}
+
+func (s *TriggerScope) DeepCopy() DeepCopier {
+	if s == nil {
+		return (*TriggerScope)(nil)
+	}
+	return &TriggerScope{
+		TaskId:        s.TaskId,
+		Output:        DeepCopy(s.Output),
+		OutputHeaders: DeepCopy(s.OutputHeaders),
 	}
 }
 
@@ -155,6 +221,10 @@ func NewScope(base *Scope, wfi *types.WorkflowInvocation) (*Scope, error) {
 		}
 		updated.Invocation = &InvocationScope{
 			ObjectMetadata: formatMetadata(wfi.Metadata),
+			WorkflowId:     wfi.Workflow().ID(),
+			ParentId:       wfi.GetSpec().GetParentId(),
+			GroupId:        wfi.GetSpec().GetGroupId(),
+			Deadline:       formatTimestamp(wfi.GetSpec().GetDeadline()),
 			Inputs:         invocationParams,
 		}
 	}
@@ -190,6 +260,10 @@ func NewScope(base *Scope, wfi *types.WorkflowInvocation) (*Scope, error) {
 			OutputHeaders:  outputHeaders,
 			Function:       task.GetSpec().GetFunctionRef(),
 		}
+		if run, ok := wfi.TaskInvocation(taskId); ok {
+			updated.Tasks[taskId].Error = formatError(run.GetStatus().GetError())
+			updated.Tasks[taskId].Meta = formatTaskMeta(run)
+		}
 	}
 
 	if base == nil {
@@ -205,6 +279,23 @@ func NewScope(base *Scope, wfi *types.WorkflowInvocation) (*Scope, error) {
 	return updated, nil
 }
 
+// WithTrigger returns a copy of the scope in which the dependency that triggered the task is exposed, if the task has
+// a partial join. Otherwise, the scope is returned as is. The copy shares the other fields with the scope.
+func WithTrigger(scope *Scope, wfi *types.WorkflowInvocation, taskID string) *Scope {
+	triggerID, ok := wfi.JoinTrigger(taskID)
+	if !ok {
+		return scope
+	}
+	trigger := &TriggerScope{TaskId: triggerID}
+	if dep, ok := scope.Tasks[triggerID]; ok {
+		trigger.Output = dep.Output
+		trigger.OutputHeaders = dep.OutputHeaders
+	}
+	copied := *scope
+	copied.Trigger = trigger
+	return &copied
+}
+
 func formatWorkflow(wf *types.Workflow) *WorkflowScope {
 	return &WorkflowScope{
 		ObjectMetadata: formatMetadata(wf.Metadata),
@@ -215,6 +306,52 @@ func formatWorkflow(wf *types.Workflow) *WorkflowScope {
 	}
 }
 
+func formatError(err *types.Error) *ErrorScope {
+	if err == nil {
+		return nil
+	}
+	return &ErrorScope{
+		Code:    err.GetCode(),
+		Message: err.GetMessage(),
+		TaskId:  err.GetTaskId(),
+		Attempt: err.GetAttempt(),
+		Raw:     err.GetRaw(),
+	}
+}
+
+func formatTaskMeta(run *types.TaskInvocation) *TaskMetaScope {
+	startedAt, err := ptypes.Timestamp(run.GetMetadata().GetCreatedAt())
+	if err != nil {
+		return nil
+	}
+	meta := &TaskMetaScope{
+		StartedAt: startedAt.UnixNano(),
+		Attempts:  run.GetSpec().GetAttempt(),
+		Function:  run.GetSpec().GetFnRef().GetID(),
+		Runtime:   run.GetSpec().GetFnRef().GetRuntime(),
+		Executor:  run.GetSpec().GetExecutorType(),
+		Failover:  run.GetSpec().GetFailover(),
+		Polls:     run.GetStatus().GetPolls(),
+	}
+	if meta.Attempts == 0 {
+		meta.Attempts = 1
+	}
+	if run.GetStatus() == nil || !run.GetStatus().Finished() {
+		return meta
+	}
+	finishedAt, err := ptypes.Timestamp(run.GetStatus().GetUpdatedAt())
+	if err != nil {
+		return meta
+	}
+	meta.FinishedAt = finishedAt.UnixNano()
+	meta.Duration = finishedAt.Sub(startedAt).Seconds()
+	meta.TotalDuration = meta.Duration
+	if firstAttemptAt, err := ptypes.Timestamp(run.GetSpec().GetFirstAttemptAt()); err == nil {
+		meta.TotalDuration = finishedAt.Sub(firstAttemptAt).Seconds()
+	}
+	return meta
+}
+
 func formatMetadata(meta *types.ObjectMetadata) *ObjectMetadata {
 	if meta == nil {
 		return nil
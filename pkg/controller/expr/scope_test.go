@@ -2,10 +2,12 @@ package expr
 
 import (
 	"testing"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,6 +67,13 @@ func TestScopeExpr(t *testing.T) {
 
 	resolvedString, _ := typedvalues.Unwrap(resolved)
 	assert.Equal(t, expected, resolvedString)
+
+	// The metadata of the invocation should be available in the scope
+	resolved, err = exprParser.Resolve(actualScope, "fooTask",
+		mustParseExpr("{$.Invocation.Id + '/' + $.Invocation.WorkflowId}"))
+	assert.NoError(t, err)
+	assert.Equal(t, "testWorkflowInvocation/testWorkflow", typedvalues.MustUnwrap(resolved))
+	assert.NotZero(t, actualScope.Invocation.CreatedAt)
 }
 
 func TestScopeOverride(t *testing.T) {
@@ -145,3 +154,228 @@ func TestScopeOverride(t *testing.T) {
 	assert.NotEqual(t, scope2, scope4)
 	assert.Equal(t, scope2.Workflow, scope4.Workflow)
 }
+
+func TestScopeTaskError(t *testing.T) {
+	scope, err := NewScope(nil, &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{
+			Id:        "testWorkflowInvocation",
+			CreatedAt: ptypes.TimestampNow(),
+		},
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{
+				Metadata: &types.ObjectMetadata{
+					Id:        "testWorkflow",
+					CreatedAt: ptypes.TimestampNow(),
+				},
+				Status: &types.WorkflowStatus{
+					Status:    types.WorkflowStatus_READY,
+					UpdatedAt: ptypes.TimestampNow(),
+					Tasks: map[string]*types.Task{
+						"fooTask": {
+							Status: &types.TaskStatus{},
+						},
+						"barTask": {
+							Status: &types.TaskStatus{},
+						},
+					},
+				},
+				Spec: &types.WorkflowSpec{
+					ApiVersion: "1",
+					OutputTask: "barTask",
+				},
+			},
+		},
+		Status: &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_IN_PROGRESS,
+			Tasks: map[string]*types.TaskInvocation{
+				"fooTask": {
+					Spec: &types.TaskInvocationSpec{},
+					Status: &types.TaskInvocationStatus{
+						Status: types.TaskInvocationStatus_FAILED,
+						Error: &types.Error{
+							Code:    types.ErrorCodeFunction,
+							Message: "function error: out of stock",
+							TaskId:  "fooTask",
+							Attempt: 1,
+							Raw:     "out of stock",
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, scope.Tasks["barTask"].Error)
+
+	exprParser := NewJavascriptExpressionParser()
+	resolved, err := exprParser.Resolve(scope, "barTask",
+		mustParseExpr("{$.Tasks.fooTask.Error.Code + ':' + $.Tasks.fooTask.Error.Raw}"))
+	assert.NoError(t, err)
+	assert.Equal(t, "FUNCTION_ERROR:out of stock", typedvalues.MustUnwrap(resolved))
+
+	// The error should survive copying the scope.
+	copied := scope.DeepCopy().(*Scope)
+	assert.Equal(t, scope.Tasks["fooTask"].Error, copied.Tasks["fooTask"].Error)
+}
+
+func TestScopeTaskMeta(t *testing.T) {
+	firstAttemptAt := time.Date(2018, 1, 2, 3, 4, 0, 0, time.UTC)
+	startedAt := firstAttemptAt.Add(10 * time.Second)
+	ts := func(at time.Time) *timestamp.Timestamp {
+		pts, _ := ptypes.TimestampProto(at)
+		return pts
+	}
+	invocation := &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{
+			Id:        "testWorkflowInvocation",
+			CreatedAt: ptypes.TimestampNow(),
+		},
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{
+				Metadata: &types.ObjectMetadata{
+					Id:        "testWorkflow",
+					CreatedAt: ptypes.TimestampNow(),
+				},
+				Status: &types.WorkflowStatus{
+					Status:    types.WorkflowStatus_READY,
+					UpdatedAt: ptypes.TimestampNow(),
+					Tasks: map[string]*types.Task{
+						"fetch":  {Status: &types.TaskStatus{}},
+						"render": {Status: &types.TaskStatus{}},
+					},
+				},
+				Spec: &types.WorkflowSpec{
+					ApiVersion: "1",
+					OutputTask: "render",
+				},
+			},
+		},
+		Status: &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_IN_PROGRESS,
+			Tasks: map[string]*types.TaskInvocation{
+				"fetch": {
+					Metadata: &types.ObjectMetadata{Id: "fetch", CreatedAt: ts(startedAt)},
+					Spec: &types.TaskInvocationSpec{
+						FnRef:          &types.FnRef{Runtime: "fission", ID: "fetch-v2"},
+						ExecutorType:   "newdeploy",
+						Attempt:        2,
+						FirstAttemptAt: ts(firstAttemptAt),
+					},
+					Status: &types.TaskInvocationStatus{
+						Status:    types.TaskInvocationStatus_SUCCEEDED,
+						UpdatedAt: ts(startedAt.Add(6500 * time.Millisecond)),
+					},
+				},
+			},
+		},
+	}
+	scope, err := NewScope(nil, invocation)
+	assert.NoError(t, err)
+	assert.Nil(t, scope.Tasks["render"].Meta)
+	assert.Equal(t, &TaskMetaScope{
+		StartedAt:     startedAt.UnixNano(),
+		FinishedAt:    startedAt.Add(6500 * time.Millisecond).UnixNano(),
+		Duration:      6.5,
+		TotalDuration: 16.5,
+		Attempts:      2,
+		Function:      "fetch-v2",
+		Runtime:       "fission",
+		Executor:      "newdeploy",
+	}, scope.Tasks["fetch"].Meta)
+
+	exprParser := NewJavascriptExpressionParser()
+	resolved, err := exprParser.Resolve(scope, "render",
+		mustParseExpr("{$.Tasks.fetch.Meta.Duration > 5 ? 'fast' : 'full'}"))
+	assert.NoError(t, err)
+	assert.Equal(t, "fast", typedvalues.MustUnwrap(resolved))
+
+	// The metadata should survive copying the scope.
+	copied := scope.DeepCopy().(*Scope)
+	assert.Equal(t, scope.Tasks["fetch"].Meta, copied.Tasks["fetch"].Meta)
+
+	// While the task run is in progress, it has not finished yet.
+	invocation.Status.Tasks["fetch"].Status.Status = types.TaskInvocationStatus_IN_PROGRESS
+	scope, err = NewScope(nil, invocation)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, scope.Tasks["fetch"].Meta.FinishedAt)
+	assert.EqualValues(t, 0, scope.Tasks["fetch"].Meta.Duration)
+}
+
+func TestScopeTrigger(t *testing.T) {
+	branch := func(output string, updatedAt int64) *types.TaskInvocation {
+		return &types.TaskInvocation{
+			Spec: &types.TaskInvocationSpec{},
+			Status: &types.TaskInvocationStatus{
+				Status:    types.TaskInvocationStatus_SUCCEEDED,
+				Output:    typedvalues.MustWrap(output),
+				UpdatedAt: &timestamp.Timestamp{Seconds: updatedAt},
+			},
+		}
+	}
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("testWorkflowInvocation"),
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{
+				Metadata: types.NewObjectMetadata("testWorkflow"),
+				Status: &types.WorkflowStatus{
+					Tasks: map[string]*types.Task{
+						"mirrorA": {Metadata: types.NewObjectMetadata("mirrorA"), Spec: &types.TaskSpec{},
+							Status: &types.TaskStatus{}},
+						"mirrorB": {Metadata: types.NewObjectMetadata("mirrorB"), Spec: &types.TaskSpec{},
+							Status: &types.TaskStatus{}},
+						"first": {
+							Metadata: types.NewObjectMetadata("first"),
+							Spec: &types.TaskSpec{
+								Requires: types.Require("mirrorA", "mirrorB"),
+								Await:    1,
+							},
+							Status: &types.TaskStatus{},
+						},
+						"all": {
+							Metadata: types.NewObjectMetadata("all"),
+							Spec: &types.TaskSpec{
+								Requires: types.Require("mirrorA", "mirrorB"),
+								Await:    2,
+							},
+							Status: &types.TaskStatus{},
+						},
+					},
+				},
+				Spec: &types.WorkflowSpec{
+					ApiVersion: "1",
+					OutputTask: "first",
+				},
+			},
+		},
+		Status: &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_IN_PROGRESS,
+			Tasks: map[string]*types.TaskInvocation{
+				"mirrorA": branch("a", 2),
+				"mirrorB": branch("b", 1),
+			},
+		},
+	}
+	scope, err := NewScope(nil, invocation)
+	assert.NoError(t, err)
+
+	// The any-of join exposes the branch that won the race.
+	triggered := WithTrigger(scope, invocation, "first")
+	exprParser := NewJavascriptExpressionParser()
+	resolved, err := exprParser.Resolve(triggered, "first", mustParseExpr("{$.Trigger.TaskId + ':' + $.Trigger.Output}"))
+	assert.NoError(t, err)
+	assert.Equal(t, "mirrorB:b", typedvalues.MustUnwrap(resolved))
+	assert.Nil(t, scope.Trigger)
+
+	// When the other branch wins the race, the binding should adapt accordingly.
+	invocation.Status.Tasks["mirrorA"].Status.UpdatedAt = &timestamp.Timestamp{Nanos: 1}
+	resolved, err = exprParser.Resolve(WithTrigger(scope, invocation, "first"), "first",
+		mustParseExpr("{$.Trigger.TaskId + ':' + $.Trigger.Output}"))
+	assert.NoError(t, err)
+	assert.Equal(t, "mirrorA:a", typedvalues.MustUnwrap(resolved))
+
+	// Tasks that await all of their dependencies do not have a trigger.
+	assert.Equal(t, scope, WithTrigger(scope, invocation, "all"))
+	resolved, err = exprParser.Resolve(scope, "all", mustParseExpr("{$.Trigger ? 'set' : 'unset'}"))
+	assert.NoError(t, err)
+	assert.Equal(t, "unset", typedvalues.MustUnwrap(resolved))
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultFinalizationRetries is the default number of retries of the finalization of an invocation, after which
+	// the failures are reported as persistent.
+	DefaultFinalizationRetries = 5
+
+	// DefaultFinalizationBackoff is the default delay before the first retry of the finalization of an invocation.
+	// The delay doubles with every retry, up to maxFinalizationBackoff.
+	DefaultFinalizationBackoff = 100 * time.Millisecond
+
+	maxFinalizationBackoff = 10 * time.Second
+
+	// finishedObservationsSize bounds the number of finished invocations that are remembered in order to observe
+	// them only once.
+	finishedObservationsSize = 10000
+)
+
+var metricFinalizationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "finalization_failures_total",
+	Help:      "Number of failed attempts to append the terminal event of an invocation, by kind (success or fail)",
+}, []string{"kind"})
+
+func init() {
+	prometheus.MustRegister(metricFinalizationFailures)
+}
+
+// FinalizationRetry configures the retries of the finalization of invocations, which appends their terminal event
+// (completed or failed) to the event store.
+type FinalizationRetry struct {
+	// MaxRetries is the number of retries after which the failures of the finalization are reported as persistent.
+	// The finalization is retried at the maximum backoff from then on, rather than being dropped. If 0,
+	// DefaultFinalizationRetries is used.
+	MaxRetries int
+
+	// Backoff is the delay before the first retry, which doubles with every retry. If 0, DefaultFinalizationBackoff
+	// is used.
+	Backoff time.Duration
+}
+
+func (r FinalizationRetry) maxRetries() int {
+	if r.MaxRetries <= 0 {
+		return DefaultFinalizationRetries
+	}
+	return r.MaxRetries
+}
+
+// backoff returns the delay before the given (1-based) retry.
+func (r FinalizationRetry) backoff(retry int) time.Duration {
+	backoff := r.Backoff
+	if backoff <= 0 {
+		backoff = DefaultFinalizationBackoff
+	}
+	for i := 1; i < retry && backoff < maxFinalizationBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxFinalizationBackoff {
+		backoff = maxFinalizationBackoff
+	}
+	return backoff
+}
+
+// finishedObservations remembers the finished invocations of which the final status and duration have been
+// observed, so that the invocations that are evaluated again after they finished, for example by a recreated
+// controller, are not observed twice. A nil finishedObservations observes every evaluation.
+type finishedObservations struct {
+	cache *lru.Cache
+}
+
+func newFinishedObservations(size int) *finishedObservations {
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	return &finishedObservations{cache: cache}
+}
+
+// first returns true if the invocation has not been observed before, and marks it as observed.
+func (o *finishedObservations) first(invocationID string) bool {
+	if o == nil {
+		return true
+	}
+	seen, _ := o.cache.ContainsOrAdd(invocationID, struct{}{})
+	return !seen
+}
+
+// finalize submits the finalization of the invocation, which appends its terminal event with apply. The kind
+// identifies the finalization task, so that concurrent finalizations of the same kind are merged.
+//
+// Transient failures are retried with an exponential backoff (see InvocationConfig.FinalizationRetry). As long as
+// the finalization is pending, the invocation has an open task, which prevents it from being evaluated again. Once
+// the terminal event has been appended, later finalizations are ignored, which prevents the controller from emitting
+// another terminal event when it evaluates the invocation before the event has been observed.
+func (c *InvocationController) finalize(invocationID, kind string, apply func() error) {
+	if atomic.LoadInt32(&c.finalized) == 1 {
+		return
+	}
+	c.submitFinalization(invocationID, kind, apply, 0, 0)
+}
+
+func (c *InvocationController) submitFinalization(invocationID, kind string, apply func() error, retry int,
+	after time.Duration) {
+	c.executor.SubmitAfter(&executor.Task{
+		TaskID:  invocationID + "." + kind,
+		GroupID: invocationID,
+		Apply: func() error {
+			if atomic.LoadInt32(&c.finalized) == 1 {
+				return nil
+			}
+			err := apply()
+			if err == nil {
+				atomic.StoreInt32(&c.finalized, 1)
+				return nil
+			}
+			metricFinalizationFailures.WithLabelValues(kind).Inc()
+			retry++
+			backoff := c.config.FinalizationRetry.backoff(retry)
+			if retry > c.config.FinalizationRetry.maxRetries() {
+				c.logger.Errorf("Failed to finalize invocation (%s) after %d attempts; retrying in %v: %v", kind,
+					retry, backoff, err)
+			} else {
+				c.logger.Warnf("Failed to finalize invocation (%s); retrying in %v: %v", kind, backoff, err)
+			}
+			c.submitFinalization(invocationID, kind, apply, retry, backoff)
+			return err
+		},
+	}, after)
+}
+
+// fail submits the finalization of the invocation as failed.
+func (c *InvocationController) fail(invocationID string, err error) {
+	c.finalize(invocationID, "fail", func() error {
+		return c.invocationAPI.Fail(invocationID, err)
+	})
+}
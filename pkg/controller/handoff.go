@@ -0,0 +1,458 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// HandoffActive indicates that the controller instance owns the invocations, and evaluates them.
+	HandoffActive HandoffState = "active"
+
+	// HandoffStandby indicates that the controller instance does not own any invocation yet. It evaluates the
+	// invocations once it has imported the state of another instance, or once it has reclaimed them.
+	HandoffStandby HandoffState = "standby"
+
+	// HandoffDraining indicates that the controller instance is waiting for its in-flight evaluations and tasks to
+	// finish, in order to export its state.
+	HandoffDraining HandoffState = "draining"
+
+	// HandoffReleased indicates that the controller instance has exported its state, and handed off the invocations to
+	// the instance that imports the state.
+	HandoffReleased HandoffState = "released"
+
+	// EventHandoff is the type of the evaluations that resume the invocations after the state has been imported.
+	EventHandoff = "handoff"
+
+	handoffDrainInterval = 10 * time.Millisecond
+)
+
+var (
+	ErrHandoffState        = errors.New("invalid handoff state")
+	ErrHandoffDrainTimeout = errors.New("timed out waiting for the in-flight tasks to finish")
+	ErrHandoffMismatch     = errors.New("handoff does not match")
+)
+
+var metricHandoffInvocations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "handoff_invocations_total",
+	Help:      "Number of invocations of which the controller state was exported or imported, by direction",
+}, []string{"direction"})
+
+func init() {
+	prometheus.MustRegister(metricHandoffInvocations)
+}
+
+// HandoffState is the state of the controller instance in the handoff of the invocations between instances.
+type HandoffState string
+
+// HandoffStatus describes the ownership of the invocations by the controller instance.
+type HandoffStatus struct {
+	State HandoffState
+
+	// HandoffID identifies the last handoff that this instance took part in, if any.
+	HandoffID string
+
+	// Epoch is incremented by every handoff. An instance only imports the state of a handoff with a higher epoch than
+	// its own, which prevents a snapshot from being imported twice.
+	Epoch int64
+}
+
+// Snapshot is the live state of an invocation controller, which is handed off to another controller instance. The
+// state that can be derived from the invocations themselves, such as the expression state, is not part of the
+// snapshot.
+type Snapshot struct {
+	HandoffID string
+	Epoch     int64
+	CreatedAt time.Time
+
+	// Invocations contains the state of the controllers of the invocations that have not finished.
+	Invocations []InvocationSnapshot
+
+	// Queued contains the invocations of which an evaluation was queued, or deferred during the handoff, in the order
+	// in which they should be evaluated.
+	Queued []string
+
+	// Locks contains the concurrency keys that are held or queued for.
+	Locks []ConcurrencyLock
+}
+
+// InvocationSnapshot is the state of the controller of a single invocation.
+type InvocationSnapshot struct {
+	ID string
+
+	// StartedTasks contains the tasks that were submitted by the controller. The tasks have finished before the
+	// snapshot was taken, but their results may not have reached the cache of the importing instance yet; until they
+	// have, the invocation is not evaluated again.
+	StartedTasks []string
+
+	ErrorCount          int
+	TaskErrors          map[string]int
+	LastError           string
+	NoopEvals           int
+	Prewarmed           bool
+	CompletedEarly      bool
+	SoftTimeoutExceeded bool
+
+	// LockKey is the evaluated concurrency key of the invocation, if any.
+	LockKey string
+
+	// PendingPolls contains the times at which the next polls of the sensors of the invocation are due.
+	PendingPolls map[string]time.Time
+
+	Stats ctrl.ControllerStats
+}
+
+// handoff guards the ownership of the invocations by the controller instance. The evaluations hold a read lock for
+// their duration, which allows the state of the controllers to be exported once the running evaluations have
+// finished.
+//
+// A nil handoff owns all invocations.
+type handoff struct {
+	status   HandoffStatus
+	deferred []string
+	mu       *sync.RWMutex
+}
+
+func newHandoff(standby bool) *handoff {
+	state := HandoffActive
+	if standby {
+		state = HandoffStandby
+	}
+	return &handoff{
+		status: HandoffStatus{State: state},
+		mu:     &sync.RWMutex{},
+	}
+}
+
+// acquire returns whether this instance owns the invocation. If it does, the ownership cannot change until release is
+// called. If it does not, the evaluation of the invocation is recorded to be resumed by the next owner.
+func (h *handoff) acquire(invocationID string) bool {
+	if h == nil {
+		return true
+	}
+	for {
+		h.mu.RLock()
+		if h.status.State == HandoffActive {
+			return true
+		}
+		h.mu.RUnlock()
+
+		h.mu.Lock()
+		state := h.status.State
+		if state != HandoffActive && state != HandoffReleased && !containsString(h.deferred, invocationID) {
+			h.deferred = append(h.deferred, invocationID)
+		}
+		h.mu.Unlock()
+		// The instance may have become the owner in the meantime.
+		if state != HandoffActive {
+			return false
+		}
+	}
+}
+
+func (h *handoff) release() {
+	if h != nil {
+		h.mu.RUnlock()
+	}
+}
+
+func (h *handoff) get() HandoffStatus {
+	if h == nil {
+		return HandoffStatus{State: HandoffActive}
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.status
+}
+
+// HandoffStatus returns the ownership of the invocations by this controller instance.
+func (c *InvocationMetaController) HandoffStatus() HandoffStatus {
+	return c.handoff.get()
+}
+
+// ExportState hands off the invocations to another controller instance, and returns the state that the other
+// instance should import to resume them.
+//
+// The instance stops evaluating the invocations, and waits for the in-flight tasks to finish, up to the drain
+// timeout. If they do not finish in time, the instance resumes the evaluation of the invocations, and
+// ErrHandoffDrainTimeout is returned. Otherwise, the instance releases the invocations: it no longer evaluates them,
+// unless the handoff is reclaimed with ReclaimState.
+func (c *InvocationMetaController) ExportState(drainTimeout time.Duration) (*Snapshot, error) {
+	h := c.handoff
+	h.mu.Lock()
+	if h.status.State != HandoffActive {
+		state := h.status.State
+		h.mu.Unlock()
+		return nil, fmt.Errorf("%v: cannot export the state of a controller that is %s", ErrHandoffState, state)
+	}
+	// The write lock waits for the running evaluation to finish.
+	h.status.State = HandoffDraining
+	h.deferred = nil
+	h.mu.Unlock()
+	logrus.Warnf("Draining the invocation controller to hand off the invocations (timeout: %v)", drainTimeout)
+
+	if !c.drain(drainTimeout) {
+		h.mu.Lock()
+		h.status.State = HandoffActive
+		deferred := h.deferred
+		h.deferred = nil
+		h.mu.Unlock()
+		for _, invocationID := range deferred {
+			c.submitEvaluation(invocationID, EventReevaluate)
+		}
+		return nil, ErrHandoffDrainTimeout
+	}
+
+	// The tasks and polls of the invocations cannot be started anymore, as long as the instance does not own them.
+	snapshot := &Snapshot{
+		HandoffID: util.UID(),
+		CreatedAt: time.Now(),
+		Queued:    c.system.Queued(),
+		Locks:     c.locks.List(),
+	}
+	c.rangeControllers(func(ic *InvocationController) {
+		snapshot.Invocations = append(snapshot.Invocations, ic.snapshot())
+	})
+	sort.Slice(snapshot.Invocations, func(i, j int) bool {
+		return snapshot.Invocations[i].ID < snapshot.Invocations[j].ID
+	})
+	for i := range snapshot.Invocations {
+		stats, _ := c.system.GetControllerStats(snapshot.Invocations[i].ID)
+		snapshot.Invocations[i].Stats = stats
+	}
+
+	h.mu.Lock()
+	for _, invocationID := range h.deferred {
+		if !containsString(snapshot.Queued, invocationID) {
+			snapshot.Queued = append(snapshot.Queued, invocationID)
+		}
+	}
+	snapshot.Epoch = h.status.Epoch + 1
+	h.status = HandoffStatus{
+		State:     HandoffReleased,
+		HandoffID: snapshot.HandoffID,
+		Epoch:     snapshot.Epoch,
+	}
+	h.deferred = nil
+	h.mu.Unlock()
+	metricHandoffInvocations.WithLabelValues("export").Add(float64(len(snapshot.Invocations)))
+	logrus.Warnf("Released %d invocation(s) in handoff %s (epoch %d)", len(snapshot.Invocations),
+		snapshot.HandoffID, snapshot.Epoch)
+	return snapshot, nil
+}
+
+// ImportState resumes the invocations of the snapshot of another controller instance, after which this instance
+// owns the invocations. The instance needs to be in standby.
+func (c *InvocationMetaController) ImportState(snapshot *Snapshot) error {
+	h := c.handoff
+	h.mu.Lock()
+	if h.status.State != HandoffStandby {
+		state := h.status.State
+		h.mu.Unlock()
+		return fmt.Errorf("%v: cannot import state into a controller that is %s", ErrHandoffState, state)
+	}
+	if snapshot.Epoch <= h.status.Epoch {
+		h.mu.Unlock()
+		return fmt.Errorf("%v: epoch %d of the snapshot is not newer than epoch %d of the controller",
+			ErrHandoffMismatch, snapshot.Epoch, h.status.Epoch)
+	}
+
+	var imported []string
+	for _, state := range snapshot.Invocations {
+		invocation, err := c.invocations.GetInvocation(state.ID)
+		if err != nil || invocation == nil {
+			logrus.Warnf("Skipping the handed off state of invocation %s: %v", state.ID, err)
+			continue
+		}
+		controller, err := c.factory(newEvaluation(invocation, EventHandoff))
+		if err != nil {
+			logrus.Warnf("Skipping the handed off state of invocation %s: %v", state.ID, err)
+			continue
+		}
+		ic := controller.(*InvocationController)
+		ic.restore(state)
+		c.system.AddController(state.ID, ic)
+		c.system.RestoreControllerStats(state.ID, state.Stats)
+		ic.resumePolls(invocation, state.PendingPolls)
+		imported = append(imported, state.ID)
+	}
+	c.locks.Restore(snapshot.Locks)
+	h.status = HandoffStatus{
+		State:     HandoffActive,
+		HandoffID: snapshot.HandoffID,
+		Epoch:     snapshot.Epoch,
+	}
+	deferred := h.deferred
+	h.deferred = nil
+	h.mu.Unlock()
+	metricHandoffInvocations.WithLabelValues("import").Add(float64(len(imported)))
+	logrus.Warnf("Imported %d invocation(s) in handoff %s (epoch %d)", len(imported), snapshot.HandoffID,
+		snapshot.Epoch)
+
+	// Resume the evaluations that were queued first, followed by the other invocations, including the ones that
+	// were created during the handoff.
+	resumed := map[string]bool{}
+	order := append(append(append([]string{}, snapshot.Queued...), imported...), deferred...)
+	for _, invocationID := range order {
+		if resumed[invocationID] {
+			continue
+		}
+		resumed[invocationID] = true
+		c.submitEvaluation(invocationID, EventHandoff)
+	}
+	return nil
+}
+
+// ReclaimState makes this instance the owner of the invocations again. On an instance that released the invocations,
+// it reverts the handoff with the given ID, which is only safe if the state was not imported by another instance. On
+// an instance in standby, it takes over the invocations without a snapshot, which recovers the invocations from the
+// cache as after a restart.
+func (c *InvocationMetaController) ReclaimState(handoffID string) error {
+	h := c.handoff
+	h.mu.Lock()
+	switch h.status.State {
+	case HandoffReleased:
+		if handoffID != h.status.HandoffID {
+			h.mu.Unlock()
+			return fmt.Errorf("%v: the invocations were released in handoff %s", ErrHandoffMismatch,
+				h.status.HandoffID)
+		}
+	case HandoffStandby:
+	default:
+		state := h.status.State
+		h.mu.Unlock()
+		return fmt.Errorf("%v: cannot reclaim the invocations of a controller that is %s", ErrHandoffState, state)
+	}
+	h.status.State = HandoffActive
+	deferred := h.deferred
+	h.deferred = nil
+	h.mu.Unlock()
+	logrus.Warnf("Reclaimed the invocations (handoff: %s)", handoffID)
+
+	for _, invocationID := range deferred {
+		c.submitEvaluation(invocationID, EventReevaluate)
+	}
+	return nil
+}
+
+// drain waits until no tasks or sensor polls of the invocations are in flight.
+func (c *InvocationMetaController) drain(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		inFlight := false
+		c.rangeControllers(func(ic *InvocationController) {
+			if c.executor.GetGroupTasks(ic.invocationID) > 0 || ic.activePollCount() > 0 {
+				inFlight = true
+			}
+		})
+		if !inFlight {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(handoffDrainInterval)
+	}
+}
+
+// rangeControllers calls fn for the controllers of the invocations that have not finished.
+func (c *InvocationMetaController) rangeControllers(fn func(ic *InvocationController)) {
+	c.system.RangeControllers(func(key string, controller ctrl.Controller) bool {
+		if ic, ok := controller.(*InvocationController); ok && !c.system.IsFinished(key) {
+			fn(ic)
+		}
+		return true
+	})
+}
+
+func (c *InvocationMetaController) submitEvaluation(invocationID string, eventType string) {
+	invocation, err := c.invocations.GetInvocation(invocationID)
+	if err != nil || invocation == nil {
+		logrus.Debugf("Could not resume evaluation of invocation %s: %v", invocationID, err)
+		return
+	}
+	c.system.Submit(newEvaluation(invocation, eventType))
+}
+
+func newEvaluation(invocation *types.WorkflowInvocation, eventType string) *ctrl.Event {
+	aggregate := fes.Aggregate{
+		Type: types.TypeInvocation,
+		Id:   invocation.ID(),
+	}
+	return &ctrl.Event{
+		Old:     invocation,
+		Updated: invocation,
+		Event: &fes.Event{
+			Type:      eventType,
+			Aggregate: &aggregate,
+			Timestamp: ptypes.TimestampNow(),
+		},
+		Aggregate: aggregate,
+	}
+}
+
+func (c *InvocationController) snapshot() InvocationSnapshot {
+	state := InvocationSnapshot{
+		ID:                  c.invocationID,
+		NoopEvals:           c.noopEvals,
+		Prewarmed:           c.prewarmed,
+		CompletedEarly:      c.completedEarly,
+		SoftTimeoutExceeded: c.softTimeoutExceeded,
+		TaskErrors:          map[string]int{},
+		PendingPolls:        map[string]time.Time{},
+	}
+	for taskID := range c.startedTasks {
+		state.StartedTasks = append(state.StartedTasks, taskID)
+	}
+	sort.Strings(state.StartedTasks)
+	if c.lockKey != nil {
+		state.LockKey = *c.lockKey
+	}
+	c.errorsMu.Lock()
+	state.ErrorCount = c.errorCount
+	state.LastError = c.lastError
+	for taskID, count := range c.taskErrors {
+		state.TaskErrors[taskID] = count
+	}
+	c.errorsMu.Unlock()
+	c.pollsMu.Lock()
+	for taskID, due := range c.pendingPolls {
+		state.PendingPolls[taskID] = due
+	}
+	c.pollsMu.Unlock()
+	return state
+}
+
+func (c *InvocationController) restore(state InvocationSnapshot) {
+	for _, taskID := range state.StartedTasks {
+		c.startedTasks[taskID] = struct{}{}
+	}
+	c.noopEvals = state.NoopEvals
+	c.prewarmed = state.Prewarmed
+	c.completedEarly = state.CompletedEarly
+	c.softTimeoutExceeded = state.SoftTimeoutExceeded
+	if len(state.LockKey) > 0 {
+		key := state.LockKey
+		c.lockKey = &key
+	}
+	c.errorsMu.Lock()
+	c.errorCount = state.ErrorCount
+	c.lastError = state.LastError
+	for taskID, count := range state.TaskErrors {
+		c.taskErrors[taskID] = count
+	}
+	c.errorsMu.Unlock()
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupHandoffController(t *testing.T, invocations *store.Invocations, exec *executor.LocalExecutor,
+	standby bool) *InvocationMetaController {
+	c, err := NewInvocationMetaController(exec, invocations, nil, nil,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), time.Minute,
+		InvocationConfig{Standby: standby, Concurrency: NewConcurrencyLocks()})
+	assert.NoError(t, err)
+	return c
+}
+
+func TestHandoff(t *testing.T) {
+	cache := testutil.NewCache()
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec:     types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute)),
+		Status:   &types.WorkflowInvocationStatus{Status: types.WorkflowInvocationStatus_IN_PROGRESS},
+	}
+	assert.NoError(t, cache.Put(invocation))
+	invocations := store.NewInvocationStore(cache)
+	blueExec := executor.NewLocalExecutor(1, 10)
+	defer blueExec.Close()
+	greenExec := executor.NewLocalExecutor(1, 10)
+	defer greenExec.Close()
+	blue := setupHandoffController(t, invocations, blueExec, false)
+	green := setupHandoffController(t, invocations, greenExec, true)
+	assert.Equal(t, HandoffStandby, green.HandoffStatus().State)
+
+	// Set up the live state of the invocation in the blue controller.
+	controller, err := blue.factory(newEvaluation(invocation, EventRefresh))
+	assert.NoError(t, err)
+	ic := controller.(*InvocationController)
+	ic.startedTasks["a"] = struct{}{}
+	ic.recordTaskError("a", "connection refused")
+	ic.setPendingPoll("sensor", time.Now().Add(time.Hour))
+	blue.system.AddController("wi", ic)
+	blue.locks.Acquire("wf", "key", "wi", true)
+	blue.locks.Acquire("wf", "key", "wi-2", true)
+
+	snapshot, err := blue.ExportState(time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, HandoffReleased, blue.HandoffStatus().State)
+	assert.EqualValues(t, 1, snapshot.Epoch)
+	assert.Len(t, snapshot.Invocations, 1)
+	assert.Equal(t, []string{"a"}, snapshot.Invocations[0].StartedTasks)
+	assert.Equal(t, map[string]int{"a": 1}, snapshot.Invocations[0].TaskErrors)
+	assert.Equal(t, "a: connection refused", snapshot.Invocations[0].LastError)
+	assert.Contains(t, snapshot.Invocations[0].PendingPolls, "sensor")
+
+	// The blue controller no longer evaluates the invocation, and cannot hand it off twice.
+	result := ic.Eval(context.Background(), newEvaluation(invocation, EventRefresh))
+	assert.Equal(t, ctrl.Success{Msg: "invocation is handed off to another controller instance"}, result)
+	_, err = blue.ExportState(time.Second)
+	assert.Error(t, err)
+
+	assert.NoError(t, green.ImportState(snapshot))
+	assert.Equal(t, HandoffStatus{State: HandoffActive, HandoffID: snapshot.HandoffID, Epoch: 1},
+		green.HandoffStatus())
+	restored, ok := green.system.GetController("wi")
+	assert.True(t, ok)
+	assert.Contains(t, restored.(*InvocationController).startedTasks, "a")
+	assert.Equal(t, 1, restored.(*InvocationController).errorCount)
+	assert.Equal(t, []string{"wi"}, green.system.Queued())
+	lock, ok := green.locks.Get("wf", "key")
+	assert.True(t, ok)
+	assert.Equal(t, "wi", lock.Holder)
+	assert.Equal(t, []string{"wi-2"}, lock.Queued)
+
+	// The snapshot cannot be imported again.
+	assert.Error(t, green.ImportState(snapshot))
+
+	// The blue controller can only reclaim the invocations of the handoff.
+	assert.Error(t, blue.ReclaimState("other"))
+	assert.NoError(t, blue.ReclaimState(snapshot.HandoffID))
+	assert.Equal(t, HandoffActive, blue.HandoffStatus().State)
+}
+
+func TestHandoff_DrainTimeout(t *testing.T) {
+	invocations := store.NewInvocationStore(testutil.NewCache())
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+	c := setupHandoffController(t, invocations, exec, false)
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec:     types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute)),
+	}
+	controller, err := c.factory(newEvaluation(invocation, EventRefresh))
+	assert.NoError(t, err)
+	c.system.AddController("wi", controller)
+
+	done := make(chan struct{})
+	exec.Submit(&executor.Task{
+		TaskID:  "wi.run.a",
+		GroupID: "wi",
+		Apply: func() error {
+			<-done
+			return nil
+		},
+	})
+	_, err = c.ExportState(50 * time.Millisecond)
+	assert.Equal(t, ErrHandoffDrainTimeout, err)
+	assert.Equal(t, HandoffActive, c.HandoffStatus().State)
+
+	close(done)
+	snapshot, err := c.ExportState(time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Invocations, 1)
+}
@@ -4,49 +4,387 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/api"
 	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/configmap"
 	"github.com/fission/fission-workflows/pkg/controller/ctrl"
 	"github.com/fission/fission-workflows/pkg/controller/executor"
 	"github.com/fission/fission-workflows/pkg/controller/expr"
 	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
 	"github.com/fission/fission-workflows/pkg/scheduler"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
 	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/fission/fission-workflows/pkg/util/exemplar"
+	"github.com/fission/fission-workflows/pkg/util/workqueue"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
 )
 
 const (
-	DefaultMaxRuntime       = 10 * time.Minute
-	awaitWorkflowMaxRuntime = 10 * time.Second
+	DefaultNoopEvalThreshold = 100
+	awaitWorkflowMaxRuntime  = 10 * time.Second
 )
 
+var (
+	ErrMemoryBudgetExceeded = errors.New("memory budget exceeded")
+	ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+	ErrRetryTimeExceeded    = errors.New("total time across retries exceeded")
+)
+
+var (
+	metricFirstTaskDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "first_task_duration_seconds",
+		Help:      "Duration from the creation of an invocation until its first task completed, by whether it was prewarmed",
+	}, []string{"prewarmed"})
+	metricLateTaskResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "late_task_results_total",
+		Help:      "Number of task events received after their invocation had already reached a terminal state",
+	}, []string{"eventType"})
+	metricRecoveredTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "recovered_tasks_total",
+		Help:      "Number of in-flight tasks recovered after a controller restart, by whether they were resubmitted or failed",
+	}, []string{"action"})
+	metricRetryBudgetExhausted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "retry_budget_exhausted_total",
+		Help:      "Number of invocations that failed because their retry budget did not allow for retrying failed tasks",
+	})
+	metricRetryTimeExceeded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "retry_time_exceeded_total",
+		Help:      "Number of failed tasks that were not retried because they exceeded the total timeout of their retry policy",
+	})
+	metricSoftTimeouts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "soft_timeouts_total",
+		Help:      "Number of invocations that exceeded the soft timeout of their workflow",
+	})
+	metricNoopEvaluations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "noop_evaluations_total",
+		Help:      "Number of evaluations of invocations that did not result in any task being started or prepared",
+	})
+	metricThrashingInvocations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "thrashing_invocations_total",
+		Help:      "Number of times that an invocation exceeded the threshold of consecutive no-op evaluations",
+	})
+
+	metricStaleReevaluations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "stale_reevaluations_total",
+		Help:      "Number of evaluations of invocations that were submitted because the invocation had become stale",
+	})
+
+	metricTaskFailovers = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "task_failovers_total",
+		Help:      "Number of task runs that were executed by the failover function of the task",
+	})
+
+	// The latency histograms carry exemplars with the trace of the observation.
+	metricInvocationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "invocation_duration_seconds",
+		Help:      "Duration of the finished invocations, from creation until completion",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 18),
+	})
+	metricTaskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "task_duration_seconds",
+		Help:      "Duration of the task runs, from submission until completion, by their final status",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 16),
+	}, []string{"status"})
+)
+
+func init() {
+	prometheus.MustRegister(metricFirstTaskDuration, metricLateTaskResults, metricRecoveredTasks,
+		metricRetryBudgetExhausted, metricRetryTimeExceeded, metricSoftTimeouts, metricNoopEvaluations,
+		metricThrashingInvocations, metricInvocationDuration, metricTaskDuration, metricTaskFailovers,
+		metricStaleReevaluations)
+}
+
+// InvocationConfig contains the configuration of the invocation controllers.
+//
+// The zero value is a valid configuration, which does not constrain the invocations.
+type InvocationConfig struct {
+	// MemoryBudget is the maximum cumulative size (in bytes) of the task outputs that a single invocation is allowed
+	// to retain. Invocations exceeding the budget are failed; the outputs are not spilled to disk instead, as the
+	// expression scope of the invocation requires them to be in memory. If 0, the memory usage of invocations is not
+	// limited.
+	MemoryBudget int64
+
+	// AwaitWorkflowTimeout is the maximum duration to wait for the workflow of a task to become ready, after which
+	// the task fails. If 0, the default awaitWorkflowMaxRuntime is used.
+	AwaitWorkflowTimeout time.Duration
+
+	// ConfigMaps resolves the references to config maps in the inputs of tasks. If nil, the references are not
+	// resolved.
+	ConfigMaps *configmap.Resolver
+
+	// Admission bounds the number of concurrent task executions across all invocations. If nil, the number of
+	// concurrent task executions is only bounded by the parallelism of the executor.
+	Admission *TaskAdmission
+
+	// Suspensions contains the functions of which the scheduling of tasks is suspended. If nil, no functions can be
+	// suspended.
+	Suspensions *FunctionSuspensions
+
+	// RuntimeHealth gates the scheduling of tasks on the health of their function runtime. If nil, tasks are
+	// scheduled regardless of the health of their runtime.
+	RuntimeHealth *RuntimeHealth
+
+	// EnvLimits bounds the number of concurrent task executions per function environment, in addition to the
+	// in-flight task limit of Admission. If nil, the environments are not limited.
+	EnvLimits *EnvironmentLimits
+
+	// Memo memoizes the outputs of pure tasks across invocations. If nil, the outputs of pure tasks are not memoized.
+	Memo *TaskMemo
+
+	// InputMiddleware contains the input middleware that are applied to the inputs of all tasks before they are
+	// submitted. If nil, only the built-in middleware that workflows request are applied.
+	InputMiddleware *InputMiddlewares
+
+	// OutputPaths contains the default output paths per function reference, which are used for the tasks that do not
+	// specify an output path. See TaskSpec.OutputPath.
+	OutputPaths map[string]string
+
+	// FairQueuing partitions the evaluations of invocations by their tenant label, and drains the partitions in a
+	// weighted round-robin. If false, the evaluations are processed in FIFO order.
+	FairQueuing bool
+
+	// TenantWeights contains the weights of the tenants when fair queuing is enabled. Tenants without a weight are
+	// assigned workqueue.DefaultPartitionWeight.
+	TenantWeights map[string]int
+
+	// ErrorBudget limits the number of task errors that an invocation tolerates before it is failed.
+	ErrorBudget ErrorBudget
+
+	// Concurrency holds the concurrency keys of the invocations of workflows with a concurrency policy. If nil, the
+	// concurrency policies are not enforced.
+	Concurrency *ConcurrencyLocks
+
+	// FinishedRetention is the grace period during which the controllers of finished invocations are retained, in
+	// order to ignore late (duplicate) events of these invocations rather than evaluating them again. If 0, the
+	// controllers are deleted as soon as the invocation has finished.
+	FinishedRetention time.Duration
+
+	// EvalDebounce is the window within which the evaluations of an invocation are merged: an evaluation that is
+	// submitted within the window after the previous one is deferred to the end of the window, which reduces the
+	// evaluations of invocations that receive many notifications. If 0, the evaluations are not debounced.
+	EvalDebounce time.Duration
+
+	// StalenessInterval is the interval at which the controller checks for invocations that have not been evaluated
+	// for MaxStaleness, which are re-evaluated. If 0, DefaultStalenessInterval and DefaultMaxStaleness are used.
+	StalenessInterval time.Duration
+	MaxStaleness      time.Duration
+
+	// SLOs evaluates the SLOs of the tasks. If nil, the SLOs of tasks are ignored.
+	SLOs *SLOMonitor
+
+	// Quarantines quarantines the workflows of which the invocations fail consistently. If nil, workflows are never
+	// quarantined.
+	Quarantines *WorkflowQuarantines
+
+	// Load contains the thresholds of the controller load above which the scheduling of low-priority invocations is
+	// deferred. If no thresholds are set, invocations are scheduled regardless of the load.
+	Load LoadThresholds
+
+	// NoopEvalThreshold is the number of consecutive evaluations of an invocation that do not start or prepare any
+	// task, after which the invocation is reported as thrashing: the controller is churning without making progress.
+	// If 0, DefaultNoopEvalThreshold is used.
+	NoopEvalThreshold int
+
+	// Updates determines how the controller learns about updates of invocations. If empty, UpdatesModeAuto is used.
+	Updates UpdatesMode
+
+	// PollingInterval is the interval at which the invocations are polled in the polling updates mode. If 0,
+	// DefaultPollingInterval is used.
+	PollingInterval time.Duration
+
+	// WorkflowMetrics determines which workflows have their own series in the per-workflow metrics. If nil, the
+	// per-workflow metrics of all workflows are rolled up.
+	WorkflowMetrics *WorkflowMetrics
+
+	// MaxLoopIterations is the maximum number of loop iterations that an invocation is allowed to start across all
+	// of its loops, including the loops nested in its sub-invocations. Invocations exceeding the budget are failed.
+	// This is independent of the limit of each loop. If 0, the loop iterations of invocations are not limited.
+	MaxLoopIterations int64
+
+	// Pinning allows invocations to be pinned to a specific controller replica for debugging. If nil, the replica
+	// label of invocations is ignored.
+	Pinning *Pinning
+
+	// TraceSampling decides which invocations are traced. If nil, the decision is left to the sampler of the tracer,
+	// except for invocations that are labeled with types.LabelTrace.
+	TraceSampling *TraceSampling
+
+	// StateStoreTimeout is the maximum duration of a read of the expression state store. Evaluations that depend on
+	// the store are deferred while it is degraded. If 0, the reads are not bounded.
+	StateStoreTimeout time.Duration
+
+	// FinalizationRetry configures the retries of the finalization of invocations, which appends their terminal
+	// event to the event store.
+	FinalizationRetry FinalizationRetry
+
+	// ClockSkewTolerance is the maximum clock skew between the nodes of the deployment that is tolerated when checking
+	// the deadlines of invocations and tasks. Deadlines are considered to have passed up to the tolerance late. If 0,
+	// the deadlines are checked against the clock of the controller as is.
+	ClockSkewTolerance time.Duration
+
+	// Clock is the clock from which the controller reads the time for its time-based decisions, such as the deadlines
+	// of invocations and tasks. It should be the same clock as the one of the executor. If nil, the real clock is used.
+	Clock clock.Clock
+
+	// Standby starts the controller without owning any invocation, until it has imported the state of the controller
+	// instance that hands off the invocations. See InvocationMetaController.ImportState.
+	Standby bool
+
+	// loadGate is created by the InvocationMetaController from the load thresholds.
+	loadGate *LoadGate
+
+	// stateStore is created by the InvocationMetaController from the StateStoreTimeout.
+	stateStore *StateStoreMonitor
+
+	// loopBudget is created by the InvocationMetaController from MaxLoopIterations.
+	loopBudget *LoopBudget
+
+	// parallelism is created by the InvocationMetaController; it enforces the maxParallelism of the workflows.
+	parallelism *WorkflowParallelism
+
+	// handoff is created by the InvocationMetaController from Standby.
+	handoff *handoff
+
+	// cancelPropagation is created by the InvocationMetaController.
+	cancelPropagation *CancelPropagation
+
+	// finished is created by the InvocationMetaController.
+	finished *finishedObservations
+
+	// reevaluateAfter is set by the InvocationMetaController; it submits an evaluation of an invocation once the delay
+	// has passed. If nil, the invocation is only evaluated again on its next update or staleness check.
+	reevaluateAfter func(invocationID string, delay time.Duration)
+}
+
+// ErrorBudget limits the number of task errors, i.e. failed task runs, that an invocation tolerates. The errors are
+// counted both across the invocation and per individual task, which allows a single flaky task to fail a number of
+// times without exhausting the budget of the whole invocation.
+//
+// Note that the error budget does not prevent a task failure from failing the invocation; a failed task fails the
+// invocation unless it is retried. The budgets are checked before the next evaluation of the invocation, so
+// remaining retries are not attempted once a budget has been exhausted.
+type ErrorBudget struct {
+	// MaxErrors is the number of task errors across the invocation at which the invocation is failed. If 0, the
+	// errors across the invocation are not limited.
+	MaxErrors int
+
+	// MaxTaskErrors is the number of errors of a single task at which the invocation is failed. If 0, the errors per
+	// task are not limited.
+	MaxTaskErrors int
+}
+
 // InvocationController is the controller for ensuring the processing of a single workflow invocation.
 type InvocationController struct {
-	invocationID  string
-	executor      *executor.LocalExecutor
-	invocationAPI *api.Invocation
-	taskAPI       *api.Task
-	scheduler     *scheduler.InvocationScheduler
-	StateStore    *expr.Store // Future: just grab the initial state of the parent, instead of constantly rebuilding it.
-	span          opentracing.Span
-	logger        *logrus.Entry
-	startedTasks  map[string]struct{}
-
+	invocationID   string
+	executor       *executor.LocalExecutor
+	invocationAPI  *api.Invocation
+	taskAPI        *api.Task
+	scheduler      *scheduler.InvocationScheduler
+	StateStore     *expr.Store // Future: just grab the initial state of the parent, instead of constantly rebuilding it.
+	span           opentracing.Span
+	trace          TraceDecision
+	logger         *logrus.Entry
+	startedTasks   map[string]struct{}
+	config         InvocationConfig
+	firstTaskDone  *sync.Once
+	completedEarly bool
+
+	// prewarmChecked prevents the initial tasks from being prepared again on every evaluation, whereas prewarmed
+	// indicates that they were actually prepared, which is not the case if the invocation had already started.
+	prewarmChecked bool
+	prewarmed      bool
+
+	// softTimeoutExceeded prevents the soft timeout from being reported again before the event has been projected.
+	softTimeoutExceeded bool
+
+	// errorCount and taskErrors count the task errors of the invocation, across the invocation and per task.
+	// lastError is the message of the most recent task error.
 	errorCount int
+	taskErrors map[string]int
+	lastError  string
+	errorsMu   *sync.Mutex
+
+	// noopEvals is the number of consecutive evaluations that did not start or prepare any task.
+	noopEvals int
+
+	// pinnedLogged prevents the evaluation of a pinned invocation from being logged on every evaluation.
+	pinnedLogged bool
+
+	// lockKey is the evaluated concurrency key of the invocation, if the workflow has a concurrency policy.
+	lockKey *string
+
+	// runningTasks contains the hashes of the inputs of the task runs that are running, to deduplicate submissions.
+	runningTasks map[string]string
+	dedupMu      *sync.Mutex
+
+	// stopped is closed once the invocation has finished, which stops the polling of its sensors.
+	stopped  chan struct{}
+	stopOnce *sync.Once
+
+	// pendingPolls contains the times at which the next polls of the sensors are due, and activePolls the number of
+	// polls in progress, which are needed to hand off the invocation to another controller instance.
+	pendingPolls map[string]time.Time
+	activePolls  int
+	pollsMu      *sync.Mutex
+
+	// cancelPropagated prevents the cancellation of the invocation from being propagated again on every evaluation.
+	cancelPropagated bool
+
+	// retryDue contains the times at which the retries of the failed tasks are due, for which an evaluation of the
+	// invocation has been scheduled.
+	retryDue map[string]time.Time
+
+	// skewObserved prevents the clock skew of the invocation from being observed on every evaluation.
+	skewObserved bool
+
+	// finalized is set (atomically) once the terminal event of the invocation has been appended by this controller.
+	finalized int32
 }
 
 func NewInvocationController(invocationID string, executor *executor.LocalExecutor, invocationAPI *api.Invocation,
 	taskAPI *api.Task, scheduler *scheduler.InvocationScheduler, stateStore *expr.Store,
-	span opentracing.Span, logger *logrus.Entry) *InvocationController {
-
+	span opentracing.Span, trace TraceDecision, logger *logrus.Entry, config InvocationConfig) *InvocationController {
+	if config.Clock == nil {
+		config.Clock = clock.RealClock{}
+	}
 	return &InvocationController{
 		invocationID:  invocationID,
 		executor:      executor,
@@ -55,8 +393,20 @@ func NewInvocationController(invocationID string, executor *executor.LocalExecut
 		scheduler:     scheduler,
 		StateStore:    stateStore,
 		span:          span,
+		trace:         trace,
 		logger:        logger,
 		startedTasks:  map[string]struct{}{},
+		config:        config,
+		firstTaskDone: &sync.Once{},
+		taskErrors:    map[string]int{},
+		errorsMu:      &sync.Mutex{},
+		runningTasks:  map[string]string{},
+		dedupMu:       &sync.Mutex{},
+		stopped:       make(chan struct{}),
+		stopOnce:      &sync.Once{},
+		pendingPolls:  map[string]time.Time{},
+		pollsMu:       &sync.Mutex{},
+		retryDue:      map[string]time.Time{},
 	}
 }
 
@@ -73,19 +423,67 @@ func (c *InvocationController) Eval(ctx context.Context, processValue *ctrl.Even
 		return ctrl.Err{Err: fmt.Errorf("invocation ID expected %v, but was %v", c.invocationID, invocation.ID())}
 	}
 
+	// Leave the invocations to the controller instance that they are handed off to.
+	if !c.config.handoff.acquire(invocation.ID()) {
+		return ctrl.Success{Msg: "invocation is handed off to another controller instance"}
+	}
+	defer c.config.handoff.release()
+
+	// Leave the invocations that are pinned to another replica to that replica, apart from their deadline and
+	// cancellation.
+	if c.config.Pinning.Skip(invocation) {
+		return c.evalPinnedElsewhere(invocation)
+	}
+	if replica, ok := c.config.Pinning.Replica(invocation); ok && !c.pinnedLogged {
+		c.logger.Warnf("DEBUG: evaluating invocation %s, which is pinned to this replica (%s)", invocation.ID(),
+			replica)
+		c.pinnedLogged = true
+	}
+
 	// Ensure that the workflow is present in the invocation
 	if invocation.Workflow() == nil {
 		err := errors.New("workflow is not present in the invocation")
-		c.executor.Submit(&executor.Task{
-			TaskID:  invocation.ID() + ".fail",
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.invocationAPI.Fail(invocation.ID(), err)
-			},
-		})
+		c.fail(invocation.ID(), err)
 		return ctrl.Err{Err: err}
 	}
 
+	// Check if the success condition or the completion policy of the workflow has been met, which completes the
+	// invocation early.
+	if !c.completedEarly && !invocation.GetStatus().Finished() {
+		if cond := invocation.Workflow().GetSpec().GetSuccessCondition(); len(cond) > 0 {
+			met, err := c.evalSuccessCondition(invocation, cond)
+			if err != nil {
+				err := fmt.Errorf("failed to evaluate success condition: %v", err)
+				c.fail(invocation.ID(), err)
+				return ctrl.Err{Err: err}
+			}
+			if met {
+				c.completeEarly(invocation, api.Completion{Mode: types.CompletionModeSuccessCondition})
+				return ctrl.Success{Msg: "success condition of the invocation has been met"}
+			}
+		}
+		if completedBy, met := evalCompletionPolicy(invocation); met {
+			c.completeEarly(invocation, api.Completion{
+				Mode:        invocation.Workflow().GetSpec().GetCompletion().GetMode(),
+				CompletedBy: completedBy,
+			})
+			return ctrl.Success{Msg: "completion policy of the invocation has been met"}
+		}
+	}
+
+	// Propagate the cancellation of the invocation to its sub-workflow invocations. This precedes the checks for
+	// running tasks, as the tasks that await the sub-workflow invocations only finish once those have.
+	if invocation.GetStatus().GetStatus() == types.WorkflowInvocationStatus_ABORTED && !c.cancelPropagated {
+		c.cancelPropagated = true
+		c.propagateCancellation(invocation)
+	}
+
+	// Report the clock skew of the node that created the invocation, which affects the enforcement of its timeouts.
+	c.observeClockSkew(invocation, c.now())
+
+	// Warn if the invocation is approaching its deadline; this also applies to invocations with long-running tasks.
+	c.checkSoftTimeout(invocation)
+
 	// Do not evaluate as long as there still tasks to be executed
 	if activeTaskCount := c.executor.GetGroupTasks(invocation.ID()); activeTaskCount > 0 {
 		return ctrl.Err{Err: fmt.Errorf("invocation still has %d open task(s) to be executed", activeTaskCount)}
@@ -101,76 +499,123 @@ func (c *InvocationController) Eval(ctx context.Context, processValue *ctrl.Even
 
 	// Check if the invocation is not in a terminal state
 	if invocation.GetStatus().Finished() {
+		c.config.Suspensions.SetWaiting(invocation.ID(), nil)
+		c.config.RuntimeHealth.SetWaiting(invocation.ID(), nil)
+		if c.config.finished.first(invocation.ID()) {
+			c.config.WorkflowMetrics.ObserveFinished(invocation)
+			c.config.Quarantines.Observe(invocation)
+			if duration, ok := invocationDuration(invocation); ok {
+				exemplar.Observe(metricInvocationDuration, duration.Seconds(), c.span)
+			}
+		}
+		c.config.loopBudget.Forget(invocation)
+		c.stopOnce.Do(func() {
+			close(c.stopped)
+		})
+		if next := c.config.Concurrency.Release(invocation.ID()); len(next) > 0 {
+			c.logger.Debugf("Released concurrency key; next in line: %v", next)
+		}
 		return ctrl.Done{Msg: fmt.Sprintf("invocation is in a terminal state (%v)",
 			invocation.GetStatus().GetStatus().String())}
 	}
 
 	// Check if the deadline has not been exceeded
-	deadline, err := ptypes.Timestamp(invocation.GetSpec().GetDeadline())
-	if err != nil {
-		createdAt, err := ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt())
+	if err := c.checkDeadline(invocation); err != nil {
+		c.fail(invocation.ID(), err)
+		return ctrl.Err{Err: err}
+	}
+
+	// Ensure that the invocation holds its concurrency key, if the workflow has a concurrency policy.
+	if policy := invocation.Workflow().GetSpec().GetConcurrency(); policy != nil && c.config.Concurrency != nil {
+		acquired, err := c.acquireConcurrencyKey(invocation, policy)
 		if err != nil {
-			err := errors.New("failed to read deadline and createdAt")
-			c.executor.Submit(&executor.Task{
-				TaskID:  invocation.ID() + ".fail",
-				GroupID: invocation.ID(),
-				Apply: func() error {
-					return c.invocationAPI.Fail(invocation.ID(), err)
-				},
-			})
+			c.fail(invocation.ID(), err)
 			return ctrl.Err{Err: err}
 		}
-		deadline = createdAt.Add(DefaultMaxRuntime)
+		if !acquired {
+			return ctrl.Success{Msg: "waiting for the concurrency key held by another invocation"}
+		}
 	}
-	if time.Now().After(deadline) {
-		err := errors.New("deadline exceeded")
-		c.executor.Submit(&executor.Task{
-			TaskID:  invocation.ID() + ".fail",
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.invocationAPI.Fail(invocation.ID(), err)
-			},
-		})
+
+	// Check if we did not exceed the error budget
+	if err := c.checkErrorBudget(); err != nil {
+		c.fail(invocation.ID(), err)
 		return ctrl.Err{Err: err}
 	}
 
-	// Check if we did not exceed the error count
-	if c.errorCount > 0 {
-		err := errors.New("error count exceeded")
-		c.executor.Submit(&executor.Task{
-			TaskID:  invocation.ID() + ".fail",
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.invocationAPI.Fail(invocation.ID(), err)
-			},
-		})
+	// Check if the invocation did not exceed its memory budget
+	if budget := c.config.MemoryBudget; budget > 0 && invocation.GetStatus().GetPayloadSize() > budget {
+		err := fmt.Errorf("%v: retaining %d bytes of task outputs (budget: %d bytes)", ErrMemoryBudgetExceeded,
+			invocation.GetStatus().GetPayloadSize(), budget)
+		c.fail(invocation.ID(), err)
+		return ctrl.Err{Err: err}
+	}
+
+	// Check if the invocation did not exceed its loop iteration budget
+	if err := c.config.loopBudget.Observe(invocation); err != nil {
+		c.fail(invocation.ID(), err)
 		return ctrl.Err{Err: err}
 	}
 
+	// Retry the failed tasks, if allowed by their retry policy and the retry budget of the invocation.
+	retried, backingOff, err := c.retryFailedTasks(invocation)
+	if err != nil {
+		c.fail(invocation.ID(), err)
+		return ctrl.Err{Err: err}
+	}
+	if backingOff > 0 {
+		return ctrl.Success{Msg: fmt.Sprintf("retrying %d failed task(s); %d backing off", retried, backingOff)}
+	}
+	if retried > 0 {
+		return ctrl.Success{Msg: fmt.Sprintf("retrying %d failed task(s)", retried)}
+	}
+
+	// Select the branches of the switches that can be decided, and skip the tasks of the branches that were not
+	// selected.
+	resolved, err := c.resolveSwitches(invocation)
+	if err != nil {
+		c.fail(invocation.ID(), err)
+		return ctrl.Err{Err: err}
+	}
+	if resolved > 0 {
+		return ctrl.Success{Msg: fmt.Sprintf("resolved %d switch action(s)", resolved)}
+	}
+
 	// Check if all tasks have finished
 	if allTasksFinished(invocation) {
 		output, outputHeaders, err := determineTaskOutput(invocation)
 		if err != nil {
-			c.executor.Submit(&executor.Task{
-				TaskID:  invocation.ID() + ".fail",
-				GroupID: invocation.ID(),
-				Apply: func() error {
-					return c.invocationAPI.Fail(invocation.ID(), err)
-				},
-			})
+			c.fail(invocation.ID(), err)
 			return ctrl.Err{Err: err}
 		} else {
-			c.executor.Submit(&executor.Task{
-				TaskID:  invocation.ID() + ".success",
-				GroupID: invocation.ID(),
-				Apply: func() error {
-					return c.invocationAPI.Complete(invocation.ID(), output, outputHeaders)
-				},
+			c.finalize(invocation.ID(), "success", func() error {
+				return c.invocationAPI.Complete(invocation.ID(), output, outputHeaders)
 			})
 			return ctrl.Success{Msg: "all tasks of the invocation have completed"}
 		}
 	}
 
+	// Reconcile the tasks that are in progress, but are not tracked by this controller. These tasks were started by a
+	// previous instance of the controller, which crashed or was restarted during their execution.
+	if recovered := c.recoverInFlightTasks(invocation); recovered > 0 {
+		return ctrl.Success{Msg: fmt.Sprintf("recovered %d in-flight task(s)", recovered)}
+	}
+
+	// Defer the scheduling of low-priority invocations while the controller is overloaded.
+	if !c.config.loadGate.Admit(invocation) {
+		return ctrl.Success{Msg: "deferred scheduling of low-priority invocation due to controller load"}
+	}
+
+	// Defer the scheduling of sub-invocations while their parent scope cannot be read from the state store in time.
+	if !c.config.stateStore.Admit(invocation) {
+		return ctrl.Success{Msg: "deferred scheduling of sub-invocation due to a degraded state store"}
+	}
+
+	// If requested by the workflow, prepare the initial tasks before they are scheduled.
+	if invocation.Workflow().GetSpec().GetPrewarm() && !c.prewarmChecked {
+		c.prewarm(invocation)
+	}
+
 	// Defer the heuristic part of the evaluation to the scheduler.
 	schedule, err := c.scheduler.Evaluate(invocation)
 	if err != nil {
@@ -180,13 +625,7 @@ func (c *InvocationController) Eval(ctx context.Context, processValue *ctrl.Even
 	// If the scheduler indicates to fail, fail the invocation immediately.
 	if abortAction := schedule.GetAbort(); abortAction != nil {
 		err := errors.New(abortAction.Reason)
-		c.executor.Submit(&executor.Task{
-			TaskID:  invocation.ID() + ".fail",
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.invocationAPI.Fail(invocation.ID(), err)
-			},
-		})
+		c.fail(invocation.ID(), err)
 		return ctrl.Err{Err: err}
 	}
 
@@ -200,29 +639,548 @@ func (c *InvocationController) Eval(ctx context.Context, processValue *ctrl.Even
 				if !ok || task == nil {
 					return fmt.Errorf("no task in workflow with ID: %s", action.TaskID)
 				}
-				taskRunSpec := types.NewTaskInvocationSpec(invocation, task, time.Now())
+				taskRunSpec := newTaskRunSpec(invocation, task, c.now())
 				return c.taskAPI.Prepare(taskRunSpec, action.GetExpectedAtTime())
 			},
 		})
 	}
 
-	// Execute the tasks listed in the schedule.
-	for _, action := range schedule.GetRunTasks() {
-		taskID := action.TaskID
-		if c.executor.Submit(&executor.Task{
-			TaskID:  fmt.Sprintf("%s.run.%s", invocation.ID(), taskID),
+	// Execute the tasks listed in the schedule.
+	var started int
+	waiting := map[string]string{}
+	unhealthy := map[string]string{}
+	for _, action := range schedule.GetRunTasks() {
+		taskID := action.TaskID
+		// Tasks of suspended functions wait until the function is resumed, or until the deadline is exceeded.
+		if task, ok := invocation.Task(taskID); ok {
+			if fnRef, suspended := c.config.Suspensions.SuspendedFnRef(task); suspended {
+				c.logger.Debugf("Deferring execution of task %s: function %s is suspended", taskID, fnRef)
+				waiting[taskID] = fnRef
+				continue
+			}
+			// Tasks of unhealthy runtimes wait until the runtime recovers, rather than failing.
+			if runtime, ok := c.config.RuntimeHealth.UnhealthyRuntime(task); ok {
+				c.logger.Debugf("Deferring execution of task %s: runtime %s is unhealthy", taskID, runtime)
+				unhealthy[taskID] = runtime
+				continue
+			}
+		}
+		// Tasks that cannot be submitted yet are left to be scheduled in a subsequent evaluation.
+		if err := c.submitTask(invocation, taskID); err != nil {
+			c.logger.Debugf("Deferring execution of task %s: %v", taskID, err)
+			if deferral, ok := err.(*taskDeferral); ok && deferral.InFlightLimit {
+				break
+			}
+			continue
+		}
+		c.startedTasks[taskID] = struct{}{}
+		started++
+	}
+	c.config.Suspensions.SetWaiting(invocation.ID(), waiting)
+	c.config.RuntimeHealth.SetWaiting(invocation.ID(), unhealthy)
+	// Throttled tasks are waiting for their rate limit, rather than for progress of the invocation.
+	throttled := c.recordThrottledTasks(invocation, schedule)
+	c.observeEvaluation(invocation, started > 0 || len(schedule.GetPrepareTasks()) > 0 || throttled > 0)
+
+	return ctrl.Success{
+		Msg: fmt.Sprintf("scheduled execution of %d tasks and preparation of %d tasks",
+			len(schedule.GetRunTasks()), len(schedule.GetPrepareTasks())),
+	}
+}
+
+// checkDeadline returns an error if the deadline of the invocation has been exceeded, or cannot be determined.
+func (c *InvocationController) checkDeadline(invocation *types.WorkflowInvocation) error {
+	deadline, err := invocationDeadline(invocation)
+	if err != nil {
+		return errors.New("failed to read deadline and createdAt")
+	}
+	if !deadline.IsZero() && c.deadlineNow().After(deadline) {
+		return &types.Error{Code: types.ErrorCodeTimeout, Message: "deadline exceeded"}
+	}
+	return nil
+}
+
+// taskOf returns the task of the invocation, or nil if the invocation has no task with the ID.
+func (c *InvocationController) taskOf(invocation *types.WorkflowInvocation, taskID string) *types.Task {
+	task, _ := invocation.Task(taskID)
+	return task
+}
+
+// taskDeferral is the error returned by submitTask if the task cannot be submitted yet.
+type taskDeferral struct {
+	Reason string
+	// InFlightLimit indicates that the in-flight task limit has been reached, which applies to all tasks alike, so
+	// that none of the other tasks of the invocation can be submitted either.
+	InFlightLimit bool
+}
+
+func (d *taskDeferral) Error() string {
+	return d.Reason
+}
+
+// submitTask submits the execution of the task to the executor, once it fits within the limits of running tasks: the
+// maximum parallelism of the workflow, the concurrency limit of the environment of the task and the in-flight task
+// limit. The limits are held until the execution has finished. If a limit has been reached, or the executor does not
+// accept the task, a taskDeferral is returned.
+func (c *InvocationController) submitTask(invocation *types.WorkflowInvocation, taskID string) error {
+	// Tasks of which the workflow is at its maximum parallelism wait for another task of the workflow to finish.
+	wf, ok := c.config.parallelism.TryAcquire(invocation)
+	if !ok {
+		return &taskDeferral{Reason: fmt.Sprintf("maximum parallelism of workflow %s reached", wf)}
+	}
+	// Tasks of which the environment is at its concurrency limit wait for another task of the environment to finish.
+	env, ok := c.config.EnvLimits.TryAcquire(c.taskOf(invocation, taskID))
+	if !ok {
+		c.config.parallelism.Release(wf)
+		return &taskDeferral{Reason: fmt.Sprintf("concurrency limit of environment %s reached", env)}
+	}
+	if !c.config.Admission.TryAcquire() {
+		c.config.EnvLimits.Release(env)
+		c.config.parallelism.Release(wf)
+		return &taskDeferral{Reason: "in-flight task limit reached", InFlightLimit: true}
+	}
+	if !c.executor.Submit(&executor.Task{
+		TaskID:  fmt.Sprintf("%s.run.%s", invocation.ID(), taskID),
+		GroupID: invocation.ID(),
+		Apply: func() error {
+			defer c.config.Admission.Release()
+			defer c.config.EnvLimits.Release(env)
+			defer c.config.parallelism.Release(wf)
+			return c.execTask(invocation, taskID)
+		},
+	}) {
+		c.config.Admission.Release()
+		c.config.EnvLimits.Release(env)
+		c.config.parallelism.Release(wf)
+		return &taskDeferral{Reason: "executor did not accept the task"}
+	}
+	return nil
+}
+
+// recoverInFlightTasks reconciles the task runs of the invocation that are in progress, but that were not started by
+// this controller. As the status of the task runs is derived from the event store, a task of which the result was
+// recorded before the crash is no longer in progress. For the remaining tasks it is unknown whether, and to what
+// extent, they have been executed. Idempotent and pure tasks are therefore submitted again, whereas other tasks are
+// failed conservatively to avoid duplicate side effects. It returns the number of recovered tasks.
+func (c *InvocationController) recoverInFlightTasks(invocation *types.WorkflowInvocation) int {
+	var recovered int
+	for taskID, taskRun := range invocation.TaskInvocations() {
+		if taskRun.GetStatus().GetStatus() != types.TaskInvocationStatus_IN_PROGRESS {
+			continue
+		}
+		if _, ok := c.startedTasks[taskID]; ok {
+			continue
+		}
+		task, ok := invocation.Task(taskID)
+		if !ok {
+			continue
+		}
+		taskID := taskID
+
+		// Sensors only poll their readiness check, so they continue polling regardless of their idempotency. Pure
+		// tasks do not have side effects, so they are as safe to execute again as idempotent tasks.
+		if task.GetSpec().GetIdempotent() || task.GetSpec().GetPure() ||
+			builtin.IsSensor(taskRun.GetSpec().GetFnRef()) {
+			// Tasks that cannot be submitted yet are recovered in a subsequent evaluation.
+			if err := c.submitTask(invocation, taskID); err != nil {
+				c.logger.Debugf("Deferring recovery of task %s: %v", taskID, err)
+				continue
+			}
+			c.logger.Infof("Resubmitting idempotent task %s, which was in progress before the controller recovered",
+				taskID)
+			metricRecoveredTasks.WithLabelValues("resubmitted").Inc()
+		} else {
+			if !c.executor.Submit(&executor.Task{
+				TaskID:  fmt.Sprintf("%s.recover.%s", invocation.ID(), taskID),
+				GroupID: invocation.ID(),
+				Apply: func() error {
+					return c.taskAPI.Fail(invocation.ID(), taskID, "task was in progress before the controller "+
+						"recovered; it is not idempotent, so it cannot be determined whether it has been executed")
+				},
+			}) {
+				continue
+			}
+			c.logger.Warnf("Failing non-idempotent task %s, which was in progress before the controller recovered",
+				taskID)
+			metricRecoveredTasks.WithLabelValues("failed").Inc()
+		}
+		c.startedTasks[taskID] = struct{}{}
+		recovered++
+	}
+	return recovered
+}
+
+// retryFailedTasks resubmits the failed tasks of the invocation that have attempts left according to their retry
+// policy, once the delay of the backoff of the policy has passed. Each retry is deducted from the retry budget of the
+// invocation. If not all failed tasks can be retried, none of them are retried, as the invocation fails regardless; in
+// case this is due to the retry budget, an error is returned. It returns the number of retried tasks, and the number
+// of tasks of which the retry is not due yet.
+//
+// A task that backs off is not submitted to the executor until its retry is due, so that it does not hold the limits
+// of running tasks, and the invocation can still be timed out or canceled in the meantime. Instead, an evaluation of
+// the invocation is scheduled for when the retry is due.
+func (c *InvocationController) retryFailedTasks(invocation *types.WorkflowInvocation) (retried int, backingOff int,
+	err error) {
+	now := c.now()
+	var failed []string
+	for taskID, taskRun := range invocation.TaskInvocations() {
+		if taskRun.GetStatus().GetStatus() != types.TaskInvocationStatus_FAILED {
+			continue
+		}
+		task, ok := invocation.Task(taskID)
+		code := taskRun.GetStatus().GetError().GetCode()
+		if !ok || code == types.ErrorCodeAborted || code == types.ErrorCodeMalformedOutput ||
+			taskRun.GetSpec().AttemptNumber() >= task.GetSpec().MaxAttempts() {
+			return 0, 0, nil
+		}
+		// The task is not retried if the retry would only start after the total timeout of its retry policy.
+		if err := checkRetryDeadline(taskRun, c.deadlineNow().Add(retryDelay(invocation, taskID, now))); err != nil {
+			metricRetryTimeExceeded.Inc()
+			return 0, 0, err
+		}
+		failed = append(failed, taskID)
+	}
+	if len(failed) == 0 {
+		return 0, 0, nil
+	}
+	sort.Strings(failed)
+
+	if budget := invocation.GetStatus().GetRetryBudget(); budget != nil && int(budget.GetRemaining()) < len(failed) {
+		metricRetryBudgetExhausted.Inc()
+		taskErr := invocation.GetStatus().GetTasks()[failed[0]].GetStatus().GetError()
+		return 0, 0, fmt.Errorf("%v: cannot retry task(s) %s after %d retries (limit: %d): %s",
+			ErrRetryBudgetExhausted, strings.Join(failed, ", "), invocation.GetStatus().GetRetries(),
+			budget.GetLimit(), taskErr.GetMessage())
+	}
+
+	for _, taskID := range failed {
+		taskID := taskID
+		if delay := retryDelay(invocation, taskID, now); delay > 0 {
+			c.scheduleRetry(invocation, taskID, now.Add(delay))
+			backingOff++
+			continue
+		}
+		// Tasks that cannot be submitted yet are retried in a subsequent evaluation.
+		if err := c.submitTask(invocation, taskID); err != nil {
+			c.logger.Debugf("Deferring retry of task %s: %v", taskID, err)
+			if deferral, ok := err.(*taskDeferral); ok && deferral.InFlightLimit {
+				break
+			}
+			continue
+		}
+		c.logger.Infof("Retrying failed task %s (attempt %d)", taskID, taskAttempt(invocation, taskID))
+		c.config.WorkflowMetrics.ObserveRetry(invocation, taskID)
+		delete(c.retryDue, taskID)
+		c.startedTasks[taskID] = struct{}{}
+		retried++
+	}
+	return retried, backingOff, nil
+}
+
+// scheduleRetry schedules an evaluation of the invocation for when the retry of the failed task is due, unless one
+// has been scheduled for it already.
+func (c *InvocationController) scheduleRetry(invocation *types.WorkflowInvocation, taskID string, due time.Time) {
+	if scheduled, ok := c.retryDue[taskID]; ok && scheduled.Equal(due) {
+		return
+	}
+	c.retryDue[taskID] = due
+	delay := due.Sub(c.now())
+	c.logger.Infof("Retrying failed task %s (attempt %d) in %v", taskID, taskAttempt(invocation, taskID), delay)
+	if c.config.reevaluateAfter != nil {
+		c.config.reevaluateAfter(invocation.ID(), delay)
+	}
+}
+
+// retryDelay returns the time until the failed task is due to be retried according to the backoff of its retry
+// policy. The delay is measured from the failure of the task, so that it is not restarted when the invocation is
+// recovered by another controller.
+func retryDelay(invocation *types.WorkflowInvocation, taskID string, now time.Time) time.Duration {
+	taskRun, ok := invocation.TaskInvocation(taskID)
+	if !ok {
+		return 0
+	}
+	task, _ := invocation.Task(taskID)
+	delay := types.RetryDelay(task, taskRun.GetSpec().AttemptNumber())
+	if delay <= 0 {
+		return 0
+	}
+	failedAt, err := ptypes.Timestamp(taskRun.GetStatus().GetUpdatedAt())
+	if err != nil {
+		return delay
+	}
+	return failedAt.Add(delay).Sub(now)
+}
+
+// resolveSwitches selects the branch of each switch of which the required tasks have succeeded, by matching the value
+// of its expression against the names of the branches. Once a switch has selected a branch, the tasks of the other
+// branches are skipped. It returns the number of submitted actions.
+func (c *InvocationController) resolveSwitches(invocation *types.WorkflowInvocation) (int, error) {
+	switches := invocation.Workflow().GetSpec().GetSwitches()
+	switchIDs := make([]string, 0, len(switches))
+	for switchID := range switches {
+		switchIDs = append(switchIDs, switchID)
+	}
+	sort.Strings(switchIDs)
+
+	var actions int
+	for _, switchID := range switchIDs {
+		switchID, sw := switchID, switches[switchID]
+		selected, decided := invocation.GetStatus().GetBranches()[switchID]
+		if !decided {
+			if !requiredTasksSucceeded(invocation, sw.GetRequires()) {
+				continue
+			}
+			result, err := evalInvocationExpr(invocation, sw.GetExpression())
+			if err != nil {
+				return 0, fmt.Errorf("failed to evaluate switch '%s': %v", switchID, err)
+			}
+			value := fmt.Sprintf("%v", typedvalues.MustUnwrap(result))
+			branch, ok := sw.Select(value)
+			if !ok {
+				return 0, fmt.Errorf("switch '%s' has no branch for value '%s' and no default branch", switchID, value)
+			}
+			c.executor.Submit(&executor.Task{
+				TaskID:  fmt.Sprintf("%s.switch.%s", invocation.ID(), switchID),
+				GroupID: invocation.ID(),
+				Apply: func() error {
+					return c.invocationAPI.SelectBranch(invocation.ID(), switchID, branch)
+				},
+			})
+			c.logger.Infof("Switch %s selected branch %s (value: %s)", switchID, branch, value)
+			actions++
+			continue
+		}
+
+		for name, branch := range sw.AllBranches() {
+			if name == selected {
+				continue
+			}
+			for _, taskID := range branch.GetTasks() {
+				taskID := taskID
+				if _, ok := invocation.TaskInvocation(taskID); ok {
+					continue
+				}
+				c.executor.Submit(&executor.Task{
+					TaskID:  fmt.Sprintf("%s.skip.%s", invocation.ID(), taskID),
+					GroupID: invocation.ID(),
+					Apply: func() error {
+						return c.taskAPI.Skip(invocation.ID(), taskID)
+					},
+				})
+				actions++
+			}
+		}
+	}
+	return actions, nil
+}
+
+// requiredTasksSucceeded returns whether all of the tasks have succeeded, or have been skipped.
+func requiredTasksSucceeded(invocation *types.WorkflowInvocation, taskIDs []string) bool {
+	for _, taskID := range taskIDs {
+		taskRun, ok := invocation.TaskInvocation(taskID)
+		if !ok {
+			return false
+		}
+		switch taskRun.GetStatus().GetStatus() {
+		case types.TaskInvocationStatus_SUCCEEDED, types.TaskInvocationStatus_SKIPPED:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkRetryDeadline returns an error if the failed task run can no longer be retried, because the total time that the
+// task has spent across its attempts exceeds the total timeout of its retry policy.
+func checkRetryDeadline(taskRun *types.TaskInvocation, now time.Time) error {
+	retryDeadline, err := ptypes.Timestamp(taskRun.GetStatus().GetRetryDeadline())
+	if err != nil || now.Before(retryDeadline) {
+		return nil
+	}
+	var elapsed time.Duration
+	if firstAttemptAt, err := ptypes.Timestamp(taskRun.GetSpec().GetFirstAttemptAt()); err == nil {
+		elapsed = now.Sub(firstAttemptAt)
+	}
+	return fmt.Errorf("%v: cannot retry task %s after %d attempt(s) taking %v (limit: %v): %s",
+		ErrRetryTimeExceeded, taskRun.ID(), taskRun.GetSpec().AttemptNumber(), elapsed.Round(time.Millisecond),
+		totalTimeout(taskRun), taskRun.GetStatus().GetError().GetMessage())
+}
+
+// totalTimeout returns the total timeout of the retry policy of the task of the task run.
+func totalTimeout(taskRun *types.TaskInvocation) time.Duration {
+	d, _ := ptypes.Duration(taskRun.GetSpec().GetTask().GetSpec().GetRetry().GetTotalTimeout())
+	return d
+}
+
+// taskFirstAttemptAt returns the start of the first attempt of the task, given that its next attempt is started at
+// now. For the first attempt this is now, whereas retries keep the start of the first attempt.
+func taskFirstAttemptAt(invocation *types.WorkflowInvocation, taskID string, now time.Time) *timestamp.Timestamp {
+	taskRun, ok := invocation.TaskInvocation(taskID)
+	if ok {
+		switch taskRun.GetStatus().GetStatus() {
+		case types.TaskInvocationStatus_FAILED, types.TaskInvocationStatus_IN_PROGRESS:
+			if ts := taskRun.GetSpec().GetFirstAttemptAt(); ts != nil {
+				return ts
+			}
+			return taskRun.GetMetadata().GetCreatedAt()
+		}
+	}
+	return util.MustTimestampProto(now)
+}
+
+// taskAttempt determines the attempt of the next execution of the task. A failed task is executed with the next
+// attempt, whereas a task that is still in progress, because it is being recovered, is executed with the same attempt.
+func taskAttempt(invocation *types.WorkflowInvocation, taskID string) int32 {
+	taskRun, ok := invocation.TaskInvocation(taskID)
+	if !ok {
+		return 1
+	}
+	switch taskRun.GetStatus().GetStatus() {
+	case types.TaskInvocationStatus_FAILED:
+		return taskRun.GetSpec().AttemptNumber() + 1
+	case types.TaskInvocationStatus_IN_PROGRESS:
+		return taskRun.GetSpec().AttemptNumber()
+	default:
+		return 1
+	}
+}
+
+// evalSuccessCondition evaluates the success condition expression of the workflow within the scope of the
+// invocation. In contrast to the input expressions, the task statuses in the scope reflect the task invocations.
+func (c *InvocationController) evalSuccessCondition(invocation *types.WorkflowInvocation, cond string) (bool, error) {
+	result, err := evalInvocationExpr(invocation, cond)
+	if err != nil {
+		return false, err
+	}
+	met, ok := typedvalues.MustUnwrap(result).(bool)
+	if !ok {
+		return false, fmt.Errorf("expected boolean, but was %v", result.ValueType())
+	}
+	return met, nil
+}
+
+// evalInvocationExpr resolves the expression in the scope of the invocation, in which the tasks include their current
+// status.
+func evalInvocationExpr(invocation *types.WorkflowInvocation, expression string) (*typedvalues.TypedValue, error) {
+	scope, err := expr.NewScope(nil, invocation)
+	if err != nil {
+		return nil, err
+	}
+	for taskID, task := range scope.Tasks {
+		if ti, ok := invocation.TaskInvocation(taskID); ok {
+			task.Status = ti.GetStatus().GetStatus().String()
+		}
+	}
+	return expr.Resolve(scope, "", typedvalues.MustWrap(expression))
+}
+
+// evalCompletionPolicy checks whether the completion policy of the workflow has been met, returning the succeeded
+// tasks that triggered the completion. Without a task set, the all mode is left to the regular completion check.
+func evalCompletionPolicy(invocation *types.WorkflowInvocation) (completedBy []string, met bool) {
+	policy := invocation.Workflow().GetSpec().GetCompletion()
+	if policy == nil {
+		return nil, false
+	}
+	mode := policy.GetMode()
+	if len(mode) == 0 {
+		mode = types.CompletionModeAll
+	}
+	taskSet := policy.GetTasks()
+	if len(taskSet) == 0 {
+		if mode == types.CompletionModeAll {
+			return nil, false
+		}
+		for taskID := range invocation.Tasks() {
+			taskSet = append(taskSet, taskID)
+		}
+	}
+
+	var succeeded []string
+	for _, taskID := range taskSet {
+		ti, ok := invocation.TaskInvocation(taskID)
+		if ok && ti.GetStatus().GetStatus() == types.TaskInvocationStatus_SUCCEEDED {
+			succeeded = append(succeeded, taskID)
+		}
+	}
+	sort.Strings(succeeded)
+
+	switch mode {
+	case types.CompletionModeAll:
+		met = len(succeeded) == len(taskSet)
+	case types.CompletionModeAny:
+		met = len(succeeded) > 0
+	case types.CompletionModeQuorum:
+		met = len(succeeded) >= int(policy.GetQuorum())
+	}
+	if !met {
+		return nil, false
+	}
+	return succeeded, true
+}
+
+// completeEarly completes the invocation before all tasks have finished, recording the unfinished tasks as abandoned.
+// If requested by the workflow, the abandoned tasks that are in progress are failed. This only affects the status of
+// the task runs; the calls of their functions that are in progress are not canceled, and keep running until they
+// return.
+func (c *InvocationController) completeEarly(invocation *types.WorkflowInvocation, completion api.Completion) {
+	c.completedEarly = true
+	for taskID := range invocation.Tasks() {
+		if ti, ok := invocation.TaskInvocation(taskID); !ok || !ti.GetStatus().Finished() {
+			completion.AbandonedTasks = append(completion.AbandonedTasks, taskID)
+		}
+	}
+	sort.Strings(completion.AbandonedTasks)
+	abandoned := completion.AbandonedTasks
+	c.logger.Infof("Completion mode '%s' met (completed by: %v); completing invocation early, abandoning tasks: %v",
+		completion.Mode, completion.CompletedBy, abandoned)
+
+	var output, outputHeaders *typedvalues.TypedValue
+	if outputTask := invocation.Workflow().GetSpec().GetOutputTask(); len(outputTask) != 0 {
+		output = controlflow.ResolveTaskOutput(outputTask, invocation)
+		outputHeaders = controlflow.ResolveTaskOutputHeaders(outputTask, invocation)
+	}
+	cancelAbandoned := invocation.Workflow().GetSpec().GetCancelAbandonedTasks()
+	c.finalize(invocation.ID(), "success", func() error {
+		if cancelAbandoned {
+			for _, taskID := range abandoned {
+				if _, ok := invocation.TaskInvocation(taskID); !ok {
+					// The task was never started
+					continue
+				}
+				err := c.taskAPI.Fail(invocation.ID(), taskID, "task was abandoned due to early completion")
+				if err != nil {
+					c.logger.Warnf("Failed to abort abandoned task %s: %v", taskID, err)
+				}
+			}
+		}
+		return c.invocationAPI.CompleteEarly(invocation.ID(), output, outputHeaders, completion)
+	})
+}
+
+// prewarm prepares the functions of the initial tasks of the invocation, which are the tasks without any
+// dependencies. This allows the function runtimes to provision the functions, while the controller evaluates the
+// inputs of the tasks.
+func (c *InvocationController) prewarm(invocation *types.WorkflowInvocation) {
+	c.prewarmChecked = true
+	if len(invocation.TaskInvocations()) > 0 {
+		// The invocation has already started executing tasks.
+		return
+	}
+	c.prewarmed = true
+	now := c.now()
+	for taskID, task := range invocation.Tasks() {
+		if len(task.GetSpec().GetRequires()) > 0 {
+			continue
+		}
+		task := task
+		c.executor.Submit(&executor.Task{
+			TaskID:  fmt.Sprintf("%s.prewarm.%s", invocation.ID(), taskID),
 			GroupID: invocation.ID(),
 			Apply: func() error {
-				return c.execTask(invocation, taskID)
+				taskRunSpec := newTaskRunSpec(invocation, task, now)
+				return c.taskAPI.Prepare(taskRunSpec, now)
 			},
-		}) {
-			c.startedTasks[action.TaskID] = struct{}{}
-		}
-	}
-
-	return ctrl.Success{
-		Msg: fmt.Sprintf("scheduled execution of %d tasks and preparation of %d tasks",
-			len(schedule.GetRunTasks()), len(schedule.GetPrepareTasks())),
+		})
 	}
 }
 
@@ -260,9 +1218,21 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 
 	// Resolve expression inputs
 	var inputs map[string]*typedvalues.TypedValue
-	if len(task.GetSpec().GetInputs()) > 0 {
+	if len(task.GetSpec().GetInputs()) > 0 || hasDependencyTransforms(task.GetSpec()) ||
+		len(task.GetSpec().GetMergeInputs()) > 0 {
 		var err error
-		inputs, err = c.resolveInputs(invocation, task.ID(), task.GetSpec().GetInputs())
+		inputs, err = c.resolveInputs(invocation, task.ID(), task.GetSpec())
+		if err == ErrStateStoreTimeout {
+			// The task is scheduled again once the state store has recovered.
+			log.Warnf("Deferring execution of task %s: %v", taskID, err)
+			span.LogKV("error", err)
+			return nil
+		}
+		if refsErr, ok := err.(*UndefinedReferencesError); ok {
+			span.LogKV("error", refsErr)
+			return c.taskAPI.FailWithError(invocation.ID(), types.NewTaskError(&types.Error{Message: refsErr.Error()},
+				taskID, taskAttempt(invocation, taskID), types.ErrorCodeInput))
+		}
 		if err != nil {
 			log.Error(err)
 			span.LogKV("error", err)
@@ -281,8 +1251,41 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 	}
 
 	// Create the task run
-	taskRunSpec := types.NewTaskInvocationSpec(invocation, task, time.Now())
+	now := c.now()
+	taskRunSpec := newTaskRunSpec(invocation, task, now)
 	taskRunSpec.Inputs = inputs
+	taskRunSpec.Attempt = taskAttempt(invocation, taskID)
+	taskRunSpec.FirstAttemptAt = taskFirstAttemptAt(invocation, taskID, now)
+	// Switch to the failover function after repeated failures of the primary function.
+	if task.GetSpec().FailsOver(taskRunSpec.Attempt) {
+		failoverFnRef := task.GetStatus().GetFailoverFnRef()
+		if failoverFnRef == nil {
+			err := fmt.Errorf("failover function '%s' of task %s has not been resolved",
+				task.GetSpec().GetFailover().GetFunctionRef(), taskID)
+			span.LogKV("error", err)
+			return err
+		}
+		log.Warnf("Failing over task %s to function %s (attempt %d)", taskID, failoverFnRef.Format(),
+			taskRunSpec.Attempt)
+		taskRunSpec.FnRef = failoverFnRef
+		taskRunSpec.Failover = true
+		span.SetTag("failover", true)
+		span.SetTag("fnref", failoverFnRef)
+		metricTaskFailovers.Inc()
+	}
+	// Ensure that the attempt does not exceed the total time that the task can spend across its attempts.
+	if retryDeadline := types.RetryDeadline(task, taskRunSpec.FirstAttemptAt); retryDeadline != nil {
+		deadline, err := ptypes.Timestamp(taskRunSpec.Deadline)
+		if limit, _ := ptypes.Timestamp(retryDeadline); err != nil || limit.Before(deadline) {
+			taskRunSpec.Deadline = retryDeadline
+		}
+	}
+	// Apply the input middleware, before the inputs are hashed, so that the hashes match the inputs of the task run.
+	if err := c.config.InputMiddleware.Apply(invocation, taskRunSpec); err != nil {
+		span.LogKV("error", err)
+		return err
+	}
+	inputs = taskRunSpec.Inputs
 	if log.Level == logrus.DebugLevel {
 		i, err := typedvalues.UnwrapMapTypedValue(taskRunSpec.GetInputs())
 		if err != nil {
@@ -292,25 +1295,84 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 		}
 	}
 
+	// Sensors are polled by the controller until their readiness check passes, rather than invoked once.
+	if builtin.IsSensor(taskRunSpec.GetFnRef()) {
+		span.SetTag("sensor", true)
+		return c.pollSensor(invocation, taskRunSpec)
+	}
+
+	// Skip the run if an identical run of the task is already running.
+	inputsHash, err := typedvalues.HashInputs(inputs)
+	if err != nil {
+		log.Warnf("Failed to hash the inputs of task %s; not deduplicating it: %v", taskID, err)
+		inputsHash = ""
+	} else if c.deduplicateTask(taskID, inputsHash) {
+		log.Infof("Dropping duplicate submission of task %s: a run with identical inputs is in progress", taskID)
+		metricDeduplicatedTasks.Inc()
+		span.SetTag("deduplicated", true)
+		return nil
+	} else {
+		defer c.releaseTask(taskID)
+	}
+
+	// Reuse the output of an identical run of a pure task, rather than invoking the function again.
+	memoized := memoKey(invocation, task, taskRunSpec, inputsHash)
+	if result, ok := c.config.Memo.Get(memoized); ok {
+		log.Infof("Reusing the memoized output of pure task %s", taskID)
+		span.SetTag("memoized", true)
+		if err := c.taskAPI.Reuse(taskRunSpec, result); err != nil {
+			span.LogKV("error", err)
+			return err
+		}
+		return nil
+	}
+
 	// Create the context with the deadline specified in the task run spec.
 	ctx := context.Background()
 	deadline, err := ptypes.Timestamp(taskRunSpec.Deadline)
 	if err == nil {
 		var cancel func()
-		ctx, cancel = context.WithDeadline(ctx, deadline)
+		ctx, cancel = context.WithDeadline(ctx, deadline.Add(c.config.ClockSkewTolerance))
 		defer cancel()
 	}
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
 	// Invoke the task
-	updated, err := c.taskAPI.Invoke(taskRunSpec, api.WithContext(ctx), api.AwaitWorklow(awaitWorkflowMaxRuntime),
+	awaitWorkflowTimeout := c.config.AwaitWorkflowTimeout
+	if awaitWorkflowTimeout <= 0 {
+		awaitWorkflowTimeout = awaitWorkflowMaxRuntime
+	}
+	startedAt := time.Now()
+	updated, err := c.taskAPI.Invoke(taskRunSpec, api.WithContext(ctx), api.AwaitWorklow(awaitWorkflowTimeout),
 		api.PostTransformer(func(ti *types.TaskInvocation) error {
 			return c.transformTaskRunOutputs(invocation, ti)
 		}))
 	if err != nil {
+		c.recordTaskError(taskID, err.Error())
+		c.config.SLOs.Observe(invocation, task, time.Since(startedAt), false)
 		span.LogKV("error", err)
 		return err
 	}
+	c.config.SLOs.Observe(invocation, task, time.Since(startedAt), updated.GetStatus().Successful())
+	exemplar.Observe(metricTaskDuration.WithLabelValues(updated.GetStatus().GetStatus().String()),
+		time.Since(startedAt).Seconds(), span)
+	c.config.WorkflowMetrics.ObserveTask(invocation, taskID, time.Since(startedAt))
+	if updated.GetStatus().Successful() {
+		c.scheduler.ObserveTask(invocation, taskID, time.Since(startedAt))
+		c.config.Memo.Put(memoized, updated.GetStatus())
+	} else {
+		c.recordTaskError(taskID, updated.GetStatus().GetError().GetMessage())
+	}
+
+	// Measure the latency of the first task, to allow comparing prewarmed with cold invocations.
+	c.firstTaskDone.Do(func() {
+		createdAt, err := ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt())
+		if err != nil || len(invocation.TaskInvocations()) > 0 {
+			return
+		}
+		metricFirstTaskDuration.WithLabelValues(strconv.FormatBool(c.prewarmed)).
+			Observe(time.Since(createdAt).Seconds())
+	})
 
 	// Post-execution debugging
 	span.SetTag("status", updated.GetStatus().GetStatus().String())
@@ -329,17 +1391,182 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 	return nil
 }
 
+// acquireConcurrencyKey tries to acquire the concurrency key of the invocation. It returns an error if the key could
+// not be evaluated, or if the key is held by another invocation and the policy rejects conflicting invocations.
+func (c *InvocationController) acquireConcurrencyKey(invocation *types.WorkflowInvocation,
+	policy *types.ConcurrencyPolicy) (bool, error) {
+	if c.lockKey == nil {
+		scope, err := expr.NewScope(nil, invocation)
+		if err != nil {
+			return false, err
+		}
+		result, err := expr.Resolve(scope, "", typedvalues.MustWrap(policy.GetKey()))
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate concurrency key: %v", err)
+		}
+		val, err := typedvalues.Unwrap(result)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate concurrency key: %v", err)
+		}
+		key := fmt.Sprintf("%v", val)
+		if val == nil || len(key) == 0 {
+			return false, errors.New("concurrency key evaluated to an empty value")
+		}
+		c.lockKey = &key
+	}
+
+	reject := policy.GetOnConflict() == types.ConcurrencyConflictReject
+	holder, acquired := c.config.Concurrency.Acquire(invocation.Workflow().ID(), *c.lockKey, invocation.ID(), !reject)
+	if acquired {
+		return true, nil
+	}
+	if reject {
+		return false, fmt.Errorf("concurrency key '%s' is held by invocation %s", *c.lockKey, holder)
+	}
+	c.logger.Debugf("Waiting for concurrency key '%s' held by invocation %s", *c.lockKey, holder)
+	return false, nil
+}
+
+// propagateCancellation cancels the sub-workflow invocations of the canceled invocation, unless their parent tasks
+// detach them.
+func (c *InvocationController) propagateCancellation(invocation *types.WorkflowInvocation) {
+	propagation := c.config.cancelPropagation
+	if propagation == nil {
+		return
+	}
+	c.executor.Submit(&executor.Task{
+		TaskID:  invocation.ID() + ".cancel",
+		GroupID: invocation.ID(),
+		Apply: func() error {
+			decisions := propagation.Propagate(invocation)
+			if len(decisions) > 0 {
+				c.logger.Debugf("Propagated cancellation to %d sub-workflow invocation(s)", len(decisions))
+			}
+			return nil
+		},
+	})
+}
+
+// recordTaskError counts a failed run of the task against the error budget of the invocation.
+func (c *InvocationController) recordTaskError(taskID string, message string) {
+	c.errorsMu.Lock()
+	defer c.errorsMu.Unlock()
+	c.errorCount++
+	c.taskErrors[taskID]++
+	if len(message) > 0 {
+		c.lastError = fmt.Sprintf("%s: %s", taskID, message)
+	}
+}
+
+// checkErrorBudget returns an error if the invocation has exhausted its error budget, either across the invocation or
+// for one of its tasks. The error includes the number of errors, and the message of the last task error.
+func (c *InvocationController) checkErrorBudget() error {
+	budget := c.config.ErrorBudget
+	c.errorsMu.Lock()
+	defer c.errorsMu.Unlock()
+	var err error
+	if budget.MaxErrors > 0 && c.errorCount >= budget.MaxErrors {
+		err = fmt.Errorf("error count exceeded: %d task errors in the invocation (max: %d)", c.errorCount,
+			budget.MaxErrors)
+	}
+	if err == nil && budget.MaxTaskErrors > 0 {
+		taskIDs := make([]string, 0, len(c.taskErrors))
+		for taskID := range c.taskErrors {
+			taskIDs = append(taskIDs, taskID)
+		}
+		sort.Strings(taskIDs)
+		for _, taskID := range taskIDs {
+			if count := c.taskErrors[taskID]; count >= budget.MaxTaskErrors {
+				err = fmt.Errorf("error count exceeded: %d errors of task %s (max: %d)", count, taskID,
+					budget.MaxTaskErrors)
+				break
+			}
+		}
+	}
+	if err != nil && len(c.lastError) > 0 {
+		err = fmt.Errorf("%v; last error: %s", err, c.lastError)
+	}
+	return err
+}
+
+// observeEvaluation tracks the consecutive evaluations of the invocation that did not result in any action. Once the
+// number of these no-op evaluations reaches the threshold, a warning is emitted, as this indicates that the
+// invocation is evaluated over and over without making progress.
+func (c *InvocationController) observeEvaluation(invocation *types.WorkflowInvocation, acted bool) {
+	if acted {
+		c.noopEvals = 0
+		return
+	}
+	metricNoopEvaluations.Inc()
+	c.noopEvals++
+	threshold := c.config.NoopEvalThreshold
+	if threshold <= 0 {
+		threshold = DefaultNoopEvalThreshold
+	}
+	if c.noopEvals == threshold {
+		metricThrashingInvocations.Inc()
+		c.logger.WithField("invocation", invocation.ID()).
+			Warnf("Invocation has been evaluated %d consecutive times without starting or preparing any task", threshold)
+	}
+}
+
+// checkSoftTimeout emits a warning once the invocation has exceeded the soft timeout of its workflow: the percentage
+// of the time between the creation of the invocation and its deadline. The invocation itself is not affected.
+func (c *InvocationController) checkSoftTimeout(invocation *types.WorkflowInvocation) {
+	percentage := invocation.Workflow().GetSpec().GetSoftTimeoutPercentage()
+	if percentage <= 0 || c.softTimeoutExceeded || invocation.GetStatus().GetSoftTimeoutExceeded() ||
+		invocation.GetStatus().Finished() {
+		return
+	}
+	start, err := invocationStart(invocation)
+	if err != nil {
+		return
+	}
+	if invocation.GetSpec().GetDeadline() == nil {
+		return
+	}
+	deadline := start.Add(invocationMaxRuntime(invocation))
+	softDeadline := start.Add(deadline.Sub(start) * time.Duration(percentage) / 100)
+	if c.deadlineNow().Before(softDeadline) {
+		return
+	}
+
+	c.softTimeoutExceeded = true
+	c.logger.Warnf("Invocation exceeded its soft timeout: %d%% of the time until its deadline (%v) has passed",
+		percentage, deadline)
+	metricSoftTimeouts.Inc()
+	c.executor.Submit(&executor.Task{
+		TaskID:  invocation.ID() + ".soft-timeout",
+		GroupID: invocation.ID(),
+		Apply: func() error {
+			return c.invocationAPI.ExceedSoftTimeout(invocation.ID())
+		},
+	})
+}
+
 func (c *InvocationController) resolveInputs(invocation *types.WorkflowInvocation, taskID string,
-	inputs map[string]*typedvalues.TypedValue) (map[string]*typedvalues.TypedValue, error) {
+	spec *types.TaskSpec) (map[string]*typedvalues.TypedValue, error) {
+	inputs := spec.GetInputs()
+	merges := spec.EffectiveMergeInputs()
+
+	// Replace references to config maps with the referenced values
+	if c.config.ConfigMaps != nil {
+		var err error
+		inputs, err = c.config.ConfigMaps.ResolveInputs(inputs)
+		if err != nil {
+			return nil, err
+		}
+		merges, err = resolveMergeConfigMaps(c.config.ConfigMaps, merges)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Inherit scope if invocation has a parent
 	log := c.logger
-	var parentScope *expr.Scope
-	if len(invocation.Spec.ParentId) != 0 {
-		var ok bool
-		parentScope, ok = c.StateStore.Get(invocation.Spec.ParentId)
-		if !ok {
-			log.Warnf("Could not find parent scope (%s) of scope (%s)", invocation.Spec.ParentId, invocation.ID())
-		}
+	parentScope, err := c.parentScope(invocation, true)
+	if err != nil {
+		return nil, err
 	}
 
 	// Setup the scope for the expressions
@@ -349,8 +1576,24 @@ func (c *InvocationController) resolveInputs(invocation *types.WorkflowInvocatio
 	}
 	c.StateStore.Set(invocation.ID(), scope)
 
+	// The trigger of a partial join is specific to the task, so it is not part of the stored scope.
+	scope = expr.WithTrigger(scope, invocation, taskID)
+
+	// Bind the transformed outputs of the dependencies, before the inputs are resolved.
+	resolvedInputs, err := resolveDependencyTransforms(scope, invocation, taskID, spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveInputMerges(scope, taskID, merges, resolvedInputs); err != nil {
+		return nil, err
+	}
+
+	// Check the inputs for references to undefined keys, if the workflow has a scope strictness.
+	if err := c.checkScope(invocation, taskID, scope, inputs, merges); err != nil {
+		return nil, err
+	}
+
 	// Resolve each of the inputs (based on priority)
-	resolvedInputs := map[string]*typedvalues.TypedValue{}
 	for _, input := range typedvalues.Prioritize(inputs) {
 		resolvedInput, err := expr.Resolve(scope, taskID, input.Val)
 		if err != nil {
@@ -372,19 +1615,88 @@ func (c *InvocationController) resolveInputs(invocation *types.WorkflowInvocatio
 	return resolvedInputs, nil
 }
 
+// hasDependencyTransforms returns whether any of the dependencies of the task has a transform.
+func hasDependencyTransforms(spec *types.TaskSpec) bool {
+	for _, dep := range spec.GetRequires() {
+		if dep.GetTransform() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDependencyTransforms evaluates the transforms of the dependencies of the task, and binds the results to the
+// inputs of the task in the scope. Within a transform, the dependency is the current task, so that output() refers to
+// the output of the dependency. Explicit inputs of the task take precedence over the transformed outputs. For a task
+// with a partial join, the dependencies that have not succeeded (yet) are skipped. Failed dependencies are bound to
+// null.
+func resolveDependencyTransforms(scope *expr.Scope, invocation *types.WorkflowInvocation, taskID string,
+	spec *types.TaskSpec) (map[string]*typedvalues.TypedValue, error) {
+	resolvedInputs := map[string]*typedvalues.TypedValue{}
+	for depID, dep := range spec.GetRequires() {
+		if dep.GetTransform() == nil {
+			continue
+		}
+		if spec.IsPartialJoin() {
+			run, ok := invocation.TaskInvocation(depID)
+			if !ok || run.GetStatus().GetStatus() != types.TaskInvocationStatus_SUCCEEDED {
+				continue
+			}
+		}
+		key := dep.InputKey(depID)
+		if _, ok := spec.GetInputs()[key]; ok {
+			continue
+		}
+		// A failed dependency can only be reached with the use-null policy, which binds null instead of transforming
+		// the missing output.
+		if run, ok := invocation.TaskInvocation(depID); ok &&
+			run.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED {
+			resolvedInputs[key] = typedvalues.MustWrap(nil)
+			scope.Tasks[taskID].Inputs[key] = nil
+			continue
+		}
+		resolved, err := expr.Resolve(scope, depID, dep.GetTransform())
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform output of dependency %v: %v", depID, err)
+		}
+		resolvedInputs[key] = resolved
+		scope.Tasks[taskID].Inputs[key] = typedvalues.MustUnwrap(resolved)
+	}
+	return resolvedInputs, nil
+}
+
+// parentScope returns the expression scope of the parent of the invocation, or nil if the invocation has no parent.
+// If bounded, the read is bounded by the state store timeout, and ErrStateStoreTimeout is returned if the state store
+// is too slow to read the scope in time.
+func (c *InvocationController) parentScope(invocation *types.WorkflowInvocation, bounded bool) (*expr.Scope, error) {
+	parentID := invocation.GetSpec().GetParentId()
+	if len(parentID) == 0 {
+		return nil, nil
+	}
+	var parentScope *expr.Scope
+	var ok bool
+	if bounded && c.config.stateStore != nil {
+		var err error
+		parentScope, ok, err = c.config.stateStore.Get(parentID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		parentScope, ok = c.StateStore.Get(parentID)
+	}
+	if !ok {
+		c.logger.Warnf("Could not find parent scope (%s) of scope (%s)", parentID, invocation.ID())
+	}
+	return parentScope, nil
+}
+
 func (c *InvocationController) resolveOutput(invocation *types.WorkflowInvocation, ti *types.TaskInvocation,
 	outputExpr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
-	log := c.logger
-
 	// Inherit scope if invocation has a parent
 	taskID := ti.GetSpec().GetTask().GetMetadata().GetId()
-	var parentScope *expr.Scope
-	if len(invocation.Spec.ParentId) != 0 {
-		var ok bool
-		parentScope, ok = c.StateStore.Get(invocation.Spec.ParentId)
-		if !ok {
-			log.Warnf("Could not find parent scope (%s) of scope (%s)", invocation.Spec.ParentId, invocation.ID())
-		}
+	parentScope, err := c.parentScope(invocation, false)
+	if err != nil {
+		return nil, err
 	}
 
 	// Setup the scope for the expressions
@@ -413,6 +1725,24 @@ func (c *InvocationController) transformTaskRunOutputs(invocation *types.Workflo
 		return nil
 	}
 
+	// Extract the output from the envelope of the function, if an output path has been configured.
+	if outputPath := c.outputPath(task); len(outputPath) > 0 {
+		output, err := unwrapOutput(ti.GetStatus().GetOutput(), outputPath)
+		if err != nil {
+			// Fail the task, rather than storing an output that downstream tasks do not expect.
+			ti.GetStatus().Status = types.TaskInvocationStatus_FAILED
+			ti.GetStatus().Error = &types.Error{
+				Message: fmt.Sprintf("failed to unwrap output of task '%s': %v", task.ID(), err),
+				Code:    types.ErrorCodeOutput,
+				TaskId:  task.ID(),
+			}
+			ti.GetStatus().Output = nil
+			return nil
+		}
+		log.Debugf("unwrapped the task run output at path '%s'", outputPath)
+		ti.GetStatus().Output = output
+	}
+
 	// If there is an output set for the task, replace the actual output of the task runOnce with this.
 	output := task.GetSpec().GetOutput()
 	if output != nil {
@@ -424,7 +1754,12 @@ func (c *InvocationController) transformTaskRunOutputs(invocation *types.Workflo
 			}
 			output = tv
 		}
-		log.Debugf("replaced the task run output (old: %v, new: %v)", ti.GetStatus().Output, output)
+		if len(task.GetSpec().GetRedact()) > 0 {
+			// The outputs have not been redacted yet, so they should not be logged.
+			log.Debug("replaced the task run output")
+		} else {
+			log.Debugf("replaced the task run output (old: %v, new: %v)", ti.GetStatus().Output, output)
+		}
 		ti.GetStatus().Output = output
 	}
 
@@ -447,18 +1782,59 @@ func (c *InvocationController) transformTaskRunOutputs(invocation *types.Workflo
 	return nil
 }
 
+// outputPath returns the output path of the task, which defaults to the output path configured for its function.
+func (c *InvocationController) outputPath(task *types.Task) string {
+	if path := task.GetSpec().GetOutputPath(); len(path) > 0 {
+		return path
+	}
+	if len(c.config.OutputPaths) == 0 {
+		return ""
+	}
+	if path, ok := c.config.OutputPaths[task.GetSpec().GetFunctionRef()]; ok {
+		return path
+	}
+	if fnRef := task.GetStatus().GetFnRef(); fnRef != nil {
+		return c.config.OutputPaths[fnRef.Format()]
+	}
+	return ""
+}
+
+// unwrapOutput extracts the value at the dot-separated path from the output. Elements of lists are referenced by
+// their index, e.g. "data.items.0".
+func unwrapOutput(output *typedvalues.TypedValue, path string) (*typedvalues.TypedValue, error) {
+	val, err := typedvalues.Unwrap(output)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch container := val.(type) {
+		case map[string]interface{}:
+			field, ok := container[key]
+			if !ok {
+				return nil, fmt.Errorf("path '%s' not found in output: missing key '%s'", path, key)
+			}
+			val = field
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(container) {
+				return nil, fmt.Errorf("path '%s' not found in output: invalid index '%s'", path, key)
+			}
+			val = container[i]
+		default:
+			return nil, fmt.Errorf("path '%s' not found in output: cannot index %T with '%s'", path, val, key)
+		}
+	}
+	return typedvalues.Wrap(val)
+}
+
 func (c *InvocationController) resolveOutputHeaders(invocation *types.WorkflowInvocation, ti *types.TaskInvocation,
 	outputHeadersExpr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
 
 	taskID := ti.GetSpec().GetTask().GetMetadata().GetId()
 	// Inherit scope if invocation has a parent
-	var parentScope *expr.Scope
-	if len(invocation.Spec.ParentId) != 0 {
-		var ok bool
-		parentScope, ok = c.StateStore.Get(invocation.Spec.ParentId)
-		if !ok {
-			c.logger.Warnf("Could not find parent scope (%s) of scope (%s)", invocation.Spec.ParentId, invocation.ID())
-		}
+	parentScope, err := c.parentScope(invocation, false)
+	if err != nil {
+		return nil, err
 	}
 
 	// Setup the scope for the expressions
@@ -486,7 +1862,8 @@ func determineTaskOutput(invocation *types.WorkflowInvocation) (output *typedval
 	wf := invocation.GetSpec().GetWorkflow()
 	for id := range invocation.Tasks() {
 		task := invocation.Status.Tasks[id]
-		if !task.GetStatus().Successful() {
+		// Tasks of the branches that were not selected by a switch are skipped, which does not fail the invocation.
+		if !task.GetStatus().Successful() && task.GetStatus().GetStatus() != types.TaskInvocationStatus_SKIPPED {
 			success = false
 			break
 		}
@@ -525,42 +1902,120 @@ func allTasksFinished(invocation *types.WorkflowInvocation) bool {
 // - It manages all of the workflow controllers.
 // - It provides an executor pool for controllers to submit their tasks to.
 type InvocationMetaController struct {
-	sensors     []ctrl.Sensor
-	executor    *executor.LocalExecutor
-	runOnce     *sync.Once
-	invocations *store.Invocations
-	system      *ctrl.System
+	sensors       []ctrl.Sensor
+	executor      *executor.LocalExecutor
+	runOnce       *sync.Once
+	invocations   *store.Invocations
+	system        *ctrl.System
+	factory       ctrl.ControllerFactory
+	updatesMode   UpdatesMode
+	stateStore    *StateStoreMonitor
+	locks         *ConcurrencyLocks
+	handoff       *handoff
+	cancels       *CancelPropagation
+	runtimeHealth *RuntimeHealth
+	clock         clock.Clock
+	done          func()
+	closeC        <-chan struct{}
 }
 
+// NewInvocationMetaController creates the invocation controller. It returns ErrPushUpdatesUnsupported if push-based
+// updates are required by the configuration, but the invocation cache does not support them.
 func NewInvocationMetaController(executor *executor.LocalExecutor, invocations *store.Invocations,
 	invocationAPI *api.Invocation, taskAPI *api.Task, scheduler *scheduler.InvocationScheduler, stateStore *expr.Store,
-	cachePollInterval time.Duration) *InvocationMetaController {
+	cachePollInterval time.Duration, config InvocationConfig) (*InvocationMetaController, error) {
+	updatesMode, err := resolveUpdatesMode(config.Updates, invocations)
+	if err != nil {
+		return nil, err
+	}
+	pollInterval := cachePollInterval
+	switch {
+	case config.Updates == UpdatesModePolling:
+		pollInterval = config.PollingInterval
+		if pollInterval <= 0 {
+			pollInterval = DefaultPollingInterval
+		}
+		logrus.Infof("Invocation controller runs in polling-only mode: polling the invocations every %v",
+			pollInterval)
+	case updatesMode == UpdatesModePolling:
+		logrus.Warnf("Invocation cache does not support push-based updates; falling back to polling the "+
+			"invocations every %v", pollInterval)
+	default:
+		logrus.Info("Invocation controller subscribed to push-based invocation updates")
+	}
+
+	var evalQueue workqueue.Interface = workqueue.NewWorkQueue(workqueue.DefaultMaxSize, true)
+	if config.FairQueuing {
+		evalQueue = workqueue.NewFairQueue("invocations", workqueue.DefaultMaxSize, true, invocationTenant,
+			config.TenantWeights)
+	}
+	if config.Load.Enabled() {
+		config.loadGate = NewLoadGate(config.Load, evalQueue.Len, executor.Utilization)
+	}
+	if config.Clock == nil {
+		config.Clock = clock.RealClock{}
+	}
+	config.Quarantines.SetClock(config.Clock)
+	config.Suspensions.SetClock(config.Clock)
+	config.loopBudget = NewLoopBudget(config.MaxLoopIterations)
+	config.parallelism = NewWorkflowParallelism()
+	config.stateStore = NewStateStoreMonitor(stateStore.Get, config.StateStoreTimeout)
+	config.handoff = newHandoff(config.Standby)
+	config.finished = newFinishedObservations(finishedObservationsSize)
+	config.cancelPropagation = NewCancelPropagation(invocations, func(invocationID string) error {
+		return invocationAPI.Cancel(invocationID)
+	})
+	if config.Standby {
+		logrus.Info("Invocation controller is in standby: waiting for the invocations to be handed off")
+	}
+	ctx, done := context.WithCancel(context.Background())
 	c := &InvocationMetaController{
-		executor:    executor,
-		runOnce:     &sync.Once{},
-		invocations: invocations,
-		system: ctrl.NewSystem(func(event *ctrl.Event) (ctrl ctrl.Controller, err error) {
+		executor:      executor,
+		runOnce:       &sync.Once{},
+		invocations:   invocations,
+		updatesMode:   updatesMode,
+		stateStore:    config.stateStore,
+		locks:         config.Concurrency,
+		handoff:       config.handoff,
+		cancels:       config.cancelPropagation,
+		runtimeHealth: config.RuntimeHealth,
+		clock:         config.Clock,
+		done:          done,
+		closeC:        ctx.Done(),
+		factory: func(event *ctrl.Event) (ctrl ctrl.Controller, err error) {
 			spanCtx, err := fes.ExtractTracingFromEventMetadata(event.Event.GetMetadata())
 			if err != nil {
 				logrus.Debugf("Could not extract span from event metadata: %v", err)
 			}
-			var span opentracing.Span
-			if spanCtx != nil {
-				span = opentracing.StartSpan("/controller/eval", opentracing.FollowsFrom(spanCtx))
-			} else {
-				span = opentracing.StartSpan("/controller/eval")
-			}
 			invocationID := event.Aggregate.Id
 			if len(invocationID) == 0 {
 				return nil, fmt.Errorf("invocation ID missing in event: %v %v", event.Aggregate, event.Event.GetType())
 			}
+			var opts []opentracing.StartSpanOption
+			if spanCtx != nil {
+				opts = append(opts, opentracing.FollowsFrom(spanCtx))
+			}
+			invocation, _ := event.Updated.(*types.WorkflowInvocation)
+			span, trace := config.TraceSampling.StartSpan(invocationID, invocation.GetSpec().GetLabels(), opts...)
 			return NewInvocationController(invocationID, executor, invocationAPI, taskAPI, scheduler,
-				stateStore, span, logrus.WithField("key", invocationID)), nil
-		}),
+				stateStore, span, trace, logrus.WithField("key", invocationID), config), nil
+		},
+	}
+	// The factory reads the config once the system runs, so the controllers it creates share this function.
+	config.reevaluateAfter = c.reevaluateAfter
+	c.system = ctrl.NewSystemWithQueue(c.factory, evalQueue)
+	c.system.SetClock(config.Clock)
+	c.system.SetRetention(config.FinishedRetention)
+	c.system.SetDebounce(config.EvalDebounce)
+	stalenessInterval, maxStaleness := config.StalenessInterval, config.MaxStaleness
+	if stalenessInterval <= 0 {
+		stalenessInterval = DefaultStalenessInterval
+	}
+	if maxStaleness <= 0 {
+		maxStaleness = DefaultMaxStaleness
 	}
 	c.sensors = []ctrl.Sensor{
-		NewInvocationNotificationSensor(invocations),
-		NewInvocationStorePollSensor(invocations, cachePollInterval),
+		NewInvocationStorePollSensor(invocations, pollInterval),
 		NewStalenessPollSensor(c.system, func(ctrlKey string) (fes.Aggregate, fes.Entity, error) {
 			aggregate := fes.Aggregate{
 				Type: types.TypeInvocation,
@@ -571,14 +2026,47 @@ func NewInvocationMetaController(executor *executor.LocalExecutor, invocations *
 				return aggregate, nil, err
 			}
 			return aggregate, invocation, nil
-		}, 100*time.Millisecond, time.Second),
+		}, stalenessInterval, maxStaleness, config.Clock),
+	}
+	if updatesMode == UpdatesModePush {
+		c.sensors = append(c.sensors, NewInvocationNotificationSensor(invocations))
+	}
+	return c, nil
+}
+
+// UpdatesMode returns how the controller learns about updates of invocations: either UpdatesModePush or
+// UpdatesModePolling.
+func (c *InvocationMetaController) UpdatesMode() UpdatesMode {
+	return c.updatesMode
+}
+
+// StateStoreDegraded returns whether the reads of the expression state store exceed the timeout.
+func (c *InvocationMetaController) StateStoreDegraded() bool {
+	return c.stateStore.Degraded()
+}
+
+// UnhealthyRuntimes returns the function runtimes that are unhealthy, of which the tasks are deferred.
+func (c *InvocationMetaController) UnhealthyRuntimes() []string {
+	return c.runtimeHealth.Unhealthy()
+}
+
+// invocationTenant returns the tenant label of the invocation in the evaluation event, which is used to partition
+// the evaluations for fair queuing. Invocations without a tenant label share the default partition.
+func invocationTenant(item interface{}) string {
+	event, ok := item.(*ctrl.Event)
+	if !ok {
+		return ""
+	}
+	invocation, ok := event.Updated.(*types.WorkflowInvocation)
+	if !ok {
+		return ""
 	}
-	return c
+	return invocation.GetSpec().GetLabels()[types.LabelTenant]
 }
 
 func (c *InvocationMetaController) Run() {
 	c.runOnce.Do(func() {
-		go c.run()
+		ctrl.Go("invocations", c.run)
 	})
 }
 
@@ -599,7 +2087,78 @@ func (c *InvocationMetaController) run() {
 
 }
 
+// Reevaluate submits an evaluation of the invocation to the controller, regardless of whether a notification or poll
+// would have triggered one. It returns whether the invocation was found and whether the evaluation was enqueued.
+// This is intended for debugging and recovery, for example when a notification is suspected to have been missed.
+func (c *InvocationMetaController) Reevaluate(invocationID string) (found bool, enqueued bool, err error) {
+	invocation, err := c.invocations.GetInvocation(invocationID)
+	if err != nil {
+		if fes.ErrEntityNotFound.Is(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	if invocation == nil {
+		return false, false, nil
+	}
+
+	aggregate := fes.Aggregate{
+		Type: types.TypeInvocation,
+		Id:   invocationID,
+	}
+	enqueued = c.system.Submit(&ctrl.Event{
+		Old:     invocation,
+		Updated: invocation,
+		Event: &fes.Event{
+			Type:      EventReevaluate,
+			Aggregate: &aggregate,
+			Timestamp: ptypes.TimestampNow(),
+		},
+		Aggregate: aggregate,
+	})
+	return true, enqueued, nil
+}
+
+// EvaluationStats returns the evaluation statistics of the invocation, if it has been evaluated before.
+func (c *InvocationMetaController) EvaluationStats(invocationID string) (ctrl.ControllerStats, bool) {
+	return c.system.GetControllerStats(invocationID)
+}
+
+// TraceDecision returns the sampling decision of the trace of the invocation, if its controller is active.
+func (c *InvocationMetaController) TraceDecision(invocationID string) (TraceDecision, bool) {
+	controller, ok := c.system.GetController(invocationID)
+	if !ok {
+		return TraceDecision{}, false
+	}
+	ic, ok := controller.(*InvocationController)
+	if !ok {
+		return TraceDecision{}, false
+	}
+	return ic.trace, true
+}
+
+// InvocationHierarchy returns the invocation along with its sub-workflow invocations, and whether the cancellation
+// of the invocations was propagated to them.
+func (c *InvocationMetaController) InvocationHierarchy(invocationID string) (*InvocationHierarchy, error) {
+	return c.cancels.Hierarchy(invocationID)
+}
+
+// reevaluateAfter submits an evaluation of the invocation once the delay has passed, such as when the retry of a
+// failed task is due. The evaluation is submitted with the state of the invocation at that time.
+func (c *InvocationMetaController) reevaluateAfter(invocationID string, delay time.Duration) {
+	timer := c.clock.NewTimer(delay)
+	ctrl.Go("invocations", func() {
+		select {
+		case <-timer.C():
+			c.submitEvaluation(invocationID, EventReevaluate)
+		case <-c.closeC:
+			timer.Stop()
+		}
+	})
+}
+
 func (c *InvocationMetaController) Close() error {
+	c.done()
 	err := c.executor.Close()
 	err = c.system.Close()
 	for _, sensor := range c.sensors {
@@ -625,7 +2184,7 @@ func NewInvocationNotificationSensor(invocations *store.Invocations) *Invocation
 }
 
 func (s *InvocationNotificationSensor) Start(evalQueue ctrl.EvalQueue) error {
-	go s.Run(evalQueue)
+	ctrl.Go("sensors", func() { s.Run(evalQueue) })
 	return nil
 }
 
@@ -643,6 +2202,7 @@ func (s *InvocationNotificationSensor) Run(evalQueue ctrl.EvalQueue) {
 			if err != nil {
 				logrus.Warnf("Failed to convert pubsub message to notification: %v", err)
 			}
+			observeLateTaskResult(notification)
 			evalQueue.Submit(notification)
 		case <-s.closeC:
 			err := sub.Close()
@@ -655,6 +2215,21 @@ func (s *InvocationNotificationSensor) Run(evalQueue ctrl.EvalQueue) {
 	}
 }
 
+// observeLateTaskResult meters the task events that arrive after the invocation has already reached a terminal state.
+// The projection of the invocation records these separately, without altering the state of the invocation.
+func observeLateTaskResult(notification *fes.Notification) {
+	if notification == nil || notification.Event.GetAggregate().GetType() != types.TypeTaskRun {
+		return
+	}
+	old, ok := notification.Old.(*types.WorkflowInvocation)
+	if !ok || old.GetStatus() == nil || !old.GetStatus().Finished() {
+		return
+	}
+	logrus.WithField("invocation", old.ID()).Infof("Received late %s event for task %s",
+		notification.Event.GetType(), notification.Event.GetAggregate().GetId())
+	metricLateTaskResults.WithLabelValues(notification.Event.GetType()).Inc()
+}
+
 func (s *InvocationNotificationSensor) Close() error {
 	s.done()
 	return nil
@@ -726,14 +2301,16 @@ type StalenessPollSensor struct {
 	system       *ctrl.System
 	maxStaleness time.Duration
 	stateFetcher func(ctrlKey string) (fes.Aggregate, fes.Entity, error)
+	clock        clock.Clock
 }
 
 func NewStalenessPollSensor(system *ctrl.System, stateFetcher func(ctrlKey string) (fes.Aggregate, fes.Entity, error),
-	interval time.Duration, maxStaleness time.Duration) *StalenessPollSensor {
+	interval time.Duration, maxStaleness time.Duration, clock clock.Clock) *StalenessPollSensor {
 	s := &StalenessPollSensor{
 		system:       system,
 		maxStaleness: maxStaleness,
 		stateFetcher: stateFetcher,
+		clock:        clock,
 	}
 	s.PollSensor = ctrl.NewPollSensor(interval, s.Poll)
 	return s
@@ -741,7 +2318,7 @@ func NewStalenessPollSensor(system *ctrl.System, stateFetcher func(ctrlKey strin
 
 func (s *StalenessPollSensor) Poll(queue ctrl.EvalQueue) {
 	s.system.RangeControllerStats(func(ctrlKey string, ctrlStats ctrl.ControllerStats) bool {
-		minLastEvaluation := time.Now().Add(-s.maxStaleness)
+		minLastEvaluation := s.clock.Now().Add(-s.maxStaleness)
 		if ctrlStats.LastEvaluatedAt.After(minLastEvaluation) {
 			return true
 		}
@@ -765,7 +2342,7 @@ func (s *StalenessPollSensor) Poll(queue ctrl.EvalQueue) {
 			}
 		}
 
-		queue.Submit(&ctrl.Event{
+		submitted := queue.Submit(&ctrl.Event{
 			Old:     entity,
 			Updated: entity,
 			Event: &fes.Event{
@@ -775,6 +2352,9 @@ func (s *StalenessPollSensor) Poll(queue ctrl.EvalQueue) {
 			},
 			Aggregate: aggregate,
 		})
+		if submitted {
+			metricStaleReevaluations.Inc()
+		}
 		return true
 	})
 }
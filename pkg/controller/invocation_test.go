@@ -0,0 +1,1232 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// setupRaceInvocation creates an invocation of a workflow with three independent mirror tasks, of which the given
+// tasks have succeeded.
+func setupRaceInvocation(policy *types.CompletionPolicy, succeeded ...string) *types.WorkflowInvocation {
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("mirrorA", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("mirrorB", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("mirrorC", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.Completion = policy
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{
+				Metadata: types.NewObjectMetadata("wf"),
+				Spec:     wfSpec,
+				Status:   &types.WorkflowStatus{},
+			},
+		},
+		Status: &types.WorkflowInvocationStatus{
+			Tasks: map[string]*types.TaskInvocation{},
+		},
+	}
+	for _, taskID := range succeeded {
+		invocation.Status.Tasks[taskID] = &types.TaskInvocation{
+			Metadata: types.NewObjectMetadata(taskID),
+			Status:   &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_SUCCEEDED},
+		}
+	}
+	return invocation
+}
+
+func TestEvalCompletionPolicy_Any(t *testing.T) {
+	policy := &types.CompletionPolicy{Mode: types.CompletionModeAny}
+	_, met := evalCompletionPolicy(setupRaceInvocation(policy))
+	assert.False(t, met)
+
+	completedBy, met := evalCompletionPolicy(setupRaceInvocation(policy, "mirrorB"))
+	assert.True(t, met)
+	assert.Equal(t, []string{"mirrorB"}, completedBy)
+}
+
+func TestEvalCompletionPolicy_Quorum(t *testing.T) {
+	policy := &types.CompletionPolicy{
+		Mode:   types.CompletionModeQuorum,
+		Tasks:  []string{"mirrorA", "mirrorB", "mirrorC"},
+		Quorum: 2,
+	}
+	_, met := evalCompletionPolicy(setupRaceInvocation(policy, "mirrorC"))
+	assert.False(t, met)
+
+	completedBy, met := evalCompletionPolicy(setupRaceInvocation(policy, "mirrorC", "mirrorA"))
+	assert.True(t, met)
+	assert.Equal(t, []string{"mirrorA", "mirrorC"}, completedBy)
+}
+
+func TestEvalCompletionPolicy_All(t *testing.T) {
+	// Without a task set, the completion is left to the regular completion check.
+	_, met := evalCompletionPolicy(setupRaceInvocation(&types.CompletionPolicy{},
+		"mirrorA", "mirrorB", "mirrorC"))
+	assert.False(t, met)
+
+	policy := &types.CompletionPolicy{
+		Mode:  types.CompletionModeAll,
+		Tasks: []string{"mirrorA", "mirrorB"},
+	}
+	_, met = evalCompletionPolicy(setupRaceInvocation(policy, "mirrorA"))
+	assert.False(t, met)
+
+	completedBy, met := evalCompletionPolicy(setupRaceInvocation(policy, "mirrorA", "mirrorB"))
+	assert.True(t, met)
+	assert.Equal(t, []string{"mirrorA", "mirrorB"}, completedBy)
+}
+
+// setupEarlyCompletion invokes a workflow that completes once any of the mirror tasks has succeeded. The call of the
+// slow mirror blocks until the release channel is closed, after which the returned channel is closed; the notify task
+// depends on the slow mirror. The executor of the controller should be closed by the caller.
+func setupEarlyCompletion(t *testing.T, cancelAbandoned bool) (c *InvocationController,
+	project func() *types.WorkflowInvocation, release chan struct{}, returned chan struct{}) {
+	release = make(chan struct{})
+	returned = make(chan struct{})
+	runtime := mock.NewRuntime()
+	runtime.Functions["fast"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("fast"), nil
+	}
+	runtime.Functions["slow"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		<-release
+		close(returned)
+		return typedvalues.MustWrap("slow"), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(4, 10)
+	exec.Start()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("fastMirror", &types.TaskSpec{FunctionRef: "fast"})
+	wfSpec.AddTask("slowMirror", &types.TaskSpec{FunctionRef: "slow"})
+	wfSpec.AddTask("notify", &types.TaskSpec{FunctionRef: "fast", Requires: types.Require("slowMirror")})
+	wfSpec.OutputTask = "fastMirror"
+	wfSpec.Completion = &types.CompletionPolicy{Mode: types.CompletionModeAny}
+	wfSpec.CancelAbandonedTasks = cancelAbandoned
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{}}
+	for taskID, taskSpec := range wfSpec.Tasks {
+		wfStatus.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "mock", ID: taskSpec.FunctionRef},
+		}}
+	}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	c = NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	project = func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	return c, project, release, returned
+}
+
+// awaitInvocation waits until the condition holds for the projection of the invocation, or fails the test.
+func awaitInvocation(t *testing.T, project func() *types.WorkflowInvocation,
+	cond func(invocation *types.WorkflowInvocation) bool) *types.WorkflowInvocation {
+	for i := 0; i < 200; i++ {
+		if invocation := project(); cond(invocation) {
+			return invocation
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.FailNow(t, "condition was not met")
+	return nil
+}
+
+func TestEval_CompleteEarly(t *testing.T) {
+	c, project, release, returned := setupEarlyCompletion(t, false)
+	defer c.executor.Close()
+	c.Eval(context.Background(), &ctrl.Event{Updated: project()})
+	invocation := awaitInvocation(t, project, func(invocation *types.WorkflowInvocation) bool {
+		run, ok := invocation.TaskInvocation("fastMirror")
+		return ok && run.GetStatus().Successful()
+	})
+
+	// The completion policy is met while the slow mirror is still running.
+	result := c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+	assert.Equal(t, ctrl.Success{Msg: "completion policy of the invocation has been met"}, result)
+	invocation = awaitInvocation(t, project, func(invocation *types.WorkflowInvocation) bool {
+		return invocation.GetStatus().Finished()
+	})
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, invocation.GetStatus().GetStatus())
+	assert.Equal(t, "fast", typedvalues.MustUnwrap(invocation.GetStatus().GetOutput()))
+	assert.Equal(t, []string{"fastMirror"}, invocation.GetStatus().GetCompletedBy())
+	assert.Equal(t, []string{"notify", "slowMirror"}, invocation.GetStatus().GetAbandonedTasks())
+
+	// Without cancelAbandonedTasks, the abandoned task is left to finish in the background.
+	run, ok := invocation.TaskInvocation("slowMirror")
+	assert.True(t, ok)
+	assert.Equal(t, types.TaskInvocationStatus_IN_PROGRESS, run.GetStatus().GetStatus())
+	close(release)
+	<-returned
+}
+
+func TestEval_CompleteEarlyCancelAbandoned(t *testing.T) {
+	c, project, release, returned := setupEarlyCompletion(t, true)
+	defer c.executor.Close()
+	c.Eval(context.Background(), &ctrl.Event{Updated: project()})
+	invocation := awaitInvocation(t, project, func(invocation *types.WorkflowInvocation) bool {
+		run, ok := invocation.TaskInvocation("fastMirror")
+		return ok && run.GetStatus().Successful()
+	})
+	c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+	invocation = awaitInvocation(t, project, func(invocation *types.WorkflowInvocation) bool {
+		return invocation.GetStatus().Finished()
+	})
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, invocation.GetStatus().GetStatus())
+	assert.Equal(t, []string{"notify", "slowMirror"}, invocation.GetStatus().GetAbandonedTasks())
+
+	// The abandoned task that was in progress is failed, whereas the task that was never started is left alone.
+	run, ok := invocation.TaskInvocation("slowMirror")
+	assert.True(t, ok)
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, run.GetStatus().GetStatus())
+	assert.Contains(t, run.GetStatus().GetError().GetMessage(), "abandoned")
+	_, ok = invocation.TaskInvocation("notify")
+	assert.False(t, ok)
+
+	// Failing the task does not cancel the call of its function, which keeps running until it returns.
+	select {
+	case <-returned:
+		assert.Fail(t, "call returned before it was released")
+	default:
+	}
+	close(release)
+	<-returned
+}
+
+func TestMemoryBudget(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.Functions["big"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap(strings.Repeat("x", 1024)), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("fetch", &types.TaskSpec{FunctionRef: "big"})
+	wfSpec.AddTask("process", &types.TaskSpec{FunctionRef: "big", Requires: types.Require("fetch")})
+	wfSpec.OutputTask = "process"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{}}
+	for taskID, taskSpec := range wfSpec.Tasks {
+		wfStatus.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "mock", ID: taskSpec.FunctionRef},
+		}}
+	}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{MemoryBudget: 512})
+	c.Eval(context.Background(), &ctrl.Event{Updated: project()})
+	invocation := awaitInvocation(t, project, func(invocation *types.WorkflowInvocation) bool {
+		run, ok := invocation.TaskInvocation("fetch")
+		return ok && run.GetStatus().Successful()
+	})
+	assert.True(t, invocation.GetStatus().GetPayloadSize() > 1024)
+
+	// The output of the first task exceeds the budget, so the invocation is failed before the next task is started.
+	result := c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+	errResult, ok := result.(ctrl.Err)
+	assert.True(t, ok)
+	assert.Contains(t, errResult.Error(), ErrMemoryBudgetExceeded.Error())
+	invocation = awaitInvocation(t, project, func(invocation *types.WorkflowInvocation) bool {
+		return invocation.GetStatus().Finished()
+	})
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, invocation.GetStatus().GetStatus())
+	assert.Contains(t, invocation.GetStatus().GetError().GetMessage(), ErrMemoryBudgetExceeded.Error())
+	_, ok = invocation.TaskInvocation("process")
+	assert.False(t, ok)
+}
+
+func TestFirstTaskDuration_Prewarmed(t *testing.T) {
+	runTask := func(prewarm bool, started bool) {
+		runtime := mock.NewRuntime()
+		runtime.Functions["task"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+			return typedvalues.MustWrap("ok"), nil
+		}
+		backend := mem.NewBackend()
+		invocationAPI := api.NewInvocationAPI(backend)
+		taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+		exec := executor.NewLocalExecutor(1, 10)
+		exec.Start()
+		defer exec.Close()
+
+		wfSpec := types.NewWorkflowSpec()
+		wfSpec.AddTask("task", &types.TaskSpec{FunctionRef: "task"})
+		wfSpec.OutputTask = "task"
+		wfSpec.Prewarm = prewarm
+		wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+			"task": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "task"}}},
+		}}
+		spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+		spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+		invocationID, err := invocationAPI.Invoke(spec)
+		assert.NoError(t, err)
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		invocation := entity.(*types.WorkflowInvocation)
+
+		c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+			scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(),
+			opentracing.StartSpan("wi"), TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+		if started {
+			// The invocation had started executing tasks before this controller evaluated it, so there is nothing
+			// left to prepare.
+			started := proto.Clone(invocation).(*types.WorkflowInvocation)
+			started.Status.Tasks = map[string]*types.TaskInvocation{"other": {}}
+			c.prewarm(started)
+			assert.True(t, c.prewarmChecked)
+		}
+		c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	sampleCount := func(prewarmed string) uint64 {
+		m := &dto.Metric{}
+		assert.NoError(t, metricFirstTaskDuration.WithLabelValues(prewarmed).(prometheus.Metric).Write(m))
+		return m.GetSummary().GetSampleCount()
+	}
+
+	// The label reflects whether the initial tasks were actually prepared, rather than whether the workflow
+	// requested it.
+	for _, tc := range []struct {
+		prewarm, started bool
+		label            string
+	}{
+		{prewarm: true, label: "true"},
+		{prewarm: false, label: "false"},
+		{prewarm: true, started: true, label: "false"},
+	} {
+		before := sampleCount(tc.label)
+		runTask(tc.prewarm, tc.started)
+		assert.Equal(t, before+1, sampleCount(tc.label), "%+v", tc)
+	}
+}
+
+func TestUnwrapOutput(t *testing.T) {
+	output := typedvalues.MustWrap(map[string]interface{}{
+		"data": map[string]interface{}{
+			"items": []interface{}{"a", "b"},
+		},
+		"meta": map[string]interface{}{
+			"requestId": "42",
+		},
+	})
+
+	unwrapped, err := unwrapOutput(output, "data.items.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", typedvalues.MustUnwrap(unwrapped))
+
+	_, err = unwrapOutput(output, "data.missing")
+	assert.Error(t, err)
+	_, err = unwrapOutput(output, "data.items.2")
+	assert.Error(t, err)
+	_, err = unwrapOutput(output, "meta.requestId.foo")
+	assert.Error(t, err)
+}
+
+func TestTransformTaskRunOutputs_OutputPath(t *testing.T) {
+	c := NewInvocationController("wi", nil, nil, nil, nil, nil, nil, TraceDecision{}, logrus.WithField("key", "wi"),
+		InvocationConfig{OutputPaths: map[string]string{"envelope": "data"}})
+	newTaskRun := func(outputPath string) *types.TaskInvocation {
+		return &types.TaskInvocation{
+			Spec: &types.TaskInvocationSpec{
+				Task: &types.Task{
+					Metadata: types.NewObjectMetadata("task"),
+					Spec:     &types.TaskSpec{FunctionRef: "envelope", OutputPath: outputPath},
+				},
+			},
+			Status: &types.TaskInvocationStatus{
+				Status: types.TaskInvocationStatus_SUCCEEDED,
+				Output: typedvalues.MustWrap(map[string]interface{}{
+					"data": "result",
+				}),
+			},
+		}
+	}
+
+	// The output path of the function is used by default.
+	ti := newTaskRun("")
+	assert.NoError(t, c.transformTaskRunOutputs(nil, ti))
+	assert.Equal(t, "result", typedvalues.MustUnwrap(ti.GetStatus().GetOutput()))
+
+	// The task fails if the output path of the task is missing from the output.
+	ti = newTaskRun("data.result")
+	assert.NoError(t, c.transformTaskRunOutputs(nil, ti))
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, ti.GetStatus().GetStatus())
+	assert.Contains(t, ti.GetStatus().GetError().GetMessage(), "data.result")
+}
+
+func TestCheckErrorBudget(t *testing.T) {
+	newController := func(budget ErrorBudget) *InvocationController {
+		return NewInvocationController("wi", nil, nil, nil, nil, nil, nil, TraceDecision{},
+			logrus.WithField("key", "wi"), InvocationConfig{ErrorBudget: budget})
+	}
+
+	// Without a budget, errors are not limited.
+	c := newController(ErrorBudget{})
+	c.recordTaskError("flaky", "timeout")
+	c.recordTaskError("flaky", "timeout")
+	assert.NoError(t, c.checkErrorBudget())
+
+	// The per-task budget allows errors to be spread across tasks.
+	c = newController(ErrorBudget{MaxErrors: 4, MaxTaskErrors: 2})
+	c.recordTaskError("a", "timeout")
+	c.recordTaskError("b", "timeout")
+	c.recordTaskError("c", "timeout")
+	assert.NoError(t, c.checkErrorBudget())
+	c.recordTaskError("b", "connection refused")
+	assert.EqualError(t, c.checkErrorBudget(), "error count exceeded: 4 task errors in the invocation (max: 4); "+
+		"last error: b: connection refused")
+
+	c = newController(ErrorBudget{MaxTaskErrors: 2})
+	c.recordTaskError("flaky", "timeout")
+	assert.NoError(t, c.checkErrorBudget())
+	c.recordTaskError("flaky", "")
+	assert.EqualError(t, c.checkErrorBudget(), "error count exceeded: 2 errors of task flaky (max: 2); "+
+		"last error: flaky: timeout")
+}
+
+func TestObserveEvaluation(t *testing.T) {
+	c := NewInvocationController("wi", nil, nil, nil, nil, nil, nil, TraceDecision{}, logrus.WithField("key", "wi"),
+		InvocationConfig{NoopEvalThreshold: 3})
+	invocation := &types.WorkflowInvocation{Metadata: &types.ObjectMetadata{Id: "wi"}}
+	thrashing := counterValue(t, metricThrashingInvocations)
+
+	c.observeEvaluation(invocation, false)
+	c.observeEvaluation(invocation, false)
+	c.observeEvaluation(invocation, true)
+	assert.Equal(t, 0, c.noopEvals)
+	assert.Equal(t, thrashing, counterValue(t, metricThrashingInvocations))
+
+	// The invocation is reported once the threshold of consecutive no-op evaluations is reached.
+	for i := 0; i < 4; i++ {
+		c.observeEvaluation(invocation, false)
+	}
+	assert.Equal(t, 4, c.noopEvals)
+	assert.Equal(t, thrashing+1, counterValue(t, metricThrashingInvocations))
+}
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	assert.NoError(t, counter.Write(m))
+	return m.GetCounter().GetValue()
+}
+
+func TestResolveInputs_DependencyTransforms(t *testing.T) {
+	c := NewInvocationController("wi", nil, nil, nil, nil, expr.NewStore(), nil, TraceDecision{},
+		logrus.WithField("key", "wi"),
+		InvocationConfig{})
+	invocation := setupRaceInvocation(nil, "mirrorA", "mirrorB", "mirrorC")
+	invocation.Status.Tasks["mirrorA"].Status.Output = typedvalues.MustWrap(map[string]interface{}{
+		"user": map[string]interface{}{"name": "alice"},
+	})
+	invocation.Status.Tasks["mirrorB"].Status.Output = typedvalues.MustWrap("bar")
+	invocation.Status.Tasks["mirrorC"].Status.Output = typedvalues.MustWrap("baz")
+
+	spec := &types.TaskSpec{
+		FunctionRef: "noop",
+		Inputs: map[string]*typedvalues.TypedValue{
+			"mirrorC": typedvalues.MustWrap("explicit"),
+		},
+		Requires: map[string]*types.TaskDependencyParameters{
+			// With a transform and an alias
+			"mirrorA": {Alias: "name", Transform: typedvalues.MustWrap("{ output().user.name }")},
+			// Without a transform
+			"mirrorB": {},
+			// With a transform that is overridden by an explicit input
+			"mirrorC": {Transform: typedvalues.MustWrap("{ output() }")},
+		},
+	}
+	invocation.Spec.Workflow.Spec.AddTask("consumer", spec)
+	invocation.Spec.Workflow.Status.Tasks = map[string]*types.Task{}
+	for taskID := range invocation.Spec.Workflow.Spec.Tasks {
+		invocation.Spec.Workflow.Status.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{}}
+	}
+
+	inputs, err := c.resolveInputs(invocation, "consumer", spec)
+	assert.NoError(t, err)
+	assert.Len(t, inputs, 2)
+	assert.Equal(t, "alice", typedvalues.MustUnwrap(inputs["name"]))
+	assert.Equal(t, "explicit", typedvalues.MustUnwrap(inputs["mirrorC"]))
+}
+
+func TestRecoverInFlightTasks(t *testing.T) {
+	// Both tasks were in progress when the previous controller crashed; only "fetch" is safe to execute again.
+	var fetched int
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["fetch"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		fetched++
+		return typedvalues.MustWrap("fetched"), nil
+	}
+	runtime.Functions["charge"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		t.Error("non-idempotent task should not be executed again")
+		return nil, nil
+	}
+	backend := mem.NewBackend()
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(2, 10)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("fetch", &types.TaskSpec{FunctionRef: "fetch", Idempotent: true})
+	wfSpec.AddTask("charge", &types.TaskSpec{FunctionRef: "charge"})
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{}}
+	for taskID, taskSpec := range wfSpec.Tasks {
+		wfStatus.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "mock", ID: taskSpec.FunctionRef},
+		}}
+	}
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec:     types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute)),
+		Status: &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_IN_PROGRESS,
+			Tasks: map[string]*types.TaskInvocation{
+				"fetch":  {Status: &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_IN_PROGRESS}},
+				"charge": {Status: &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_IN_PROGRESS}},
+			},
+		},
+	}
+	invocation.Spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec,
+		Status: wfStatus}
+
+	c := NewInvocationController("wi", exec, nil, taskAPI, scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()),
+		expr.NewStore(), opentracing.StartSpan("wi"), TraceDecision{}, logrus.WithField("key", "wi"),
+		InvocationConfig{})
+	result := c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+	assert.Equal(t, ctrl.Success{Msg: "recovered 2 in-flight task(s)"}, result)
+
+	for i := 0; i < 100 && exec.GetGroupTasks("wi") > 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, exec.GetGroupTasks("wi"))
+	assert.Equal(t, 1, fetched)
+
+	// The idempotent task was executed again, whereas the other task was failed.
+	taskEvents := map[string][]string{}
+	var chargeErr *types.Error
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate("wi"))
+	assert.NoError(t, err)
+	for _, event := range invocationEvents {
+		taskEvents[event.Aggregate.Id] = append(taskEvents[event.Aggregate.Id], event.Type)
+		if failed, err := fes.ParseEventData(event); err == nil && event.Type == events.EventTaskFailed {
+			chargeErr = failed.(*events.TaskFailed).GetError()
+		}
+	}
+	assert.Equal(t, []string{events.EventTaskStarted, events.EventTaskSucceeded}, taskEvents["fetch"])
+	assert.Equal(t, []string{events.EventTaskFailed}, taskEvents["charge"])
+	assert.Equal(t, types.ErrorCodeAborted, chargeErr.GetCode())
+
+	// Once recovered, the tasks are tracked by the controller and are not recovered again.
+	assert.Equal(t, 0, c.recoverInFlightTasks(invocation))
+}
+
+func TestRetryBudget(t *testing.T) {
+	// Task "flaky" succeeds on its second attempt, whereas task "broken" keeps failing.
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["flaky"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		if spec.GetAttempt() < 2 {
+			return nil, errors.New("flaky failure")
+		}
+		return typedvalues.MustWrap("ok"), nil
+	}
+	runtime.Functions["broken"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return nil, errors.New("broken")
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	// Both tasks allow for retries, but the invocation only allows for two retries in total.
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("flaky", &types.TaskSpec{FunctionRef: "flaky", Retry: &types.RetryPolicy{MaxAttempts: 3}})
+	wfSpec.AddTask("broken", &types.TaskSpec{FunctionRef: "broken", Retry: &types.RetryPolicy{MaxAttempts: 5}})
+	wfSpec.OutputTask = "flaky"
+	wfSpec.RetryBudget = 2
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{}}
+	for taskID, taskSpec := range wfSpec.Tasks {
+		wfStatus.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "mock", ID: taskSpec.FunctionRef},
+		}}
+	}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	eval := func() (*types.WorkflowInvocation, ctrl.Result) {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		invocation := entity.(*types.WorkflowInvocation)
+		result := c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return invocation, result
+	}
+
+	invocation, result := eval()
+	assert.Equal(t, ctrl.Success{Msg: "scheduled execution of 2 tasks and preparation of 0 tasks"}, result)
+	assert.Equal(t, &types.RetryBudgetStatus{Limit: 2, Remaining: 2}, invocation.GetStatus().GetRetryBudget())
+
+	// Both tasks failed on their first attempt, and are retried within the budget.
+	invocation, result = eval()
+	assert.Equal(t, ctrl.Success{Msg: "retrying 2 failed task(s)"}, result)
+
+	// The flaky task has succeeded, but the budget does not allow the broken task to be retried again.
+	invocation, result = eval()
+	assert.True(t, invocation.GetStatus().GetTasks()["flaky"].GetStatus().Successful())
+	assert.Equal(t, int32(2), invocation.GetStatus().GetTasks()["broken"].GetStatus().GetError().GetAttempt())
+	assert.Equal(t, int32(2), invocation.GetStatus().GetRetries())
+	assert.Equal(t, &types.RetryBudgetStatus{Limit: 2, Remaining: 0}, invocation.GetStatus().GetRetryBudget())
+	errResult, ok := result.(ctrl.Err)
+	assert.True(t, ok)
+	assert.Contains(t, errResult.Err.Error(), ErrRetryBudgetExhausted.Error())
+
+	invocation, _ = eval()
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, invocation.GetStatus().GetStatus())
+}
+
+func TestFailover(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["primary"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return nil, errors.New("primary is down")
+	}
+	runtime.Functions["secondary"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("ok"), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("fetch", &types.TaskSpec{
+		FunctionRef: "primary",
+		Retry:       &types.RetryPolicy{MaxAttempts: 3},
+		Failover:    &types.Failover{FunctionRef: "secondary", After: 2},
+	})
+	wfSpec.OutputTask = "fetch"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"fetch": {Status: &types.TaskStatus{
+			FnRef:         &types.FnRef{Runtime: "mock", ID: "primary"},
+			FailoverFnRef: &types.FnRef{Runtime: "mock", ID: "secondary"},
+		}},
+	}}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	eval := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		invocation := entity.(*types.WorkflowInvocation)
+		c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return invocation
+	}
+
+	// The first two attempts are executed by the primary function.
+	eval()
+	invocation := eval()
+	taskRun := invocation.GetStatus().GetTasks()["fetch"]
+	assert.Equal(t, int32(1), taskRun.GetSpec().GetAttempt())
+	assert.Equal(t, "primary", taskRun.GetSpec().GetFnRef().GetID())
+	assert.False(t, taskRun.GetSpec().GetFailover())
+	invocation = eval()
+	taskRun = invocation.GetStatus().GetTasks()["fetch"]
+	assert.Equal(t, int32(2), taskRun.GetSpec().GetAttempt())
+	assert.Equal(t, "primary", taskRun.GetSpec().GetFnRef().GetID())
+
+	// After two failed attempts, the task fails over to the secondary function.
+	invocation = eval()
+	taskRun = invocation.GetStatus().GetTasks()["fetch"]
+	assert.True(t, taskRun.GetStatus().Successful())
+	assert.Equal(t, int32(3), taskRun.GetSpec().GetAttempt())
+	assert.Equal(t, "secondary", taskRun.GetSpec().GetFnRef().GetID())
+	assert.True(t, taskRun.GetSpec().GetFailover())
+}
+
+func TestRetryBackoff(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["flaky"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		if spec.GetAttempt() < 2 {
+			return nil, errors.New("flaky failure")
+		}
+		return typedvalues.MustWrap("ok"), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	fakeClock := clock.NewFakeClock(time.Now())
+	exec := executor.NewLocalExecutorWithClock(1, 10, fakeClock)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("flaky", &types.TaskSpec{FunctionRef: "flaky", Retry: &types.RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: ptypes.DurationProto(time.Minute),
+	}})
+	wfSpec.OutputTask = "flaky"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"flaky": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "flaky"}}},
+	}}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Hour))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	var reevaluations []time.Duration
+	admission := NewTaskAdmission(1)
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{
+			Clock:     fakeClock,
+			Admission: admission,
+			reevaluateAfter: func(invocationID string, delay time.Duration) {
+				reevaluations = append(reevaluations, delay)
+			},
+		})
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	await := func() {
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	c.Eval(context.Background(), &ctrl.Event{Updated: project()})
+	await()
+	invocation := project()
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, invocation.GetStatus().GetTasks()["flaky"].GetStatus().GetStatus())
+
+	// The delay is measured from the failure of the task, rather than from the evaluation that retries it.
+	failedAt, err := ptypes.Timestamp(invocation.GetStatus().GetTasks()["flaky"].GetStatus().GetUpdatedAt())
+	assert.NoError(t, err)
+	assert.Equal(t, 40*time.Second, retryDelay(invocation, "flaky", failedAt.Add(20*time.Second)))
+
+	// The failed task is not submitted until the delay has passed, so it does not hold any limits in the meantime.
+	// Instead, an evaluation is scheduled for when the retry is due, only once.
+	result := c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+	assert.Equal(t, ctrl.Success{Msg: "retrying 0 failed task(s); 1 backing off"}, result)
+	result = c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+	assert.Equal(t, ctrl.Success{Msg: "retrying 0 failed task(s); 1 backing off"}, result)
+	assert.Equal(t, 0, exec.GetGroupTasks(invocationID))
+	assert.Equal(t, 0, admission.InFlight())
+	assert.Len(t, reevaluations, 1)
+	assert.True(t, reevaluations[0] >= time.Minute && reevaluations[0] < time.Minute+time.Second)
+	assert.Equal(t, int32(1), project().GetStatus().GetTasks()["flaky"].GetSpec().GetAttempt())
+
+	// The fake clock started before the task failed, so it has to pass the delay by a bit more.
+	fakeClock.Step(time.Minute + time.Second)
+	result = c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+	assert.Equal(t, ctrl.Success{Msg: "retrying 1 failed task(s)"}, result)
+	await()
+	taskRun := project().GetStatus().GetTasks()["flaky"]
+	assert.True(t, taskRun.GetStatus().Successful())
+	assert.Equal(t, int32(2), taskRun.GetSpec().GetAttempt())
+
+	// An invocation with a task that backs off is still timed out once its deadline has passed.
+	c = NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{Clock: fakeClock})
+	fakeClock.Step(2 * time.Hour)
+	result = c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+	errResult, ok := result.(ctrl.Err)
+	assert.True(t, ok)
+	assert.Contains(t, errResult.Error(), "deadline exceeded")
+}
+
+func TestSoftTimeout(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["task"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("ok"), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	// The soft timeout is exceeded 10ms after the creation of the invocation.
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("task", &types.TaskSpec{FunctionRef: "task"})
+	wfSpec.OutputTask = "task"
+	wfSpec.SoftTimeoutPercentage = 1
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"task": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "task"}}},
+	}}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Second))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	eval := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		invocation := entity.(*types.WorkflowInvocation)
+		c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return invocation
+	}
+	countWarnings := func() int {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		var count int
+		for _, event := range invocationEvents {
+			if event.GetType() == events.EventInvocationSoftTimeoutExceeded {
+				count++
+			}
+		}
+		return count
+	}
+
+	invocation := eval()
+	assert.False(t, invocation.GetStatus().GetSoftTimeoutExceeded())
+	assert.Equal(t, 1, countWarnings())
+
+	// The warning is emitted once, and does not affect the invocation.
+	invocation = eval()
+	assert.True(t, invocation.GetStatus().GetSoftTimeoutExceeded())
+	eval()
+	invocation = eval()
+	assert.Equal(t, 1, countWarnings())
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, invocation.GetStatus().GetStatus())
+}
+
+func TestSwitch(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["classify"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("large"), nil
+	}
+	runtime.Functions["task"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("ok"), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	// The route switch selects either the small or the large branch, after which merge joins the branches.
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("classify", &types.TaskSpec{FunctionRef: "classify"})
+	wfSpec.AddTask("small", &types.TaskSpec{FunctionRef: "task", Requires: types.Require("classify")})
+	wfSpec.AddTask("large", &types.TaskSpec{FunctionRef: "task", Requires: types.Require("classify")})
+	wfSpec.AddTask("merge", &types.TaskSpec{FunctionRef: "task", Requires: types.Require("small", "large")})
+	wfSpec.OutputTask = "merge"
+	wfSpec.Switches = map[string]*types.Switch{
+		"route": {
+			Expression: "{ $.Tasks.classify.Output }",
+			Requires:   []string{"classify"},
+			Branches: map[string]*types.Branch{
+				"small": {Tasks: []string{"small"}},
+				"large": {Tasks: []string{"large"}},
+			},
+		},
+	}
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{}}
+	for taskID, taskSpec := range wfSpec.Tasks {
+		wfStatus.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "mock", ID: taskSpec.FunctionRef},
+		}}
+	}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	eval := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		invocation := entity.(*types.WorkflowInvocation)
+		c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return invocation
+	}
+
+	invocation := eval()
+	for i := 0; i < 10 && !invocation.GetStatus().Finished(); i++ {
+		invocation = eval()
+	}
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, invocation.GetStatus().GetStatus(),
+		invocation.GetStatus().GetError().GetMessage())
+	assert.Equal(t, map[string]string{"route": "large"}, invocation.GetStatus().GetBranches())
+	assert.Equal(t, types.TaskInvocationStatus_SKIPPED, invocation.GetStatus().GetTasks()["small"].GetStatus().GetStatus())
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED,
+		invocation.GetStatus().GetTasks()["large"].GetStatus().GetStatus())
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED,
+		invocation.GetStatus().GetTasks()["merge"].GetStatus().GetStatus())
+}
+
+func TestCheckRetryDeadline(t *testing.T) {
+	now := time.Now()
+	firstAttemptAt := util.MustTimestampProto(now.Add(-time.Minute))
+	task := &types.Task{
+		Metadata: types.NewObjectMetadata("task"),
+		Spec: &types.TaskSpec{Retry: &types.RetryPolicy{
+			MaxAttempts:  5,
+			TotalTimeout: ptypes.DurationProto(2 * time.Minute),
+		}},
+	}
+	taskRun := &types.TaskInvocation{
+		Metadata: types.NewObjectMetadata("task"),
+		Spec:     &types.TaskInvocationSpec{Task: task, Attempt: 2, FirstAttemptAt: firstAttemptAt},
+		Status: &types.TaskInvocationStatus{
+			Status:        types.TaskInvocationStatus_FAILED,
+			RetryDeadline: types.RetryDeadline(task, firstAttemptAt),
+			Error:         &types.Error{Message: "broken"},
+		},
+	}
+
+	// Within the total timeout the task can be retried...
+	assert.NoError(t, checkRetryDeadline(taskRun, now))
+
+	// ...but not once the total time across the attempts exceeds it.
+	err := checkRetryDeadline(taskRun, now.Add(time.Minute))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrRetryTimeExceeded.Error())
+
+	// Without a total timeout, the retries are not limited in time.
+	taskRun.Status.RetryDeadline = nil
+	assert.NoError(t, checkRetryDeadline(taskRun, now.Add(time.Hour)))
+
+	// Retries keep the start of the first attempt.
+	invocation := types.NewWorkflowInvocation("wf", "wi", now.Add(time.Hour))
+	assert.Equal(t, util.MustTimestampProto(now), taskFirstAttemptAt(invocation, "task", now))
+	invocation.Status.Tasks = map[string]*types.TaskInvocation{"task": taskRun}
+	assert.Equal(t, firstAttemptAt, taskFirstAttemptAt(invocation, "task", now))
+}
+
+// flakyBackend fails the appends of the given event type a number of times.
+type flakyBackend struct {
+	*mem.Backend
+	eventType string
+	failures  int32
+}
+
+func (b *flakyBackend) Append(event *fes.Event) error {
+	if event.Type == b.eventType && atomic.AddInt32(&b.failures, -1) >= 0 {
+		return errors.New("event store unavailable")
+	}
+	return b.Backend.Append(event)
+}
+
+func TestFinalizationRetry(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["ok"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("ok"), nil
+	}
+	backend := &flakyBackend{Backend: mem.NewBackend(), eventType: events.EventInvocationCompleted, failures: 2}
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("ok", &types.TaskSpec{FunctionRef: "ok"})
+	wfSpec.OutputTask = "ok"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"ok": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "ok"}}},
+	}}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"),
+		InvocationConfig{FinalizationRetry: FinalizationRetry{Backoff: 10 * time.Millisecond}})
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	eval := func() (*types.WorkflowInvocation, ctrl.Result) {
+		invocation := project()
+		result := c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return invocation, result
+	}
+	failures := counterValue(t, metricFinalizationFailures.WithLabelValues("success"))
+
+	eval()
+	_, result := eval()
+	assert.Equal(t, ctrl.Success{Msg: "all tasks of the invocation have completed"}, result)
+
+	// The completion is retried until it has been appended.
+	invocation := project()
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, invocation.GetStatus().GetStatus())
+	assert.Equal(t, failures+2, counterValue(t, metricFinalizationFailures.WithLabelValues("success")))
+
+	// Once finalized, the controller does not append another terminal event, even if it evaluates the invocation
+	// before it has observed the completion.
+	c.finalize(invocationID, "success", func() error {
+		return invocationAPI.Complete(invocationID, nil, nil)
+	})
+	_, result = eval()
+	assert.IsType(t, ctrl.Done{}, result)
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+	assert.NoError(t, err)
+	var completed int
+	for _, event := range invocationEvents {
+		if event.Type == events.EventInvocationCompleted {
+			completed++
+		}
+	}
+	assert.Equal(t, 1, completed)
+}
+
+type evalQueueFunc func(event *ctrl.Event) bool
+
+func (f evalQueueFunc) Submit(event *ctrl.Event) bool {
+	return f(event)
+}
+
+func TestStalenessPollSensor(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	stale := ctrl.ControllerStats{LastEvaluatedAt: fakeClock.Now().Add(-time.Minute)}
+	system := ctrl.NewSystem(nil)
+	system.RestoreControllerStats("wi-stale", stale)
+	system.RestoreControllerStats("wi-fresh", ctrl.ControllerStats{LastEvaluatedAt: fakeClock.Now()})
+	system.RestoreControllerStats("wi-finished", stale)
+	sensor := NewStalenessPollSensor(system, func(ctrlKey string) (fes.Aggregate, fes.Entity, error) {
+		invocation := &types.WorkflowInvocation{
+			Metadata: types.NewObjectMetadata(ctrlKey),
+			Status:   &types.WorkflowInvocationStatus{Status: types.WorkflowInvocationStatus_IN_PROGRESS},
+		}
+		if ctrlKey == "wi-finished" {
+			invocation.Status.Status = types.WorkflowInvocationStatus_SUCCEEDED
+		}
+		return fes.Aggregate{Type: types.TypeInvocation, Id: ctrlKey}, invocation, nil
+	}, time.Second, 10*time.Second, fakeClock)
+
+	// Only the unfinished invocation that has not been evaluated for the max staleness is re-evaluated.
+	before := counterValue(t, metricStaleReevaluations)
+	var submitted []string
+	sensor.Poll(evalQueueFunc(func(event *ctrl.Event) bool {
+		submitted = append(submitted, event.Aggregate.Id)
+		return true
+	}))
+	assert.Equal(t, []string{"wi-stale"}, submitted)
+	assert.Equal(t, before+1, counterValue(t, metricStaleReevaluations))
+
+	// Evaluations that are not enqueued are not counted.
+	sensor.Poll(evalQueueFunc(func(event *ctrl.Event) bool {
+		return false
+	}))
+	assert.Equal(t, before+1, counterValue(t, metricStaleReevaluations))
+}
+
+func TestSubmitTask(t *testing.T) {
+	exec := executor.NewLocalExecutor(1, 10)
+	admission := NewTaskAdmission(2)
+	c := NewInvocationController("wi", exec, nil, nil, nil, expr.NewStore(), nil, TraceDecision{},
+		logrus.WithField("key", "wi"), InvocationConfig{Admission: admission})
+	c.config.parallelism = NewWorkflowParallelism()
+	limited := newParallelismInvocation("wf-limited", 1)
+	unlimited := newParallelismInvocation("wf-unlimited", 0)
+
+	assert.NoError(t, c.submitTask(limited, "a"))
+	assert.Equal(t, 1, admission.InFlight())
+
+	// The maximum parallelism of the workflow only defers the tasks of the workflow.
+	err := c.submitTask(limited, "b")
+	assert.Equal(t, &taskDeferral{Reason: "maximum parallelism of workflow wf-limited reached"}, err)
+	assert.Equal(t, 1, admission.InFlight())
+	assert.NoError(t, c.submitTask(unlimited, "a"))
+
+	// The in-flight task limit defers all tasks.
+	err = c.submitTask(unlimited, "b")
+	assert.Equal(t, &taskDeferral{Reason: "in-flight task limit reached", InFlightLimit: true}, err)
+	assert.Equal(t, 2, admission.InFlight())
+	assert.Equal(t, 1, c.config.parallelism.InFlight("wf-limited"))
+
+	// The limits are released if the executor does not accept the task.
+	admission.Release()
+	exec.Close()
+	err = c.submitTask(unlimited, "b")
+	assert.Equal(t, &taskDeferral{Reason: "executor did not accept the task"}, err)
+	assert.Equal(t, 1, admission.InFlight())
+}
+
+func TestEval_ReplayAfterDeadline(t *testing.T) {
+	// Task "flaky" fails on its first run, and succeeds on its replay, unless it is given a deadline that has passed.
+	var runs int32
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["flaky"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		deadline, err := ptypes.Timestamp(spec.GetDeadline())
+		if err != nil || !deadline.After(time.Now()) {
+			return nil, errors.New("deadline exceeded")
+		}
+		if atomic.AddInt32(&runs, 1) == 1 {
+			return nil, errors.New("flaky failure")
+		}
+		return typedvalues.MustWrap("ok"), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("flaky", &types.TaskSpec{FunctionRef: "flaky"})
+	wfSpec.OutputTask = "flaky"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"flaky": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "flaky"}}},
+	}}
+	deadline := time.Now().Add(500 * time.Millisecond)
+	spec := types.NewWorkflowInvocationSpec("wf", deadline)
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	run := func() *types.WorkflowInvocation {
+		c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+			scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(),
+			opentracing.StartSpan("wi"), TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+		var invocation *types.WorkflowInvocation
+		for i := 0; i < 10; i++ {
+			invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+			assert.NoError(t, err)
+			entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+			assert.NoError(t, err)
+			invocation = entity.(*types.WorkflowInvocation)
+			if invocation.GetStatus().Finished() {
+				break
+			}
+			c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+			for j := 0; j < 100 && exec.GetGroupTasks(invocationID) > 0; j++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+		return invocation
+	}
+
+	invocation := run()
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, invocation.GetStatus().GetStatus())
+
+	// The replay is given the runtime of the invocation again, so its task runs do not time out immediately.
+	time.Sleep(time.Until(deadline) + 50*time.Millisecond)
+	_, err = invocationAPI.ReplayFailedTasks(invocationID, nil)
+	assert.NoError(t, err)
+	invocation = run()
+	assert.Equal(t, types.WorkflowInvocationStatus_SUCCEEDED, invocation.GetStatus().GetStatus())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+	taskDeadline, err := ptypes.Timestamp(invocation.GetStatus().GetTasks()["flaky"].GetSpec().GetDeadline())
+	assert.NoError(t, err)
+	assert.True(t, taskDeadline.After(deadline))
+}
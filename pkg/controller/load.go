@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	metricOverloaded = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "overloaded",
+		Help:      "Whether the load of the invocation controller exceeds the load thresholds (1) or not (0)",
+	})
+	metricEvalQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "eval_queue_depth",
+		Help:      "Number of evaluations in the evaluation queue, as last observed by the load gate",
+	})
+	metricExecutorUtilization = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "executor_utilization",
+		Help:      "Fraction of the executor workers that are busy, as last observed by the load gate",
+	})
+	metricDeferredEvaluations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "load_deferred_evaluations_total",
+		Help:      "Number of evaluations of low-priority invocations that were deferred due to the controller load",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(metricOverloaded, metricEvalQueueDepth, metricExecutorUtilization,
+		metricDeferredEvaluations)
+}
+
+// LoadThresholds configures the load above which the scheduling of low-priority invocations is deferred.
+type LoadThresholds struct {
+	// MaxQueueDepth is the number of queued evaluations above which the controller is overloaded. If 0, the depth of
+	// the evaluation queue is not considered.
+	MaxQueueDepth int
+
+	// MaxUtilization is the fraction (0-1) of busy executor workers above which the controller is overloaded. If 0,
+	// the utilization of the executor is not considered.
+	MaxUtilization float64
+}
+
+// Enabled returns whether any of the thresholds has been set.
+func (t LoadThresholds) Enabled() bool {
+	return t.MaxQueueDepth > 0 || t.MaxUtilization > 0
+}
+
+// LoadGate defers the scheduling of low-priority invocations while the controller is overloaded, which provides
+// backpressure without rejecting these invocations. High-priority and normal invocations are always admitted.
+//
+// A nil LoadGate admits all invocations.
+type LoadGate struct {
+	thresholds  LoadThresholds
+	queueDepth  func() int
+	utilization func() float64
+}
+
+// NewLoadGate creates a LoadGate that observes the load using the provided functions.
+func NewLoadGate(thresholds LoadThresholds, queueDepth func() int, utilization func() float64) *LoadGate {
+	return &LoadGate{
+		thresholds:  thresholds,
+		queueDepth:  queueDepth,
+		utilization: utilization,
+	}
+}
+
+// Overloaded returns whether the current load exceeds any of the thresholds.
+func (g *LoadGate) Overloaded() bool {
+	if g == nil {
+		return false
+	}
+	depth := g.queueDepth()
+	utilization := g.utilization()
+	metricEvalQueueDepth.Set(float64(depth))
+	metricExecutorUtilization.Set(utilization)
+
+	overloaded := (g.thresholds.MaxQueueDepth > 0 && depth > g.thresholds.MaxQueueDepth) ||
+		(g.thresholds.MaxUtilization > 0 && utilization > g.thresholds.MaxUtilization)
+	if overloaded {
+		metricOverloaded.Set(1)
+	} else {
+		metricOverloaded.Set(0)
+	}
+	return overloaded
+}
+
+// Admit returns whether the tasks of the invocation can be scheduled. Low-priority invocations are not admitted
+// while the controller is overloaded. The effective priority of the invocation is used, so sub-workflow invocations
+// of a higher-priority parent are admitted regardless of their own priority. The load is observed on every call,
+// which keeps the load metrics up to date.
+func (g *LoadGate) Admit(invocation *types.WorkflowInvocation) bool {
+	if g == nil {
+		return true
+	}
+	if g.Overloaded() && invocation.Priority() == types.PriorityLow {
+		metricDeferredEvaluations.Inc()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPrioritizedInvocation(priority string) *types.WorkflowInvocation {
+	spec := &types.WorkflowInvocationSpec{}
+	if len(priority) > 0 {
+		spec.Labels = map[string]string{types.LabelPriority: priority}
+	}
+	return &types.WorkflowInvocation{Spec: spec}
+}
+
+func TestLoadGate(t *testing.T) {
+	depth, utilization := 0, 0.0
+	gate := NewLoadGate(LoadThresholds{MaxQueueDepth: 10, MaxUtilization: 0.8},
+		func() int { return depth }, func() float64 { return utilization })
+	low := newPrioritizedInvocation(types.PriorityLow)
+	normal := newPrioritizedInvocation("")
+	high := newPrioritizedInvocation(types.PriorityHigh)
+
+	assert.False(t, gate.Overloaded())
+	assert.True(t, gate.Admit(low))
+
+	// Either threshold overloads the controller, which only defers low-priority invocations.
+	depth = 11
+	assert.True(t, gate.Overloaded())
+	assert.False(t, gate.Admit(low))
+	assert.True(t, gate.Admit(normal))
+	assert.True(t, gate.Admit(high))
+
+	depth, utilization = 10, 0.9
+	assert.False(t, gate.Admit(low))
+	assert.True(t, gate.Admit(high))
+
+	utilization = 0.8
+	assert.True(t, gate.Admit(low))
+}
+
+func TestLoadGate_Nil(t *testing.T) {
+	var gate *LoadGate
+	assert.False(t, gate.Overloaded())
+	assert.True(t, gate.Admit(newPrioritizedInvocation(types.PriorityLow)))
+	assert.False(t, LoadThresholds{}.Enabled())
+}
+
+func TestLoadGate_InheritedPriority(t *testing.T) {
+	gate := NewLoadGate(LoadThresholds{MaxQueueDepth: 1}, func() int { return 2 }, func() float64 { return 0 })
+
+	// A low-priority sub-workflow invocation of a normal or high-priority parent is not deferred.
+	child := newPrioritizedInvocation(types.PriorityLow)
+	child.Spec.ParentPriority = types.PriorityHigh
+	assert.True(t, gate.Admit(child))
+
+	child.Spec.ParentPriority = types.PriorityLow
+	assert.False(t, gate.Admit(child))
+
+	// The priority recorded in the status takes precedence over the spec.
+	child.Status = &types.WorkflowInvocationStatus{EffectivePriority: types.PriorityNormal}
+	assert.True(t, gate.Admit(child))
+}
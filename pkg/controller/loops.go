@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxReportedLoops is the maximum number of loops that are named in the error of an invocation that exceeded its
+// loop iteration budget.
+const maxReportedLoops = 5
+
+var ErrLoopIterationsExceeded = errors.New("loop iteration budget exceeded")
+
+var metricLoopIterationsExceeded = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "loop_iterations_exceeded_total",
+	Help:      "Number of invocations that were failed for exceeding the loop iteration budget",
+})
+
+func init() {
+	prometheus.MustRegister(metricLoopIterationsExceeded)
+}
+
+// LoopBudget limits the total number of loop iterations of an invocation, across all of its loops, including the
+// loops nested in its sub-invocations.
+//
+// The limit of each individual loop does not protect against nested loops, of which the iterations multiply: a
+// foreach over 1000 items of which each item repeats a task 1000 times stays within the limits of both loops, but
+// schedules a million tasks. As the nested loops run in sub-invocations, the iterations are accounted to the
+// top-level invocation that they descend from.
+//
+// The budget is tracked in memory, based on the loop iterations in the status of the invocations. After a restart,
+// the iterations of an invocation are accounted again once it is evaluated.
+//
+// A nil LoopBudget does not limit the loop iterations.
+type LoopBudget struct {
+	max int64
+
+	// roots contains the top-level invocation of each known invocation.
+	roots map[string]string
+
+	// iterations contains the loop iterations per invocation, grouped by their top-level invocation.
+	iterations map[string]map[string]map[string]int64
+	mu         *sync.Mutex
+}
+
+// NewLoopBudget returns a budget of max loop iterations per top-level invocation, or nil if max is not positive.
+func NewLoopBudget(max int64) *LoopBudget {
+	if max <= 0 {
+		return nil
+	}
+	return &LoopBudget{
+		max:        max,
+		roots:      map[string]string{},
+		iterations: map[string]map[string]map[string]int64{},
+		mu:         &sync.Mutex{},
+	}
+}
+
+// Observe records the loop iterations of the invocation, and returns an error if the top-level invocation that it
+// descends from has exceeded the budget.
+func (b *LoopBudget) Observe(invocation *types.WorkflowInvocation) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	root := b.root(invocation)
+	tree, ok := b.iterations[root]
+	if !ok {
+		tree = map[string]map[string]int64{}
+		b.iterations[root] = tree
+	}
+	tree[invocation.ID()] = invocation.GetStatus().GetLoopIterations()
+
+	var total int64
+	for _, loops := range tree {
+		for _, n := range loops {
+			total += n
+		}
+	}
+	if total <= b.max {
+		return nil
+	}
+	metricLoopIterationsExceeded.Inc()
+	return fmt.Errorf("%v: started %d loop iterations (budget: %d) in loops %s", ErrLoopIterationsExceeded, total,
+		b.max, formatLoops(tree))
+}
+
+// Forget removes the loop iterations of the invocation. If the invocation is a top-level invocation, the iterations
+// of its sub-invocations are removed as well.
+func (b *LoopBudget) Forget(invocation *types.WorkflowInvocation) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(invocation.GetSpec().GetParentId()) != 0 {
+		return
+	}
+	for invocationID := range b.iterations[invocation.ID()] {
+		delete(b.roots, invocationID)
+	}
+	delete(b.iterations, invocation.ID())
+	delete(b.roots, invocation.ID())
+}
+
+// root returns the top-level invocation of the invocation. The caller should hold the lock.
+func (b *LoopBudget) root(invocation *types.WorkflowInvocation) string {
+	if root, ok := b.roots[invocation.ID()]; ok {
+		return root
+	}
+	root := invocation.ID()
+	if parentID := invocation.GetSpec().GetParentId(); len(parentID) != 0 {
+		// The parent is evaluated before its sub-invocations are created, so it is normally known.
+		root = parentID
+		if parentRoot, ok := b.roots[parentID]; ok {
+			root = parentRoot
+		}
+	}
+	b.roots[invocation.ID()] = root
+	return root
+}
+
+// formatLoops formats the loops with the most iterations as '<invocation>/<task> (<iterations>)'.
+func formatLoops(tree map[string]map[string]int64) string {
+	type loop struct {
+		id         string
+		iterations int64
+	}
+	var loops []loop
+	for invocationID, iterations := range tree {
+		for taskID, n := range iterations {
+			loops = append(loops, loop{id: invocationID + "/" + taskID, iterations: n})
+		}
+	}
+	sort.Slice(loops, func(i, j int) bool {
+		if loops[i].iterations != loops[j].iterations {
+			return loops[i].iterations > loops[j].iterations
+		}
+		return loops[i].id < loops[j].id
+	})
+	var formatted []string
+	for i, l := range loops {
+		if i == maxReportedLoops {
+			formatted = append(formatted, fmt.Sprintf("and %d more", len(loops)-maxReportedLoops))
+			break
+		}
+		formatted = append(formatted, fmt.Sprintf("%s (%d)", l.id, l.iterations))
+	}
+	return strings.Join(formatted, ", ")
+}
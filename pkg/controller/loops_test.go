@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLoopInvocation(id, parentID string, iterations map[string]int64) *types.WorkflowInvocation {
+	return &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{Id: id},
+		Spec:     &types.WorkflowInvocationSpec{ParentId: parentID},
+		Status:   &types.WorkflowInvocationStatus{LoopIterations: iterations},
+	}
+}
+
+func TestLoopBudget(t *testing.T) {
+	budget := NewLoopBudget(10)
+
+	// The iterations of nested loops in sub-invocations are accounted to the top-level invocation.
+	assert.NoError(t, budget.Observe(newLoopInvocation("wi", "", map[string]int64{"outer": 3})))
+	assert.NoError(t, budget.Observe(newLoopInvocation("wi-1", "wi", map[string]int64{"inner": 3})))
+	assert.NoError(t, budget.Observe(newLoopInvocation("wi-2", "wi", map[string]int64{"inner": 3})))
+	assert.NoError(t, budget.Observe(newLoopInvocation("wi-2-1", "wi-2", map[string]int64{"inner": 1})))
+
+	// Re-observing an invocation replaces its iterations.
+	assert.NoError(t, budget.Observe(newLoopInvocation("wi", "", map[string]int64{"outer": 3})))
+
+	// Other top-level invocations have their own budget.
+	assert.NoError(t, budget.Observe(newLoopInvocation("other", "", map[string]int64{"loop": 10})))
+
+	err := budget.Observe(newLoopInvocation("wi-3", "wi", map[string]int64{"inner": 1}))
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), ErrLoopIterationsExceeded.Error()))
+	assert.Contains(t, err.Error(), "started 11 loop iterations (budget: 10)")
+	assert.Contains(t, err.Error(), "wi-1/inner (3), wi-2/inner (3), wi/outer (3)")
+
+	// Once the top-level invocation has finished, its sub-invocations are forgotten.
+	budget.Forget(newLoopInvocation("wi-3", "wi", nil))
+	assert.Error(t, budget.Observe(newLoopInvocation("wi-3", "wi", map[string]int64{"inner": 1})))
+	budget.Forget(newLoopInvocation("wi", "", nil))
+	assert.Equal(t, map[string]string{"other": "other"}, budget.roots)
+	assert.NoError(t, budget.Observe(newLoopInvocation("wi-3", "wi", map[string]int64{"inner": 1})))
+
+	// A nil budget does not limit the iterations.
+	assert.Nil(t, NewLoopBudget(0))
+	var unlimited *LoopBudget
+	assert.NoError(t, unlimited.Observe(newLoopInvocation("wi", "", map[string]int64{"loop": 1000})))
+	unlimited.Forget(newLoopInvocation("wi", "", nil))
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultMemoSize is the default maximum number of memoized task outputs.
+const DefaultMemoSize = 1000
+
+var metricMemoLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "memo_lookups_total",
+	Help:      "Number of lookups of memoized outputs of pure tasks by result (hit or miss)",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(metricMemoLookups)
+}
+
+// TaskMemo memoizes the outputs of the successful runs of pure tasks (see TaskSpec.Pure), across invocations. A run
+// of a pure task with the same inputs as a memoized run reuses the output of that run, rather than invoking the
+// function again. The outputs of tasks that are not pure are never memoized, as their outputs can depend on more than
+// their inputs, and running them can have side effects.
+//
+// The memo is bounded; the least recently used outputs are evicted first. A nil TaskMemo does not memoize any output.
+type TaskMemo struct {
+	cache *lru.Cache // memo key -> *types.TaskInvocationStatus
+}
+
+// NewTaskMemo creates a memo of at most size outputs. If size is not positive, DefaultMemoSize is used.
+func NewTaskMemo(size int) *TaskMemo {
+	if size <= 0 {
+		size = DefaultMemoSize
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	return &TaskMemo{cache: cache}
+}
+
+// Get returns the memoized result for the key. An empty key is never memoized.
+func (m *TaskMemo) Get(key string) (*types.TaskInvocationStatus, bool) {
+	if m == nil || len(key) == 0 {
+		return nil, false
+	}
+	val, ok := m.cache.Get(key)
+	if !ok {
+		metricMemoLookups.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	metricMemoLookups.WithLabelValues("hit").Inc()
+	return val.(*types.TaskInvocationStatus), true
+}
+
+// Put memoizes the result for the key, if the result is successful.
+func (m *TaskMemo) Put(key string, result *types.TaskInvocationStatus) {
+	if m == nil || len(key) == 0 || !result.Successful() {
+		return
+	}
+	m.cache.Add(key, &types.TaskInvocationStatus{
+		Status:        types.TaskInvocationStatus_SUCCEEDED,
+		Output:        proto.Clone(result.GetOutput()).(*typedvalues.TypedValue),
+		OutputHeaders: proto.Clone(result.GetOutputHeaders()).(*typedvalues.TypedValue),
+	})
+}
+
+// Len returns the number of memoized outputs.
+func (m *TaskMemo) Len() int {
+	if m == nil {
+		return 0
+	}
+	return m.cache.Len()
+}
+
+// memoKey returns the key under which the output of the run of the task is memoized, or an empty key if the task is
+// not pure. As the transformations of the output, such as the output path, are part of the task, the runs of the same
+// task in the same version of the workflow are identical if they invoke the same function with the same inputs.
+func memoKey(invocation *types.WorkflowInvocation, task *types.Task, spec *types.TaskInvocationSpec,
+	inputsHash string) string {
+	if !task.GetSpec().GetPure() || len(inputsHash) == 0 {
+		return ""
+	}
+	wf := invocation.Workflow()
+	return fmt.Sprintf("%s@%d/%s/%s/%s", wf.ID(), wf.GetMetadata().GetGeneration(), spec.GetTaskId(),
+		spec.GetFnRef().Format(), inputsHash)
+}
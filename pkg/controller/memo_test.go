@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskMemo(t *testing.T) {
+	memo := NewTaskMemo(2)
+	memo.Put("failed", &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_FAILED})
+	_, ok := memo.Get("failed")
+	assert.False(t, ok)
+
+	memo.Put("", &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_SUCCEEDED})
+	assert.Equal(t, 0, memo.Len())
+
+	memo.Put("a", &types.TaskInvocationStatus{
+		Status: types.TaskInvocationStatus_SUCCEEDED,
+		Output: typedvalues.MustWrap("foo"),
+	})
+	result, ok := memo.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "foo", typedvalues.MustUnwrap(result.GetOutput()))
+
+	// A nil memo does not memoize anything.
+	var nilMemo *TaskMemo
+	nilMemo.Put("a", result)
+	_, ok = nilMemo.Get("a")
+	assert.False(t, ok)
+}
+
+func TestPureTaskMemoization(t *testing.T) {
+	calls := map[string]int{}
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	for _, fn := range []string{"hash", "send"} {
+		fn := fn
+		runtime.Functions[fn] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+			calls[fn]++
+			return typedvalues.MustWrap(fn + "-output"), nil
+		}
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("hash", &types.TaskSpec{FunctionRef: "hash", Pure: true, Inputs: types.Input("foo")})
+	wfSpec.AddTask("send", &types.TaskSpec{FunctionRef: "send", Inputs: types.Input("foo")})
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{}}
+	for taskID, taskSpec := range wfSpec.Tasks {
+		wfStatus.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "mock", ID: taskSpec.FunctionRef},
+		}}
+	}
+	config := InvocationConfig{Memo: NewTaskMemo(10)}
+
+	invoke := func() *types.WorkflowInvocation {
+		spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+		spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+		invocationID, err := invocationAPI.Invoke(spec)
+		assert.NoError(t, err)
+		c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+			scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(),
+			opentracing.StartSpan("wi"), TraceDecision{}, logrus.WithField("key", "wi"), config)
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		c.Eval(context.Background(), &ctrl.Event{Updated: entity.(*types.WorkflowInvocation)})
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		invocationEvents, err = backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err = projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+
+	invoke()
+	assert.Equal(t, map[string]int{"hash": 1, "send": 1}, calls)
+	assert.Equal(t, 1, config.Memo.Len())
+
+	// The pure task reuses the memoized output, whereas the other task is executed again.
+	invocation := invoke()
+	assert.Equal(t, map[string]int{"hash": 1, "send": 2}, calls)
+	taskRun := invocation.GetStatus().GetTasks()["hash"]
+	assert.True(t, taskRun.GetStatus().Successful())
+	assert.Equal(t, "hash-output", typedvalues.MustUnwrap(taskRun.GetStatus().GetOutput()))
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/configmap"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+// MergeConflictError indicates that the sources of an input merge with the error conflict resolution have different
+// values at the same path.
+type MergeConflictError struct {
+	Input string
+	Path  []string
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("conflicting values at '%s' in the merge of input %s", strings.Join(e.Path, "."), e.Input)
+}
+
+// resolveInputMerges resolves the sources of the input merges of the task, deep-merges them in order, and binds the
+// results to the inputs of the task in the scope. The merges should not include the inputs that are overridden by
+// explicit inputs of the task (see types.TaskSpec.EffectiveMergeInputs). Sources that resolve to null are skipped, so
+// that the outputs of optional dependencies can be merged.
+func resolveInputMerges(scope *expr.Scope, taskID string, merges map[string]*types.InputMerge,
+	resolvedInputs map[string]*typedvalues.TypedValue) error {
+	inputKeys := make([]string, 0, len(merges))
+	for key := range merges {
+		inputKeys = append(inputKeys, key)
+	}
+	sort.Strings(inputKeys)
+
+	for _, key := range inputKeys {
+		merge := merges[key]
+		merged := map[string]interface{}{}
+		for i, src := range merge.GetSources() {
+			resolved, err := expr.Resolve(scope, taskID, src)
+			if err != nil {
+				return fmt.Errorf("failed to resolve source %d of the merge of input %v: %v", i, key, err)
+			}
+			val, err := typedvalues.Unwrap(resolved)
+			if err != nil {
+				return fmt.Errorf("failed to resolve source %d of the merge of input %v: %v", i, key, err)
+			}
+			if val == nil {
+				continue
+			}
+			obj, ok := val.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("source %d of the merge of input %v is not an object: %T", i, key, val)
+			}
+			if err := mergeInto(merged, obj, merge.GetConflicts(), nil); err != nil {
+				if conflict, ok := err.(*MergeConflictError); ok {
+					conflict.Input = key
+				}
+				return err
+			}
+		}
+		resolved, err := typedvalues.Wrap(merged)
+		if err != nil {
+			return fmt.Errorf("failed to bind the merge of input %v: %v", key, err)
+		}
+		resolvedInputs[key] = resolved
+		scope.Tasks[taskID].Inputs[key] = merged
+	}
+	return nil
+}
+
+// resolveMergeConfigMaps replaces the references to config maps in the sources of the input merges with the referenced
+// values. The merges of the task spec are not modified.
+func resolveMergeConfigMaps(resolver *configmap.Resolver,
+	merges map[string]*types.InputMerge) (map[string]*types.InputMerge, error) {
+	resolved := make(map[string]*types.InputMerge, len(merges))
+	for key, merge := range merges {
+		sources := make([]*typedvalues.TypedValue, len(merge.GetSources()))
+		for i, src := range merge.GetSources() {
+			val, err := resolver.ResolveInput(src)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve source %d of the merge of input %v: %v", i, key, err)
+			}
+			sources[i] = val
+		}
+		resolved[key] = &types.InputMerge{Sources: sources, Conflicts: merge.GetConflicts()}
+	}
+	return resolved, nil
+}
+
+// mergeInto deep-merges src into dst. Nested objects are merged recursively; other values of src replace the values
+// of dst, unless the conflict resolution is error and the values differ.
+func mergeInto(dst, src map[string]interface{}, conflicts string, path []string) error {
+	for k, v := range src {
+		existing, ok := dst[k]
+		if !ok {
+			dst[k] = copyValue(v)
+			continue
+		}
+		existingObj, existingIsObj := existing.(map[string]interface{})
+		obj, isObj := v.(map[string]interface{})
+		if existingIsObj && isObj {
+			if err := mergeInto(existingObj, obj, conflicts, append(path, k)); err != nil {
+				return err
+			}
+			continue
+		}
+		if conflicts == types.MergeConflictError && !reflect.DeepEqual(existing, v) {
+			return &MergeConflictError{Path: append(append([]string{}, path...), k)}
+		}
+		dst[k] = copyValue(v)
+	}
+	return nil
+}
+
+// copyValue copies nested objects, so that merging into them does not modify the sources.
+func copyValue(v interface{}) interface{} {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	cp := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		cp[k] = copyValue(v)
+	}
+	return cp
+}
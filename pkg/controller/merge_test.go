@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/configmap"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeInto(t *testing.T) {
+	dst := map[string]interface{}{}
+	defaults := map[string]interface{}{
+		"retries": 1,
+		"http":    map[string]interface{}{"timeout": "1s", "headers": map[string]interface{}{"accept": "json"}},
+	}
+	overrides := map[string]interface{}{
+		"http":  map[string]interface{}{"timeout": "5s", "headers": map[string]interface{}{"auth": "token"}},
+		"debug": true,
+	}
+	assert.NoError(t, mergeInto(dst, defaults, types.MergeConflictLastWins, nil))
+	assert.NoError(t, mergeInto(dst, overrides, types.MergeConflictLastWins, nil))
+	assert.Equal(t, map[string]interface{}{
+		"retries": 1,
+		"debug":   true,
+		"http": map[string]interface{}{
+			"timeout": "5s",
+			"headers": map[string]interface{}{"accept": "json", "auth": "token"},
+		},
+	}, dst)
+
+	// The sources are not modified by the merge.
+	assert.Equal(t, map[string]interface{}{"accept": "json"},
+		defaults["http"].(map[string]interface{})["headers"])
+
+	// With the error conflict resolution, only different values at the same path conflict.
+	dst = map[string]interface{}{}
+	assert.NoError(t, mergeInto(dst, defaults, types.MergeConflictError, nil))
+	assert.NoError(t, mergeInto(dst, map[string]interface{}{"retries": 1}, types.MergeConflictError, nil))
+	err := mergeInto(dst, overrides, types.MergeConflictError, nil)
+	assert.IsType(t, &MergeConflictError{}, err)
+	assert.Equal(t, []string{"http", "timeout"}, err.(*MergeConflictError).Path)
+
+	// An object does not merge with a value that is not an object.
+	err = mergeInto(dst, map[string]interface{}{"http": "none"}, types.MergeConflictError, nil)
+	assert.Equal(t, []string{"http"}, err.(*MergeConflictError).Path)
+	assert.NoError(t, mergeInto(dst, map[string]interface{}{"http": "none"}, types.MergeConflictLastWins, nil))
+	assert.Equal(t, "none", dst["http"])
+}
+
+func TestResolveInputs_MergeInputs(t *testing.T) {
+	c := NewInvocationController("wi", nil, nil, nil, nil, expr.NewStore(), nil, TraceDecision{},
+		logrus.WithField("key", "wi"),
+		InvocationConfig{})
+	invocation := setupRaceInvocation(nil, "mirrorA", "mirrorB", "mirrorC")
+	invocation.Status.Tasks["mirrorA"].Status.Output = typedvalues.MustWrap(map[string]interface{}{
+		"user": map[string]interface{}{"name": "alice", "role": "viewer"},
+	})
+	invocation.Status.Tasks["mirrorB"].Status.Output = typedvalues.MustWrap(map[string]interface{}{
+		"user": map[string]interface{}{"role": "admin"},
+	})
+	invocation.Status.Tasks["mirrorC"].Status.Output = typedvalues.MustWrap(nil)
+
+	spec := &types.TaskSpec{
+		FunctionRef: "noop",
+		Inputs: map[string]*typedvalues.TypedValue{
+			"explicit": typedvalues.MustWrap("explicit"),
+		},
+		MergeInputs: map[string]*types.InputMerge{
+			types.InputMain: {
+				Sources: []*typedvalues.TypedValue{
+					typedvalues.MustWrap("{ output('mirrorA') }"),
+					typedvalues.MustWrap("{ output('mirrorC') }"),
+					typedvalues.MustWrap("{ output('mirrorB') }"),
+				},
+			},
+			// Explicit inputs take precedence over merged inputs.
+			"explicit": {
+				Sources: []*typedvalues.TypedValue{typedvalues.MustWrap("{ output('mirrorA') }")},
+			},
+		},
+		Requires: map[string]*types.TaskDependencyParameters{
+			"mirrorA": {},
+			"mirrorB": {},
+			"mirrorC": {},
+		},
+	}
+	invocation.Spec.Workflow.Spec.AddTask("consumer", spec)
+	invocation.Spec.Workflow.Status.Tasks = map[string]*types.Task{}
+	for taskID := range invocation.Spec.Workflow.Spec.Tasks {
+		invocation.Spec.Workflow.Status.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{}}
+	}
+
+	inputs, err := c.resolveInputs(invocation, "consumer", spec)
+	assert.NoError(t, err)
+	assert.Len(t, inputs, 2)
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{"name": "alice", "role": "admin"},
+	}, typedvalues.MustUnwrap(inputs[types.InputMain]))
+	assert.Equal(t, "explicit", typedvalues.MustUnwrap(inputs["explicit"]))
+
+	// With the error conflict resolution, the conflicting roles fail the resolution.
+	spec.MergeInputs[types.InputMain].Conflicts = types.MergeConflictError
+	_, err = c.resolveInputs(invocation, "consumer", spec)
+	assert.IsType(t, &MergeConflictError{}, err)
+	assert.Equal(t, types.InputMain, err.(*MergeConflictError).Input)
+
+	// Sources that are not objects cannot be merged.
+	spec.MergeInputs[types.InputMain].Sources = []*typedvalues.TypedValue{typedvalues.MustWrap("scalar")}
+	_, err = c.resolveInputs(invocation, "consumer", spec)
+	assert.Error(t, err)
+}
+
+type staticConfigMaps map[string]map[string]string
+
+func (g staticConfigMaps) Get(namespace string, name string) (map[string]string, error) {
+	return g[name], nil
+}
+
+func TestResolveMergeConfigMaps(t *testing.T) {
+	resolver := configmap.NewResolver(staticConfigMaps{"endpoints": {"api": "http://api.example.com"}},
+		configmap.Config{Namespace: "default", Allowed: []string{"endpoints"}})
+	ref := typedvalues.MustWrap(map[string]interface{}{configmap.ReferenceKey: "endpoints/api"})
+	merges := map[string]*types.InputMerge{
+		types.InputMain: {Sources: []*typedvalues.TypedValue{ref}, Conflicts: types.MergeConflictError},
+	}
+
+	resolved, err := resolveMergeConfigMaps(resolver, merges)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://api.example.com", typedvalues.MustUnwrap(resolved[types.InputMain].GetSources()[0]))
+	assert.Equal(t, types.MergeConflictError, resolved[types.InputMain].GetConflicts())
+	// The merges of the task spec are not modified.
+	assert.Equal(t, ref, merges[types.InputMain].GetSources()[0])
+
+	merges[types.InputMain].Sources = []*typedvalues.TypedValue{
+		typedvalues.MustWrap(map[string]interface{}{configmap.ReferenceKey: "secret/password"}),
+	}
+	_, err = resolveMergeConfigMaps(resolver, merges)
+	assert.Error(t, err)
+}
+
+func TestEval_MergeOnlyInputs(t *testing.T) {
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["defaults"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap(map[string]interface{}{"retries": 1, "debug": false}), nil
+	}
+	runtime.Functions["overrides"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap(map[string]interface{}{"debug": true}), nil
+	}
+	runtime.Functions["echo"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return spec.GetInputs()[types.InputMain], nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	// The config task has no explicit inputs; its main input is only defined by the merge.
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("defaults", &types.TaskSpec{FunctionRef: "defaults"})
+	wfSpec.AddTask("overrides", &types.TaskSpec{FunctionRef: "overrides"})
+	wfSpec.AddTask("config", &types.TaskSpec{
+		FunctionRef: "echo",
+		Requires:    types.Require("defaults", "overrides"),
+		MergeInputs: map[string]*types.InputMerge{
+			types.InputMain: {
+				Sources: []*typedvalues.TypedValue{
+					typedvalues.MustWrap("{ output('defaults') }"),
+					typedvalues.MustWrap("{ output('overrides') }"),
+				},
+			},
+		},
+	})
+	wfSpec.OutputTask = "config"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{}}
+	for taskID, taskSpec := range wfSpec.Tasks {
+		wfStatus.Tasks[taskID] = &types.Task{Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "mock", ID: taskSpec.FunctionRef},
+		}}
+	}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	eval := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		invocation := entity.(*types.WorkflowInvocation)
+		c.Eval(context.Background(), &ctrl.Event{Updated: invocation})
+		for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return invocation
+	}
+
+	eval()
+	eval()
+	invocation := eval()
+	run, ok := invocation.GetStatus().GetTasks()["config"]
+	assert.True(t, ok)
+	assert.True(t, run.GetStatus().Successful())
+	assert.Equal(t, map[string]interface{}{"retries": int32(1), "debug": true},
+		typedvalues.MustUnwrap(run.GetStatus().GetOutput()))
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	// MiddlewareCorrelationID is the name of the built-in input middleware that adds the correlation ID of the
+	// invocation to the headers of its tasks.
+	MiddlewareCorrelationID = "correlation-id"
+
+	// HeaderCorrelationID is the header that contains the correlation ID.
+	HeaderCorrelationID = "X-Correlation-Id"
+
+	// LabelCorrelationID is the invocation label that contains the correlation ID of the invocation. Invocations
+	// without the label are correlated by their ID.
+	LabelCorrelationID = "correlation-id"
+)
+
+// InputMiddleware transforms the resolved inputs of a task run before it is submitted, such as by adding headers or
+// redacting inputs. It can inspect and modify the inputs of the task run spec, which are never nil.
+type InputMiddleware interface {
+	Apply(invocation *types.WorkflowInvocation, spec *types.TaskInvocationSpec) error
+}
+
+// InputMiddlewareFunc is an InputMiddleware that is implemented by a function.
+type InputMiddlewareFunc func(invocation *types.WorkflowInvocation, spec *types.TaskInvocationSpec) error
+
+func (fn InputMiddlewareFunc) Apply(invocation *types.WorkflowInvocation, spec *types.TaskInvocationSpec) error {
+	return fn(invocation, spec)
+}
+
+// InputMiddlewares contains the registered input middleware, and the chain of middleware that is applied to the
+// inputs of the tasks of all invocations. Workflows can extend this chain with other registered middleware (see
+// WorkflowSpec.InputMiddleware).
+//
+// The middleware of the controller are applied first, in the configured order, followed by the middleware of the
+// workflow, in the order in which they are listed. Middleware that occur in both are only applied once, as part of
+// the chain of the controller.
+//
+// A nil InputMiddlewares has no middleware of its own, but still applies the built-in middleware of workflows.
+type InputMiddlewares struct {
+	registered map[string]InputMiddleware
+	chain      []string
+}
+
+// NewInputMiddlewares creates the input middleware of the controller, with the chain of the named middleware. The
+// built-in middleware are registered.
+func NewInputMiddlewares(chain []string) (*InputMiddlewares, error) {
+	m := &InputMiddlewares{
+		registered: builtinInputMiddlewares(),
+	}
+	for _, name := range chain {
+		if _, ok := m.registered[name]; !ok {
+			return nil, fmt.Errorf("unknown input middleware '%s'", name)
+		}
+	}
+	m.chain = chain
+	return m, nil
+}
+
+// Register registers the middleware with the name, replacing any middleware with the same name.
+func (m *InputMiddlewares) Register(name string, middleware InputMiddleware) {
+	m.registered[name] = middleware
+}
+
+// Chain returns the names of the middleware that are applied to the tasks of the workflow, in order.
+func (m *InputMiddlewares) Chain(wf *types.WorkflowSpec) []string {
+	var chain []string
+	if m != nil {
+		chain = append(chain, m.chain...)
+	}
+	seen := map[string]bool{}
+	for _, name := range chain {
+		seen[name] = true
+	}
+	for _, name := range wf.GetInputMiddleware() {
+		if !seen[name] {
+			seen[name] = true
+			chain = append(chain, name)
+		}
+	}
+	return chain
+}
+
+// Apply applies the chain of middleware of the workflow of the invocation to the inputs of the task run.
+func (m *InputMiddlewares) Apply(invocation *types.WorkflowInvocation, spec *types.TaskInvocationSpec) error {
+	chain := m.Chain(invocation.Workflow().GetSpec())
+	if len(chain) == 0 {
+		return nil
+	}
+	registered := builtinInputMiddlewares()
+	if m != nil {
+		registered = m.registered
+	}
+	if spec.Inputs == nil {
+		spec.Inputs = map[string]*typedvalues.TypedValue{}
+	}
+	for _, name := range chain {
+		middleware, ok := registered[name]
+		if !ok {
+			return fmt.Errorf("unknown input middleware '%s'", name)
+		}
+		if err := middleware.Apply(invocation, spec); err != nil {
+			return fmt.Errorf("input middleware '%s' failed: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func builtinInputMiddlewares() map[string]InputMiddleware {
+	return map[string]InputMiddleware{
+		MiddlewareCorrelationID: InputMiddlewareFunc(injectCorrelationID),
+	}
+}
+
+// injectCorrelationID adds the correlation ID of the invocation to the headers of the task, unless the task sets the
+// header itself. The correlation ID is the value of the correlation-id label of the invocation, or its ID.
+func injectCorrelationID(invocation *types.WorkflowInvocation, spec *types.TaskInvocationSpec) error {
+	correlationID := invocation.GetSpec().GetLabels()[LabelCorrelationID]
+	if len(correlationID) == 0 {
+		correlationID = invocation.ID()
+	}
+	return SetHeader(spec, HeaderCorrelationID, correlationID, false)
+}
+
+// SetHeader sets the header in the inputs of the task run. Headers are matched case-insensitively; if the header is
+// already present, it is only replaced if override is true.
+func SetHeader(spec *types.TaskInvocationSpec, header string, value string, override bool) error {
+	headers := map[string]interface{}{}
+	if tv, ok := spec.Inputs[types.InputHeaders]; ok && tv != nil {
+		existing, err := typedvalues.UnwrapMap(tv)
+		if err != nil {
+			return fmt.Errorf("headers of task %s are not a map: %v", spec.GetTaskId(), err)
+		}
+		for k, v := range existing {
+			if strings.EqualFold(k, header) {
+				if !override {
+					return nil
+				}
+				continue
+			}
+			headers[k] = v
+		}
+	}
+	headers[header] = value
+	tv, err := typedvalues.Wrap(headers)
+	if err != nil {
+		return err
+	}
+	spec.Inputs[types.InputHeaders] = tv
+	return nil
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMiddlewareInvocation(middleware ...string) *types.WorkflowInvocation {
+	return &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{Id: "wi-123"},
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{Spec: &types.WorkflowSpec{InputMiddleware: middleware}},
+		},
+	}
+}
+
+func appendInput(name string) InputMiddleware {
+	return InputMiddlewareFunc(func(invocation *types.WorkflowInvocation, spec *types.TaskInvocationSpec) error {
+		var applied []interface{}
+		if tv, ok := spec.Inputs["applied"]; ok {
+			applied = typedvalues.MustUnwrap(tv).([]interface{})
+		}
+		spec.Inputs["applied"] = typedvalues.MustWrap(append(applied, name))
+		return nil
+	})
+}
+
+func TestInputMiddlewares_Chain(t *testing.T) {
+	_, err := NewInputMiddlewares([]string{"unknown"})
+	assert.Error(t, err)
+
+	m, err := NewInputMiddlewares([]string{MiddlewareCorrelationID})
+	assert.NoError(t, err)
+	m.Register("a", appendInput("a"))
+	m.Register("b", appendInput("b"))
+
+	// The middleware of the controller are applied first, followed by the middleware of the workflow, in order.
+	invocation := newMiddlewareInvocation("b", MiddlewareCorrelationID, "a")
+	assert.Equal(t, []string{MiddlewareCorrelationID, "b", "a"}, m.Chain(invocation.Workflow().GetSpec()))
+	spec := &types.TaskInvocationSpec{TaskId: "task"}
+	assert.NoError(t, m.Apply(invocation, spec))
+	assert.Equal(t, []interface{}{"b", "a"}, typedvalues.MustUnwrap(spec.Inputs["applied"]))
+	assert.Equal(t, map[string]interface{}{HeaderCorrelationID: "wi-123"},
+		typedvalues.MustUnwrap(spec.Inputs[types.InputHeaders]))
+
+	// Middleware that are not registered fail the task run.
+	err = m.Apply(newMiddlewareInvocation("c"), &types.TaskInvocationSpec{})
+	assert.Error(t, err)
+
+	// Without middleware of the controller, the built-in middleware are still available to workflows.
+	var none *InputMiddlewares
+	spec = &types.TaskInvocationSpec{}
+	assert.NoError(t, none.Apply(newMiddlewareInvocation(), spec))
+	assert.Nil(t, spec.Inputs)
+	assert.NoError(t, none.Apply(newMiddlewareInvocation(MiddlewareCorrelationID), spec))
+	assert.Contains(t, spec.Inputs, types.InputHeaders)
+}
+
+func TestInjectCorrelationID(t *testing.T) {
+	invocation := newMiddlewareInvocation()
+	invocation.Spec.Labels = map[string]string{LabelCorrelationID: "req-1"}
+
+	// Existing headers are retained.
+	spec := &types.TaskInvocationSpec{Inputs: map[string]*typedvalues.TypedValue{
+		types.InputHeaders: typedvalues.MustWrap(map[string]interface{}{"Accept": "application/json"}),
+	}}
+	assert.NoError(t, injectCorrelationID(invocation, spec))
+	assert.Equal(t, map[string]interface{}{"Accept": "application/json", HeaderCorrelationID: "req-1"},
+		typedvalues.MustUnwrap(spec.Inputs[types.InputHeaders]))
+
+	// A correlation ID that is set by the task is not overridden.
+	spec = &types.TaskInvocationSpec{Inputs: map[string]*typedvalues.TypedValue{
+		types.InputHeaders: typedvalues.MustWrap(map[string]interface{}{"x-correlation-id": "custom"}),
+	}}
+	assert.NoError(t, injectCorrelationID(invocation, spec))
+	assert.Equal(t, map[string]interface{}{"x-correlation-id": "custom"},
+		typedvalues.MustUnwrap(spec.Inputs[types.InputHeaders]))
+
+	// Headers that are not a map cannot be extended.
+	spec = &types.TaskInvocationSpec{Inputs: map[string]*typedvalues.TypedValue{
+		types.InputHeaders: typedvalues.MustWrap("foo"),
+	}}
+	assert.Error(t, injectCorrelationID(invocation, spec))
+}
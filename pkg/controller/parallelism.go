@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	metricWorkflowInFlightTasks = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "workflow_inflight_tasks",
+		Help:      "Number of task executions in flight across the invocations of each workflow with a maximum parallelism",
+	}, []string{"workflow"})
+	metricWorkflowMaxParallelism = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "workflow_max_parallelism",
+		Help:      "Maximum number of task executions in flight across the invocations of each workflow",
+	}, []string{"workflow"})
+	metricWorkflowDeferredTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "workflow_deferred_tasks_total",
+		Help: "Number of task executions that were deferred because the maximum parallelism of their workflow " +
+			"was reached",
+	}, []string{"workflow"})
+)
+
+func init() {
+	prometheus.MustRegister(metricWorkflowInFlightTasks, metricWorkflowMaxParallelism, metricWorkflowDeferredTasks)
+}
+
+// WorkflowParallelism bounds the number of concurrent task executions across all invocations of a workflow, as
+// configured by the maxParallelism of the workflow. This protects a downstream resource that the tasks of the workflow
+// share, regardless of the number of invocations.
+//
+// Like EnvironmentLimits, a slot is acquired when a task execution is submitted, and released once the result of the
+// execution has been ingested. The workflow limit is checked before the environment limit and the in-flight task limit
+// of TaskAdmission; a task is only submitted if it is admitted by all of them. The slots are kept in memory, so they
+// are scoped to the controller instance.
+//
+// A nil WorkflowParallelism does not limit any workflow.
+type WorkflowParallelism struct {
+	inFlight map[string]int
+	mu       *sync.Mutex
+}
+
+func NewWorkflowParallelism() *WorkflowParallelism {
+	return &WorkflowParallelism{
+		inFlight: map[string]int{},
+		mu:       &sync.Mutex{},
+	}
+}
+
+// TryAcquire attempts to acquire a slot of the workflow of the invocation, without blocking. It returns the workflow of
+// which a slot was acquired, which is empty if the workflow is not limited, and whether the task can be executed.
+// Every acquired slot should be released with Release.
+func (p *WorkflowParallelism) TryAcquire(invocation *types.WorkflowInvocation) (workflowID string, ok bool) {
+	limit := int(invocation.Workflow().GetSpec().GetMaxParallelism())
+	if p == nil || limit <= 0 {
+		return "", true
+	}
+	workflowID = invocation.GetSpec().GetWorkflowId()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	metricWorkflowMaxParallelism.WithLabelValues(workflowID).Set(float64(limit))
+	if p.inFlight[workflowID] >= limit {
+		metricWorkflowDeferredTasks.WithLabelValues(workflowID).Inc()
+		return workflowID, false
+	}
+	p.inFlight[workflowID]++
+	metricWorkflowInFlightTasks.WithLabelValues(workflowID).Set(float64(p.inFlight[workflowID]))
+	return workflowID, true
+}
+
+// Release releases a slot of the workflow. It is a no-op for an empty workflow.
+func (p *WorkflowParallelism) Release(workflowID string) {
+	if p == nil || len(workflowID) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.inFlight[workflowID] > 1 {
+		p.inFlight[workflowID]--
+	} else {
+		delete(p.inFlight, workflowID)
+	}
+	metricWorkflowInFlightTasks.WithLabelValues(workflowID).Set(float64(p.inFlight[workflowID]))
+}
+
+// InFlight returns the number of acquired slots of the workflow.
+func (p *WorkflowParallelism) InFlight(workflowID string) int {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.inFlight[workflowID]
+}
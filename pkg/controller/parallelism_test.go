@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newParallelismInvocation(workflowID string, maxParallelism int32) *types.WorkflowInvocation {
+	invocation := types.NewWorkflowInvocation(workflowID, "wi-"+workflowID, time.Now().Add(time.Minute))
+	invocation.Spec.Workflow = &types.Workflow{
+		Metadata: types.NewObjectMetadata(workflowID),
+		Spec:     &types.WorkflowSpec{MaxParallelism: maxParallelism},
+	}
+	return invocation
+}
+
+func TestWorkflowParallelism(t *testing.T) {
+	parallelism := NewWorkflowParallelism()
+	limited := newParallelismInvocation("wf-limited", 2)
+
+	// The slots are shared by all invocations of the workflow.
+	wf, ok := parallelism.TryAcquire(limited)
+	assert.True(t, ok)
+	assert.Equal(t, "wf-limited", wf)
+	_, ok = parallelism.TryAcquire(newParallelismInvocation("wf-limited", 2))
+	assert.True(t, ok)
+	_, ok = parallelism.TryAcquire(limited)
+	assert.False(t, ok)
+	assert.Equal(t, 2, parallelism.InFlight("wf-limited"))
+
+	// Workflows without a maximum parallelism are not limited.
+	for i := 0; i < 10; i++ {
+		wf, ok = parallelism.TryAcquire(newParallelismInvocation("wf-unlimited", 0))
+		assert.True(t, ok)
+		assert.Empty(t, wf)
+	}
+
+	parallelism.Release("wf-limited")
+	wf, ok = parallelism.TryAcquire(limited)
+	assert.True(t, ok)
+	parallelism.Release(wf)
+	parallelism.Release(wf)
+	parallelism.Release(wf)
+	assert.Equal(t, 0, parallelism.InFlight("wf-limited"))
+}
+
+func TestWorkflowParallelism_Nil(t *testing.T) {
+	var parallelism *WorkflowParallelism
+	wf, ok := parallelism.TryAcquire(newParallelismInvocation("wf-limited", 1))
+	assert.True(t, ok)
+	assert.Empty(t, wf)
+	parallelism.Release("wf-limited")
+	assert.Equal(t, 0, parallelism.InFlight("wf-limited"))
+}
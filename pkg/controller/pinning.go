@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricPinnedSkips = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "pinned_evaluations_skipped_total",
+	Help:      "Number of evaluations skipped because the invocation is pinned to another controller replica",
+})
+
+func init() {
+	prometheus.MustRegister(metricPinnedSkips)
+}
+
+// Pinning restricts the evaluation of invocations that are labeled with types.LabelReplica to the controller replica
+// with that ID; the other replicas skip these invocations. This allows a developer to attach a debugger to, and tail
+// the logs of, the replica that evaluates a specific invocation while reproducing a bug.
+//
+// Pinning is a debugging aid, not a production feature: an invocation that is pinned to a replica that does not exist
+// is not evaluated by any replica until it exceeds its deadline or is canceled. The other replicas still enforce the
+// deadline and the cancellation of the invocation, so that it does terminate.
+//
+// A nil Pinning ignores the labels, and evaluates all invocations.
+type Pinning struct {
+	// ReplicaID is the ID of this controller replica, which the invocations can be pinned to.
+	ReplicaID string
+}
+
+// Replica returns the replica that the invocation is pinned to, if any.
+func (p *Pinning) Replica(invocation *types.WorkflowInvocation) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	replica, ok := invocation.GetSpec().GetLabels()[types.LabelReplica]
+	return replica, ok && len(replica) > 0
+}
+
+// Skip returns whether this replica should skip the evaluation of the invocation, because it is pinned to another
+// replica.
+func (p *Pinning) Skip(invocation *types.WorkflowInvocation) bool {
+	replica, ok := p.Replica(invocation)
+	if !ok || replica == p.ReplicaID {
+		return false
+	}
+	metricPinnedSkips.Inc()
+	return true
+}
+
+// evalPinnedElsewhere evaluates an invocation that is pinned to another replica. It only fails the invocation once its
+// deadline has been exceeded, and propagates its cancellation to its sub-workflow invocations; the tasks of the
+// invocation are left to the replica that it is pinned to.
+func (c *InvocationController) evalPinnedElsewhere(invocation *types.WorkflowInvocation) ctrl.Result {
+	if invocation.GetStatus().GetStatus() == types.WorkflowInvocationStatus_ABORTED && !c.cancelPropagated {
+		c.cancelPropagated = true
+		c.propagateCancellation(invocation)
+	}
+	if invocation.GetStatus().Finished() {
+		return ctrl.Done{Msg: fmt.Sprintf("invocation is pinned to another controller replica, and in a terminal "+
+			"state (%v)", invocation.GetStatus().GetStatus().String())}
+	}
+	if err := c.checkDeadline(invocation); err != nil {
+		c.fail(invocation.ID(), err)
+		return ctrl.Err{Err: err}
+	}
+	return ctrl.Success{Msg: "invocation is pinned to another controller replica"}
+}
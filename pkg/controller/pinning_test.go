@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func TestPinning(t *testing.T) {
+	pinned := func(replica string) *types.WorkflowInvocation {
+		return &types.WorkflowInvocation{
+			Spec: &types.WorkflowInvocationSpec{Labels: map[string]string{types.LabelReplica: replica}},
+		}
+	}
+	unpinned := &types.WorkflowInvocation{Spec: &types.WorkflowInvocationSpec{}}
+
+	pinning := &Pinning{ReplicaID: "replica-1"}
+	assert.False(t, pinning.Skip(pinned("replica-1")))
+	assert.True(t, pinning.Skip(pinned("replica-2")))
+	assert.False(t, pinning.Skip(pinned("")))
+	assert.False(t, pinning.Skip(unpinned))
+	replica, ok := pinning.Replica(pinned("replica-1"))
+	assert.True(t, ok)
+	assert.Equal(t, "replica-1", replica)
+
+	// Without pinning, the label is ignored.
+	var disabled *Pinning
+	assert.False(t, disabled.Skip(pinned("replica-2")))
+	_, ok = disabled.Replica(pinned("replica-2"))
+	assert.False(t, ok)
+}
+
+func TestEval_PinnedToMissingReplica(t *testing.T) {
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": mock.NewRuntime()}, backend, nil)
+	fakeClock := clock.NewFakeClock(time.Now())
+	exec := executor.NewLocalExecutorWithClock(1, 10, fakeClock)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("task", &types.TaskSpec{FunctionRef: "task"})
+	wfSpec.OutputTask = "task"
+	spec := types.NewWorkflowInvocationSpec("wf", fakeClock.Now().Add(time.Minute))
+	spec.Labels = map[string]string{types.LabelReplica: "replica-missing"}
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec,
+		Status: &types.WorkflowStatus{Tasks: map[string]*types.Task{}}}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{
+			Clock:   fakeClock,
+			Pinning: &Pinning{ReplicaID: "replica-1"},
+		})
+	result := c.Eval(context.Background(), &ctrl.Event{Updated: project()})
+	assert.Equal(t, ctrl.Success{Msg: "invocation is pinned to another controller replica"}, result)
+
+	// The invocation is failed by the other replicas once its deadline has passed.
+	fakeClock.Step(time.Hour)
+	result = c.Eval(context.Background(), &ctrl.Event{Updated: project()})
+	errResult, ok := result.(ctrl.Err)
+	assert.True(t, ok)
+	assert.Contains(t, errResult.Error(), "deadline exceeded")
+	for i := 0; i < 100 && exec.GetGroupTasks(invocationID) > 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	invocation := project()
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, invocation.GetStatus().GetStatus())
+	_, done := c.Eval(context.Background(), &ctrl.Event{Updated: invocation}).(ctrl.Done)
+	assert.True(t, done)
+
+	// A canceled invocation is no longer evaluated either.
+	invocationID, err = invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+	assert.NoError(t, invocationAPI.Cancel(invocationID))
+	c = NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{
+			Clock:   fakeClock,
+			Pinning: &Pinning{ReplicaID: "replica-1"},
+		})
+	_, done = c.Eval(context.Background(), &ctrl.Event{Updated: project()}).(ctrl.Done)
+	assert.True(t, done)
+}
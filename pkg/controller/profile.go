@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+)
+
+// Profile is a preset of the settings of the invocation controller that trade off the freshness of the evaluations
+// against the throughput of the controller.
+type Profile string
+
+const (
+	// ProfileDefault balances freshness and throughput; it is used if no profile is configured.
+	ProfileDefault Profile = "default"
+
+	// ProfileLowLatency evaluates invocations as soon as they are updated, and re-evaluates stale invocations early,
+	// at the cost of more evaluations.
+	ProfileLowLatency Profile = "low-latency"
+
+	// ProfileHighThroughput merges the evaluations of invocations that are updated in quick succession, and checks
+	// for stale invocations less often, at the cost of some latency.
+	ProfileHighThroughput Profile = "high-throughput"
+)
+
+const (
+	DefaultStalenessInterval = 100 * time.Millisecond
+	DefaultMaxStaleness      = time.Second
+)
+
+// ProfileSettings are the settings of the controller that are set by a profile.
+type ProfileSettings struct {
+	// EvalDebounce is the window within which the evaluations of an invocation are merged.
+	EvalDebounce time.Duration
+
+	// StalenessInterval is the interval at which the controller checks for invocations that have not been
+	// evaluated for MaxStaleness, which are re-evaluated.
+	StalenessInterval time.Duration
+	MaxStaleness      time.Duration
+
+	// PollInterval is the interval at which the invocation store is polled for updates that were missed.
+	PollInterval time.Duration
+
+	// ExecutorWorkers is the number of workers of the task executor, which bounds the number of tasks that are
+	// executed in parallel.
+	ExecutorWorkers int
+}
+
+var profiles = map[Profile]ProfileSettings{
+	ProfileDefault: {
+		StalenessInterval: DefaultStalenessInterval,
+		MaxStaleness:      DefaultMaxStaleness,
+		PollInterval:      time.Second,
+		ExecutorWorkers:   1000,
+	},
+	ProfileLowLatency: {
+		StalenessInterval: 50 * time.Millisecond,
+		MaxStaleness:      500 * time.Millisecond,
+		PollInterval:      500 * time.Millisecond,
+		ExecutorWorkers:   2000,
+	},
+	ProfileHighThroughput: {
+		EvalDebounce:      100 * time.Millisecond,
+		StalenessInterval: time.Second,
+		MaxStaleness:      5 * time.Second,
+		PollInterval:      5 * time.Second,
+		ExecutorWorkers:   500,
+	},
+}
+
+// ParseProfile parses the name of a profile. An empty name is the default profile.
+func ParseProfile(name string) (Profile, error) {
+	if len(name) == 0 {
+		return ProfileDefault, nil
+	}
+	profile := Profile(name)
+	if _, ok := profiles[profile]; !ok {
+		return "", fmt.Errorf("unknown profile '%s' (expected '%s', '%s' or '%s')", name, ProfileDefault,
+			ProfileLowLatency, ProfileHighThroughput)
+	}
+	return profile, nil
+}
+
+// Settings returns the settings of the profile. An unknown profile has the settings of the default profile.
+func (p Profile) Settings() ProfileSettings {
+	if settings, ok := profiles[p]; ok {
+		return settings
+	}
+	return profiles[ProfileDefault]
+}
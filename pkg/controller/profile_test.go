@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProfile(t *testing.T) {
+	profile, err := ParseProfile("")
+	assert.NoError(t, err)
+	assert.Equal(t, ProfileDefault, profile)
+	assert.Equal(t, DefaultStalenessInterval, profile.Settings().StalenessInterval)
+	assert.Equal(t, DefaultMaxStaleness, profile.Settings().MaxStaleness)
+	assert.Zero(t, profile.Settings().EvalDebounce)
+
+	lowLatency, err := ParseProfile("low-latency")
+	assert.NoError(t, err)
+	highThroughput, err := ParseProfile("high-throughput")
+	assert.NoError(t, err)
+	assert.True(t, lowLatency.Settings().StalenessInterval < highThroughput.Settings().StalenessInterval)
+	assert.True(t, lowLatency.Settings().EvalDebounce < highThroughput.Settings().EvalDebounce)
+
+	_, err = ParseProfile("fast")
+	assert.Error(t, err)
+	assert.Equal(t, ProfileDefault.Settings(), Profile("fast").Settings())
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// DefaultQuarantineWindow is the default window within which the consecutive failed invocations of a workflow are
+// counted.
+const DefaultQuarantineWindow = 10 * time.Minute
+
+var metricQuarantinedWorkflows = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "quarantined_workflows_total",
+	Help:      "Number of times that a workflow was quarantined due to consecutive failed invocations",
+})
+
+func init() {
+	prometheus.MustRegister(metricQuarantinedWorkflows)
+}
+
+// QuarantineConfig configures the automatic quarantine of workflows.
+type QuarantineConfig struct {
+	// Failures is the number of consecutive failed invocations of a workflow within the window after which the
+	// workflow is quarantined. If 0, workflows are never quarantined.
+	Failures int
+
+	// Window is the window within which the consecutive failed invocations are counted. If 0,
+	// DefaultQuarantineWindow is used.
+	Window time.Duration
+}
+
+// Quarantiner records the quarantine of workflows, such as the api.Workflow.
+type Quarantiner interface {
+	Quarantine(workflowID string, quarantine *types.WorkflowQuarantine) error
+}
+
+// WorkflowQuarantines quarantines the workflows of which the invocations fail consistently, such as due to an invalid
+// expression, to prevent new invocations from failing the same way. Once a workflow has had the configured number of
+// consecutive failed invocations within the window, it is quarantined: new invocations of the workflow are rejected,
+// until an operator clears the quarantine. A successful invocation resets the count; canceled invocations are ignored.
+//
+// The invocations are only counted by the controller that evaluated them. A nil WorkflowQuarantines does not
+// quarantine any workflow.
+type WorkflowQuarantines struct {
+	config      QuarantineConfig
+	quarantiner Quarantiner
+	failures    map[string]*workflowFailures // workflow ID -> consecutive failures
+	clock       clock.Clock
+	mu          *sync.Mutex
+}
+
+type workflowFailures struct {
+	at        []time.Time
+	lastError string
+}
+
+// NewWorkflowQuarantines creates the quarantines of workflows, or returns nil if quarantines are disabled.
+func NewWorkflowQuarantines(config QuarantineConfig) *WorkflowQuarantines {
+	if config.Failures <= 0 {
+		return nil
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultQuarantineWindow
+	}
+	return &WorkflowQuarantines{
+		config:   config,
+		failures: map[string]*workflowFailures{},
+		clock:    clock.RealClock{},
+		mu:       &sync.Mutex{},
+	}
+}
+
+// SetClock sets the clock with which the failures are timed, which should be the clock of the controller. By default,
+// the real clock is used.
+func (q *WorkflowQuarantines) SetClock(clock clock.Clock) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	q.clock = clock
+	q.mu.Unlock()
+}
+
+// SetQuarantiner sets the quarantiner that records the quarantines. Until it is set, workflows are not quarantined.
+func (q *WorkflowQuarantines) SetQuarantiner(quarantiner Quarantiner) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	q.quarantiner = quarantiner
+	q.mu.Unlock()
+}
+
+// Observe records the outcome of the finished invocation, and quarantines its workflow if the invocation is the last
+// of the consecutive failures that exceed the threshold.
+func (q *WorkflowQuarantines) Observe(invocation *types.WorkflowInvocation) {
+	wf := invocation.Workflow()
+	if q == nil || wf == nil || wf.GetSpec().GetInternal() {
+		return
+	}
+	wfID := wf.ID()
+
+	q.mu.Lock()
+	now := q.clock.Now()
+	switch invocation.GetStatus().GetStatus() {
+	case types.WorkflowInvocationStatus_SUCCEEDED:
+		delete(q.failures, wfID)
+		q.mu.Unlock()
+		return
+	case types.WorkflowInvocationStatus_FAILED:
+	default:
+		q.mu.Unlock()
+		return
+	}
+	failures, ok := q.failures[wfID]
+	if !ok {
+		failures = &workflowFailures{}
+		q.failures[wfID] = failures
+	}
+	failures.at = append(failures.at, now)
+	failures.lastError = invocation.GetStatus().GetError().GetMessage()
+	expired := 0
+	for expired < len(failures.at) && now.Sub(failures.at[expired]) > q.config.Window {
+		expired++
+	}
+	failures.at = failures.at[expired:]
+	if len(failures.at) < q.config.Failures || q.quarantiner == nil {
+		q.mu.Unlock()
+		return
+	}
+	// Reset the count, so that a workflow of which the quarantine is cleared is only quarantined again after another
+	// series of failures.
+	delete(q.failures, wfID)
+	quarantiner := q.quarantiner
+	q.mu.Unlock()
+
+	quarantine := &types.WorkflowQuarantine{
+		Reason: fmt.Sprintf("%d consecutive invocations failed within %v; last error: %s", len(failures.at),
+			q.config.Window, failures.lastError),
+		QuarantinedAt:       ptypes.TimestampNow(),
+		ConsecutiveFailures: int32(len(failures.at)),
+		Window:              ptypes.DurationProto(q.config.Window),
+	}
+	if err := quarantiner.Quarantine(wfID, quarantine); err != nil {
+		logrus.Errorf("Failed to quarantine workflow %s: %v", wfID, err)
+		return
+	}
+	metricQuarantinedWorkflows.Inc()
+	logrus.Warnf("Quarantined workflow %s: %s", wfID, quarantine.GetReason())
+}
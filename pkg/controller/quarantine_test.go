@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+type quarantineRecorder map[string]*types.WorkflowQuarantine
+
+func (r quarantineRecorder) Quarantine(workflowID string, quarantine *types.WorkflowQuarantine) error {
+	r[workflowID] = quarantine
+	return nil
+}
+
+func invocationWithStatus(wfID string, status types.WorkflowInvocationStatus_Status) *types.WorkflowInvocation {
+	invocation := types.NewWorkflowInvocation(wfID, "wi", time.Now().Add(time.Minute))
+	invocation.Spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata(wfID), Spec: &types.WorkflowSpec{}}
+	invocation.Status.Status = status
+	if status == types.WorkflowInvocationStatus_FAILED {
+		invocation.Status.Error = &types.Error{Message: "bad expression"}
+	}
+	return invocation
+}
+
+func TestWorkflowQuarantines(t *testing.T) {
+	recorder := quarantineRecorder{}
+	quarantines := NewWorkflowQuarantines(QuarantineConfig{Failures: 3, Window: time.Minute})
+	quarantines.SetQuarantiner(recorder)
+
+	// A successful invocation resets the consecutive failures, and canceled invocations are ignored.
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_SUCCEEDED))
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_ABORTED))
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	quarantines.Observe(invocationWithStatus("other", types.WorkflowInvocationStatus_FAILED))
+	assert.Empty(t, recorder)
+
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	assert.Len(t, recorder, 1)
+	quarantine := recorder["wf"]
+	assert.Equal(t, int32(3), quarantine.GetConsecutiveFailures())
+	assert.Contains(t, quarantine.GetReason(), "bad expression")
+	assert.NotNil(t, quarantine.GetWindow())
+
+	// The failures are counted again after a quarantine.
+	delete(recorder, "wf")
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	assert.Empty(t, recorder)
+}
+
+func TestWorkflowQuarantines_Window(t *testing.T) {
+	recorder := quarantineRecorder{}
+	fakeClock := clock.NewFakeClock(time.Now())
+	quarantines := NewWorkflowQuarantines(QuarantineConfig{Failures: 2, Window: time.Minute})
+	quarantines.SetQuarantiner(recorder)
+	quarantines.SetClock(fakeClock)
+
+	// Failures outside of the window do not count.
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	fakeClock.Step(2 * time.Minute)
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	assert.Empty(t, recorder)
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+	assert.Len(t, recorder, 1)
+}
+
+func TestWorkflowQuarantines_Disabled(t *testing.T) {
+	quarantines := NewWorkflowQuarantines(QuarantineConfig{})
+	assert.Nil(t, quarantines)
+	quarantines.SetQuarantiner(quarantineRecorder{})
+	quarantines.Observe(invocationWithStatus("wf", types.WorkflowInvocationStatus_FAILED))
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricThrottledTasks = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "throttled_tasks_total",
+	Help:      "Number of times that the execution of a task was deferred by its rate limit",
+})
+
+func init() {
+	prometheus.MustRegister(metricThrottledTasks)
+}
+
+// recordThrottledTasks records the tasks that the scheduler deferred due to their rate limit in the status of the
+// invocation, unless they have already been recorded with the same deferral. The throttled tasks are scheduled again
+// by a subsequent evaluation of the invocation, once their rate limit allows it. It returns the number of throttled
+// tasks.
+func (c *InvocationController) recordThrottledTasks(invocation *types.WorkflowInvocation,
+	schedule *scheduler.Schedule) int {
+	for _, action := range schedule.GetThrottleTasks() {
+		taskID := action.TaskID
+		if throttle, ok := invocation.GetStatus().GetThrottledTasks()[taskID]; ok &&
+			proto.Equal(throttle.GetUntil(), action.GetUntil()) {
+			continue
+		}
+		until, err := ptypes.Timestamp(action.GetUntil())
+		if err != nil {
+			continue
+		}
+		c.executor.Submit(&executor.Task{
+			TaskID:  fmt.Sprintf("%s.throttle.%s", invocation.ID(), taskID),
+			GroupID: invocation.ID(),
+			Apply: func() error {
+				return c.invocationAPI.ThrottleTask(invocation.ID(), taskID, until)
+			},
+		})
+		metricThrottledTasks.Inc()
+		c.logger.Debugf("Deferring execution of task %s until %v: rate limit reached", taskID, until)
+	}
+	return len(schedule.GetThrottleTasks())
+}
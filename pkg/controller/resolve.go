@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// ResolveWorkflowSpec returns the workflow definition that the controller effectively executes for the invocation.
+//
+// The definition includes the tasks that were added dynamically during the invocation, and has the defaults and
+// inherited settings applied the way that the controller applies them: the resolved function references, the effective
+// number of attempts and await count of each task, the timeout that each task inherits from the invocation deadline,
+// and the default modes of the completion and concurrency policies. The workflow of the invocation is not modified.
+func ResolveWorkflowSpec(invocation *types.WorkflowInvocation) *types.WorkflowSpec {
+	spec := &types.WorkflowSpec{}
+	if wfSpec := invocation.Workflow().GetSpec(); wfSpec != nil {
+		spec = proto.Clone(wfSpec).(*types.WorkflowSpec)
+	}
+	if len(spec.ApiVersion) == 0 {
+		spec.ApiVersion = types.WorkflowAPIVersion
+	}
+
+	maxRuntime := invocationMaxRuntime(invocation)
+	tasks := invocation.Tasks()
+	spec.Tasks = make(map[string]*types.TaskSpec, len(tasks))
+	for taskID, task := range tasks {
+		spec.Tasks[taskID] = resolveTaskSpec(task, maxRuntime)
+	}
+
+	if spec.Completion == nil {
+		spec.Completion = &types.CompletionPolicy{}
+	}
+	if len(spec.Completion.Mode) == 0 {
+		spec.Completion.Mode = types.CompletionModeAll
+	}
+	if spec.Concurrency != nil && len(spec.Concurrency.OnConflict) == 0 {
+		spec.Concurrency.OnConflict = types.ConcurrencyConflictQueue
+	}
+	return spec
+}
+
+func resolveTaskSpec(task *types.Task, maxRuntime time.Duration) *types.TaskSpec {
+	spec := &types.TaskSpec{}
+	if task.GetSpec() != nil {
+		spec = proto.Clone(task.GetSpec()).(*types.TaskSpec)
+	}
+	if fnRef := task.GetStatus().GetFnRef(); fnRef != nil {
+		spec.FunctionRef = fnRef.Format()
+	}
+	if spec.Retry == nil {
+		spec.Retry = &types.RetryPolicy{}
+	}
+	spec.Retry.MaxAttempts = task.GetSpec().MaxAttempts()
+	if len(spec.Requires) > 0 {
+		spec.Await = task.GetSpec().AwaitCount()
+	}
+
+	// Tasks cannot run longer than the invocation, regardless of their own timeout (see types.TaskDeadline).
+	timeout, err := ptypes.Duration(spec.GetTimeout())
+	if maxRuntime > 0 && (err != nil || timeout > maxRuntime) {
+		spec.Timeout = ptypes.DurationProto(maxRuntime)
+	}
+	return spec
+}
+
+// invocationMaxRuntime returns the time that the invocation is given to complete, measured from its start. It returns
+// 0 for invocations without a deadline, which do not time out.
+func invocationMaxRuntime(invocation *types.WorkflowInvocation) time.Duration {
+	createdAt, err := ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt())
+	if err != nil {
+		return 0
+	}
+	deadline, err := ptypes.Timestamp(invocation.GetSpec().GetDeadline())
+	if err != nil {
+		return 0
+	}
+	return deadline.Sub(createdAt)
+}
+
+// invocationStart returns the time from which the runtime of the invocation is measured: the last replay of its failed
+// tasks, or otherwise its creation.
+func invocationStart(invocation *types.WorkflowInvocation) (time.Time, error) {
+	if replayedAt := invocation.GetStatus().GetReplayedAt(); replayedAt != nil {
+		return ptypes.Timestamp(replayedAt)
+	}
+	return ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt())
+}
+
+// newTaskRunSpec creates the spec of a run of the task started at startAt (see types.NewTaskInvocationSpec). Unlike the
+// deadline in the spec of the invocation, the deadline of the task run is derived from the deadline of the invocation
+// as the controller enforces it, which is moved forward when the invocation is replayed. Otherwise, the replayed tasks
+// of an invocation that is replayed after its original deadline would time out immediately.
+func newTaskRunSpec(invocation *types.WorkflowInvocation, task *types.Task,
+	startAt time.Time) *types.TaskInvocationSpec {
+	spec := types.NewTaskInvocationSpec(invocation, task, startAt)
+	deadline, err := invocationDeadline(invocation)
+	if err != nil || deadline.IsZero() {
+		return spec
+	}
+	if ts, err := ptypes.TimestampProto(deadline); err == nil {
+		spec.Deadline = types.TaskDeadlineWithin(ts, task, startAt)
+	}
+	return spec
+}
+
+// invocationDeadline returns the time by which the invocation has to complete. A replayed invocation is given the
+// same runtime again, measured from its replay. The zero time is returned for invocations without a deadline.
+func invocationDeadline(invocation *types.WorkflowInvocation) (time.Time, error) {
+	start, err := invocationStart(invocation)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if _, err := ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt()); err != nil {
+		return time.Time{}, err
+	}
+	if invocation.GetSpec().GetDeadline() == nil {
+		return time.Time{}, nil
+	}
+	return start.Add(invocationMaxRuntime(invocation)), nil
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveWorkflowSpec(t *testing.T) {
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("first", &types.TaskSpec{FunctionRef: "fn"})
+	wfSpec.AddTask("second", &types.TaskSpec{
+		FunctionRef: "fn",
+		Requires:    types.Require("first"),
+		Timeout:     ptypes.DurationProto(10 * time.Second),
+		Retry:       &types.RetryPolicy{MaxAttempts: 3},
+	})
+	wfSpec.OutputTask = "second"
+	wfSpec.Concurrency = &types.ConcurrencyPolicy{Key: "{$.Invocation.Inputs.key}"}
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"first": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "fission", Namespace: "default", ID: "fn"}}},
+	}}
+
+	createdAt := time.Now()
+	invocation := types.NewWorkflowInvocation("wf", "wi", createdAt.Add(time.Minute))
+	invocation.Metadata.CreatedAt, _ = ptypes.TimestampProto(createdAt)
+	invocation.Spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocation.Status.DynamicTasks = map[string]*types.Task{
+		"dynamic": {
+			Metadata: types.NewObjectMetadata("dynamic"),
+			Spec:     &types.TaskSpec{FunctionRef: "other", Timeout: ptypes.DurationProto(time.Hour)},
+		},
+	}
+
+	spec := ResolveWorkflowSpec(invocation)
+	assert.Len(t, spec.Tasks, 3)
+
+	// Defaults are applied to the tasks, and timeouts are inherited from the invocation.
+	first := spec.Tasks["first"]
+	assert.Equal(t, "fission://default/fn", first.FunctionRef)
+	assert.EqualValues(t, 1, first.GetRetry().GetMaxAttempts())
+	assert.EqualValues(t, 60, first.GetTimeout().GetSeconds())
+	second := spec.Tasks["second"]
+	assert.Equal(t, "fn", second.FunctionRef)
+	assert.EqualValues(t, 3, second.GetRetry().GetMaxAttempts())
+	assert.EqualValues(t, 1, second.GetAwait())
+	assert.EqualValues(t, 10, second.GetTimeout().GetSeconds())
+	assert.EqualValues(t, 60, spec.Tasks["dynamic"].GetTimeout().GetSeconds())
+
+	// Defaults are applied to the policies of the workflow.
+	assert.Equal(t, types.CompletionModeAll, spec.GetCompletion().GetMode())
+	assert.Equal(t, types.ConcurrencyConflictQueue, spec.GetConcurrency().GetOnConflict())
+
+	// The workflow of the invocation is not modified.
+	assert.Nil(t, wfSpec.Tasks["first"].GetRetry())
+	assert.Nil(t, wfSpec.GetCompletion())
+	assert.Empty(t, wfSpec.GetConcurrency().GetOnConflict())
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricUnhealthyRuntimeTasks = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "unhealthy_runtime_tasks",
+	Help:      "Number of tasks that are waiting for their function runtime to become healthy",
+})
+
+func init() {
+	prometheus.MustRegister(metricUnhealthyRuntimeTasks)
+}
+
+// HealthChecker reports whether a function runtime is able to execute functions, such as the health probe of the
+// Fission endpoints.
+type HealthChecker interface {
+	Healthy() bool
+}
+
+// RuntimeHealth gates the scheduling of tasks on the health of their function runtime.
+//
+// Like the tasks of suspended functions, tasks of which the runtime is unhealthy are not submitted, as the execution
+// would fail and burn through the retries of the task, but are deferred by the invocation controller until the
+// runtime recovers, or until the invocation exceeds its deadline.
+//
+// A nil RuntimeHealth considers all runtimes healthy.
+type RuntimeHealth struct {
+	checkers map[string]HealthChecker
+
+	// waiting contains per invocation the tasks that are waiting on an unhealthy runtime, and the runtime they are
+	// waiting on.
+	waiting map[string]map[string]string
+	mu      *sync.RWMutex
+}
+
+func NewRuntimeHealth() *RuntimeHealth {
+	return &RuntimeHealth{
+		checkers: map[string]HealthChecker{},
+		waiting:  map[string]map[string]string{},
+		mu:       &sync.RWMutex{},
+	}
+}
+
+// Register gates the tasks of the runtime on the health reported by the checker.
+func (h *RuntimeHealth) Register(runtime string, checker HealthChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers[runtime] = checker
+}
+
+// UnhealthyRuntime returns the runtime of the task if it is unhealthy. Tasks of which the function has not been
+// resolved yet are not gated.
+func (h *RuntimeHealth) UnhealthyRuntime(task *types.Task) (runtime string, unhealthy bool) {
+	if h == nil {
+		return "", false
+	}
+	runtime = task.GetStatus().GetFnRef().GetRuntime()
+	h.mu.RLock()
+	checker, ok := h.checkers[runtime]
+	h.mu.RUnlock()
+	if !ok || checker.Healthy() {
+		return "", false
+	}
+	return runtime, true
+}
+
+// Unhealthy returns the registered runtimes that are unhealthy, ordered by name.
+func (h *RuntimeHealth) Unhealthy() []string {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var runtimes []string
+	for runtime, checker := range h.checkers {
+		if !checker.Healthy() {
+			runtimes = append(runtimes, runtime)
+		}
+	}
+	sort.Strings(runtimes)
+	return runtimes
+}
+
+// SetWaiting replaces the tasks of the invocation that are waiting on unhealthy runtimes, mapping the task IDs to the
+// runtimes. A nil or empty tasks map clears the waiting tasks of the invocation.
+func (h *RuntimeHealth) SetWaiting(invocationID string, tasks map[string]string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(tasks) == 0 {
+		delete(h.waiting, invocationID)
+	} else {
+		h.waiting[invocationID] = tasks
+	}
+	var count int
+	for _, tasks := range h.waiting {
+		count += len(tasks)
+	}
+	metricUnhealthyRuntimeTasks.Set(float64(count))
+}
+
+// Waiting returns the IDs of the invocations that have tasks waiting on the runtime, which should be re-evaluated
+// once the runtime has recovered to schedule these tasks without delay.
+func (h *RuntimeHealth) Waiting(runtime string) (invocationIDs []string) {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for invocationID, tasks := range h.waiting {
+		for _, waitingOn := range tasks {
+			if waitingOn == runtime {
+				invocationIDs = append(invocationIDs, invocationID)
+				break
+			}
+		}
+	}
+	sort.Strings(invocationIDs)
+	return invocationIDs
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeHealthChecker bool
+
+func (h *fakeHealthChecker) Healthy() bool {
+	return bool(*h)
+}
+
+func TestRuntimeHealth(t *testing.T) {
+	healthy := fakeHealthChecker(true)
+	health := NewRuntimeHealth()
+	health.Register("fission", &healthy)
+	task := &types.Task{
+		Spec: &types.TaskSpec{FunctionRef: "payments"},
+		Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "fission", Namespace: "default", ID: "payments"},
+		},
+	}
+	internal := &types.Task{
+		Spec: &types.TaskSpec{FunctionRef: "noop"},
+		Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "internal", ID: "noop"},
+		},
+	}
+	_, unhealthy := health.UnhealthyRuntime(task)
+	assert.False(t, unhealthy)
+	assert.Empty(t, health.Unhealthy())
+
+	// Only the tasks of the unhealthy runtime are gated.
+	healthy = false
+	runtime, unhealthy := health.UnhealthyRuntime(task)
+	assert.True(t, unhealthy)
+	assert.Equal(t, "fission", runtime)
+	_, unhealthy = health.UnhealthyRuntime(internal)
+	assert.False(t, unhealthy)
+	assert.Equal(t, []string{"fission"}, health.Unhealthy())
+
+	health.SetWaiting("wi-2", map[string]string{"charge": runtime})
+	health.SetWaiting("wi-1", map[string]string{"charge": runtime, "refund": runtime})
+	health.SetWaiting("wi-3", map[string]string{"charge": runtime})
+	health.SetWaiting("wi-3", nil)
+	assert.Equal(t, []string{"wi-1", "wi-2"}, health.Waiting("fission"))
+	assert.Empty(t, health.Waiting("internal"))
+}
+
+func TestRuntimeHealth_Nil(t *testing.T) {
+	var health *RuntimeHealth
+	_, unhealthy := health.UnhealthyRuntime(&types.Task{Spec: &types.TaskSpec{FunctionRef: "payments"}})
+	assert.False(t, unhealthy)
+	health.SetWaiting("wi-1", map[string]string{"charge": "fission"})
+	assert.Empty(t, health.Waiting("fission"))
+	assert.Empty(t, health.Unhealthy())
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"strconv"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util/exemplar"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The reasons of a sampling decision.
+const (
+	// SamplingReasonDebug indicates that the trace was forced by the types.LabelTrace label of the invocation.
+	SamplingReasonDebug = "debug"
+
+	// SamplingReasonLabel indicates that the trace was forced by one of the configured labels.
+	SamplingReasonLabel = "label"
+
+	// SamplingReasonRate indicates that the trace was sampled, or dropped, according to the sampling rate.
+	SamplingReasonRate = "rate"
+)
+
+var metricTraceSampling = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "trace_sampling_decisions_total",
+	Help:      "Number of trace sampling decisions of invocation evaluations, by reason and effective decision",
+}, []string{"reason", "sampled"})
+
+func init() {
+	prometheus.MustRegister(metricTraceSampling)
+}
+
+// TraceSampling decides per invocation whether the trace of its evaluation is sampled. The decision is deterministic:
+// all evaluations of an invocation, across controller restarts and replicas, make the same decision, which prevents
+// traces with gaps.
+//
+// Invocations with the types.LabelTrace label set to "true", or with one of the ForceLabels, are always sampled.
+// Of the other invocations, a fraction of Rate is sampled. The trace of an invocation that is sampled by rate is
+// still subject to the sampler of the tracer; forced traces override the sampler of the tracer.
+//
+// A nil TraceSampling leaves all decisions to the sampler of the tracer.
+type TraceSampling struct {
+	// Rate is the fraction of invocations that is sampled, between 0 and 1.
+	Rate float64
+
+	// ForceLabels are the labels that force the trace of the invocation to be sampled. An empty value matches any
+	// value of the label.
+	ForceLabels map[string]string
+}
+
+// TraceDecision is the effective sampling decision of the trace of an invocation.
+type TraceDecision struct {
+	// Sampled indicates whether the trace of the invocation is recorded.
+	Sampled bool
+
+	// Reason is the reason of the decision: SamplingReasonDebug, SamplingReasonLabel or SamplingReasonRate.
+	Reason string
+
+	// TraceID is the ID of the trace of the invocation, if it is sampled.
+	TraceID string
+}
+
+// Decide returns whether the trace of the invocation with the given ID and labels should be sampled, and the reason
+// of the decision.
+func (s *TraceSampling) Decide(invocationID string, labels map[string]string) (bool, string) {
+	if labels[types.LabelTrace] == "true" {
+		return true, SamplingReasonDebug
+	}
+	if s == nil {
+		return true, SamplingReasonRate
+	}
+	for key, value := range s.ForceLabels {
+		if actual, ok := labels[key]; ok && (len(value) == 0 || actual == value) {
+			return true, SamplingReasonLabel
+		}
+	}
+	if s.Rate >= 1 {
+		return true, SamplingReasonRate
+	}
+	sum := sha256.Sum256([]byte(invocationID))
+	return float64(binary.BigEndian.Uint64(sum[:8]))/math.MaxUint64 < s.Rate, SamplingReasonRate
+}
+
+// StartSpan starts the span of the evaluation of the invocation, and applies the sampling decision to it. The spans of
+// the tasks of the invocation are children of this span, and inherit the decision.
+func (s *TraceSampling) StartSpan(invocationID string, labels map[string]string,
+	opts ...opentracing.StartSpanOption) (opentracing.Span, TraceDecision) {
+	sampled, reason := s.Decide(invocationID, labels)
+	span := opentracing.StartSpan("/controller/eval", opts...)
+	if !sampled {
+		ext.SamplingPriority.Set(span, 0)
+	} else if reason != SamplingReasonRate {
+		ext.SamplingPriority.Set(span, 1)
+		span.SetTag("sampling.reason", reason)
+	}
+
+	decision := TraceDecision{Reason: reason}
+	decision.TraceID, decision.Sampled = exemplar.TraceID(span)
+	metricTraceSampling.WithLabelValues(reason, strconv.FormatBool(decision.Sampled)).Inc()
+	return span, decision
+}
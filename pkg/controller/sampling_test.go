@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-client-go"
+)
+
+func TestTraceSampling_Decide(t *testing.T) {
+	var disabled *TraceSampling
+	sampled, reason := disabled.Decide("wi", nil)
+	assert.True(t, sampled)
+	assert.Equal(t, SamplingReasonRate, reason)
+
+	sampling := &TraceSampling{Rate: 0, ForceLabels: map[string]string{"tenant": "acme", "canary": ""}}
+	sampled, reason = sampling.Decide("wi", nil)
+	assert.False(t, sampled)
+	assert.Equal(t, SamplingReasonRate, reason)
+
+	sampled, reason = sampling.Decide("wi", map[string]string{types.LabelTrace: "true"})
+	assert.True(t, sampled)
+	assert.Equal(t, SamplingReasonDebug, reason)
+
+	sampled, reason = sampling.Decide("wi", map[string]string{"tenant": "acme"})
+	assert.True(t, sampled)
+	assert.Equal(t, SamplingReasonLabel, reason)
+
+	sampled, _ = sampling.Decide("wi", map[string]string{"tenant": "other"})
+	assert.False(t, sampled)
+
+	sampled, reason = sampling.Decide("wi", map[string]string{"canary": "any"})
+	assert.True(t, sampled)
+	assert.Equal(t, SamplingReasonLabel, reason)
+}
+
+func TestTraceSampling_DecideRate(t *testing.T) {
+	sampling := &TraceSampling{Rate: 0.25}
+	var count int
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("wi-%d", i)
+		sampled, _ := sampling.Decide(id, nil)
+		if sampled {
+			count++
+		}
+		// The decision is the same for every evaluation of an invocation.
+		again, _ := sampling.Decide(id, nil)
+		assert.Equal(t, sampled, again)
+	}
+	assert.InDelta(t, 250, count, 50)
+}
+
+func TestTraceSampling_StartSpan(t *testing.T) {
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(false), jaeger.NewNullReporter())
+	defer closer.Close()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	sampling := &TraceSampling{Rate: 0}
+
+	// The label overrides the sampler of the tracer.
+	span, decision := sampling.StartSpan("wi", map[string]string{types.LabelTrace: "true"})
+	defer span.Finish()
+	assert.True(t, decision.Sampled)
+	assert.Equal(t, SamplingReasonDebug, decision.Reason)
+	assert.NotEmpty(t, decision.TraceID)
+
+	child := opentracing.StartSpan("/task/t", opentracing.ChildOf(span.Context()))
+	defer child.Finish()
+	assert.True(t, child.Context().(jaeger.SpanContext).IsSampled())
+
+	span, decision = sampling.StartSpan("wi", nil)
+	defer span.Finish()
+	assert.False(t, decision.Sampled)
+	assert.Empty(t, decision.TraceID)
+}
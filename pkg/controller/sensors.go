@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var ErrSensorTimeout = errors.New("sensor timed out")
+
+var metricSensorPolls = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "sensor_polls_total",
+	Help:      "Number of polls of sensor tasks, by result (ready, not_ready, timeout or error)",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(metricSensorPolls)
+}
+
+// pollSensor starts the task run of the sensor, unless it was already started, and polls its readiness check.
+//
+// Rather than waiting in between, the next poll is submitted to the executor with a delay of the interval of the
+// sensor, so that the sensor does not occupy a worker between its polls. Once the check passes, the task run succeeds
+// with the output of the check. If the check does not pass before the timeout of the sensor or the deadline of the
+// task run, the task run fails.
+func (c *InvocationController) pollSensor(invocation *types.WorkflowInvocation, spec *types.TaskInvocationSpec) error {
+	taskID := spec.GetTaskId()
+	startedAt := c.now()
+	var polls int32
+	if run, ok := invocation.TaskInvocation(taskID); ok &&
+		run.GetStatus().GetStatus() == types.TaskInvocationStatus_IN_PROGRESS &&
+		run.GetSpec().AttemptNumber() == spec.AttemptNumber() {
+		// The sensor was started by a previous instance of the controller; continue polling where it left off.
+		if ts, err := ptypes.Timestamp(run.GetMetadata().GetCreatedAt()); err == nil {
+			startedAt = ts
+		}
+		polls = run.GetStatus().GetPolls()
+	} else if err := c.taskAPI.Start(spec); err != nil {
+		return err
+	}
+
+	interval, timeout, err := builtin.SensorTiming(spec.GetInputs())
+	if err != nil {
+		metricSensorPolls.WithLabelValues("error").Inc()
+		return c.failSensor(spec, err.Error(), types.ErrorCodeFunction)
+	}
+	deadline := startedAt.Add(timeout)
+	if taskDeadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil && taskDeadline.Before(deadline) {
+		deadline = taskDeadline
+	}
+	return c.checkSensor(invocation, spec, spec, interval, deadline, polls)
+}
+
+// checkSensor performs a single poll of the sensor, using the resolved inputs of the run spec.
+func (c *InvocationController) checkSensor(invocation *types.WorkflowInvocation, spec *types.TaskInvocationSpec,
+	resolved *types.TaskInvocationSpec, interval time.Duration, deadline time.Time, polls int32) error {
+	// Stop polling once the invocation has finished, for example because it was canceled.
+	select {
+	case <-c.stopped:
+		return nil
+	default:
+	}
+	taskID := spec.GetTaskId()
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	check, err := builtin.CheckSensor(ctx, resolved.GetInputs())
+	cancel()
+	if err != nil {
+		metricSensorPolls.WithLabelValues("error").Inc()
+		return c.failSensor(spec, err.Error(), types.ErrorCodeFunction)
+	}
+	if check.Ready {
+		metricSensorPolls.WithLabelValues("ready").Inc()
+		c.logger.Infof("Sensor %s is ready after %d poll(s): %s", taskID, polls+1, check.Result)
+		return c.taskAPI.Succeed(invocation.ID(), taskID, check.Output)
+	}
+
+	result := builtin.TruncateSensorResult(check.Result)
+	polls++
+	if !c.now().Add(interval).Before(deadline) {
+		metricSensorPolls.WithLabelValues("timeout").Inc()
+		return c.failSensor(spec, fmt.Sprintf("%v: not ready after %d poll(s): %s", ErrSensorTimeout, polls, result),
+			types.ErrorCodeTimeout)
+	}
+	metricSensorPolls.WithLabelValues("not_ready").Inc()
+	if err := c.taskAPI.Poll(invocation.ID(), taskID, result); err != nil {
+		return err
+	}
+
+	// The next poll is not part of the group of the invocation, as the invocation cannot progress until the sensor
+	// has finished anyway; the task run of the sensor remains in progress in the meantime.
+	c.setPendingPoll(taskID, c.now().Add(interval))
+	if !c.executor.SubmitAfter(&executor.Task{
+		TaskID: fmt.Sprintf("%s.poll.%s", invocation.ID(), taskID),
+		Apply: func() error {
+			// Leave the poll to the controller instance that the invocation was handed off to.
+			if !c.startPoll(taskID) {
+				return nil
+			}
+			defer c.finishPoll()
+			// Resolve the inputs again, as the condition of the sensor is evaluated for each poll.
+			next := proto.Clone(resolved).(*types.TaskInvocationSpec)
+			inputs, err := c.resolveInputs(invocation, taskID, spec.GetTask().GetSpec())
+			if err != nil {
+				metricSensorPolls.WithLabelValues("error").Inc()
+				return c.failSensor(spec, err.Error(), types.ErrorCodeFunction)
+			}
+			next.Inputs = inputs
+			return c.checkSensor(invocation, spec, next, interval, deadline, polls)
+		},
+	}, interval) {
+		c.clearPendingPoll(taskID)
+		return c.failSensor(spec, "failed to schedule the next poll of the sensor", types.ErrorCodeRuntime)
+	}
+	c.logger.Debugf("Sensor %s is not ready (poll %d): %s", taskID, polls, result)
+	return nil
+}
+
+func (c *InvocationController) failSensor(spec *types.TaskInvocationSpec, msg string, code string) error {
+	return c.taskAPI.FailWithError(spec.GetInvocationId(), types.NewTaskError(&types.Error{Message: msg},
+		spec.GetTaskId(), spec.AttemptNumber(), code))
+}
+
+// setPendingPoll records the time at which the next poll of the sensor is due.
+func (c *InvocationController) setPendingPoll(taskID string, due time.Time) {
+	c.pollsMu.Lock()
+	c.pendingPolls[taskID] = due
+	c.pollsMu.Unlock()
+}
+
+func (c *InvocationController) clearPendingPoll(taskID string) {
+	c.pollsMu.Lock()
+	delete(c.pendingPolls, taskID)
+	c.pollsMu.Unlock()
+}
+
+// startPoll marks the pending poll of the sensor as in progress. It returns false if the invocation has been handed
+// off to another controller instance; the poll then remains pending, to be resumed by that instance.
+func (c *InvocationController) startPoll(taskID string) bool {
+	// The ownership cannot change until the poll has been marked as in progress.
+	if !c.config.handoff.acquire(c.invocationID) {
+		return false
+	}
+	defer c.config.handoff.release()
+	c.pollsMu.Lock()
+	defer c.pollsMu.Unlock()
+	delete(c.pendingPolls, taskID)
+	c.activePolls++
+	return true
+}
+
+func (c *InvocationController) finishPoll() {
+	c.pollsMu.Lock()
+	c.activePolls--
+	c.pollsMu.Unlock()
+}
+
+func (c *InvocationController) activePollCount() int {
+	c.pollsMu.Lock()
+	defer c.pollsMu.Unlock()
+	return c.activePolls
+}
+
+// resumePolls resumes the polling of the sensors of which the next poll was due when the invocation was handed off.
+// The sensors continue polling where they left off.
+func (c *InvocationController) resumePolls(invocation *types.WorkflowInvocation, polls map[string]time.Time) {
+	for taskID, due := range polls {
+		taskID := taskID
+		c.setPendingPoll(taskID, due)
+		if !c.executor.SubmitAfter(&executor.Task{
+			TaskID: fmt.Sprintf("%s.poll.%s", invocation.ID(), taskID),
+			Apply: func() error {
+				if !c.startPoll(taskID) {
+					return nil
+				}
+				defer c.finishPoll()
+				return c.execTask(invocation, taskID)
+			},
+		}, time.Until(due)) {
+			// Leave the sensor to be recovered by the evaluation of the invocation instead.
+			c.clearPendingPoll(taskID)
+			delete(c.startedTasks, taskID)
+			c.logger.Warnf("Failed to resume the polling of sensor %s", taskID)
+		}
+	}
+}
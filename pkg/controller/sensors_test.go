@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// runSensor evaluates an invocation of a workflow with a single sensor task with the inputs, and returns the task run
+// of the sensor once it has finished.
+func runSensor(t *testing.T, inputs map[string]interface{}) *types.TaskInvocation {
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{}, backend, nil)
+	exec := executor.NewLocalExecutor(1, 10)
+	exec.Start()
+	defer exec.Close()
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("await", &types.TaskSpec{
+		FunctionRef: builtin.Sensor,
+		Inputs:      typedvalues.MustWrapMapTypedValue(inputs),
+	})
+	wfSpec.OutputTask = "await"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"await": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: builtin.Runtime, ID: builtin.Sensor}}},
+	}}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	c := NewInvocationController(invocationID, exec, invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	c.Eval(context.Background(), &ctrl.Event{Updated: project()})
+	for i := 0; i < 500; i++ {
+		if taskRun, ok := project().TaskInvocation("await"); ok && taskRun.GetStatus().Finished() {
+			return taskRun
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("sensor did not finish")
+	return nil
+}
+
+func TestSensor_PollsUntilReady(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ready"))
+	}))
+	defer server.Close()
+
+	taskRun := runSensor(t, map[string]interface{}{
+		builtin.SensorInputURL:      server.URL,
+		builtin.SensorInputInterval: "20ms",
+	})
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED, taskRun.GetStatus().GetStatus())
+	assert.Equal(t, "ready", typedvalues.MustUnwrap(taskRun.GetStatus().GetOutput()))
+	assert.EqualValues(t, 2, taskRun.GetStatus().GetPolls())
+	assert.Equal(t, "503 Service Unavailable", taskRun.GetStatus().GetLastPollResult())
+	assert.NotNil(t, taskRun.GetStatus().GetLastPolledAt())
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+}
+
+func TestSensor_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	taskRun := runSensor(t, map[string]interface{}{
+		builtin.SensorInputURL:      server.URL,
+		builtin.SensorInputInterval: "20ms",
+		builtin.SensorInputTimeout:  "100ms",
+	})
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, taskRun.GetStatus().GetStatus())
+	assert.Equal(t, types.ErrorCodeTimeout, taskRun.GetStatus().GetError().GetCode())
+	assert.Contains(t, taskRun.GetStatus().GetError().GetMessage(), ErrSensorTimeout.Error())
+	assert.True(t, taskRun.GetStatus().GetPolls() > 0)
+	assert.Equal(t, "404 Not Found", taskRun.GetStatus().GetLastPollResult())
+}
+
+func TestSensor_InvalidCheck(t *testing.T) {
+	taskRun := runSensor(t, map[string]interface{}{
+		builtin.SensorInputInterval: "20ms",
+	})
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, taskRun.GetStatus().GetStatus())
+	assert.Equal(t, types.ErrorCodeFunction, taskRun.GetStatus().GetError().GetCode())
+	assert.Zero(t, taskRun.GetStatus().GetPolls())
+}
@@ -0,0 +1,272 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSLOWindow is the default rolling window over which the SLOs of tasks are evaluated.
+	DefaultSLOWindow = 5 * time.Minute
+
+	// DefaultSLOMinSamples is the default minimum number of task runs in the window before an SLO is evaluated.
+	DefaultSLOMinSamples = 10
+
+	// SLOLatency and SLOSuccessRate identify the objectives of an SLO.
+	SLOLatency     = "latency"
+	SLOSuccessRate = "success_rate"
+
+	defaultSLOPercentile = 95
+	sloWebhookTimeout    = 5 * time.Second
+
+	// maxSLOSamples bounds the number of task runs that are retained per task, regardless of the window.
+	maxSLOSamples = 10000
+)
+
+var (
+	metricSLOBreached = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "slo_breached",
+		Help:      "Whether the objective of the SLO of a task is breached (1) or met (0) over the rolling window",
+	}, []string{"workflow", "task", "objective"})
+	metricSLOBreaches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "slo_breaches_total",
+		Help:      "Number of times that the objective of the SLO of a task became breached",
+	}, []string{"objective"})
+)
+
+func init() {
+	prometheus.MustRegister(metricSLOBreached, metricSLOBreaches)
+}
+
+// SLOConfig configures the evaluation of the SLOs of tasks.
+type SLOConfig struct {
+	// Window is the rolling window over which the SLOs are evaluated, unless the SLO has a window of its own. If 0,
+	// DefaultSLOWindow is used.
+	Window time.Duration
+
+	// MinSamples is the minimum number of task runs in the window before an SLO is evaluated. If 0,
+	// DefaultSLOMinSamples is used.
+	MinSamples int
+
+	// WebhookURL is the URL to which the breaches and recoveries of SLOs are posted. If empty, no webhook is called.
+	WebhookURL string
+}
+
+// SLOBreach describes the change of the state of an objective of the SLO of a task, which is posted to the webhook.
+type SLOBreach struct {
+	WorkflowID string `json:"workflowId"`
+	TaskID     string `json:"taskId"`
+	Objective  string `json:"objective"`
+
+	// Breached is true if the objective became breached, and false if it recovered.
+	Breached bool `json:"breached"`
+
+	// Target and Observed are the objective and the value over the window: a latency in seconds, or a success rate
+	// as a percentage.
+	Target   float64 `json:"target"`
+	Observed float64 `json:"observed"`
+	Samples  int     `json:"samples"`
+	Window   string  `json:"window"`
+	Time     string  `json:"time"`
+}
+
+// SLOMonitor evaluates the SLOs of tasks (see TaskSpec.Slo) over a rolling window of the runs of each task, across the
+// invocations of the workflow. Once an objective is breached, or recovers, the state is exposed with the
+// workflows_controller_slo_breached metric, and posted to the webhook, if any.
+//
+// The runs are only observed by the controller that executed them. A nil SLOMonitor does not evaluate any SLO.
+type SLOMonitor struct {
+	config SLOConfig
+	client *http.Client
+	tasks  map[string]*taskSLOState // <workflow>/<task> -> state
+	mu     *sync.Mutex
+}
+
+type taskSLOState struct {
+	samples  []sloSample
+	breached map[string]bool // objective -> breached
+}
+
+type sloSample struct {
+	at        time.Time
+	duration  time.Duration
+	succeeded bool
+}
+
+func NewSLOMonitor(config SLOConfig) *SLOMonitor {
+	if config.Window <= 0 {
+		config.Window = DefaultSLOWindow
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = DefaultSLOMinSamples
+	}
+	return &SLOMonitor{
+		config: config,
+		client: &http.Client{Timeout: sloWebhookTimeout},
+		tasks:  map[string]*taskSLOState{},
+		mu:     &sync.Mutex{},
+	}
+}
+
+// Observe records a run of the task, and evaluates the SLO of the task. It is a no-op for tasks without an SLO.
+func (m *SLOMonitor) Observe(invocation *types.WorkflowInvocation, task *types.Task, duration time.Duration,
+	succeeded bool) {
+	slo := task.GetSpec().GetSlo()
+	if m == nil || slo == nil {
+		return
+	}
+	window := m.config.Window
+	if w, err := ptypes.Duration(slo.GetWindow()); err == nil && w > 0 {
+		window = w
+	}
+	wfID := invocation.GetSpec().GetWorkflowId()
+
+	m.mu.Lock()
+	now := time.Now() // taken under the lock to keep the samples ordered
+	key := wfID + "/" + task.ID()
+	state, ok := m.tasks[key]
+	if !ok {
+		state = &taskSLOState{breached: map[string]bool{}}
+		m.tasks[key] = state
+	}
+	state.samples = append(state.samples, sloSample{at: now, duration: duration, succeeded: succeeded})
+	expired := sort.Search(len(state.samples), func(i int) bool {
+		return now.Sub(state.samples[i].at) <= window
+	})
+	if len(state.samples)-expired > maxSLOSamples {
+		expired = len(state.samples) - maxSLOSamples
+	}
+	state.samples = state.samples[expired:]
+	if len(state.samples) < m.config.MinSamples {
+		m.mu.Unlock()
+		return
+	}
+
+	// The changes are signaled under the lock, to signal the changes of an objective in order.
+	update := func(objective string, target, observed float64, breached bool) {
+		if state.breached[objective] == breached {
+			return
+		}
+		state.breached[objective] = breached
+		m.signal(SLOBreach{
+			WorkflowID: wfID,
+			TaskID:     task.ID(),
+			Objective:  objective,
+			Breached:   breached,
+			Target:     target,
+			Observed:   observed,
+			Samples:    len(state.samples),
+			Window:     window.String(),
+			Time:       now.Format(time.RFC3339),
+		})
+	}
+	if latency, err := ptypes.Duration(slo.GetLatency()); err == nil && latency > 0 {
+		percentile := slo.GetPercentile()
+		if percentile <= 0 {
+			percentile = defaultSLOPercentile
+		}
+		observed := latencyPercentile(state.samples, percentile)
+		update(SLOLatency, latency.Seconds(), observed.Seconds(), observed > latency)
+	}
+	if target := slo.GetSuccessRate(); target > 0 {
+		var succeeded int
+		for _, sample := range state.samples {
+			if sample.succeeded {
+				succeeded++
+			}
+		}
+		observed := 100 * float64(succeeded) / float64(len(state.samples))
+		update(SLOSuccessRate, target, observed, observed < target)
+	}
+	m.mu.Unlock()
+}
+
+// Breached returns the objectives of the SLO of the task that are currently breached.
+func (m *SLOMonitor) Breached(workflowID, taskID string) []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.tasks[workflowID+"/"+taskID]
+	if !ok {
+		return nil
+	}
+	var objectives []string
+	for objective, breached := range state.breached {
+		if breached {
+			objectives = append(objectives, objective)
+		}
+	}
+	sort.Strings(objectives)
+	return objectives
+}
+
+func (m *SLOMonitor) signal(change SLOBreach) {
+	if change.Breached {
+		metricSLOBreached.WithLabelValues(change.WorkflowID, change.TaskID, change.Objective).Set(1)
+		metricSLOBreaches.WithLabelValues(change.Objective).Inc()
+		logrus.Warnf("SLO of task %s of workflow %s breached: %s is %.3f (objective: %.3f) over the last %s",
+			change.TaskID, change.WorkflowID, change.Objective, change.Observed, change.Target, change.Window)
+	} else {
+		metricSLOBreached.WithLabelValues(change.WorkflowID, change.TaskID, change.Objective).Set(0)
+		logrus.Infof("SLO of task %s of workflow %s recovered: %s is %.3f (objective: %.3f) over the last %s",
+			change.TaskID, change.WorkflowID, change.Objective, change.Observed, change.Target, change.Window)
+	}
+	if len(m.config.WebhookURL) > 0 {
+		ctrl.Go("slo", func() {
+			if err := m.post(change); err != nil {
+				logrus.Errorf("Failed to post SLO breach of task %s of workflow %s to webhook: %v", change.TaskID,
+					change.WorkflowID, err)
+			}
+		})
+	}
+}
+
+func (m *SLOMonitor) post(change SLOBreach) error {
+	body, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	resp, err := m.client.Post(m.config.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// latencyPercentile returns the nearest-rank percentile (0-100) of the durations of the samples.
+func latencyPercentile(samples []sloSample, percentile float64) time.Duration {
+	durations := make([]time.Duration, len(samples))
+	for i, sample := range samples {
+		durations[i] = sample.duration
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	rank := int(math.Ceil(percentile/100*float64(len(durations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return durations[rank]
+}
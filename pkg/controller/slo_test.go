@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func sloTask(slo *types.TaskSLO) *types.Task {
+	return &types.Task{
+		Metadata: types.NewObjectMetadata("charge"),
+		Spec:     &types.TaskSpec{FunctionRef: "payments", Slo: slo},
+	}
+}
+
+func TestSLOMonitor_Latency(t *testing.T) {
+	monitor := NewSLOMonitor(SLOConfig{MinSamples: 4})
+	invocation := finishedInvocation("checkout")
+	task := sloTask(&types.TaskSLO{Latency: ptypes.DurationProto(time.Second), Percentile: 75})
+
+	for i := 0; i < 3; i++ {
+		monitor.Observe(invocation, task, 2*time.Second, true)
+	}
+	assert.Empty(t, monitor.Breached("checkout", "charge"), "breached before the minimum number of samples")
+
+	monitor.Observe(invocation, task, 2*time.Second, true)
+	assert.Equal(t, []string{SLOLatency}, monitor.Breached("checkout", "charge"))
+
+	// The objective recovers once the percentile is within the objective again.
+	for i := 0; i < 12; i++ {
+		monitor.Observe(invocation, task, 100*time.Millisecond, true)
+	}
+	assert.Empty(t, monitor.Breached("checkout", "charge"))
+}
+
+func TestSLOMonitor_SuccessRate(t *testing.T) {
+	breaches := make(chan SLOBreach, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		breach := SLOBreach{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&breach))
+		breaches <- breach
+	}))
+	defer webhook.Close()
+	monitor := NewSLOMonitor(SLOConfig{MinSamples: 2, WebhookURL: webhook.URL})
+	invocation := finishedInvocation("checkout")
+	task := sloTask(&types.TaskSLO{SuccessRate: 99, Window: ptypes.DurationProto(50 * time.Millisecond)})
+
+	monitor.Observe(invocation, task, time.Millisecond, true)
+	monitor.Observe(invocation, task, time.Millisecond, false)
+	assert.Equal(t, []string{SLOSuccessRate}, monitor.Breached("checkout", "charge"))
+	select {
+	case breach := <-breaches:
+		assert.Equal(t, "charge", breach.TaskID)
+		assert.Equal(t, SLOSuccessRate, breach.Objective)
+		assert.True(t, breach.Breached)
+		assert.EqualValues(t, 50, breach.Observed)
+	case <-time.After(time.Second):
+		assert.Fail(t, "webhook was not called")
+	}
+
+	// The failed run drops out of the window of the SLO.
+	time.Sleep(60 * time.Millisecond)
+	monitor.Observe(invocation, task, time.Millisecond, true)
+	monitor.Observe(invocation, task, time.Millisecond, true)
+	assert.Empty(t, monitor.Breached("checkout", "charge"))
+
+	var disabled *SLOMonitor
+	disabled.Observe(invocation, task, time.Millisecond, false)
+	assert.Empty(t, disabled.Breached("checkout", "charge"))
+}
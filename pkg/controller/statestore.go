@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrStateStoreTimeout is returned when the expression state could not be read from the state store in time.
+var ErrStateStoreTimeout = errors.New("timed out reading the expression state")
+
+// MaxPendingStateStoreReads is the maximum number of bounded reads of the expression state store that are in progress
+// at the same time, including the reads that have been abandoned after they timed out.
+const MaxPendingStateStoreReads = 32
+
+var (
+	metricStateStoreReadDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "state_store_read_duration_seconds",
+		Help:      "Duration of the reads of the expression state store, including the reads that timed out",
+		Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5},
+	})
+	metricStateStoreTimeouts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "state_store_read_timeouts_total",
+		Help:      "Number of reads of the expression state store that exceeded the timeout",
+	})
+	metricStateStoreDegraded = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "state_store_degraded",
+		Help:      "Whether the last read of the expression state store exceeded the timeout (1) or not (0)",
+	})
+	metricStateStoreDeferredEvaluations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "state_store_deferred_evaluations_total",
+		Help:      "Number of evaluations that were deferred because the expression state store was degraded",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(metricStateStoreReadDuration, metricStateStoreTimeouts, metricStateStoreDegraded,
+		metricStateStoreDeferredEvaluations)
+}
+
+// StateStoreMonitor bounds the reads of the expression state store, which keeps a slow store from blocking the
+// workers of the controller. Once a read exceeds the timeout, the store is considered degraded until a read completes
+// in time again. While the store is degraded, the evaluations that depend on it probe the store before scheduling
+// any task, and are deferred if the probe times out as well.
+//
+// A read that times out is abandoned, rather than canceled; it completes in the background, and its duration is
+// still observed. The number of reads that are in progress is bounded by MaxPendingStateStoreReads; once a hanging
+// store has reached this bound, reads fail immediately with ErrStateStoreTimeout rather than piling up goroutines.
+//
+// A StateStoreMonitor without a timeout reads the store without a bound, and is never degraded. A nil
+// StateStoreMonitor admits all invocations.
+type StateStoreMonitor struct {
+	get      func(id string) (*expr.Scope, bool)
+	timeout  time.Duration
+	degraded int32
+	pending  int32
+}
+
+// NewStateStoreMonitor creates a StateStoreMonitor that bounds the reads of the store, using the provided function,
+// by the timeout.
+func NewStateStoreMonitor(get func(id string) (*expr.Scope, bool), timeout time.Duration) *StateStoreMonitor {
+	return &StateStoreMonitor{
+		get:     get,
+		timeout: timeout,
+	}
+}
+
+// Get reads the expression state with the given ID from the store. It returns ErrStateStoreTimeout if the read did
+// not complete within the timeout.
+func (m *StateStoreMonitor) Get(id string) (*expr.Scope, bool, error) {
+	if m.timeout <= 0 {
+		start := time.Now()
+		scope, ok := m.get(id)
+		metricStateStoreReadDuration.Observe(time.Since(start).Seconds())
+		return scope, ok, nil
+	}
+
+	type result struct {
+		scope *expr.Scope
+		ok    bool
+	}
+	if atomic.AddInt32(&m.pending, 1) > MaxPendingStateStoreReads {
+		atomic.AddInt32(&m.pending, -1)
+		metricStateStoreTimeouts.Inc()
+		m.setDegraded(true)
+		return nil, false, ErrStateStoreTimeout
+	}
+	resultC := make(chan result, 1)
+	start := time.Now()
+	ctrl.Go("statestore", func() {
+		defer atomic.AddInt32(&m.pending, -1)
+		scope, ok := m.get(id)
+		metricStateStoreReadDuration.Observe(time.Since(start).Seconds())
+		resultC <- result{scope, ok}
+	})
+
+	timer := time.NewTimer(m.timeout)
+	defer timer.Stop()
+	select {
+	case r := <-resultC:
+		m.setDegraded(false)
+		return r.scope, r.ok, nil
+	case <-timer.C:
+		metricStateStoreTimeouts.Inc()
+		m.setDegraded(true)
+		return nil, false, ErrStateStoreTimeout
+	}
+}
+
+// Degraded returns whether the last read of the store exceeded the timeout.
+func (m *StateStoreMonitor) Degraded() bool {
+	return m != nil && atomic.LoadInt32(&m.degraded) == 1
+}
+
+// Admit returns whether the tasks of the invocation can be scheduled. Only the sub-invocations read the state store
+// while scheduling their tasks, for the scope of their parent. While the store is degraded, their parent scope is
+// probed first; if the probe times out, the evaluation is deferred until the store has recovered.
+func (m *StateStoreMonitor) Admit(invocation *types.WorkflowInvocation) bool {
+	parentID := invocation.GetSpec().GetParentId()
+	if len(parentID) == 0 || !m.Degraded() {
+		return true
+	}
+	if _, _, err := m.Get(parentID); err != nil {
+		metricStateStoreDeferredEvaluations.Inc()
+		return false
+	}
+	return true
+}
+
+func (m *StateStoreMonitor) setDegraded(degraded bool) {
+	if degraded {
+		atomic.StoreInt32(&m.degraded, 1)
+		metricStateStoreDegraded.Set(1)
+	} else {
+		atomic.StoreInt32(&m.degraded, 0)
+		metricStateStoreDegraded.Set(0)
+	}
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateStoreMonitor(t *testing.T) {
+	var delay int64
+	scope := &expr.Scope{}
+	monitor := NewStateStoreMonitor(func(id string) (*expr.Scope, bool) {
+		time.Sleep(time.Duration(atomic.LoadInt64(&delay)))
+		return scope, id == "parent"
+	}, 50*time.Millisecond)
+	child := &types.WorkflowInvocation{Spec: &types.WorkflowInvocationSpec{ParentId: "parent"}}
+
+	found, ok, err := monitor.Get("parent")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, scope, found)
+	assert.False(t, monitor.Degraded())
+
+	atomic.StoreInt64(&delay, int64(200*time.Millisecond))
+	_, _, err = monitor.Get("parent")
+	assert.Equal(t, ErrStateStoreTimeout, err)
+	assert.True(t, monitor.Degraded())
+
+	// While the store is degraded, sub-invocations are deferred until a probe succeeds.
+	assert.False(t, monitor.Admit(child))
+	assert.True(t, monitor.Admit(&types.WorkflowInvocation{Spec: &types.WorkflowInvocationSpec{}}))
+
+	atomic.StoreInt64(&delay, 0)
+	assert.True(t, monitor.Admit(child))
+	assert.False(t, monitor.Degraded())
+}
+
+func TestStateStoreMonitor_Unbounded(t *testing.T) {
+	monitor := NewStateStoreMonitor(func(id string) (*expr.Scope, bool) {
+		time.Sleep(10 * time.Millisecond)
+		return nil, false
+	}, 0)
+	_, ok, err := monitor.Get("parent")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, monitor.Degraded())
+
+	var disabled *StateStoreMonitor
+	assert.True(t, disabled.Admit(&types.WorkflowInvocation{Spec: &types.WorkflowInvocationSpec{ParentId: "parent"}}))
+}
+
+func TestStateStoreMonitor_BoundedGoroutines(t *testing.T) {
+	release := make(chan struct{})
+	monitor := NewStateStoreMonitor(func(id string) (*expr.Scope, bool) {
+		<-release
+		return nil, false
+	}, time.Millisecond)
+	_, _, err := monitor.Get("parent")
+	assert.Equal(t, ErrStateStoreTimeout, err)
+
+	// A flood of sub-invocations that are not ready, as their parent scope cannot be read, does not pile up reads.
+	before, owned := runtime.NumGoroutine(), ctrl.Goroutines("statestore")
+	child := &types.WorkflowInvocation{Spec: &types.WorkflowInvocationSpec{ParentId: "parent"}}
+	for i := 0; i < 10*MaxPendingStateStoreReads; i++ {
+		assert.False(t, monitor.Admit(child))
+	}
+	assert.True(t, runtime.NumGoroutine()-before < MaxPendingStateStoreReads)
+	assert.True(t, ctrl.Goroutines("statestore")-owned < MaxPendingStateStoreReads)
+
+	close(release)
+	for i := 0; i < 500 && ctrl.Goroutines("statestore") > 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, ctrl.Goroutines("statestore"))
+}
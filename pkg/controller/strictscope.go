@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricScopeWarnings = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "controller",
+	Name:      "scope_warnings_total",
+	Help:      "Number of times that the inputs of a task referenced undefined keys of the scope, by scope strictness",
+}, []string{"strictness"})
+
+func init() {
+	prometheus.MustRegister(metricScopeWarnings)
+}
+
+// UndefinedReferencesError indicates that the inputs of a task reference keys that are undefined in the scope, in a
+// workflow with the fail scope strictness.
+type UndefinedReferencesError struct {
+	TaskID     string
+	References []string
+}
+
+func (e *UndefinedReferencesError) Error() string {
+	return fmt.Sprintf("inputs of task %s reference undefined keys: %s", e.TaskID, strings.Join(e.References, ", "))
+}
+
+// checkScope checks the input expressions of the task, including the sources of its input merges, for references to
+// keys that are undefined in the scope, if the workflow of the invocation has a scope strictness. The references are
+// recorded as a warning in the status of the invocation, unless the same warning has already been recorded. With the
+// fail strictness, an UndefinedReferencesError is returned as well.
+func (c *InvocationController) checkScope(invocation *types.WorkflowInvocation, taskID string, scope *expr.Scope,
+	inputs map[string]*typedvalues.TypedValue, merges map[string]*types.InputMerge) error {
+	strictness := invocation.Workflow().GetSpec().GetScopeStrictness()
+	if len(strictness) == 0 {
+		return nil
+	}
+
+	exprs := make(map[string][]*typedvalues.TypedValue, len(inputs)+len(merges))
+	for key, input := range inputs {
+		exprs[key] = append(exprs[key], input)
+	}
+	for key, merge := range merges {
+		exprs[key] = append(exprs[key], merge.GetSources()...)
+	}
+	var keys []string
+	refs := map[string]struct{}{}
+	for key, values := range exprs {
+		var undefinedInInput bool
+		for _, value := range values {
+			undefined, err := expr.UndefinedReferences(scope, taskID, value)
+			if err != nil {
+				return fmt.Errorf("failed to check input field %v for undefined references: %v", key, err)
+			}
+			for _, ref := range undefined {
+				undefinedInInput = true
+				refs[ref] = struct{}{}
+			}
+		}
+		if undefinedInInput {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	references := make([]string, 0, len(refs))
+	for ref := range refs {
+		references = append(references, ref)
+	}
+	sort.Strings(references)
+
+	metricScopeWarnings.WithLabelValues(strictness).Inc()
+	c.logger.Warnf("Inputs %v of task %s reference undefined keys: %v", keys, taskID, references)
+	if !scopeWarningRecorded(invocation, taskID, keys, references) {
+		err := c.invocationAPI.WarnUndefinedReferences(invocation.ID(), taskID, keys, references)
+		if err != nil {
+			c.logger.Errorf("Failed to record the undefined references of task %s: %v", taskID, err)
+		}
+	}
+	if strictness == types.ScopeStrictnessFail {
+		return &UndefinedReferencesError{TaskID: taskID, References: references}
+	}
+	return nil
+}
+
+// scopeWarningRecorded returns whether the status of the invocation already contains the warning for the task, which
+// is the case when the inputs of the task are resolved again, such as for a retry or a poll of a sensor.
+func scopeWarningRecorded(invocation *types.WorkflowInvocation, taskID string, keys []string,
+	references []string) bool {
+	warning, ok := invocation.GetStatus().GetScopeWarnings()[taskID]
+	return ok && equalStrings(warning.GetInputs(), keys) && equalStrings(warning.GetReferences(), references)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// execStrictTask executes the task of an invocation of a workflow with the scope strictness, of which an input and the
+// source of an input merge reference a misspelled task. It returns the projection of the invocation, and the number of scope warnings that
+// were recorded.
+func execStrictTask(t *testing.T, strictness string, times int) (*types.WorkflowInvocation, int) {
+	runtime := mock.NewRuntime()
+	runtime.Functions["notify"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		return typedvalues.MustWrap("notified"), nil
+	}
+	backend := mem.NewBackend()
+	invocationAPI := api.NewInvocationAPI(backend)
+	taskAPI := api.NewTaskAPI(map[string]fnenv.Runtime{"mock": runtime}, backend, nil)
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.ScopeStrictness = strictness
+	wfSpec.AddTask("notify", &types.TaskSpec{
+		FunctionRef: "notify",
+		Inputs: typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+			types.InputMain: "{ output('fetsh') }",
+			"user":          "{ param('user') }",
+		}),
+		MergeInputs: map[string]*types.InputMerge{
+			"options": {Sources: []*typedvalues.TypedValue{typedvalues.MustWrap("{ output('fetsh') }")}},
+		},
+	})
+	wfSpec.OutputTask = "notify"
+	wfStatus := &types.WorkflowStatus{Tasks: map[string]*types.Task{
+		"notify": {Status: &types.TaskStatus{FnRef: &types.FnRef{Runtime: "mock", ID: "notify"}}},
+	}}
+	spec := types.NewWorkflowInvocationSpec("wf", time.Now().Add(time.Minute))
+	spec.Inputs = typedvalues.MustWrapMapTypedValue(map[string]interface{}{"user": "alice"})
+	spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata("wf"), Spec: wfSpec, Status: wfStatus}
+	invocationID, err := invocationAPI.Invoke(spec)
+	assert.NoError(t, err)
+
+	c := NewInvocationController(invocationID, executor.NewLocalExecutor(1, 10), invocationAPI, taskAPI,
+		scheduler.NewInvocationScheduler(scheduler.NewHorizonPolicy()), expr.NewStore(), opentracing.StartSpan("wi"),
+		TraceDecision{}, logrus.WithField("key", "wi"), InvocationConfig{})
+	project := func() *types.WorkflowInvocation {
+		invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+		assert.NoError(t, err)
+		entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+		assert.NoError(t, err)
+		return entity.(*types.WorkflowInvocation)
+	}
+	for i := 0; i < times; i++ {
+		assert.NoError(t, c.execTask(project(), "notify"))
+	}
+
+	invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(invocationID))
+	assert.NoError(t, err)
+	var warnings int
+	for _, event := range invocationEvents {
+		if event.Type == events.EventInvocationScopeWarning {
+			warnings++
+		}
+	}
+	return project(), warnings
+}
+
+func TestCheckScope_Warn(t *testing.T) {
+	invocation, warnings := execStrictTask(t, types.ScopeStrictnessWarn, 2)
+
+	// The task is executed regardless of the undefined reference, which is only recorded once.
+	assert.Equal(t, 1, warnings)
+	warning := invocation.GetStatus().GetScopeWarnings()["notify"]
+	assert.Equal(t, []string{types.InputMain, "options"}, warning.GetInputs())
+	assert.Equal(t, []string{"output('fetsh')"}, warning.GetReferences())
+	assert.NotNil(t, warning.GetRecordedAt())
+	run, ok := invocation.TaskInvocation("notify")
+	assert.True(t, ok)
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED, run.GetStatus().GetStatus())
+}
+
+func TestCheckScope_Fail(t *testing.T) {
+	invocation, warnings := execStrictTask(t, types.ScopeStrictnessFail, 1)
+
+	assert.Equal(t, 1, warnings)
+	run, ok := invocation.TaskInvocation("notify")
+	assert.True(t, ok)
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, run.GetStatus().GetStatus())
+	assert.Equal(t, types.ErrorCodeInput, run.GetStatus().GetError().GetCode())
+	assert.Contains(t, run.GetStatus().GetError().GetMessage(), "output('fetsh')")
+}
+
+func TestCheckScope_Disabled(t *testing.T) {
+	invocation, warnings := execStrictTask(t, "", 1)
+
+	assert.Equal(t, 0, warnings)
+	assert.Empty(t, invocation.GetStatus().GetScopeWarnings())
+	run, ok := invocation.TaskInvocation("notify")
+	assert.True(t, ok)
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED, run.GetStatus().GetStatus())
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+var (
+	metricSuspendedFunctions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "suspended_functions",
+		Help:      "Number of functions of which the scheduling of tasks is suspended",
+	})
+	metricSuspendedTasks = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      "suspended_tasks",
+		Help:      "Number of tasks that are waiting for their function to be resumed",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(metricSuspendedFunctions, metricSuspendedTasks)
+}
+
+// SuspendedFunction describes a function of which the scheduling of tasks is suspended.
+type SuspendedFunction struct {
+	FnRef        string
+	Since        time.Time
+	WaitingTasks int
+}
+
+// FunctionSuspensions keeps track of the functions that are suspended, for example during planned maintenance.
+//
+// Tasks that reference a suspended function are not failed, but are deferred by the invocation controller until the
+// function is resumed, or until the invocation exceeds its deadline.
+//
+// A nil FunctionSuspensions does not suspend any function.
+type FunctionSuspensions struct {
+	suspended map[string]time.Time
+
+	// waiting contains per invocation the tasks that are waiting on a suspended function, and the function they
+	// are waiting on.
+	waiting map[string]map[string]string
+	clock   clock.Clock
+	mu      *sync.RWMutex
+}
+
+func NewFunctionSuspensions() *FunctionSuspensions {
+	return &FunctionSuspensions{
+		suspended: map[string]time.Time{},
+		waiting:   map[string]map[string]string{},
+		clock:     clock.RealClock{},
+		mu:        &sync.RWMutex{},
+	}
+}
+
+// SetClock sets the clock from which the time of the suspensions is read, which should be the clock of the
+// controller. By default, the real clock is used.
+func (s *FunctionSuspensions) SetClock(clock clock.Clock) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.clock = clock
+	s.mu.Unlock()
+}
+
+// Suspend suspends the scheduling of tasks that reference the function.
+func (s *FunctionSuspensions) Suspend(fnRef string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.suspended[fnRef]; !ok {
+		s.suspended[fnRef] = s.clock.Now()
+	}
+	metricSuspendedFunctions.Set(float64(len(s.suspended)))
+}
+
+// Resume resumes the scheduling of tasks that reference the function. It returns the IDs of the invocations that
+// have tasks waiting on the function, which should be re-evaluated to schedule these tasks without delay.
+func (s *FunctionSuspensions) Resume(fnRef string) (invocationIDs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.suspended, fnRef)
+	metricSuspendedFunctions.Set(float64(len(s.suspended)))
+	for invocationID, tasks := range s.waiting {
+		for _, waitingOn := range tasks {
+			if waitingOn == fnRef {
+				invocationIDs = append(invocationIDs, invocationID)
+				break
+			}
+		}
+	}
+	sort.Strings(invocationIDs)
+	return invocationIDs
+}
+
+// SuspendedFnRef returns the suspended function that the task references, if any. A task is matched both by the
+// function reference in its spec and by the resolved function reference.
+func (s *FunctionSuspensions) SuspendedFnRef(task *types.Task) (fnRef string, suspended bool) {
+	if s == nil {
+		return "", false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.suspended) == 0 {
+		return "", false
+	}
+	fnRef = task.GetSpec().GetFunctionRef()
+	if _, ok := s.suspended[fnRef]; ok {
+		return fnRef, true
+	}
+	if resolved := task.GetStatus().GetFnRef(); resolved != nil {
+		fnRef = resolved.Format()
+		if _, ok := s.suspended[fnRef]; ok {
+			return fnRef, true
+		}
+	}
+	return "", false
+}
+
+// SetWaiting replaces the tasks of the invocation that are waiting on suspended functions, mapping the task IDs to
+// the functions. A nil or empty tasks map clears the waiting tasks of the invocation.
+func (s *FunctionSuspensions) SetWaiting(invocationID string, tasks map[string]string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(tasks) == 0 {
+		delete(s.waiting, invocationID)
+	} else {
+		s.waiting[invocationID] = tasks
+	}
+	var count int
+	for _, tasks := range s.waiting {
+		count += len(tasks)
+	}
+	metricSuspendedTasks.Set(float64(count))
+}
+
+// List returns the suspended functions, ordered by their function reference.
+func (s *FunctionSuspensions) List() []SuspendedFunction {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	waitingTasks := map[string]int{}
+	for _, tasks := range s.waiting {
+		for _, fnRef := range tasks {
+			waitingTasks[fnRef]++
+		}
+	}
+	var result []SuspendedFunction
+	for fnRef, since := range s.suspended {
+		result = append(result, SuspendedFunction{
+			FnRef:        fnRef,
+			Since:        since,
+			WaitingTasks: waitingTasks[fnRef],
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FnRef < result[j].FnRef
+	})
+	return result
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionSuspensions(t *testing.T) {
+	suspensions := NewFunctionSuspensions()
+	task := &types.Task{
+		Spec: &types.TaskSpec{FunctionRef: "payments"},
+		Status: &types.TaskStatus{
+			FnRef: &types.FnRef{Runtime: "fission", Namespace: "default", ID: "payments"},
+		},
+	}
+	_, suspended := suspensions.SuspendedFnRef(task)
+	assert.False(t, suspended)
+
+	// The task is matched by its resolved function reference as well.
+	suspensions.Suspend("fission://default/payments")
+	fnRef, suspended := suspensions.SuspendedFnRef(task)
+	assert.True(t, suspended)
+	assert.Equal(t, "fission://default/payments", fnRef)
+
+	suspensions.SetWaiting("wi-1", map[string]string{"charge": fnRef, "refund": fnRef})
+	suspensions.SetWaiting("wi-2", map[string]string{"charge": fnRef})
+	list := suspensions.List()
+	assert.Len(t, list, 1)
+	assert.Equal(t, 3, list[0].WaitingTasks)
+
+	// Finished invocations no longer wait on the function.
+	suspensions.SetWaiting("wi-2", nil)
+	assert.Equal(t, 2, suspensions.List()[0].WaitingTasks)
+
+	assert.Equal(t, []string{"wi-1"}, suspensions.Resume(fnRef))
+	_, suspended = suspensions.SuspendedFnRef(task)
+	assert.False(t, suspended)
+	assert.Empty(t, suspensions.List())
+}
+
+func TestFunctionSuspensions_Nil(t *testing.T) {
+	var suspensions *FunctionSuspensions
+	_, suspended := suspensions.SuspendedFnRef(&types.Task{Spec: &types.TaskSpec{FunctionRef: "payments"}})
+	assert.False(t, suspended)
+	suspensions.SetWaiting("wi-1", map[string]string{"charge": "payments"})
+	assert.Empty(t, suspensions.List())
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/store"
+)
+
+// UpdatesMode determines how the invocation controller learns about updates of invocations.
+type UpdatesMode string
+
+const (
+	// UpdatesModeAuto subscribes to the updates of the invocation cache if the cache supports it, and otherwise
+	// falls back to polling the invocation cache at the regular cadence.
+	UpdatesModeAuto UpdatesMode = "auto"
+
+	// UpdatesModePush requires the invocation cache to support subscribing to updates; the controller cannot be
+	// started otherwise.
+	UpdatesModePush UpdatesMode = "push"
+
+	// UpdatesModePolling does not subscribe to updates, but polls the invocation cache at the polling interval of the
+	// controller, which is typically faster than the regular cadence to compensate for the lack of notifications.
+	UpdatesModePolling UpdatesMode = "polling"
+)
+
+// DefaultPollingInterval is the interval at which the invocation cache is polled in the polling updates mode.
+const DefaultPollingInterval = 250 * time.Millisecond
+
+var ErrPushUpdatesUnsupported = errors.New("invocation cache does not support push-based updates")
+
+// ParseUpdatesMode parses the updates mode. An empty mode is parsed as UpdatesModeAuto.
+func ParseUpdatesMode(s string) (UpdatesMode, error) {
+	switch mode := UpdatesMode(s); mode {
+	case "":
+		return UpdatesModeAuto, nil
+	case UpdatesModeAuto, UpdatesModePush, UpdatesModePolling:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown updates mode '%s' (expected one of: %s, %s, %s)", s, UpdatesModeAuto,
+			UpdatesModePush, UpdatesModePolling)
+	}
+}
+
+// resolveUpdatesMode determines the effective updates mode given the capabilities of the invocation cache: either
+// UpdatesModePush or UpdatesModePolling.
+func resolveUpdatesMode(mode UpdatesMode, invocations *store.Invocations) (UpdatesMode, error) {
+	switch mode {
+	case UpdatesModePolling:
+		return UpdatesModePolling, nil
+	case UpdatesModePush:
+		if !invocations.SupportsUpdates() {
+			return "", ErrPushUpdatesUnsupported
+		}
+		return UpdatesModePush, nil
+	case UpdatesModeAuto, "":
+		if !invocations.SupportsUpdates() {
+			return UpdatesModePolling, nil
+		}
+		return UpdatesModePush, nil
+	default:
+		return "", fmt.Errorf("unknown updates mode '%s'", mode)
+	}
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fes/cache"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveUpdatesMode(t *testing.T) {
+	mode, err := ParseUpdatesMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, UpdatesModeAuto, mode)
+	_, err = ParseUpdatesMode("pull")
+	assert.Error(t, err)
+
+	// A cache without pubsub support only supports polling.
+	invocations := store.NewInvocationStore(testutil.NewCache())
+	_, err = resolveUpdatesMode(UpdatesModePush, invocations)
+	assert.Equal(t, ErrPushUpdatesUnsupported, err)
+	mode, err = resolveUpdatesMode(UpdatesModeAuto, invocations)
+	assert.NoError(t, err)
+	assert.Equal(t, UpdatesModePolling, mode)
+
+	// Polling can be selected explicitly, even if the cache supports pubsub.
+	backend := mem.NewBackend()
+	invocations = store.NewInvocationStore(cache.NewSubscribedCache(testutil.NewCache(),
+		projectors.NewWorkflowInvocation(), backend.Subscribe()))
+	mode, err = resolveUpdatesMode(UpdatesModeAuto, invocations)
+	assert.NoError(t, err)
+	assert.Equal(t, UpdatesModePush, mode)
+	mode, err = resolveUpdatesMode(UpdatesModePolling, invocations)
+	assert.NoError(t, err)
+	assert.Equal(t, UpdatesModePolling, mode)
+}
@@ -18,15 +18,18 @@ import (
 )
 
 const (
-	EventRefresh = "refresh"
-	parseTask    = "task"
+	EventRefresh    = "refresh"
+	EventReevaluate = "reevaluate"
+	parseTask       = "task"
+
+	parseBackoffStep = time.Second
+	maxParseBackoff  = 5 * time.Minute
 )
 
 // WorkflowController is the controller for ensuring the processing of a single workflow.
 type WorkflowController struct {
 	api        *api.Workflow
 	executor   *executor.LocalExecutor
-	errorCount int
 	workflowID string
 }
 
@@ -56,8 +59,9 @@ func (c *WorkflowController) Eval(ctx context.Context, processValue *ctrl.Event)
 		return ctrl.Done{}
 	case types.WorkflowStatus_FAILED:
 		// The previous parsing has failed. We retry the parsing but with a increasing backoff.
-		backoff := time.Duration(c.errorCount) * time.Second
-		log.Infof("Backing off for %v before trying to parse workflow again", backoff)
+		backoff := parseBackoff(workflow.GetStatus(), c.executor.Clock().Now())
+		log.Infof("Backing off for %v before trying to parse workflow again (attempt %d)", backoff,
+			workflow.GetStatus().GetParseAttempts()+1)
 		c.executor.SubmitAfter(&executor.Task{
 			TaskID:  workflow.ID() + "." + parseTask,
 			GroupID: workflow.ID(),
@@ -69,7 +73,6 @@ func (c *WorkflowController) Eval(ctx context.Context, processValue *ctrl.Event)
 		return ctrl.Success{Msg: "retrying parsing of the workflow"}
 	case types.WorkflowStatus_QUEUED:
 		// The workflow has not yet been processed, so we try to parse it.
-		c.errorCount = 0
 		c.executor.Submit(&executor.Task{
 			TaskID:  workflow.ID() + "." + parseTask,
 			GroupID: workflow.ID(),
@@ -84,6 +87,25 @@ func (c *WorkflowController) Eval(ctx context.Context, processValue *ctrl.Event)
 	}
 }
 
+// parseBackoff returns the remaining duration to back off before the next attempt to parse the workflow.
+//
+// The backoff is derived from the failed attempts recorded in the status of the workflow rather than from the state of
+// the controller, to ensure that the backoff is retained when the controller restarts.
+func parseBackoff(status *types.WorkflowStatus, now time.Time) time.Duration {
+	backoff := time.Duration(status.GetParseAttempts()) * parseBackoffStep
+	if backoff > maxParseBackoff {
+		backoff = maxParseBackoff
+	}
+	failedAt, err := ptypes.Timestamp(status.GetUpdatedAt())
+	if err != nil {
+		return backoff
+	}
+	if remaining := failedAt.Add(backoff).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
 // WorkflowMetaController is the component responsible for the full integration of the workflows reconciliation loop.
 //
 // Specifically, the meta-controller is responsible for the following:
@@ -102,6 +124,11 @@ type WorkflowMetaController struct {
 func NewWorkflowMetaController(api *api.Workflow, workflows *store.Workflows, executor *executor.LocalExecutor,
 	storePollInterval time.Duration) *WorkflowMetaController {
 
+	system := ctrl.NewSystem(func(event *ctrl.Event) (ctrl ctrl.Controller, err error) {
+		return NewWorkflowController(api, executor, event.Aggregate.Id), nil
+	})
+	// The backoff of the parsing is measured with the clock of the executor, which delays the parse tasks.
+	system.SetClock(executor.Clock())
 	return &WorkflowMetaController{
 		api:       api,
 		executor:  executor,
@@ -111,9 +138,7 @@ func NewWorkflowMetaController(api *api.Workflow, workflows *store.Workflows, ex
 			NewWorkflowNotificationSensor(workflows),
 			NewWorkflowStorePollSensor(workflows, storePollInterval),
 		},
-		system: ctrl.NewSystem(func(event *ctrl.Event) (ctrl ctrl.Controller, err error) {
-			return NewWorkflowController(api, executor, event.Aggregate.Id), nil
-		}),
+		system: system,
 	}
 }
 
@@ -161,7 +186,7 @@ func NewWorkflowNotificationSensor(workflows *store.Workflows) *WorkflowNotifica
 }
 
 func (s *WorkflowNotificationSensor) Start(evalQueue ctrl.EvalQueue) error {
-	go s.Run(evalQueue)
+	ctrl.Go("sensors", func() { s.Run(evalQueue) })
 	return nil
 }
 
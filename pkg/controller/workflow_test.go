@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWorkflowEvent(t *testing.T, at time.Time, payload proto.Message) *fes.Event {
+	event, err := fes.NewEvent(projectors.NewWorkflowAggregate("wf-1"), payload)
+	assert.NoError(t, err)
+	ts, err := ptypes.TimestampProto(at)
+	assert.NoError(t, err)
+	event.Timestamp = ts
+	return event
+}
+
+// TestParseBackoff_Restart verifies that the backoff of a workflow that failed to parse is retained when the
+// controller restarts halfway through the backoff.
+func TestParseBackoff_Restart(t *testing.T) {
+	now := time.Now()
+	failed := &events.WorkflowParsingFailed{Error: &types.Error{Message: "function not found"}}
+	history := []*fes.Event{
+		newWorkflowEvent(t, now.Add(-time.Minute), &events.WorkflowCreated{Spec: types.NewWorkflowSpec()}),
+		newWorkflowEvent(t, now.Add(-50*time.Second), failed),
+		newWorkflowEvent(t, now.Add(-40*time.Second), failed),
+		newWorkflowEvent(t, now.Add(-time.Second), failed),
+	}
+
+	// After the restart, the state of the workflow is recovered by replaying its events.
+	entity, err := projectors.NewWorkflow().Project(nil, history...)
+	assert.NoError(t, err)
+	wf := entity.(*types.Workflow)
+	assert.Equal(t, types.WorkflowStatus_FAILED, wf.GetStatus().GetStatus())
+	assert.EqualValues(t, 3, wf.GetStatus().GetParseAttempts())
+
+	// The third attempt failed a second ago, so 2 of the 3 seconds of backoff remain.
+	assert.Equal(t, 2*time.Second, parseBackoff(wf.GetStatus(), now))
+	assert.Equal(t, time.Duration(0), parseBackoff(wf.GetStatus(), now.Add(time.Minute)))
+
+	// Recreating the workflow resets the attempts.
+	entity, err = projectors.NewWorkflow().Project(wf, newWorkflowEvent(t, now,
+		&events.WorkflowCreated{Spec: types.NewWorkflowSpec()}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, entity.(*types.Workflow).GetStatus().GetParseAttempts())
+}
+
+func TestParseBackoff_Max(t *testing.T) {
+	status := &types.WorkflowStatus{ParseAttempts: 1000}
+	assert.Equal(t, maxParseBackoff, parseBackoff(status, time.Now()))
+}
@@ -0,0 +1,303 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OtherWorkflowsLabel is the workflow label of the per-workflow metrics of the workflows that are rolled up.
+const OtherWorkflowsLabel = "other"
+
+// timedOutStatusLabel is the status label of the invocations that failed because they exceeded their deadline.
+const timedOutStatusLabel = "TIMED_OUT"
+
+// The names of the per-workflow metrics, without the namespace and subsystem, which are referenced by the generated
+// dashboards (see WorkflowDashboard).
+const (
+	workflowInvocationsFinishedName = "workflow_invocations_finished_total"
+	workflowInvocationDurationName  = "workflow_invocation_duration_seconds"
+	workflowTaskDurationName        = "workflow_task_duration_seconds"
+	workflowTaskRetriesName         = "workflow_task_retries_total"
+)
+
+// workflowDurationObjectives are the quantiles of the per-workflow durations.
+var workflowDurationObjectives = map[float64]float64{0.5: 0.05, 0.95: 0.005, 0.99: 0.001}
+
+var (
+	metricWorkflowInvocationsFinished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      workflowInvocationsFinishedName,
+		Help:      "Number of finished invocations per workflow and final status (with TIMED_OUT for timed out invocations)",
+	}, []string{"workflow", "status"})
+	metricWorkflowInvocationDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace:  "workflows",
+		Subsystem:  "controller",
+		Name:       workflowInvocationDurationName,
+		Help:       "Duration of the finished invocations per workflow, from creation until completion",
+		Objectives: workflowDurationObjectives,
+	}, []string{"workflow"})
+	metricWorkflowTaskDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace:  "workflows",
+		Subsystem:  "controller",
+		Name:       workflowTaskDurationName,
+		Help:       "Duration of the task runs per workflow and task, for the workflows that have their own series",
+		Objectives: workflowDurationObjectives,
+	}, []string{"workflow", "task"})
+	metricWorkflowTaskRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "controller",
+		Name:      workflowTaskRetriesName,
+		Help:      "Number of retries of failed tasks per workflow and task (with task 'other' for rolled up workflows)",
+	}, []string{"workflow", "task"})
+)
+
+func init() {
+	prometheus.MustRegister(metricWorkflowInvocationsFinished, metricWorkflowInvocationDuration,
+		metricWorkflowTaskDuration, metricWorkflowTaskRetries)
+}
+
+// WorkflowMetricsConfig configures which workflows have their own series in the per-workflow metrics.
+type WorkflowMetricsConfig struct {
+	// Workflows contains the IDs of the workflows that have opted in to per-workflow metrics.
+	Workflows []string
+
+	// Threshold is the number of finished invocations after which a workflow that has not opted in gets its own
+	// series. If 0, only the workflows that have opted in get their own series.
+	Threshold int
+
+	// Limit is the maximum number of workflows that get their own series by exceeding the threshold. The workflows
+	// that have opted in do not count towards the limit. If 0, the number of these workflows is not limited.
+	Limit int
+}
+
+// WorkflowMetrics bounds the cardinality of the per-workflow metrics of the controller.
+//
+// With thousands of workflows, labeling the metrics with the ID of every workflow can overwhelm the metrics backend.
+// Instead, only the workflows that have opted in, or that have exceeded the traffic threshold, are labeled with their
+// own ID; the metrics of all other workflows are rolled up under OtherWorkflowsLabel. The configuration can be changed
+// at runtime, in which case the series of the workflows that no longer qualify are removed.
+//
+// Note that the invocations of a workflow that finished before it exceeded the threshold remain counted under
+// OtherWorkflowsLabel.
+//
+// A nil WorkflowMetrics rolls up the metrics of all workflows.
+type WorkflowMetrics struct {
+	config WorkflowMetricsConfig
+	optIn  map[string]struct{}
+
+	// finished counts the finished invocations per workflow, for the workflows that have not opted in.
+	finished map[string]int
+
+	// labeled contains the workflows that currently have their own series.
+	labeled map[string]struct{}
+
+	// tasks contains the tasks of the labeled workflows that have task series.
+	tasks map[string]map[string]struct{}
+
+	// promoted is the number of labeled workflows that exceeded the threshold.
+	promoted int
+	mu       *sync.Mutex
+}
+
+func NewWorkflowMetrics(config WorkflowMetricsConfig) *WorkflowMetrics {
+	m := &WorkflowMetrics{
+		finished: map[string]int{},
+		labeled:  map[string]struct{}{},
+		tasks:    map[string]map[string]struct{}{},
+		mu:       &sync.Mutex{},
+	}
+	m.Configure(config)
+	return m
+}
+
+// Configure replaces the configuration. The series of the workflows that no longer qualify for their own series
+// are removed; their finished invocations continue to be counted towards the threshold.
+func (m *WorkflowMetrics) Configure(config WorkflowMetricsConfig) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if config.Threshold < 0 {
+		config.Threshold = 0
+	}
+	if config.Limit < 0 {
+		config.Limit = 0
+	}
+	m.config = config
+	m.optIn = make(map[string]struct{}, len(config.Workflows))
+	for _, wfID := range config.Workflows {
+		m.optIn[wfID] = struct{}{}
+	}
+
+	// Re-evaluate the labeled workflows against the new configuration, retaining the most active workflows if the
+	// limit has been lowered.
+	var candidates []string
+	for wfID := range m.labeled {
+		if _, ok := m.optIn[wfID]; !ok {
+			candidates = append(candidates, wfID)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return m.finished[candidates[i]] > m.finished[candidates[j]]
+	})
+	m.promoted = 0
+	for _, wfID := range candidates {
+		if m.exceedsThreshold(wfID) && (config.Limit == 0 || m.promoted < config.Limit) {
+			m.promoted++
+			continue
+		}
+		delete(m.labeled, wfID)
+		deleteWorkflowSeries(wfID, m.tasks[wfID])
+		delete(m.tasks, wfID)
+	}
+	for wfID := range m.optIn {
+		m.labeled[wfID] = struct{}{}
+	}
+}
+
+// Config returns the current configuration.
+func (m *WorkflowMetrics) Config() WorkflowMetricsConfig {
+	if m == nil {
+		return WorkflowMetricsConfig{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	config := m.config
+	config.Workflows = append([]string{}, m.config.Workflows...)
+	return config
+}
+
+// Label returns the label under which the metrics of the workflow are reported, either the ID of the workflow or
+// OtherWorkflowsLabel.
+func (m *WorkflowMetrics) Label(workflowID string) string {
+	if m == nil {
+		return OtherWorkflowsLabel
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.label(workflowID)
+}
+
+// ObserveFinished records the metrics of a finished invocation, and counts the invocation towards the threshold of
+// its workflow.
+func (m *WorkflowMetrics) ObserveFinished(invocation *types.WorkflowInvocation) {
+	wfID := invocation.GetSpec().GetWorkflowId()
+	label := OtherWorkflowsLabel
+	if m != nil {
+		m.mu.Lock()
+		if _, ok := m.optIn[wfID]; !ok {
+			m.finished[wfID]++
+		}
+		label = m.label(wfID)
+		m.mu.Unlock()
+	}
+
+	metricWorkflowInvocationsFinished.WithLabelValues(label, invocationStatusLabel(invocation)).Inc()
+	if duration, ok := invocationDuration(invocation); ok {
+		metricWorkflowInvocationDuration.WithLabelValues(label).Observe(duration.Seconds())
+	}
+}
+
+// ObserveTask records the duration of a task run of the invocation. As the number of tasks is unbounded across
+// workflows, the task durations are only recorded for the workflows that have their own series.
+func (m *WorkflowMetrics) ObserveTask(invocation *types.WorkflowInvocation, taskID string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	wfID := invocation.GetSpec().GetWorkflowId()
+	m.mu.Lock()
+	label := m.label(wfID)
+	if label != OtherWorkflowsLabel {
+		if _, ok := m.tasks[wfID]; !ok {
+			m.tasks[wfID] = map[string]struct{}{}
+		}
+		m.tasks[wfID][taskID] = struct{}{}
+	}
+	m.mu.Unlock()
+	if label == OtherWorkflowsLabel {
+		return
+	}
+	metricWorkflowTaskDuration.WithLabelValues(label, taskID).Observe(duration.Seconds())
+}
+
+// ObserveRetry counts a retry of a failed task of the invocation. Like the task durations, the retries are only
+// counted per task for the workflows that have their own series; the retries of the other workflows are rolled up.
+func (m *WorkflowMetrics) ObserveRetry(invocation *types.WorkflowInvocation, taskID string) {
+	label := OtherWorkflowsLabel
+	if m != nil {
+		wfID := invocation.GetSpec().GetWorkflowId()
+		m.mu.Lock()
+		label = m.label(wfID)
+		if label != OtherWorkflowsLabel {
+			if _, ok := m.tasks[wfID]; !ok {
+				m.tasks[wfID] = map[string]struct{}{}
+			}
+			m.tasks[wfID][taskID] = struct{}{}
+		}
+		m.mu.Unlock()
+	}
+	if label == OtherWorkflowsLabel {
+		taskID = OtherWorkflowsLabel
+	}
+	metricWorkflowTaskRetries.WithLabelValues(label, taskID).Inc()
+}
+
+// invocationStatusLabel returns the status label of the finished invocation. Invocations that failed because they
+// exceeded their deadline are labeled TIMED_OUT, to tell them apart from other failures.
+func invocationStatusLabel(invocation *types.WorkflowInvocation) string {
+	status := invocation.GetStatus()
+	if status.GetStatus() == types.WorkflowInvocationStatus_FAILED && status.GetError().GetCode() == types.ErrorCodeTimeout {
+		return timedOutStatusLabel
+	}
+	return status.GetStatus().String()
+}
+
+// invocationDuration returns the duration of the finished invocation, from its creation until its completion.
+func invocationDuration(invocation *types.WorkflowInvocation) (time.Duration, bool) {
+	createdAt, err := ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt())
+	if err != nil {
+		return 0, false
+	}
+	finishedAt, err := ptypes.Timestamp(invocation.GetStatus().GetUpdatedAt())
+	if err != nil {
+		finishedAt = time.Now()
+	}
+	return finishedAt.Sub(createdAt), true
+}
+
+// label returns the label of the workflow, promoting it to its own series if it has exceeded the threshold. The
+// caller should hold the lock.
+func (m *WorkflowMetrics) label(workflowID string) string {
+	if _, ok := m.labeled[workflowID]; ok {
+		return workflowID
+	}
+	if !m.exceedsThreshold(workflowID) || (m.config.Limit > 0 && m.promoted >= m.config.Limit) {
+		return OtherWorkflowsLabel
+	}
+	m.labeled[workflowID] = struct{}{}
+	m.promoted++
+	return workflowID
+}
+
+func (m *WorkflowMetrics) exceedsThreshold(workflowID string) bool {
+	return m.config.Threshold > 0 && m.finished[workflowID] >= m.config.Threshold
+}
+
+func deleteWorkflowSeries(workflowID string, tasks map[string]struct{}) {
+	for _, status := range types.WorkflowInvocationStatus_Status_name {
+		metricWorkflowInvocationsFinished.DeleteLabelValues(workflowID, status)
+	}
+	metricWorkflowInvocationsFinished.DeleteLabelValues(workflowID, timedOutStatusLabel)
+	metricWorkflowInvocationDuration.DeleteLabelValues(workflowID)
+	for taskID := range tasks {
+		metricWorkflowTaskDuration.DeleteLabelValues(workflowID, taskID)
+		metricWorkflowTaskRetries.DeleteLabelValues(workflowID, taskID)
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func finishedInvocation(workflowID string) *types.WorkflowInvocation {
+	invocation := types.NewWorkflowInvocation(workflowID, "wi", time.Now().Add(time.Minute))
+	invocation.Status.Status = types.WorkflowInvocationStatus_SUCCEEDED
+	return invocation
+}
+
+func TestWorkflowMetrics_OptIn(t *testing.T) {
+	metrics := NewWorkflowMetrics(WorkflowMetricsConfig{Workflows: []string{"checkout"}})
+	assert.Equal(t, "checkout", metrics.Label("checkout"))
+	assert.Equal(t, OtherWorkflowsLabel, metrics.Label("shipping"))
+
+	var disabled *WorkflowMetrics
+	assert.Equal(t, OtherWorkflowsLabel, disabled.Label("checkout"))
+	disabled.ObserveFinished(finishedInvocation("checkout"))
+}
+
+func TestWorkflowMetrics_Threshold(t *testing.T) {
+	metrics := NewWorkflowMetrics(WorkflowMetricsConfig{Threshold: 2, Limit: 1})
+	metrics.ObserveFinished(finishedInvocation("checkout"))
+	assert.Equal(t, OtherWorkflowsLabel, metrics.Label("checkout"))
+	metrics.ObserveFinished(finishedInvocation("checkout"))
+	assert.Equal(t, "checkout", metrics.Label("checkout"))
+
+	// The limit bounds the number of workflows that get their own series by exceeding the threshold.
+	metrics.ObserveFinished(finishedInvocation("shipping"))
+	metrics.ObserveFinished(finishedInvocation("shipping"))
+	assert.Equal(t, OtherWorkflowsLabel, metrics.Label("shipping"))
+
+	// Raising the threshold at runtime demotes the workflows that no longer exceed it, freeing up the limit.
+	metrics.Configure(WorkflowMetricsConfig{Threshold: 3, Limit: 1})
+	assert.Equal(t, OtherWorkflowsLabel, metrics.Label("checkout"))
+	metrics.ObserveFinished(finishedInvocation("shipping"))
+	assert.Equal(t, "shipping", metrics.Label("shipping"))
+	assert.Equal(t, 3, metrics.Config().Threshold)
+}
+
+func TestInvocationStatusLabel(t *testing.T) {
+	invocation := finishedInvocation("checkout")
+	assert.Equal(t, "SUCCEEDED", invocationStatusLabel(invocation))
+
+	invocation.Status.Status = types.WorkflowInvocationStatus_FAILED
+	invocation.Status.Error = &types.Error{Message: "boom"}
+	assert.Equal(t, "FAILED", invocationStatusLabel(invocation))
+
+	invocation.Status.Error = &types.Error{Code: types.ErrorCodeTimeout, Message: "deadline exceeded"}
+	assert.Equal(t, timedOutStatusLabel, invocationStatusLabel(invocation))
+}
@@ -167,6 +167,23 @@ func (b *Backend) Get(key fes.Aggregate) ([]*fes.Event, error) {
 	return events, nil
 }
 
+// Delete removes the event stream of the aggregate, along with the events of its child aggregates.
+func (b *Backend) Delete(key fes.Aggregate) error {
+	if err := fes.ValidateAggregate(&key); err != nil {
+		return err
+	}
+	b.storeLock.Lock()
+	defer b.storeLock.Unlock()
+	if events, ok := b.store[key]; ok {
+		delete(b.store, key)
+		b.evict(key, events)
+		return nil
+	}
+	// Removing the entry from the buffer calls evict.
+	b.buf.Remove(key)
+	return nil
+}
+
 func (b *Backend) Len() int {
 	return int(atomic.LoadInt32(b.entries))
 }
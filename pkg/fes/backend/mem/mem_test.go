@@ -202,6 +202,31 @@ func TestBackend_GetNonexistent(t *testing.T) {
 	assert.EqualValues(t, []*fes.Event{}, getEvents)
 }
 
+func TestBackend_Delete(t *testing.T) {
+	mem := setupBackend()
+	active := fes.Aggregate{Type: "type", Id: "active"}
+	completed := fes.Aggregate{Type: "type", Id: "completed"}
+	assert.NoError(t, mem.Append(newEvent(active, []byte("event 1"))))
+	event := newEvent(completed, []byte("event 1"))
+	event.Hints = &fes.EventHints{Completed: true}
+	assert.NoError(t, mem.Append(event))
+	assert.Equal(t, 2, mem.Len())
+
+	assert.NoError(t, mem.Delete(active))
+	assert.NoError(t, mem.Delete(completed))
+	assert.Equal(t, 0, mem.Len())
+	events, err := mem.Get(active)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	events, err = mem.Get(completed)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	// Deleting an unknown aggregate is a no-op.
+	assert.NoError(t, mem.Delete(fes.Aggregate{Type: "type", Id: "unknown"}))
+	assert.Equal(t, 0, mem.Len())
+}
+
 func TestBackend_Subscribe(t *testing.T) {
 	mem := setupBackend()
 	key := fes.Aggregate{Type: "type", Id: "id"}
@@ -130,6 +130,8 @@ func (m *Event) GetMetadata() map[string]string {
 // EventHints is a collection of optional metadata that help components in the event store to improve performance.
 type EventHints struct {
 	Completed bool `protobuf:"varint,1,opt,name=completed" json:"completed,omitempty"`
+	// Reopened indicates that the event reopens an aggregate that was previously completed.
+	Reopened bool `protobuf:"varint,2,opt,name=reopened" json:"reopened,omitempty"`
 }
 
 func (m *EventHints) Reset()                    { *m = EventHints{} }
@@ -144,6 +146,13 @@ func (m *EventHints) GetCompleted() bool {
 	return false
 }
 
+func (m *EventHints) GetReopened() bool {
+	if m != nil {
+		return m.Reopened
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Aggregate)(nil), "fission.workflows.eventstore.Aggregate")
 	proto.RegisterType((*Event)(nil), "fission.workflows.eventstore.Event")
@@ -153,28 +162,28 @@ func init() {
 func init() { proto.RegisterFile("pkg/fes/fes.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 353 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0x41, 0x4b, 0xfb, 0x30,
-	0x18, 0xc6, 0x69, 0xbb, 0xee, 0xbf, 0xbe, 0xe3, 0x2f, 0x1a, 0x76, 0xa8, 0x65, 0xe0, 0xd8, 0xc5,
-	0xe2, 0x21, 0xc5, 0x79, 0x19, 0x0a, 0xca, 0x84, 0x81, 0x97, 0x5d, 0x8a, 0x27, 0x6f, 0x99, 0x7b,
-	0x5b, 0xcb, 0xda, 0xa4, 0x34, 0xd9, 0x46, 0x3f, 0xaf, 0x5f, 0x44, 0x9a, 0xae, 0x2b, 0x2a, 0x8c,
-	0x79, 0x28, 0xa4, 0xc9, 0xf3, 0x7b, 0xf2, 0x3c, 0x79, 0xe1, 0x22, 0x5f, 0xc7, 0x41, 0x84, 0xb2,
-	0xfa, 0x68, 0x5e, 0x08, 0x25, 0xc8, 0x30, 0x4a, 0xa4, 0x4c, 0x04, 0xa7, 0x3b, 0x51, 0xac, 0xa3,
-	0x54, 0xec, 0x24, 0xc5, 0x2d, 0x72, 0x25, 0x95, 0x28, 0xd0, 0xbb, 0x8a, 0x85, 0x88, 0x53, 0x0c,
-	0xb4, 0x76, 0xb9, 0x89, 0x02, 0x95, 0x64, 0x28, 0x15, 0xcb, 0xf2, 0x1a, 0xf7, 0x2e, 0x7f, 0x0a,
-	0x18, 0x2f, 0xeb, 0xa3, 0x71, 0x00, 0xce, 0x2c, 0x8e, 0x0b, 0x8c, 0x99, 0x42, 0x72, 0x06, 0x66,
-	0xb2, 0x72, 0x8d, 0x91, 0xe1, 0x3b, 0xa1, 0x99, 0xac, 0x08, 0x81, 0x8e, 0x2a, 0x73, 0x74, 0x4d,
-	0xbd, 0xa3, 0xd7, 0xe3, 0x4f, 0x0b, 0xec, 0x79, 0x75, 0xf7, 0x29, 0x6a, 0x32, 0x07, 0x87, 0x35,
-	0xf6, 0xae, 0x35, 0x32, 0xfc, 0xfe, 0xe4, 0x9a, 0x1e, 0x2b, 0x43, 0x0f, 0x69, 0xc2, 0x96, 0x24,
-	0x53, 0x70, 0x0e, 0x9d, 0xdc, 0x8e, 0xb6, 0xf1, 0x68, 0x5d, 0x8a, 0x36, 0xa5, 0xe8, 0x6b, 0xa3,
-	0x08, 0x5b, 0x31, 0xf1, 0xa1, 0xb3, 0x62, 0x8a, 0xb9, 0xb6, 0x86, 0x06, 0xbf, 0xa0, 0x19, 0x2f,
-	0x43, 0xad, 0x20, 0x4f, 0xd0, 0xcd, 0x59, 0x81, 0x5c, 0xb9, 0xdd, 0xbf, 0xe5, 0xdc, 0x63, 0xe4,
-	0x11, 0xec, 0x8f, 0x84, 0x2b, 0xe9, 0xfe, 0xd3, 0xbc, 0x7f, 0x9c, 0xd7, 0x6f, 0xf8, 0x52, 0xe9,
-	0xc3, 0x1a, 0x23, 0x0b, 0xe8, 0x65, 0xa8, 0x98, 0x8e, 0xdb, 0x1b, 0x59, 0x7e, 0x7f, 0x72, 0x7b,
-	0x82, 0x05, 0x5d, 0xec, 0x99, 0x39, 0x57, 0x45, 0x19, 0x1e, 0x2c, 0xbc, 0x07, 0xf8, 0xff, 0xed,
-	0x88, 0x9c, 0x83, 0xb5, 0xc6, 0x72, 0x3f, 0xb0, 0x6a, 0x49, 0x06, 0x60, 0x6f, 0x59, 0xba, 0x69,
-	0x46, 0x56, 0xff, 0xdc, 0x9b, 0x53, 0x63, 0x7c, 0x03, 0xd0, 0x06, 0x24, 0x43, 0x70, 0xde, 0x45,
-	0x96, 0xa7, 0xa8, 0xb0, 0x1e, 0x78, 0x2f, 0x6c, 0x37, 0x9e, 0xed, 0x37, 0x2b, 0x42, 0xb9, 0xec,
-	0xea, 0x37, 0xbd, 0xfb, 0x0a, 0x00, 0x00, 0xff, 0xff, 0xf1, 0xee, 0x2c, 0xf3, 0xbf, 0x02, 0x00,
-	0x00,
+	// 356 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x95, 0x52, 0xcf, 0x4b, 0xc3, 0x30,
+	0x14, 0x66, 0xed, 0x3a, 0xdb, 0x27, 0x8a, 0x86, 0x1d, 0x62, 0x19, 0x38, 0x76, 0x71, 0xa7, 0x14,
+	0xf5, 0x32, 0x26, 0x28, 0x13, 0x26, 0x5e, 0x76, 0x29, 0x9e, 0xbc, 0x65, 0xee, 0xb5, 0x96, 0xb5,
+	0x4d, 0x69, 0xb3, 0x8d, 0xfe, 0xbd, 0xfe, 0x23, 0xb6, 0xe9, 0xda, 0xa2, 0xc2, 0x98, 0x87, 0xc0,
+	0x4b, 0xbe, 0x1f, 0xf9, 0x5e, 0x5e, 0xe0, 0x32, 0x59, 0xfb, 0x8e, 0x87, 0x59, 0xb9, 0x58, 0x92,
+	0x0a, 0x29, 0xc8, 0xc0, 0x0b, 0xb2, 0x2c, 0x10, 0x31, 0xdb, 0x89, 0x74, 0xed, 0x85, 0x62, 0x97,
+	0x31, 0xdc, 0x62, 0x2c, 0x33, 0x29, 0x52, 0xb4, 0xaf, 0x7d, 0x21, 0xfc, 0x10, 0x1d, 0xc5, 0x5d,
+	0x6e, 0x3c, 0x47, 0x06, 0x11, 0x66, 0x92, 0x47, 0x49, 0x25, 0xb7, 0xaf, 0x7e, 0x13, 0x78, 0x9c,
+	0x57, 0xd0, 0xc8, 0x01, 0x6b, 0xe6, 0xfb, 0x29, 0xfa, 0x5c, 0x22, 0x39, 0x07, 0x2d, 0x58, 0xd1,
+	0xce, 0xb0, 0x33, 0xb6, 0xdc, 0xa2, 0x22, 0x04, 0xba, 0x32, 0x4f, 0x90, 0x6a, 0xea, 0x44, 0xd5,
+	0xa3, 0x2f, 0x1d, 0x8c, 0x79, 0x79, 0xf7, 0x31, 0x6c, 0x32, 0x07, 0x8b, 0xd7, 0xf6, 0x54, 0x2f,
+	0x80, 0xd3, 0xbb, 0x1b, 0x76, 0xa8, 0x19, 0xd6, 0xa4, 0x71, 0x5b, 0x25, 0x99, 0x80, 0xd5, 0xf4,
+	0x44, 0xbb, 0xca, 0xc6, 0x66, 0x55, 0x53, 0xac, 0x6e, 0x8a, 0xbd, 0xd5, 0x0c, 0xb7, 0x25, 0x93,
+	0x31, 0x74, 0x57, 0x5c, 0x72, 0x6a, 0x28, 0x51, 0xff, 0x8f, 0x68, 0x16, 0xe7, 0xae, 0x62, 0x90,
+	0x27, 0xe8, 0x25, 0x3c, 0x2d, 0x72, 0xd0, 0xde, 0xff, 0x72, 0xee, 0x65, 0xe4, 0x11, 0x8c, 0xcf,
+	0xa0, 0x80, 0xe9, 0x89, 0xd2, 0x8f, 0x0f, 0xeb, 0xd5, 0x1b, 0xbe, 0x96, 0x7c, 0xb7, 0x92, 0x91,
+	0x05, 0x98, 0x11, 0x4a, 0xae, 0xe2, 0x9a, 0x43, 0xbd, 0xb0, 0xb8, 0x3d, 0xc2, 0x82, 0x2d, 0xf6,
+	0x9a, 0x79, 0x2c, 0xd3, 0xdc, 0x6d, 0x2c, 0xec, 0x07, 0x38, 0xfb, 0x01, 0x91, 0x0b, 0xd0, 0xd7,
+	0x98, 0xef, 0x07, 0x56, 0x96, 0xa4, 0x0f, 0xc6, 0x96, 0x87, 0x9b, 0x7a, 0x64, 0xd5, 0x66, 0xaa,
+	0x4d, 0x3a, 0xa3, 0x17, 0x80, 0x36, 0x20, 0x19, 0x80, 0xf5, 0x21, 0xa2, 0x24, 0x44, 0x89, 0xd5,
+	0xc0, 0x4d, 0xb7, 0x3d, 0x20, 0x36, 0x98, 0x29, 0x8a, 0x04, 0xe3, 0x02, 0xd4, 0x14, 0xd8, 0xec,
+	0x9f, 0x8d, 0x77, 0xbd, 0xf8, 0xc5, 0xcb, 0x9e, 0x7a, 0xef, 0xfb, 0x6f, 0x8e, 0x3d, 0x02, 0xc4,
+	0xdb, 0x02, 0x00, 0x00,
 }
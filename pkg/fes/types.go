@@ -35,6 +35,14 @@ type Backend interface {
 	List(matcher AggregateMatcher) ([]Aggregate, error)
 }
 
+// EventDeleter is implemented by backends that support deleting the event streams of aggregates, such as to purge
+// finished invocations. Deleting an aggregate that does not exist is not an error.
+type EventDeleter interface {
+	// Delete removes all events of the aggregate, including the events of its child aggregates that are stored along
+	// with it.
+	Delete(aggregate Aggregate) error
+}
+
 type CacheReader interface {
 	//Get(entity Entity) error
 	List() []Aggregate
@@ -0,0 +1,69 @@
+package fission
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// HeaderSession contains the session token of a task with an affinity. Tasks with the same session token should
+	// be routed to the same function instance. A router that supports session affinity echoes the header in the
+	// response; otherwise the request is routed as usual.
+	HeaderSession = "X-Fission-Session"
+
+	sessionCacheSize = 10000
+
+	affinityReused      = "reused"
+	affinityNew         = "new"
+	affinityUnsupported = "unsupported"
+)
+
+var metricAffinityRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "fnenv",
+	Name:      "affinity_requests_total",
+	Help: "Number of Fission function requests of tasks with an affinity, by whether the request reused the " +
+		"function instance of the session, started a new session, or was not routed by affinity.",
+}, []string{"outcome"})
+
+func init() {
+	prometheus.MustRegister(metricAffinityRequests)
+}
+
+// sessions keeps track of the session tokens that have been routed to a function instance before.
+type sessions struct {
+	seen *lru.Cache // map[string]bool
+}
+
+func newSessions() *sessions {
+	cache, err := lru.New(sessionCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &sessions{seen: cache}
+}
+
+// sessionToken returns the session token shared by the tasks of the invocation that have the same affinity.
+func sessionToken(spec *types.TaskInvocationSpec) (string, bool) {
+	affinity := spec.GetTask().GetSpec().GetAffinity()
+	if len(affinity) == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%s/%s", spec.GetInvocationId(), affinity), true
+}
+
+// observe records and returns the outcome of a request with the session token.
+func (s *sessions) observe(token string, resp *http.Response) string {
+	outcome := affinityNew
+	if resp.Header.Get(HeaderSession) != token {
+		outcome = affinityUnsupported
+	} else if ok, _ := s.seen.ContainsOrAdd(token, true); ok {
+		outcome = affinityReused
+	}
+	metricAffinityRequests.WithLabelValues(outcome).Inc()
+	return outcome
+}
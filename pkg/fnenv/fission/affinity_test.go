@@ -0,0 +1,31 @@
+package fission
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessions(t *testing.T) {
+	_, ok := sessionToken(&types.TaskInvocationSpec{
+		InvocationId: "wi-1",
+		Task:         &types.Task{Spec: &types.TaskSpec{}},
+	})
+	assert.False(t, ok)
+
+	token, ok := sessionToken(&types.TaskInvocationSpec{
+		InvocationId: "wi-1",
+		Task:         &types.Task{Spec: &types.TaskSpec{Affinity: "model"}},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "wi-1/model", token)
+
+	sessions := newSessions()
+	echoed := &http.Response{Header: http.Header{}}
+	echoed.Header.Set(HeaderSession, token)
+	assert.Equal(t, affinityUnsupported, sessions.observe(token, &http.Response{Header: http.Header{}}))
+	assert.Equal(t, affinityNew, sessions.observe(token, echoed))
+	assert.Equal(t, affinityReused, sessions.observe(token, echoed))
+}
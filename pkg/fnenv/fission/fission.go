@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/fission/fission"
 	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/inputref"
+	"github.com/fission/fission-workflows/pkg/fnenv/signing"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/httpconv"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/fission/fission-workflows/pkg/util/backoff"
@@ -36,11 +39,29 @@ var log = logrus.WithField("component", "fnenv.fission")
 // FunctionEnv adapts the Fission platform to the function execution runtime. This allows the workflow engine
 // to invoke Fission functions.
 type FunctionEnv struct {
-	executor    *executor.Client
-	executorURL string
-	controller  *controller.Client
-	routerURL   string
-	client      *http.Client
+	executor     *executor.Client
+	executorURL  string
+	controller   *controller.Client
+	routerURL    string
+	client       *http.Client
+	sessions     *sessions
+	functions    *functions
+	inputRefs    *inputref.Store
+	verifier     *signing.Verifier
+	malformed    MalformedOutputPolicy
+}
+
+// ErrExecutorTypeMismatch is returned when a task is pinned to an executor type on which the function is not
+// deployed.
+type ErrExecutorTypeMismatch struct {
+	Fn       types.FnRef
+	Expected string
+	Actual   string
+}
+
+func (e ErrExecutorTypeMismatch) Error() string {
+	return fmt.Sprintf("function %s is not deployed on executor type '%s' (actual: '%s')", e.Fn.Format(),
+		e.Expected, e.Actual)
 }
 
 const (
@@ -51,14 +72,35 @@ const (
 func New(executorURL, serverURL, routerURL string) *FunctionEnv {
 
 	return &FunctionEnv{
-		executor:    executor.MakeClient(executorURL),
-		controller:  controller.MakeClient(serverURL),
-		routerURL:   routerURL,
-		executorURL: executorURL,
-		client:      &http.Client{},
+		executor:     executor.MakeClient(executorURL),
+		controller:   controller.MakeClient(serverURL),
+		routerURL:    routerURL,
+		executorURL:  executorURL,
+		client:       &http.Client{},
+		sessions:     newSessions(),
+		functions:    newFunctions(),
+		malformed:    DefaultMalformedOutputPolicy,
 	}
 }
 
+// SetInputReferences sets the store used to pass large bodies by reference to the functions that support it. If nil,
+// the bodies are always inlined in the function requests.
+func (fe *FunctionEnv) SetInputReferences(store *inputref.Store) {
+	fe.inputRefs = store
+}
+
+// SetVerifier sets the verifier of the signatures of the function responses. If nil, the responses of the functions
+// are not verified.
+func (fe *FunctionEnv) SetVerifier(verifier *signing.Verifier) {
+	fe.verifier = verifier
+}
+
+// SetMalformedOutputPolicy sets how the responses of functions of which the body cannot be deserialized according to
+// their content type are handled.
+func (fe *FunctionEnv) SetMalformedOutputPolicy(policy MalformedOutputPolicy) {
+	fe.malformed = policy
+}
+
 // Invoke executes the task in a blocking way.
 //
 // spec contains the complete configuration needed for the execution.
@@ -76,18 +118,64 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 	span.SetTag("fnref", fnRef.Format())
 
 	// Construct request and add body
+	// By default, the request is sent to the Fission router. If the task is pinned to an executor type, the function
+	// must be deployed on that executor type; the request is then sent directly to the function service provided by
+	// the executor.
 	fnUrl := fe.createRouterURL(fnRef)
+	if executorType := spec.GetExecutorType(); len(executorType) != 0 {
+		if err := fe.validateExecutorType(fnRef, executorType); err != nil {
+			if err, ok := err.(ErrExecutorTypeMismatch); ok {
+				return &types.TaskInvocationStatus{
+					Status: types.TaskInvocationStatus_FAILED,
+					Error:  &types.Error{Message: err.Error(), Code: types.ErrorCodeRuntime},
+				}, nil
+			}
+			return nil, err
+		}
+		serviceURL, err := fe.getFnURL(fnRef)
+		if err != nil {
+			return nil, err
+		}
+		fnUrl = serviceURL.String()
+		span.SetTag("executorType", executorType)
+	}
 	span.SetTag("fnUrl", fnUrl)
 	req, err := http.NewRequest(defaultHTTPMethod, fnUrl, nil)
 	if err != nil {
 		panic(fmt.Errorf("failed to create request for '%v': %v", fnUrl, err))
 	}
-	// Map task inputs to request
-	err = httpconv.FormatRequest(spec.Inputs, req)
+	// Map task inputs to request, encoding the body with the content type declared by the task (if any).
+	contentType := spec.GetTask().GetSpec().GetContentType()
+	err = httpconv.FormatRequest(httpconv.WithContentType(spec.Inputs, contentType), req)
 	if err != nil {
 		return nil, err
 	}
 
+	// Pass a large body by reference if the function supports it, which avoids copying it through the request.
+	if fe.inputRefs != nil && spec.GetTask().GetSpec().GetInputReferences() {
+		referenced, err := fe.inputRefs.Externalize(req)
+		if err != nil {
+			return nil, err
+		}
+		if referenced {
+			span.SetTag("inputRef", req.Header.Get(inputref.HeaderReference))
+		}
+	}
+
+	// Route the tasks with the same affinity to the same function instance, if supported by the router.
+	session, hasAffinity := sessionToken(spec)
+	if hasAffinity {
+		req.Header.Set(HeaderSession, session)
+		span.SetTag("session", session)
+	}
+
+	// Request a signed response from the functions that have opted in to signing.
+	var nonce string
+	signed := fe.verifier.Required(fnRef)
+	if signed {
+		nonce = fe.verifier.Sign(req)
+	}
+
 	// Add tracing
 	if span := opentracing.SpanFromContext(cfg.Ctx); span != nil {
 		err := opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders,
@@ -141,6 +229,10 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 		return nil, fmt.Errorf("error executing fission function at %s after %d attempts: %v", fnUrl, maxAttempts, err)
 	}
 	span.LogKV("status code", resp.Status)
+	if hasAffinity {
+		outcome := fe.sessions.observe(session, resp)
+		ctxLog.Debugf("Function request of session %s: %s", session, outcome)
+	}
 
 	fnenv.FnActive.WithLabelValues(Name).Dec()
 
@@ -156,10 +248,41 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 		span.LogKV("HTTP response", string(bs))
 	}
 
-	// Parse output
+	// Verify that the response was produced by the intended function before ingesting it.
+	if signed {
+		if err := fe.verifier.Verify(nonce, resp); err != nil {
+			ctxLog.Warnf("[%s] Rejected function response: %v", fnRef.ID, err)
+			span.LogKV("error", err)
+			return &types.TaskInvocationStatus{
+				Status: types.TaskInvocationStatus_FAILED,
+				Error: &types.Error{
+					Message: fmt.Sprintf("failed to verify function response: %v", err),
+					Code:    types.ErrorCodeRuntime,
+				},
+			}, nil
+		}
+	}
+
+	// Parse output, assuming the declared content type if the function did not specify one.
+	if len(contentType) > 0 && len(resp.Header.Get("Content-Type")) == 0 {
+		resp.Header.Set("Content-Type", contentType)
+	}
+	// The body is buffered, so that it is still available if it turns out to be malformed.
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output: %v", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
 	output, err := httpconv.ParseResponse(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse output: %v", err)
+		ctxLog.Warnf("[%s] Malformed function response (policy: %s): %v", fnRef.ID, fe.malformed, err)
+		span.LogKV("error", err)
+		var failed *types.TaskInvocationStatus
+		output, failed = fe.malformed.handleMalformedOutput(body, resp.Header.Get("Content-Type"), err)
+		if failed != nil {
+			return failed, nil
+		}
 	}
 
 	// Parse response headers
@@ -173,6 +296,8 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 			Status: types.TaskInvocationStatus_FAILED,
 			Error: &types.Error{
 				Message: fmt.Sprintf("fission function error: %v", msg),
+				Code:    types.ErrorCodeFunction,
+				Raw:     fmt.Sprintf("%v", msg),
 			},
 		}, nil
 	}
@@ -237,10 +362,13 @@ func (fe *FunctionEnv) getFnURL(fn types.FnRef) (*url.URL, error) {
 }
 
 func createFunctionMeta(fn types.FnRef) *metav1.ObjectMeta {
-
+	ns := fn.Namespace
+	if len(ns) == 0 {
+		ns = metav1.NamespaceDefault
+	}
 	return &metav1.ObjectMeta{
 		Name:      fn.ID,
-		Namespace: metav1.NamespaceDefault,
+		Namespace: ns,
 	}
 }
 
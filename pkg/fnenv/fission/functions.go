@@ -0,0 +1,87 @@
+package fission
+
+import (
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	functionCacheSize = 10000
+	functionCacheTTL  = time.Minute
+)
+
+// cachedFunction contains the properties of the spec of a Fission function that are looked up for task executions.
+type cachedFunction struct {
+	env          string
+	executorType string
+	resolvedAt   time.Time
+}
+
+// functions caches the specs of the functions, as these are looked up for every task execution when the concurrency
+// of environments is limited, or when the task is pinned to an executor type.
+type functions struct {
+	cache *lru.Cache // map[string]cachedFunction
+}
+
+func newFunctions() *functions {
+	cache, err := lru.New(functionCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &functions{cache: cache}
+}
+
+// lookupFunction returns the cached spec of the function, or fetches it from the controller if it is not cached or
+// has been cached for longer than a minute. Changes to the spec of a function therefore take effect with a delay.
+func (fe *FunctionEnv) lookupFunction(fn types.FnRef) (cachedFunction, error) {
+	key := fn.Format()
+	if cached, ok := fe.functions.cache.Get(key); ok {
+		entry := cached.(cachedFunction)
+		if time.Since(entry.resolvedAt) < functionCacheTTL {
+			return entry, nil
+		}
+	}
+	fnSpec, err := fe.controller.FunctionGet(createFunctionMeta(fn))
+	if err != nil {
+		return cachedFunction{}, err
+	}
+	entry := cachedFunction{
+		env:          fnSpec.Spec.Environment.Name,
+		executorType: string(fnSpec.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType),
+		resolvedAt:   time.Now(),
+	}
+	fe.functions.cache.Add(key, entry)
+	return entry, nil
+}
+
+// Environment returns the name of the Fission environment of the function. Functions of other runtimes do not have
+// an environment.
+func (fe *FunctionEnv) Environment(fn types.FnRef) (string, error) {
+	if len(fn.Runtime) > 0 && fn.Runtime != Name {
+		return "", nil
+	}
+	entry, err := fe.lookupFunction(fn)
+	if err != nil {
+		return "", err
+	}
+	return entry.env, nil
+}
+
+// validateExecutorType verifies that the function is deployed on the executor type, returning an
+// ErrExecutorTypeMismatch if it is not.
+func (fe *FunctionEnv) validateExecutorType(fn types.FnRef, executorType string) error {
+	entry, err := fe.lookupFunction(fn)
+	if err != nil {
+		return err
+	}
+	if entry.executorType != executorType {
+		return ErrExecutorTypeMismatch{
+			Fn:       fn,
+			Expected: executorType,
+			Actual:   entry.executorType,
+		}
+	}
+	return nil
+}
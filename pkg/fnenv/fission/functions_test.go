@@ -0,0 +1,33 @@
+package fission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateExecutorType(t *testing.T) {
+	var requests int32
+	controller := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"metadata": {"name": "hello", "namespace": "default"}, "spec": {"environment": ` +
+			`{"name": "go"}, "InvokeStrategy": {"ExecutionStrategy": {"ExecutorType": "newdeploy"}}}}`))
+	}))
+	defer controller.Close()
+	fe := New("http://executor", controller.URL, "http://router")
+	fn := types.FnRef{Runtime: Name, ID: "hello"}
+
+	assert.NoError(t, fe.validateExecutorType(fn, "newdeploy"))
+	err := fe.validateExecutorType(fn, "poolmgr")
+	assert.Equal(t, ErrExecutorTypeMismatch{Fn: fn, Expected: "poolmgr", Actual: "newdeploy"}, err)
+
+	// The spec of the function is cached, and shared with the lookups of its environment.
+	env, err := fe.Environment(fn)
+	assert.NoError(t, err)
+	assert.Equal(t, "go", env)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,179 @@
+package fission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	DefaultHealthProbeInterval    = 5 * time.Second
+	DefaultHealthFailureThreshold = 3
+
+	routerHealthPath   = "/router-healthz"
+	executorHealthPath = "/healthz"
+)
+
+var (
+	metricHealthy = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv",
+		Name:      "fission_healthy",
+		Help:      "Whether the Fission router and executor are reachable (1) or not (0), according to the health probe",
+	})
+	metricHealthProbes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv",
+		Name:      "fission_health_probes_total",
+		Help:      "Number of probes of the Fission endpoints, by endpoint (router or executor) and result (ok or failed)",
+	}, []string{"endpoint", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(metricHealthy, metricHealthProbes)
+}
+
+// HealthConfig configures the health probe of the Fission endpoints.
+type HealthConfig struct {
+	// Interval is the interval at which the endpoints are probed. If 0, DefaultHealthProbeInterval is used.
+	Interval time.Duration
+
+	// FailureThreshold is the number of consecutive failed probes after which Fission is considered unhealthy. If 0,
+	// DefaultHealthFailureThreshold is used.
+	FailureThreshold int
+}
+
+// HealthProbe probes the health endpoints of the Fission router and executor, to detect outages of Fission.
+//
+// Fission is considered unhealthy once FailureThreshold consecutive probes have failed, and healthy again after the
+// first probe that succeeds. A single failed probe, such as a timeout during a brief network hiccup, does not affect
+// the health. Until the first probe, Fission is assumed to be healthy.
+type HealthProbe struct {
+	routerURL   string
+	executorURL string
+	client      *http.Client
+	config      HealthConfig
+	failures    int
+	healthy     bool
+	onRecover   []func()
+	mu          sync.RWMutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func NewHealthProbe(routerURL, executorURL string, config HealthConfig) *HealthProbe {
+	if config.Interval <= 0 {
+		config.Interval = DefaultHealthProbeInterval
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultHealthFailureThreshold
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	metricHealthy.Set(1)
+	return &HealthProbe{
+		routerURL:   strings.TrimSuffix(routerURL, "/"),
+		executorURL: strings.TrimSuffix(executorURL, "/"),
+		client:      &http.Client{Timeout: config.Interval},
+		config:      config,
+		healthy:     true,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Healthy returns whether Fission is considered healthy.
+func (p *HealthProbe) Healthy() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.healthy
+}
+
+// OnRecover registers a function that is called each time Fission becomes healthy again after an outage.
+func (p *HealthProbe) OnRecover(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onRecover = append(p.onRecover, fn)
+}
+
+// Probe probes the endpoints once, and returns whether Fission is considered healthy afterwards.
+func (p *HealthProbe) Probe() bool {
+	err := p.probe("router", p.routerURL+routerHealthPath)
+	if err == nil {
+		err = p.probe("executor", p.executorURL+executorHealthPath)
+	}
+
+	p.mu.Lock()
+	wasHealthy := p.healthy
+	if err != nil {
+		p.failures++
+		if p.failures >= p.config.FailureThreshold {
+			p.healthy = false
+		}
+	} else {
+		p.failures = 0
+		p.healthy = true
+	}
+	healthy := p.healthy
+	onRecover := p.onRecover
+	p.mu.Unlock()
+
+	switch {
+	case wasHealthy && !healthy:
+		metricHealthy.Set(0)
+		log.Warnf("Fission is unhealthy after %d failed probes; pausing the submission of tasks: %v",
+			p.config.FailureThreshold, err)
+	case !wasHealthy && healthy:
+		metricHealthy.Set(1)
+		log.Infof("Fission is healthy again; resuming the submission of tasks")
+		for _, fn := range onRecover {
+			fn()
+		}
+	case err != nil:
+		log.Debugf("Failed to probe Fission: %v", err)
+	}
+	return healthy
+}
+
+// Run probes the endpoints at the configured interval, until the probe is closed.
+func (p *HealthProbe) Run() error {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.Probe()
+		}
+	}
+}
+
+func (p *HealthProbe) Close() error {
+	p.cancel()
+	return nil
+}
+
+func (p *HealthProbe) probe(endpoint string, url string) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req.WithContext(p.ctx))
+	if err == nil {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("%s responded with status %d", url, resp.StatusCode)
+		}
+	}
+	if err != nil {
+		metricHealthProbes.WithLabelValues(endpoint, "failed").Inc()
+		return err
+	}
+	metricHealthProbes.WithLabelValues(endpoint, "ok").Inc()
+	return nil
+}
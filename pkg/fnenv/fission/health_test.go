@@ -0,0 +1,51 @@
+package fission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthProbe(t *testing.T) {
+	var down int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case routerHealthPath, executorHealthPath:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	probe := NewHealthProbe(server.URL, server.URL+"/", HealthConfig{FailureThreshold: 2})
+	defer probe.Close()
+	var recoveries int32
+	probe.OnRecover(func() {
+		atomic.AddInt32(&recoveries, 1)
+	})
+	assert.True(t, probe.Healthy())
+	assert.True(t, probe.Probe())
+
+	// A single failed probe does not make Fission unhealthy.
+	atomic.StoreInt32(&down, 1)
+	assert.True(t, probe.Probe())
+	assert.False(t, probe.Probe())
+	assert.False(t, probe.Healthy())
+	assert.EqualValues(t, 0, atomic.LoadInt32(&recoveries))
+
+	// The first successful probe recovers Fission.
+	atomic.StoreInt32(&down, 0)
+	assert.True(t, probe.Probe())
+	assert.True(t, probe.Healthy())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&recoveries))
+	assert.True(t, probe.Probe())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&recoveries))
+}
@@ -0,0 +1,77 @@
+package fission
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+// MalformedOutputPolicy determines how the runtime handles a function response of which the body cannot be
+// deserialized according to its content type, such as a response with broken JSON.
+type MalformedOutputPolicy string
+
+const (
+	// MalformedOutputFailTask fails the task. The failure is permanent; the task is not retried.
+	MalformedOutputFailTask MalformedOutputPolicy = "fail-task"
+
+	// MalformedOutputRawString stores the body of the response as a string output.
+	MalformedOutputRawString MalformedOutputPolicy = "raw-string"
+
+	// MalformedOutputRetry fails the task with a transient error, which is retried according to the retry policy of
+	// the task.
+	MalformedOutputRetry MalformedOutputPolicy = "retry"
+
+	// DefaultMalformedOutputPolicy is the policy used if none has been configured.
+	DefaultMalformedOutputPolicy = MalformedOutputFailTask
+
+	// maxRawBodySize is the maximum number of bytes of the body of a malformed response that is included in the
+	// error of the task.
+	maxRawBodySize = 4096
+)
+
+var malformedOutputPolicies = []MalformedOutputPolicy{MalformedOutputFailTask, MalformedOutputRawString,
+	MalformedOutputRetry}
+
+// ParseMalformedOutputPolicy parses the policy for handling malformed function responses. An empty string results in
+// the default policy.
+func ParseMalformedOutputPolicy(s string) (MalformedOutputPolicy, error) {
+	if len(s) == 0 {
+		return DefaultMalformedOutputPolicy, nil
+	}
+	for _, policy := range malformedOutputPolicies {
+		if string(policy) == s {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown malformed output policy '%s' (expected one of: %v)", s, malformedOutputPolicies)
+}
+
+// handleMalformedOutput applies the policy to the body of a function response that could not be deserialized. It
+// returns the output to use instead if the policy allows the task to continue, or otherwise the status of the failed
+// task. The (truncated) body is included in the error of the failed task for debugging.
+func (p MalformedOutputPolicy) handleMalformedOutput(body []byte, contentType string,
+	parseErr error) (*typedvalues.TypedValue, *types.TaskInvocationStatus) {
+	if p == MalformedOutputRawString {
+		return typedvalues.MustWrap(string(body)), nil
+	}
+
+	code := types.ErrorCodeMalformedOutput
+	if p == MalformedOutputRetry {
+		code = types.ErrorCodeRuntime
+	}
+	raw := string(body)
+	if len(raw) > maxRawBodySize {
+		raw = raw[:maxRawBodySize]
+	}
+	return nil, &types.TaskInvocationStatus{
+		Status: types.TaskInvocationStatus_FAILED,
+		Error: &types.Error{
+			Message: fmt.Sprintf("failed to deserialize function response of type '%s': %v",
+				strings.TrimSpace(contentType), parseErr),
+			Code: code,
+			Raw:  raw,
+		},
+	}
+}
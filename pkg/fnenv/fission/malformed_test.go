@@ -0,0 +1,46 @@
+package fission
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMalformedOutputPolicy(t *testing.T) {
+	policy, err := ParseMalformedOutputPolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, MalformedOutputFailTask, policy)
+
+	policy, err = ParseMalformedOutputPolicy("raw-string")
+	assert.NoError(t, err)
+	assert.Equal(t, MalformedOutputRawString, policy)
+
+	_, err = ParseMalformedOutputPolicy("ignore")
+	assert.Error(t, err)
+}
+
+func TestHandleMalformedOutput(t *testing.T) {
+	body := []byte(`{"broken":`)
+	parseErr := errors.New("unexpected EOF")
+
+	output, failed := MalformedOutputRawString.handleMalformedOutput(body, "application/json", parseErr)
+	assert.Nil(t, failed)
+	assert.Equal(t, `{"broken":`, typedvalues.MustUnwrap(output))
+
+	output, failed = MalformedOutputFailTask.handleMalformedOutput(body, "application/json", parseErr)
+	assert.Nil(t, output)
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, failed.GetStatus())
+	assert.Equal(t, types.ErrorCodeMalformedOutput, failed.GetError().GetCode())
+	assert.Equal(t, `{"broken":`, failed.GetError().GetRaw())
+	assert.Contains(t, failed.GetError().GetMessage(), "application/json")
+
+	// A transient failure is retried according to the retry policy of the task; the raw body is truncated.
+	large := []byte(strings.Repeat("x", maxRawBodySize+1))
+	_, failed = MalformedOutputRetry.handleMalformedOutput(large, "application/json", parseErr)
+	assert.Equal(t, types.ErrorCodeRuntime, failed.GetError().GetCode())
+	assert.Len(t, failed.GetError().GetRaw(), maxRawBodySize)
+}
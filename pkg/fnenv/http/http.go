@@ -130,6 +130,8 @@ func (r *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOpt
 			Status: types.TaskInvocationStatus_FAILED,
 			Error: &types.Error{
 				Message: fmt.Sprintf("HTTP runtime request error: %v", msg),
+				Code:    types.ErrorCodeFunction,
+				Raw:     fmt.Sprintf("%v", msg),
 			},
 		}, nil
 	}
@@ -0,0 +1,152 @@
+// Package inputref passes large function inputs by reference instead of inlining them in the function request.
+//
+// Functions that support the reference protocol receive a request without a body, with the HeaderReference header
+// containing the URL from which the body can be fetched on demand with a GET request. The Content-Type header of the
+// request is retained, describing the referenced body. The bodies are kept in memory for a limited number of
+// references, after which the least recently used bodies are evicted.
+package inputref
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// HeaderReference contains the URL of the referenced body of a function request.
+	HeaderReference = "X-Workflows-Input-Ref"
+
+	// PathPrefix is the path under which the Store serves the referenced bodies.
+	PathPrefix = "/inputs/"
+
+	DefaultThreshold  = 1 << 20 // 1 MiB
+	DefaultMaxEntries = 1000
+)
+
+var metricReferences = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "fnenv",
+	Name:      "input_references_total",
+	Help:      "Number of function inputs passed by reference, by whether the reference was created or fetched",
+}, []string{"action"})
+
+func init() {
+	prometheus.MustRegister(metricReferences)
+}
+
+// Config contains the configuration of the store of referenced inputs.
+type Config struct {
+	// URL is the externally reachable base URL of the workflow engine, which functions use to fetch the bodies.
+	URL string
+
+	// Threshold is the size in bytes above which bodies are passed by reference. Defaults to DefaultThreshold.
+	Threshold int64
+
+	// MaxEntries is the number of bodies that are retained. Defaults to DefaultMaxEntries.
+	MaxEntries int
+}
+
+type blob struct {
+	data        []byte
+	contentType string
+}
+
+// Store keeps the referenced bodies and serves them to the functions.
+type Store struct {
+	config Config
+	blobs  *lru.Cache
+}
+
+func NewStore(config Config) (*Store, error) {
+	if len(config.URL) == 0 {
+		return nil, fmt.Errorf("no URL provided for input references")
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultMaxEntries
+	}
+	blobs, err := lru.New(config.MaxEntries)
+	if err != nil {
+		return nil, err
+	}
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	return &Store{
+		config: config,
+		blobs:  blobs,
+	}, nil
+}
+
+// Externalize replaces the body of the request with a reference if the body exceeds the threshold. It returns
+// whether the body was replaced.
+func (s *Store) Externalize(req *http.Request) (bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return false, nil
+	}
+	size := req.ContentLength
+	if size <= 0 {
+		size = contentLength(req)
+	}
+	if size >= 0 && size <= s.config.Threshold {
+		return false, nil
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return false, err
+	}
+	req.Body.Close()
+	if int64(len(data)) <= s.config.Threshold {
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+		return false, nil
+	}
+
+	id := util.UID()
+	s.blobs.Add(id, &blob{
+		data:        data,
+		contentType: req.Header.Get("Content-Type"),
+	})
+	req.Body = http.NoBody
+	req.ContentLength = 0
+	req.Header.Del("Content-Length")
+	req.Header.Set(HeaderReference, s.config.URL+PathPrefix+id)
+	metricReferences.WithLabelValues("created").Inc()
+	return true, nil
+}
+
+// ServeHTTP serves the referenced bodies by their ID.
+func (s *Store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	entry, ok := s.blobs.Get(path.Base(r.URL.Path))
+	if !ok {
+		http.Error(w, "input reference not found", http.StatusNotFound)
+		return
+	}
+	b := entry.(*blob)
+	if len(b.contentType) > 0 {
+		w.Header().Set("Content-Type", b.contentType)
+	}
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(b.data)))
+	w.Write(b.data)
+	metricReferences.WithLabelValues("fetched").Inc()
+}
+
+// contentLength returns the length of the body as indicated by the Content-Length header, or -1 if unknown.
+func contentLength(req *http.Request) int64 {
+	n, err := strconv.ParseInt(req.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+package inputref
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "http://fn/", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	return req
+}
+
+func TestStore_Externalize(t *testing.T) {
+	store, err := NewStore(Config{URL: "http://workflows/", Threshold: 4})
+	assert.NoError(t, err)
+
+	// Small bodies are inlined
+	req := newRequest("abc")
+	referenced, err := store.Externalize(req)
+	assert.NoError(t, err)
+	assert.False(t, referenced)
+	assert.Empty(t, req.Header.Get(HeaderReference))
+	body, _ := ioutil.ReadAll(req.Body)
+	assert.Equal(t, "abc", string(body))
+
+	// Large bodies are passed by reference
+	req = newRequest("abcdefgh")
+	referenced, err = store.Externalize(req)
+	assert.NoError(t, err)
+	assert.True(t, referenced)
+	ref := req.Header.Get(HeaderReference)
+	assert.True(t, strings.HasPrefix(ref, "http://workflows"+PathPrefix))
+	assert.EqualValues(t, 0, req.ContentLength)
+	body, _ = ioutil.ReadAll(req.Body)
+	assert.Empty(t, body)
+
+	// The referenced body can be fetched
+	w := httptest.NewRecorder()
+	store.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ref, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abcdefgh", w.Body.String())
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	store.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://workflows"+PathPrefix+"unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNewStore_MissingURL(t *testing.T) {
+	_, err := NewStore(Config{})
+	assert.Error(t, err)
+}
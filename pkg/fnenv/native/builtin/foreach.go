@@ -3,6 +3,8 @@ package builtin
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
@@ -15,6 +17,14 @@ const (
 	ForeachInputDo         = "do"
 	ForeachInputCollect    = "collect"
 	ForeachInputSequential = "sequential"
+	ForeachInputWidth      = "width"
+
+	// ForeachMetadataWidth is the metadata key of the output of a foreach that holds the effective width: the number
+	// of lanes in which the tasks of the foreach run.
+	ForeachMetadataWidth = "width"
+
+	// DefaultForeachMaxWidth is the default maximum width of a foreach, to which larger explicit widths are clamped.
+	DefaultForeachMaxWidth = 1000
 )
 
 /*
@@ -29,10 +39,20 @@ Note, currently the task in the 'do' does not have access to state in the curren
 foreach                  | yes      | list          | The list of elements that foreach should be looped over.
 do                       | yes      | task/workflow | The action to perform for every element.
 sequential               | no       | bool          | Whether to execute the tasks sequentially (default: false).
+width                    | no       | number        | The maximum number of tasks that run concurrently (default: unbounded).
 collect                  | no       | bool          | Collect the outputs of the tasks into an array (default: true).
 
 The element is made available to the action using the field `_item`.
 
+The width can be an expression, which is evaluated when the foreach runs; for example, to scale the width with a quota
+that is computed from the inputs. It should resolve to a positive integer, and is clamped to the maximum width. Without
+a width, all tasks run concurrently, regardless of the maximum width. The effective width is recorded in the metadata
+of the output of the foreach, under the key 'width'. A sequential foreach has a width of 1.
+
+The width is not enforced as a concurrency limit, but by dividing the tasks into lanes: each task requires the task
+that precedes it by the width. So, no more than width tasks run at once, but a slow task holds up the next task in its
+lane, even if the tasks of the other lanes have all completed.
+
 **output** None
 
 **Example**
@@ -52,7 +72,10 @@ foo:
 
 A complete example of this function can be found in the [foreachwhale](../examples/whales/foreachwhale.wf.yaml) example.
 */
-type FunctionForeach struct{}
+type FunctionForeach struct {
+	// MaxWidth is the maximum width of a foreach. If 0, DefaultForeachMaxWidth is used.
+	MaxWidth int
+}
 
 func (fn *FunctionForeach) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
 	// Verify and parse foreach
@@ -104,6 +127,18 @@ func (fn *FunctionForeach) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.
 		seq = b
 	}
 
+	// Wrap width
+	width, err := fn.width(spec.Inputs, len(foreach))
+	if err != nil {
+		return nil, err
+	}
+	if seq {
+		if _, ok := spec.Inputs[ForeachInputWidth]; ok && width > 1 {
+			return nil, fmt.Errorf("a sequential foreach cannot have a width of %d", width)
+		}
+		width = 1
+	}
+
 	// Create the workflows
 	wf := &types.WorkflowSpec{
 		OutputTask: "collector",
@@ -124,8 +159,9 @@ func (fn *FunctionForeach) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.
 		wf.AddTask(name, t)
 		tasks = append(tasks, name)
 
-		if seq && k != 0 {
-			t.Require(tasks[k-1])
+		// Bound the concurrency by making each task wait for the task that precedes it by the width.
+		if k >= width {
+			t.Require(tasks[k-width])
 		}
 	}
 
@@ -144,5 +180,53 @@ func (fn *FunctionForeach) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.
 	ct.Input(ComposeInput, typedvalues.MustWrap(output))
 	wf.AddTask("collector", ct)
 
-	return typedvalues.Wrap(wf)
+	wfTv, err := typedvalues.Wrap(wf)
+	if err != nil {
+		return nil, err
+	}
+	wfTv.SetMetadata(ForeachMetadataWidth, strconv.Itoa(width))
+	return wfTv, nil
+}
+
+// width returns the effective width of the foreach over n elements, which is n unless the width input bounds it. An
+// explicit width is clamped to the maximum width.
+func (fn *FunctionForeach) width(inputs map[string]*typedvalues.TypedValue, n int) (int, error) {
+	width := n
+	if widthTv, ok := inputs[ForeachInputWidth]; ok {
+		f, err := typedvalues.UnwrapFloat64(widthTv)
+		if err != nil {
+			return 0, fmt.Errorf("width could not be parsed into a number: %v", err)
+		}
+		if f < 1 || f != math.Trunc(f) {
+			return 0, fmt.Errorf("width should be a positive integer, but was %v", f)
+		}
+		maxWidth := fn.MaxWidth
+		if maxWidth <= 0 {
+			maxWidth = DefaultForeachMaxWidth
+		}
+		if f > float64(maxWidth) {
+			f = float64(maxWidth)
+		}
+		if f < float64(width) {
+			width = int(f)
+		}
+	}
+	if width < 1 {
+		// An empty foreach
+		width = 1
+	}
+	return width, nil
+}
+
+// ForeachWidth returns the effective width of a run of a foreach, based on its output. It returns false if the function
+// is not the foreach function, or if the output does not record a width.
+func ForeachWidth(fnRef *types.FnRef, output *typedvalues.TypedValue) (int, bool) {
+	if fnRef.GetRuntime() != Runtime || fnRef.GetID() != Foreach {
+		return 0, false
+	}
+	width, err := strconv.Atoi(output.GetMetadata()[ForeachMetadataWidth])
+	if err != nil {
+		return 0, false
+	}
+	return width, true
 }
@@ -28,3 +28,63 @@ func TestFunctionForeach_Invoke(t *testing.T) {
 	assert.NotNil(t, wf.Tasks["do_0"])
 	assert.Equal(t, foreachElements[0], int(typedvalues.MustUnwrap(wf.Tasks["do_0"].Inputs["_item"]).(int32)))
 }
+
+func TestFunctionForeach_InvokeWidth(t *testing.T) {
+	invoke := func(fn *FunctionForeach, width interface{}) (*types.WorkflowSpec, int, error) {
+		inputs := map[string]*typedvalues.TypedValue{
+			ForeachInputForeach: typedvalues.MustWrap([]interface{}{1, 2, 3, 4, 5}),
+			ForeachInputDo:      typedvalues.MustWrap(&types.TaskSpec{FunctionRef: Noop}),
+		}
+		if width != nil {
+			inputs[ForeachInputWidth] = typedvalues.MustWrap(width)
+		}
+		out, err := fn.Invoke(&types.TaskInvocationSpec{Inputs: inputs})
+		if err != nil {
+			return nil, 0, err
+		}
+		wf, err := controlflow.UnwrapWorkflow(out)
+		assert.NoError(t, err)
+		effective, ok := ForeachWidth(&types.FnRef{Runtime: Runtime, ID: Foreach}, out)
+		assert.True(t, ok)
+		return wf, effective, nil
+	}
+
+	// Each task waits for the task that precedes it by the width.
+	wf, width, err := invoke(&FunctionForeach{}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, width)
+	assert.Empty(t, wf.Tasks["do_0"].Requires)
+	assert.Empty(t, wf.Tasks["do_1"].Requires)
+	assert.Contains(t, wf.Tasks["do_2"].Requires, "do_0")
+	assert.Len(t, wf.Tasks["do_2"].Requires, 1)
+	assert.Contains(t, wf.Tasks["do_4"].Requires, "do_2")
+
+	// Expressions evaluate to floats.
+	_, width, err = invoke(&FunctionForeach{}, float64(3))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, width)
+
+	// Without a width, all tasks run concurrently, regardless of the maximum width.
+	_, width, err = invoke(&FunctionForeach{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, width)
+	_, width, err = invoke(&FunctionForeach{MaxWidth: 4}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, width)
+
+	// An explicit width is clamped to the maximum width.
+	_, width, err = invoke(&FunctionForeach{MaxWidth: 4}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, width)
+
+	for _, invalid := range []interface{}{0, -1, 1.5, "two"} {
+		_, _, err = invoke(&FunctionForeach{}, invalid)
+		assert.Error(t, err, "width: %v", invalid)
+	}
+
+	// Only the outputs of the foreach function record a width.
+	out := typedvalues.MustWrap("foo")
+	out.SetMetadata(ForeachMetadataWidth, "2")
+	_, ok := ForeachWidth(&types.FnRef{Runtime: Runtime, ID: Noop}, out)
+	assert.False(t, ok)
+}
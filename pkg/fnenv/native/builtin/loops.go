@@ -0,0 +1,40 @@
+package builtin
+
+import (
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+)
+
+// Runtime is the name under which the builtin functions are registered as a runtime.
+const Runtime = "internal"
+
+// LoopIterations returns the number of iterations that a run of a loop function (foreach, repeat or while) started,
+// based on the dynamic workflow that it produced as output. It returns false if the function is not a loop function,
+// or if the run did not start any iterations, such as the final run of a while loop.
+//
+// The while function starts a single iteration per run, as each iteration schedules the next run of the loop in a
+// sub-invocation.
+func LoopIterations(fnRef *types.FnRef, output *typedvalues.TypedValue) (int64, bool) {
+	if fnRef.GetRuntime() != Runtime || !controlflow.IsControlFlow(output) {
+		return 0, false
+	}
+	var iterations int64
+	switch fnRef.GetID() {
+	case While:
+		iterations = 1
+	case Repeat, Foreach:
+		wf, err := controlflow.UnwrapWorkflow(output)
+		if err != nil {
+			return 0, false
+		}
+		iterations = int64(len(wf.GetTasks()))
+		if fnRef.GetID() == Foreach {
+			// Exclude the task that collects the outputs of the iterations.
+			iterations--
+		}
+	default:
+		return 0, false
+	}
+	return iterations, iterations > 0
+}
@@ -0,0 +1,50 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoopIterations(t *testing.T) {
+	task := &types.TaskSpec{FunctionRef: Noop}
+
+	repeat, err := (&FunctionRepeat{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			RepeatInputDo:    typedvalues.MustWrap(task),
+			RepeatInputTimes: typedvalues.MustWrap(3),
+		},
+	})
+	assert.NoError(t, err)
+	n, ok := LoopIterations(&types.FnRef{Runtime: Runtime, ID: Repeat}, repeat)
+	assert.True(t, ok)
+	assert.EqualValues(t, 3, n)
+
+	foreach, err := (&FunctionForeach{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			ForeachInputForeach: typedvalues.MustWrap([]interface{}{1, 2}),
+			ForeachInputDo:      typedvalues.MustWrap(task),
+		},
+	})
+	assert.NoError(t, err)
+	n, ok = LoopIterations(&types.FnRef{Runtime: Runtime, ID: Foreach}, foreach)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, n)
+
+	// While starts one iteration per run
+	n, ok = LoopIterations(&types.FnRef{Runtime: Runtime, ID: While}, typedvalues.MustWrap(&types.WorkflowSpec{}))
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, n)
+
+	// The final run of a while loop does not start an iteration
+	_, ok = LoopIterations(&types.FnRef{Runtime: Runtime, ID: While}, typedvalues.MustWrap("done"))
+	assert.False(t, ok)
+
+	// Other functions are not loops
+	_, ok = LoopIterations(&types.FnRef{Runtime: Runtime, ID: If}, repeat)
+	assert.False(t, ok)
+	_, ok = LoopIterations(&types.FnRef{Runtime: "fission", ID: Repeat}, repeat)
+	assert.False(t, ok)
+}
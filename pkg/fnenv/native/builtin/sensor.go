@@ -0,0 +1,192 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	Sensor               = "sensor"
+	SensorInputURL       = "url"
+	SensorInputCondition = "condition"
+	SensorInputInterval  = "interval"
+	SensorInputTimeout   = "timeout"
+
+	SensorDefaultInterval = 10 * time.Second
+	SensorDefaultTimeout  = 10 * time.Minute
+
+	// sensorCheckTimeout is the maximum duration of a single HTTP readiness check.
+	sensorCheckTimeout = 10 * time.Second
+
+	// sensorMaxResultSize is the maximum size of the response body that is included in the result of a check.
+	sensorMaxResultSize = 1024
+)
+
+var ErrSensorNotReady = errors.New("sensor is not ready")
+
+var sensorClient = &http.Client{Timeout: sensorCheckTimeout}
+
+/*
+FunctionSensor waits for an external dependency to become ready, such as a file that appears or a row that is
+inserted into a database, by periodically evaluating a readiness check.
+
+The readiness check is either an HTTP check, which is ready once a GET request to the url returns a 2xx response, or
+a condition, which is ready once the expression evaluates to true. The condition is evaluated again for each poll.
+
+The polls are scheduled by the controller; the task does not occupy a worker between polls. The number of polls and
+the result of the last poll are recorded in the status of the task run. If the check is not ready before the timeout,
+the task fails.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+url             | no       | string            | The URL to poll; ready once it returns a 2xx response.
+condition       | no       | bool              | The condition to evaluate; ready once it is true.
+interval        | no       | string            | The interval between polls. (default: 10s)
+timeout         | no       | string            | The maximum duration to wait for the check to become ready. (default: 10m)
+
+Either the url or the condition needs to be provided.
+
+**output** (*) The body of the response to the HTTP check, or the value of the condition.
+
+**Example**
+
+```yaml
+# ...
+awaitReport:
+  run: sensor
+  inputs:
+    url: http://reports.default/daily/latest
+    interval: 30s
+    timeout: 1h
+# ...
+```
+*/
+type FunctionSensor struct{}
+
+// Invoke evaluates the readiness check once, failing if the check is not ready. The polling of sensors is managed by
+// the controller, which uses CheckSensor instead.
+func (fn *FunctionSensor) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	check, err := CheckSensor(context.Background(), spec.GetInputs())
+	if err != nil {
+		return nil, err
+	}
+	if !check.Ready {
+		return nil, fmt.Errorf("%v: %s", ErrSensorNotReady, check.Result)
+	}
+	return check.Output, nil
+}
+
+// SensorCheck is the result of a single evaluation of the readiness check of a sensor.
+type SensorCheck struct {
+	Ready bool
+
+	// Result is a short description of the result of the check, such as the status of the HTTP response.
+	Result string
+
+	// Output is the output of the sensor, once the check is ready.
+	Output *typedvalues.TypedValue
+}
+
+// IsSensor returns whether the function reference refers to the sensor function.
+func IsSensor(fnRef *types.FnRef) bool {
+	return fnRef.GetRuntime() == Runtime && fnRef.GetID() == Sensor
+}
+
+// SensorTiming returns the interval between the polls and the timeout of the sensor with the inputs.
+func SensorTiming(inputs map[string]*typedvalues.TypedValue) (interval time.Duration, timeout time.Duration,
+	err error) {
+	interval, err = durationInput(inputs, SensorInputInterval, SensorDefaultInterval)
+	if err != nil {
+		return 0, 0, err
+	}
+	timeout, err = durationInput(inputs, SensorInputTimeout, SensorDefaultTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+	if interval <= 0 || timeout <= 0 {
+		return 0, 0, fmt.Errorf("sensor interval (%v) and timeout (%v) should be positive", interval, timeout)
+	}
+	return interval, timeout, nil
+}
+
+// CheckSensor evaluates the readiness check of the sensor with the inputs once. An error is only returned if the
+// check itself is invalid; a check that cannot be performed, such as an unreachable URL, is not ready.
+func CheckSensor(ctx context.Context, inputs map[string]*typedvalues.TypedValue) (SensorCheck, error) {
+	if conditionTv, ok := inputs[SensorInputCondition]; ok {
+		condition, err := typedvalues.UnwrapBool(conditionTv)
+		if err != nil {
+			return SensorCheck{}, fmt.Errorf("failed to format sensor condition to a boolean: %v", err)
+		}
+		return SensorCheck{
+			Ready:  condition,
+			Result: fmt.Sprintf("condition is %v", condition),
+			Output: typedvalues.MustWrap(condition),
+		}, nil
+	}
+
+	urlTv, ok := inputs[SensorInputURL]
+	if !ok {
+		return SensorCheck{}, fmt.Errorf("sensor requires either a '%s' or a '%s' input", SensorInputURL,
+			SensorInputCondition)
+	}
+	url, err := typedvalues.UnwrapString(urlTv)
+	if err != nil {
+		return SensorCheck{}, fmt.Errorf("failed to format sensor url to a string: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return SensorCheck{}, fmt.Errorf("invalid sensor url: %v", err)
+	}
+	resp, err := sensorClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return SensorCheck{Result: err.Error()}, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return SensorCheck{Result: resp.Status}, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return SensorCheck{Result: fmt.Sprintf("failed to read response: %v", err)}, nil
+	}
+	return SensorCheck{
+		Ready:  true,
+		Result: resp.Status,
+		Output: typedvalues.MustWrap(string(body)),
+	}, nil
+}
+
+// TruncateSensorResult truncates the result of a check, such as an error that contains a response body, to a size
+// that can be recorded in the status of the task run.
+func TruncateSensorResult(result string) string {
+	if len(result) <= sensorMaxResultSize {
+		return result
+	}
+	return result[:sensorMaxResultSize] + "..."
+}
+
+func durationInput(inputs map[string]*typedvalues.TypedValue, key string, defaultValue time.Duration) (
+	time.Duration, error) {
+	tv, ok := inputs[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	s, err := typedvalues.UnwrapString(tv)
+	if err != nil {
+		return 0, fmt.Errorf("failed to format %s (%v) to a string: %v", key, tv, err)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %v", key, err)
+	}
+	return d, nil
+}
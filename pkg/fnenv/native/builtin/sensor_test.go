@@ -0,0 +1,87 @@
+package builtin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensorFunctionCondition(t *testing.T) {
+	internalFunctionTest(t,
+		&FunctionSensor{},
+		&types.TaskInvocationSpec{
+			Inputs: map[string]*typedvalues.TypedValue{
+				SensorInputCondition: typedvalues.MustWrap(true),
+			},
+		},
+		true)
+
+	_, err := (&FunctionSensor{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			SensorInputCondition: typedvalues.MustWrap(false),
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestCheckSensorURL(t *testing.T) {
+	var ready int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&ready) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("report"))
+	}))
+	defer server.Close()
+	inputs := map[string]*typedvalues.TypedValue{
+		SensorInputURL: typedvalues.MustWrap(server.URL),
+	}
+
+	check, err := CheckSensor(context.Background(), inputs)
+	assert.NoError(t, err)
+	assert.False(t, check.Ready)
+	assert.Equal(t, "404 Not Found", check.Result)
+
+	atomic.StoreInt32(&ready, 1)
+	check, err = CheckSensor(context.Background(), inputs)
+	assert.NoError(t, err)
+	assert.True(t, check.Ready)
+	assert.Equal(t, "report", typedvalues.MustUnwrap(check.Output))
+
+	// An unreachable URL is not ready, rather than an invalid check.
+	server.Close()
+	check, err = CheckSensor(context.Background(), inputs)
+	assert.NoError(t, err)
+	assert.False(t, check.Ready)
+
+	_, err = CheckSensor(context.Background(), map[string]*typedvalues.TypedValue{})
+	assert.Error(t, err)
+}
+
+func TestSensorTiming(t *testing.T) {
+	interval, timeout, err := SensorTiming(map[string]*typedvalues.TypedValue{})
+	assert.NoError(t, err)
+	assert.Equal(t, SensorDefaultInterval, interval)
+	assert.Equal(t, SensorDefaultTimeout, timeout)
+
+	interval, timeout, err = SensorTiming(map[string]*typedvalues.TypedValue{
+		SensorInputInterval: typedvalues.MustWrap("30s"),
+		SensorInputTimeout:  typedvalues.MustWrap("1h"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, interval)
+	assert.Equal(t, time.Hour, timeout)
+
+	_, _, err = SensorTiming(map[string]*typedvalues.TypedValue{
+		SensorInputInterval: typedvalues.MustWrap("-1s"),
+	})
+	assert.Error(t, err)
+}
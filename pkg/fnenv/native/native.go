@@ -76,6 +76,7 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 			Status:    types.TaskInvocationStatus_FAILED,
 			Error: &types.Error{
 				Message: err.Error(),
+				Code:    types.ErrorCodeFunction,
 			},
 		}, nil
 	}
@@ -0,0 +1,296 @@
+// Package recording provides a function runtime that records the interactions with the functions of another runtime
+// to a fixture file, and a function runtime that replays these interactions. This allows workflows to be tested
+// deterministically, without access to the functions, for example in CI.
+//
+// Interactions are matched by the function reference and the hash of the inputs of the task run (see
+// typedvalues.HashInputs). The resolutions of function references are recorded as well, so that workflows can be
+// parsed without access to the runtime.
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+var (
+	ErrUnknownMode = errors.New("unknown recording mode")
+
+	metricInteractions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv",
+		Name:      "recorded_interactions_total",
+		Help:      "Number of function interactions by recording mode and result (recorded, replayed or unmatched)",
+	}, []string{"mode", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(metricInteractions)
+}
+
+// Config contains the configuration of the recording of function interactions.
+type Config struct {
+	// Mode is either ModeRecord or ModeReplay.
+	Mode string
+
+	// File is the fixture file that the interactions are recorded to, or replayed from.
+	File string
+}
+
+// Validate returns an error if the mode of the config is unknown, or no fixture file has been provided.
+func (c Config) Validate() error {
+	if c.Mode != ModeRecord && c.Mode != ModeReplay {
+		return fmt.Errorf("%v: '%s' (expected '%s' or '%s')", ErrUnknownMode, c.Mode, ModeRecord, ModeReplay)
+	}
+	if len(c.File) == 0 {
+		return errors.New("no fixture file provided")
+	}
+	return nil
+}
+
+// Interaction is a recorded function request along with its response.
+type Interaction struct {
+	FnRef     string          `json:"fnRef"`
+	InputHash string          `json:"inputHash"`
+	Status    json.RawMessage `json:"status"`
+}
+
+// Fixture is the format of the fixture files.
+type Fixture struct {
+	// Resolutions contains the resolved function IDs, with the key being the formatted function reference.
+	Resolutions  map[string]string `json:"resolutions,omitempty"`
+	Interactions []*Interaction    `json:"interactions"`
+}
+
+// LoadFixture reads the fixture file.
+func LoadFixture(file string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	fixture := &Fixture{}
+	if err := json.Unmarshal(data, fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture file %s: %v", file, err)
+	}
+	return fixture, nil
+}
+
+// interactionKey returns the key by which a function request is matched with the recorded interactions.
+func interactionKey(fnRef string, inputHash string) string {
+	return fnRef + "#" + inputHash
+}
+
+func requestKey(spec *types.TaskInvocationSpec) (fnRef string, inputHash string, err error) {
+	inputHash, err = typedvalues.HashInputs(spec.GetInputs())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to hash inputs of task: %v", err)
+	}
+	return spec.GetFnRef().Format(), inputHash, nil
+}
+
+// Recorder is a function runtime that records the interactions with the functions of the recorded runtime to the
+// fixture file. The fixture is rewritten after every recorded interaction. An interaction that is identical to a
+// previously recorded interaction replaces it.
+//
+// Requests that fail outside of the control of the function, for example because the function could not be reached,
+// are not recorded.
+type Recorder struct {
+	runtime  fnenv.Runtime
+	resolver fnenv.RuntimeResolver
+	file     string
+	fixture  *Fixture
+	index    map[string]int
+	mu       *sync.Mutex
+}
+
+// NewRecorder creates a recorder for the runtime and resolver, which records to the file. The resolver is optional.
+// Existing interactions in the file are retained.
+func NewRecorder(runtime fnenv.Runtime, resolver fnenv.RuntimeResolver, file string) (*Recorder, error) {
+	fixture, err := LoadFixture(file)
+	if os.IsNotExist(err) {
+		fixture, err = &Fixture{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{
+		runtime:  runtime,
+		resolver: resolver,
+		file:     file,
+		fixture:  fixture,
+		index:    map[string]int{},
+		mu:       &sync.Mutex{},
+	}
+	for i, interaction := range fixture.Interactions {
+		r.index[interactionKey(interaction.FnRef, interaction.InputHash)] = i
+	}
+	return r, nil
+}
+
+func (r *Recorder) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	status, err := r.runtime.Invoke(spec, opts...)
+	if err != nil || status == nil {
+		return status, err
+	}
+	fnRef, inputHash, err := requestKey(spec)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := (&jsonpb.Marshaler{}).Marshal(buf, status); err != nil {
+		return nil, fmt.Errorf("failed to record response of %s: %v", fnRef, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	interaction := &Interaction{
+		FnRef:     fnRef,
+		InputHash: inputHash,
+		Status:    buf.Bytes(),
+	}
+	key := interactionKey(fnRef, inputHash)
+	if i, ok := r.index[key]; ok {
+		r.fixture.Interactions[i] = interaction
+	} else {
+		r.index[key] = len(r.fixture.Interactions)
+		r.fixture.Interactions = append(r.fixture.Interactions, interaction)
+	}
+	if err := r.save(); err != nil {
+		logrus.Errorf("Failed to save recorded interaction with %s to %s: %v", fnRef, r.file, err)
+	} else {
+		metricInteractions.WithLabelValues(ModeRecord, "recorded").Inc()
+	}
+	return status, nil
+}
+
+func (r *Recorder) Resolve(ref types.FnRef) (string, error) {
+	if r.resolver == nil {
+		return ref.ID, nil
+	}
+	id, err := r.resolver.Resolve(ref)
+	if err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fixture.Resolutions == nil {
+		r.fixture.Resolutions = map[string]string{}
+	}
+	r.fixture.Resolutions[ref.Format()] = id
+	if err := r.save(); err != nil {
+		logrus.Errorf("Failed to save recorded resolution of %s to %s: %v", ref.Format(), r.file, err)
+	}
+	return id, nil
+}
+
+// save writes the fixture to a temporary file first, to avoid leaving a partially written fixture file behind.
+func (r *Recorder) save() error {
+	sort.SliceStable(r.fixture.Interactions, func(i, j int) bool {
+		return r.fixture.Interactions[i].FnRef < r.fixture.Interactions[j].FnRef
+	})
+	for i, interaction := range r.fixture.Interactions {
+		r.index[interactionKey(interaction.FnRef, interaction.InputHash)] = i
+	}
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(r.file), filepath.Base(r.file)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), r.file)
+}
+
+// ErrUnmatchedRequest is returned by the Replayer for requests that do not match a recorded interaction.
+type ErrUnmatchedRequest struct {
+	FnRef     string
+	InputHash string
+	File      string
+}
+
+func (e ErrUnmatchedRequest) Error() string {
+	return fmt.Sprintf("no recorded interaction with %s for inputs with hash %s in fixture %s", e.FnRef, e.InputHash,
+		e.File)
+}
+
+// Replayer is a function runtime that serves the responses of the recorded interactions. Requests that do not match
+// a recorded interaction fail with an ErrUnmatchedRequest. Function references that have not been recorded are
+// resolved to their ID.
+type Replayer struct {
+	file         string
+	resolutions  map[string]string
+	interactions map[string]*types.TaskInvocationStatus
+}
+
+// NewReplayer creates a replayer of the interactions recorded in the fixture file.
+func NewReplayer(file string) (*Replayer, error) {
+	fixture, err := LoadFixture(file)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replayer{
+		file:         file,
+		resolutions:  fixture.Resolutions,
+		interactions: map[string]*types.TaskInvocationStatus{},
+	}
+	for _, interaction := range fixture.Interactions {
+		status := &types.TaskInvocationStatus{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(interaction.Status), status); err != nil {
+			return nil, fmt.Errorf("failed to parse recorded response of %s: %v", interaction.FnRef, err)
+		}
+		r.interactions[interactionKey(interaction.FnRef, interaction.InputHash)] = status
+	}
+	return r, nil
+}
+
+func (r *Replayer) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	fnRef, inputHash, err := requestKey(spec)
+	if err != nil {
+		return nil, err
+	}
+	status, ok := r.interactions[interactionKey(fnRef, inputHash)]
+	if !ok {
+		metricInteractions.WithLabelValues(ModeReplay, "unmatched").Inc()
+		return nil, ErrUnmatchedRequest{FnRef: fnRef, InputHash: inputHash, File: r.file}
+	}
+	metricInteractions.WithLabelValues(ModeReplay, "replayed").Inc()
+	return proto.Clone(status).(*types.TaskInvocationStatus), nil
+}
+
+func (r *Replayer) Resolve(ref types.FnRef) (string, error) {
+	if id, ok := r.resolutions[ref.Format()]; ok {
+		return id, nil
+	}
+	return ref.ID, nil
+}
@@ -0,0 +1,85 @@
+package recording
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recording")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "fixture.json")
+
+	var calls int
+	runtime := mock.NewRuntime()
+	runtime.ManualExecution = true
+	runtime.Functions["echo"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		calls++
+		return spec.Inputs[types.InputMain], nil
+	}
+	fnRef := types.NewFnRef("fission", "", "echo")
+	newSpec := func(input string) *types.TaskInvocationSpec {
+		return &types.TaskInvocationSpec{
+			FnRef: &fnRef,
+			Inputs: map[string]*typedvalues.TypedValue{
+				types.InputMain: typedvalues.MustWrap(input),
+			},
+		}
+	}
+
+	recorder, err := NewRecorder(runtime, nil, file)
+	assert.NoError(t, err)
+	for _, input := range []string{"foo", "bar", "foo"} {
+		status, err := recorder.Invoke(newSpec(input))
+		assert.NoError(t, err)
+		assert.Equal(t, input, typedvalues.MustUnwrap(status.Output))
+	}
+	assert.Equal(t, 3, calls)
+	fixture, err := LoadFixture(file)
+	assert.NoError(t, err)
+	assert.Len(t, fixture.Interactions, 2)
+
+	// The replayer serves the recorded responses, without calling the function.
+	replayer, err := NewReplayer(file)
+	assert.NoError(t, err)
+	for _, input := range []string{"foo", "bar"} {
+		status, err := replayer.Invoke(newSpec(input))
+		assert.NoError(t, err)
+		assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED, status.Status)
+		assert.Equal(t, input, typedvalues.MustUnwrap(status.Output))
+	}
+	assert.Equal(t, 3, calls)
+
+	// Requests that have not been recorded fail.
+	_, err = replayer.Invoke(newSpec("baz"))
+	assert.IsType(t, ErrUnmatchedRequest{}, err)
+	assert.Contains(t, err.Error(), fnRef.Format())
+	otherFnRef := types.NewFnRef("fission", "", "other")
+	spec := newSpec("foo")
+	spec.FnRef = &otherFnRef
+	_, err = replayer.Invoke(spec)
+	assert.IsType(t, ErrUnmatchedRequest{}, err)
+
+	// A new recorder retains the interactions that have been recorded before.
+	recorder, err = NewRecorder(runtime, nil, file)
+	assert.NoError(t, err)
+	_, err = recorder.Invoke(newSpec("baz"))
+	assert.NoError(t, err)
+	fixture, err = LoadFixture(file)
+	assert.NoError(t, err)
+	assert.Len(t, fixture.Interactions, 3)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{Mode: ModeReplay, File: "fixture.json"}.Validate())
+	assert.Error(t, Config{Mode: "rewind", File: "fixture.json"}.Validate())
+	assert.Error(t, Config{Mode: ModeRecord}.Validate())
+}
@@ -0,0 +1,202 @@
+// Package signing verifies that the responses of functions were produced by the intended function, and were not
+// tampered with in transit.
+//
+// For each request to a function that requires signed responses, the runtime adds a random nonce in the HeaderNonce
+// header. The function signs its response with an HMAC-SHA256 over the nonce followed by the response body, using a
+// key that it shares with the workflow engine, and returns the hex-encoded signature in the HeaderSignature header.
+// Binding the signature to the nonce ensures that a response to another request, such as a misrouted or replayed
+// response, is rejected as well.
+//
+// Multiple keys can be configured at the same time, of which any is accepted. This allows the keys to be rotated
+// without downtime: add the new key, update the functions, and remove the old key.
+package signing
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// HeaderNonce contains the nonce of the function request, which the function includes in its signature.
+	HeaderNonce = "X-Workflows-Signature-Nonce"
+
+	// HeaderSignature contains the hex-encoded HMAC-SHA256 signature of the function response.
+	HeaderSignature = "X-Workflows-Signature"
+)
+
+var (
+	ErrMissingSignature = errors.New("response is not signed")
+	ErrInvalidSignature = errors.New("response signature is invalid")
+	ErrNoKeys           = errors.New("no signing keys configured")
+)
+
+var metricVerifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "fnenv",
+	Name:      "signature_verifications_total",
+	Help:      "Number of verified function response signatures, by result (valid, missing or invalid)",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(metricVerifications)
+}
+
+// Config contains the configuration of the verification of function responses.
+type Config struct {
+	// Keys are the shared keys of which any is accepted.
+	Keys [][]byte
+
+	// Functions contains the functions that are required to sign their responses, either as used in the workflow
+	// definitions (e.g. 'payments') or fully resolved (e.g. 'fission://default/payments').
+	Functions []string
+}
+
+// Verifier verifies the signatures of the responses of the functions that have opted in to signing.
+//
+// A nil Verifier does not require any function to sign its responses.
+type Verifier struct {
+	keys      [][]byte
+	functions map[string]struct{}
+	mu        *sync.RWMutex
+}
+
+func NewVerifier(config Config) (*Verifier, error) {
+	v := &Verifier{
+		functions: map[string]struct{}{},
+		mu:        &sync.RWMutex{},
+	}
+	for _, fn := range config.Functions {
+		v.functions[fn] = struct{}{}
+	}
+	if err := v.SetKeys(config.Keys); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SetKeys replaces the accepted keys, for example to rotate them.
+func (v *Verifier) SetKeys(keys [][]byte) error {
+	var accepted [][]byte
+	for _, key := range keys {
+		if len(key) > 0 {
+			accepted = append(accepted, key)
+		}
+	}
+	if len(accepted) == 0 {
+		return ErrNoKeys
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys = accepted
+	return nil
+}
+
+// Required returns whether the function is required to sign its responses.
+func (v *Verifier) Required(fn types.FnRef) bool {
+	if v == nil {
+		return false
+	}
+	_, byRef := v.functions[fn.Format()]
+	_, byID := v.functions[fn.ID]
+	return byRef || byID
+}
+
+// Sign adds a new nonce to the function request, and returns it.
+func (v *Verifier) Sign(req *http.Request) string {
+	nonce := util.UID()
+	req.Header.Set(HeaderNonce, nonce)
+	return nonce
+}
+
+// Verify verifies the signature of the response to the request with the nonce. The body of the response is read to
+// compute the signature, and is replaced so that it can be read again.
+func (v *Verifier) Verify(nonce string, resp *http.Response) error {
+	signature, err := hex.DecodeString(resp.Header.Get(HeaderSignature))
+	if err != nil || len(signature) == 0 {
+		metricVerifications.WithLabelValues("missing").Inc()
+		return ErrMissingSignature
+	}
+	var body []byte
+	if resp.Body != nil {
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %v", err)
+		}
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for _, key := range v.keys {
+		if hmac.Equal(signature, sign(key, nonce, body)) {
+			metricVerifications.WithLabelValues("valid").Inc()
+			return nil
+		}
+	}
+	metricVerifications.WithLabelValues("invalid").Inc()
+	return ErrInvalidSignature
+}
+
+// Signature returns the hex-encoded signature of a response body to the request with the nonce, as it should be
+// returned by a function in the HeaderSignature header.
+func Signature(key []byte, nonce string, body []byte) string {
+	return hex.EncodeToString(sign(key, nonce, body))
+}
+
+func sign(key []byte, nonce string, body []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(nonce))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// ParseKeys decodes the base64-encoded keys.
+func ParseKeys(encoded []string) ([][]byte, error) {
+	var keys [][]byte
+	for _, s := range encoded {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// LoadKeyFile reads the base64-encoded keys from the file, one key per line.
+func LoadKeyFile(path string) ([][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var encoded []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		encoded = append(encoded, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ParseKeys(encoded)
+}
@@ -0,0 +1,105 @@
+package signing
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResponse(body string, signature string) *http.Response {
+	resp := &http.Response{
+		Header: http.Header{},
+		Body:   ioutil.NopCloser(bytes.NewBufferString(body)),
+	}
+	if len(signature) > 0 {
+		resp.Header.Set(HeaderSignature, signature)
+	}
+	return resp
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	key := []byte("secret")
+	v, err := NewVerifier(Config{Keys: [][]byte{key}})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://fn/", nil)
+	nonce := v.Sign(req)
+	assert.NotEmpty(t, nonce)
+	assert.Equal(t, nonce, req.Header.Get(HeaderNonce))
+
+	// Valid signature; the body remains readable
+	resp := newResponse("output", Signature(key, nonce, []byte("output")))
+	assert.NoError(t, v.Verify(nonce, resp))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, "output", string(body))
+
+	// Missing signature
+	assert.Equal(t, ErrMissingSignature, v.Verify(nonce, newResponse("output", "")))
+
+	// Tampered body
+	resp = newResponse("tampered", Signature(key, nonce, []byte("output")))
+	assert.Equal(t, ErrInvalidSignature, v.Verify(nonce, resp))
+
+	// Signature for another request
+	resp = newResponse("output", Signature(key, "other", []byte("output")))
+	assert.Equal(t, ErrInvalidSignature, v.Verify(nonce, resp))
+
+	// Wrong key
+	resp = newResponse("output", Signature([]byte("other"), nonce, []byte("output")))
+	assert.Equal(t, ErrInvalidSignature, v.Verify(nonce, resp))
+}
+
+func TestVerifier_SetKeys(t *testing.T) {
+	oldKey, newKey := []byte("old"), []byte("new")
+	v, err := NewVerifier(Config{Keys: [][]byte{oldKey}})
+	assert.NoError(t, err)
+
+	// During the rotation, both keys are accepted
+	assert.NoError(t, v.SetKeys([][]byte{oldKey, newKey}))
+	assert.NoError(t, v.Verify("n", newResponse("a", Signature(oldKey, "n", []byte("a")))))
+	assert.NoError(t, v.Verify("n", newResponse("a", Signature(newKey, "n", []byte("a")))))
+
+	// After the rotation, the old key is rejected
+	assert.NoError(t, v.SetKeys([][]byte{newKey}))
+	assert.Equal(t, ErrInvalidSignature, v.Verify("n", newResponse("a", Signature(oldKey, "n", []byte("a")))))
+
+	// Removing all keys is refused
+	assert.Equal(t, ErrNoKeys, v.SetKeys(nil))
+	assert.NoError(t, v.Verify("n", newResponse("a", Signature(newKey, "n", []byte("a")))))
+}
+
+func TestVerifier_Required(t *testing.T) {
+	var nilVerifier *Verifier
+	assert.False(t, nilVerifier.Required(types.FnRef{Runtime: "fission", ID: "payments"}))
+
+	v, err := NewVerifier(Config{
+		Keys:      [][]byte{[]byte("secret")},
+		Functions: []string{"payments", "fission://billing/invoice"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, v.Required(types.FnRef{Runtime: "fission", ID: "payments"}))
+	assert.True(t, v.Required(types.FnRef{Runtime: "fission", Namespace: "billing", ID: "invoice"}))
+	assert.False(t, v.Required(types.FnRef{Runtime: "fission", ID: "invoice"}))
+}
+
+func TestLoadKeyFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "signing-keys")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("c2VjcmV0\n\n  b3RoZXI=  \n")
+	assert.NoError(t, err)
+	f.Close()
+
+	keys, err := LoadKeyFile(f.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("secret"), []byte("other")}, keys)
+
+	_, err = ParseKeys([]string{"not base64!"})
+	assert.Error(t, err)
+}
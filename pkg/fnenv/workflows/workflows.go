@@ -23,6 +23,7 @@ import (
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/validate"
+	"github.com/fission/fission-workflows/pkg/util/backoff"
 	"github.com/fission/fission-workflows/pkg/util/labels"
 	"github.com/fission/fission-workflows/pkg/util/pubsub"
 	"github.com/golang/protobuf/ptypes"
@@ -31,27 +32,78 @@ import (
 )
 
 const (
-	PollInterval = time.Duration(100) * time.Millisecond
-	Name         = "workflows"
+	PollInterval    = time.Duration(100) * time.Millisecond
+	MaxPollInterval = time.Duration(2) * time.Second
+	Name            = "workflows"
 )
 
+// ErrWorkflowNotReady is returned when the workflow of an invocation did not become ready within the time that the
+// runtime was allowed to wait for it.
+var ErrWorkflowNotReady = errors.New("workflow never became ready")
+
+// ErrWorkflowNotFound is returned when an invocation references a workflow that does not exist, unless the runtime
+// allows deferred binding.
+type ErrWorkflowNotFound struct {
+	WorkflowID string
+}
+
+func (e ErrWorkflowNotFound) Error() string {
+	return fmt.Sprintf("workflow not found: %s", e.WorkflowID)
+}
+
+// workflowGetter looks up workflows, such as the store.Workflows or the store.WorkflowCache.
+type workflowGetter interface {
+	GetWorkflow(workflowID string) (*types.Workflow, error)
+}
+
 // Runtime provides an abstraction of the workflow engine itself to use as a Task runtime environment.
 type Runtime struct {
-	api          *api.Invocation
-	invocations  *store.Invocations
-	workflows    *store.Workflows
-	pollInterval time.Duration
+	api             *api.Invocation
+	invocations     *store.Invocations
+	workflows       workflowGetter
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+	deferredBinding bool
 }
 
 func NewRuntime(api *api.Invocation, invocations *store.Invocations, workflows *store.Workflows) *Runtime {
 	return &Runtime{
-		api:          api,
-		invocations:  invocations,
-		workflows:    workflows,
-		pollInterval: PollInterval,
+		api:             api,
+		invocations:     invocations,
+		workflows:       workflows,
+		pollInterval:    PollInterval,
+		maxPollInterval: MaxPollInterval,
 	}
 }
 
+// SetWorkflowCache looks up the workflows of the invocations in the cache, rather than in the workflow store, to avoid
+// looking up the same workflow for each of many concurrent invocations.
+func (rt *Runtime) SetWorkflowCache(cache *store.WorkflowCache) {
+	rt.workflows = cache
+}
+
+// SetDeferredBinding allows invocations of workflows that do not exist yet. Their workflows are awaited to be created
+// and to become ready, within the time that the runtime is allowed to wait for the workflow (see fnenv.AwaitWorkflow).
+// By default, invocations of workflows that do not exist fail immediately with ErrWorkflowNotFound.
+func (rt *Runtime) SetDeferredBinding(enabled bool) {
+	rt.deferredBinding = enabled
+}
+
+// ResolveWorkflow returns the workflow once it is ready, waiting up to the timeout for it to become ready. Unless
+// deferred binding is enabled, ErrWorkflowNotFound is returned immediately if the workflow does not exist.
+func (rt *Runtime) ResolveWorkflow(ctx context.Context, workflowID string, timeout time.Duration) (*types.Workflow,
+	error) {
+	if !rt.deferredBinding {
+		wf, err := rt.workflows.GetWorkflow(workflowID)
+		if (err != nil && fes.ErrEntityNotFound.Is(err)) || (err == nil && wf == nil) {
+			return nil, ErrWorkflowNotFound{WorkflowID: workflowID}
+		}
+	}
+	awaitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return rt.awaitReadyWorkflow(awaitCtx, workflowID)
+}
+
 func (rt *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus, error) {
 	if err := validate.TaskInvocationSpec(spec); err != nil {
 		return nil, err
@@ -63,7 +115,7 @@ func (rt *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOp
 	}
 
 	// Note: currently context is not supported in the runtime interface, so we use a background context.
-	wfi, err := rt.InvokeWorkflow(wfSpec, opts...)
+	wfi, err := rt.invokeWorkflow(wfSpec, true, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +123,14 @@ func (rt *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOp
 }
 
 func (rt *Runtime) InvokeWorkflow(spec *types.WorkflowInvocationSpec, opts ...fnenv.InvokeOption) (*types.WorkflowInvocation, error) {
+	return rt.invokeWorkflow(spec, false, opts...)
+}
+
+// invokeWorkflow invokes the workflow, and waits for the result of the invocation. If enforceContract is set, which is
+// the case for the invocations by other workflows, the inputs and the output of the invocation are validated against
+// the contract of the workflow.
+func (rt *Runtime) invokeWorkflow(spec *types.WorkflowInvocationSpec, enforceContract bool,
+	opts ...fnenv.InvokeOption) (*types.WorkflowInvocation, error) {
 	cfg := fnenv.ParseInvokeOptions(opts)
 	if err := validate.WorkflowInvocationSpec(spec); err != nil {
 		return nil, err
@@ -85,9 +145,7 @@ func (rt *Runtime) InvokeWorkflow(spec *types.WorkflowInvocationSpec, opts ...fn
 
 	// Check if the workflow required by the invocation exists
 	if spec.Workflow == nil {
-		awaitWorkflowCtx, cancel := context.WithTimeout(ctx, cfg.AwaitWorkflow)
-		wf, err := rt.awaitReadyWorkflow(awaitWorkflowCtx, spec.GetWorkflowId())
-		cancel()
+		wf, err := rt.ResolveWorkflow(ctx, spec.GetWorkflowId(), cfg.AwaitWorkflow)
 		if err != nil {
 			span.LogKV("error", err)
 			return nil, err
@@ -103,6 +161,15 @@ func (rt *Runtime) InvokeWorkflow(spec *types.WorkflowInvocationSpec, opts ...fn
 
 	span.SetTag("workflow.name", spec.GetWorkflow().GetMetadata().GetName())
 
+	// Fail fast if the inputs do not satisfy the contract of the workflow, rather than failing somewhere in the workflow.
+	contract := spec.GetWorkflow().GetSpec().GetContract()
+	if enforceContract {
+		if err := validate.ContractInputs(contract, spec.GetInputs()); err != nil {
+			span.LogKV("error", err)
+			return nil, fmt.Errorf("workflow %s: %v", spec.GetWorkflow().GetMetadata().GetId(), err)
+		}
+	}
+
 	// If debugging mode is enabled, add all inputs to the trace.
 	if logrus.GetLevel() == logrus.DebugLevel {
 		var inputs interface{}
@@ -134,13 +201,19 @@ func (rt *Runtime) InvokeWorkflow(spec *types.WorkflowInvocationSpec, opts ...fn
 	if err != nil {
 		return nil, err
 	}
-	awaitInvocationCtx, cancel := context.WithDeadline(ctx, deadline)
-	invocation, err := rt.awaitInvocationResult(awaitInvocationCtx, invocationID)
-	cancel()
+	invocation, err := rt.awaitInvocationResult(ctx, deadline, invocationID)
 	if err != nil {
 		span.LogKV("error", err)
 		return nil, err
 	}
+
+	// The output of a successful invocation should satisfy the guarantees of the contract.
+	if enforceContract && invocation.GetStatus().Successful() {
+		if err := validate.ContractOutput(contract, invocation.GetStatus().GetOutput()); err != nil {
+			span.LogKV("error", err)
+			return nil, fmt.Errorf("workflow %s: %v", spec.GetWorkflow().GetMetadata().GetId(), err)
+		}
+	}
 	return invocation, nil
 }
 
@@ -197,7 +270,7 @@ func (rt *Runtime) awaitReadyWorkflow(ctx context.Context, workflowID string) (w
 			if result, err := rt.checkForReadyWorkflow(workflowID); result != nil {
 				return result, nil
 			} else {
-				return nil, err
+				return nil, fmt.Errorf("%v: %v", ErrWorkflowNotReady, err)
 			}
 		case <-sub.Ch:
 			return rt.checkForReadyWorkflow(workflowID)
@@ -206,8 +279,15 @@ func (rt *Runtime) awaitReadyWorkflow(ctx context.Context, workflowID string) (w
 	return rt.pollUntilWorkflowResult(ctx, workflowID)
 }
 
-func (rt *Runtime) awaitInvocationResult(ctx context.Context, invocationID string) (invocation *types.WorkflowInvocation, err error) {
+// awaitInvocationResult waits until the invocation has finished, and returns it. If the deadline of the invocation
+// passes, or the context of the caller is done before the invocation has finished, the invocation is canceled. In the
+// latter case the context error is returned, and the invocation is canceled with ErrInvocationCallerCanceled as the
+// reason, to distinguish it from other cancellations.
+func (rt *Runtime) awaitInvocationResult(ctx context.Context, deadline time.Time,
+	invocationID string) (invocation *types.WorkflowInvocation, err error) {
 	if pub, ok := rt.invocations.CacheReader.(pubsub.Publisher); ok {
+		awaitCtx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
 		sub := pub.Subscribe(pubsub.SubscriptionOptions{
 			Buffer: 1,
 			LabelMatcher: labels.And(
@@ -225,21 +305,21 @@ func (rt *Runtime) awaitInvocationResult(ctx context.Context, invocationID strin
 
 		// Block until either we received an completion event or the context completed
 		select {
-		case <-ctx.Done():
+		case <-awaitCtx.Done():
 			// Check once before cancelling, whether cancelling is needed.
 			if result := rt.checkForInvocationResult(invocationID); result != nil {
 				return result, nil
 			}
 
 			// Cancel the invocation
-			err := rt.api.Cancel(invocationID)
-			if err == nil {
-				err = errors.New(api.ErrInvocationCanceled)
-			} else {
+			if err := rt.cancelInvocation(ctx, invocationID); err != nil {
 				logrus.Errorf("Failed to cancel invocation: %v", err)
+				return nil, err
 			}
-			//span.LogKV("error", err)
-			return nil, err
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, errors.New(api.ErrInvocationCanceled)
 		case <-sub.Ch:
 			logrus.Debugf("Received terminal event for invocation %s", invocationID)
 			return rt.checkForInvocationResult(invocationID), nil
@@ -248,55 +328,73 @@ func (rt *Runtime) awaitInvocationResult(ctx context.Context, invocationID strin
 
 	// Fallback to polling the cache if the cache does not support pubsub.
 	logrus.Debug("Workflows store does not support pubsub, falling back to polling.")
-	return rt.pollUntilInvocationResult(ctx, invocationID)
+	return rt.pollUntilInvocationResult(ctx, deadline, invocationID)
 }
 
-// pollUntilInvocationResult continuously (or until the context is canceled) polls whether the workflow invocation with the
-// specified ID has finished. It either returns the invocation object (if completed) or an error in case of timeouts or
-// context cancellation.
-func (rt *Runtime) pollUntilInvocationResult(ctx context.Context, wfiID string) (*types.WorkflowInvocation, error) {
+// pollUntilInvocationResult continuously (or until the deadline passes or the context is canceled) polls whether the
+// workflow invocation with the specified ID has finished. It either returns the invocation object (if completed) or
+// an error in case of timeouts or context cancellation.
+func (rt *Runtime) pollUntilInvocationResult(ctx context.Context, deadline time.Time,
+	wfiID string) (*types.WorkflowInvocation, error) {
+	awaitCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
 	for {
 		if result := rt.checkForInvocationResult(wfiID); result != nil {
 			return result, nil
 		}
 
 		select {
-		case <-ctx.Done():
-			err := rt.api.Cancel(wfiID)
+		case <-awaitCtx.Done():
+			err := rt.cancelInvocation(ctx, wfiID)
 			if err != nil {
 				return nil, err
 			}
-			return nil, ctx.Err()
+			return nil, awaitCtx.Err()
 		default:
 			time.Sleep(rt.pollInterval)
 		}
 	}
 }
 
+// cancelInvocation cancels the invocation. If the context of the caller is done, the cancellation records it as the
+// reason.
+func (rt *Runtime) cancelInvocation(ctx context.Context, invocationID string) error {
+	opts := []api.CallOption{api.WithContext(ctx)}
+	if err := ctx.Err(); err != nil {
+		opts = append(opts, api.WithReason(fmt.Sprintf("%s: %v", api.ErrInvocationCallerCanceled, err)))
+	}
+	return rt.api.Cancel(invocationID, opts...)
+}
+
+// pollUntilWorkflowResult polls (until the context is canceled) whether the workflow with the specified ID is ready.
+// To avoid busy-waiting on workflows that take long to become ready, the interval between polls increases
+// exponentially, up to the maximum poll interval of the runtime.
 func (rt *Runtime) pollUntilWorkflowResult(ctx context.Context, workflowID string) (*types.Workflow, error) {
-	for {
-		if wf, err := rt.checkForReadyWorkflow(workflowID); err == nil {
+	for attempt := 0; ; attempt++ {
+		wf, err := rt.checkForReadyWorkflow(workflowID)
+		if err == nil {
 			return wf, nil
 		}
 
+		wait := backoff.ExponentialBackoff(attempt, rt.pollInterval)
+		if wait <= 0 || wait > rt.maxPollInterval {
+			wait = rt.maxPollInterval
+		}
 		select {
 		case <-ctx.Done():
-			err := rt.api.Cancel(workflowID)
-			if err != nil {
-				return nil, err
-			}
-			return nil, ctx.Err()
-		default:
-			time.Sleep(rt.pollInterval)
+			return nil, fmt.Errorf("%v: %v", ErrWorkflowNotReady, err)
+		case <-time.After(wait):
 		}
 	}
 }
 
 func toWorkflowSpec(spec *types.TaskInvocationSpec) (*types.WorkflowInvocationSpec, error) {
 	wfSpec := &types.WorkflowInvocationSpec{
-		WorkflowId: spec.FnRef.ID,
-		Inputs:     spec.Inputs,
-		Deadline:   spec.Deadline,
+		WorkflowId:     spec.FnRef.ID,
+		Inputs:         spec.Inputs,
+		Deadline:       spec.Deadline,
+		ParentTaskId:   spec.TaskId,
+		ParentPriority: spec.Priority,
 	}
 	// Check for the parent input
 	if parentTv, ok := spec.Inputs[types.InputParent]; ok {
@@ -13,6 +13,7 @@ import (
 	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
 	"github.com/fission/fission-workflows/pkg/fes/cache"
 	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/fnenv"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/util"
@@ -44,6 +45,84 @@ func TestRuntime_InvokeWorkflow_PollTimeout(t *testing.T) {
 	assert.EqualError(t, err, context.DeadlineExceeded.Error())
 }
 
+func TestRuntime_InvokeWorkflow_AwaitWorkflowBackoff(t *testing.T) {
+	runtime, _, _, _ := setup()
+	runtime.invocations = store.NewInvocationStore(testutil.NewCache()) // ensure that cache does not support pubsub
+	runtime.pollInterval = 10 * time.Millisecond
+	runtime.maxPollInterval = 40 * time.Millisecond
+	workflows := &countingCache{Cache: testutil.NewCache()}
+	err := workflows.Put(&types.Workflow{
+		Metadata: &types.ObjectMetadata{
+			Id: workflowID,
+		},
+		Status: &types.WorkflowStatus{
+			Status: types.WorkflowStatus_QUEUED,
+		},
+	})
+	assert.NoError(t, err)
+	runtime.workflows = store.NewWorkflowsStore(workflows)
+
+	_, err = runtime.InvokeWorkflow(types.NewWorkflowInvocationSpec(workflowID, defaultDeadline()),
+		fnenv.AwaitWorkflow(200*time.Millisecond))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrWorkflowNotReady.Error())
+
+	// With a fixed poll interval of 10ms the workflow would have been checked ~20 times; the backoff (10, 20, 40, 40,
+	// ...ms) should bound this to ~7 checks.
+	assert.True(t, workflows.count > 1, "expected the workflow to be checked more than once")
+	assert.True(t, workflows.count <= 10, "expected a bounded number of checks, but was %d", workflows.count)
+}
+
+func TestRuntime_InvokeWorkflow_NotFound(t *testing.T) {
+	runtime, _, backend, _ := setup()
+
+	// By default, the invocation is rejected immediately, before it is created.
+	start := time.Now()
+	_, err := runtime.InvokeWorkflow(types.NewWorkflowInvocationSpec("unknown", defaultDeadline()),
+		fnenv.AwaitWorkflow(time.Second))
+	assert.Equal(t, ErrWorkflowNotFound{WorkflowID: "unknown"}, err)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, 0, backend.Len())
+}
+
+func TestRuntime_InvokeWorkflow_DeferredBinding(t *testing.T) {
+	runtime, invocationAPI, _, cc := setup()
+	runtime.SetDeferredBinding(true)
+	workflows := testutil.NewCache()
+	runtime.workflows = store.NewWorkflowsStore(workflows)
+
+	// The workflow is created after the invocation was requested.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		err := workflows.Put(&types.Workflow{
+			Metadata: &types.ObjectMetadata{Id: "later"},
+			Status:   &types.WorkflowStatus{Status: types.WorkflowStatus_READY},
+		})
+		if err != nil {
+			panic(err)
+		}
+	}()
+	go func() {
+		for len(cc.List()) == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		err := invocationAPI.Complete(cc.List()[0].Id, typedvalues.MustWrap("foo"), nil)
+		if err != nil {
+			panic(err)
+		}
+	}()
+	wfi, err := runtime.InvokeWorkflow(types.NewWorkflowInvocationSpec("later", defaultDeadline()),
+		fnenv.AwaitWorkflow(300*time.Millisecond))
+	assert.NoError(t, err)
+	assert.True(t, wfi.GetStatus().Successful())
+
+	// Workflows that are not created in time are still rejected.
+	_, err = runtime.InvokeWorkflow(types.NewWorkflowInvocationSpec("unknown", defaultDeadline()),
+		fnenv.AwaitWorkflow(50*time.Millisecond))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrWorkflowNotReady.Error())
+}
+
 func TestRuntime_InvokeWorkflow_InvalidSpec(t *testing.T) {
 	runtime, _, _, _ := setup()
 	_, err := runtime.InvokeWorkflow(types.NewWorkflowInvocationSpec("", defaultDeadline()))
@@ -144,6 +223,39 @@ func TestRuntime_InvokeWorkflow_Cancel(t *testing.T) {
 	assert.False(t, wfi.GetStatus().Successful())
 }
 
+func TestRuntime_InvokeWorkflow_CallerCanceled(t *testing.T) {
+	for name, pubsub := range map[string]bool{"sub": true, "poll": false} {
+		t.Run(name, func(t *testing.T) {
+			runtime, _, backend, cache := setup()
+			if !pubsub {
+				runtime.invocations = store.NewInvocationStore(testutil.NewCache())
+				runtime.pollInterval = 10 * time.Millisecond
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				// Cancel the caller while the invocation is running
+				time.Sleep(50 * time.Millisecond)
+				cancel()
+			}()
+			_, err := runtime.InvokeWorkflow(types.NewWorkflowInvocationSpec(workflowID, defaultDeadline()),
+				fnenv.WithContext(ctx))
+			assert.Equal(t, context.Canceled, err)
+
+			// The invocation should not be left orphaned.
+			entities := cache.List()
+			assert.Len(t, entities, 1)
+			invocationEvents, err := backend.Get(projectors.NewInvocationAggregate(entities[0].Id))
+			assert.NoError(t, err)
+			entity, err := projectors.NewWorkflowInvocation().Project(nil, invocationEvents...)
+			assert.NoError(t, err)
+			wfi := entity.(*types.WorkflowInvocation)
+			assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, wfi.GetStatus().GetStatus())
+			assert.Equal(t, api.ErrInvocationCallerCanceled+": "+context.Canceled.Error(),
+				wfi.GetStatus().GetError().Error())
+		})
+	}
+}
+
 func TestRuntime_Invoke(t *testing.T) {
 	runtime, invocationAPI, _, cache := setup()
 
@@ -185,6 +297,98 @@ func TestRuntime_Invoke(t *testing.T) {
 	util.AssertProtoEqual(t, outputHeaders, task.GetOutputHeaders())
 }
 
+func TestRuntime_Invoke_Contract(t *testing.T) {
+	runtime, invocationAPI, _, cache := setup()
+	workflows := testutil.NewCache()
+	err := workflows.Put(&types.Workflow{
+		Metadata: &types.ObjectMetadata{
+			Id: workflowID,
+		},
+		Spec: &types.WorkflowSpec{
+			Contract: &types.WorkflowContract{
+				Inputs: map[string]*types.ContractField{
+					"name":  {Type: types.ContractTypeString},
+					"count": {Type: types.ContractTypeNumber, Optional: true},
+				},
+				Outputs: map[string]*types.ContractField{
+					"greeting": {Type: types.ContractTypeString},
+				},
+			},
+		},
+		Status: &types.WorkflowStatus{
+			Status: types.WorkflowStatus_READY,
+		},
+	})
+	assert.NoError(t, err)
+	runtime.workflows = store.NewWorkflowsStore(workflows)
+
+	deadline, _ := ptypes.TimestampProto(time.Now().Add(10 * time.Second))
+	fnref := types.NewFnRef("workflows", "", workflowID)
+	newSpec := func(inputs types.Inputs) *types.TaskInvocationSpec {
+		spec := types.NewTaskInvocationSpec(&types.WorkflowInvocation{
+			Metadata: types.NewObjectMetadata("wi-123"),
+			Spec: &types.WorkflowInvocationSpec{
+				Deadline: deadline,
+			},
+		}, &types.Task{
+			Metadata: types.NewObjectMetadata("ti-123"),
+			Spec:     &types.TaskSpec{},
+			Status: &types.TaskStatus{
+				FnRef: &fnref,
+			},
+		}, time.Now())
+		spec.Inputs = inputs
+		return spec
+	}
+
+	// Mismatching inputs fail the invocation before the sub-workflow is invoked.
+	_, err = runtime.Invoke(newSpec(types.Inputs{
+		"count": typedvalues.MustWrap("many"),
+	}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required input 'name'")
+	assert.Contains(t, err.Error(), "input 'count' should be of type number, but was string")
+	assert.Empty(t, cache.List())
+
+	// An output that does not satisfy the contract fails the task as well.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		entities := cache.List()
+		err := invocationAPI.Complete(entities[0].Id, typedvalues.MustWrap("hello"), nil)
+		if err != nil {
+			panic(err)
+		}
+	}()
+	_, err = runtime.Invoke(newSpec(types.Inputs{
+		"name": typedvalues.MustWrap("alice"),
+	}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output should be of type map, but was string")
+}
+
+func TestToWorkflowSpec_ParentPriority(t *testing.T) {
+	spec, err := toWorkflowSpec(&types.TaskInvocationSpec{
+		FnRef:    &types.FnRef{ID: workflowID},
+		TaskId:   "t1",
+		Priority: types.PriorityHigh,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "t1", spec.GetParentTaskId())
+	assert.Equal(t, types.PriorityHigh, spec.GetParentPriority())
+	assert.Equal(t, types.PriorityHigh, spec.EffectivePriority())
+}
+
+// countingCache counts the number of times that an aggregate was retrieved from the cache.
+type countingCache struct {
+	*testutil.Cache
+	count int
+}
+
+func (c *countingCache) GetAggregate(a fes.Aggregate) (fes.Entity, error) {
+	c.count++
+	return c.Cache.GetAggregate(a)
+}
+
 func setup() (*Runtime, *api.Invocation, *mem.Backend, fes.CacheReaderWriter) {
 	backend := mem.NewBackend()
 	invocationAPI := api.NewInvocationAPI(backend)
@@ -2,18 +2,28 @@ package yaml
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/util/mediatype"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 )
 
-const defaultFunctionRef = builtin.Noop
+const (
+	defaultFunctionRef = builtin.Noop
+
+	// The join modes of a task: whether it awaits all of its dependencies, or just the first one to succeed.
+	joinAll = "all"
+	joinAny = "any"
+)
 
 var DefaultParser = &Parser{}
 
@@ -67,17 +77,123 @@ func parseWorkflow(def *workflowSpec) (*types.WorkflowSpec, error) {
 		tasks[id] = p
 	}
 
-	return &types.WorkflowSpec{
-		ApiVersion: def.APIVersion,
-		OutputTask: def.Output,
-		Tasks:      tasks,
-	}, nil
+	spec := &types.WorkflowSpec{
+		ApiVersion:            def.APIVersion,
+		OutputTask:            def.Output,
+		Prewarm:               def.Prewarm,
+		SuccessCondition:      def.SuccessCondition,
+		CancelAbandonedTasks:  def.CancelAbandonedTasks,
+		Completion:            parseCompletionPolicy(def.Completion),
+		Concurrency:           parseConcurrencyPolicy(def.Concurrency),
+		RetryBudget:           def.RetryBudget,
+		SoftTimeoutPercentage: def.SoftTimeoutPercentage,
+		Switches:              parseSwitches(def.Switches),
+		Contract:              parseContract(def.Contract),
+		InputMiddleware:       def.InputMiddleware,
+		ScopeStrictness:       def.ScopeStrictness,
+		MaxParallelism:        def.MaxParallelism,
+		Tasks:                 tasks,
+	}
+	if len(def.Timeout) > 0 {
+		timeout, err := time.ParseDuration(def.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout '%v': %v", def.Timeout, err)
+		}
+		spec.Timeout = ptypes.DurationProto(timeout)
+	}
+	return spec, nil
+}
+
+func parseContract(c *contract) *types.WorkflowContract {
+	if c == nil {
+		return nil
+	}
+	return &types.WorkflowContract{
+		Inputs:  parseContractFields(c.Inputs),
+		Outputs: parseContractFields(c.Outputs),
+	}
+}
+
+func parseContractFields(fields map[string]*contractField) map[string]*types.ContractField {
+	if len(fields) == 0 {
+		return nil
+	}
+	result := make(map[string]*types.ContractField, len(fields))
+	for name, field := range fields {
+		if field == nil {
+			field = &contractField{}
+		}
+		result[name] = &types.ContractField{
+			Type:        field.Type,
+			Optional:    field.Optional,
+			Description: field.Description,
+		}
+	}
+	return result
+}
+
+func parseSwitches(switches map[string]*switchSpec) map[string]*types.Switch {
+	if len(switches) == 0 {
+		return nil
+	}
+	result := make(map[string]*types.Switch, len(switches))
+	for id, sw := range switches {
+		if sw == nil {
+			continue
+		}
+		branches := make(map[string]*types.Branch, len(sw.Branches))
+		for name, tasks := range sw.Branches {
+			branches[name] = &types.Branch{Tasks: tasks}
+		}
+		var defaultBranch *types.Branch
+		if sw.Default != nil {
+			defaultBranch = &types.Branch{Tasks: sw.Default}
+		}
+		result[id] = &types.Switch{
+			Expression: sw.Expression,
+			Requires:   sw.Requires,
+			Branches:   branches,
+			Default:    defaultBranch,
+		}
+	}
+	return result
+}
+
+func parseCompletionPolicy(p *completionPolicy) *types.CompletionPolicy {
+	if p == nil {
+		return nil
+	}
+	return &types.CompletionPolicy{
+		Mode:   p.Mode,
+		Tasks:  p.Tasks,
+		Quorum: p.Quorum,
+	}
+}
+
+func parseConcurrencyPolicy(p *concurrencyPolicy) *types.ConcurrencyPolicy {
+	if p == nil {
+		return nil
+	}
+	return &types.ConcurrencyPolicy{
+		Key:        p.Key,
+		OnConflict: p.OnConflict,
+	}
 }
 
 func parseTask(t *taskSpec) (*types.TaskSpec, error) {
 	deps := map[string]*types.TaskDependencyParameters{}
 	for _, dep := range t.Requires {
-		deps[dep] = &types.TaskDependencyParameters{}
+		params := &types.TaskDependencyParameters{
+			Alias: dep.Alias,
+		}
+		if dep.Transform != nil {
+			transform, err := parseInput(dep.Transform)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse transform of dependency %v: %v", dep.Task, err)
+			}
+			params.Transform = transform
+		}
+		deps[dep.Task] = params
 	}
 
 	inputs, err := parseInputs(t.Inputs)
@@ -85,21 +201,166 @@ func parseTask(t *taskSpec) (*types.TaskSpec, error) {
 		return nil, err
 	}
 
+	await := int32(len(deps))
+	switch t.Join {
+	case "", joinAll:
+	case joinAny:
+		await = 1
+	default:
+		return nil, fmt.Errorf("unknown join mode '%v' (expected '%v' or '%v')", t.Join, joinAll, joinAny)
+	}
+
 	fn := t.Run
 	if len(fn) == 0 {
 		fn = defaultFunctionRef
 	}
 
 	result := &types.TaskSpec{
-		FunctionRef: fn,
-		Requires:    deps,
-		Await:       int32(len(deps)),
-		Inputs:      inputs,
+		FunctionRef:     fn,
+		Requires:        deps,
+		Await:           await,
+		Inputs:          inputs,
+		ExecutorType:    t.ExecutorType,
+		OutputPath:      t.OutputPath,
+		Affinity:        t.Affinity,
+		Idempotent:      t.Idempotent,
+		Pure:            t.Pure,
+		InputReferences: t.InputReferences,
+
+		FailedReferencePolicy:   t.FailedReferencePolicy,
+		FailedReferencePolicies: t.FailedReferencePolicies,
+		CancelPropagation:       t.CancelPropagation,
+	}
+	if len(t.ContentType) > 0 {
+		if _, err := mediatype.Parse(t.ContentType); err != nil {
+			return nil, fmt.Errorf("invalid content type '%v': %v", t.ContentType, err)
+		}
+		result.ContentType = t.ContentType
+	}
+	if len(t.Timeout) > 0 {
+		timeout, err := time.ParseDuration(t.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout '%v': %v", t.Timeout, err)
+		}
+		result.Timeout = ptypes.DurationProto(timeout)
+	}
+	if t.Retry != nil {
+		result.Retry = &types.RetryPolicy{
+			MaxAttempts:       t.Retry.MaxAttempts,
+			BackoffMultiplier: t.Retry.BackoffMultiplier,
+		}
+		if len(t.Retry.TotalTimeout) > 0 {
+			totalTimeout, err := time.ParseDuration(t.Retry.TotalTimeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid total timeout '%v' of retry policy: %v", t.Retry.TotalTimeout, err)
+			}
+			result.Retry.TotalTimeout = ptypes.DurationProto(totalTimeout)
+		}
+		if len(t.Retry.InitialDelay) > 0 {
+			initialDelay, err := time.ParseDuration(t.Retry.InitialDelay)
+			if err != nil {
+				return nil, fmt.Errorf("invalid initial delay '%v' of retry policy: %v", t.Retry.InitialDelay, err)
+			}
+			result.Retry.InitialDelay = ptypes.DurationProto(initialDelay)
+		}
+		if len(t.Retry.MaxDelay) > 0 {
+			maxDelay, err := time.ParseDuration(t.Retry.MaxDelay)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max delay '%v' of retry policy: %v", t.Retry.MaxDelay, err)
+			}
+			result.Retry.MaxDelay = ptypes.DurationProto(maxDelay)
+		}
+	}
+	if t.Failover != nil {
+		result.Failover = &types.Failover{
+			FunctionRef: t.Failover.Run,
+			After:       t.Failover.After,
+		}
+	}
+	if t.RateLimit != nil {
+		window, err := time.ParseDuration(t.RateLimit.Window)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window '%v' of rate limit: %v", t.RateLimit.Window, err)
+		}
+		result.RateLimit = &types.RateLimit{
+			Executions: t.RateLimit.Executions,
+			Window:     ptypes.DurationProto(window),
+			Key:        t.RateLimit.Key,
+		}
+	}
+	if t.SLO != nil {
+		slo, err := parseSLO(t.SLO)
+		if err != nil {
+			return nil, err
+		}
+		result.Slo = slo
+	}
+	for inputKey, merge := range t.MergeInputs {
+		if merge == nil || len(merge.Sources) == 0 {
+			return nil, fmt.Errorf("merge of input %v has no sources", inputKey)
+		}
+		sources := make([]*typedvalues.TypedValue, len(merge.Sources))
+		for i, src := range merge.Sources {
+			source, err := parseInput(src)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse source %d of the merge of input %v: %v", i, inputKey, err)
+			}
+			sources[i] = source
+		}
+		if result.MergeInputs == nil {
+			result.MergeInputs = map[string]*types.InputMerge{}
+		}
+		result.MergeInputs[inputKey] = &types.InputMerge{
+			Sources:   sources,
+			Conflicts: merge.Conflicts,
+		}
+	}
+	for _, rule := range t.Redact {
+		if len(rule.Path) == 0 {
+			return nil, errors.New("redaction rule is missing a path")
+		}
+		result.Redact = append(result.Redact, &types.RedactionRule{
+			Path:  rule.Path,
+			Strip: rule.Strip,
+		})
 	}
 
 	return result, nil
 }
 
+// parseSLO parses the service level objectives of a task, of which the percentile and the success rate are
+// percentages.
+func parseSLO(s *slo) (*types.TaskSLO, error) {
+	if len(s.Latency) == 0 && s.SuccessRate == 0 {
+		return nil, errors.New("SLO has neither a latency nor a success rate objective")
+	}
+	if s.Percentile < 0 || s.Percentile >= 100 {
+		return nil, fmt.Errorf("invalid percentile '%v' of SLO: not between 0 and 100", s.Percentile)
+	}
+	if s.SuccessRate < 0 || s.SuccessRate > 100 {
+		return nil, fmt.Errorf("invalid success rate '%v' of SLO: not between 0 and 100", s.SuccessRate)
+	}
+	result := &types.TaskSLO{
+		Percentile:  s.Percentile,
+		SuccessRate: s.SuccessRate,
+	}
+	if len(s.Latency) > 0 {
+		latency, err := time.ParseDuration(s.Latency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency '%v' of SLO: %v", s.Latency, err)
+		}
+		result.Latency = ptypes.DurationProto(latency)
+	}
+	if len(s.Window) > 0 {
+		window, err := time.ParseDuration(s.Window)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid window '%v' of SLO", s.Window)
+		}
+		result.Window = ptypes.DurationProto(window)
+	}
+	return result, nil
+}
+
 // parseInputs parses the inputs of a task. This is typically a map[interface{}]interface{}.
 func parseInputs(i interface{}) (map[string]*typedvalues.TypedValue, error) {
 	if i == nil {
@@ -232,12 +493,173 @@ type workflowSpec struct {
 	APIVersion  string
 	Description string
 	Output      string
+	Prewarm     bool
 	Tasks       map[string]*taskSpec
+
+	SuccessCondition      string `yaml:"successCondition"`
+	CancelAbandonedTasks  bool   `yaml:"cancelAbandonedTasks"`
+	Completion            *completionPolicy
+	Concurrency           *concurrencyPolicy
+	RetryBudget           int32 `yaml:"retryBudget"`
+	SoftTimeoutPercentage int32 `yaml:"softTimeoutPercentage"`
+	Switches              map[string]*switchSpec
+	Contract              *contract
+	InputMiddleware       []string `yaml:"inputMiddleware"`
+	ScopeStrictness       string   `yaml:"scopeStrictness"`
+	MaxParallelism        int32    `yaml:"maxParallelism"`
+	Timeout               string
+}
+
+// contract declares the inputs that the workflow expects and the output fields that it guarantees. A field without
+// any properties (e.g. 'name: {}' or 'name:') is a required field of any type.
+type contract struct {
+	Inputs  map[string]*contractField
+	Outputs map[string]*contractField
+}
+
+type contractField struct {
+	Type        string
+	Optional    bool
+	Description string
+}
+
+// switchSpec selects one of the named branches, each a list of task IDs, by the value of the expression.
+type switchSpec struct {
+	Expression string
+	Requires   []string
+	Branches   map[string][]string
+	Default    []string
+}
+
+type concurrencyPolicy struct {
+	Key        string
+	OnConflict string `yaml:"onConflict"`
+}
+
+type completionPolicy struct {
+	Mode   string
+	Tasks  []string
+	Quorum int32
 }
 
 type taskSpec struct {
-	ID       string
-	Run      string
-	Inputs   interface{}
-	Requires []string
+	ID              string
+	Run             string
+	Inputs          interface{}
+	Requires        []dependency
+	ExecutorType    string `yaml:"executorType"`
+	OutputPath      string `yaml:"outputPath"`
+	Affinity        string
+	Idempotent      bool
+	Pure            bool
+	InputReferences bool `yaml:"inputReferences"`
+	Timeout         string
+	ContentType     string `yaml:"contentType"`
+	Redact          []redactionRule
+	Retry           *retryPolicy
+	Failover        *failover
+	RateLimit       *rateLimit `yaml:"rateLimit"`
+	SLO             *slo       `yaml:"slo"`
+	Join            string
+	MergeInputs     map[string]*inputMerge `yaml:"mergeInputs"`
+
+	FailedReferencePolicy   string            `yaml:"failedReferencePolicy"`
+	FailedReferencePolicies map[string]string `yaml:"failedReferencePolicies"`
+	CancelPropagation       string            `yaml:"cancelPropagation"`
+}
+
+type retryPolicy struct {
+	MaxAttempts       int32   `yaml:"maxAttempts"`
+	TotalTimeout      string  `yaml:"totalTimeout"`
+	InitialDelay      string  `yaml:"initialDelay"`
+	BackoffMultiplier float64 `yaml:"backoffMultiplier"`
+	MaxDelay          string  `yaml:"maxDelay"`
+}
+
+type failover struct {
+	Run   string
+	After int32
+}
+
+type rateLimit struct {
+	Executions int32
+	Window     string
+	Key        string
+}
+
+type slo struct {
+	Latency     string
+	Percentile  float64
+	SuccessRate float64 `yaml:"successRate"`
+	Window      string
+}
+
+// dependency is either the ID of the task that is required, or a map containing the ID of the task along with the
+// parameters of the dependency.
+type dependency struct {
+	Task      string
+	Alias     string
+	Transform interface{}
+}
+
+type dependencyParams dependency
+
+func (d *dependency) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&d.Task); err == nil {
+		return nil
+	}
+	return unmarshal((*dependencyParams)(d))
+}
+
+func (d *dependency) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &d.Task); err == nil {
+		return nil
+	}
+	return json.Unmarshal(data, (*dependencyParams)(d))
+}
+
+// inputMerge is either the list of sources to merge, or a map containing the sources along with the conflict
+// resolution.
+type inputMerge struct {
+	Sources   []interface{}
+	Conflicts string
+}
+
+type inputMergeParams inputMerge
+
+func (m *inputMerge) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&m.Sources); err == nil {
+		return nil
+	}
+	return unmarshal((*inputMergeParams)(m))
+}
+
+func (m *inputMerge) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.Sources); err == nil {
+		return nil
+	}
+	return json.Unmarshal(data, (*inputMergeParams)(m))
+}
+
+// redactionRule is either the path of the output field to mask, or a map containing the path along with whether the
+// field should be stripped.
+type redactionRule struct {
+	Path  string
+	Strip bool
+}
+
+type redactionRuleParams redactionRule
+
+func (r *redactionRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&r.Path); err == nil {
+		return nil
+	}
+	return unmarshal((*redactionRuleParams)(r))
+}
+
+func (r *redactionRule) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &r.Path); err == nil {
+		return nil
+	}
+	return json.Unmarshal(data, (*redactionRuleParams)(r))
 }
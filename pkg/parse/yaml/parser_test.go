@@ -6,6 +6,7 @@ import (
 
 	"fmt"
 
+	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
 	"github.com/stretchr/testify/assert"
@@ -161,3 +162,351 @@ tasks:
 	assert.NoError(t, err)
 	assert.NotNil(t, wf)
 }
+
+func TestParseWorkflowWithExecutorType(t *testing.T) {
+
+	data := `
+tasks:
+  pinned:
+    run: bla
+    executorType: poolmgr
+  unpinned:
+    run: bla
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "poolmgr", wf.Tasks["pinned"].ExecutorType)
+	assert.Empty(t, wf.Tasks["unpinned"].ExecutorType)
+}
+
+func TestParseWorkflowWithDependencyTransforms(t *testing.T) {
+
+	data := `
+tasks:
+  fetch:
+    run: bla
+  plain:
+    run: bla
+  greet:
+    run: bla
+    requires:
+    - plain
+    - task: fetch
+      alias: name
+      transform: "{ output().user.name }"
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	requires := wf.Tasks["greet"].Requires
+	assert.Len(t, requires, 2)
+	assert.Nil(t, requires["plain"].GetTransform())
+	assert.Equal(t, "name", requires["fetch"].GetAlias())
+	assert.Equal(t, typedvalues.TypeExpression, requires["fetch"].GetTransform().ValueType())
+	assert.EqualValues(t, 2, wf.Tasks["greet"].Await)
+}
+
+func TestParseWorkflowWithJoin(t *testing.T) {
+	data := `
+tasks:
+  mirrorA:
+    run: bla
+  mirrorB:
+    run: bla
+  first:
+    run: bla
+    join: any
+    requires:
+    - mirrorA
+    - mirrorB
+  all:
+    run: bla
+    join: all
+    requires:
+    - mirrorA
+    - mirrorB
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, wf.Tasks["first"].Await)
+	assert.True(t, wf.Tasks["first"].IsPartialJoin())
+	assert.EqualValues(t, 2, wf.Tasks["all"].Await)
+	assert.False(t, wf.Tasks["all"].IsPartialJoin())
+
+	_, err = Parse(strings.NewReader(strings.Replace(data, "join: any", "join: some", 1)))
+	assert.Error(t, err)
+}
+
+func TestParseWorkflowWithTaskTimeout(t *testing.T) {
+	data := `
+tasks:
+  resize:
+    run: bla
+    timeout: 30s
+    retry:
+      maxAttempts: 3
+      totalTimeout: 2m
+      initialDelay: 1s
+      backoffMultiplier: 1.5
+      maxDelay: 10s
+  other:
+    run: bla
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 30, wf.Tasks["resize"].GetTimeout().GetSeconds())
+	assert.EqualValues(t, 120, wf.Tasks["resize"].GetRetry().GetTotalTimeout().GetSeconds())
+	assert.EqualValues(t, 1, wf.Tasks["resize"].GetRetry().GetInitialDelay().GetSeconds())
+	assert.EqualValues(t, 1.5, wf.Tasks["resize"].GetRetry().GetBackoffMultiplier())
+	assert.EqualValues(t, 10, wf.Tasks["resize"].GetRetry().GetMaxDelay().GetSeconds())
+	assert.Nil(t, wf.Tasks["other"].GetTimeout())
+
+	_, err = Parse(strings.NewReader(strings.Replace(data, "30s", "soon", 1)))
+	assert.Error(t, err)
+	_, err = Parse(strings.NewReader(strings.Replace(data, "2m", "later", 1)))
+	assert.Error(t, err)
+	_, err = Parse(strings.NewReader(strings.Replace(data, "10s", "never", 1)))
+	assert.Error(t, err)
+}
+
+func TestParseWorkflowWithTimeout(t *testing.T) {
+	data := `
+timeout: 1h
+tasks:
+  resize:
+    run: bla
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3600, wf.GetTimeout().GetSeconds())
+
+	wf, err = Parse(strings.NewReader(strings.Replace(data, "timeout: 1h", "", 1)))
+	assert.NoError(t, err)
+	assert.Nil(t, wf.GetTimeout())
+
+	_, err = Parse(strings.NewReader(strings.Replace(data, "1h", "eventually", 1)))
+	assert.Error(t, err)
+}
+
+func TestParseWorkflowWithFailover(t *testing.T) {
+	data := `
+tasks:
+  fetch:
+    run: primary
+    retry:
+      maxAttempts: 3
+    failover:
+      run: secondary
+      after: 2
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, &types.Failover{FunctionRef: "secondary", After: 2}, wf.Tasks["fetch"].GetFailover())
+}
+
+func TestParseWorkflowWithRateLimit(t *testing.T) {
+	data := `
+tasks:
+  call:
+    run: api
+    rateLimit:
+      executions: 5
+      window: 1s
+      key: quota
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	rateLimit := wf.Tasks["call"].GetRateLimit()
+	assert.EqualValues(t, 5, rateLimit.GetExecutions())
+	assert.EqualValues(t, 1, rateLimit.GetWindow().GetSeconds())
+	assert.Equal(t, "quota", rateLimit.GetKey())
+
+	_, err = Parse(strings.NewReader(strings.Replace(data, "1s", "often", 1)))
+	assert.Error(t, err)
+}
+
+func TestParseWorkflowWithSLO(t *testing.T) {
+	data := `
+tasks:
+  charge:
+    run: payments
+    slo:
+      latency: 2s
+      percentile: 95
+      successRate: 99
+      window: 10m
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	slo := wf.Tasks["charge"].GetSlo()
+	assert.EqualValues(t, 2, slo.GetLatency().GetSeconds())
+	assert.EqualValues(t, 95, slo.GetPercentile())
+	assert.EqualValues(t, 99, slo.GetSuccessRate())
+	assert.EqualValues(t, 600, slo.GetWindow().GetSeconds())
+
+	_, err = Parse(strings.NewReader(strings.Replace(data, "successRate: 99", "successRate: 101", 1)))
+	assert.Error(t, err)
+	_, err = Parse(strings.NewReader(strings.Replace(data, "2s", "fast", 1)))
+	assert.Error(t, err)
+}
+
+func TestParseWorkflowWithFailedReferencePolicy(t *testing.T) {
+	data := `
+tasks:
+  report:
+    run: bla
+    requires:
+    - fetch
+    - enrich
+    failedReferencePolicy: use-null
+    failedReferencePolicies:
+      fetch: block
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	spec := wf.Tasks["report"]
+	assert.Equal(t, "use-null", spec.GetFailedReferencePolicy())
+	assert.Equal(t, "block", spec.FailedReferencePolicyFor("fetch"))
+	assert.Equal(t, "use-null", spec.FailedReferencePolicyFor("enrich"))
+}
+
+func TestParseWorkflowWithMergeInputs(t *testing.T) {
+	data := `
+tasks:
+  report:
+    run: bla
+    requires:
+    - defaults
+    - overrides
+    mergeInputs:
+      default:
+      - "{ output('defaults') }"
+      - "{ output('overrides') }"
+      headers:
+        sources:
+        - accept: json
+        - "{ output('overrides').headers }"
+        conflicts: error
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	merges := wf.Tasks["report"].GetMergeInputs()
+	assert.Len(t, merges, 2)
+	assert.Len(t, merges["default"].GetSources(), 2)
+	assert.Equal(t, "", merges["default"].GetConflicts())
+	assert.Equal(t, typedvalues.MustWrap(map[string]interface{}{"accept": "json"}),
+		merges["headers"].GetSources()[0])
+	assert.Equal(t, types.MergeConflictError, merges["headers"].GetConflicts())
+
+	_, err = Parse(strings.NewReader(`
+tasks:
+  report:
+    run: bla
+    mergeInputs:
+      default: []
+`))
+	assert.Error(t, err)
+}
+
+func TestParseWorkflowWithContentType(t *testing.T) {
+	data := `
+tasks:
+  report:
+    run: bla
+    contentType: text/csv
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "text/csv", wf.Tasks["report"].ContentType)
+
+	_, err = Parse(strings.NewReader(strings.Replace(data, "text/csv", "text/csv;;", 1)))
+	assert.Error(t, err)
+}
+
+func TestParseWorkflowWithSwitch(t *testing.T) {
+	data := `
+output: merge
+tasks:
+  classify:
+    run: bla
+  small:
+    run: bla
+    requires:
+    - classify
+  large:
+    run: bla
+    requires:
+    - classify
+  unknown:
+    run: bla
+  merge:
+    run: bla
+    requires:
+    - small
+    - large
+    - unknown
+switches:
+  route:
+    expression: "{ $.Tasks.classify.Output }"
+    requires:
+    - classify
+    branches:
+      small:
+      - small
+      large:
+      - large
+    default:
+    - unknown
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	sw := wf.Switches["route"]
+	assert.NotNil(t, sw)
+	assert.Equal(t, "{ $.Tasks.classify.Output }", sw.Expression)
+	assert.Equal(t, []string{"classify"}, sw.Requires)
+	assert.Equal(t, []string{"small"}, sw.Branches["small"].Tasks)
+	assert.Equal(t, []string{"large"}, sw.Branches["large"].Tasks)
+	assert.Equal(t, []string{"unknown"}, sw.Default.Tasks)
+}
+
+func TestParseWorkflowWithContract(t *testing.T) {
+	data := `
+output: greet
+contract:
+  inputs:
+    name:
+      type: string
+      description: The name of the person to greet
+    count:
+      type: number
+      optional: true
+    anything:
+  outputs:
+    greeting:
+      type: string
+tasks:
+  greet:
+    run: noop
+`
+
+	wf, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	contract := wf.GetContract()
+	assert.NotNil(t, contract)
+	assert.Equal(t, &types.ContractField{Type: "string", Description: "The name of the person to greet"},
+		contract.Inputs["name"])
+	assert.Equal(t, &types.ContractField{Type: "number", Optional: true}, contract.Inputs["count"])
+	assert.Equal(t, &types.ContractField{}, contract.Inputs["anything"])
+	assert.Equal(t, &types.ContractField{Type: "string"}, contract.Outputs["greeting"])
+}
@@ -0,0 +1,97 @@
+// Package redact removes sensitive fields, such as personally identifiable information, from the outputs of tasks
+// before the outputs are stored in the event store or logged.
+//
+// The fields are configured per task with redaction rules, which either mask the value of a field or strip the field
+// from the output. Optionally, the original values are kept encrypted in a Vault, from which authorized callers can
+// retrieve them.
+package redact
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+// Mask is the value that masked fields are replaced with.
+const Mask = "[REDACTED]"
+
+// Apply redacts the fields of the output that are matched by the rules. It returns the redacted output, along with
+// the original values of the redacted fields by their path. Rules of which the field is not present in the output are
+// ignored. An error is returned if the output cannot be traversed, in which case the output should not be stored.
+func Apply(output *typedvalues.TypedValue, rules []*types.RedactionRule) (redacted *typedvalues.TypedValue,
+	originals map[string]interface{}, err error) {
+	if len(rules) == 0 || output == nil {
+		return output, nil, nil
+	}
+	val, err := typedvalues.Unwrap(output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	originals = map[string]interface{}{}
+	for _, rule := range rules {
+		original, ok, err := redactPath(val, strings.Split(rule.GetPath(), "."), rule.GetStrip())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to redact '%s': %v", rule.GetPath(), err)
+		}
+		if ok {
+			originals[rule.GetPath()] = original
+		}
+	}
+
+	redacted, err = typedvalues.Wrap(val)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Retain the metadata (e.g. the content type) of the original output.
+	redacted.Metadata = output.Metadata
+	return redacted, originals, nil
+}
+
+// redactPath redacts the field at the path within the value in place, returning the original value of the field.
+func redactPath(val interface{}, path []string, strip bool) (original interface{}, found bool, err error) {
+	key := path[0]
+	switch container := val.(type) {
+	case map[string]interface{}:
+		field, ok := container[key]
+		if !ok {
+			return nil, false, nil
+		}
+		if len(path) > 1 {
+			return redactPath(field, path[1:], strip)
+		}
+		if strip {
+			delete(container, key)
+		} else {
+			container[key] = Mask
+		}
+		return field, true, nil
+	case []interface{}:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			return nil, false, fmt.Errorf("invalid index '%s'", key)
+		}
+		if i >= len(container) {
+			return nil, false, nil
+		}
+		if len(path) > 1 {
+			return redactPath(container[i], path[1:], strip)
+		}
+		// Removing the element would shift the indices of the others, so stripped elements are nulled instead.
+		field := container[i]
+		if strip {
+			container[i] = nil
+		} else {
+			container[i] = Mask
+		}
+		return field, true, nil
+	case nil:
+		return nil, false, nil
+	default:
+		// The value cannot be inspected (e.g. unparsed bytes), so it is not possible to guarantee the redaction.
+		return nil, false, fmt.Errorf("cannot index %T with '%s'", val, key)
+	}
+}
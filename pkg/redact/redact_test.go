@@ -0,0 +1,75 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	output := typedvalues.MustWrap(map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":  "Jane",
+			"ssn":   "123-45-6789",
+			"email": "jane@example.com",
+		},
+		"cards": []interface{}{"4111111111111111"},
+	})
+	redacted, originals, err := Apply(output, []*types.RedactionRule{
+		{Path: "user.ssn"},
+		{Path: "user.email", Strip: true},
+		{Path: "cards.0"},
+		{Path: "user.phone"}, // missing fields are ignored
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{
+			"name": "Jane",
+			"ssn":  Mask,
+		},
+		"cards": []interface{}{Mask},
+	}, typedvalues.MustUnwrap(redacted))
+	assert.Equal(t, map[string]interface{}{
+		"user.ssn":   "123-45-6789",
+		"user.email": "jane@example.com",
+		"cards.0":    "4111111111111111",
+	}, originals)
+
+	// The original output is not modified.
+	assert.Equal(t, "123-45-6789",
+		typedvalues.MustUnwrap(output).(map[string]interface{})["user"].(map[string]interface{})["ssn"])
+}
+
+func TestApply_Unindexable(t *testing.T) {
+	// An output that cannot be inspected cannot be redacted.
+	_, _, err := Apply(typedvalues.MustWrap("ssn: 123-45-6789"), []*types.RedactionRule{{Path: "ssn"}})
+	assert.Error(t, err)
+}
+
+func TestVault(t *testing.T) {
+	vault, err := NewVault([]byte("0123456789abcdef0123456789abcdef"), NewMemoryStore(0))
+	assert.NoError(t, err)
+	assert.NoError(t, vault.Store("wi-1", "task", map[string]interface{}{"user.ssn": "123-45-6789"}))
+
+	values, ok, err := vault.Retrieve("wi-1", "task")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"user.ssn": "123-45-6789"}, values)
+
+	_, ok, err = vault.Retrieve("wi-1", "other")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// The values are bound to the key of the task; they cannot be decrypted for another task.
+	store := NewMemoryStore(0)
+	vault, err = NewVault([]byte("0123456789abcdef"), store)
+	assert.NoError(t, err)
+	assert.NoError(t, vault.Store("wi-1", "task", map[string]interface{}{"user.ssn": "123-45-6789"}))
+	ciphertext, _, _ := store.Get("wi-1/task")
+	assert.NotContains(t, string(ciphertext), "123-45-6789")
+	assert.NoError(t, store.Put("wi-1/other", ciphertext))
+	_, _, err = vault.Retrieve("wi-1", "other")
+	assert.Equal(t, ErrCorrupted, err)
+}
@@ -0,0 +1,120 @@
+package redact
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/golang-lru"
+)
+
+// DefaultMaxEntries is the default number of tasks of which the MemoryStore retains the redacted values.
+const DefaultMaxEntries = 10000
+
+var ErrCorrupted = errors.New("redacted values could not be decrypted")
+
+// SideStore stores the encrypted values of redacted fields outside of the event store.
+type SideStore interface {
+	Put(key string, ciphertext []byte) error
+	Get(key string) (ciphertext []byte, ok bool, err error)
+}
+
+// MemoryStore is a SideStore that retains the values of a limited number of tasks in memory. When full, the values
+// of the least recently used tasks are evicted. The values do not survive a restart of the workflow engine.
+type MemoryStore struct {
+	entries *lru.Cache
+}
+
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	entries, err := lru.New(maxEntries)
+	if err != nil {
+		panic(err)
+	}
+	return &MemoryStore{entries: entries}
+}
+
+func (s *MemoryStore) Put(key string, ciphertext []byte) error {
+	s.entries.Add(key, ciphertext)
+	return nil
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, bool, error) {
+	ciphertext, ok := s.entries.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return ciphertext.([]byte), true, nil
+}
+
+// Vault keeps the original values of redacted fields encrypted (AES-GCM) in a side store, allowing authorized callers
+// to retrieve them.
+type Vault struct {
+	aead  cipher.AEAD
+	store SideStore
+}
+
+// NewVault creates a vault that encrypts the values with the key, which should be 16, 24 or 32 bytes long to select
+// AES-128, AES-192 or AES-256.
+func NewVault(key []byte, store SideStore) (*Vault, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{
+		aead:  aead,
+		store: store,
+	}, nil
+}
+
+// Store encrypts and stores the original values of the redacted fields of a task, keyed by their path.
+func (v *Vault) Store(invocationID string, taskID string, values map[string]interface{}) error {
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	key := vaultKey(invocationID, taskID)
+	// The key is used as additional data, which prevents the values from being retrieved for another task.
+	ciphertext := v.aead.Seal(nonce, nonce, plaintext, []byte(key))
+	return v.store.Put(key, ciphertext)
+}
+
+// Retrieve returns the original values of the redacted fields of a task, keyed by their path.
+func (v *Vault) Retrieve(invocationID string, taskID string) (map[string]interface{}, bool, error) {
+	key := vaultKey(invocationID, taskID)
+	ciphertext, ok, err := v.store.Get(key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if len(ciphertext) < v.aead.NonceSize() {
+		return nil, false, ErrCorrupted
+	}
+	nonce, ciphertext := ciphertext[:v.aead.NonceSize()], ciphertext[v.aead.NonceSize():]
+	plaintext, err := v.aead.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return nil, false, ErrCorrupted
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, false, fmt.Errorf("%v: %v", ErrCorrupted, err)
+	}
+	return values, true, nil
+}
+
+func vaultKey(invocationID string, taskID string) string {
+	return invocationID + "/" + taskID
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+// taskReferences returns the tasks that the task references, with the value indicating whether the task is
+// referenced by an expression in its inputs, in the sources of its input merges or in the transforms of its
+// dependencies, rather than only as a dependency.
+func taskReferences(task *types.Task) map[string]bool {
+	refs := map[string]bool{}
+	var values []*typedvalues.TypedValue
+	for depID, dep := range task.GetSpec().GetRequires() {
+		refs[depID] = false
+		if dep.GetTransform() != nil {
+			values = append(values, dep.GetTransform())
+		}
+	}
+	for _, input := range task.GetSpec().GetInputs() {
+		values = append(values, input)
+	}
+	values = append(values, task.GetSpec().MergeSources()...)
+	for _, ref := range expr.TaskReferences(values...) {
+		if ref != task.ID() {
+			refs[ref] = true
+		}
+	}
+	return refs
+}
+
+// blockingReferences returns the failed tasks that the task references with the block policy, sorted by their ID.
+//
+// Once the partial join of the task has been triggered, the failed dependencies that are not referenced by an
+// expression no longer block the task, as the join no longer waits for them.
+func blockingReferences(invocation *types.WorkflowInvocation, taskID string) []string {
+	task, ok := invocation.Task(taskID)
+	if !ok {
+		return nil
+	}
+	_, joined := invocation.JoinTrigger(taskID)
+	var blocking []string
+	for ref, inExpr := range taskReferences(task) {
+		if task.GetSpec().FailedReferencePolicyFor(ref) != types.FailedReferenceBlock || (joined && !inExpr) {
+			continue
+		}
+		if taskRun, ok := invocation.TaskInvocation(ref); ok &&
+			taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED {
+			blocking = append(blocking, ref)
+		}
+	}
+	sort.Strings(blocking)
+	return blocking
+}
+
+// failureHandled returns whether the failure of the task is handled by the failed reference policies of the tasks
+// that reference it, in which case the failure does not fail the invocation.
+//
+// This is the case if the task is referenced by at least one other task, and none of these tasks propagate the
+// failure. With the use-null policy, the references resolve to null. With the block policy, the tasks that have not
+// started yet are held back; the failure is only handled as long as the invocation can make progress otherwise, as
+// the blocked tasks would wait forever.
+func failureHandled(invocation *types.WorkflowInvocation, failedTaskID string) bool {
+	var dependents, blocked int
+	for taskID, task := range invocation.Tasks() {
+		if taskID == failedTaskID {
+			continue
+		}
+		if _, ok := taskReferences(task)[failedTaskID]; !ok {
+			continue
+		}
+		dependents++
+		switch task.GetSpec().FailedReferencePolicyFor(failedTaskID) {
+		case types.FailedReferenceUseNull:
+		case types.FailedReferenceBlock:
+			if taskRun, ok := invocation.TaskInvocation(taskID); !ok ||
+				taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_UNKNOWN {
+				blocked++
+			}
+		default:
+			return false
+		}
+	}
+	if dependents == 0 {
+		return false
+	}
+	return blocked == 0 || progressing(invocation)
+}
+
+// progressing returns whether the invocation has tasks in progress, or tasks that can be scheduled.
+func progressing(invocation *types.WorkflowInvocation) bool {
+	for _, taskRun := range invocation.TaskInvocations() {
+		if taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_IN_PROGRESS {
+			return true
+		}
+	}
+	return len(schedulingHorizon(invocation, getOpenTasks(invocation))) > 0
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupFailedReferenceInvocation creates an invocation of a workflow in which report depends on and references the
+// output of fetch, which has failed, while the unrelated backup task is still in progress.
+func setupFailedReferenceInvocation(policy string, policies map[string]string) *types.WorkflowInvocation {
+	invocation := setupInvocation()
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("fetch", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("backup", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("report", &types.TaskSpec{
+		FunctionRef: "noop",
+		Requires:    types.Require("fetch"),
+		Inputs: map[string]*typedvalues.TypedValue{
+			types.InputMain: typedvalues.MustWrap("{ output('fetch') }"),
+		},
+		FailedReferencePolicy:   policy,
+		FailedReferencePolicies: policies,
+	})
+	wfSpec.SetOutput("report")
+	invocation.Spec.Workflow.Spec = wfSpec
+	setTaskRun(invocation, "fetch", types.TaskInvocationStatus_FAILED, 1)
+	setTaskRun(invocation, "backup", types.TaskInvocationStatus_IN_PROGRESS, 1)
+	return invocation
+}
+
+func failedReferencePolicies() map[string]Policy {
+	return map[string]Policy{
+		"horizon":         NewHorizonPolicy(),
+		"prewarm-all":     NewPrewarmAllPolicy(time.Second),
+		"prewarm-horizon": NewPrewarmHorizonPolicy(time.Second),
+		"critical-path":   NewCriticalPathPolicy(NewTaskStats(), DefaultEstimatedTaskDuration),
+	}
+}
+
+func TestPolicies_FailedReferencePropagate(t *testing.T) {
+	for _, policy := range []string{"", types.FailedReferencePropagate} {
+		invocation := setupFailedReferenceInvocation(policy, nil)
+		for name, p := range failedReferencePolicies() {
+			schedule, err := p.Evaluate(invocation)
+			assert.NoError(t, err, name)
+			assert.NotNil(t, schedule.GetAbort(), name)
+		}
+	}
+}
+
+func TestPolicies_FailedReferenceUseNull(t *testing.T) {
+	invocation := setupFailedReferenceInvocation(types.FailedReferenceUseNull, nil)
+	for name, runTasks := range evaluatePolicies(t, invocation, failedReferencePolicies()) {
+		assert.Equal(t, []string{"report"}, runTasks, name)
+	}
+}
+
+func TestPolicies_FailedReferenceBlock(t *testing.T) {
+	// While the invocation can make progress otherwise, report is held back without failing the invocation.
+	invocation := setupFailedReferenceInvocation(types.FailedReferenceBlock, nil)
+	assert.Equal(t, []string{"fetch"}, blockingReferences(invocation, "report"))
+	for name, runTasks := range evaluatePolicies(t, invocation, failedReferencePolicies()) {
+		assert.Empty(t, runTasks, name)
+	}
+
+	// Once nothing else can make progress, report would be blocked forever, so the invocation is aborted.
+	setTaskRun(invocation, "backup", types.TaskInvocationStatus_SUCCEEDED, 2)
+	for name, p := range failedReferencePolicies() {
+		schedule, err := p.Evaluate(invocation)
+		assert.NoError(t, err, name)
+		assert.NotNil(t, schedule.GetAbort(), name)
+	}
+}
+
+func TestPolicies_FailedReferencePerReference(t *testing.T) {
+	// The policy for the reference to fetch overrides the policy of the task.
+	invocation := setupFailedReferenceInvocation(types.FailedReferencePropagate, map[string]string{
+		"fetch": types.FailedReferenceUseNull,
+	})
+	for name, runTasks := range evaluatePolicies(t, invocation, failedReferencePolicies()) {
+		assert.Equal(t, []string{"report"}, runTasks, name)
+	}
+
+	task, _ := invocation.Task("report")
+	assert.Equal(t, types.FailedReferenceUseNull, task.GetSpec().FailedReferencePolicyFor("fetch"))
+	assert.Equal(t, types.FailedReferencePropagate, task.GetSpec().FailedReferencePolicyFor("backup"))
+}
+
+func TestPolicies_FailedReferenceMergeInputs(t *testing.T) {
+	// The failed fetch task is only referenced by the source of an input merge of report.
+	invocation := setupFailedReferenceInvocation(types.FailedReferenceBlock, nil)
+	report := invocation.Spec.Workflow.Spec.Tasks["report"]
+	report.Inputs = nil
+	report.MergeInputs = map[string]*types.InputMerge{
+		types.InputMain: {Sources: []*typedvalues.TypedValue{typedvalues.MustWrap("{ output('fetch') }")}},
+	}
+	assert.Equal(t, []string{"fetch"}, blockingReferences(invocation, "report"))
+	for name, runTasks := range evaluatePolicies(t, invocation, failedReferencePolicies()) {
+		assert.Empty(t, runTasks, name)
+	}
+}
@@ -2,15 +2,22 @@ package scheduler
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/fission/fission-workflows/pkg/controller/expr"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/graph"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/golang/protobuf/ptypes"
+	gonum "gonum.org/v1/gonum/graph"
 )
 
 var DefaultPolicy = NewHorizonPolicy()
 
+// DefaultEstimatedTaskDuration is the duration assumed for tasks without any historical durations.
+const DefaultEstimatedTaskDuration = time.Second
+
 // HorizonPolicy is the default policy of the workflow engine. It solely schedules tasks that are on the scheduling horizon.
 //
 // The scheduling horizon is the set of tasks that only depend on tasks that have already completed.
@@ -39,8 +46,7 @@ func (p *HorizonPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Schedul
 
 	// Find and schedule all tasks on the scheduling horizon
 	openTasks := getOpenTasks(invocation)
-	depGraph := graph.Parse(graph.NewTaskInstanceIterator(openTasks))
-	horizon := graph.Roots(depGraph)
+	horizon := schedulingHorizon(invocation, openTasks)
 	for _, node := range horizon {
 		schedule.AddRunTask(newRunTaskAction(node.(*graph.TaskInvocationNode).Task().ID()))
 	}
@@ -81,8 +87,7 @@ func (p *PrewarmAllPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Sche
 
 	// Find and schedule all tasks on the scheduling horizon
 	openTasks := getOpenTasks(invocation)
-	depGraph := graph.Parse(graph.NewTaskInstanceIterator(openTasks))
-	horizon := graph.Roots(depGraph)
+	horizon := schedulingHorizon(invocation, openTasks)
 	for _, node := range horizon {
 		taskRun := node.(*graph.TaskInvocationNode)
 		schedule.AddRunTask(newRunTaskAction(taskRun.TaskInvocation.ID()))
@@ -131,8 +136,7 @@ func (p *PrewarmHorizonPolicy) Evaluate(invocation *types.WorkflowInvocation) (*
 
 	// Find and schedule all tasks on the scheduling horizon
 	openTasks := getOpenTasks(invocation)
-	depGraph := graph.Parse(graph.NewTaskInstanceIterator(openTasks))
-	horizon := graph.Roots(depGraph)
+	horizon := schedulingHorizon(invocation, openTasks)
 	for _, node := range horizon {
 		taskRun := node.(*graph.TaskInvocationNode)
 		schedule.AddRunTask(newRunTaskAction(taskRun.TaskInvocation.ID()))
@@ -152,10 +156,217 @@ func (p *PrewarmHorizonPolicy) Evaluate(invocation *types.WorkflowInvocation) (*
 	return schedule, nil
 }
 
+// CriticalPathPolicy is a latency-aware policy that, like the HorizonPolicy, schedules all tasks on the scheduling
+// horizon, but orders them by their criticality: the tasks that are the start of the longest (estimated) path
+// through the remaining tasks are started first. This way, long-pole tasks are not delayed by short tasks, when the
+// executor cannot start all tasks at once.
+//
+// The durations of tasks are estimated based on the historical durations of the tasks. Tasks without any history
+// are assumed to take defaultDuration.
+type CriticalPathPolicy struct {
+	stats           *TaskStats
+	defaultDuration time.Duration
+}
+
+func NewCriticalPathPolicy(stats *TaskStats, defaultDuration time.Duration) *CriticalPathPolicy {
+	return &CriticalPathPolicy{
+		stats:           stats,
+		defaultDuration: defaultDuration,
+	}
+}
+
+func (p *CriticalPathPolicy) ObserveTask(invocation *types.WorkflowInvocation, taskID string,
+	duration time.Duration) {
+	p.stats.Observe(invocation.Workflow().ID(), taskID, duration)
+}
+
+func (p *CriticalPathPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Schedule, error) {
+	schedule := &Schedule{InvocationId: invocation.ID(), CreatedAt: ptypes.TimestampNow()}
+
+	// If there are failed tasks halt the workflow
+	if failedTasks := getFailedTasks(invocation); len(failedTasks) > 0 {
+		for _, failedTask := range failedTasks {
+			msg := fmt.Sprintf("Task '%v' failed", failedTask.ID())
+			if err := failedTask.GetStatus().GetError(); err != nil {
+				msg = err.Message
+			}
+			schedule.Abort = newAbortAction(msg)
+		}
+		return schedule, nil
+	}
+
+	// Find all tasks on the scheduling horizon
+	openTasks := getOpenTasks(invocation)
+	var horizon []string
+	for _, node := range schedulingHorizon(invocation, openTasks) {
+		horizon = append(horizon, node.(*graph.TaskInvocationNode).Task().ID())
+	}
+
+	// Schedule the tasks in the order of the length of the remaining path that they are on.
+	pathLengths := p.remainingPathLengths(invocation, openTasks)
+	sort.Slice(horizon, func(i, j int) bool {
+		if pathLengths[horizon[i]] != pathLengths[horizon[j]] {
+			return pathLengths[horizon[i]] > pathLengths[horizon[j]]
+		}
+		return horizon[i] < horizon[j]
+	})
+	for _, taskID := range horizon {
+		schedule.AddRunTask(newRunTaskAction(taskID))
+	}
+	return schedule, nil
+}
+
+// remainingPathLengths computes for each open task the estimated duration of the longest path of open tasks that
+// starts with the task.
+func (p *CriticalPathPolicy) remainingPathLengths(invocation *types.WorkflowInvocation,
+	openTasks map[string]*types.TaskInvocation) map[string]time.Duration {
+	dependents := map[string][]string{}
+	for taskID, task := range invocation.Tasks() {
+		for dep := range task.GetSpec().GetRequires() {
+			dependents[dep] = append(dependents[dep], taskID)
+		}
+	}
+
+	workflowID := invocation.Workflow().ID()
+	lengths := map[string]time.Duration{}
+	var pathLength func(taskID string) time.Duration
+	pathLength = func(taskID string) time.Duration {
+		if l, ok := lengths[taskID]; ok {
+			return l
+		}
+		var longest time.Duration
+		for _, dependent := range dependents[taskID] {
+			if _, ok := openTasks[dependent]; !ok {
+				continue
+			}
+			if l := pathLength(dependent); l > longest {
+				longest = l
+			}
+		}
+		duration, ok := p.stats.Estimate(workflowID, taskID)
+		if !ok {
+			duration = p.defaultDuration
+		}
+		lengths[taskID] = duration + longest
+		return lengths[taskID]
+	}
+	for taskID := range openTasks {
+		pathLength(taskID)
+	}
+	return lengths
+}
+
+// schedulingHorizon returns the nodes of the open tasks that can be started: the tasks that do not depend on any
+// open task, and the tasks with a partial join (e.g. an any-of join) of which enough dependencies have succeeded.
+// Tasks that belong to a branch of a switch are excluded until the switch has selected their branch, and tasks of which
+// the inputs reference the output of a task that has not finished yet are excluded until that task has finished.
+func schedulingHorizon(invocation *types.WorkflowInvocation,
+	openTasks map[string]*types.TaskInvocation) []gonum.Node {
+	var horizon []gonum.Node
+	inHorizon := map[string]bool{}
+	for _, node := range graph.Roots(graph.Parse(graph.NewTaskInstanceIterator(openTasks))) {
+		taskID := node.(*graph.TaskInvocationNode).Task().ID()
+		inHorizon[taskID] = true
+		if !heldBack(invocation, taskID) {
+			horizon = append(horizon, node)
+		}
+	}
+	var joined []string
+	for taskID := range openTasks {
+		if _, ok := invocation.JoinTrigger(taskID); ok && !inHorizon[taskID] && !heldBack(invocation, taskID) {
+			joined = append(joined, taskID)
+		}
+	}
+	sort.Strings(joined)
+	for _, taskID := range joined {
+		horizon = append(horizon, &graph.TaskInvocationNode{TaskInvocation: openTasks[taskID]})
+	}
+	return horizon
+}
+
+// heldBack returns whether a task that is otherwise ready to run should not be scheduled yet.
+func heldBack(invocation *types.WorkflowInvocation, taskID string) bool {
+	if invocation.HeldBySwitch(taskID) {
+		return true
+	}
+	if pending := pendingReferences(invocation, taskID); len(pending) > 0 {
+		log.WithField("invocation", invocation.ID()).Debugf("Deferring task %s: its inputs reference the "+
+			"unfinished task(s) %v", taskID, pending)
+		return true
+	}
+	if blocking := blockingReferences(invocation, taskID); len(blocking) > 0 {
+		log.WithField("invocation", invocation.ID()).Debugf("Deferring task %s: it is blocked by the failed "+
+			"task(s) %v", taskID, blocking)
+		return true
+	}
+	return false
+}
+
+// pendingReferences returns the tasks that are referenced by the input expressions of the task, including the sources
+// of its input merges, but that have not reached a terminal state yet. Evaluating the inputs before these tasks have finished would bind them to a partial
+// scope, in which the outputs of the referenced tasks are still missing.
+//
+// References to unknown tasks, and to tasks that (transitively) depend on the task itself, are not considered pending:
+// the former cannot be waited for, and waiting for the latter would deadlock the invocation.
+func pendingReferences(invocation *types.WorkflowInvocation, taskID string) []string {
+	task, ok := invocation.Task(taskID)
+	if !ok {
+		return nil
+	}
+	inputs := make([]*typedvalues.TypedValue, 0, len(task.GetSpec().GetInputs()))
+	for _, input := range task.GetSpec().GetInputs() {
+		inputs = append(inputs, input)
+	}
+	inputs = append(inputs, task.GetSpec().MergeSources()...)
+	var pending []string
+	for _, ref := range expr.TaskReferences(inputs...) {
+		if ref == taskID {
+			continue
+		}
+		if _, ok := invocation.Task(ref); !ok {
+			continue
+		}
+		if taskRun, ok := invocation.TaskInvocation(ref); ok && taskRun.GetStatus().Finished() {
+			continue
+		}
+		if dependsOn(invocation, ref, taskID) {
+			continue
+		}
+		pending = append(pending, ref)
+	}
+	return pending
+}
+
+// dependsOn returns whether the task directly or transitively requires the dependency.
+func dependsOn(invocation *types.WorkflowInvocation, taskID string, dependency string) bool {
+	visited := map[string]bool{}
+	queue := []string{taskID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		task, ok := invocation.Task(current)
+		if !ok {
+			continue
+		}
+		for dep := range task.GetSpec().GetRequires() {
+			if dep == dependency {
+				return true
+			}
+			if !visited[dep] {
+				visited[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return false
+}
+
+// getFailedTasks returns the failed tasks of the invocation of which the failure is not handled by the failed
+// reference policies of the tasks that reference them (see failureHandled).
 func getFailedTasks(invocation *types.WorkflowInvocation) []*types.TaskInvocation {
 	var failedTasks []*types.TaskInvocation
 	for _, task := range invocation.TaskInvocations() {
-		if task.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED {
+		if task.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED && !failureHandled(invocation, task.ID()) {
 			failedTasks = append(failedTasks, task)
 		}
 	}
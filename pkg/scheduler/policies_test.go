@@ -0,0 +1,309 @@
+package scheduler
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupInvocation creates an invocation of a workflow with two independent chains of tasks:
+// short, and long -> afterLong.
+func setupInvocation() *types.WorkflowInvocation {
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("short", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("long", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("afterLong", &types.TaskSpec{
+		FunctionRef: "noop",
+		Requires:    types.Require("long"),
+	})
+	wfSpec.SetOutput("afterLong")
+	return &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{
+				Metadata: types.NewObjectMetadata("wf"),
+				Spec:     wfSpec,
+				Status:   &types.WorkflowStatus{},
+			},
+		},
+		Status: &types.WorkflowInvocationStatus{
+			Tasks: map[string]*types.TaskInvocation{},
+		},
+	}
+}
+
+// evaluatePolicies evaluates each of the policies on the same invocation, and returns the IDs of the tasks that
+// each policy decided to run, in the order of the schedule.
+func evaluatePolicies(t *testing.T, invocation *types.WorkflowInvocation,
+	policies map[string]Policy) map[string][]string {
+	runTasks := map[string][]string{}
+	for name, policy := range policies {
+		schedule, err := policy.Evaluate(invocation)
+		assert.NoError(t, err, name)
+		assert.Nil(t, schedule.GetAbort(), name)
+		for _, action := range schedule.GetRunTasks() {
+			runTasks[name] = append(runTasks[name], action.GetTaskID())
+		}
+	}
+	return runTasks
+}
+
+func sorted(ids []string) []string {
+	cp := append([]string{}, ids...)
+	sort.Strings(cp)
+	return cp
+}
+
+func TestCriticalPathPolicy_ComparedToHorizonPolicy(t *testing.T) {
+	invocation := setupInvocation()
+	runTasks := evaluatePolicies(t, invocation, map[string]Policy{
+		"horizon":       NewHorizonPolicy(),
+		"critical-path": NewCriticalPathPolicy(NewTaskStats(), DefaultEstimatedTaskDuration),
+	})
+
+	// Both policies should decide to run the same tasks...
+	assert.Equal(t, sorted(runTasks["horizon"]), sorted(runTasks["critical-path"]))
+	// ...but the critical path policy should start the task on the longest path first.
+	assert.Equal(t, []string{"long", "short"}, runTasks["critical-path"])
+}
+
+func TestCriticalPathPolicy_UsesHistoricalDurations(t *testing.T) {
+	invocation := setupInvocation()
+	policy := NewCriticalPathPolicy(NewTaskStats(), DefaultEstimatedTaskDuration)
+	policy.ObserveTask(invocation, "short", 10*time.Second)
+	policy.ObserveTask(invocation, "long", 10*time.Millisecond)
+	policy.ObserveTask(invocation, "afterLong", 10*time.Millisecond)
+
+	runTasks := evaluatePolicies(t, invocation, map[string]Policy{
+		"critical-path": policy,
+	})
+	assert.Equal(t, []string{"short", "long"}, runTasks["critical-path"])
+}
+
+func TestTaskStats(t *testing.T) {
+	stats := NewTaskStats()
+	_, ok := stats.Estimate("wf", "task")
+	assert.False(t, ok)
+
+	stats.Observe("wf", "task", time.Second)
+	d, ok := stats.Estimate("wf", "task")
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+
+	// Subsequent observations should move the estimate towards the observed durations.
+	stats.Observe("wf", "task", 2*time.Second)
+	d, _ = stats.Estimate("wf", "task")
+	assert.True(t, d > time.Second && d < 2*time.Second)
+}
+
+// setupRaceInvocation creates an invocation of a workflow in which the first task awaits whichever of its two
+// branches succeeds first: fast, or slowStart -> slow.
+func setupRaceInvocation() *types.WorkflowInvocation {
+	invocation := setupInvocation()
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("fast", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("slowStart", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("slow", &types.TaskSpec{
+		FunctionRef: "noop",
+		Requires:    types.Require("slowStart"),
+	})
+	wfSpec.AddTask("first", &types.TaskSpec{
+		FunctionRef: "noop",
+		Requires:    types.Require("fast", "slow"),
+		Await:       1,
+	})
+	wfSpec.SetOutput("first")
+	invocation.Spec.Workflow.Spec = wfSpec
+	return invocation
+}
+
+func setTaskRun(invocation *types.WorkflowInvocation, taskID string, status types.TaskInvocationStatus_Status,
+	updatedAt int64) {
+	invocation.Status.Tasks[taskID] = &types.TaskInvocation{
+		Metadata: types.NewObjectMetadata(taskID),
+		Spec:     &types.TaskInvocationSpec{TaskId: taskID},
+		Status: &types.TaskInvocationStatus{
+			Status:    status,
+			UpdatedAt: &timestamp.Timestamp{Seconds: updatedAt},
+		},
+	}
+}
+
+func TestPolicies_AnyOfJoin(t *testing.T) {
+	policies := map[string]Policy{
+		"horizon":         NewHorizonPolicy(),
+		"prewarm-all":     NewPrewarmAllPolicy(time.Second),
+		"prewarm-horizon": NewPrewarmHorizonPolicy(time.Second),
+		"critical-path":   NewCriticalPathPolicy(NewTaskStats(), DefaultEstimatedTaskDuration),
+	}
+
+	// While none of the branches have succeeded, the join should not be on the horizon.
+	invocation := setupRaceInvocation()
+	setTaskRun(invocation, "fast", types.TaskInvocationStatus_IN_PROGRESS, 1)
+	setTaskRun(invocation, "slowStart", types.TaskInvocationStatus_SUCCEEDED, 1)
+	for name, runTasks := range evaluatePolicies(t, invocation, policies) {
+		assert.Equal(t, []string{"slow"}, runTasks, name)
+	}
+
+	// Once the fast branch has won the race, the join should be started without waiting for the slow branch.
+	setTaskRun(invocation, "fast", types.TaskInvocationStatus_SUCCEEDED, 2)
+	for name, runTasks := range evaluatePolicies(t, invocation, policies) {
+		assert.Equal(t, []string{"first", "slow"}, sorted(runTasks), name)
+	}
+	trigger, ok := invocation.JoinTrigger("first")
+	assert.True(t, ok)
+	assert.Equal(t, "fast", trigger)
+}
+
+func TestJoinTrigger_Race(t *testing.T) {
+	invocation := setupRaceInvocation()
+	_, ok := invocation.JoinTrigger("first")
+	assert.False(t, ok)
+
+	// Tasks that await all of their dependencies are never triggered by a single dependency.
+	setTaskRun(invocation, "slowStart", types.TaskInvocationStatus_SUCCEEDED, 1)
+	_, ok = invocation.JoinTrigger("slow")
+	assert.False(t, ok)
+
+	// If both branches have succeeded by the time that the join is evaluated, the first one to succeed wins.
+	setTaskRun(invocation, "fast", types.TaskInvocationStatus_SUCCEEDED, 3)
+	setTaskRun(invocation, "slow", types.TaskInvocationStatus_SUCCEEDED, 2)
+	trigger, ok := invocation.JoinTrigger("first")
+	assert.True(t, ok)
+	assert.Equal(t, "slow", trigger)
+
+	// Ties are broken by the IDs of the dependencies, so that the trigger is deterministic.
+	setTaskRun(invocation, "fast", types.TaskInvocationStatus_SUCCEEDED, 2)
+	trigger, _ = invocation.JoinTrigger("first")
+	assert.Equal(t, "fast", trigger)
+
+	// Failed branches do not trigger the join.
+	setTaskRun(invocation, "fast", types.TaskInvocationStatus_FAILED, 1)
+	trigger, _ = invocation.JoinTrigger("first")
+	assert.Equal(t, "slow", trigger)
+}
+
+// setupSwitchInvocation creates an invocation of a workflow in which the route switch selects either the small or the
+// large branch after classify has succeeded, after which merge joins the branches.
+func setupSwitchInvocation() *types.WorkflowInvocation {
+	invocation := setupInvocation()
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("classify", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("small", &types.TaskSpec{FunctionRef: "noop", Requires: types.Require("classify")})
+	wfSpec.AddTask("large", &types.TaskSpec{FunctionRef: "noop", Requires: types.Require("classify")})
+	wfSpec.AddTask("merge", &types.TaskSpec{FunctionRef: "noop", Requires: types.Require("small", "large")})
+	wfSpec.Switches = map[string]*types.Switch{
+		"route": {
+			Expression: "{ $.Tasks.classify.Output }",
+			Requires:   []string{"classify"},
+			Branches: map[string]*types.Branch{
+				"small": {Tasks: []string{"small"}},
+				"large": {Tasks: []string{"large"}},
+			},
+		},
+	}
+	wfSpec.SetOutput("merge")
+	invocation.Spec.Workflow.Spec = wfSpec
+	return invocation
+}
+
+func TestPolicies_Switch(t *testing.T) {
+	policies := map[string]Policy{
+		"horizon":         NewHorizonPolicy(),
+		"prewarm-all":     NewPrewarmAllPolicy(time.Second),
+		"prewarm-horizon": NewPrewarmHorizonPolicy(time.Second),
+		"critical-path":   NewCriticalPathPolicy(NewTaskStats(), DefaultEstimatedTaskDuration),
+	}
+
+	// The branches are held back until the switch has selected one of them.
+	invocation := setupSwitchInvocation()
+	setTaskRun(invocation, "classify", types.TaskInvocationStatus_SUCCEEDED, 1)
+	for name, runTasks := range evaluatePolicies(t, invocation, policies) {
+		assert.Empty(t, runTasks, name)
+	}
+
+	// Only the selected branch is scheduled.
+	invocation.Status.Branches = map[string]string{"route": "large"}
+	for name, runTasks := range evaluatePolicies(t, invocation, policies) {
+		assert.Equal(t, []string{"large"}, runTasks, name)
+	}
+
+	// Tasks that depend on a skipped branch are scheduled once the selected branch has finished.
+	setTaskRun(invocation, "small", types.TaskInvocationStatus_SKIPPED, 2)
+	setTaskRun(invocation, "large", types.TaskInvocationStatus_SUCCEEDED, 2)
+	for name, runTasks := range evaluatePolicies(t, invocation, policies) {
+		assert.Equal(t, []string{"merge"}, runTasks, name)
+	}
+}
+
+// setupForwardReferenceInvocation creates an invocation of a workflow in which the inputs of a task reference the
+// output of a task that it does not depend on: report references the output of the unrelated audit task.
+func setupForwardReferenceInvocation() *types.WorkflowInvocation {
+	invocation := setupInvocation()
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("fetch", &types.TaskSpec{FunctionRef: "noop"})
+	wfSpec.AddTask("audit", &types.TaskSpec{FunctionRef: "noop", Requires: types.Require("fetch")})
+	wfSpec.AddTask("report", &types.TaskSpec{
+		FunctionRef: "noop",
+		Inputs: map[string]*typedvalues.TypedValue{
+			types.InputMain: typedvalues.MustWrap("{ output('audit') }"),
+			"previous":      typedvalues.MustWrap("{ $.Tasks.notify.Output }"),
+		},
+	})
+	wfSpec.AddTask("notify", &types.TaskSpec{
+		FunctionRef: "noop",
+		Requires:    types.Require("report"),
+	})
+	wfSpec.SetOutput("notify")
+	invocation.Spec.Workflow.Spec = wfSpec
+	return invocation
+}
+
+func TestPolicies_ForwardReference(t *testing.T) {
+	policies := map[string]Policy{
+		"horizon":         NewHorizonPolicy(),
+		"prewarm-all":     NewPrewarmAllPolicy(time.Second),
+		"prewarm-horizon": NewPrewarmHorizonPolicy(time.Second),
+		"critical-path":   NewCriticalPathPolicy(NewTaskStats(), DefaultEstimatedTaskDuration),
+	}
+
+	// The report task is deferred until the audit task has finished. The reference to notify is ignored, because
+	// notify depends on report.
+	invocation := setupForwardReferenceInvocation()
+	for name, runTasks := range evaluatePolicies(t, invocation, policies) {
+		assert.Equal(t, []string{"fetch"}, runTasks, name)
+	}
+	setTaskRun(invocation, "fetch", types.TaskInvocationStatus_SUCCEEDED, 1)
+	setTaskRun(invocation, "audit", types.TaskInvocationStatus_IN_PROGRESS, 2)
+	for name, runTasks := range evaluatePolicies(t, invocation, policies) {
+		assert.Empty(t, runTasks, name)
+	}
+
+	// Once the referenced task has reached a terminal state, the inputs of report can be bound.
+	setTaskRun(invocation, "audit", types.TaskInvocationStatus_FAILED, 3)
+	assert.Empty(t, pendingReferences(invocation, "report"))
+	setTaskRun(invocation, "audit", types.TaskInvocationStatus_SUCCEEDED, 3)
+	for name, runTasks := range evaluatePolicies(t, invocation, policies) {
+		assert.Equal(t, []string{"report"}, runTasks, name)
+	}
+}
+
+func TestPendingReferences_MergeInputs(t *testing.T) {
+	invocation := setupForwardReferenceInvocation()
+	report := invocation.Spec.Workflow.Spec.Tasks["report"]
+	report.Inputs = nil
+	report.MergeInputs = map[string]*types.InputMerge{
+		types.InputMain: {Sources: []*typedvalues.TypedValue{typedvalues.MustWrap("{ output('audit') }")}},
+	}
+
+	// The sources of the input merges are bound like inputs, so the report task waits for the audit task.
+	assert.Equal(t, []string{"audit"}, pendingReferences(invocation, "report"))
+	setTaskRun(invocation, "fetch", types.TaskInvocationStatus_SUCCEEDED, 1)
+	setTaskRun(invocation, "audit", types.TaskInvocationStatus_SUCCEEDED, 2)
+	assert.Empty(t, pendingReferences(invocation, "report"))
+}
@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// applyRateLimits defers the run tasks of the schedule of which the rate limit does not allow another execution at
+// the time now, by replacing their run task action with a throttle task action.
+//
+// The executions are counted from the task runs of the invocation, so that the rate limits hold across restarts of
+// the controller. The tasks that share a rate limit key (see rateLimitKey) share their executions. The tasks that are
+// scheduled in this evaluation are counted as executions that start now, in the order of the schedule.
+func applyRateLimits(invocation *types.WorkflowInvocation, schedule *Schedule, now time.Time) {
+	var runTasks []*RunTaskAction
+	var starts map[string][]time.Time
+	for _, action := range schedule.GetRunTasks() {
+		task, ok := invocation.Task(action.TaskID)
+		rateLimit := task.GetSpec().GetRateLimit()
+		if !ok || rateLimit == nil {
+			runTasks = append(runTasks, action)
+			continue
+		}
+		if starts == nil {
+			starts = rateLimitedStarts(invocation)
+		}
+		key := rateLimitKey(task.GetSpec())
+		if until, throttled := throttledUntil(rateLimit, starts[key], now); throttled {
+			ts, _ := ptypes.TimestampProto(until)
+			schedule.ThrottleTasks = append(schedule.ThrottleTasks, &ThrottleTaskAction{
+				TaskID: action.TaskID,
+				Until:  ts,
+			})
+			continue
+		}
+		starts[key] = append(starts[key], now)
+		runTasks = append(runTasks, action)
+	}
+	schedule.RunTasks = runTasks
+}
+
+// rateLimitKey returns the key of the rate limit of the task. Unless the rate limit specifies a key, the tasks that
+// run the same function share their rate limit; in particular the iterations of a loop, which are copies of the same
+// task.
+func rateLimitKey(spec *types.TaskSpec) string {
+	if key := spec.GetRateLimit().GetKey(); len(key) > 0 {
+		return "key:" + key
+	}
+	return "fn:" + spec.GetFunctionRef()
+}
+
+// rateLimitedStarts returns the start times of the task runs of the invocation that have a rate limit, sorted per
+// rate limit key.
+func rateLimitedStarts(invocation *types.WorkflowInvocation) map[string][]time.Time {
+	starts := map[string][]time.Time{}
+	for _, taskRun := range invocation.TaskInvocations() {
+		spec := taskRun.GetSpec().GetTask().GetSpec()
+		if spec.GetRateLimit() == nil {
+			continue
+		}
+		startedAt, err := ptypes.Timestamp(taskRun.GetMetadata().GetCreatedAt())
+		if err != nil {
+			continue
+		}
+		key := rateLimitKey(spec)
+		starts[key] = append(starts[key], startedAt)
+	}
+	for _, ts := range starts {
+		sort.Slice(ts, func(i, j int) bool {
+			return ts[i].Before(ts[j])
+		})
+	}
+	return starts
+}
+
+// throttledUntil returns whether the rate limit does not allow another execution at the time now, given the sorted
+// start times of the previous executions, and if so, the time until which the execution is deferred.
+func throttledUntil(rateLimit *types.RateLimit, starts []time.Time, now time.Time) (time.Time, bool) {
+	window, err := ptypes.Duration(rateLimit.GetWindow())
+	if err != nil || window <= 0 || rateLimit.GetExecutions() <= 0 {
+		return time.Time{}, false
+	}
+	var inWindow []time.Time
+	for _, startedAt := range starts {
+		if now.Sub(startedAt) < window {
+			inWindow = append(inWindow, startedAt)
+		}
+	}
+	excess := len(inWindow) - int(rateLimit.GetExecutions())
+	if excess < 0 {
+		return time.Time{}, false
+	}
+	// Another execution is allowed once enough of the executions in the window have left it.
+	return inWindow[excess].Add(window), true
+}
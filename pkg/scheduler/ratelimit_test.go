@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvocationScheduler_RateLimit(t *testing.T) {
+	rateLimit := &types.RateLimit{Executions: 2, Window: ptypes.DurationProto(time.Minute)}
+	wfSpec := types.NewWorkflowSpec()
+	for _, id := range []string{"do_0", "do_1", "do_2", "do_3"} {
+		wfSpec.AddTask(id, &types.TaskSpec{FunctionRef: "api", RateLimit: rateLimit})
+	}
+	wfSpec.AddTask("other", &types.TaskSpec{
+		FunctionRef: "api",
+		RateLimit:   &types.RateLimit{Executions: 1, Window: ptypes.DurationProto(time.Minute), Key: "other"},
+	})
+	wfSpec.SetOutput("other")
+	invocation := &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi"),
+		Spec: &types.WorkflowInvocationSpec{
+			Workflow: &types.Workflow{
+				Metadata: types.NewObjectMetadata("wf"),
+				Spec:     wfSpec,
+				Status:   &types.WorkflowStatus{},
+			},
+		},
+		Status: &types.WorkflowInvocationStatus{
+			Tasks: map[string]*types.TaskInvocation{},
+		},
+	}
+
+	// do_0 started within the window, so only one more execution of the function is allowed.
+	startedAt := time.Now().Add(-30 * time.Second)
+	ts, _ := ptypes.TimestampProto(startedAt)
+	task, _ := invocation.Task("do_0")
+	invocation.Status.Tasks["do_0"] = &types.TaskInvocation{
+		Metadata: &types.ObjectMetadata{Id: "do_0", CreatedAt: ts},
+		Spec:     &types.TaskInvocationSpec{TaskId: "do_0", Task: task},
+		Status:   &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_IN_PROGRESS},
+	}
+
+	schedule, err := NewInvocationScheduler(NewHorizonPolicy()).Evaluate(invocation)
+	assert.NoError(t, err)
+	var runTasks []string
+	for _, action := range schedule.GetRunTasks() {
+		runTasks = append(runTasks, action.GetTaskID())
+	}
+	assert.Len(t, runTasks, 2)
+	assert.Contains(t, runTasks, "other")
+
+	// The other tasks are throttled until do_0 has left the window.
+	assert.Len(t, schedule.GetThrottleTasks(), 2)
+	for _, action := range schedule.GetThrottleTasks() {
+		assert.NotContains(t, runTasks, action.GetTaskID())
+		until, err := ptypes.Timestamp(action.GetUntil())
+		assert.NoError(t, err)
+		assert.True(t, until.Equal(startedAt.Add(time.Minute)), until)
+	}
+}
+
+func TestThrottledUntil(t *testing.T) {
+	now := time.Now()
+	rateLimit := &types.RateLimit{Executions: 2, Window: ptypes.DurationProto(10 * time.Second)}
+	starts := []time.Time{now.Add(-time.Minute), now.Add(-8 * time.Second), now.Add(-2 * time.Second)}
+
+	until, throttled := throttledUntil(rateLimit, starts, now)
+	assert.True(t, throttled)
+	assert.Equal(t, now.Add(2*time.Second), until)
+
+	_, throttled = throttledUntil(rateLimit, starts[:2], now)
+	assert.False(t, throttled)
+
+	// Once the oldest execution has left the window, the task is no longer throttled.
+	_, throttled = throttledUntil(rateLimit, starts, now.Add(2*time.Second))
+	assert.False(t, throttled)
+}
@@ -26,10 +26,18 @@ var (
 	})
 )
 
+// Policy decides which actions should be taken for an invocation. The policy is the extension point of the scheduler;
+// the InvocationScheduler uses the policy that it has been configured with.
 type Policy interface {
 	Evaluate(invocation *types.WorkflowInvocation) (*Schedule, error)
 }
 
+// TaskObserver is an optional interface for policies that take the historical performance of tasks into account.
+type TaskObserver interface {
+	// ObserveTask is called by the scheduler for each successfully completed task of an invocation.
+	ObserveTask(invocation *types.WorkflowInvocation, taskID string, duration time.Duration)
+}
+
 func init() {
 	prometheus.MustRegister(metricEvalCount, metricEvalTime)
 }
@@ -60,10 +68,23 @@ func (ws *InvocationScheduler) Evaluate(invocation *types.WorkflowInvocation) (*
 		return nil, err
 	}
 
+	// The rate limits of tasks are enforced regardless of the policy.
+	if schedule.GetAbort() == nil {
+		applyRateLimits(invocation, schedule, time.Now())
+	}
+
 	ctxLog.Debugf("Determined schedule: %v", schedule)
 	return schedule, nil
 }
 
+// ObserveTask reports the duration of a completed task to the policy, if the policy is interested in it.
+func (ws *InvocationScheduler) ObserveTask(invocation *types.WorkflowInvocation, taskID string,
+	duration time.Duration) {
+	if observer, ok := ws.policy.(TaskObserver); ok {
+		observer.ObserveTask(invocation, taskID, duration)
+	}
+}
+
 func newRunTaskAction(taskID string) *RunTaskAction {
 	return &RunTaskAction{
 		TaskID: taskID,
@@ -12,6 +12,7 @@ It has these top-level messages:
 	AbortAction
 	RunTaskAction
 	PrepareTaskAction
+	ThrottleTaskAction
 */
 package scheduler
 
@@ -38,11 +39,12 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Schedule struct {
-	InvocationId string                     `protobuf:"bytes,1,opt,name=invocationId" json:"invocationId,omitempty"`
-	CreatedAt    *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=createdAt" json:"createdAt,omitempty"`
-	Abort        *AbortAction               `protobuf:"bytes,4,opt,name=abort" json:"abort,omitempty"`
-	RunTasks     []*RunTaskAction           `protobuf:"bytes,5,rep,name=runTasks" json:"runTasks,omitempty"`
-	PrepareTasks []*PrepareTaskAction       `protobuf:"bytes,6,rep,name=prepareTasks" json:"prepareTasks,omitempty"`
+	InvocationId  string                     `protobuf:"bytes,1,opt,name=invocationId" json:"invocationId,omitempty"`
+	CreatedAt     *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=createdAt" json:"createdAt,omitempty"`
+	Abort         *AbortAction               `protobuf:"bytes,4,opt,name=abort" json:"abort,omitempty"`
+	RunTasks      []*RunTaskAction           `protobuf:"bytes,5,rep,name=runTasks" json:"runTasks,omitempty"`
+	PrepareTasks  []*PrepareTaskAction       `protobuf:"bytes,6,rep,name=prepareTasks" json:"prepareTasks,omitempty"`
+	ThrottleTasks []*ThrottleTaskAction      `protobuf:"bytes,7,rep,name=throttleTasks" json:"throttleTasks,omitempty"`
 }
 
 func (m *Schedule) Reset()                    { *m = Schedule{} }
@@ -85,6 +87,13 @@ func (m *Schedule) GetPrepareTasks() []*PrepareTaskAction {
 	return nil
 }
 
+func (m *Schedule) GetThrottleTasks() []*ThrottleTaskAction {
+	if m != nil {
+		return m.ThrottleTasks
+	}
+	return nil
+}
+
 type AbortAction struct {
 	Reason string `protobuf:"bytes,1,opt,name=reason" json:"reason,omitempty"`
 }
@@ -142,11 +151,37 @@ func (m *PrepareTaskAction) GetExpectedAt() *google_protobuf.Timestamp {
 	return nil
 }
 
+// ThrottleTaskAction defers the execution of a task on the scheduling horizon until its rate limit allows it.
+type ThrottleTaskAction struct {
+	TaskID string                     `protobuf:"bytes,1,opt,name=taskID" json:"taskID,omitempty"`
+	Until  *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=until" json:"until,omitempty"`
+}
+
+func (m *ThrottleTaskAction) Reset()                    { *m = ThrottleTaskAction{} }
+func (m *ThrottleTaskAction) String() string            { return proto.CompactTextString(m) }
+func (*ThrottleTaskAction) ProtoMessage()               {}
+func (*ThrottleTaskAction) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *ThrottleTaskAction) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+func (m *ThrottleTaskAction) GetUntil() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Schedule)(nil), "fission.workflows.scheduler.Schedule")
 	proto.RegisterType((*AbortAction)(nil), "fission.workflows.scheduler.AbortAction")
 	proto.RegisterType((*RunTaskAction)(nil), "fission.workflows.scheduler.RunTaskAction")
 	proto.RegisterType((*PrepareTaskAction)(nil), "fission.workflows.scheduler.PrepareTaskAction")
+	proto.RegisterType((*ThrottleTaskAction)(nil), "fission.workflows.scheduler.ThrottleTaskAction")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -224,29 +259,31 @@ var _Scheduler_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/scheduler/scheduler.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 374 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x4f, 0x4b, 0xeb, 0x40,
-	0x14, 0xc5, 0x5f, 0xfb, 0x5e, 0x4b, 0x73, 0xd3, 0xb7, 0x70, 0x16, 0x12, 0x22, 0x62, 0x09, 0x14,
-	0x83, 0xe2, 0x04, 0xea, 0x46, 0xba, 0x10, 0x2a, 0x22, 0x74, 0x27, 0xb1, 0x20, 0xb8, 0x72, 0x92,
-	0x4e, 0xd3, 0x90, 0x3f, 0x13, 0x66, 0x26, 0xad, 0x7e, 0x3f, 0x3f, 0x98, 0x24, 0x93, 0xa4, 0x2d,
-	0x6a, 0x70, 0x93, 0xe4, 0x0e, 0xbf, 0x73, 0xce, 0xcd, 0x9d, 0x0b, 0xa7, 0x59, 0x14, 0x38, 0xc2,
-	0x5f, 0xd3, 0x65, 0x1e, 0x53, 0xbe, 0xfb, 0xc2, 0x19, 0x67, 0x92, 0xa1, 0x93, 0x55, 0x28, 0x44,
-	0xc8, 0x52, 0xbc, 0x65, 0x3c, 0x5a, 0xc5, 0x6c, 0x2b, 0x70, 0x83, 0x98, 0xd3, 0x20, 0x94, 0xeb,
-	0xdc, 0xc3, 0x3e, 0x4b, 0x9c, 0x8a, 0xab, 0xdf, 0x57, 0x0d, 0xef, 0x14, 0x01, 0xf2, 0x3d, 0xa3,
-	0x42, 0x3d, 0x95, 0xb1, 0x79, 0x16, 0x30, 0x16, 0xc4, 0xd4, 0x29, 0x2b, 0x2f, 0x5f, 0x39, 0x32,
-	0x4c, 0xa8, 0x90, 0x24, 0xc9, 0x14, 0x60, 0x7d, 0x74, 0x61, 0xf0, 0x54, 0x45, 0x21, 0x0b, 0x86,
-	0x61, 0xba, 0x61, 0x3e, 0x91, 0x21, 0x4b, 0xe7, 0x4b, 0xa3, 0x33, 0xea, 0xd8, 0x9a, 0x7b, 0x70,
-	0x86, 0x6e, 0x40, 0xf3, 0x39, 0x25, 0x92, 0x2e, 0x67, 0xd2, 0xe8, 0x8e, 0x3a, 0xb6, 0x3e, 0x31,
-	0xb1, 0x4a, 0xc1, 0x75, 0x0a, 0x5e, 0xd4, 0x29, 0xee, 0x0e, 0x46, 0xb7, 0xd0, 0x23, 0x1e, 0xe3,
-	0xd2, 0xf8, 0x57, 0xaa, 0x6c, 0xdc, 0xf2, 0xd3, 0x78, 0x56, 0x90, 0x33, 0xbf, 0x08, 0x75, 0x95,
-	0x0c, 0x3d, 0xc0, 0x80, 0xe7, 0xe9, 0x82, 0x88, 0x48, 0x18, 0xbd, 0xd1, 0x5f, 0x5b, 0x9f, 0x5c,
-	0xb4, 0x5a, 0xb8, 0x0a, 0xae, 0x4c, 0x1a, 0x2d, 0x72, 0x61, 0x98, 0x71, 0x9a, 0x11, 0x4e, 0x95,
-	0x57, 0xbf, 0xf4, 0xc2, 0xad, 0x5e, 0x8f, 0x3b, 0x41, 0xe5, 0x77, 0xe0, 0x61, 0x8d, 0x41, 0xdf,
-	0xeb, 0x18, 0x1d, 0x43, 0x9f, 0x53, 0x22, 0x58, 0x5a, 0x8d, 0xb0, 0xaa, 0xac, 0x73, 0xf8, 0x7f,
-	0xd0, 0x55, 0x01, 0x4a, 0x22, 0xa2, 0xf9, 0x7d, 0x0d, 0xaa, 0xca, 0x0a, 0xe0, 0xe8, 0x4b, 0xe4,
-	0x4f, 0x30, 0x9a, 0x02, 0xd0, 0xb7, 0x8c, 0xfa, 0xbf, 0xbd, 0x93, 0x3d, 0x7a, 0x92, 0x80, 0x56,
-	0x5f, 0x3f, 0x47, 0xaf, 0x30, 0xa0, 0x1b, 0x12, 0xe7, 0x44, 0x52, 0x74, 0xf9, 0xcd, 0x3c, 0xd4,
-	0x66, 0x3d, 0x57, 0xf5, 0xbc, 0x59, 0x0b, 0x73, 0xdc, 0x3a, 0xbc, 0x3a, 0xc0, 0xfa, 0x73, 0xa7,
-	0xbf, 0x68, 0xcd, 0xb9, 0xd7, 0x2f, 0x7b, 0xbb, 0xfe, 0x0c, 0x00, 0x00, 0xff, 0xff, 0xc1, 0x32,
-	0xe7, 0xf1, 0x1d, 0x03, 0x00, 0x00,
+	// 407 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8d, 0x93, 0x41, 0x4f, 0xc2, 0x30,
+	0x14, 0xc7, 0x45, 0x04, 0xd9, 0x03, 0x0e, 0xf6, 0x60, 0xc8, 0x8c, 0x91, 0x2c, 0x21, 0x12, 0x8d,
+	0x9d, 0xc1, 0x8b, 0xe1, 0x60, 0x82, 0x31, 0x26, 0xdc, 0xcc, 0xc4, 0x98, 0x78, 0x30, 0x8e, 0x51,
+	0xc6, 0xc2, 0x58, 0x97, 0xb6, 0x03, 0xfd, 0x2c, 0x7e, 0x59, 0xc7, 0xda, 0x0d, 0x08, 0xb2, 0x70,
+	0xd9, 0xf6, 0x96, 0xdf, 0xfb, 0xfd, 0xf7, 0xda, 0x0e, 0xce, 0xc3, 0xa9, 0x6b, 0x72, 0x67, 0x42,
+	0x46, 0x91, 0x4f, 0xd8, 0xea, 0x09, 0x87, 0x8c, 0x0a, 0x8a, 0xce, 0xc6, 0x1e, 0xe7, 0x1e, 0x0d,
+	0xf0, 0x82, 0xb2, 0xe9, 0xd8, 0xa7, 0x0b, 0x8e, 0x33, 0x44, 0xef, 0xba, 0x9e, 0x98, 0x44, 0x43,
+	0xec, 0xd0, 0x99, 0xa9, 0xb8, 0xf4, 0x7e, 0x93, 0xf1, 0xe6, 0x32, 0x40, 0xfc, 0x84, 0x84, 0xcb,
+	0xab, 0x14, 0xeb, 0x17, 0x2e, 0xa5, 0xae, 0x4f, 0xcc, 0xa4, 0x1a, 0x46, 0x63, 0x53, 0x78, 0x33,
+	0xc2, 0x85, 0x3d, 0x0b, 0x25, 0x60, 0xfc, 0x16, 0xa1, 0xf2, 0xaa, 0xa2, 0x90, 0x01, 0x35, 0x2f,
+	0x98, 0x53, 0xc7, 0x16, 0xb1, 0xbb, 0x3f, 0x6a, 0x14, 0x9a, 0x85, 0xb6, 0x66, 0x6d, 0xbc, 0x43,
+	0xf7, 0xa0, 0x39, 0x8c, 0xd8, 0x82, 0x8c, 0x7a, 0xa2, 0x71, 0x18, 0x03, 0xd5, 0x8e, 0x8e, 0x65,
+	0x0a, 0x4e, 0x53, 0xf0, 0x20, 0x4d, 0xb1, 0x56, 0x30, 0x7a, 0x80, 0x92, 0x3d, 0xa4, 0x4c, 0x34,
+	0x8e, 0x92, 0xae, 0x36, 0xce, 0x19, 0x1a, 0xf7, 0x96, 0x64, 0xcf, 0x59, 0x86, 0x5a, 0xb2, 0x0d,
+	0x3d, 0x43, 0x85, 0x45, 0xc1, 0xc0, 0xe6, 0x53, 0xde, 0x28, 0x35, 0x8b, 0xb1, 0xe2, 0x2a, 0x57,
+	0x61, 0x49, 0x58, 0x49, 0xb2, 0x5e, 0x64, 0x41, 0x2d, 0x64, 0x24, 0xb4, 0x19, 0x91, 0xae, 0x72,
+	0xe2, 0xc2, 0xb9, 0xae, 0x97, 0x55, 0x83, 0xf2, 0x6d, 0x38, 0xd0, 0x1b, 0xd4, 0xc5, 0x24, 0x9e,
+	0x5e, 0xf8, 0x4a, 0x7a, 0x9c, 0x48, 0xcd, 0x5c, 0xe9, 0x60, 0xad, 0x43, 0x59, 0x37, 0x2d, 0x46,
+	0x0b, 0xaa, 0x6b, 0x0b, 0x81, 0x4e, 0xa1, 0x1c, 0xaf, 0x26, 0xa7, 0x81, 0xda, 0x19, 0x55, 0x19,
+	0x97, 0x50, 0xdf, 0x18, 0x76, 0x09, 0x8a, 0xb8, 0xea, 0x3f, 0xa5, 0xa0, 0xac, 0x0c, 0x17, 0x4e,
+	0xb6, 0x26, 0xd9, 0x05, 0xa3, 0x2e, 0x00, 0xf9, 0x0e, 0x89, 0xb3, 0xef, 0x56, 0xaf, 0xd1, 0xc6,
+	0x27, 0xa0, 0xed, 0xe9, 0x76, 0x26, 0xdd, 0x42, 0x29, 0x0a, 0x84, 0xe7, 0xef, 0x11, 0x22, 0xc1,
+	0xce, 0x0c, 0xb4, 0xf4, 0xd4, 0x32, 0xf4, 0x05, 0x15, 0x32, 0xb7, 0xfd, 0x28, 0x3e, 0x67, 0xe8,
+	0xfa, 0x9f, 0x15, 0x97, 0x3f, 0xc4, 0xbb, 0xaa, 0xfb, 0xd9, 0x69, 0xd6, 0x5b, 0xb9, 0xdb, 0x93,
+	0x06, 0x18, 0x07, 0x8f, 0xd5, 0x0f, 0x2d, 0x7b, 0x3f, 0x2c, 0x27, 0x9f, 0x75, 0xf7, 0x07, 0xfd,
+	0x1b, 0x72, 0x35, 0xd4, 0x03, 0x00, 0x00,
 }
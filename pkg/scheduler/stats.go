@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// statsSmoothing is the weight of a new observation in the moving average of the task durations.
+const statsSmoothing = 0.3
+
+// TaskStats keeps track of the historical durations of the tasks of workflows.
+//
+// The durations are kept as an exponential moving average per task, allowing the estimates to follow changes in the
+// performance of the underlying functions.
+type TaskStats struct {
+	durations map[string]time.Duration // taskKey -> average duration
+	mu        *sync.RWMutex
+}
+
+func NewTaskStats() *TaskStats {
+	return &TaskStats{
+		durations: map[string]time.Duration{},
+		mu:        &sync.RWMutex{},
+	}
+}
+
+// Observe records the duration of an execution of the task of the workflow.
+func (s *TaskStats) Observe(workflowID string, taskID string, duration time.Duration) {
+	key := taskKey(workflowID, taskID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avg, ok := s.durations[key]
+	if !ok {
+		s.durations[key] = duration
+		return
+	}
+	s.durations[key] = avg + time.Duration(statsSmoothing*float64(duration-avg))
+}
+
+// Estimate returns the expected duration of the task of the workflow, or false if the task has not been observed.
+func (s *TaskStats) Estimate(workflowID string, taskID string) (time.Duration, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, ok := s.durations[taskKey(workflowID, taskID)]
+	return d, ok
+}
+
+func taskKey(workflowID string, taskID string) string {
+	return workflowID + "/" + taskID
+}
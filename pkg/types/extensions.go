@@ -1,6 +1,8 @@
 package types
 
 import (
+	"sort"
+
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/golang/protobuf/proto"
 )
@@ -20,6 +22,61 @@ const (
 	TypeWorkflow   = "workflow"
 	TypeInvocation = "invocation"
 	TypeTaskRun    = "taskrun"
+
+	CompletionModeAll              = "all"
+	CompletionModeAny              = "any"
+	CompletionModeQuorum           = "quorum"
+	CompletionModeSuccessCondition = "successCondition"
+
+	ConcurrencyConflictQueue  = "queue"
+	ConcurrencyConflictReject = "reject"
+
+	// The policies for references to failed tasks (see TaskSpec.FailedReferencePolicy).
+	FailedReferencePropagate = "propagate-failure"
+	FailedReferenceUseNull   = "use-null"
+	FailedReferenceBlock     = "block"
+
+	// The modes of propagating the cancellation of an invocation to its sub-workflow invocations (see
+	// TaskSpec.CancelPropagation).
+	CancelPropagationCascade = "cascade"
+	CancelPropagationDetach  = "detach"
+
+	// The strategies for resolving conflicts between the sources of a merged input (see InputMerge.Conflicts).
+	MergeConflictLastWins = "last-wins"
+	MergeConflictError    = "error"
+
+	// The modes of handling references to undefined keys of the scope (see WorkflowSpec.ScopeStrictness).
+	ScopeStrictnessWarn = "warn"
+	ScopeStrictnessFail = "fail"
+
+	// DefaultBranch is the name under which the selection of the default branch of a switch is recorded.
+	DefaultBranch = "default"
+
+	// The types of the fields of a workflow contract.
+	ContractTypeString = "string"
+	ContractTypeNumber = "number"
+	ContractTypeBool   = "bool"
+	ContractTypeBytes  = "bytes"
+	ContractTypeMap    = "map"
+	ContractTypeList   = "list"
+
+	// LabelTenant is the invocation label that identifies the tenant that the invocation belongs to.
+	LabelTenant = "tenant"
+
+	// LabelPriority is the invocation label that contains the priority of the invocation: PriorityLow,
+	// PriorityNormal or PriorityHigh. Invocations without the label have a normal priority.
+	LabelPriority  = "priority"
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+
+	// LabelReplica is the invocation label that pins the invocation to the controller replica with the given ID, for
+	// debugging purposes. The label is ignored unless the controller replicas allow pinning.
+	LabelReplica = "debug.replica"
+
+	// LabelTrace is the invocation label that forces the trace of the invocation to be sampled when set to "true",
+	// regardless of the sampling rate of the controller.
+	LabelTrace = "debug.trace"
 )
 
 // InvocationEvent
@@ -44,6 +101,50 @@ func (m *Error) Error() string {
 	return m.Message
 }
 
+// ErrorCode classifies the cause of an Error.
+const (
+	// ErrorCodeFunction indicates that the function was executed, but returned an error (e.g. a 5xx response).
+	ErrorCodeFunction = "FUNCTION_ERROR"
+
+	// ErrorCodeRuntime indicates that the function could not be executed by the function runtime.
+	ErrorCodeRuntime = "RUNTIME_ERROR"
+
+	// ErrorCodeTimeout indicates that the task did not complete before its deadline.
+	ErrorCodeTimeout = "TIMEOUT"
+
+	// ErrorCodeOutput indicates that the output of the function could not be processed.
+	ErrorCodeOutput = "OUTPUT_ERROR"
+
+	// ErrorCodeMalformedOutput indicates that the response of the function could not be deserialized according to
+	// its content type. Tasks that failed with this error are not retried.
+	ErrorCodeMalformedOutput = "MALFORMED_OUTPUT"
+
+	// ErrorCodeAborted indicates that the task was abandoned by the workflow engine.
+	ErrorCodeAborted = "ABORTED"
+
+	// ErrorCodeInput indicates that the inputs of the task could not be resolved, such as inputs that reference
+	// undefined keys of the scope in a workflow with a strict scope.
+	ErrorCodeInput = "INPUT_ERROR"
+)
+
+// NewTaskError completes the error of a failed task execution, ensuring that all fields of the error schema are set.
+// If the error does not have a code, the defaultCode is used.
+func NewTaskError(err *Error, taskID string, attempt int32, defaultCode string) *Error {
+	result := &Error{}
+	if err != nil {
+		*result = *err
+	}
+	if len(result.Code) == 0 {
+		result.Code = defaultCode
+	}
+	if len(result.Message) == 0 {
+		result.Message = "unknown error"
+	}
+	result.TaskId = taskID
+	result.Attempt = attempt
+	return result
+}
+
 //
 // WorkflowInvocation
 //
@@ -107,6 +208,99 @@ func (m *WorkflowInvocation) Tasks() map[string]*Task {
 	return tasks
 }
 
+// JoinTrigger returns the dependency that triggered the task with a partial join (see TaskSpec.AwaitCount): the
+// dependency of which the success completed the join. Dependencies are ordered by the time at which they succeeded,
+// so for an any-of join this is the dependency that succeeded first. It returns false if the task does not have a
+// partial join, or if not enough of its dependencies have succeeded yet.
+func (m *WorkflowInvocation) JoinTrigger(taskID string) (string, bool) {
+	task, ok := m.Task(taskID)
+	if !ok || !task.GetSpec().IsPartialJoin() {
+		return "", false
+	}
+	var succeeded []string
+	for depID := range task.GetSpec().GetRequires() {
+		run, ok := m.TaskInvocation(depID)
+		if ok && run.GetStatus().GetStatus() == TaskInvocationStatus_SUCCEEDED {
+			succeeded = append(succeeded, depID)
+		}
+	}
+	await := int(task.GetSpec().AwaitCount())
+	if len(succeeded) < await {
+		return "", false
+	}
+	sort.Slice(succeeded, func(i, j int) bool {
+		ti := m.Status.Tasks[succeeded[i]].GetStatus().GetUpdatedAt()
+		tj := m.Status.Tasks[succeeded[j]].GetStatus().GetUpdatedAt()
+		if ti.GetSeconds() != tj.GetSeconds() {
+			return ti.GetSeconds() < tj.GetSeconds()
+		}
+		if ti.GetNanos() != tj.GetNanos() {
+			return ti.GetNanos() < tj.GetNanos()
+		}
+		return succeeded[i] < succeeded[j]
+	})
+	return succeeded[await-1], true
+}
+
+// HeldBySwitch returns whether the task belongs to a branch of a switch that has not been selected (yet). These tasks
+// should not be scheduled.
+func (m *WorkflowInvocation) HeldBySwitch(taskID string) bool {
+	switchID, branch, ok := m.Workflow().GetSpec().SwitchOf(taskID)
+	if !ok {
+		return false
+	}
+	selected, decided := m.GetStatus().GetBranches()[switchID]
+	return !decided || selected != branch
+}
+
+// Priority returns the effective priority of the invocation. The priority that was recorded in the status when the
+// invocation was created takes precedence; otherwise the priority is derived from the spec.
+func (m *WorkflowInvocation) Priority() string {
+	if priority := m.GetStatus().GetEffectivePriority(); len(priority) > 0 {
+		return priority
+	}
+	return m.GetSpec().EffectivePriority()
+}
+
+//
+// WorkflowInvocationSpec
+//
+
+// EffectivePriority returns the highest of the priority label of the invocation and the priority inherited from the
+// parent invocation, if any. Invocations without either are of normal priority.
+func (m *WorkflowInvocationSpec) EffectivePriority() string {
+	priority := m.GetLabels()[LabelPriority]
+	if parentPriority := m.GetParentPriority(); len(parentPriority) > 0 {
+		return MaxPriority(priority, parentPriority)
+	}
+	return MaxPriority(priority)
+}
+
+// MaxPriority returns the highest of the priorities. Empty or unknown priorities are considered to be normal.
+func MaxPriority(priorities ...string) string {
+	var max string
+	for i, priority := range priorities {
+		if i == 0 || priorityRank(priority) > priorityRank(max) {
+			max = priority
+		}
+	}
+	if max != PriorityLow && max != PriorityHigh {
+		return PriorityNormal
+	}
+	return max
+}
+
+func priorityRank(priority string) int {
+	switch priority {
+	case PriorityLow:
+		return 0
+	case PriorityHigh:
+		return 2
+	default:
+		return 1
+	}
+}
+
 //
 // WorkflowInvocationStatus
 //
@@ -164,6 +358,19 @@ func (m *TaskInvocation) Task() *Task {
 	return m.GetSpec().GetTask()
 }
 
+//
+// TaskInvocationSpec
+//
+
+// AttemptNumber returns the attempt of the task execution, starting at 1. Task runs that do not specify an attempt are
+// the first attempt.
+func (m *TaskInvocationSpec) AttemptNumber() int32 {
+	if m.GetAttempt() < 1 {
+		return 1
+	}
+	return m.GetAttempt()
+}
+
 //
 // TaskInvocationStatus
 //
@@ -215,6 +422,30 @@ func (m *TaskSpec) Parent() (string, bool) {
 	return parent, present
 }
 
+// AwaitCount returns the number of dependencies that need to have succeeded before the task can be started. Unless
+// Await is set to a lower number, the task awaits all of its dependencies.
+func (m *TaskSpec) AwaitCount() int32 {
+	if m.GetAwait() <= 0 || int(m.GetAwait()) > len(m.GetRequires()) {
+		return int32(len(m.GetRequires()))
+	}
+	return m.GetAwait()
+}
+
+// IsPartialJoin returns whether the task can be started before all of its dependencies have succeeded; for example,
+// an any-of join, which only awaits the first dependency to succeed.
+func (m *TaskSpec) IsPartialJoin() bool {
+	return m.AwaitCount() < int32(len(m.GetRequires()))
+}
+
+// InputKey returns the key of the task input to which the transformed output of the dependency is bound: the alias of
+// the dependency if set, or the ID of the dependency otherwise.
+func (m *TaskDependencyParameters) InputKey(dependencyID string) string {
+	if len(m.GetAlias()) > 0 {
+		return m.GetAlias()
+	}
+	return dependencyID
+}
+
 func (m *TaskSpec) Require(taskID string, opts ...*TaskDependencyParameters) *TaskSpec {
 	if m.Requires == nil {
 		m.Requires = map[string]*TaskDependencyParameters{}
@@ -228,6 +459,78 @@ func (m *TaskSpec) Require(taskID string, opts ...*TaskDependencyParameters) *Ta
 	return m
 }
 
+// MaxAttempts returns the maximum number of times that the task is executed according to its retry policy, including
+// the first attempt.
+func (m *TaskSpec) MaxAttempts() int32 {
+	if m.GetRetry().GetMaxAttempts() < 1 {
+		return 1
+	}
+	return m.GetRetry().GetMaxAttempts()
+}
+
+// FailsOver returns whether the attempt of the task is executed by its failover function: the attempts after the
+// configured number of failed attempts of the primary function.
+func (m *TaskSpec) FailsOver(attempt int32) bool {
+	failover := m.GetFailover()
+	if failover == nil {
+		return false
+	}
+	after := failover.GetAfter()
+	if after < 1 {
+		after = 1
+	}
+	return attempt > after
+}
+
+// FailedReferencePolicyFor returns the policy of the task for a reference to the task with the id, once that task has
+// failed: the policy for the specific reference, or else the policy of the task, which defaults to
+// FailedReferencePropagate.
+func (m *TaskSpec) FailedReferencePolicyFor(taskID string) string {
+	if policy, ok := m.GetFailedReferencePolicies()[taskID]; ok && len(policy) > 0 {
+		return policy
+	}
+	if policy := m.GetFailedReferencePolicy(); len(policy) > 0 {
+		return policy
+	}
+	return FailedReferencePropagate
+}
+
+// CancelPropagationMode returns whether the cancellation of the invocation is propagated to the sub-workflow
+// invocations started by the task, which defaults to CancelPropagationCascade.
+func (m *TaskSpec) CancelPropagationMode() string {
+	if mode := m.GetCancelPropagation(); len(mode) > 0 {
+		return mode
+	}
+	return CancelPropagationCascade
+}
+
+// EffectiveMergeInputs returns the input merges of the task that are not overridden by an explicit input of the task.
+func (m *TaskSpec) EffectiveMergeInputs() map[string]*InputMerge {
+	merges := map[string]*InputMerge{}
+	for key, merge := range m.GetMergeInputs() {
+		if _, ok := m.GetInputs()[key]; !ok {
+			merges[key] = merge
+		}
+	}
+	return merges
+}
+
+// MergeSources returns the sources of the effective input merges of the task, ordered by the key of the input and the
+// order of the sources within the merge.
+func (m *TaskSpec) MergeSources() []*typedvalues.TypedValue {
+	merges := m.EffectiveMergeInputs()
+	keys := make([]string, 0, len(merges))
+	for key := range merges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var sources []*typedvalues.TypedValue
+	for _, key := range keys {
+		sources = append(sources, merges[key].GetSources()...)
+	}
+	return sources
+}
+
 //
 //func (m *TaskSpec) Overlay(overlay *TaskSpec) *TaskSpec {
 //	nt := proto.Clone(m).(*TaskSpec)
@@ -362,6 +665,47 @@ func (m *WorkflowSpec) TaskSpec(taskID string) *TaskSpec {
 	return tasks[taskID]
 }
 
+// SwitchOf returns the switch and the name of the branch that the task belongs to, if any.
+func (m *WorkflowSpec) SwitchOf(taskID string) (switchID string, branch string, ok bool) {
+	for id, sw := range m.GetSwitches() {
+		for name, b := range sw.AllBranches() {
+			for _, t := range b.GetTasks() {
+				if t == taskID {
+					return id, name, true
+				}
+			}
+		}
+	}
+	return "", "", false
+}
+
+//
+// Switch
+//
+
+// AllBranches returns the branches of the switch, including the default branch under the DefaultBranch name.
+func (m *Switch) AllBranches() map[string]*Branch {
+	branches := make(map[string]*Branch, len(m.GetBranches())+1)
+	for name, b := range m.GetBranches() {
+		branches[name] = b
+	}
+	if m.GetDefault() != nil {
+		branches[DefaultBranch] = m.GetDefault()
+	}
+	return branches
+}
+
+// Select returns the name of the branch that matches the value, falling back to the default branch.
+func (m *Switch) Select(value string) (string, bool) {
+	if _, ok := m.GetBranches()[value]; ok {
+		return value, true
+	}
+	if m.GetDefault() != nil {
+		return DefaultBranch, true
+	}
+	return "", false
+}
+
 //
 // WorkflowStatus
 //
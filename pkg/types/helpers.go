@@ -1,11 +1,13 @@
 package types
 
 import (
+	"math"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/util"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 )
 
 func NewWorkflow(id string) *Workflow {
@@ -106,30 +108,102 @@ type NamedTypedValue struct {
 type Inputs map[string]*typedvalues.TypedValue
 
 func NewTaskInvocationSpec(invocation *WorkflowInvocation, task *Task, startAt time.Time) *TaskInvocationSpec {
-	// Decide on the deadline of the task invocation.
-	// If there is no timeout specified for the task, use the deadline of the overall workflow invocation.
-	// If there is a timeout specified for the task, calculate the deadline using the startAt + timout time, but do not
-	// exceed the invocation deadline.
-	deadline := invocation.GetSpec().GetDeadline()
-	if task.GetSpec().GetTimeout() != nil {
-		deadlineTime, err := ptypes.Timestamp(deadline)
-		maxRuntime, err := ptypes.Duration(task.GetSpec().GetTimeout())
-		if err == nil {
-			taskMaxRuntime := startAt.Add(maxRuntime)
-			if taskMaxRuntime.Before(deadlineTime) {
-				deadline, _ = ptypes.TimestampProto(taskMaxRuntime)
-			}
-		}
-	}
-
 	return &TaskInvocationSpec{
 		InvocationId: invocation.ID(),
 		Task:         task,
 		FnRef:        task.GetStatus().GetFnRef(),
 		TaskId:       task.ID(),
-		Deadline:     deadline,
+		Deadline:     TaskDeadline(invocation, task, startAt),
 		Inputs:       task.GetSpec().GetInputs(),
+		ExecutorType: task.GetSpec().GetExecutorType(),
+		Priority:     invocation.Priority(),
+	}
+}
+
+// TaskDeadline derives the deadline of a task run started at startAt. The task run is given the time remaining until
+// the deadline of the invocation, or the timeout of the task if that is smaller, which ensures that a slow task
+// cannot prevent the invocation from finishing by its deadline. If neither is specified, nil is returned.
+func TaskDeadline(invocation *WorkflowInvocation, task *Task, startAt time.Time) *timestamp.Timestamp {
+	return TaskDeadlineWithin(invocation.GetSpec().GetDeadline(), task, startAt)
+}
+
+// TaskDeadlineWithin derives the deadline of a task run started at startAt, like TaskDeadline, but within the provided
+// deadline of the invocation rather than the one in its spec.
+func TaskDeadlineWithin(deadline *timestamp.Timestamp, task *Task, startAt time.Time) *timestamp.Timestamp {
+	if task.GetSpec().GetTimeout() == nil {
+		return deadline
+	}
+	maxRuntime, err := ptypes.Duration(task.GetSpec().GetTimeout())
+	if err != nil {
+		return deadline
+	}
+	taskDeadline := startAt.Add(maxRuntime)
+	if deadline != nil {
+		invocationDeadline, err := ptypes.Timestamp(deadline)
+		if err == nil && !taskDeadline.Before(invocationDeadline) {
+			return deadline
+		}
+	}
+	ts, err := ptypes.TimestampProto(taskDeadline)
+	if err != nil {
+		return deadline
+	}
+	return ts
+}
+
+// RetryDeadline returns the time after which the task is no longer retried, based on the total timeout of its retry
+// policy and the start of its first attempt. If the task has no total timeout, nil is returned.
+func RetryDeadline(task *Task, firstAttemptAt *timestamp.Timestamp) *timestamp.Timestamp {
+	if task.GetSpec().GetRetry().GetTotalTimeout() == nil || firstAttemptAt == nil {
+		return nil
+	}
+	totalTimeout, err := ptypes.Duration(task.GetSpec().GetRetry().GetTotalTimeout())
+	if err != nil {
+		return nil
+	}
+	startedAt, err := ptypes.Timestamp(firstAttemptAt)
+	if err != nil {
+		return nil
+	}
+	ts, err := ptypes.TimestampProto(startedAt.Add(totalTimeout))
+	if err != nil {
+		return nil
+	}
+	return ts
+}
+
+// DefaultBackoffMultiplier is the factor by which the delay between the retries of a task grows if its retry policy
+// does not specify one.
+const DefaultBackoffMultiplier = 2
+
+// DefaultMaxRetryDelay caps the delay between the retries of a task if its retry policy does not specify a max delay.
+const DefaultMaxRetryDelay = 5 * time.Minute
+
+// RetryDelay returns the delay before the given (1-based) retry of the task: the initial delay of its retry policy,
+// multiplied by the backoff multiplier for every preceding retry, and capped by the max delay (DefaultMaxRetryDelay if
+// not set). If the retry policy has no initial delay, the task is retried without delay.
+func RetryDelay(task *Task, retry int32) time.Duration {
+	policy := task.GetSpec().GetRetry()
+	initialDelay, err := ptypes.Duration(policy.GetInitialDelay())
+	if err != nil || initialDelay <= 0 {
+		return 0
+	}
+	multiplier := policy.GetBackoffMultiplier()
+	if multiplier <= 0 {
+		multiplier = DefaultBackoffMultiplier
+	}
+	maxDelay, err := ptypes.Duration(policy.GetMaxDelay())
+	if err != nil || maxDelay <= 0 {
+		maxDelay = DefaultMaxRetryDelay
+	}
+	if retry < 1 {
+		retry = 1
+	}
+	delay := float64(initialDelay) * math.Pow(multiplier, float64(retry-1))
+	if delay >= float64(maxDelay) {
+		return maxDelay
 	}
+	return time.Duration(delay)
 }
 
 func Input(val interface{}) map[string]*typedvalues.TypedValue {
@@ -1,9 +1,11 @@
 package types
 
 import (
+	"math"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +68,99 @@ func TestCalculateWorkflowWithDynamicTasks(t *testing.T) {
 	assert.Equal(t, 0, len(cwf["foo"].Spec.Requires))
 	assert.Equal(t, int32(42), cwf["bar2"].Spec.Await)
 }
+
+func TestNewTaskError(t *testing.T) {
+	err := NewTaskError(&Error{Code: ErrorCodeTimeout, Message: "deadline exceeded"}, "foo", 1, ErrorCodeRuntime)
+	assert.Equal(t, &Error{Code: ErrorCodeTimeout, Message: "deadline exceeded", TaskId: "foo", Attempt: 1}, err)
+
+	err = NewTaskError(nil, "foo", 2, ErrorCodeFunction)
+	assert.Equal(t, &Error{Code: ErrorCodeFunction, Message: "unknown error", TaskId: "foo", Attempt: 2}, err)
+}
+
+func TestTaskDeadline(t *testing.T) {
+	now := time.Now()
+	invocation := NewWorkflowInvocation("wf-1", "wfi-1", now.Add(time.Minute))
+	task := &Task{Metadata: NewObjectMetadata("t1"), Spec: &TaskSpec{}}
+
+	// Without a timeout, the task inherits the deadline of the invocation.
+	assert.Equal(t, invocation.GetSpec().GetDeadline(), TaskDeadline(invocation, task, now))
+
+	// With a smaller timeout, the timeout determines the deadline.
+	task.Spec.Timeout = ptypes.DurationProto(10 * time.Second)
+	deadline, err := ptypes.Timestamp(TaskDeadline(invocation, task, now))
+	assert.NoError(t, err)
+	assert.True(t, deadline.Equal(now.Add(10*time.Second)))
+
+	// The deadline never exceeds the remaining time of the invocation.
+	deadline, err = ptypes.Timestamp(TaskDeadline(invocation, task, now.Add(55*time.Second)))
+	assert.NoError(t, err)
+	assert.True(t, deadline.Equal(now.Add(time.Minute)))
+
+	// Without an invocation deadline, the timeout still applies.
+	invocation.Spec.Deadline = nil
+	deadline, err = ptypes.Timestamp(TaskDeadline(invocation, task, now))
+	assert.NoError(t, err)
+	assert.True(t, deadline.Equal(now.Add(10*time.Second)))
+}
+
+func TestRetryDeadline(t *testing.T) {
+	now := time.Now()
+	firstAttemptAt, _ := ptypes.TimestampProto(now)
+	task := &Task{Metadata: NewObjectMetadata("t1"), Spec: &TaskSpec{Retry: &RetryPolicy{MaxAttempts: 3}}}
+
+	// Without a total timeout, the retries are not limited in time.
+	assert.Nil(t, RetryDeadline(task, firstAttemptAt))
+
+	// The retry deadline is relative to the first attempt.
+	task.Spec.Retry.TotalTimeout = ptypes.DurationProto(time.Minute)
+	deadline, err := ptypes.Timestamp(RetryDeadline(task, firstAttemptAt))
+	assert.NoError(t, err)
+	assert.True(t, deadline.Equal(now.Add(time.Minute)))
+	assert.Nil(t, RetryDeadline(task, nil))
+}
+
+func TestRetryDelay(t *testing.T) {
+	task := &Task{Metadata: NewObjectMetadata("t1"), Spec: &TaskSpec{Retry: &RetryPolicy{MaxAttempts: 5}}}
+
+	// Without an initial delay, the task is retried immediately.
+	assert.Equal(t, time.Duration(0), RetryDelay(task, 1))
+
+	// By default, the delay doubles with every retry.
+	task.Spec.Retry.InitialDelay = ptypes.DurationProto(time.Second)
+	assert.Equal(t, time.Second, RetryDelay(task, 1))
+	assert.Equal(t, 2*time.Second, RetryDelay(task, 2))
+	assert.Equal(t, 4*time.Second, RetryDelay(task, 3))
+
+	task.Spec.Retry.BackoffMultiplier = 3
+	task.Spec.Retry.MaxDelay = ptypes.DurationProto(5 * time.Second)
+	assert.Equal(t, 3*time.Second, RetryDelay(task, 2))
+	assert.Equal(t, 5*time.Second, RetryDelay(task, 3))
+	assert.Equal(t, 5*time.Second, RetryDelay(task, 100))
+
+	// Without a max delay, the delay is capped by default, also once the backoff overflows.
+	task.Spec.Retry.MaxDelay = nil
+	assert.Equal(t, DefaultMaxRetryDelay, RetryDelay(task, 10))
+	assert.Equal(t, DefaultMaxRetryDelay, RetryDelay(task, 1000))
+	assert.Equal(t, DefaultMaxRetryDelay, RetryDelay(task, math.MaxInt32))
+}
+
+func TestNewTaskInvocationSpec_Priority(t *testing.T) {
+	invocation := NewWorkflowInvocation("wf-1", "wfi-1", time.Now().Add(time.Minute))
+	task := &Task{Metadata: NewObjectMetadata("t1"), Spec: &TaskSpec{}}
+	assert.Equal(t, PriorityNormal, NewTaskInvocationSpec(invocation, task, time.Now()).GetPriority())
+
+	// The task runs with the highest of the priority of the invocation and the priority of its parent.
+	invocation.Spec.Labels = map[string]string{LabelPriority: PriorityLow}
+	assert.Equal(t, PriorityLow, NewTaskInvocationSpec(invocation, task, time.Now()).GetPriority())
+	invocation.Spec.ParentPriority = PriorityHigh
+	assert.Equal(t, PriorityHigh, NewTaskInvocationSpec(invocation, task, time.Now()).GetPriority())
+}
+
+func TestMaxPriority(t *testing.T) {
+	assert.Equal(t, PriorityNormal, MaxPriority())
+	assert.Equal(t, PriorityNormal, MaxPriority(""))
+	assert.Equal(t, PriorityLow, MaxPriority(PriorityLow, PriorityLow))
+	assert.Equal(t, PriorityNormal, MaxPriority(PriorityLow, ""))
+	assert.Equal(t, PriorityNormal, MaxPriority("urgent", PriorityLow))
+	assert.Equal(t, PriorityHigh, MaxPriority(PriorityLow, PriorityHigh, ""))
+}
@@ -0,0 +1,104 @@
+package typedvalues
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// The supported algorithms of content hashes.
+const (
+	HashSHA256 = "sha256"
+	HashSHA512 = "sha512"
+)
+
+var hashAlgorithms = map[string]func() hash.Hash{
+	HashSHA256: sha256.New,
+	HashSHA512: sha512.New,
+}
+
+// HashAlgorithms returns the sorted names of the supported algorithms of content hashes.
+func HashAlgorithms() []string {
+	var algorithms []string
+	for algorithm := range hashAlgorithms {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Strings(algorithms)
+	return algorithms
+}
+
+// ValidateHashAlgorithm returns an error if the algorithm is not a supported algorithm of content hashes.
+func ValidateHashAlgorithm(algorithm string) error {
+	if _, ok := hashAlgorithms[algorithm]; !ok {
+		return fmt.Errorf("unknown hash algorithm '%s' (supported: %s)", algorithm,
+			strings.Join(HashAlgorithms(), ", "))
+	}
+	return nil
+}
+
+// ContentHash computes the hash of the canonical form of the value (see Canonical) with the algorithm, formatted as
+// '<algorithm>:<hex-encoded digest>'. Values that are logically identical have the same content hash, regardless of
+// their metadata or of the order in which their maps were wrapped.
+func ContentHash(tv *TypedValue, algorithm string) (string, error) {
+	newHash, ok := hashAlgorithms[algorithm]
+	if !ok {
+		return "", ValidateHashAlgorithm(algorithm)
+	}
+	data, err := Canonical(tv)
+	if err != nil {
+		return "", err
+	}
+	h := newHash()
+	h.Write(data)
+	return algorithm + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashInputs computes the SHA-256 digest of a set of named values, such as the inputs of a task, which is independent
+// of the order of the values and of the order in which their maps were wrapped.
+func HashInputs(inputs map[string]*TypedValue) (string, error) {
+	keys := make([]string, 0, len(inputs))
+	for key := range inputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		data, err := Canonical(inputs[key])
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Canonical encodes the value independently of the order of the entries of its maps. The serialized form of a typed
+// value cannot be used for this, as it depends on the order in which the maps were iterated when wrapping them.
+//
+// Values that can be represented as JSON are encoded as their type followed by their JSON encoding, of which the
+// object keys are sorted. Other values are encoded with the deterministic Protobuf serialization.
+func Canonical(tv *TypedValue) ([]byte, error) {
+	if i, err := Unwrap(tv); err == nil {
+		// encoding/json sorts the keys of maps.
+		if data, err := json.Marshal(i); err == nil {
+			return append([]byte(tv.ValueType()+":"), data...), nil
+		}
+	}
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(tv); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
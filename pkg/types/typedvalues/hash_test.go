@@ -0,0 +1,71 @@
+package typedvalues
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentHash(t *testing.T) {
+	a := MustWrap(map[string]interface{}{
+		"b": 1,
+		"a": []interface{}{"x", map[string]interface{}{"d": true, "c": nil}},
+	})
+	b := MustWrap(map[string]interface{}{
+		"a": []interface{}{"x", map[string]interface{}{"c": nil, "d": true}},
+		"b": 1,
+	})
+	hashA, err := ContentHash(a, HashSHA256)
+	assert.NoError(t, err)
+	hashB, err := ContentHash(b, HashSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, hashA, hashB)
+	assert.True(t, strings.HasPrefix(hashA, HashSHA256+":"))
+	assert.Len(t, hashA, len(HashSHA256)+1+64)
+
+	// The metadata of a value is not part of its content.
+	a.SetMetadata("source", "test")
+	hashWithMetadata, err := ContentHash(a, HashSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, hashA, hashWithMetadata)
+
+	other, err := ContentHash(MustWrap(map[string]interface{}{"b": 2}), HashSHA256)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hashA, other)
+
+	// The type of a value is part of its content.
+	str, err := ContentHash(MustWrap("1"), HashSHA256)
+	assert.NoError(t, err)
+	num, err := ContentHash(MustWrap(1), HashSHA256)
+	assert.NoError(t, err)
+	assert.NotEqual(t, str, num)
+
+	sha512Hash, err := ContentHash(a, HashSHA512)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sha512Hash, HashSHA512+":"))
+
+	_, err = ContentHash(a, "md5")
+	assert.Error(t, err)
+}
+
+func TestHashInputs(t *testing.T) {
+	a, err := HashInputs(map[string]*TypedValue{
+		"foo": MustWrap("bar"),
+		"baz": MustWrap(map[string]interface{}{"a": 1, "b": 2}),
+	})
+	assert.NoError(t, err)
+	b, err := HashInputs(map[string]*TypedValue{
+		"baz": MustWrap(map[string]interface{}{"b": 2, "a": 1}),
+		"foo": MustWrap("bar"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	c, err := HashInputs(map[string]*TypedValue{
+		"foo": MustWrap("bar"),
+		"baz": MustWrap(map[string]interface{}{"a": 1, "b": 3}),
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, c)
+}
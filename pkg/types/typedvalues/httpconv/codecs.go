@@ -0,0 +1,218 @@
+package httpconv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/util/mediatype"
+	"github.com/pkg/errors"
+)
+
+var (
+	MediaTypeForm = mediatype.MustParse("application/x-www-form-urlencoded")
+	MediaTypeCSV  = mediatype.MustParse("text/csv")
+
+	formMapper = &FormMapper{}
+	csvMapper  = &CSVMapper{}
+
+	// codecs contains the ParserFormatters registered by media type identifier (e.g. "text/csv"). These take
+	// precedence over the built-in heuristics of the DefaultHTTPMapper.
+	codecs   = map[string]ParserFormatter{}
+	codecsMu = &sync.RWMutex{}
+)
+
+func init() {
+	RegisterCodec(MediaTypeForm.Identifier(), formMapper)
+	RegisterCodec(MediaTypeCSV.Identifier(), csvMapper)
+}
+
+// RegisterCodec registers the codec used to parse and format values of the media type, identified by its type and
+// subtype (e.g. "application/xml"). A codec registered earlier for the same media type is replaced.
+func RegisterCodec(mediaType string, codec ParserFormatter) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[mediaType] = codec
+}
+
+// LookupCodec returns the codec registered for the media type, if any.
+func LookupCodec(mt *mediatype.MediaType) (ParserFormatter, bool) {
+	if mt == nil {
+		return nil, false
+	}
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[mt.Identifier()]
+	return codec, ok
+}
+
+// FormMapper maps maps of values to and from form-encoded (application/x-www-form-urlencoded) bodies. Fields with a
+// single value are parsed as strings, whereas fields with multiple values are parsed as lists of strings.
+type FormMapper struct{}
+
+func (m *FormMapper) Format(w http.ResponseWriter, body *typedvalues.TypedValue) error {
+	i, err := typedvalues.Unwrap(body)
+	if err != nil {
+		return err
+	}
+	fields, ok := i.(map[string]interface{})
+	if !ok && i != nil {
+		return errors.Wrapf(typedvalues.ErrUnsupportedType, "cannot format %s to a form", body.ValueType())
+	}
+	values := url.Values{}
+	for k, v := range fields {
+		if vs, ok := v.([]interface{}); ok {
+			for _, e := range vs {
+				values.Add(k, fmt.Sprintf("%v", e))
+			}
+			continue
+		}
+		values.Set(k, fmt.Sprintf("%v", v))
+	}
+	_, err = w.Write([]byte(values.Encode()))
+	if err != nil {
+		return err
+	}
+	mediatype.SetContentTypeHeader(MediaTypeForm, w)
+	return nil
+}
+
+func (m *FormMapper) Parse(mt *mediatype.MediaType, reader io.Reader) (*typedvalues.TypedValue, error) {
+	bs, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	values, err := url.ParseQuery(string(bs))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	fields := map[string]interface{}{}
+	for k, vs := range values {
+		if len(vs) == 1 {
+			fields[k] = vs[0]
+			continue
+		}
+		var list []interface{}
+		for _, v := range vs {
+			list = append(list, v)
+		}
+		fields[k] = list
+	}
+	return typedvalues.Wrap(fields)
+}
+
+// CSVMapper maps lists of records to and from CSV (text/csv) bodies. The records are parsed as lists of strings.
+// Records can be formatted either from lists, or from maps, in which case a header row with the (sorted) keys of the
+// maps is added.
+type CSVMapper struct{}
+
+func (m *CSVMapper) Format(w http.ResponseWriter, body *typedvalues.TypedValue) error {
+	i, err := typedvalues.Unwrap(body)
+	if err != nil {
+		return err
+	}
+	rows, ok := i.([]interface{})
+	if !ok && i != nil {
+		return errors.Wrapf(typedvalues.ErrUnsupportedType, "cannot format %s to CSV", body.ValueType())
+	}
+	records, err := csvRecords(rows)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	mediatype.SetContentTypeHeader(MediaTypeCSV, w)
+	return nil
+}
+
+func (m *CSVMapper) Parse(mt *mediatype.MediaType, reader io.Reader) (*typedvalues.TypedValue, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	rows := make([]interface{}, len(records))
+	for i, record := range records {
+		row := make([]interface{}, len(record))
+		for j, field := range record {
+			row[j] = field
+		}
+		rows[i] = row
+	}
+	return typedvalues.Wrap(rows)
+}
+
+// csvRecords converts the rows, either all lists or all maps, to CSV records.
+func csvRecords(rows []interface{}) ([][]string, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	if _, ok := rows[0].(map[string]interface{}); !ok {
+		var records [][]string
+		for _, row := range rows {
+			fields, ok := row.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot format row of type %T to CSV", row)
+			}
+			record := make([]string, len(fields))
+			for j, field := range fields {
+				record[j] = fmt.Sprintf("%v", field)
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	}
+
+	// Use the union of the keys of all rows as the header.
+	keys := map[string]bool{}
+	for _, row := range rows {
+		fields, ok := row.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot format row of type %T to CSV", row)
+		}
+		for k := range fields {
+			keys[k] = true
+		}
+	}
+	var header []string
+	for k := range keys {
+		header = append(header, k)
+	}
+	sort.Strings(header)
+	records := [][]string{header}
+	for _, row := range rows {
+		fields := row.(map[string]interface{})
+		record := make([]string, len(header))
+		for j, k := range header {
+			if v, ok := fields[k]; ok && v != nil {
+				record[j] = fmt.Sprintf("%v", v)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// WithContentType returns the inputs with the content type that the body should be formatted with, unless the inputs
+// already specify a content type themselves. The provided inputs are not modified.
+func WithContentType(inputs map[string]*typedvalues.TypedValue, contentType string) map[string]*typedvalues.TypedValue {
+	if len(contentType) == 0 {
+		return inputs
+	}
+	if _, err := DefaultHTTPMapper.findAndParseContentType(inputs); err == nil {
+		return inputs
+	}
+	result := make(map[string]*typedvalues.TypedValue, len(inputs)+1)
+	for k, v := range inputs {
+		result[k] = v
+	}
+	result[inputContentType] = typedvalues.MustWrap(contentType)
+	return result
+}
@@ -0,0 +1,88 @@
+package httpconv
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/util/mediatype"
+	"github.com/stretchr/testify/assert"
+)
+
+func formatBody(t *testing.T, body interface{}, contentType string) (string, http.Header) {
+	reqURL, _ := url.Parse("http://bar.example")
+	req := &http.Request{URL: reqURL, Header: http.Header{}}
+	inputs := WithContentType(map[string]*typedvalues.TypedValue{
+		types.InputMain: typedvalues.MustWrap(body),
+	}, contentType)
+	assert.NoError(t, FormatRequest(inputs, req))
+	bs, err := ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	return string(bs), req.Header
+}
+
+func TestFormMapper(t *testing.T) {
+	body, headers := formatBody(t, map[string]interface{}{
+		"name": "foo",
+		"tags": []interface{}{"a", "b"},
+	}, "application/x-www-form-urlencoded")
+	assert.Equal(t, "name=foo&tags=a&tags=b", body)
+	assert.Equal(t, MediaTypeForm.String(), headers.Get(headerContentType))
+
+	tv, err := formMapper.Parse(MediaTypeForm, strings.NewReader(body))
+	assert.NoError(t, err)
+	i, err := typedvalues.Unwrap(tv)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name": "foo",
+		"tags": []interface{}{"a", "b"},
+	}, i)
+}
+
+func TestCSVMapper(t *testing.T) {
+	body, headers := formatBody(t, []interface{}{
+		map[string]interface{}{"id": 1, "name": "foo"},
+		map[string]interface{}{"id": 2},
+	}, "text/csv")
+	assert.Equal(t, "id,name\n1,foo\n2,\n", body)
+	assert.Equal(t, MediaTypeCSV.String(), headers.Get(headerContentType))
+
+	tv, err := csvMapper.Parse(MediaTypeCSV, strings.NewReader(body))
+	assert.NoError(t, err)
+	i, err := typedvalues.Unwrap(tv)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		[]interface{}{"id", "name"},
+		[]interface{}{"1", "foo"},
+		[]interface{}{"2", ""},
+	}, i)
+}
+
+func TestRegisterCodec(t *testing.T) {
+	mt := mediatype.MustParse("application/x-test")
+	_, ok := LookupCodec(mt)
+	assert.False(t, ok)
+
+	RegisterCodec(mt.Identifier(), textMapper)
+	codec, ok := LookupCodec(mediatype.MustParse("application/x-test; charset=utf-8"))
+	assert.True(t, ok)
+	assert.Equal(t, textMapper, codec)
+	assert.Equal(t, textMapper, DefaultHTTPMapper.MediaTypeResolver(mt))
+}
+
+func TestWithContentType(t *testing.T) {
+	inputs := map[string]*typedvalues.TypedValue{
+		types.InputMain: typedvalues.MustWrap("foo"),
+	}
+	assert.Len(t, WithContentType(inputs, ""), 1)
+	assert.Len(t, WithContentType(inputs, "text/csv"), 2)
+	assert.Len(t, inputs, 1)
+
+	// Content types specified by the inputs take precedence.
+	inputs[types.InputHeaders] = typedvalues.MustWrap(map[string]interface{}{"Content-Type": "text/plain"})
+	assert.Len(t, WithContentType(inputs, "text/csv"), 2)
+}
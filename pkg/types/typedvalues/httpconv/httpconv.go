@@ -61,6 +61,11 @@ var DefaultHTTPMapper = &HTTPMapper{
 			return bytesMapper
 		}
 
+		// Prefer the codecs registered for the media type
+		if codec, ok := LookupCodec(mt); ok {
+			return codec
+		}
+
 		// Choose the mapper based on some hard-coded heuristics
 		switch mt.Identifier() {
 		case MediaTypeJSON.String(), "text/json":
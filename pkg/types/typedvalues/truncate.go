@@ -0,0 +1,60 @@
+package typedvalues
+
+import (
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// MetadataTruncated is set to "true" in the metadata of a value that has been truncated.
+	MetadataTruncated = "truncated"
+
+	// MetadataSize contains the size (in bytes) of the serialized value before it was truncated.
+	MetadataSize = "size"
+)
+
+// Truncate returns a preview of the value if its serialized size exceeds maxBytes, or the value itself otherwise. The
+// preview is a string containing at most maxBytes bytes of the value: the value itself for strings, and its JSON
+// encoding for other values. The preview retains the metadata of the value, along with the MetadataTruncated and
+// MetadataSize entries. The value itself is never modified.
+func Truncate(tv *TypedValue, maxBytes int) (truncated *TypedValue, ok bool) {
+	if tv == nil || maxBytes < 0 {
+		return tv, false
+	}
+	size := proto.Size(tv)
+	if size <= maxBytes {
+		return tv, false
+	}
+
+	var preview string
+	if s, err := UnwrapString(tv); err == nil {
+		preview = s
+	} else if i, err := Unwrap(tv); err == nil {
+		if data, err := json.Marshal(i); err == nil {
+			preview = string(data)
+		}
+	}
+	if len(preview) > maxBytes {
+		preview = preview[:maxBytes]
+		// Avoid cutting a multi-byte character in half.
+		for len(preview) > 0 && !utf8.ValidString(preview) {
+			preview = preview[:len(preview)-1]
+		}
+	}
+
+	truncated = MustWrap(preview)
+	for k, v := range tv.GetMetadata() {
+		truncated.SetMetadata(k, v)
+	}
+	truncated.SetMetadata(MetadataTruncated, "true")
+	truncated.SetMetadata(MetadataSize, strconv.Itoa(size))
+	return truncated, true
+}
+
+// IsTruncated returns true if the value is a preview of a value that has been truncated (see Truncate).
+func IsTruncated(tv *TypedValue) bool {
+	return tv.GetMetadata()[MetadataTruncated] == "true"
+}
@@ -0,0 +1,45 @@
+package typedvalues
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	small := MustWrap("foo")
+	tv, ok := Truncate(small, 100)
+	assert.False(t, ok)
+	assert.Equal(t, small, tv)
+
+	large := MustWrap(strings.Repeat("a", 1000))
+	large.SetMetadata("source", "test")
+	tv, ok = Truncate(large, 10)
+	assert.True(t, ok)
+	assert.Equal(t, strings.Repeat("a", 10), MustUnwrap(tv))
+	assert.True(t, IsTruncated(tv))
+	assert.Equal(t, strconv.Itoa(proto.Size(large)), tv.GetMetadata()[MetadataSize])
+	assert.Equal(t, "test", tv.GetMetadata()["source"])
+
+	// The original value is left intact.
+	assert.Equal(t, strings.Repeat("a", 1000), MustUnwrap(large))
+	assert.False(t, IsTruncated(large))
+
+	// Other values are previewed as JSON.
+	tv, ok = Truncate(MustWrap(map[string]interface{}{"key": strings.Repeat("b", 100)}), 10)
+	assert.True(t, ok)
+	assert.Equal(t, `{"key":"bb`, MustUnwrap(tv))
+
+	// Multi-byte characters are not cut in half.
+	tv, ok = Truncate(MustWrap(strings.Repeat("é", 100)), 5)
+	assert.True(t, ok)
+	assert.Equal(t, "éé", MustUnwrap(tv))
+
+	// A negative maximum disables the truncation.
+	tv, ok = Truncate(large, -1)
+	assert.False(t, ok)
+	assert.Equal(t, large, tv)
+}
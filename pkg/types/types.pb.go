@@ -11,12 +11,21 @@ It has these top-level messages:
 	Workflow
 	WorkflowSpec
 	WorkflowStatus
+	WorkflowQuarantine
 	WorkflowInvocation
 	WorkflowInvocationSpec
 	WorkflowInvocationStatus
+	ScopeWarning
+	RetryBudgetStatus
 	DependencyConfig
 	Task
 	TaskSpec
+	RedactionRule
+	RetryPolicy
+	Failover
+	RateLimit
+	TaskSLO
+	TaskThrottle
 	TaskStatus
 	TaskDependencyParameters
 	TaskInvocation
@@ -27,6 +36,13 @@ It has these top-level messages:
 	FnRef
 	TypedValueMap
 	TypedValueList
+	CompletionPolicy
+	ConcurrencyPolicy
+	Switch
+	Branch
+	ContractField
+	WorkflowContract
+	InputMerge
 */
 package types
 
@@ -107,7 +123,7 @@ func (x WorkflowInvocationStatus_Status) String() string {
 	return proto.EnumName(WorkflowInvocationStatus_Status_name, int32(x))
 }
 func (WorkflowInvocationStatus_Status) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{5, 0}
+	return fileDescriptor0, []int{6, 0}
 }
 
 type TaskStatus_Status int32
@@ -132,7 +148,7 @@ var TaskStatus_Status_value = map[string]int32{
 func (x TaskStatus_Status) String() string {
 	return proto.EnumName(TaskStatus_Status_name, int32(x))
 }
-func (TaskStatus_Status) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{9, 0} }
+func (TaskStatus_Status) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{18, 0} }
 
 type TaskDependencyParameters_DependencyType int32
 
@@ -157,7 +173,7 @@ func (x TaskDependencyParameters_DependencyType) String() string {
 	return proto.EnumName(TaskDependencyParameters_DependencyType_name, int32(x))
 }
 func (TaskDependencyParameters_DependencyType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{10, 0}
+	return fileDescriptor0, []int{19, 0}
 }
 
 type TaskInvocationStatus_Status int32
@@ -195,7 +211,7 @@ func (x TaskInvocationStatus_Status) String() string {
 	return proto.EnumName(TaskInvocationStatus_Status_name, int32(x))
 }
 func (TaskInvocationStatus_Status) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{13, 0}
+	return fileDescriptor0, []int{22, 0}
 }
 
 //
@@ -255,6 +271,53 @@ type WorkflowSpec struct {
 	Name string `protobuf:"bytes,6,opt,name=name" json:"name,omitempty"`
 	// Internal indicates whether is a workflow should be visible to a human (default) or not.
 	Internal bool `protobuf:"varint,7,opt,name=internal" json:"internal,omitempty"`
+	// Prewarm indicates whether the functions of the initial tasks (the tasks without dependencies) should be
+	// prepared as soon as an invocation of the workflow is created, overlapping the provisioning of the functions
+	// with the evaluation of the inputs.
+	Prewarm bool `protobuf:"varint,8,opt,name=prewarm" json:"prewarm,omitempty"`
+	// SuccessCondition is an optional expression that is evaluated while the invocation is in progress. Once it
+	// evaluates to true, the invocation completes successfully, even if some of its tasks have not finished yet.
+	SuccessCondition string `protobuf:"bytes,9,opt,name=successCondition" json:"successCondition,omitempty"`
+	// CancelAbandonedTasks indicates whether the unfinished tasks should be aborted when the invocation completes early
+	// due to the successCondition or the completion policy. By default, these tasks are left to finish in the
+	// background. Aborting a task fails its task run, but does not cancel the call of its function, which keeps
+	// running until it returns.
+	CancelAbandonedTasks bool `protobuf:"varint,10,opt,name=cancelAbandonedTasks" json:"cancelAbandonedTasks,omitempty"`
+	// Completion is the optional policy that determines when the invocation completes. By default, an invocation
+	// completes once all of its tasks have finished.
+	Completion *CompletionPolicy `protobuf:"bytes,11,opt,name=completion" json:"completion,omitempty"`
+	// Concurrency is the optional policy that provides mutual exclusion between the invocations of the workflow that
+	// have the same concurrency key. By default, invocations run concurrently.
+	Concurrency *ConcurrencyPolicy `protobuf:"bytes,12,opt,name=concurrency" json:"concurrency,omitempty"`
+	// RetryBudget is the maximum number of retries across all tasks of an invocation. Once the budget has been exhausted,
+	// failed tasks are no longer retried, regardless of their retry policy. If 0, the retries are not limited.
+	RetryBudget int32 `protobuf:"varint,13,opt,name=retryBudget" json:"retryBudget,omitempty"`
+	// SoftTimeoutPercentage is the percentage of the time until the deadline of an invocation after which a warning is
+	// emitted that the invocation is approaching its deadline. It does not affect the deadline itself. If 0, no warning is
+	// emitted.
+	SoftTimeoutPercentage int32 `protobuf:"varint,14,opt,name=softTimeoutPercentage" json:"softTimeoutPercentage,omitempty"`
+	// Switches contains the switches of the workflow, with the key being the switch id. Each switch selects exactly
+	// one of its branches of tasks to execute; the tasks of the other branches are skipped.
+	Switches map[string]*Switch `protobuf:"bytes,15,rep,name=switches" json:"switches,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Contract optionally declares the inputs that the workflow expects and the output that it guarantees. The
+	// inputs of invocations by other workflows are validated against the contract.
+	Contract *WorkflowContract `protobuf:"bytes,16,opt,name=contract" json:"contract,omitempty"`
+	// InputMiddleware contains the names of the input middleware that are applied to the inputs of the tasks of the
+	// workflow before they are submitted, in order. They are applied after the input middleware of the controller.
+	InputMiddleware []string `protobuf:"bytes,17,rep,name=inputMiddleware" json:"inputMiddleware,omitempty"`
+	// ScopeStrictness determines how the references in the input expressions of the tasks to keys that are undefined
+	// in the scope, such as a misspelled task id, are handled. By default, these references silently resolve to
+	// undefined. With warn, the references are recorded as a warning in the status of the invocation; with fail, the
+	// task fails as well.
+	ScopeStrictness string `protobuf:"bytes,18,opt,name=scopeStrictness" json:"scopeStrictness,omitempty"`
+	// MaxParallelism is the maximum number of tasks that run concurrently across all invocations of the workflow, to
+	// protect a downstream resource that is shared by the invocations. Tasks that would exceed the limit are deferred
+	// until another task of the workflow has finished. If 0, the tasks of the workflow are not limited.
+	MaxParallelism int32 `protobuf:"varint,19,opt,name=maxParallelism" json:"maxParallelism,omitempty"`
+	// Timeout is the time that the invocations of the workflow are given to complete, from their creation. It applies
+	// to the invocations that do not specify a deadline of their own. If not set, the default timeout of the
+	// invocation API applies; if that is not set either, the invocations do not time out.
+	Timeout *google_protobuf1.Duration `protobuf:"bytes,20,opt,name=timeout" json:"timeout,omitempty"`
 }
 
 func (m *WorkflowSpec) Reset()                    { *m = WorkflowSpec{} }
@@ -311,12 +374,110 @@ func (m *WorkflowSpec) GetInternal() bool {
 	return false
 }
 
+func (m *WorkflowSpec) GetPrewarm() bool {
+	if m != nil {
+		return m.Prewarm
+	}
+	return false
+}
+
+func (m *WorkflowSpec) GetSuccessCondition() string {
+	if m != nil {
+		return m.SuccessCondition
+	}
+	return ""
+}
+
+func (m *WorkflowSpec) GetCancelAbandonedTasks() bool {
+	if m != nil {
+		return m.CancelAbandonedTasks
+	}
+	return false
+}
+
+func (m *WorkflowSpec) GetCompletion() *CompletionPolicy {
+	if m != nil {
+		return m.Completion
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetConcurrency() *ConcurrencyPolicy {
+	if m != nil {
+		return m.Concurrency
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetRetryBudget() int32 {
+	if m != nil {
+		return m.RetryBudget
+	}
+	return 0
+}
+
+func (m *WorkflowSpec) GetSoftTimeoutPercentage() int32 {
+	if m != nil {
+		return m.SoftTimeoutPercentage
+	}
+	return 0
+}
+
+func (m *WorkflowSpec) GetSwitches() map[string]*Switch {
+	if m != nil {
+		return m.Switches
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetContract() *WorkflowContract {
+	if m != nil {
+		return m.Contract
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetInputMiddleware() []string {
+	if m != nil {
+		return m.InputMiddleware
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetScopeStrictness() string {
+	if m != nil {
+		return m.ScopeStrictness
+	}
+	return ""
+}
+
+func (m *WorkflowSpec) GetMaxParallelism() int32 {
+	if m != nil {
+		return m.MaxParallelism
+	}
+	return 0
+}
+
+func (m *WorkflowSpec) GetTimeout() *google_protobuf1.Duration {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
 type WorkflowStatus struct {
 	Status    WorkflowStatus_Status      `protobuf:"varint,1,opt,name=status,enum=fission.workflows.types.WorkflowStatus_Status" json:"status,omitempty"`
 	UpdatedAt *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=updatedAt" json:"updatedAt,omitempty"`
 	// Tasks contains the status of the tasks, with the key being the task id.
 	Tasks map[string]*Task `protobuf:"bytes,3,rep,name=tasks" json:"tasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Error *Error           `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	// ParseAttempts is the number of failed attempts to parse the workflow since it was (re)created. Together with
+	// updatedAt, it determines the backoff before the next attempt.
+	ParseAttempts int32 `protobuf:"varint,5,opt,name=parseAttempts" json:"parseAttempts,omitempty"`
+	// Contract is the contract of the workflow, which is in effect once the workflow has been parsed.
+	Contract *WorkflowContract `protobuf:"bytes,6,opt,name=contract" json:"contract,omitempty"`
+	// Quarantine is the quarantine state of the workflow, which is set once its invocations have failed repeatedly.
+	Quarantine *WorkflowQuarantine `protobuf:"bytes,7,opt,name=quarantine" json:"quarantine,omitempty"`
 }
 
 func (m *WorkflowStatus) Reset()                    { *m = WorkflowStatus{} }
@@ -352,6 +513,98 @@ func (m *WorkflowStatus) GetError() *Error {
 	return nil
 }
 
+func (m *WorkflowStatus) GetParseAttempts() int32 {
+	if m != nil {
+		return m.ParseAttempts
+	}
+	return 0
+}
+
+func (m *WorkflowStatus) GetContract() *WorkflowContract {
+	if m != nil {
+		return m.Contract
+	}
+	return nil
+}
+
+func (m *WorkflowStatus) GetQuarantine() *WorkflowQuarantine {
+	if m != nil {
+		return m.Quarantine
+	}
+	return nil
+}
+
+// WorkflowQuarantine is the quarantine state of a workflow. A quarantined workflow rejects new invocations, until
+// an operator clears the quarantine.
+type WorkflowQuarantine struct {
+	Quarantined bool `protobuf:"varint,1,opt,name=quarantined" json:"quarantined,omitempty"`
+	// Reason describes why the workflow was quarantined, including the error of the last failed invocation.
+	Reason        string                     `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+	QuarantinedAt *google_protobuf.Timestamp `protobuf:"bytes,3,opt,name=quarantinedAt" json:"quarantinedAt,omitempty"`
+	// ConsecutiveFailures is the number of consecutive failed invocations that quarantined the workflow.
+	ConsecutiveFailures int32 `protobuf:"varint,4,opt,name=consecutiveFailures" json:"consecutiveFailures,omitempty"`
+	// Window is the window within which the failures occurred.
+	Window *google_protobuf1.Duration `protobuf:"bytes,5,opt,name=window" json:"window,omitempty"`
+	// Quarantines is the number of times that the workflow has been quarantined.
+	Quarantines int32 `protobuf:"varint,6,opt,name=quarantines" json:"quarantines,omitempty"`
+	// ClearedAt is the time at which the last quarantine was cleared by an operator, if any.
+	ClearedAt *google_protobuf.Timestamp `protobuf:"bytes,7,opt,name=clearedAt" json:"clearedAt,omitempty"`
+}
+
+func (m *WorkflowQuarantine) Reset()                    { *m = WorkflowQuarantine{} }
+func (m *WorkflowQuarantine) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowQuarantine) ProtoMessage()               {}
+func (*WorkflowQuarantine) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *WorkflowQuarantine) GetQuarantined() bool {
+	if m != nil {
+		return m.Quarantined
+	}
+	return false
+}
+
+func (m *WorkflowQuarantine) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *WorkflowQuarantine) GetQuarantinedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.QuarantinedAt
+	}
+	return nil
+}
+
+func (m *WorkflowQuarantine) GetConsecutiveFailures() int32 {
+	if m != nil {
+		return m.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (m *WorkflowQuarantine) GetWindow() *google_protobuf1.Duration {
+	if m != nil {
+		return m.Window
+	}
+	return nil
+}
+
+func (m *WorkflowQuarantine) GetQuarantines() int32 {
+	if m != nil {
+		return m.Quarantines
+	}
+	return 0
+}
+
+func (m *WorkflowQuarantine) GetClearedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.ClearedAt
+	}
+	return nil
+}
+
 //
 // Workflow Invocation Model
 //
@@ -364,7 +617,7 @@ type WorkflowInvocation struct {
 func (m *WorkflowInvocation) Reset()                    { *m = WorkflowInvocation{} }
 func (m *WorkflowInvocation) String() string            { return proto.CompactTextString(m) }
 func (*WorkflowInvocation) ProtoMessage()               {}
-func (*WorkflowInvocation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*WorkflowInvocation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *WorkflowInvocation) GetMetadata() *ObjectMetadata {
 	if m != nil {
@@ -405,15 +658,34 @@ type WorkflowInvocationSpec struct {
 	// Deadline is the timestamp before which the workflow invocation needs to be completed.
 	//
 	// The field is a hard deadline; any invocation exceeding the deadline specified here will be canceled.
-	// Each invocation has a deadline. If no deadline is provided Fission Workflows uses a default deadline (typically
-	// 10 minutes).
+	// If no deadline is provided, the deadline is derived from the timeout of the workflow when the invocation is
+	// created, or otherwise from the default timeout of the invocation API. If neither is set, the invocation does
+	// not time out.
 	Deadline *google_protobuf.Timestamp `protobuf:"bytes,5,opt,name=Deadline" json:"Deadline,omitempty"`
+	// GroupId optionally tags the invocation as a member of a group of related invocations.
+	//
+	// Groups allow related, but otherwise independent, invocations to be tracked and canceled together.
+	GroupId string `protobuf:"bytes,6,opt,name=groupId" json:"groupId,omitempty"`
+	// Labels are optional key-value pairs that describe the invocation. For example, the tenant label is used to
+	// partition the invocations for fair queuing.
+	Labels map[string]string `protobuf:"bytes,7,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// ParentTaskId contains the id of the task of the parent invocation that started this invocation, which
+	// determines whether the cancellation of the parent is propagated to this invocation.
+	//
+	// Like the parentId, this is used within the workflow engine.
+	ParentTaskId string `protobuf:"bytes,8,opt,name=parentTaskId" json:"parentTaskId,omitempty"`
+	// ParentPriority contains the effective priority of the parent invocation that started this invocation. The
+	// invocation inherits this priority if it exceeds its own, which avoids that a high-priority invocation waits
+	// on a deferred low-priority sub-workflow invocation.
+	//
+	// Like the parentId, this is used within the workflow engine.
+	ParentPriority string `protobuf:"bytes,9,opt,name=parentPriority" json:"parentPriority,omitempty"`
 }
 
 func (m *WorkflowInvocationSpec) Reset()                    { *m = WorkflowInvocationSpec{} }
 func (m *WorkflowInvocationSpec) String() string            { return proto.CompactTextString(m) }
 func (*WorkflowInvocationSpec) ProtoMessage()               {}
-func (*WorkflowInvocationSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*WorkflowInvocationSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *WorkflowInvocationSpec) GetWorkflowId() string {
 	if m != nil {
@@ -450,6 +722,34 @@ func (m *WorkflowInvocationSpec) GetDeadline() *google_protobuf.Timestamp {
 	return nil
 }
 
+func (m *WorkflowInvocationSpec) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *WorkflowInvocationSpec) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationSpec) GetParentTaskId() string {
+	if m != nil {
+		return m.ParentTaskId
+	}
+	return ""
+}
+
+func (m *WorkflowInvocationSpec) GetParentPriority() string {
+	if m != nil {
+		return m.ParentPriority
+	}
+	return ""
+}
+
 type WorkflowInvocationStatus struct {
 	Status    WorkflowInvocationStatus_Status     `protobuf:"varint,1,opt,name=status,enum=fission.workflows.types.WorkflowInvocationStatus_Status" json:"status,omitempty"`
 	UpdatedAt *google_protobuf.Timestamp          `protobuf:"bytes,2,opt,name=updatedAt" json:"updatedAt,omitempty"`
@@ -460,12 +760,48 @@ type WorkflowInvocationStatus struct {
 	DynamicTasks  map[string]*Task                    `protobuf:"bytes,5,rep,name=dynamicTasks" json:"dynamicTasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Error         *Error                              `protobuf:"bytes,6,opt,name=error" json:"error,omitempty"`
 	OutputHeaders *fission_workflows_types.TypedValue `protobuf:"bytes,7,opt,name=outputHeaders" json:"outputHeaders,omitempty"`
+	// PayloadSize is the cumulative size (in bytes) of the task outputs retained by the invocation.
+	PayloadSize int64 `protobuf:"varint,8,opt,name=payloadSize" json:"payloadSize,omitempty"`
+	// AbandonedTasks contains the IDs of the tasks that had not finished when the invocation completed early.
+	AbandonedTasks []string `protobuf:"bytes,9,rep,name=abandonedTasks" json:"abandonedTasks,omitempty"`
+	// LateTasks contains the results of tasks that finished after the invocation had already reached a terminal state.
+	// These results are recorded for auditing purposes only; they do not affect the invocation.
+	LateTasks map[string]*TaskInvocation `protobuf:"bytes,10,rep,name=lateTasks" json:"lateTasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// CompletionMode is the completion mode (all, any, quorum, or successCondition) that completed the invocation.
+	CompletionMode string `protobuf:"bytes,11,opt,name=completionMode" json:"completionMode,omitempty"`
+	// CompletedBy contains the IDs of the tasks that triggered the completion of the invocation, in case the
+	// invocation was completed by its completion policy.
+	CompletedBy []string `protobuf:"bytes,12,rep,name=completedBy" json:"completedBy,omitempty"`
+	// Retries is the number of times that tasks of the invocation have been retried.
+	Retries int32 `protobuf:"varint,13,opt,name=retries" json:"retries,omitempty"`
+	// RetryBudget contains the remaining retry budget of the invocation, if the workflow has a retry budget.
+	RetryBudget *RetryBudgetStatus `protobuf:"bytes,14,opt,name=retryBudget" json:"retryBudget,omitempty"`
+	// SoftTimeoutExceeded indicates whether the invocation has exceeded the soft timeout of the workflow.
+	SoftTimeoutExceeded bool `protobuf:"varint,15,opt,name=softTimeoutExceeded" json:"softTimeoutExceeded,omitempty"`
+	// Branches contains the branch that was selected by each decided switch of the workflow, with the key being the
+	// switch id.
+	Branches map[string]string `protobuf:"bytes,16,rep,name=branches" json:"branches,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// LoopIterations contains the number of iterations that each loop of the invocation has started, with the key being
+	// the id of the loop task. The iterations of loops nested in sub-invocations are recorded in the sub-invocations.
+	LoopIterations map[string]int64 `protobuf:"bytes,17,rep,name=loopIterations" json:"loopIterations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// ThrottledTasks contains the tasks of which the execution is currently deferred by their rate limit, with the key
+	// being the task id. A task is removed once it has started.
+	ThrottledTasks map[string]*TaskThrottle `protobuf:"bytes,18,rep,name=throttledTasks" json:"throttledTasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// ReplayedAt is the time at which the failed tasks of the invocation were last replayed. The runtime of a replayed
+	// invocation is measured from this time, rather than from its creation.
+	ReplayedAt *google_protobuf.Timestamp `protobuf:"bytes,19,opt,name=replayedAt" json:"replayedAt,omitempty"`
+	// ScopeWarnings contains the references to undefined keys of the scope in the inputs of the tasks, with the key
+	// being the task id. They are only recorded if the workflow has a scope strictness.
+	ScopeWarnings map[string]*ScopeWarning `protobuf:"bytes,20,rep,name=scopeWarnings" json:"scopeWarnings,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// EffectivePriority is the priority with which the invocation is scheduled: the highest of the priority label of
+	// the invocation and the priority inherited from the parent invocation.
+	EffectivePriority string `protobuf:"bytes,21,opt,name=effectivePriority" json:"effectivePriority,omitempty"`
 }
 
 func (m *WorkflowInvocationStatus) Reset()                    { *m = WorkflowInvocationStatus{} }
 func (m *WorkflowInvocationStatus) String() string            { return proto.CompactTextString(m) }
 func (*WorkflowInvocationStatus) ProtoMessage()               {}
-func (*WorkflowInvocationStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*WorkflowInvocationStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *WorkflowInvocationStatus) GetStatus() WorkflowInvocationStatus_Status {
 	if m != nil {
@@ -516,6 +852,166 @@ func (m *WorkflowInvocationStatus) GetOutputHeaders() *fission_workflows_types.T
 	return nil
 }
 
+func (m *WorkflowInvocationStatus) GetPayloadSize() int64 {
+	if m != nil {
+		return m.PayloadSize
+	}
+	return 0
+}
+
+func (m *WorkflowInvocationStatus) GetAbandonedTasks() []string {
+	if m != nil {
+		return m.AbandonedTasks
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetLateTasks() map[string]*TaskInvocation {
+	if m != nil {
+		return m.LateTasks
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetCompletionMode() string {
+	if m != nil {
+		return m.CompletionMode
+	}
+	return ""
+}
+
+func (m *WorkflowInvocationStatus) GetCompletedBy() []string {
+	if m != nil {
+		return m.CompletedBy
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetRetries() int32 {
+	if m != nil {
+		return m.Retries
+	}
+	return 0
+}
+
+func (m *WorkflowInvocationStatus) GetRetryBudget() *RetryBudgetStatus {
+	if m != nil {
+		return m.RetryBudget
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetSoftTimeoutExceeded() bool {
+	if m != nil {
+		return m.SoftTimeoutExceeded
+	}
+	return false
+}
+
+func (m *WorkflowInvocationStatus) GetBranches() map[string]string {
+	if m != nil {
+		return m.Branches
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetLoopIterations() map[string]int64 {
+	if m != nil {
+		return m.LoopIterations
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetThrottledTasks() map[string]*TaskThrottle {
+	if m != nil {
+		return m.ThrottledTasks
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetReplayedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.ReplayedAt
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetScopeWarnings() map[string]*ScopeWarning {
+	if m != nil {
+		return m.ScopeWarnings
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetEffectivePriority() string {
+	if m != nil {
+		return m.EffectivePriority
+	}
+	return ""
+}
+
+// ScopeWarning describes the references to keys that are undefined in the scope in the inputs of a task.
+type ScopeWarning struct {
+	// Inputs contains the keys of the inputs of which the expressions reference undefined keys.
+	Inputs []string `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
+	// References contains the references to undefined keys, such as $.Tasks.fetch or output('fetch').
+	References []string                   `protobuf:"bytes,2,rep,name=references" json:"references,omitempty"`
+	RecordedAt *google_protobuf.Timestamp `protobuf:"bytes,3,opt,name=recordedAt" json:"recordedAt,omitempty"`
+}
+
+func (m *ScopeWarning) Reset()                    { *m = ScopeWarning{} }
+func (m *ScopeWarning) String() string            { return proto.CompactTextString(m) }
+func (*ScopeWarning) ProtoMessage()               {}
+func (*ScopeWarning) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *ScopeWarning) GetInputs() []string {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *ScopeWarning) GetReferences() []string {
+	if m != nil {
+		return m.References
+	}
+	return nil
+}
+
+func (m *ScopeWarning) GetRecordedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.RecordedAt
+	}
+	return nil
+}
+
+// RetryBudgetStatus contains the state of the retry budget of an invocation.
+type RetryBudgetStatus struct {
+	// Limit is the maximum number of retries across the tasks of the invocation.
+	Limit int32 `protobuf:"varint,1,opt,name=limit" json:"limit,omitempty"`
+	// Remaining is the number of retries that are left.
+	Remaining int32 `protobuf:"varint,2,opt,name=remaining" json:"remaining,omitempty"`
+}
+
+func (m *RetryBudgetStatus) Reset()                    { *m = RetryBudgetStatus{} }
+func (m *RetryBudgetStatus) String() string            { return proto.CompactTextString(m) }
+func (*RetryBudgetStatus) ProtoMessage()               {}
+func (*RetryBudgetStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *RetryBudgetStatus) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *RetryBudgetStatus) GetRemaining() int32 {
+	if m != nil {
+		return m.Remaining
+	}
+	return 0
+}
+
 type DependencyConfig struct {
 	// Dependencies for this task to execute
 	Requires map[string]*TaskDependencyParameters `protobuf:"bytes,1,rep,name=requires" json:"requires,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
@@ -526,7 +1022,7 @@ type DependencyConfig struct {
 func (m *DependencyConfig) Reset()                    { *m = DependencyConfig{} }
 func (m *DependencyConfig) String() string            { return proto.CompactTextString(m) }
 func (*DependencyConfig) ProtoMessage()               {}
-func (*DependencyConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*DependencyConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *DependencyConfig) GetRequires() map[string]*TaskDependencyParameters {
 	if m != nil {
@@ -554,7 +1050,7 @@ type Task struct {
 func (m *Task) Reset()                    { *m = Task{} }
 func (m *Task) String() string            { return proto.CompactTextString(m) }
 func (*Task) ProtoMessage()               {}
-func (*Task) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*Task) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *Task) GetMetadata() *ObjectMetadata {
 	if m != nil {
@@ -570,89 +1066,479 @@ func (m *Task) GetSpec() *TaskSpec {
 	return nil
 }
 
-func (m *Task) GetStatus() *TaskStatus {
+func (m *Task) GetStatus() *TaskStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// A task is the primitive unit of a workflow, representing an action that needs to be performed in order to continue.
+//
+// A task as a number of inputs and exactly two outputs
+// Id is specified outside of TaskSpec
+type TaskSpec struct {
+	// FunctionRef contains an identifier for the function.
+	//
+	// This possibly ambiguous reference will be resolved to a unambiguous function reference during the workflow
+	// parsing. The result of this is found in the TaskStatus.FnRef.
+	FunctionRef string                                         `protobuf:"bytes,1,opt,name=functionRef" json:"functionRef,omitempty"`
+	Inputs      map[string]*fission_workflows_types.TypedValue `protobuf:"bytes,2,rep,name=inputs" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Dependencies for this task to execute.
+	Requires map[string]*TaskDependencyParameters `protobuf:"bytes,3,rep,name=requires" json:"requires,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Await signals the number of dependencies to wait for before this task can be started.
+	Await int32 `protobuf:"varint,4,opt,name=await" json:"await,omitempty"`
+	// Output transforms or overrides the output of the executed function.
+	Output *fission_workflows_types.TypedValue `protobuf:"bytes,5,opt,name=output" json:"output,omitempty"`
+	// OutputHeaders transforms or overrides the output headers of the executed function.
+	OutputHeaders *fission_workflows_types.TypedValue `protobuf:"bytes,6,opt,name=outputHeaders" json:"outputHeaders,omitempty"`
+	// Timeout specifies the the maximum runtime duration when executing this task.
+	//
+	// It overrides the deadline specified by the workflow invocation, but cannot exceed it. If set, this field will be
+	// used in the task invocation spec to compute the deadline.
+	Timeout *google_protobuf1.Duration `protobuf:"bytes,7,opt,name=timeout" json:"timeout,omitempty"`
+	// ExecutorType is a hint for the function runtime to execute the task on a specific executor type (e.g. the
+	// poolmgr or newdeploy executor in Fission). If empty, the default executor type of the function is used.
+	ExecutorType string `protobuf:"bytes,8,opt,name=executorType" json:"executorType,omitempty"`
+	// OutputPath is the dot-separated path (e.g. "data") of the value to extract from the output of the function,
+	// before it is stored as the output of the task. This unwraps the value from an envelope that the function
+	// wraps its results in. If the path is missing from the output, the task fails.
+	OutputPath string `protobuf:"bytes,9,opt,name=outputPath" json:"outputPath,omitempty"`
+	// Affinity is the name of a group of tasks within the invocation that should run on the same function instance,
+	// to reuse its (costly) initialization. The function runtime forwards a session token shared by the tasks of the group,
+	// which is used for session affinity if the runtime supports it.
+	Affinity string `protobuf:"bytes,10,opt,name=affinity" json:"affinity,omitempty"`
+	// Idempotent indicates that the task can safely be executed more than once. When the controller recovers an
+	// invocation of which the task was in progress, an idempotent task is started again, whereas a task that is not
+	// idempotent is failed, as it cannot be determined whether it had (partially) been executed.
+	Idempotent bool `protobuf:"varint,11,opt,name=idempotent" json:"idempotent,omitempty"`
+	// Redact contains the fields of the output that are redacted before the output is stored or logged, in order to
+	// prevent sensitive values from ending up in the event store.
+	Redact []*RedactionRule `protobuf:"bytes,12,rep,name=redact" json:"redact,omitempty"`
+	// Retry is the optional policy for retrying the task when it fails. By default, a failed task is not retried.
+	Retry *RetryPolicy `protobuf:"bytes,13,opt,name=retry" json:"retry,omitempty"`
+	// InputReferences indicates that the function supports receiving its body by reference. If the body exceeds the
+	// threshold of the function runtime, the runtime passes a reference to the body that the function fetches on demand,
+	// instead of inlining the body in the request.
+	InputReferences bool `protobuf:"varint,14,opt,name=inputReferences" json:"inputReferences,omitempty"`
+	// ContentType is the media type (e.g. "text/csv") with which the function expects its body to be encoded, and with
+	// which its output is decoded if the function does not specify the media type of the output. If empty, the media type
+	// is inferred from the inputs, falling back to JSON for structured values.
+	ContentType string `protobuf:"bytes,15,opt,name=contentType" json:"contentType,omitempty"`
+	// Failover optionally configures a secondary function that the retries of the task switch to after repeated
+	// failures of the primary function.
+	Failover *Failover `protobuf:"bytes,16,opt,name=failover" json:"failover,omitempty"`
+	// RateLimit optionally limits the rate at which the task is executed within the invocation, for example to stay
+	// within the quota of an external API that the task calls in a loop.
+	RateLimit *RateLimit `protobuf:"bytes,17,opt,name=rateLimit" json:"rateLimit,omitempty"`
+	// FailedReferencePolicy determines how the task handles a reference to a task that has failed, through its
+	// dependencies or its input expressions: propagate-failure (default), use-null, or block.
+	FailedReferencePolicy string `protobuf:"bytes,18,opt,name=failedReferencePolicy" json:"failedReferencePolicy,omitempty"`
+	// FailedReferencePolicies overrides the FailedReferencePolicy for the references to specific tasks, with the key
+	// being the id of the referenced task.
+	FailedReferencePolicies map[string]string `protobuf:"bytes,19,rep,name=failedReferencePolicies" json:"failedReferencePolicies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// CancelPropagation determines whether the cancellation of the invocation is propagated to the sub-workflow
+	// invocations that the task started: cascade (default) cancels them along with the invocation, whereas detach
+	// leaves them running to completion.
+	CancelPropagation string `protobuf:"bytes,20,opt,name=cancelPropagation" json:"cancelPropagation,omitempty"`
+	// Pure indicates that the task is deterministic and free of side effects: its output only depends on its inputs.
+	// This allows the controller to memoize the output of the task, and to run the task again when recovering an
+	// invocation. Tasks that are not pure are never memoized.
+	Pure bool `protobuf:"varint,21,opt,name=pure" json:"pure,omitempty"`
+	// SLO contains the service level objectives of the task. A breach of an objective is signaled by the controller.
+	Slo *TaskSLO `protobuf:"bytes,22,opt,name=slo" json:"slo,omitempty"`
+	// MergeInputs binds inputs of the task to the deep merge of several sources, such as the outputs of multiple
+	// dependencies, with the key being the name of the input. Explicit inputs take precedence over merged inputs.
+	MergeInputs map[string]*InputMerge `protobuf:"bytes,23,rep,name=mergeInputs" json:"mergeInputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *TaskSpec) Reset()                    { *m = TaskSpec{} }
+func (m *TaskSpec) String() string            { return proto.CompactTextString(m) }
+func (*TaskSpec) ProtoMessage()               {}
+func (*TaskSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *TaskSpec) GetFunctionRef() string {
+	if m != nil {
+		return m.FunctionRef
+	}
+	return ""
+}
+
+func (m *TaskSpec) GetInputs() map[string]*fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetRequires() map[string]*TaskDependencyParameters {
+	if m != nil {
+		return m.Requires
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetAwait() int32 {
+	if m != nil {
+		return m.Await
+	}
+	return 0
+}
+
+func (m *TaskSpec) GetOutput() *fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetOutputHeaders() *fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.OutputHeaders
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetTimeout() *google_protobuf1.Duration {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetExecutorType() string {
+	if m != nil {
+		return m.ExecutorType
+	}
+	return ""
+}
+
+func (m *TaskSpec) GetOutputPath() string {
+	if m != nil {
+		return m.OutputPath
+	}
+	return ""
+}
+
+func (m *TaskSpec) GetAffinity() string {
+	if m != nil {
+		return m.Affinity
+	}
+	return ""
+}
+
+func (m *TaskSpec) GetIdempotent() bool {
+	if m != nil {
+		return m.Idempotent
+	}
+	return false
+}
+
+func (m *TaskSpec) GetRedact() []*RedactionRule {
+	if m != nil {
+		return m.Redact
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetRetry() *RetryPolicy {
+	if m != nil {
+		return m.Retry
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetInputReferences() bool {
+	if m != nil {
+		return m.InputReferences
+	}
+	return false
+}
+
+func (m *TaskSpec) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *TaskSpec) GetFailover() *Failover {
+	if m != nil {
+		return m.Failover
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetRateLimit() *RateLimit {
+	if m != nil {
+		return m.RateLimit
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetFailedReferencePolicy() string {
+	if m != nil {
+		return m.FailedReferencePolicy
+	}
+	return ""
+}
+
+func (m *TaskSpec) GetFailedReferencePolicies() map[string]string {
+	if m != nil {
+		return m.FailedReferencePolicies
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetCancelPropagation() string {
+	if m != nil {
+		return m.CancelPropagation
+	}
+	return ""
+}
+
+func (m *TaskSpec) GetPure() bool {
+	if m != nil {
+		return m.Pure
+	}
+	return false
+}
+
+func (m *TaskSpec) GetSlo() *TaskSLO {
+	if m != nil {
+		return m.Slo
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetMergeInputs() map[string]*InputMerge {
+	if m != nil {
+		return m.MergeInputs
+	}
+	return nil
+}
+
+// RedactionRule configures the redaction of a field of the output of a task.
+type RedactionRule struct {
+	// Path is the dot-separated path (e.g. "user.ssn") of the field in the output to redact.
+	Path string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	// Strip removes the field from the output, instead of masking its value.
+	Strip bool `protobuf:"varint,2,opt,name=strip" json:"strip,omitempty"`
+}
+
+func (m *RedactionRule) Reset()                    { *m = RedactionRule{} }
+func (m *RedactionRule) String() string            { return proto.CompactTextString(m) }
+func (*RedactionRule) ProtoMessage()               {}
+func (*RedactionRule) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *RedactionRule) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *RedactionRule) GetStrip() bool {
+	if m != nil {
+		return m.Strip
+	}
+	return false
+}
+
+// RetryPolicy configures the retrying of a task that has failed.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times that the task is executed, including the first attempt. If 0 or 1,
+	// the task is not retried.
+	MaxAttempts int32 `protobuf:"varint,1,opt,name=maxAttempts" json:"maxAttempts,omitempty"`
+	// TotalTimeout is the maximum wall-clock time that the task can spend across all of its attempts, measured from
+	// the start of the first attempt. Once it has been exceeded, the task is no longer retried. The deadline of each
+	// attempt is capped accordingly. If not set, the total time is only limited by the deadline of the invocation.
+	TotalTimeout *google_protobuf1.Duration `protobuf:"bytes,2,opt,name=totalTimeout" json:"totalTimeout,omitempty"`
+	// InitialDelay is the delay before the first retry of the task, measured from the failure of the first attempt.
+	// If not set, the task is retried immediately.
+	InitialDelay *google_protobuf1.Duration `protobuf:"bytes,3,opt,name=initialDelay" json:"initialDelay,omitempty"`
+	// BackoffMultiplier is the factor by which the delay grows with every subsequent retry. If 0, the delay doubles
+	// with every retry.
+	BackoffMultiplier float64 `protobuf:"fixed64,4,opt,name=backoffMultiplier" json:"backoffMultiplier,omitempty"`
+	// MaxDelay caps the delay between the retries of the task. If not set, the delay is capped at 5 minutes.
+	MaxDelay *google_protobuf1.Duration `protobuf:"bytes,5,opt,name=maxDelay" json:"maxDelay,omitempty"`
+}
+
+func (m *RetryPolicy) Reset()                    { *m = RetryPolicy{} }
+func (m *RetryPolicy) String() string            { return proto.CompactTextString(m) }
+func (*RetryPolicy) ProtoMessage()               {}
+func (*RetryPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *RetryPolicy) GetMaxAttempts() int32 {
+	if m != nil {
+		return m.MaxAttempts
+	}
+	return 0
+}
+
+func (m *RetryPolicy) GetTotalTimeout() *google_protobuf1.Duration {
+	if m != nil {
+		return m.TotalTimeout
+	}
+	return nil
+}
+
+func (m *RetryPolicy) GetInitialDelay() *google_protobuf1.Duration {
+	if m != nil {
+		return m.InitialDelay
+	}
+	return nil
+}
+
+func (m *RetryPolicy) GetBackoffMultiplier() float64 {
+	if m != nil {
+		return m.BackoffMultiplier
+	}
+	return 0
+}
+
+func (m *RetryPolicy) GetMaxDelay() *google_protobuf1.Duration {
+	if m != nil {
+		return m.MaxDelay
+	}
+	return nil
+}
+
+// Failover configures the function that a task fails over to after repeated failures of its primary function.
+type Failover struct {
+	// FunctionRef references the function that the task fails over to.
+	FunctionRef string `protobuf:"bytes,1,opt,name=functionRef" json:"functionRef,omitempty"`
+	// After is the number of failed attempts of the primary function after which the task fails over. If 0, the
+	// task fails over after the first failed attempt.
+	After int32 `protobuf:"varint,2,opt,name=after" json:"after,omitempty"`
+}
+
+func (m *Failover) Reset()                    { *m = Failover{} }
+func (m *Failover) String() string            { return proto.CompactTextString(m) }
+func (*Failover) ProtoMessage()               {}
+func (*Failover) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *Failover) GetFunctionRef() string {
+	if m != nil {
+		return m.FunctionRef
+	}
+	return ""
+}
+
+func (m *Failover) GetAfter() int32 {
+	if m != nil {
+		return m.After
+	}
+	return 0
+}
+
+// RateLimit limits the number of executions of a task in a sliding window, scoped to the invocation.
+type RateLimit struct {
+	// Executions is the maximum number of executions that start within any window.
+	Executions int32 `protobuf:"varint,1,opt,name=executions" json:"executions,omitempty"`
+	// Window is the duration of the sliding window in which the executions are counted.
+	Window *google_protobuf1.Duration `protobuf:"bytes,2,opt,name=window" json:"window,omitempty"`
+	// Key identifies the tasks of the invocation that share the limit. If empty, the tasks that run the same
+	// function share the limit, which includes the iterations of a loop over the task.
+	Key string `protobuf:"bytes,3,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *RateLimit) Reset()                    { *m = RateLimit{} }
+func (m *RateLimit) String() string            { return proto.CompactTextString(m) }
+func (*RateLimit) ProtoMessage()               {}
+func (*RateLimit) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *RateLimit) GetExecutions() int32 {
+	if m != nil {
+		return m.Executions
+	}
+	return 0
+}
+
+func (m *RateLimit) GetWindow() *google_protobuf1.Duration {
 	if m != nil {
-		return m.Status
+		return m.Window
 	}
 	return nil
 }
 
-// A task is the primitive unit of a workflow, representing an action that needs to be performed in order to continue.
-//
-// A task as a number of inputs and exactly two outputs
-// Id is specified outside of TaskSpec
-type TaskSpec struct {
-	// FunctionRef contains an identifier for the function.
-	//
-	// This possibly ambiguous reference will be resolved to a unambiguous function reference during the workflow
-	// parsing. The result of this is found in the TaskStatus.FnRef.
-	FunctionRef string                                         `protobuf:"bytes,1,opt,name=functionRef" json:"functionRef,omitempty"`
-	Inputs      map[string]*fission_workflows_types.TypedValue `protobuf:"bytes,2,rep,name=inputs" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	// Dependencies for this task to execute.
-	Requires map[string]*TaskDependencyParameters `protobuf:"bytes,3,rep,name=requires" json:"requires,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	// Await signals the number of dependencies to wait for before this task can be started.
-	Await int32 `protobuf:"varint,4,opt,name=await" json:"await,omitempty"`
-	// Output transforms or overrides the output of the executed function.
-	Output *fission_workflows_types.TypedValue `protobuf:"bytes,5,opt,name=output" json:"output,omitempty"`
-	// OutputHeaders transforms or overrides the output headers of the executed function.
-	OutputHeaders *fission_workflows_types.TypedValue `protobuf:"bytes,6,opt,name=outputHeaders" json:"outputHeaders,omitempty"`
-	// Timeout specifies the the maximum runtime duration when executing this task.
-	//
-	// It overrides the deadline specified by the workflow invocation, but cannot exceed it. If set, this field will be
-	// used in the task invocation spec to compute the deadline.
-	Timeout *google_protobuf1.Duration `protobuf:"bytes,7,opt,name=timeout" json:"timeout,omitempty"`
-}
-
-func (m *TaskSpec) Reset()                    { *m = TaskSpec{} }
-func (m *TaskSpec) String() string            { return proto.CompactTextString(m) }
-func (*TaskSpec) ProtoMessage()               {}
-func (*TaskSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
-
-func (m *TaskSpec) GetFunctionRef() string {
+func (m *RateLimit) GetKey() string {
 	if m != nil {
-		return m.FunctionRef
+		return m.Key
 	}
 	return ""
 }
 
-func (m *TaskSpec) GetInputs() map[string]*fission_workflows_types.TypedValue {
+// TaskSLO contains the service level objectives of a task, which are evaluated over the runs of the task across the
+// invocations of the workflow.
+type TaskSLO struct {
+	// Latency is the objective of the latency of the task runs at the percentile.
+	Latency *google_protobuf1.Duration `protobuf:"bytes,1,opt,name=latency" json:"latency,omitempty"`
+	// Percentile is the percentile (0-100) of the latency objective. If 0, the 95th percentile is used.
+	Percentile float64 `protobuf:"fixed64,2,opt,name=percentile" json:"percentile,omitempty"`
+	// SuccessRate is the objective of the percentage (0-100) of the task runs that succeed.
+	SuccessRate float64 `protobuf:"fixed64,3,opt,name=successRate" json:"successRate,omitempty"`
+	// Window is the duration of the rolling window over which the objectives are evaluated. If not set, the
+	// window of the controller is used.
+	Window *google_protobuf1.Duration `protobuf:"bytes,4,opt,name=window" json:"window,omitempty"`
+}
+
+func (m *TaskSLO) Reset()                    { *m = TaskSLO{} }
+func (m *TaskSLO) String() string            { return proto.CompactTextString(m) }
+func (*TaskSLO) ProtoMessage()               {}
+func (*TaskSLO) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *TaskSLO) GetLatency() *google_protobuf1.Duration {
 	if m != nil {
-		return m.Inputs
+		return m.Latency
 	}
 	return nil
 }
 
-func (m *TaskSpec) GetRequires() map[string]*TaskDependencyParameters {
+func (m *TaskSLO) GetPercentile() float64 {
 	if m != nil {
-		return m.Requires
+		return m.Percentile
 	}
-	return nil
+	return 0
 }
 
-func (m *TaskSpec) GetAwait() int32 {
+func (m *TaskSLO) GetSuccessRate() float64 {
 	if m != nil {
-		return m.Await
+		return m.SuccessRate
 	}
 	return 0
 }
 
-func (m *TaskSpec) GetOutput() *fission_workflows_types.TypedValue {
+func (m *TaskSLO) GetWindow() *google_protobuf1.Duration {
 	if m != nil {
-		return m.Output
+		return m.Window
 	}
 	return nil
 }
 
-func (m *TaskSpec) GetOutputHeaders() *fission_workflows_types.TypedValue {
+// TaskThrottle describes a task of which the execution is deferred by its rate limit.
+type TaskThrottle struct {
+	// Since is the time at which the task was first throttled.
+	Since *google_protobuf.Timestamp `protobuf:"bytes,1,opt,name=since" json:"since,omitempty"`
+	// Until is the time at which the rate limit of the task allows it to be executed again.
+	Until *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=until" json:"until,omitempty"`
+}
+
+func (m *TaskThrottle) Reset()                    { *m = TaskThrottle{} }
+func (m *TaskThrottle) String() string            { return proto.CompactTextString(m) }
+func (*TaskThrottle) ProtoMessage()               {}
+func (*TaskThrottle) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *TaskThrottle) GetSince() *google_protobuf.Timestamp {
 	if m != nil {
-		return m.OutputHeaders
+		return m.Since
 	}
 	return nil
 }
 
-func (m *TaskSpec) GetTimeout() *google_protobuf1.Duration {
+func (m *TaskThrottle) GetUntil() *google_protobuf.Timestamp {
 	if m != nil {
-		return m.Timeout
+		return m.Until
 	}
 	return nil
 }
@@ -662,12 +1548,14 @@ type TaskStatus struct {
 	UpdatedAt *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=updatedAt" json:"updatedAt,omitempty"`
 	FnRef     *FnRef                     `protobuf:"bytes,3,opt,name=fnRef" json:"fnRef,omitempty"`
 	Error     *Error                     `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	// FailoverFnRef is the resolved function reference of the failover function of the task, if any.
+	FailoverFnRef *FnRef `protobuf:"bytes,5,opt,name=failoverFnRef" json:"failoverFnRef,omitempty"`
 }
 
 func (m *TaskStatus) Reset()                    { *m = TaskStatus{} }
 func (m *TaskStatus) String() string            { return proto.CompactTextString(m) }
 func (*TaskStatus) ProtoMessage()               {}
-func (*TaskStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*TaskStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *TaskStatus) GetStatus() TaskStatus_Status {
 	if m != nil {
@@ -697,15 +1585,25 @@ func (m *TaskStatus) GetError() *Error {
 	return nil
 }
 
+func (m *TaskStatus) GetFailoverFnRef() *FnRef {
+	if m != nil {
+		return m.FailoverFnRef
+	}
+	return nil
+}
+
 type TaskDependencyParameters struct {
 	Type  TaskDependencyParameters_DependencyType `protobuf:"varint,1,opt,name=type,enum=fission.workflows.types.TaskDependencyParameters_DependencyType" json:"type,omitempty"`
 	Alias string                                  `protobuf:"bytes,2,opt,name=alias" json:"alias,omitempty"`
+	// Transform is an expression that maps the output of the dependency into the shape that the task expects. The
+	// result is bound as an input of the task before its inputs are resolved. See TaskDependencyParameters.InputKey.
+	Transform *fission_workflows_types.TypedValue `protobuf:"bytes,3,opt,name=transform" json:"transform,omitempty"`
 }
 
 func (m *TaskDependencyParameters) Reset()                    { *m = TaskDependencyParameters{} }
 func (m *TaskDependencyParameters) String() string            { return proto.CompactTextString(m) }
 func (*TaskDependencyParameters) ProtoMessage()               {}
-func (*TaskDependencyParameters) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*TaskDependencyParameters) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *TaskDependencyParameters) GetType() TaskDependencyParameters_DependencyType {
 	if m != nil {
@@ -721,6 +1619,13 @@ func (m *TaskDependencyParameters) GetAlias() string {
 	return ""
 }
 
+func (m *TaskDependencyParameters) GetTransform() *fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Transform
+	}
+	return nil
+}
+
 //
 // Task Invocation Model
 //
@@ -733,7 +1638,7 @@ type TaskInvocation struct {
 func (m *TaskInvocation) Reset()                    { *m = TaskInvocation{} }
 func (m *TaskInvocation) String() string            { return proto.CompactTextString(m) }
 func (*TaskInvocation) ProtoMessage()               {}
-func (*TaskInvocation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*TaskInvocation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *TaskInvocation) GetMetadata() *ObjectMetadata {
 	if m != nil {
@@ -776,12 +1681,25 @@ type TaskInvocationSpec struct {
 	// Each task has a deadline. If no deadline is specified the task invocation inherits the deadline of the
 	// invocation.
 	Deadline *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=Deadline" json:"Deadline,omitempty"`
+	// ExecutorType is the executor type that the task invocation should be executed on, derived from the task.
+	// If empty, the function runtime uses the default executor type of the function.
+	ExecutorType string `protobuf:"bytes,7,opt,name=executorType" json:"executorType,omitempty"`
+	// Attempt is the attempt of the task execution, starting at 1.
+	Attempt int32 `protobuf:"varint,8,opt,name=attempt" json:"attempt,omitempty"`
+	// FirstAttemptAt is the time at which the first attempt of the task was started.
+	FirstAttemptAt *google_protobuf.Timestamp `protobuf:"bytes,9,opt,name=firstAttemptAt" json:"firstAttemptAt,omitempty"`
+	// Failover indicates whether the task run is executed by the failover function of the task, instead of the
+	// primary function.
+	Failover bool `protobuf:"varint,10,opt,name=failover" json:"failover,omitempty"`
+	// Priority is the effective priority of the workflow invocation of the task, which is inherited by the
+	// sub-workflow invocations that the task starts.
+	Priority string `protobuf:"bytes,11,opt,name=priority" json:"priority,omitempty"`
 }
 
 func (m *TaskInvocationSpec) Reset()                    { *m = TaskInvocationSpec{} }
 func (m *TaskInvocationSpec) String() string            { return proto.CompactTextString(m) }
 func (*TaskInvocationSpec) ProtoMessage()               {}
-func (*TaskInvocationSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*TaskInvocationSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *TaskInvocationSpec) GetFnRef() *FnRef {
 	if m != nil {
@@ -825,18 +1743,67 @@ func (m *TaskInvocationSpec) GetDeadline() *google_protobuf.Timestamp {
 	return nil
 }
 
+func (m *TaskInvocationSpec) GetExecutorType() string {
+	if m != nil {
+		return m.ExecutorType
+	}
+	return ""
+}
+
+func (m *TaskInvocationSpec) GetAttempt() int32 {
+	if m != nil {
+		return m.Attempt
+	}
+	return 0
+}
+
+func (m *TaskInvocationSpec) GetFirstAttemptAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.FirstAttemptAt
+	}
+	return nil
+}
+
+func (m *TaskInvocationSpec) GetFailover() bool {
+	if m != nil {
+		return m.Failover
+	}
+	return false
+}
+
+func (m *TaskInvocationSpec) GetPriority() string {
+	if m != nil {
+		return m.Priority
+	}
+	return ""
+}
+
 type TaskInvocationStatus struct {
 	Status        TaskInvocationStatus_Status         `protobuf:"varint,1,opt,name=status,enum=fission.workflows.types.TaskInvocationStatus_Status" json:"status,omitempty"`
 	UpdatedAt     *google_protobuf.Timestamp          `protobuf:"bytes,2,opt,name=updatedAt" json:"updatedAt,omitempty"`
 	Output        *fission_workflows_types.TypedValue `protobuf:"bytes,3,opt,name=output" json:"output,omitempty"`
 	Error         *Error                              `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
 	OutputHeaders *fission_workflows_types.TypedValue `protobuf:"bytes,5,opt,name=outputHeaders" json:"outputHeaders,omitempty"`
+	// Deadline is the effective deadline of the task run, derived from the deadline of the invocation and the timeout
+	// of the task.
+	Deadline *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=deadline" json:"deadline,omitempty"`
+	// RetryDeadline is the time after which the task is no longer retried, if the retry policy of the task has a
+	// total timeout. The remaining time budget of the task is the time until this deadline.
+	RetryDeadline *google_protobuf.Timestamp `protobuf:"bytes,7,opt,name=retryDeadline" json:"retryDeadline,omitempty"`
+	// Polls is the number of polls of a sensor task of which the readiness check did not pass.
+	Polls int32 `protobuf:"varint,8,opt,name=polls" json:"polls,omitempty"`
+	// LastPollResult is the result of the last of these polls, such as the status of the HTTP response.
+	LastPollResult string                     `protobuf:"bytes,9,opt,name=lastPollResult" json:"lastPollResult,omitempty"`
+	LastPolledAt   *google_protobuf.Timestamp `protobuf:"bytes,10,opt,name=lastPolledAt" json:"lastPolledAt,omitempty"`
+	// OutputHash is the content hash of the output, formatted as '<algorithm>:<hex-encoded digest>', if content
+	// hashing is enabled.
+	OutputHash string `protobuf:"bytes,11,opt,name=outputHash" json:"outputHash,omitempty"`
 }
 
 func (m *TaskInvocationStatus) Reset()                    { *m = TaskInvocationStatus{} }
 func (m *TaskInvocationStatus) String() string            { return proto.CompactTextString(m) }
 func (*TaskInvocationStatus) ProtoMessage()               {}
-func (*TaskInvocationStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*TaskInvocationStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *TaskInvocationStatus) GetStatus() TaskInvocationStatus_Status {
 	if m != nil {
@@ -873,6 +1840,48 @@ func (m *TaskInvocationStatus) GetOutputHeaders() *fission_workflows_types.Typed
 	return nil
 }
 
+func (m *TaskInvocationStatus) GetDeadline() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+func (m *TaskInvocationStatus) GetRetryDeadline() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.RetryDeadline
+	}
+	return nil
+}
+
+func (m *TaskInvocationStatus) GetPolls() int32 {
+	if m != nil {
+		return m.Polls
+	}
+	return 0
+}
+
+func (m *TaskInvocationStatus) GetLastPollResult() string {
+	if m != nil {
+		return m.LastPollResult
+	}
+	return ""
+}
+
+func (m *TaskInvocationStatus) GetLastPolledAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.LastPolledAt
+	}
+	return nil
+}
+
+func (m *TaskInvocationStatus) GetOutputHash() string {
+	if m != nil {
+		return m.OutputHash
+	}
+	return ""
+}
+
 // ObjectMetadata contains common metadata present for all objects in the workflow engine.
 //
 // It closely follows the structure of Kubernetes' ObjectMetadata, leaving out the parameters that do not fit the
@@ -894,7 +1903,7 @@ type ObjectMetadata struct {
 func (m *ObjectMetadata) Reset()                    { *m = ObjectMetadata{} }
 func (m *ObjectMetadata) String() string            { return proto.CompactTextString(m) }
 func (*ObjectMetadata) ProtoMessage()               {}
-func (*ObjectMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*ObjectMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *ObjectMetadata) GetId() string {
 	if m != nil {
@@ -924,14 +1933,26 @@ func (m *ObjectMetadata) GetGeneration() int64 {
 	return 0
 }
 
+// Error describes why a task or invocation failed.
+//
+// The fields are a stable schema across function runtimes, such that the errors can be inspected reliably, for
+// example in expressions.
 type Error struct {
 	Message string `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
+	// Code classifies the error; see the ErrorCode constants for the possible codes.
+	Code string `protobuf:"bytes,2,opt,name=code" json:"code,omitempty"`
+	// TaskId is the ID of the task that failed, if the error originates from a task.
+	TaskId string `protobuf:"bytes,3,opt,name=taskId" json:"taskId,omitempty"`
+	// Attempt is the attempt of the task execution that failed, starting at 1.
+	Attempt int32 `protobuf:"varint,4,opt,name=attempt" json:"attempt,omitempty"`
+	// Raw contains the raw response of the function that failed, if any.
+	Raw string `protobuf:"bytes,5,opt,name=raw" json:"raw,omitempty"`
 }
 
 func (m *Error) Reset()                    { *m = Error{} }
 func (m *Error) String() string            { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()               {}
-func (*Error) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*Error) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *Error) GetMessage() string {
 	if m != nil {
@@ -940,6 +1961,34 @@ func (m *Error) GetMessage() string {
 	return ""
 }
 
+func (m *Error) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *Error) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *Error) GetAttempt() int32 {
+	if m != nil {
+		return m.Attempt
+	}
+	return 0
+}
+
+func (m *Error) GetRaw() string {
+	if m != nil {
+		return m.Raw
+	}
+	return ""
+}
+
 // FnRef is an immutable, unique reference to a function on a specific function runtime environment.
 //
 // The string representation (via String or Format): runtime://runtimeId
@@ -955,7 +2004,7 @@ type FnRef struct {
 func (m *FnRef) Reset()                    { *m = FnRef{} }
 func (m *FnRef) String() string            { return proto.CompactTextString(m) }
 func (*FnRef) ProtoMessage()               {}
-func (*FnRef) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*FnRef) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func (m *FnRef) GetRuntime() string {
 	if m != nil {
@@ -986,7 +2035,7 @@ type TypedValueMap struct {
 func (m *TypedValueMap) Reset()                    { *m = TypedValueMap{} }
 func (m *TypedValueMap) String() string            { return proto.CompactTextString(m) }
 func (*TypedValueMap) ProtoMessage()               {}
-func (*TypedValueMap) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*TypedValueMap) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 func (m *TypedValueMap) GetValue() map[string]*fission_workflows_types.TypedValue {
 	if m != nil {
@@ -1003,7 +2052,7 @@ type TypedValueList struct {
 func (m *TypedValueList) Reset()                    { *m = TypedValueList{} }
 func (m *TypedValueList) String() string            { return proto.CompactTextString(m) }
 func (*TypedValueList) ProtoMessage()               {}
-func (*TypedValueList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*TypedValueList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 func (m *TypedValueList) GetValue() []*fission_workflows_types.TypedValue {
 	if m != nil {
@@ -1012,16 +2061,253 @@ func (m *TypedValueList) GetValue() []*fission_workflows_types.TypedValue {
 	return nil
 }
 
+// CompletionPolicy determines when an invocation completes, based on the completion of a designated set of tasks.
+type CompletionPolicy struct {
+	// Mode is one of: all (default), any, or quorum.
+	//
+	// - all: the invocation completes once all tasks in the set have succeeded.
+	// - any: the invocation completes once any of the tasks in the set has succeeded (race-to-first).
+	// - quorum: the invocation completes once at least quorum tasks of the set have succeeded.
+	Mode string `protobuf:"bytes,1,opt,name=mode" json:"mode,omitempty"`
+	// Tasks is the set of tasks that the mode is applied to. If empty, the mode is applied to all tasks.
+	Tasks []string `protobuf:"bytes,2,rep,name=tasks" json:"tasks,omitempty"`
+	// Quorum is the number of tasks of the set that need to succeed in the quorum mode.
+	Quorum int32 `protobuf:"varint,3,opt,name=quorum" json:"quorum,omitempty"`
+}
+
+func (m *CompletionPolicy) Reset()                    { *m = CompletionPolicy{} }
+func (m *CompletionPolicy) String() string            { return proto.CompactTextString(m) }
+func (*CompletionPolicy) ProtoMessage()               {}
+func (*CompletionPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *CompletionPolicy) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *CompletionPolicy) GetTasks() []string {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
+func (m *CompletionPolicy) GetQuorum() int32 {
+	if m != nil {
+		return m.Quorum
+	}
+	return 0
+}
+
+// ConcurrencyPolicy ensures that at most one invocation of the workflow runs per concurrency key.
+type ConcurrencyPolicy struct {
+	// Key is the expression that evaluates to the concurrency key of an invocation, e.g. "{$.Invocation.Inputs.service}".
+	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	// OnConflict is one of: queue (default) or reject.
+	//
+	// - queue: the invocation waits until the invocation that holds the key has finished.
+	// - reject: the invocation fails immediately.
+	OnConflict string `protobuf:"bytes,2,opt,name=onConflict" json:"onConflict,omitempty"`
+}
+
+func (m *ConcurrencyPolicy) Reset()                    { *m = ConcurrencyPolicy{} }
+func (m *ConcurrencyPolicy) String() string            { return proto.CompactTextString(m) }
+func (*ConcurrencyPolicy) ProtoMessage()               {}
+func (*ConcurrencyPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *ConcurrencyPolicy) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ConcurrencyPolicy) GetOnConflict() string {
+	if m != nil {
+		return m.OnConflict
+	}
+	return ""
+}
+
+// Switch selects exactly one of a set of named branches, based on the value of an expression that is evaluated once.
+type Switch struct {
+	// Expression is evaluated once all tasks in requires have finished. The string value of the result is matched
+	// against the names of the branches.
+	Expression string `protobuf:"bytes,1,opt,name=expression" json:"expression,omitempty"`
+	// Requires contains the tasks that need to finish before the expression is evaluated.
+	Requires []string `protobuf:"bytes,2,rep,name=requires" json:"requires,omitempty"`
+	// Branches contains the tasks of each branch, with the key being the name of the branch.
+	Branches map[string]*Branch `protobuf:"bytes,3,rep,name=branches" json:"branches,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Default is the branch that is selected if the value does not match any of the branches. Without a default
+	// branch, the invocation fails if no branch matches.
+	Default *Branch `protobuf:"bytes,4,opt,name=default" json:"default,omitempty"`
+}
+
+func (m *Switch) Reset()                    { *m = Switch{} }
+func (m *Switch) String() string            { return proto.CompactTextString(m) }
+func (*Switch) ProtoMessage()               {}
+func (*Switch) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *Switch) GetExpression() string {
+	if m != nil {
+		return m.Expression
+	}
+	return ""
+}
+
+func (m *Switch) GetRequires() []string {
+	if m != nil {
+		return m.Requires
+	}
+	return nil
+}
+
+func (m *Switch) GetBranches() map[string]*Branch {
+	if m != nil {
+		return m.Branches
+	}
+	return nil
+}
+
+func (m *Switch) GetDefault() *Branch {
+	if m != nil {
+		return m.Default
+	}
+	return nil
+}
+
+// Branch is a set of tasks that are executed only if the branch is selected by its switch.
+type Branch struct {
+	Tasks []string `protobuf:"bytes,1,rep,name=tasks" json:"tasks,omitempty"`
+}
+
+func (m *Branch) Reset()                    { *m = Branch{} }
+func (m *Branch) String() string            { return proto.CompactTextString(m) }
+func (*Branch) ProtoMessage()               {}
+func (*Branch) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *Branch) GetTasks() []string {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
+// ContractField declares a single input or output field of a workflow contract.
+type ContractField struct {
+	// Type is the expected type of the field: string, number, bool, bytes, map or list. If empty, any type is
+	// allowed.
+	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	// Optional indicates that the field may be omitted. By default, the field is required.
+	Optional    bool   `protobuf:"varint,2,opt,name=optional" json:"optional,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+}
+
+func (m *ContractField) Reset()                    { *m = ContractField{} }
+func (m *ContractField) String() string            { return proto.CompactTextString(m) }
+func (*ContractField) ProtoMessage()               {}
+func (*ContractField) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *ContractField) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ContractField) GetOptional() bool {
+	if m != nil {
+		return m.Optional
+	}
+	return false
+}
+
+func (m *ContractField) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+// WorkflowContract declares the inputs that a workflow expects and the output that it guarantees, which allows
+// workflows that are maintained independently to be composed safely.
+type WorkflowContract struct {
+	// Inputs contains the inputs that the workflow expects, with the key being the name of the input.
+	Inputs map[string]*ContractField `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Outputs contains the fields of the output of the workflow that it guarantees, with the key being the name
+	// of the field. It requires the output of the workflow to be a map.
+	Outputs map[string]*ContractField `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *WorkflowContract) Reset()                    { *m = WorkflowContract{} }
+func (m *WorkflowContract) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowContract) ProtoMessage()               {}
+func (*WorkflowContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *WorkflowContract) GetInputs() map[string]*ContractField {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *WorkflowContract) GetOutputs() map[string]*ContractField {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+// InputMerge binds an input of a task to the deep merge of several sources.
+type InputMerge struct {
+	// Sources are the values to merge, typically expressions that reference the outputs of the dependencies of the
+	// task (e.g. "{ output('FetchUser') }"). The sources are merged in order, so later sources take precedence.
+	Sources []*fission_workflows_types.TypedValue `protobuf:"bytes,1,rep,name=sources" json:"sources,omitempty"`
+	// Conflicts determines how a conflict between the sources is resolved: last-wins (default) takes the value of
+	// the later source, whereas error fails the task.
+	Conflicts string `protobuf:"bytes,2,opt,name=conflicts" json:"conflicts,omitempty"`
+}
+
+func (m *InputMerge) Reset()                    { *m = InputMerge{} }
+func (m *InputMerge) String() string            { return proto.CompactTextString(m) }
+func (*InputMerge) ProtoMessage()               {}
+func (*InputMerge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *InputMerge) GetSources() []*fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Sources
+	}
+	return nil
+}
+
+func (m *InputMerge) GetConflicts() string {
+	if m != nil {
+		return m.Conflicts
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Workflow)(nil), "fission.workflows.types.Workflow")
 	proto.RegisterType((*WorkflowSpec)(nil), "fission.workflows.types.WorkflowSpec")
 	proto.RegisterType((*WorkflowStatus)(nil), "fission.workflows.types.WorkflowStatus")
+	proto.RegisterType((*WorkflowQuarantine)(nil), "fission.workflows.types.WorkflowQuarantine")
 	proto.RegisterType((*WorkflowInvocation)(nil), "fission.workflows.types.WorkflowInvocation")
 	proto.RegisterType((*WorkflowInvocationSpec)(nil), "fission.workflows.types.WorkflowInvocationSpec")
 	proto.RegisterType((*WorkflowInvocationStatus)(nil), "fission.workflows.types.WorkflowInvocationStatus")
+	proto.RegisterType((*ScopeWarning)(nil), "fission.workflows.types.ScopeWarning")
+	proto.RegisterType((*RetryBudgetStatus)(nil), "fission.workflows.types.RetryBudgetStatus")
 	proto.RegisterType((*DependencyConfig)(nil), "fission.workflows.types.DependencyConfig")
 	proto.RegisterType((*Task)(nil), "fission.workflows.types.Task")
 	proto.RegisterType((*TaskSpec)(nil), "fission.workflows.types.TaskSpec")
+	proto.RegisterType((*RedactionRule)(nil), "fission.workflows.types.RedactionRule")
+	proto.RegisterType((*RetryPolicy)(nil), "fission.workflows.types.RetryPolicy")
+	proto.RegisterType((*Failover)(nil), "fission.workflows.types.Failover")
+	proto.RegisterType((*RateLimit)(nil), "fission.workflows.types.RateLimit")
+	proto.RegisterType((*TaskSLO)(nil), "fission.workflows.types.TaskSLO")
+	proto.RegisterType((*TaskThrottle)(nil), "fission.workflows.types.TaskThrottle")
 	proto.RegisterType((*TaskStatus)(nil), "fission.workflows.types.TaskStatus")
 	proto.RegisterType((*TaskDependencyParameters)(nil), "fission.workflows.types.TaskDependencyParameters")
 	proto.RegisterType((*TaskInvocation)(nil), "fission.workflows.types.TaskInvocation")
@@ -1032,6 +2318,13 @@ func init() {
 	proto.RegisterType((*FnRef)(nil), "fission.workflows.types.FnRef")
 	proto.RegisterType((*TypedValueMap)(nil), "fission.workflows.types.TypedValueMap")
 	proto.RegisterType((*TypedValueList)(nil), "fission.workflows.types.TypedValueList")
+	proto.RegisterType((*CompletionPolicy)(nil), "fission.workflows.types.CompletionPolicy")
+	proto.RegisterType((*ConcurrencyPolicy)(nil), "fission.workflows.types.ConcurrencyPolicy")
+	proto.RegisterType((*Switch)(nil), "fission.workflows.types.Switch")
+	proto.RegisterType((*Branch)(nil), "fission.workflows.types.Branch")
+	proto.RegisterType((*ContractField)(nil), "fission.workflows.types.ContractField")
+	proto.RegisterType((*WorkflowContract)(nil), "fission.workflows.types.WorkflowContract")
+	proto.RegisterType((*InputMerge)(nil), "fission.workflows.types.InputMerge")
 	proto.RegisterEnum("fission.workflows.types.WorkflowStatus_Status", WorkflowStatus_Status_name, WorkflowStatus_Status_value)
 	proto.RegisterEnum("fission.workflows.types.WorkflowInvocationStatus_Status", WorkflowInvocationStatus_Status_name, WorkflowInvocationStatus_Status_value)
 	proto.RegisterEnum("fission.workflows.types.TaskStatus_Status", TaskStatus_Status_name, TaskStatus_Status_value)
@@ -1042,98 +2335,214 @@ func init() {
 func init() { proto.RegisterFile("pkg/types/types.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1487 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0xdd, 0x72, 0xdb, 0x44,
-	0x14, 0xae, 0x2c, 0xcb, 0x3f, 0xc7, 0x8d, 0x31, 0x3b, 0xa5, 0x18, 0x0f, 0x94, 0x54, 0x1d, 0xa6,
-	0x1d, 0xa0, 0x32, 0x49, 0x0b, 0x4d, 0x09, 0x9d, 0xe2, 0x5a, 0x4a, 0xab, 0xc9, 0x8f, 0x83, 0x6c,
-	0xb7, 0x53, 0x98, 0xb6, 0xa3, 0x58, 0x6b, 0xa3, 0x26, 0x96, 0x84, 0x24, 0xb7, 0x93, 0x3b, 0x9e,
-	0x80, 0x87, 0x60, 0xe0, 0x19, 0xb8, 0xe4, 0xa2, 0x37, 0xcc, 0xf0, 0x0c, 0x0c, 0x17, 0x5c, 0x71,
-	0xc1, 0x3b, 0x30, 0xbb, 0x5a, 0xfd, 0xd9, 0x71, 0x6c, 0x67, 0x5c, 0x86, 0x9b, 0x44, 0xbb, 0x7b,
-	0xce, 0xd9, 0xb3, 0xe7, 0xe7, 0xfb, 0x76, 0x0d, 0x6f, 0x39, 0x87, 0x83, 0xba, 0x7f, 0xec, 0x60,
-	0x2f, 0xf8, 0x2b, 0x39, 0xae, 0xed, 0xdb, 0xe8, 0xed, 0xbe, 0xe9, 0x79, 0xa6, 0x6d, 0x49, 0x2f,
-	0x6d, 0xf7, 0xb0, 0x7f, 0x64, 0xbf, 0xf4, 0x24, 0xba, 0x5c, 0x7b, 0x7f, 0x60, 0xdb, 0x83, 0x23,
-	0x5c, 0xa7, 0x62, 0x07, 0xa3, 0x7e, 0xdd, 0x37, 0x87, 0xd8, 0xf3, 0xf5, 0xa1, 0x13, 0x68, 0xd6,
-	0x2e, 0x8d, 0x0b, 0x18, 0x23, 0x57, 0xf7, 0x89, 0xa9, 0x60, 0x7d, 0x67, 0x60, 0xfa, 0xdf, 0x8e,
-	0x0e, 0xa4, 0x9e, 0x3d, 0xac, 0xb3, 0x4d, 0xc2, 0xff, 0xd7, 0xa3, 0xcd, 0xea, 0x69, 0xaf, 0x8c,
-	0x17, 0xfa, 0xd1, 0x28, 0xfd, 0x1d, 0x58, 0x13, 0x7f, 0xe7, 0xa0, 0xf0, 0x88, 0x69, 0xa1, 0x26,
-	0x14, 0x86, 0xd8, 0xd7, 0x0d, 0xdd, 0xd7, 0xab, 0xdc, 0x2a, 0x77, 0xad, 0xb4, 0x7e, 0x55, 0x9a,
-	0x72, 0x0e, 0xa9, 0x75, 0xf0, 0x1c, 0xf7, 0xfc, 0x5d, 0x26, 0xae, 0x45, 0x8a, 0xe8, 0x36, 0x64,
-	0x3d, 0x07, 0xf7, 0xaa, 0x19, 0x6a, 0xe0, 0x83, 0xa9, 0x06, 0xc2, 0x5d, 0xdb, 0x0e, 0xee, 0x69,
-	0x54, 0x05, 0xdd, 0x85, 0x9c, 0xe7, 0xeb, 0xfe, 0xc8, 0xab, 0xf2, 0x33, 0x76, 0x8f, 0x94, 0xa9,
-	0xb8, 0xc6, 0xd4, 0xc4, 0x3f, 0x33, 0x70, 0x3e, 0x69, 0x17, 0x5d, 0x02, 0xd0, 0x1d, 0xf3, 0x21,
-	0x76, 0x89, 0x15, 0x7a, 0xa6, 0xa2, 0x96, 0x98, 0x41, 0x5b, 0x20, 0xf8, 0xba, 0x77, 0xe8, 0x55,
-	0x33, 0xab, 0xfc, 0xb5, 0xd2, 0xfa, 0x27, 0x73, 0x79, 0x2b, 0x75, 0x88, 0x8a, 0x62, 0xf9, 0xee,
-	0xb1, 0x16, 0xa8, 0x93, 0x7d, 0xec, 0x91, 0xef, 0x8c, 0x7c, 0xb2, 0x44, 0xbd, 0x2f, 0x6a, 0x89,
-	0x19, 0xb4, 0x0a, 0x25, 0x03, 0x7b, 0x3d, 0xd7, 0x74, 0x48, 0x26, 0xab, 0x59, 0x2a, 0x90, 0x9c,
-	0x42, 0x55, 0xc8, 0xf7, 0x6d, 0xb7, 0x87, 0x55, 0xa3, 0x2a, 0xd0, 0xd5, 0x70, 0x88, 0x10, 0x64,
-	0x2d, 0x7d, 0x88, 0xab, 0x39, 0x3a, 0x4d, 0xbf, 0x51, 0x0d, 0x0a, 0xa6, 0xe5, 0x63, 0xd7, 0xd2,
-	0x8f, 0xaa, 0xf9, 0x55, 0xee, 0x5a, 0x41, 0x8b, 0xc6, 0xb5, 0x6f, 0x00, 0x62, 0x07, 0x51, 0x05,
-	0xf8, 0x43, 0x7c, 0xcc, 0x8e, 0x4e, 0x3e, 0xd1, 0x2d, 0x10, 0x68, 0x09, 0xb0, 0x0c, 0x5d, 0x9e,
-	0x7a, 0x66, 0x62, 0x85, 0x66, 0x27, 0x90, 0xff, 0x3c, 0xb3, 0xc1, 0x89, 0x3f, 0xf3, 0x50, 0x4e,
-	0x07, 0x1f, 0x6d, 0x45, 0x59, 0x23, 0x9b, 0x94, 0xd7, 0xa5, 0x39, 0xb3, 0x26, 0xa5, 0x93, 0x87,
-	0x36, 0xa0, 0x38, 0x72, 0x0c, 0xdd, 0xc7, 0x46, 0xc3, 0x67, 0xbe, 0xd5, 0xa4, 0xa0, 0x19, 0xa4,
-	0xb0, 0x19, 0xa4, 0x4e, 0xd8, 0x2d, 0x5a, 0x2c, 0x8c, 0x1e, 0x84, 0x59, 0xe4, 0x69, 0x16, 0xd7,
-	0xe7, 0x75, 0x60, 0x32, 0x8f, 0x37, 0x41, 0xc0, 0xae, 0x6b, 0xbb, 0x34, 0x43, 0xa5, 0xf5, 0x4b,
-	0x53, 0x2d, 0x29, 0x44, 0x4a, 0x0b, 0x84, 0x6b, 0x8f, 0x66, 0x44, 0xfc, 0x46, 0x3a, 0xe2, 0xef,
-	0x9d, 0x1a, 0xf1, 0x64, 0xb4, 0x37, 0x20, 0xc7, 0x82, 0x0c, 0x90, 0xfb, 0xaa, 0xab, 0x74, 0x15,
-	0xb9, 0x72, 0x0e, 0x15, 0x41, 0xd0, 0x94, 0x86, 0xfc, 0xb8, 0x92, 0x21, 0xd3, 0x5b, 0x0d, 0x75,
-	0x47, 0x91, 0x2b, 0x3c, 0x2a, 0x41, 0x5e, 0x56, 0x76, 0x94, 0x8e, 0x22, 0x57, 0xb2, 0xe2, 0xdf,
-	0x1c, 0xa0, 0xf0, 0xb4, 0xaa, 0xf5, 0xc2, 0xee, 0x51, 0x08, 0x59, 0x4e, 0x87, 0x37, 0x53, 0x1d,
-	0x5e, 0x9f, 0x19, 0xed, 0x78, 0xff, 0x44, 0xaf, 0xab, 0x63, 0xbd, 0xbe, 0xb6, 0x88, 0x99, 0x74,
-	0xd7, 0x7f, 0xcf, 0xc3, 0xc5, 0x93, 0xf7, 0x22, 0x7d, 0x19, 0x9a, 0x53, 0x8d, 0xb0, 0xff, 0xe3,
-	0x19, 0xd4, 0x86, 0x9c, 0x69, 0x39, 0x23, 0x3f, 0x04, 0x80, 0xcd, 0x05, 0x0f, 0x23, 0xa9, 0x54,
-	0x3b, 0xa8, 0x21, 0x66, 0x8a, 0x34, 0xa7, 0xa3, 0xbb, 0xd8, 0xf2, 0x55, 0x83, 0x41, 0x41, 0x34,
-	0x46, 0x77, 0xa0, 0x10, 0x5a, 0x66, 0x35, 0x76, 0x79, 0xe6, 0x96, 0x5a, 0xa4, 0x82, 0x3e, 0x83,
-	0x82, 0x8c, 0x75, 0xe3, 0xc8, 0xb4, 0x30, 0x85, 0x89, 0xd3, 0x5b, 0x24, 0x92, 0xad, 0x3d, 0x85,
-	0x52, 0xc2, 0xd3, 0x13, 0x4a, 0xf4, 0x76, 0xba, 0x44, 0xaf, 0x4c, 0x2f, 0x51, 0x42, 0x21, 0x0f,
-	0x89, 0x68, 0xb2, 0x50, 0x5f, 0xe5, 0xa0, 0x3a, 0x2d, 0x4f, 0x68, 0x7f, 0x0c, 0x20, 0x36, 0x16,
-	0x4e, 0xf5, 0xf2, 0xa0, 0x42, 0x4b, 0x43, 0xc5, 0x17, 0x8b, 0xbb, 0x32, 0x09, 0x1a, 0x9b, 0x90,
-	0x0b, 0xa0, 0x9e, 0x65, 0x74, 0xae, 0xe0, 0x31, 0x15, 0x34, 0x80, 0xf3, 0xc6, 0xb1, 0xa5, 0x0f,
-	0xcd, 0x1e, 0x35, 0x5c, 0x15, 0xa8, 0x5f, 0xcd, 0xc5, 0xfd, 0x92, 0x13, 0x56, 0x02, 0xf7, 0x52,
-	0x86, 0x63, 0x68, 0xcb, 0x2d, 0x00, 0x6d, 0x48, 0x85, 0x95, 0xc0, 0xd1, 0x07, 0x58, 0x37, 0xb0,
-	0xeb, 0x51, 0xb6, 0x99, 0xf3, 0x88, 0x69, 0xcd, 0x9a, 0x3e, 0x03, 0x25, 0xef, 0xa4, 0x4b, 0xf0,
-	0xea, 0xa9, 0x28, 0x19, 0x1f, 0x3f, 0x51, 0x86, 0xb5, 0xa7, 0xf0, 0xe6, 0x44, 0x18, 0x96, 0x89,
-	0xc7, 0x4f, 0x22, 0x3c, 0x2e, 0x41, 0xbe, 0xbb, 0xb7, 0xbd, 0xd7, 0x7a, 0xb4, 0x57, 0x39, 0x87,
-	0x56, 0xa0, 0xd8, 0x6e, 0x3e, 0x50, 0xe4, 0x2e, 0x01, 0x62, 0x0e, 0xbd, 0x01, 0x25, 0x75, 0xef,
-	0xd9, 0xbe, 0xd6, 0xba, 0xaf, 0x29, 0xed, 0x76, 0x25, 0x43, 0xd7, 0xbb, 0xcd, 0xa6, 0xa2, 0xc8,
-	0x14, 0xa8, 0x63, 0xd0, 0xce, 0x12, 0x3b, 0x8d, 0x7b, 0x2d, 0x8d, 0x80, 0xb6, 0x20, 0xfe, 0xc3,
-	0x41, 0x45, 0xc6, 0x0e, 0xb6, 0x0c, 0x6c, 0xf5, 0x8e, 0x9b, 0xb6, 0xd5, 0x37, 0x07, 0xa8, 0x0d,
-	0x05, 0x17, 0x7f, 0x37, 0x32, 0x5d, 0x4c, 0xfa, 0x87, 0x14, 0xc7, 0xad, 0xa9, 0xfe, 0x8e, 0x2b,
-	0x4b, 0x1a, 0xd3, 0x0c, 0x0a, 0x22, 0x32, 0x84, 0x2e, 0x80, 0xa0, 0xbf, 0xd4, 0xcd, 0xa0, 0x79,
-	0x04, 0x2d, 0x18, 0xd4, 0x2c, 0x58, 0x49, 0x29, 0x9c, 0x10, 0xba, 0xfb, 0xe9, 0xd0, 0xad, 0x9d,
-	0x1a, 0xba, 0xd8, 0x9d, 0x7d, 0xdd, 0xd5, 0x87, 0xd8, 0xc7, 0xae, 0x97, 0x0c, 0xe7, 0xaf, 0x1c,
-	0x64, 0xe9, 0xf5, 0x68, 0x29, 0xb4, 0xf4, 0x69, 0x8a, 0x96, 0xe6, 0xb8, 0xd6, 0x04, 0x44, 0xb4,
-	0x39, 0x46, 0x44, 0x57, 0x4e, 0x57, 0x4c, 0x53, 0xcf, 0x5f, 0x59, 0x28, 0x84, 0xf6, 0xc8, 0x25,
-	0xaf, 0x3f, 0xb2, 0x7a, 0xb4, 0x28, 0x71, 0x9f, 0x45, 0x2d, 0x39, 0x85, 0x94, 0x31, 0xba, 0xb9,
-	0x3e, 0xd3, 0xc9, 0x13, 0x09, 0x66, 0x3b, 0x51, 0x12, 0x01, 0x8e, 0xd5, 0x67, 0x1b, 0x9a, 0x59,
-	0x0a, 0xd9, 0x44, 0x29, 0x24, 0x30, 0x4d, 0x58, 0x1c, 0xd3, 0x26, 0x40, 0x23, 0x77, 0x56, 0xd0,
-	0x40, 0x37, 0x20, 0x4f, 0x1e, 0x48, 0xf6, 0xc8, 0x67, 0xc8, 0xf3, 0xce, 0x04, 0xce, 0xcb, 0xec,
-	0x7d, 0xa4, 0x85, 0x92, 0xaf, 0x9b, 0xed, 0xfe, 0xf3, 0x3e, 0xf9, 0x29, 0x13, 0x40, 0x27, 0xc3,
-	0x9e, 0x7b, 0x63, 0x7c, 0xfa, 0xe1, 0x1c, 0x15, 0xbb, 0x3c, 0x06, 0xbd, 0x09, 0x42, 0x9f, 0xd6,
-	0x37, 0x3f, 0x83, 0x47, 0xb6, 0x88, 0x94, 0x16, 0x08, 0x9f, 0xed, 0x62, 0x2d, 0x7e, 0x9c, 0xc4,
-	0xdb, 0x76, 0xa7, 0x41, 0x71, 0x32, 0x71, 0x01, 0xe6, 0x12, 0x58, 0x9a, 0x11, 0x5f, 0x71, 0x50,
-	0x9d, 0x16, 0x4e, 0xd4, 0x81, 0x2c, 0xd9, 0x80, 0x85, 0xec, 0xcb, 0x85, 0xf3, 0x91, 0xc0, 0x56,
-	0x52, 0x14, 0x1a, 0xb5, 0x46, 0x9b, 0xe7, 0xc8, 0xd4, 0x3d, 0x1a, 0xc2, 0xa2, 0x16, 0x0c, 0xc4,
-	0x4d, 0x28, 0xa7, 0xa5, 0x51, 0x01, 0xb2, 0x72, 0xa3, 0xd3, 0xa8, 0x9c, 0x23, 0x07, 0x69, 0xb6,
-	0xf6, 0x3a, 0x5a, 0x6b, 0xa7, 0xc2, 0x21, 0x04, 0x65, 0xf9, 0xf1, 0x5e, 0x63, 0x57, 0x6d, 0x3e,
-	0x6b, 0x75, 0x3b, 0xfb, 0xdd, 0x4e, 0x25, 0x23, 0xfe, 0xc1, 0x41, 0x39, 0xcd, 0x70, 0xcb, 0x81,
-	0xc7, 0xbb, 0x29, 0x78, 0xfc, 0x68, 0x4e, 0x76, 0x4d, 0x00, 0xa5, 0x32, 0x06, 0x94, 0xd7, 0xe7,
-	0x35, 0x91, 0x86, 0xcc, 0x1f, 0x79, 0x40, 0x93, 0x7b, 0xc4, 0x65, 0xc5, 0x2d, 0x52, 0x56, 0x17,
-	0x21, 0x47, 0xee, 0x60, 0xaa, 0xc1, 0x12, 0xc0, 0x46, 0xa8, 0x15, 0x01, 0x2d, 0x3f, 0x83, 0x32,
-	0x27, 0x5d, 0x39, 0x11, 0x72, 0x45, 0x38, 0x6f, 0x46, 0x52, 0xaa, 0xc1, 0x5e, 0xf0, 0xa9, 0x39,
-	0xb4, 0x06, 0x59, 0xb2, 0x3d, 0x43, 0xcc, 0x19, 0xb7, 0x0a, 0x2a, 0x9a, 0xba, 0xcf, 0xe7, 0xfe,
-	0x47, 0xf7, 0xf9, 0xdf, 0x78, 0xb8, 0x70, 0x52, 0x16, 0xd1, 0xce, 0x18, 0xf6, 0xdc, 0x5c, 0xa8,
-	0x08, 0x96, 0x87, 0x42, 0x31, 0x3f, 0xf1, 0x8b, 0xf3, 0xd3, 0x99, 0xc0, 0x68, 0x92, 0xd5, 0x84,
-	0xb3, 0xb2, 0x9a, 0xf8, 0xfc, 0xb5, 0xde, 0x23, 0x29, 0x58, 0x6e, 0xab, 0xfb, 0xfb, 0x8a, 0x5c,
-	0xc9, 0x89, 0x3f, 0x70, 0x50, 0x4e, 0x83, 0x02, 0x2a, 0x43, 0xc6, 0x0c, 0x5f, 0xc3, 0x19, 0x33,
-	0xfe, 0x85, 0x29, 0x93, 0xf8, 0x85, 0x69, 0x03, 0x8a, 0x3d, 0x17, 0xb3, 0xd4, 0xf0, 0xb3, 0x53,
-	0x13, 0x09, 0x93, 0x37, 0xf7, 0x00, 0x5b, 0x38, 0x20, 0x65, 0x1a, 0x62, 0x5e, 0x4b, 0xcc, 0x88,
-	0x97, 0x41, 0xa0, 0x71, 0x45, 0x55, 0xc8, 0x0f, 0xb1, 0xe7, 0xe9, 0x03, 0xcc, 0x7c, 0x09, 0x87,
-	0x62, 0x0b, 0x04, 0xda, 0xe6, 0x44, 0xc4, 0x1d, 0x59, 0x84, 0xd7, 0x99, 0x73, 0xe1, 0x10, 0xbd,
-	0x0b, 0x45, 0xe2, 0xa7, 0xe7, 0xe8, 0x3d, 0xcc, 0x5e, 0xd9, 0xf1, 0x04, 0x39, 0xa1, 0x2a, 0xb3,
-	0x26, 0xcd, 0xa8, 0xb2, 0xf8, 0x0b, 0x07, 0x2b, 0x71, 0x3a, 0x76, 0x75, 0x87, 0x10, 0x34, 0xfd,
-	0x66, 0x77, 0xea, 0xb5, 0x39, 0xb2, 0xb8, 0xab, 0x3b, 0x12, 0xfd, 0x60, 0xaf, 0x3f, 0xfa, 0x5d,
-	0x7b, 0x02, 0x10, 0x4f, 0x2e, 0xbf, 0x13, 0xb7, 0xa1, 0x1c, 0x2f, 0xec, 0x98, 0x9e, 0x4f, 0x0c,
-	0x26, 0x3d, 0x9f, 0xcf, 0x20, 0xfd, 0x77, 0x2f, 0xff, 0xb5, 0x40, 0x97, 0x0e, 0x72, 0x34, 0x85,
-	0x37, 0xfe, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x41, 0x5e, 0x49, 0xf4, 0xbe, 0x16, 0x00, 0x00,
+	// 3337 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0x4f, 0x77, 0x1b, 0x47,
+	0x72, 0x37, 0xfe, 0x03, 0x05, 0x92, 0x82, 0x5a, 0xb2, 0x3c, 0xc1, 0xdb, 0x68, 0x95, 0xd9, 0x5d,
+	0xaf, 0xe2, 0x5d, 0x53, 0x16, 0x25, 0x79, 0x25, 0x6b, 0x65, 0x8b, 0x24, 0x40, 0x0b, 0x11, 0x29,
+	0xd2, 0x43, 0xca, 0x7a, 0x8e, 0x63, 0xfb, 0x35, 0x67, 0x1a, 0xd4, 0x58, 0x83, 0x99, 0xf1, 0x4c,
+	0x43, 0x14, 0x73, 0xcc, 0x21, 0xc7, 0x1c, 0x72, 0xce, 0x07, 0xc8, 0x3d, 0x87, 0x1c, 0xf3, 0x01,
+	0x72, 0xca, 0x31, 0xef, 0x25, 0x2f, 0xb9, 0x26, 0xef, 0xe5, 0x90, 0x4b, 0x0e, 0x39, 0xed, 0xab,
+	0xee, 0x9e, 0x99, 0x1e, 0x00, 0xe4, 0x00, 0x34, 0xe5, 0x0b, 0x89, 0xae, 0xae, 0xfa, 0x75, 0x75,
+	0x4f, 0x75, 0x75, 0x55, 0x75, 0xc3, 0xbb, 0xe1, 0xab, 0xa3, 0x5b, 0xfc, 0x24, 0x64, 0xb1, 0xfc,
+	0xbb, 0x1a, 0x46, 0x01, 0x0f, 0xc8, 0x7b, 0x43, 0x37, 0x8e, 0xdd, 0xc0, 0x5f, 0x3d, 0x0e, 0xa2,
+	0x57, 0x43, 0x2f, 0x38, 0x8e, 0x57, 0x45, 0x77, 0xf7, 0xe7, 0x47, 0x41, 0x70, 0xe4, 0xb1, 0x5b,
+	0x82, 0xed, 0x70, 0x3c, 0xbc, 0xc5, 0xdd, 0x11, 0x8b, 0x39, 0x1d, 0x85, 0x52, 0xb2, 0x7b, 0x7d,
+	0x92, 0xc1, 0x19, 0x47, 0x94, 0x23, 0x94, 0xec, 0xdf, 0x3e, 0x72, 0xf9, 0xcb, 0xf1, 0xe1, 0xaa,
+	0x1d, 0x8c, 0x6e, 0xa9, 0x41, 0x92, 0xff, 0x1f, 0xa6, 0x83, 0xdd, 0xca, 0x6b, 0xe5, 0xbc, 0xa6,
+	0xde, 0x38, 0xff, 0x5b, 0xa2, 0x99, 0xff, 0x5c, 0x82, 0xe6, 0x0b, 0x25, 0x45, 0x36, 0xa1, 0x39,
+	0x62, 0x9c, 0x3a, 0x94, 0x53, 0xa3, 0x74, 0xa3, 0x74, 0xb3, 0xbd, 0xf6, 0xeb, 0xd5, 0x53, 0xe6,
+	0xb1, 0xba, 0x7b, 0xf8, 0x3d, 0xb3, 0xf9, 0x8e, 0x62, 0xb7, 0x52, 0x41, 0xf2, 0x00, 0xaa, 0x71,
+	0xc8, 0x6c, 0xa3, 0x2c, 0x00, 0x7e, 0x75, 0x2a, 0x40, 0x32, 0xea, 0x7e, 0xc8, 0x6c, 0x4b, 0x88,
+	0x90, 0xcf, 0xa0, 0x1e, 0x73, 0xca, 0xc7, 0xb1, 0x51, 0x29, 0x18, 0x3d, 0x15, 0x16, 0xec, 0x96,
+	0x12, 0x33, 0xff, 0xb7, 0x09, 0x4b, 0x3a, 0x2e, 0xb9, 0x0e, 0x40, 0x43, 0xf7, 0x4b, 0x16, 0x21,
+	0x8a, 0x98, 0x53, 0xcb, 0xd2, 0x28, 0x64, 0x0b, 0x6a, 0x9c, 0xc6, 0xaf, 0x62, 0xa3, 0x7c, 0xa3,
+	0x72, 0xb3, 0xbd, 0xf6, 0xd1, 0x5c, 0xda, 0xae, 0x1e, 0xa0, 0x48, 0xdf, 0xe7, 0xd1, 0x89, 0x25,
+	0xc5, 0x71, 0x9c, 0x60, 0xcc, 0xc3, 0x31, 0xc7, 0x2e, 0xa1, 0x7d, 0xcb, 0xd2, 0x28, 0xe4, 0x06,
+	0xb4, 0x1d, 0x16, 0xdb, 0x91, 0x1b, 0xe2, 0x97, 0x34, 0xaa, 0x82, 0x41, 0x27, 0x11, 0x03, 0x1a,
+	0xc3, 0x20, 0xb2, 0xd9, 0xc0, 0x31, 0x6a, 0xa2, 0x37, 0x69, 0x12, 0x02, 0x55, 0x9f, 0x8e, 0x98,
+	0x51, 0x17, 0x64, 0xf1, 0x9b, 0x74, 0xa1, 0xe9, 0xfa, 0x9c, 0x45, 0x3e, 0xf5, 0x8c, 0xc6, 0x8d,
+	0xd2, 0xcd, 0xa6, 0x95, 0xb6, 0x11, 0x29, 0x8c, 0xd8, 0x31, 0x8d, 0x46, 0x46, 0x53, 0x74, 0x25,
+	0x4d, 0xf2, 0x01, 0x74, 0xe2, 0xb1, 0x6d, 0xb3, 0x38, 0xde, 0x0c, 0x7c, 0xc7, 0x15, 0xaa, 0xb4,
+	0x04, 0xea, 0x14, 0x9d, 0xac, 0xc1, 0x55, 0x9b, 0xfa, 0x36, 0xf3, 0xd6, 0x0f, 0xa9, 0xef, 0x04,
+	0x3e, 0x73, 0xc4, 0xac, 0x0d, 0x10, 0x90, 0x33, 0xfb, 0xc8, 0x00, 0xc0, 0x0e, 0x46, 0xa1, 0xc7,
+	0x04, 0x72, 0x5b, 0x7c, 0xc3, 0x3f, 0x3d, 0x75, 0x49, 0x37, 0x53, 0xd6, 0xbd, 0xc0, 0x73, 0xed,
+	0x13, 0x4b, 0x13, 0x26, 0xdb, 0xd0, 0xb6, 0x03, 0xdf, 0x1e, 0x47, 0x11, 0xf3, 0xed, 0x13, 0x63,
+	0x49, 0x60, 0x7d, 0x70, 0x06, 0x56, 0xca, 0xab, 0xc0, 0x74, 0x71, 0x5c, 0xfe, 0x88, 0xf1, 0xe8,
+	0x64, 0x63, 0xec, 0x1c, 0x31, 0x6e, 0x2c, 0xdf, 0x28, 0xdd, 0xac, 0x59, 0x3a, 0x89, 0xdc, 0x85,
+	0x77, 0xe3, 0x60, 0xc8, 0x0f, 0xdc, 0x11, 0x0b, 0xc6, 0x7c, 0x8f, 0x45, 0x36, 0xf3, 0x39, 0x3d,
+	0x62, 0xc6, 0x8a, 0xe0, 0x9d, 0xdd, 0x49, 0x76, 0xa1, 0x19, 0x1f, 0xbb, 0xdc, 0x7e, 0xc9, 0x62,
+	0xe3, 0x92, 0xb0, 0xa0, 0x3b, 0xf3, 0x59, 0xd0, 0xbe, 0x92, 0x92, 0x46, 0x94, 0x82, 0x90, 0x3e,
+	0x34, 0xed, 0xc0, 0xe7, 0x11, 0xb5, 0xb9, 0xd1, 0x29, 0x58, 0xbf, 0x04, 0x70, 0x53, 0x09, 0x58,
+	0xa9, 0x28, 0xb9, 0x09, 0x97, 0x5c, 0x3f, 0x1c, 0xf3, 0x1d, 0xd7, 0x71, 0x3c, 0xfc, 0xf6, 0xcc,
+	0xb8, 0x7c, 0xa3, 0x72, 0xb3, 0x65, 0x4d, 0x92, 0x91, 0x33, 0xb6, 0x83, 0x90, 0xed, 0xf3, 0xc8,
+	0xb5, 0xb9, 0xcf, 0xe2, 0xd8, 0x20, 0xc2, 0x22, 0x26, 0xc9, 0xe4, 0x7d, 0x58, 0x19, 0xd1, 0x37,
+	0x7b, 0x34, 0xa2, 0x9e, 0xc7, 0x3c, 0x37, 0x1e, 0x19, 0x57, 0xc4, 0xd2, 0x4c, 0x50, 0xc9, 0x1d,
+	0x68, 0x70, 0xb9, 0x50, 0xc6, 0x55, 0x31, 0x83, 0x3f, 0x5a, 0x95, 0x1e, 0x6d, 0x35, 0xf1, 0x68,
+	0xab, 0x3d, 0xe5, 0xd1, 0xac, 0x84, 0xb3, 0xfb, 0x35, 0x40, 0xb6, 0xa9, 0x48, 0x07, 0x2a, 0xaf,
+	0xd8, 0x89, 0xda, 0xae, 0xf8, 0x93, 0xfc, 0x0e, 0x6a, 0xc2, 0x6d, 0x29, 0xaf, 0xf2, 0x27, 0xa7,
+	0x2e, 0x0a, 0xa2, 0x08, 0x8f, 0x22, 0xf9, 0x3f, 0x29, 0xdf, 0x2f, 0x75, 0xff, 0x02, 0x96, 0x73,
+	0xeb, 0x3d, 0x03, 0xff, 0x5e, 0x1e, 0xff, 0xe7, 0xa7, 0xe2, 0x4b, 0x20, 0x0d, 0xdd, 0xfc, 0x8f,
+	0x2a, 0xac, 0xe4, 0xdd, 0x11, 0xd9, 0x4a, 0xfd, 0x18, 0x0e, 0xb1, 0xb2, 0xb6, 0x3a, 0xa7, 0x1f,
+	0x5b, 0xcd, 0xbb, 0x33, 0x72, 0x1f, 0x5a, 0xe3, 0xd0, 0xa1, 0x9c, 0x39, 0xeb, 0x5c, 0x69, 0xd6,
+	0x9d, 0x5a, 0xcc, 0x83, 0xe4, 0xfc, 0xb0, 0x32, 0x66, 0xf2, 0x24, 0xf1, 0x6b, 0x15, 0x61, 0x95,
+	0x6b, 0xf3, 0x2a, 0x30, 0xed, 0xd9, 0xee, 0x42, 0x8d, 0x45, 0x51, 0x10, 0x09, 0x9f, 0xd5, 0x5e,
+	0xbb, 0x7e, 0x2a, 0x52, 0x1f, 0xb9, 0x2c, 0xc9, 0x4c, 0x7e, 0x09, 0xcb, 0x21, 0x8d, 0x62, 0xb6,
+	0xce, 0x39, 0x1b, 0x85, 0x3c, 0x16, 0x3e, 0xad, 0x66, 0xe5, 0x89, 0x39, 0x6b, 0xaf, 0x9f, 0xdf,
+	0xda, 0x9f, 0x02, 0xfc, 0x30, 0xa6, 0x11, 0xf5, 0xb9, 0xeb, 0x33, 0xe1, 0x0e, 0xdb, 0x6b, 0xbf,
+	0x29, 0x04, 0xfa, 0x22, 0x15, 0xb1, 0x34, 0xf1, 0xee, 0x8b, 0x02, 0x4b, 0xbc, 0x93, 0xb7, 0x94,
+	0x3f, 0x3e, 0xd3, 0x12, 0x75, 0x3b, 0xb9, 0x0f, 0x75, 0x65, 0x1e, 0x00, 0xf5, 0x2f, 0x9e, 0xf7,
+	0x9f, 0xf7, 0x7b, 0x9d, 0x77, 0x48, 0x0b, 0x6a, 0x56, 0x7f, 0xbd, 0xf7, 0x55, 0xa7, 0x8c, 0xe4,
+	0xad, 0xf5, 0xc1, 0x76, 0xbf, 0xd7, 0xa9, 0x90, 0x36, 0x34, 0x7a, 0xfd, 0xed, 0xfe, 0x41, 0xbf,
+	0xd7, 0xa9, 0x9a, 0xff, 0x52, 0x06, 0x32, 0xad, 0x35, 0x3a, 0xb5, 0x4c, 0x6f, 0x47, 0xe8, 0xd8,
+	0xb4, 0x74, 0x12, 0xb9, 0x06, 0xf5, 0x88, 0xd1, 0x38, 0xf0, 0x85, 0xb2, 0x2d, 0x4b, 0xb5, 0xc8,
+	0x63, 0x58, 0xd6, 0xd8, 0xd6, 0xb9, 0x51, 0x29, 0xb4, 0xad, 0xbc, 0x00, 0xf9, 0x08, 0xae, 0xd8,
+	0x81, 0x1f, 0x33, 0x7b, 0xcc, 0xdd, 0xd7, 0x6c, 0x8b, 0xba, 0xde, 0x38, 0x62, 0xb1, 0xb0, 0x91,
+	0x9a, 0x35, 0xab, 0x8b, 0xdc, 0x86, 0xfa, 0xb1, 0xeb, 0x3b, 0xc1, 0xb1, 0x51, 0x2b, 0xf2, 0x0a,
+	0x8a, 0x31, 0x3f, 0xc1, 0x58, 0x58, 0x48, 0x4d, 0x9f, 0xa0, 0xd8, 0x20, 0xb6, 0xc7, 0x68, 0x24,
+	0x26, 0xd1, 0x28, 0xde, 0x20, 0x29, 0xb3, 0xf9, 0x5f, 0xa5, 0x6c, 0x4d, 0x07, 0xfe, 0xeb, 0xc0,
+	0x16, 0x43, 0x5f, 0x4c, 0x04, 0xb4, 0x99, 0x8b, 0x80, 0x6e, 0x15, 0x5a, 0x62, 0x36, 0xbe, 0x16,
+	0x0b, 0x0d, 0x26, 0x62, 0xa1, 0xdb, 0x8b, 0xc0, 0xe4, 0xa3, 0xa2, 0xff, 0xab, 0xc2, 0xb5, 0xd9,
+	0x63, 0x61, 0xdc, 0x92, 0xc0, 0x0d, 0x9c, 0x24, 0x3e, 0xca, 0x28, 0x64, 0x1f, 0xea, 0xe2, 0xc4,
+	0x48, 0x02, 0xa4, 0x87, 0x0b, 0x4e, 0x66, 0x75, 0x20, 0xa4, 0xa5, 0x47, 0x51, 0x50, 0x18, 0xbc,
+	0x84, 0x34, 0x62, 0x3e, 0x1f, 0x38, 0x2a, 0x54, 0x4a, 0xdb, 0xe4, 0x11, 0x34, 0x13, 0x64, 0xa3,
+	0x5a, 0xe0, 0xeb, 0x93, 0x21, 0xad, 0x54, 0x84, 0x7c, 0x0c, 0xcd, 0x1e, 0xa3, 0x8e, 0x87, 0x8e,
+	0xa0, 0x56, 0x68, 0x0f, 0x29, 0x2f, 0xc6, 0x4c, 0x47, 0x51, 0x30, 0x0e, 0x07, 0x8e, 0x0a, 0xb3,
+	0x92, 0x26, 0xae, 0x80, 0x47, 0x0f, 0x99, 0x17, 0x1b, 0x8d, 0xf3, 0xad, 0xc0, 0xb6, 0x90, 0x56,
+	0x2b, 0x20, 0xa1, 0x88, 0x09, 0x4b, 0x72, 0xc6, 0xe8, 0x24, 0x06, 0x8e, 0x88, 0xd3, 0x5a, 0x56,
+	0x8e, 0x86, 0xe7, 0xad, 0x6c, 0xef, 0x45, 0x6e, 0x10, 0xb9, 0xfc, 0x44, 0x85, 0x6a, 0x13, 0xd4,
+	0xee, 0xb7, 0xd0, 0xd6, 0x16, 0x79, 0x86, 0xc7, 0x7a, 0x90, 0xf7, 0x58, 0xbf, 0x38, 0xdd, 0x63,
+	0x61, 0x76, 0xf0, 0x25, 0xb2, 0xea, 0xa7, 0xe7, 0x03, 0x68, 0x6b, 0x53, 0x98, 0x81, 0x7f, 0x55,
+	0xc7, 0x6f, 0xe9, 0x2e, 0xef, 0xef, 0x2e, 0x83, 0x71, 0x9a, 0x75, 0x92, 0xbd, 0x89, 0x43, 0xf2,
+	0xfe, 0xc2, 0x06, 0x7e, 0x71, 0xc7, 0xa5, 0x95, 0x3f, 0x2e, 0x7f, 0xbf, 0xb8, 0x2a, 0xd3, 0x07,
+	0xe7, 0x43, 0xa8, 0xcb, 0x04, 0xc0, 0xa8, 0xce, 0xbf, 0xee, 0x4a, 0x84, 0x1c, 0xc1, 0x92, 0x73,
+	0xe2, 0xd3, 0x91, 0x6b, 0xcb, 0xa8, 0xbb, 0x26, 0xf4, 0xda, 0x5c, 0x5c, 0xaf, 0x9e, 0x86, 0x22,
+	0xd5, 0xcb, 0x01, 0x67, 0xc7, 0x7b, 0x7d, 0x91, 0xe3, 0x7d, 0x00, 0xcb, 0x52, 0xd1, 0x27, 0x8c,
+	0x3a, 0x2c, 0x8a, 0x8d, 0xc6, 0xfc, 0x53, 0xcc, 0x4b, 0xa2, 0x93, 0x0f, 0xe9, 0x89, 0x17, 0x50,
+	0x67, 0xdf, 0xfd, 0x4b, 0x26, 0x76, 0x42, 0xc5, 0xd2, 0x49, 0xb8, 0x11, 0x68, 0x3e, 0x07, 0x69,
+	0x89, 0x58, 0x76, 0x82, 0x4a, 0xbe, 0x85, 0x96, 0x47, 0x39, 0x4b, 0xd2, 0x14, 0x5c, 0xb0, 0xc7,
+	0x8b, 0x2f, 0xd8, 0x76, 0x02, 0x21, 0x57, 0x2b, 0x83, 0x44, 0x3d, 0xb2, 0x04, 0x65, 0x27, 0x70,
+	0x98, 0xc8, 0x70, 0x5a, 0xd6, 0x04, 0x15, 0x67, 0xa4, 0x28, 0xcc, 0xd9, 0xc0, 0xd4, 0x05, 0x95,
+	0xd5, 0x49, 0xe8, 0x6d, 0x30, 0xf7, 0x70, 0x59, 0xac, 0x52, 0x91, 0xa4, 0x89, 0x69, 0x8f, 0x9e,
+	0xa8, 0xac, 0x14, 0xa4, 0x3d, 0x56, 0xc6, 0xab, 0xf6, 0x82, 0x2e, 0x8e, 0xa7, 0xb4, 0x96, 0xb7,
+	0xf4, 0xdf, 0xd8, 0x8c, 0x39, 0xcc, 0x31, 0x2e, 0x89, 0x48, 0x61, 0x56, 0x17, 0xf9, 0x1a, 0x9a,
+	0x87, 0x11, 0xf5, 0x45, 0x42, 0xd3, 0x11, 0x4b, 0xf8, 0xd9, 0xe2, 0x4b, 0xb8, 0xa1, 0x10, 0x54,
+	0x72, 0x93, 0x00, 0x92, 0x11, 0xac, 0x78, 0x41, 0x10, 0x0e, 0x38, 0x93, 0x07, 0x7d, 0x2c, 0x92,
+	0x92, 0xf6, 0x5a, 0xff, 0x1c, 0x5f, 0x29, 0x87, 0x23, 0x07, 0x9a, 0x00, 0xc7, 0xe1, 0xf8, 0xcb,
+	0x28, 0xe0, 0xdc, 0x4b, 0xec, 0x86, 0x9c, 0x77, 0xb8, 0x83, 0x1c, 0x8e, 0x1a, 0x2e, 0x0f, 0x4e,
+	0x3e, 0x01, 0x88, 0x58, 0xe8, 0xd1, 0x13, 0xe1, 0x7e, 0xae, 0x14, 0xba, 0x1f, 0x8d, 0x9b, 0x7c,
+	0x0f, 0xcb, 0x22, 0xdd, 0x7a, 0x41, 0x23, 0xdf, 0xf5, 0x8f, 0x62, 0xe3, 0xaa, 0xd0, 0xb4, 0x77,
+	0x0e, 0x97, 0xa8, 0xc3, 0x48, 0x45, 0xf3, 0xd0, 0xe4, 0xb7, 0x70, 0x99, 0x0d, 0x87, 0xcc, 0xc6,
+	0xe8, 0x2c, 0x3d, 0x5a, 0xde, 0x15, 0x96, 0x3c, 0xdd, 0xd1, 0xa5, 0x05, 0xe1, 0xf0, 0xa3, 0xfc,
+	0xe1, 0xf2, 0xeb, 0x33, 0xc3, 0xe1, 0x4c, 0x5b, 0xfd, 0x80, 0xf9, 0x16, 0x2e, 0x4f, 0x79, 0xa9,
+	0x0b, 0x0c, 0xbc, 0xbb, 0x0c, 0x56, 0xf2, 0x9b, 0xfa, 0xed, 0x4c, 0xe3, 0x21, 0x2c, 0xe7, 0x0c,
+	0x7f, 0x91, 0x93, 0xb2, 0xbb, 0x0e, 0x57, 0x66, 0x98, 0x74, 0x11, 0x44, 0x45, 0x87, 0x78, 0x09,
+	0x57, 0x66, 0x98, 0xe9, 0x0c, 0x88, 0x87, 0xf9, 0xb9, 0xfe, 0xea, 0xcc, 0xb9, 0x26, 0x90, 0xfa,
+	0x48, 0x47, 0x40, 0xa6, 0xcd, 0xec, 0xc7, 0x0c, 0xa4, 0xa3, 0xe9, 0xf1, 0xc3, 0x37, 0x69, 0xca,
+	0xd4, 0x86, 0xc6, 0xf3, 0x67, 0x4f, 0x9f, 0xed, 0xbe, 0x78, 0xd6, 0x79, 0x87, 0x2c, 0x43, 0x6b,
+	0x7f, 0xf3, 0x49, 0xbf, 0xf7, 0x1c, 0x73, 0xa5, 0x12, 0xb9, 0x04, 0xed, 0xc1, 0xb3, 0xef, 0xf6,
+	0xac, 0xdd, 0xcf, 0xad, 0xfe, 0xfe, 0x7e, 0xa7, 0x2c, 0xfa, 0x9f, 0x6f, 0x6e, 0xf6, 0xfb, 0x3d,
+	0x91, 0x4b, 0x65, 0x79, 0x55, 0x15, 0x71, 0xd6, 0x37, 0x76, 0x2d, 0xcc, 0xab, 0x6a, 0xe6, 0x5f,
+	0x95, 0x60, 0x49, 0x1f, 0x1a, 0xf3, 0x25, 0x15, 0xed, 0x96, 0x84, 0xd3, 0x56, 0x2d, 0x8c, 0x92,
+	0x23, 0x36, 0x64, 0x58, 0x4b, 0x62, 0x32, 0x12, 0x6e, 0x59, 0x1a, 0x45, 0x6e, 0x7d, 0x3b, 0x88,
+	0x9c, 0x39, 0x93, 0x29, 0x8d, 0xdb, 0xfc, 0x1c, 0x2e, 0x4f, 0x79, 0x71, 0xfc, 0xca, 0x9e, 0x3b,
+	0x72, 0xb9, 0x58, 0xcd, 0x9a, 0x25, 0x1b, 0xe4, 0x67, 0xd0, 0x8a, 0xd8, 0x88, 0xba, 0xa8, 0xab,
+	0x58, 0xd3, 0x9a, 0x95, 0x11, 0xcc, 0xff, 0x29, 0x41, 0xa7, 0xc7, 0x42, 0xe6, 0x3b, 0x58, 0xf2,
+	0xda, 0x0c, 0xfc, 0xa1, 0x7b, 0x44, 0xf6, 0xa1, 0x19, 0xb1, 0x1f, 0xc6, 0x6e, 0xc4, 0xe4, 0x9c,
+	0xda, 0x6b, 0xbf, 0x3b, 0xf5, 0x2b, 0x4c, 0x0a, 0xaf, 0x5a, 0x4a, 0x52, 0xf9, 0xf1, 0x04, 0x08,
+	0xb5, 0xa3, 0xc7, 0xd4, 0xe5, 0x4a, 0x07, 0xd9, 0xe8, 0xfa, 0xb0, 0x9c, 0x13, 0x98, 0x61, 0x10,
+	0x9f, 0xe7, 0x0d, 0xe2, 0xf6, 0x99, 0x96, 0x97, 0xa9, 0x83, 0xd5, 0xa5, 0x11, 0xe3, 0x2c, 0x8a,
+	0x75, 0xe3, 0xf8, 0xa7, 0x12, 0x54, 0x91, 0xef, 0x62, 0x72, 0xb6, 0x7b, 0xb9, 0x9c, 0x6d, 0x8e,
+	0xfa, 0x92, 0x60, 0xc7, 0x20, 0x2f, 0x97, 0xa5, 0xfd, 0xe2, 0x6c, 0xc1, 0x7c, 0x5e, 0xf6, 0xaf,
+	0x4b, 0xd0, 0x4c, 0xf0, 0x30, 0x6a, 0x18, 0x8e, 0x7d, 0x5b, 0x78, 0x15, 0x36, 0x54, 0xab, 0xa6,
+	0x93, 0x48, 0x7f, 0x22, 0x17, 0xfb, 0xb0, 0x50, 0xc9, 0x99, 0xd9, 0xd7, 0x53, 0xcd, 0x24, 0x64,
+	0xb8, 0x7b, 0xab, 0x18, 0xa8, 0xd0, 0x14, 0xaa, 0x9a, 0x29, 0x68, 0xa1, 0x6f, 0x6d, 0xf1, 0xd0,
+	0x77, 0x2a, 0xb6, 0xac, 0x9f, 0x3b, 0xb6, 0xd4, 0x4a, 0x91, 0x8d, 0x79, 0x4b, 0x91, 0x98, 0x9b,
+	0xb1, 0x37, 0x58, 0xbd, 0x08, 0x22, 0x44, 0x4e, 0x72, 0x33, 0x9d, 0x96, 0x95, 0xfb, 0xf7, 0x28,
+	0x7f, 0xa9, 0xf2, 0x32, 0x8d, 0x82, 0x19, 0x2e, 0x1d, 0x0e, 0x5d, 0x1f, 0x8f, 0x56, 0x90, 0x19,
+	0x6e, 0xd2, 0x46, 0x59, 0xd7, 0x61, 0xa3, 0x30, 0xe0, 0xcc, 0xe7, 0x22, 0x84, 0x6c, 0x5a, 0x1a,
+	0x85, 0x7c, 0x8a, 0x45, 0x1b, 0x07, 0x4b, 0x62, 0x4b, 0xe2, 0xeb, 0xbc, 0x7f, 0x46, 0xf4, 0x87,
+	0x6c, 0xa8, 0xfc, 0xd8, 0x63, 0x96, 0x92, 0x22, 0x9f, 0x40, 0x4d, 0xc4, 0x80, 0x22, 0xb4, 0x6c,
+	0xaf, 0xfd, 0xf2, 0xec, 0xe0, 0x51, 0x55, 0xcb, 0xa5, 0x48, 0x5a, 0x37, 0xb6, 0x32, 0x6f, 0xb7,
+	0x22, 0x14, 0x9c, 0x24, 0xcb, 0x20, 0xd7, 0x47, 0x85, 0xc5, 0x22, 0x5d, 0x92, 0xe6, 0xaa, 0x91,
+	0x30, 0x93, 0x1f, 0x52, 0xd7, 0x0b, 0x5e, 0xb3, 0xc8, 0xe8, 0x14, 0xec, 0xaa, 0x2d, 0xc5, 0x68,
+	0xa5, 0x22, 0xe4, 0x31, 0xb4, 0x22, 0xca, 0xd9, 0xb6, 0x70, 0x83, 0x97, 0x85, 0xbc, 0x79, 0xfa,
+	0x54, 0x12, 0x4e, 0x2b, 0x13, 0xc2, 0x92, 0x3e, 0xa2, 0x31, 0x27, 0x55, 0x5b, 0x4e, 0x56, 0x15,
+	0xb8, 0x67, 0x77, 0x92, 0x37, 0xf0, 0xde, 0xac, 0x0e, 0x8c, 0xd5, 0xaf, 0x88, 0xef, 0xf1, 0x69,
+	0xf1, 0x6e, 0xd9, 0x9a, 0x0d, 0x20, 0x37, 0xcf, 0x69, 0xf0, 0x18, 0x98, 0xc9, 0x5b, 0x95, 0xbd,
+	0x28, 0x08, 0xe9, 0x91, 0x30, 0x4b, 0x51, 0x42, 0x6f, 0x59, 0xd3, 0x1d, 0x78, 0x2b, 0x14, 0x8e,
+	0x23, 0x26, 0x22, 0xb7, 0xa6, 0x25, 0x7e, 0x93, 0x35, 0xa8, 0xc4, 0x5e, 0x60, 0x5c, 0x13, 0xab,
+	0x75, 0xe3, 0x6c, 0x3d, 0xb7, 0x77, 0x2d, 0x64, 0x26, 0x07, 0xd0, 0x1e, 0xb1, 0xe8, 0x88, 0x49,
+	0x57, 0x61, 0xbc, 0x57, 0x50, 0x2f, 0x4e, 0xe7, 0xb8, 0x93, 0x09, 0xc9, 0x79, 0xe9, 0x30, 0x6f,
+	0xbd, 0x28, 0xf1, 0x13, 0x1f, 0x36, 0xdd, 0x3f, 0x83, 0x9f, 0x9d, 0xf5, 0x51, 0x17, 0x8a, 0xf5,
+	0x6c, 0xe8, 0x4c, 0x2e, 0xde, 0x8f, 0x59, 0x20, 0x01, 0x23, 0x00, 0xf5, 0xd3, 0xf1, 0x01, 0x2e,
+	0x90, 0xe6, 0x1e, 0x84, 0xbd, 0xa0, 0xb3, 0x92, 0x43, 0x88, 0xdf, 0xa8, 0x63, 0xcc, 0x23, 0x37,
+	0x14, 0x63, 0x34, 0x2d, 0xd9, 0x30, 0xff, 0xb6, 0x0c, 0x6d, 0xcd, 0x37, 0xe0, 0x56, 0x1f, 0xd1,
+	0x37, 0x69, 0x25, 0x5f, 0x86, 0x24, 0x3a, 0x89, 0x3c, 0x82, 0x25, 0x1e, 0x70, 0xea, 0xa9, 0x6c,
+	0xd2, 0x28, 0x17, 0x39, 0xdb, 0x1c, 0x3b, 0x8a, 0xa3, 0x6b, 0x74, 0xa9, 0xd7, 0x63, 0x1e, 0x3d,
+	0x31, 0x2a, 0x85, 0xe2, 0x3a, 0x3b, 0xee, 0x9b, 0x43, 0x6a, 0xbf, 0x0a, 0x86, 0xc3, 0x9d, 0xb1,
+	0xc7, 0xdd, 0xd0, 0x73, 0x99, 0xbc, 0xad, 0x28, 0x59, 0xd3, 0x1d, 0xe4, 0x1e, 0x34, 0x47, 0xf4,
+	0x8d, 0x1c, 0xa8, 0xb0, 0x12, 0x9d, 0xb2, 0x9a, 0x1b, 0xd0, 0x4c, 0x9c, 0xd4, 0x1c, 0x47, 0x35,
+	0x1e, 0x8b, 0x43, 0xce, 0xa2, 0x34, 0x42, 0xc2, 0x86, 0x19, 0x42, 0x2b, 0x75, 0x54, 0x78, 0x0c,
+	0xc8, 0x23, 0x45, 0x24, 0xc2, 0x72, 0x51, 0x35, 0x8a, 0x56, 0x2f, 0x2f, 0xcf, 0x5b, 0x2f, 0x57,
+	0x46, 0x54, 0x49, 0x8d, 0xc8, 0xfc, 0x87, 0x12, 0x34, 0xd4, 0x6e, 0xc7, 0xc3, 0xd0, 0xa3, 0x5c,
+	0xdc, 0xa6, 0x96, 0x0a, 0x0f, 0x43, 0xc5, 0x89, 0x5a, 0x86, 0xf2, 0xba, 0xd3, 0xf5, 0xa4, 0x29,
+	0x96, 0x2c, 0x8d, 0x82, 0x4b, 0xa1, 0x6e, 0x8e, 0x71, 0x66, 0x62, 0xe8, 0x92, 0xa5, 0x93, 0xb4,
+	0x79, 0x54, 0xe7, 0x9c, 0x87, 0x19, 0xc1, 0x92, 0x9e, 0x7a, 0x90, 0x8f, 0xa0, 0x16, 0xbb, 0xbe,
+	0xcd, 0x8c, 0x52, 0x61, 0x64, 0x2d, 0x19, 0x51, 0x62, 0x8c, 0x0a, 0xce, 0x51, 0x05, 0x94, 0x8c,
+	0xe6, 0x7f, 0x97, 0x01, 0xb2, 0x10, 0x8d, 0x6c, 0x4c, 0x14, 0x27, 0x3f, 0x98, 0x23, 0xae, 0xbb,
+	0xb8, 0x72, 0xe4, 0x5d, 0xa8, 0x0d, 0x85, 0x69, 0x55, 0x0a, 0x8a, 0x72, 0x5b, 0xc8, 0x65, 0x49,
+	0xe6, 0x73, 0xde, 0xd4, 0xf5, 0x60, 0x39, 0x39, 0x73, 0x05, 0x9a, 0x51, 0x2b, 0x90, 0x96, 0x63,
+	0xe6, 0x85, 0xcc, 0xdf, 0xea, 0x99, 0xda, 0xfe, 0xc1, 0xba, 0x75, 0x90, 0xbf, 0xdd, 0x2a, 0x69,
+	0x59, 0x58, 0xd9, 0xfc, 0xeb, 0x32, 0x18, 0xa7, 0x79, 0x5d, 0x72, 0x00, 0x55, 0x1c, 0x48, 0x2d,
+	0xfc, 0xe3, 0x85, 0xdd, 0xb6, 0x96, 0xc7, 0xe0, 0xd9, 0x61, 0x09, 0x34, 0xb1, 0x23, 0x3d, 0x97,
+	0xc6, 0x89, 0x3b, 0x16, 0x0d, 0xb2, 0x0e, 0x2d, 0x1e, 0x51, 0x3f, 0x1e, 0x06, 0xd1, 0xc8, 0xa8,
+	0xcc, 0x7f, 0x12, 0x65, 0x52, 0xe6, 0x43, 0x58, 0xc9, 0x0f, 0x48, 0x9a, 0x50, 0xed, 0xad, 0x1f,
+	0xac, 0x77, 0xde, 0xc1, 0xb5, 0xd8, 0xdc, 0x7d, 0x76, 0x60, 0xed, 0x6e, 0x77, 0x4a, 0x84, 0xc0,
+	0x4a, 0xef, 0xab, 0x67, 0xeb, 0x3b, 0x83, 0xcd, 0xef, 0x76, 0x9f, 0x1f, 0xec, 0x3d, 0x3f, 0xe8,
+	0x94, 0xcd, 0x7f, 0x2f, 0xc1, 0x4a, 0xbe, 0xa2, 0x70, 0x31, 0xd9, 0xcc, 0x67, 0xb9, 0x6c, 0xe6,
+	0x37, 0x73, 0x56, 0x33, 0xb4, 0xbc, 0xa6, 0x3f, 0x91, 0xd7, 0x7c, 0x38, 0x2f, 0x44, 0x3e, 0xc3,
+	0xf9, 0xb7, 0x2a, 0x90, 0xe9, 0x31, 0x32, 0xfb, 0x2e, 0x2d, 0x62, 0xdf, 0xd7, 0xa0, 0xce, 0xe5,
+	0x75, 0x89, 0xba, 0xcd, 0x94, 0x2d, 0xb2, 0x9b, 0xe6, 0x45, 0x95, 0x82, 0x0c, 0x77, 0x5a, 0x95,
+	0x99, 0x19, 0x92, 0x89, 0xe7, 0x51, 0xc2, 0x35, 0x70, 0xd4, 0x6b, 0x9d, 0x1c, 0x8d, 0xdc, 0x86,
+	0x2a, 0x0e, 0x6f, 0xd4, 0xe6, 0x29, 0x46, 0x09, 0xd6, 0xdc, 0xdd, 0x54, 0x7d, 0x81, 0xbb, 0xa9,
+	0xc9, 0x84, 0xa4, 0x31, 0x23, 0x21, 0x31, 0xa0, 0x41, 0xe5, 0x69, 0x2c, 0xf2, 0x95, 0x9a, 0x95,
+	0x34, 0xc9, 0x06, 0xac, 0x0c, 0xdd, 0x28, 0xe6, 0xea, 0xb0, 0x5e, 0xe7, 0x46, 0xab, 0x70, 0xec,
+	0x09, 0x09, 0x4c, 0x67, 0xd2, 0x50, 0x5e, 0xbe, 0xff, 0x49, 0xdb, 0xd8, 0x17, 0x26, 0x55, 0xc4,
+	0xb6, 0xba, 0xcc, 0xfb, 0x89, 0xae, 0xa6, 0xcc, 0xff, 0xac, 0xc1, 0xd5, 0x59, 0xf6, 0x47, 0xb6,
+	0x27, 0xdc, 0xf7, 0xdd, 0x85, 0xcc, 0xf7, 0xe2, 0x1c, 0x79, 0x96, 0x08, 0x57, 0x16, 0x4f, 0x84,
+	0xcf, 0xe7, 0xcf, 0xa7, 0xd2, 0xe7, 0xda, 0xb9, 0xd3, 0xe7, 0x8f, 0xa1, 0xe9, 0x2c, 0x60, 0xb0,
+	0x09, 0x2f, 0x3e, 0x2f, 0x10, 0xe9, 0x64, 0x6a, 0xed, 0xc5, 0x37, 0xf3, 0x79, 0x01, 0xf4, 0xd6,
+	0x61, 0xe0, 0x79, 0xb1, 0x32, 0x66, 0xd9, 0xc0, 0x0b, 0x18, 0x8f, 0xc6, 0x7c, 0x2f, 0xf0, 0x3c,
+	0x8b, 0xc5, 0x63, 0x8f, 0x27, 0x37, 0xa2, 0x79, 0x2a, 0xf9, 0x14, 0x96, 0x12, 0x8a, 0xf8, 0x64,
+	0x50, 0x38, 0x7c, 0x8e, 0x3f, 0xcb, 0xee, 0x9f, 0xd0, 0xf8, 0xa5, 0x32, 0x6a, 0x8d, 0x62, 0x7e,
+	0xff, 0x56, 0xcb, 0x92, 0xd8, 0xd8, 0x7f, 0x3a, 0xd8, 0xdb, 0xeb, 0xf7, 0x3a, 0x75, 0xf3, 0x6f,
+	0x4a, 0xb0, 0x92, 0x77, 0xf3, 0x64, 0x05, 0xca, 0x6e, 0x72, 0x57, 0x5f, 0x76, 0xb3, 0xf7, 0x81,
+	0x65, 0xed, 0x7d, 0x20, 0x3e, 0x8c, 0x88, 0x98, 0x32, 0xd9, 0xca, 0x1c, 0x0f, 0x23, 0x12, 0x66,
+	0x9c, 0xfc, 0x11, 0xf3, 0x55, 0x19, 0x5a, 0x98, 0x5e, 0xc5, 0xd2, 0x28, 0xe6, 0x09, 0xd4, 0x84,
+	0xbd, 0xa1, 0xcb, 0x19, 0xb1, 0x38, 0xc6, 0x37, 0x72, 0x52, 0x97, 0xa4, 0x89, 0x0a, 0xd9, 0x81,
+	0x93, 0x2a, 0x84, 0xbf, 0x35, 0xe7, 0x5d, 0xc9, 0x39, 0x6f, 0xcd, 0x71, 0x55, 0xf3, 0x8e, 0xab,
+	0x03, 0x95, 0x88, 0x1e, 0xab, 0xc7, 0x90, 0xf8, 0xd3, 0xdc, 0x85, 0x9a, 0x38, 0x10, 0x50, 0x28,
+	0xc2, 0xb0, 0x2d, 0x9d, 0x74, 0xd2, 0xc4, 0x12, 0x29, 0xce, 0x3f, 0x0e, 0xa9, 0xcd, 0xd4, 0x48,
+	0x19, 0x01, 0x57, 0x6e, 0xd0, 0x53, 0xee, 0xbc, 0x3c, 0xe8, 0x99, 0xff, 0x58, 0x82, 0xe5, 0xcc,
+	0xfc, 0x77, 0x68, 0x88, 0x49, 0xa3, 0xf8, 0xad, 0x8a, 0xa5, 0xb7, 0xe7, 0xd8, 0x35, 0x3b, 0x34,
+	0x5c, 0x15, 0x3f, 0xd4, 0xed, 0xaf, 0xf8, 0xdd, 0xfd, 0x06, 0x20, 0x23, 0x5e, 0xbc, 0xe7, 0x7b,
+	0x0a, 0x2b, 0x59, 0xc7, 0xb6, 0x1b, 0x73, 0x04, 0xd4, 0x35, 0x9f, 0x0f, 0x50, 0xfc, 0x33, 0x0f,
+	0xa0, 0x33, 0xf9, 0x16, 0x13, 0xbf, 0xe1, 0x08, 0xbf, 0xa1, 0x4a, 0x17, 0xf1, 0x37, 0xee, 0xca,
+	0xec, 0xb1, 0x6c, 0x2b, 0xb9, 0xe7, 0xbe, 0x06, 0xf5, 0x1f, 0xc6, 0x41, 0x34, 0x96, 0x01, 0x54,
+	0xcd, 0x52, 0x2d, 0xb3, 0x0f, 0x97, 0xa7, 0x5e, 0x65, 0xce, 0x58, 0x08, 0xdc, 0x6c, 0x3e, 0x16,
+	0x9c, 0x3d, 0xd7, 0xe6, 0xea, 0x73, 0x6a, 0x14, 0xf3, 0xef, 0xcb, 0x50, 0x97, 0x8f, 0xee, 0x64,
+	0xca, 0x14, 0x46, 0x2c, 0xd6, 0x1f, 0xf3, 0x66, 0x14, 0x3c, 0x8a, 0xd2, 0xca, 0xa6, 0x54, 0x31,
+	0x6d, 0x93, 0x81, 0x76, 0xb1, 0x59, 0x29, 0x28, 0x9f, 0xca, 0xe1, 0x4e, 0xbd, 0xc6, 0x7c, 0x00,
+	0x0d, 0x87, 0x0d, 0x29, 0xfa, 0x9f, 0x6a, 0xc1, 0x6b, 0x41, 0x09, 0x61, 0x25, 0xfc, 0xf8, 0x12,
+	0xb1, 0xe8, 0x8e, 0x68, 0xee, 0x97, 0x88, 0x0a, 0x5b, 0x33, 0x8a, 0xeb, 0x50, 0x97, 0xc4, 0xec,
+	0x4b, 0x95, 0xb4, 0x2f, 0x65, 0x52, 0x58, 0x4e, 0x5e, 0xcf, 0x6d, 0xb9, 0xcc, 0x13, 0x9e, 0x23,
+	0x0d, 0xb5, 0x5b, 0x2a, 0x50, 0xee, 0x42, 0x33, 0x10, 0x2f, 0x92, 0xa9, 0xa7, 0xca, 0x02, 0x69,
+	0x7b, 0xf2, 0x15, 0x73, 0x65, 0xea, 0x15, 0xb3, 0xf9, 0xff, 0x65, 0xe8, 0x4c, 0xbe, 0xd4, 0x23,
+	0x3b, 0xb9, 0x6b, 0x95, 0xf6, 0xda, 0xbd, 0xb9, 0x1f, 0xf9, 0xcd, 0x0c, 0xcf, 0xf6, 0xa0, 0x21,
+	0x9d, 0x71, 0x52, 0x08, 0xff, 0x78, 0x7e, 0xbc, 0xdd, 0x31, 0xcf, 0x00, 0x13, 0x98, 0x2e, 0x2d,
+	0x8a, 0x53, 0x7e, 0x9f, 0xff, 0x28, 0xef, 0x9f, 0xf5, 0x0e, 0x39, 0x5b, 0x5f, 0xbd, 0xe8, 0x73,
+	0x08, 0x4b, 0xfa, 0xd8, 0x6f, 0x63, 0x0c, 0xd3, 0x05, 0xc8, 0x8a, 0x41, 0xe4, 0x11, 0x34, 0xe2,
+	0x60, 0x1c, 0xd9, 0x2c, 0x5e, 0xc4, 0x25, 0x24, 0x32, 0xe8, 0x49, 0x6d, 0xb5, 0x07, 0x93, 0xa4,
+	0x29, 0x23, 0x6c, 0x34, 0xfe, 0xbc, 0x26, 0x44, 0x0f, 0xeb, 0xe2, 0x34, 0xb9, 0xf3, 0x87, 0x01,
+	0x00, 0x2e, 0x0e, 0x09, 0x4d, 0x07, 0x31, 0x00, 0x00,
 }
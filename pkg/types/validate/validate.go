@@ -7,11 +7,14 @@ package validate
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/graph"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/golang/protobuf/ptypes"
 	"gonum.org/v1/gonum/graph/topo"
 )
 
@@ -34,6 +37,25 @@ var (
 	ErrNoWorkflow                   = errors.New("workflow id is required")
 	ErrNoID                         = errors.New("id is required")
 	ErrNoStatus                     = errors.New("status is required")
+	ErrInvalidSuccessCondition      = errors.New("success condition should be an expression")
+	ErrInvalidCompletionPolicy      = errors.New("invalid completion policy")
+	ErrInvalidConcurrencyPolicy     = errors.New("invalid concurrency policy")
+	ErrInvalidRetryBudget           = errors.New("retry budget should not be negative")
+	ErrInvalidMaxParallelism        = errors.New("maximum parallelism should not be negative")
+	ErrInvalidTimeout               = errors.New("timeout should not be negative")
+	ErrInvalidRetryPolicy           = errors.New("retry policy should not have a negative number of attempts")
+	ErrInvalidRetryTimeout          = errors.New("total timeout of the retry policy should be positive")
+	ErrInvalidRetryBackoff          = errors.New("invalid backoff of the retry policy")
+	ErrInvalidSoftTimeout           = errors.New("soft timeout percentage should be between 0 and 100")
+	ErrInvalidSwitch                = errors.New("invalid switch")
+	ErrInvalidFailover              = errors.New("invalid failover")
+	ErrInvalidRateLimit             = errors.New("invalid rate limit")
+	ErrInvalidFailedReferencePolicy = errors.New("unknown failed reference policy")
+	ErrInvalidCancelPropagation     = errors.New("unknown cancel propagation mode")
+	ErrInvalidContract              = errors.New("invalid contract")
+	ErrContractMismatch             = errors.New("contract mismatch")
+	ErrInvalidScopeStrictness       = errors.New("unknown scope strictness")
+	ErrInvalidInputMerge            = errors.New("invalid input merge")
 )
 
 type Error struct {
@@ -119,6 +141,49 @@ func WorkflowSpec(spec *types.WorkflowSpec) error {
 		errs.append(ErrInvalidOutputTask)
 	}
 
+	if len(spec.SuccessCondition) > 0 && !typedvalues.IsExpression(spec.SuccessCondition) {
+		errs.append(fmt.Errorf("%v: '%v'", ErrInvalidSuccessCondition, spec.SuccessCondition))
+	}
+
+	if spec.Completion != nil {
+		errs.append(CompletionPolicy(spec.Completion, spec))
+	}
+
+	if spec.Concurrency != nil {
+		errs.append(ConcurrencyPolicy(spec.Concurrency))
+	}
+
+	if spec.RetryBudget < 0 {
+		errs.append(fmt.Errorf("%v: %d", ErrInvalidRetryBudget, spec.RetryBudget))
+	}
+
+	if spec.MaxParallelism < 0 {
+		errs.append(fmt.Errorf("%v: %d", ErrInvalidMaxParallelism, spec.MaxParallelism))
+	}
+
+	if timeout := spec.GetTimeout(); timeout != nil {
+		if d, err := ptypes.Duration(timeout); err != nil || d < 0 {
+			errs.append(fmt.Errorf("%v: %v", ErrInvalidTimeout, timeout))
+		}
+	}
+
+	if spec.SoftTimeoutPercentage < 0 || spec.SoftTimeoutPercentage >= 100 {
+		errs.append(fmt.Errorf("%v: %d", ErrInvalidSoftTimeout, spec.SoftTimeoutPercentage))
+	}
+
+	switch spec.ScopeStrictness {
+	case "", types.ScopeStrictnessWarn, types.ScopeStrictnessFail:
+	default:
+		errs.append(fmt.Errorf("%v '%s' (expected '%s' or '%s')", ErrInvalidScopeStrictness, spec.ScopeStrictness,
+			types.ScopeStrictnessWarn, types.ScopeStrictnessFail))
+	}
+
+	errs.append(Switches(spec))
+
+	if spec.Contract != nil {
+		errs.append(WorkflowContract(spec.Contract))
+	}
+
 	refTable := map[string]*types.TaskSpec{}
 	for taskID, task := range spec.Tasks {
 		if len(taskID) == 0 {
@@ -163,6 +228,209 @@ func WorkflowSpec(spec *types.WorkflowSpec) error {
 	return errs.getOrNil()
 }
 
+// CompletionPolicy validates the completion policy of the workflow spec.
+func CompletionPolicy(policy *types.CompletionPolicy, spec *types.WorkflowSpec) error {
+	errs := Error{subject: "CompletionPolicy"}
+
+	setSize := len(spec.GetTasks())
+	if len(policy.GetTasks()) > 0 {
+		setSize = len(policy.GetTasks())
+	}
+	for _, taskID := range policy.GetTasks() {
+		if _, ok := spec.GetTasks()[taskID]; !ok {
+			errs.append(fmt.Errorf("%v: unknown task '%v'", ErrInvalidCompletionPolicy, taskID))
+		}
+	}
+
+	switch policy.GetMode() {
+	case "", types.CompletionModeAll, types.CompletionModeAny:
+	case types.CompletionModeQuorum:
+		if policy.GetQuorum() <= 0 || int(policy.GetQuorum()) > setSize {
+			errs.append(fmt.Errorf("%v: quorum should be between 1 and %d, but was %d", ErrInvalidCompletionPolicy,
+				setSize, policy.GetQuorum()))
+		}
+	default:
+		errs.append(fmt.Errorf("%v: unknown mode '%v'", ErrInvalidCompletionPolicy, policy.GetMode()))
+	}
+
+	return errs.getOrNil()
+}
+
+// ConcurrencyPolicy validates the concurrency policy of the workflow spec.
+func ConcurrencyPolicy(policy *types.ConcurrencyPolicy) error {
+	errs := Error{subject: "ConcurrencyPolicy"}
+
+	if !typedvalues.IsExpression(policy.GetKey()) {
+		errs.append(fmt.Errorf("%v: key should be an expression, but was '%v'", ErrInvalidConcurrencyPolicy,
+			policy.GetKey()))
+	}
+
+	switch policy.GetOnConflict() {
+	case "", types.ConcurrencyConflictQueue, types.ConcurrencyConflictReject:
+	default:
+		errs.append(fmt.Errorf("%v: unknown conflict behavior '%v'", ErrInvalidConcurrencyPolicy,
+			policy.GetOnConflict()))
+	}
+
+	return errs.getOrNil()
+}
+
+// Switches validates the switches of the workflow spec. Each task can belong to at most one branch.
+func Switches(spec *types.WorkflowSpec) error {
+	errs := Error{subject: "Switches"}
+
+	branchOf := map[string]string{}
+	for switchID, sw := range spec.GetSwitches() {
+		if !typedvalues.IsExpression(sw.GetExpression()) {
+			errs.append(fmt.Errorf("%v: '%v': expression should be an expression, but was '%v'", ErrInvalidSwitch,
+				switchID, sw.GetExpression()))
+		}
+		if len(sw.GetBranches()) == 0 {
+			errs.append(fmt.Errorf("%v: '%v': switch has no branches", ErrInvalidSwitch, switchID))
+		}
+		if _, ok := sw.GetBranches()[types.DefaultBranch]; ok {
+			errs.append(fmt.Errorf("%v: '%v': branch name '%v' is reserved for the default branch", ErrInvalidSwitch,
+				switchID, types.DefaultBranch))
+		}
+		for _, taskID := range sw.GetRequires() {
+			if _, ok := spec.GetTasks()[taskID]; !ok {
+				errs.append(fmt.Errorf("%v: '%v': unknown required task '%v'", ErrInvalidSwitch, switchID, taskID))
+			}
+		}
+		for name, branch := range sw.AllBranches() {
+			for _, taskID := range branch.GetTasks() {
+				if _, ok := spec.GetTasks()[taskID]; !ok {
+					errs.append(fmt.Errorf("%v: '%v': unknown task '%v' in branch '%v'", ErrInvalidSwitch, switchID,
+						taskID, name))
+				}
+				if other, ok := branchOf[taskID]; ok {
+					errs.append(fmt.Errorf("%v: '%v': task '%v' is part of multiple branches (%v, %v.%v)",
+						ErrInvalidSwitch, switchID, taskID, other, switchID, name))
+				}
+				branchOf[taskID] = switchID + "." + name
+			}
+		}
+	}
+
+	// A switch cannot depend on the tasks of its own branches.
+	for switchID, sw := range spec.GetSwitches() {
+		for _, taskID := range sw.GetRequires() {
+			if id, _, ok := spec.SwitchOf(taskID); ok && id == switchID {
+				errs.append(fmt.Errorf("%v: '%v': required task '%v' is part of a branch of the switch",
+					ErrInvalidSwitch, switchID, taskID))
+			}
+		}
+	}
+
+	return errs.getOrNil()
+}
+
+// WorkflowContract validates the contract of the workflow spec.
+func WorkflowContract(contract *types.WorkflowContract) error {
+	errs := Error{subject: "WorkflowContract"}
+
+	for kind, fields := range map[string]map[string]*types.ContractField{
+		"input":  contract.GetInputs(),
+		"output": contract.GetOutputs(),
+	} {
+		for name, field := range fields {
+			switch field.GetType() {
+			case "", types.ContractTypeString, types.ContractTypeNumber, types.ContractTypeBool, types.ContractTypeBytes,
+				types.ContractTypeMap, types.ContractTypeList:
+			default:
+				errs.append(fmt.Errorf("%v: %s '%v' has unknown type '%v'", ErrInvalidContract, kind, name,
+					field.GetType()))
+			}
+		}
+	}
+
+	return errs.getOrNil()
+}
+
+// ContractInputs validates the inputs of an invocation against the input contract of the workflow. All required
+// inputs need to be provided, and the provided inputs need to be of the declared type. Inputs that are not declared in
+// the contract are allowed.
+func ContractInputs(contract *types.WorkflowContract, inputs map[string]*typedvalues.TypedValue) error {
+	errs := Error{subject: "Inputs"}
+	for _, err := range contractFields("input", contract.GetInputs(), inputs) {
+		errs.append(err)
+	}
+	return errs.getOrNil()
+}
+
+// ContractOutput validates the output of an invocation against the output contract of the workflow. If the contract
+// declares any output fields, the output needs to be a map that contains all required fields.
+func ContractOutput(contract *types.WorkflowContract, output *typedvalues.TypedValue) error {
+	errs := Error{subject: "Output"}
+	if len(contract.GetOutputs()) == 0 {
+		return nil
+	}
+	fields := map[string]*typedvalues.TypedValue{}
+	if output != nil && output.ValueType() != typedvalues.TypeNil {
+		var err error
+		fields, err = typedvalues.UnwrapTypedValueMap(output)
+		if err != nil {
+			errs.append(fmt.Errorf("%v: output should be of type %s, but was %s", ErrContractMismatch,
+				types.ContractTypeMap, contractType(output)))
+			return errs.getOrNil()
+		}
+	}
+	for _, err := range contractFields("output field", contract.GetOutputs(), fields) {
+		errs.append(err)
+	}
+	return errs.getOrNil()
+}
+
+// contractFields returns the mismatches between the values and the declared fields, sorted by the name of the field.
+func contractFields(kind string, fields map[string]*types.ContractField,
+	values map[string]*typedvalues.TypedValue) []error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		field := fields[name]
+		value, ok := values[name]
+		if !ok || value.ValueType() == typedvalues.TypeNil {
+			if !field.GetOptional() {
+				errs = append(errs, fmt.Errorf("%v: missing required %s '%s'", ErrContractMismatch, kind, name))
+			}
+			continue
+		}
+		if len(field.GetType()) > 0 && contractType(value) != field.GetType() {
+			errs = append(errs, fmt.Errorf("%v: %s '%s' should be of type %s, but was %s", ErrContractMismatch, kind,
+				name, field.GetType(), contractType(value)))
+		}
+	}
+	return errs
+}
+
+// contractType returns the contract type of the value, or the type of the value itself if it has no contract type.
+func contractType(tv *typedvalues.TypedValue) string {
+	valueType := tv.ValueType()
+	switch valueType {
+	case typedvalues.TypeString:
+		return types.ContractTypeString
+	case typedvalues.TypeBool:
+		return types.ContractTypeBool
+	case typedvalues.TypeBytes:
+		return types.ContractTypeBytes
+	case typedvalues.TypeMap:
+		return types.ContractTypeMap
+	case typedvalues.TypeList:
+		return types.ContractTypeList
+	}
+	for _, numberType := range typedvalues.TypeNumber {
+		if valueType == numberType {
+			return types.ContractTypeNumber
+		}
+	}
+	return valueType
+}
+
 func TaskSpec(spec *types.TaskSpec) error {
 	errs := Error{subject: "TaskSpec"}
 
@@ -175,9 +443,102 @@ func TaskSpec(spec *types.TaskSpec) error {
 		errs.append(ErrTaskRequiresFnRef)
 	}
 
+	if spec.GetRetry().GetMaxAttempts() < 0 {
+		errs.append(fmt.Errorf("%v: %d", ErrInvalidRetryPolicy, spec.GetRetry().GetMaxAttempts()))
+	}
+
+	if totalTimeout := spec.GetRetry().GetTotalTimeout(); totalTimeout != nil {
+		if d, err := ptypes.Duration(totalTimeout); err != nil || d <= 0 {
+			errs.append(fmt.Errorf("%v: %v", ErrInvalidRetryTimeout, totalTimeout))
+		}
+	}
+
+	if delay := spec.GetRetry().GetInitialDelay(); delay != nil {
+		if d, err := ptypes.Duration(delay); err != nil || d < 0 {
+			errs.append(fmt.Errorf("%v: initial delay should not be negative: %v", ErrInvalidRetryBackoff, delay))
+		}
+	}
+	if delay := spec.GetRetry().GetMaxDelay(); delay != nil {
+		if d, err := ptypes.Duration(delay); err != nil || d < 0 {
+			errs.append(fmt.Errorf("%v: max delay should not be negative: %v", ErrInvalidRetryBackoff, delay))
+		}
+	}
+	if multiplier := spec.GetRetry().GetBackoffMultiplier(); multiplier != 0 && multiplier < 1 {
+		errs.append(fmt.Errorf("%v: multiplier should be at least 1: %v", ErrInvalidRetryBackoff, multiplier))
+	}
+
+	if failover := spec.GetFailover(); failover != nil {
+		if len(failover.GetFunctionRef()) == 0 {
+			errs.append(fmt.Errorf("%v: function reference is required", ErrInvalidFailover))
+		}
+		if failover.GetAfter() < 0 {
+			errs.append(fmt.Errorf("%v: number of failed attempts should not be negative: %d", ErrInvalidFailover,
+				failover.GetAfter()))
+		}
+		if !spec.FailsOver(spec.MaxAttempts()) {
+			errs.append(fmt.Errorf("%v: the retry policy (max attempts: %d) does not allow for any attempt after %d "+
+				"failed attempts", ErrInvalidFailover, spec.MaxAttempts(), failover.GetAfter()))
+		}
+	}
+
+	if rateLimit := spec.GetRateLimit(); rateLimit != nil {
+		if rateLimit.GetExecutions() <= 0 {
+			errs.append(fmt.Errorf("%v: number of executions should be positive: %d", ErrInvalidRateLimit,
+				rateLimit.GetExecutions()))
+		}
+		if d, err := ptypes.Duration(rateLimit.GetWindow()); err != nil || d <= 0 {
+			errs.append(fmt.Errorf("%v: window should be positive: %v", ErrInvalidRateLimit, rateLimit.GetWindow()))
+		}
+	}
+
+	if err := failedReferencePolicy(spec.GetFailedReferencePolicy()); err != nil {
+		errs.append(err)
+	}
+	for taskID, policy := range spec.GetFailedReferencePolicies() {
+		if err := failedReferencePolicy(policy); err != nil {
+			errs.append(fmt.Errorf("%v (reference to task %s)", err, taskID))
+		}
+	}
+
+	switch spec.GetCancelPropagation() {
+	case "", types.CancelPropagationCascade, types.CancelPropagationDetach:
+	default:
+		errs.append(fmt.Errorf("%v '%s' (expected '%s' or '%s')", ErrInvalidCancelPropagation,
+			spec.GetCancelPropagation(), types.CancelPropagationCascade, types.CancelPropagationDetach))
+	}
+
+	inputKeys := make([]string, 0, len(spec.GetMergeInputs()))
+	for inputKey := range spec.GetMergeInputs() {
+		inputKeys = append(inputKeys, inputKey)
+	}
+	sort.Strings(inputKeys)
+	for _, inputKey := range inputKeys {
+		merge := spec.GetMergeInputs()[inputKey]
+		if len(merge.GetSources()) == 0 {
+			errs.append(fmt.Errorf("%v of input %s: no sources", ErrInvalidInputMerge, inputKey))
+		}
+		switch merge.GetConflicts() {
+		case "", types.MergeConflictLastWins, types.MergeConflictError:
+		default:
+			errs.append(fmt.Errorf("%v of input %s: unknown conflict resolution '%s' (expected '%s' or '%s')",
+				ErrInvalidInputMerge, inputKey, merge.GetConflicts(), types.MergeConflictLastWins,
+				types.MergeConflictError))
+		}
+	}
+
 	return errs.getOrNil()
 }
 
+func failedReferencePolicy(policy string) error {
+	switch policy {
+	case "", types.FailedReferencePropagate, types.FailedReferenceUseNull, types.FailedReferenceBlock:
+		return nil
+	default:
+		return fmt.Errorf("%v '%s' (expected '%s', '%s' or '%s')", ErrInvalidFailedReferencePolicy, policy,
+			types.FailedReferencePropagate, types.FailedReferenceUseNull, types.FailedReferenceBlock)
+	}
+}
+
 func DynamicTaskSpec(task *types.TaskSpec) error {
 	err := TaskSpec(task)
 	if err != nil {
@@ -2,8 +2,11 @@ package validate
 
 import (
 	"testing"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -48,6 +51,265 @@ func TestWorkflowSpecInvalidOutputTask(t *testing.T) {
 	assert.Error(t, WorkflowSpec(spec))
 }
 
+func TestWorkflowSpecSuccessCondition(t *testing.T) {
+	spec := validSpec()
+	spec.SuccessCondition = "{ $.Tasks.middle.Status == 'SUCCEEDED' }"
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.SuccessCondition = "notAnExpression"
+	assert.Error(t, WorkflowSpec(spec))
+}
+
+func TestWorkflowSpecCompletionPolicy(t *testing.T) {
+	spec := validSpec()
+	spec.Completion = &types.CompletionPolicy{
+		Mode:   types.CompletionModeQuorum,
+		Tasks:  []string{"first", "middle"},
+		Quorum: 2,
+	}
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.Completion.Quorum = 3
+	assert.Error(t, WorkflowSpec(spec))
+
+	spec.Completion = &types.CompletionPolicy{
+		Mode:  types.CompletionModeAny,
+		Tasks: []string{"nonExistent"},
+	}
+	assert.Error(t, WorkflowSpec(spec))
+
+	spec.Completion = &types.CompletionPolicy{Mode: "nonExistent"}
+	assert.Error(t, WorkflowSpec(spec))
+}
+
+func TestWorkflowSpecConcurrencyPolicy(t *testing.T) {
+	spec := validSpec()
+	spec.Concurrency = &types.ConcurrencyPolicy{Key: "{$.Invocation.Inputs.service}"}
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.Concurrency.OnConflict = types.ConcurrencyConflictReject
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.Concurrency.OnConflict = "nonExistent"
+	assert.Error(t, WorkflowSpec(spec))
+
+	spec.Concurrency = &types.ConcurrencyPolicy{Key: "service"}
+	assert.Error(t, WorkflowSpec(spec))
+}
+
+func TestWorkflowSpecScopeStrictness(t *testing.T) {
+	spec := validSpec()
+	spec.ScopeStrictness = types.ScopeStrictnessFail
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.ScopeStrictness = "strict"
+	assert.Error(t, WorkflowSpec(spec))
+}
+
+func TestWorkflowSpecMaxParallelism(t *testing.T) {
+	spec := validSpec()
+	spec.MaxParallelism = 5
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.MaxParallelism = -1
+	assert.Error(t, WorkflowSpec(spec))
+}
+
+func TestWorkflowSpecTimeout(t *testing.T) {
+	spec := validSpec()
+	spec.Timeout = ptypes.DurationProto(time.Hour)
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.Timeout = ptypes.DurationProto(0)
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.Timeout = ptypes.DurationProto(-time.Second)
+	assert.Error(t, WorkflowSpec(spec))
+}
+
+func TestTaskSpecFailover(t *testing.T) {
+	task := &types.TaskSpec{
+		FunctionRef: "primary",
+		Retry:       &types.RetryPolicy{MaxAttempts: 3},
+		Failover:    &types.Failover{FunctionRef: "secondary", After: 2},
+	}
+	assert.NoError(t, TaskSpec(task))
+
+	// The retry policy does not allow for an attempt after the failed attempts of the primary function.
+	task.Failover.After = 3
+	assert.Error(t, TaskSpec(task))
+
+	task.Failover = &types.Failover{After: 1}
+	assert.Error(t, TaskSpec(task))
+
+	task.Failover = &types.Failover{FunctionRef: "secondary", After: -1}
+	assert.Error(t, TaskSpec(task))
+}
+
+func TestTaskSpecRetryBackoff(t *testing.T) {
+	task := &types.TaskSpec{
+		FunctionRef: "fn",
+		Retry: &types.RetryPolicy{
+			MaxAttempts:       3,
+			InitialDelay:      ptypes.DurationProto(time.Second),
+			BackoffMultiplier: 1.5,
+			MaxDelay:          ptypes.DurationProto(time.Minute),
+		},
+	}
+	assert.NoError(t, TaskSpec(task))
+
+	task.Retry.BackoffMultiplier = 0.5
+	assert.Error(t, TaskSpec(task))
+
+	task.Retry.BackoffMultiplier = 0
+	task.Retry.InitialDelay = ptypes.DurationProto(-time.Second)
+	assert.Error(t, TaskSpec(task))
+
+	task.Retry.InitialDelay = nil
+	task.Retry.MaxDelay = ptypes.DurationProto(-time.Second)
+	assert.Error(t, TaskSpec(task))
+}
+
+func TestTaskSpecMergeInputs(t *testing.T) {
+	task := &types.TaskSpec{
+		FunctionRef: "report",
+		MergeInputs: map[string]*types.InputMerge{
+			types.InputMain: {
+				Sources:   []*typedvalues.TypedValue{typedvalues.MustWrap(map[string]interface{}{"a": 1})},
+				Conflicts: types.MergeConflictError,
+			},
+		},
+	}
+	assert.NoError(t, TaskSpec(task))
+
+	task.MergeInputs[types.InputMain].Conflicts = "first-wins"
+	assert.Error(t, TaskSpec(task))
+
+	task.MergeInputs[types.InputMain] = &types.InputMerge{}
+	assert.Error(t, TaskSpec(task))
+}
+
+func TestTaskSpecFailedReferencePolicy(t *testing.T) {
+	task := &types.TaskSpec{
+		FunctionRef:             "report",
+		FailedReferencePolicy:   types.FailedReferenceUseNull,
+		FailedReferencePolicies: map[string]string{"fetch": types.FailedReferenceBlock},
+	}
+	assert.NoError(t, TaskSpec(task))
+
+	task.FailedReferencePolicy = "ignore"
+	assert.Error(t, TaskSpec(task))
+
+	task.FailedReferencePolicy = ""
+	task.FailedReferencePolicies["fetch"] = "ignore"
+	assert.Error(t, TaskSpec(task))
+}
+
+func TestTaskSpecCancelPropagation(t *testing.T) {
+	task := &types.TaskSpec{
+		FunctionRef:       "notify",
+		CancelPropagation: types.CancelPropagationDetach,
+	}
+	assert.NoError(t, TaskSpec(task))
+
+	task.CancelPropagation = "orphan"
+	assert.Error(t, TaskSpec(task))
+}
+
+func TestWorkflowSpecSwitches(t *testing.T) {
+	spec := validSpec()
+	spec.Switches = map[string]*types.Switch{
+		"route": {
+			Expression: "{ $.Tasks.first.Output }",
+			Requires:   []string{"first"},
+			Branches: map[string]*types.Branch{
+				"a": {Tasks: []string{"middle"}},
+			},
+			Default: &types.Branch{Tasks: []string{"last"}},
+		},
+	}
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.Switches["route"].Expression = "notAnExpression"
+	assert.Error(t, WorkflowSpec(spec))
+	spec.Switches["route"].Expression = "{ $.Tasks.first.Output }"
+
+	// The default branch name is reserved.
+	spec.Switches["route"].Branches[types.DefaultBranch] = &types.Branch{}
+	assert.Error(t, WorkflowSpec(spec))
+	delete(spec.Switches["route"].Branches, types.DefaultBranch)
+
+	// Tasks can belong to at most one branch.
+	spec.Switches["route"].Branches["b"] = &types.Branch{Tasks: []string{"middle"}}
+	assert.Error(t, WorkflowSpec(spec))
+	spec.Switches["route"].Branches["b"] = &types.Branch{Tasks: []string{"nonExistent"}}
+	assert.Error(t, WorkflowSpec(spec))
+	delete(spec.Switches["route"].Branches, "b")
+
+	// The switch cannot depend on its own branches.
+	spec.Switches["route"].Requires = []string{"middle"}
+	assert.Error(t, WorkflowSpec(spec))
+}
+
+func TestWorkflowSpecContract(t *testing.T) {
+	spec := validSpec()
+	spec.Contract = &types.WorkflowContract{
+		Inputs: map[string]*types.ContractField{
+			"name": {Type: types.ContractTypeString},
+			"any":  {},
+		},
+	}
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.Contract.Outputs = map[string]*types.ContractField{
+		"result": {Type: "integer"},
+	}
+	assert.Error(t, WorkflowSpec(spec))
+}
+
+func TestContractInputs(t *testing.T) {
+	contract := &types.WorkflowContract{
+		Inputs: map[string]*types.ContractField{
+			"name":  {Type: types.ContractTypeString},
+			"count": {Type: types.ContractTypeNumber, Optional: true},
+			"any":   {},
+		},
+	}
+	assert.NoError(t, ContractInputs(nil, nil))
+	assert.NoError(t, ContractInputs(contract, map[string]*typedvalues.TypedValue{
+		"name":  typedvalues.MustWrap("alice"),
+		"count": typedvalues.MustWrap(3),
+		"any":   typedvalues.MustWrap([]interface{}{1, 2}),
+		"extra": typedvalues.MustWrap(true),
+	}))
+
+	err := ContractInputs(contract, map[string]*typedvalues.TypedValue{
+		"count": typedvalues.MustWrap("many"),
+		"any":   typedvalues.MustWrap(nil),
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "Inputs is invalid: (0) contract mismatch: missing required input 'any'; "+
+		"(1) contract mismatch: input 'count' should be of type number, but was string; "+
+		"(2) contract mismatch: missing required input 'name'", err.Error())
+}
+
+func TestContractOutput(t *testing.T) {
+	contract := &types.WorkflowContract{
+		Outputs: map[string]*types.ContractField{
+			"greeting": {Type: types.ContractTypeString},
+		},
+	}
+	assert.NoError(t, ContractOutput(&types.WorkflowContract{}, typedvalues.MustWrap("hello")))
+	assert.NoError(t, ContractOutput(contract, typedvalues.MustWrap(map[string]interface{}{
+		"greeting": "hello",
+	})))
+	assert.Error(t, ContractOutput(contract, typedvalues.MustWrap("hello")))
+	assert.Error(t, ContractOutput(contract, nil))
+	assert.Error(t, ContractOutput(contract, typedvalues.MustWrap(map[string]interface{}{
+		"greeting": 42,
+	})))
+}
+
 func TestWorkflowSpecNoTasks(t *testing.T) {
 	spec := validSpec()
 	spec.Tasks = map[string]*types.TaskSpec{}
@@ -0,0 +1,40 @@
+// Package exemplar links metric observations to traces.
+//
+// An exemplar attaches the ID of the trace of an observation to the bucket of the histogram that it fell into, which
+// allows operators to jump from a slow sample in a latency histogram to the corresponding trace. Exemplars are only
+// exposed to scrapers that request the OpenMetrics format.
+package exemplar
+
+import (
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uber/jaeger-client-go"
+)
+
+// TraceIDLabel is the label of the exemplar that contains the trace ID.
+const TraceIDLabel = "trace_id"
+
+// TraceID returns the ID of the trace of the span, if the span is part of a sampled trace. The traces that are not
+// sampled are not reported to the tracing backend, so linking to them would lead nowhere.
+func TraceID(span opentracing.Span) (string, bool) {
+	if span == nil {
+		return "", false
+	}
+	sc, ok := span.Context().(jaeger.SpanContext)
+	if !ok || !sc.IsValid() || !sc.IsSampled() {
+		return "", false
+	}
+	return sc.TraceID().String(), true
+}
+
+// Observe adds the value to the observer, attaching the trace of the span as an exemplar if the span is part of a
+// sampled trace and the observer supports exemplars.
+func Observe(observer prometheus.Observer, value float64, span opentracing.Span) {
+	if eo, ok := observer.(prometheus.ExemplarObserver); ok {
+		if traceID, ok := TraceID(span); ok {
+			eo.ObserveWithExemplar(value, prometheus.Labels{TraceIDLabel: traceID})
+			return
+		}
+	}
+	observer.Observe(value)
+}
@@ -0,0 +1,58 @@
+package exemplar
+
+import (
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-client-go"
+)
+
+func newTracer(sampled bool) opentracing.Tracer {
+	tracer, _ := jaeger.NewTracer("test", jaeger.NewConstSampler(sampled), jaeger.NewNullReporter())
+	return tracer
+}
+
+func exemplars(t *testing.T, h prometheus.Histogram) []*dto.Exemplar {
+	m := &dto.Metric{}
+	assert.NoError(t, h.Write(m))
+	var result []*dto.Exemplar
+	for _, bucket := range m.GetHistogram().GetBucket() {
+		if e := bucket.GetExemplar(); e != nil {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func TestObserve(t *testing.T) {
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test", Buckets: []float64{1, 10}})
+
+	// Without a span, no exemplar is attached
+	Observe(h, 0.5, nil)
+	assert.Empty(t, exemplars(t, h))
+
+	// Traces that are not sampled are not linked
+	span := newTracer(false).StartSpan("unsampled")
+	Observe(h, 0.5, span)
+	assert.Empty(t, exemplars(t, h))
+
+	// Sampled traces are linked
+	span = newTracer(true).StartSpan("sampled")
+	Observe(h, 5, span)
+	result := exemplars(t, h)
+	if assert.Len(t, result, 1) {
+		assert.EqualValues(t, 5, result[0].GetValue())
+		assert.Equal(t, TraceIDLabel, result[0].GetLabel()[0].GetName())
+		assert.Equal(t, span.Context().(jaeger.SpanContext).TraceID().String(), result[0].GetLabel()[0].GetValue())
+	}
+
+	// Observers without exemplar support are observed regularly
+	s := prometheus.NewSummary(prometheus.SummaryOpts{Name: "test"})
+	Observe(s, 5, span)
+	m := &dto.Metric{}
+	assert.NoError(t, s.Write(m))
+	assert.EqualValues(t, 1, m.GetSummary().GetSampleCount())
+}
@@ -42,9 +42,24 @@ func NewNamedDelayingQueue(name string) DelayingInterface {
 	return newDelayingQueue(DefaultMaxSize, clock.RealClock{}, name)
 }
 
+// NewDelayingQueueWithClock constructs a new workqueue with delayed queuing ability, of which the delays are measured
+// with the clock, such as a fake clock in tests.
+func NewDelayingQueueWithClock(maxSize int, clock clock.Clock) DelayingInterface {
+	return newDelayingQueue(maxSize, clock, "")
+}
+
+// NewDelayingQueueFrom adds delayed queuing to an existing queue, such as a FairQueue.
+func NewDelayingQueueFrom(q Interface) DelayingInterface {
+	return newDelayingQueueFrom(q, clock.RealClock{})
+}
+
 func newDelayingQueue(maxSize int, clock clock.Clock, name string) DelayingInterface {
+	return newDelayingQueueFrom(NewNamed(maxSize, name), clock)
+}
+
+func newDelayingQueueFrom(q Interface, clock clock.Clock) DelayingInterface {
 	ret := &delayingType{
-		Interface:       NewNamed(maxSize, name),
+		Interface:       q,
 		clock:           clock,
 		heartbeat:       clock.Tick(maxWait),
 		stopCh:          make(chan struct{}),
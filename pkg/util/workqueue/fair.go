@@ -0,0 +1,217 @@
+package workqueue
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	metricPartitionDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "workqueue",
+		Name:      "partition_depth",
+		Help:      "Number of items queued in a partition of a fair queue",
+	}, []string{"queue", "partition"})
+	metricPartitionDispatched = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "workqueue",
+		Name:      "partition_dispatched_total",
+		Help:      "Number of items dispatched from a partition of a fair queue",
+	}, []string{"queue", "partition"})
+)
+
+func init() {
+	prometheus.MustRegister(metricPartitionDepth, metricPartitionDispatched)
+}
+
+const DefaultPartitionWeight = 1
+
+// PartitionFunc returns the partition (e.g. the tenant) that an item belongs to.
+type PartitionFunc func(item interface{}) string
+
+// FairQueue is a work queue that partitions the items, and drains the partitions in a weighted round-robin.
+//
+// In contrast to the FIFO work queue, a burst of items in one partition does not starve the other partitions: each
+// round, a partition dispatches at most its weight in items, before the next partition is served. Otherwise, the
+// queue has the same semantics as the regular work queue; items that are already queued are deduplicated, and items
+// that are added while being processed are queued again once they are done.
+type FairQueue struct {
+	name      string
+	maxSize   int
+	replace   bool
+	partition PartitionFunc
+	weights   map[string]int
+
+	// partitions contains the queued keys per partition.
+	partitions map[string][]interface{}
+
+	// ring contains the partitions with queued items in the order in which they are served.
+	ring []string
+
+	// current is the index in the ring of the partition that is being served; credit is the number of items that the
+	// partition is still allowed to dispatch in the current round.
+	current int
+	credit  int
+
+	size       int
+	dirty      map[interface{}]interface{}
+	processing map[interface{}]interface{}
+	cond       *sync.Cond
+
+	shuttingDown bool
+}
+
+// NewFairQueue creates a fair queue with the given name, which is used to label the metrics. Partitions without
+// a weight in weights are assigned the DefaultPartitionWeight.
+func NewFairQueue(name string, maxSize int, replace bool, partition PartitionFunc,
+	weights map[string]int) *FairQueue {
+	return &FairQueue{
+		name:       name,
+		maxSize:    maxSize,
+		replace:    replace,
+		partition:  partition,
+		weights:    weights,
+		partitions: map[string][]interface{}{},
+		dirty:      map[interface{}]interface{}{},
+		processing: map[interface{}]interface{}{},
+		cond:       sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+func (q *FairQueue) Add(item interface{}) (accepted bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return false
+	}
+
+	key := getKey(item)
+	if _, ok := q.dirty[key]; ok {
+		if q.replace {
+			q.dirty[key] = item
+		}
+		return true
+	}
+
+	if q.size >= q.maxSize {
+		return false
+	}
+
+	q.dirty[key] = item
+	if _, ok := q.processing[key]; ok {
+		return true
+	}
+
+	q.push(key, item)
+	return true
+}
+
+func (q *FairQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.size
+}
+
+func (q *FairQueue) Get() (item interface{}, shutdown bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.size == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.size == 0 {
+		// We must be shutting down.
+		return nil, true
+	}
+
+	partition := q.ring[q.current]
+	keys := q.partitions[partition]
+	key := keys[0]
+	q.partitions[partition] = keys[1:]
+	q.size--
+	q.credit--
+	metricPartitionDepth.WithLabelValues(q.name, partition).Set(float64(len(keys) - 1))
+	metricPartitionDispatched.WithLabelValues(q.name, partition).Inc()
+
+	if len(keys) == 1 {
+		// The partition has been drained; remove it from the ring.
+		delete(q.partitions, partition)
+		q.ring = append(q.ring[:q.current], q.ring[q.current+1:]...)
+		q.nextPartition(false)
+	} else if q.credit <= 0 {
+		q.nextPartition(true)
+	}
+
+	item = q.dirty[key]
+	q.processing[key] = item
+	delete(q.dirty, key)
+	return item, false
+}
+
+func (q *FairQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	key := getKey(item)
+	delete(q.processing, key)
+	if dirty, ok := q.dirty[key]; ok {
+		q.push(key, dirty)
+	}
+}
+
+func (q *FairQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+func (q *FairQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+// push appends the key to the queue of its partition. The caller should hold the lock.
+func (q *FairQueue) push(key interface{}, item interface{}) {
+	partition := q.partition(item)
+	keys, ok := q.partitions[partition]
+	if !ok {
+		// Add the partition to the ring, just before the partition that is currently served, to ensure that it will
+		// not be served before the other partitions have had their turn.
+		q.ring = append(q.ring, "")
+		copy(q.ring[q.current+1:], q.ring[q.current:])
+		q.ring[q.current] = partition
+		if len(q.ring) == 1 {
+			q.credit = q.weight(partition)
+		} else {
+			q.current++
+		}
+	}
+	q.partitions[partition] = append(keys, key)
+	q.size++
+	metricPartitionDepth.WithLabelValues(q.name, partition).Set(float64(len(keys) + 1))
+	q.cond.Signal()
+}
+
+// nextPartition moves on to serve the next partition in the ring. If advance is false, the current index already
+// points to the next partition, because the served partition was removed from the ring.
+func (q *FairQueue) nextPartition(advance bool) {
+	if len(q.ring) == 0 {
+		q.current = 0
+		q.credit = 0
+		return
+	}
+	if advance {
+		q.current++
+	}
+	q.current %= len(q.ring)
+	q.credit = q.weight(q.ring[q.current])
+}
+
+func (q *FairQueue) weight(partition string) int {
+	if w, ok := q.weights[partition]; ok && w > 0 {
+		return w
+	}
+	return DefaultPartitionWeight
+}
@@ -0,0 +1,80 @@
+package workqueue
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// tenantOf partitions items of the form '<tenant>/<id>' by their tenant.
+func tenantOf(item interface{}) string {
+	return strings.SplitN(item.(string), "/", 2)[0]
+}
+
+func drain(q Interface) []string {
+	var items []string
+	for q.Len() > 0 {
+		item, _ := q.Get()
+		q.Done(item)
+		items = append(items, item.(string))
+	}
+	return items
+}
+
+func TestFairQueue_RoundRobin(t *testing.T) {
+	q := NewFairQueue("test", DefaultMaxSize, false, tenantOf, nil)
+
+	// A burst of tenant a should not starve tenant b.
+	for _, item := range []string{"a/1", "a/2", "a/3", "a/4", "b/1", "b/2"} {
+		assert.True(t, q.Add(item))
+	}
+	assert.Equal(t, 6, q.Len())
+	assert.Equal(t, []string{"a/1", "b/1", "a/2", "b/2", "a/3", "a/4"}, drain(q))
+}
+
+func TestFairQueue_Weights(t *testing.T) {
+	q := NewFairQueue("test", DefaultMaxSize, false, tenantOf, map[string]int{"a": 2})
+	for _, item := range []string{"a/1", "a/2", "a/3", "a/4", "b/1", "b/2", "b/3"} {
+		assert.True(t, q.Add(item))
+	}
+	assert.Equal(t, []string{"a/1", "a/2", "b/1", "a/3", "a/4", "b/2", "b/3"}, drain(q))
+}
+
+func TestFairQueue_Dedup(t *testing.T) {
+	q := NewFairQueue("test", DefaultMaxSize, false, tenantOf, nil)
+	assert.True(t, q.Add("a/1"))
+	assert.True(t, q.Add("a/1"))
+	assert.Equal(t, 1, q.Len())
+
+	// Items added while being processed should be queued again once done.
+	item, _ := q.Get()
+	assert.True(t, q.Add("a/1"))
+	assert.Equal(t, 0, q.Len())
+	q.Done(item)
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestFairQueue_MaxSize(t *testing.T) {
+	q := NewFairQueue("test", 2, false, tenantOf, nil)
+	assert.True(t, q.Add("a/1"))
+	assert.True(t, q.Add("b/1"))
+	assert.False(t, q.Add("c/1"))
+}
+
+func TestFairQueue_Delaying(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	q := newDelayingQueueFrom(NewFairQueue("test", DefaultMaxSize, false, tenantOf, nil), fakeClock)
+	defer q.ShutDown()
+
+	q.AddAfter("a/1", time.Minute)
+	assert.Equal(t, 0, q.Len())
+
+	fakeClock.Step(time.Minute)
+	for i := 0; i < 100 && q.Len() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, q.Len())
+}
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -157,6 +158,19 @@ func TestWorkflowInvocation(t *testing.T) {
 	util.AssertProtoEqual(t, wiSpec, invocation.Spec)
 	assert.Equal(t, etv.Value, invocation.Status.Output.Value)
 	assert.True(t, invocation.Status.Successful())
+
+	// Test bulk invocation statuses
+	statuses, err := client.Invocation.GetStatuses(ctx, &apiserver.InvocationStatusQuery{
+		Ids: []string{wiId, "nonExistentInvocation"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, statuses.Statuses, 2)
+	assert.Equal(t, wiId, statuses.Statuses[0].Id)
+	assert.Empty(t, statuses.Statuses[0].Error)
+	assert.True(t, statuses.Statuses[0].Status.Successful())
+	assert.Equal(t, "nonExistentInvocation", statuses.Statuses[1].Id)
+	assert.NotEmpty(t, statuses.Statuses[1].Error)
+	assert.Nil(t, statuses.Statuses[1].Status)
 }
 
 func TestDynamicWorkflowInvocation(t *testing.T) {
@@ -359,6 +373,45 @@ func TestParallelInvocation(t *testing.T) {
 	assert.InDelta(t, 0, maxStartTime.Sub(minStartTime).Nanoseconds(), float64(time.Second.Nanoseconds()))
 }
 
+func TestInvocationGroup(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wfSpec := types.NewWorkflowSpec()
+	wfSpec.AddTask("sleep", &types.TaskSpec{
+		FunctionRef: builtin.Sleep,
+		Inputs:      types.Input("10ms"),
+	})
+	wfSpec.SetOutput("sleep")
+	wf, err := client.Workflow.CreateSync(ctx, wfSpec)
+	assert.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	groupID := util.UID()
+	var members []string
+	for i := 0; i < 3; i++ {
+		wiSpec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+		wiSpec.GroupId = groupID
+		wfi, err := client.Invocation.InvokeSync(ctx, wiSpec)
+		assert.NoError(t, err)
+		assert.True(t, wfi.Status.Successful())
+		members = append(members, wfi.ID())
+	}
+
+	group, err := client.Invocation.GetGroup(ctx, &types.ObjectMetadata{Id: groupID})
+	assert.NoError(t, err)
+	assert.Equal(t, groupID, group.Id)
+	sort.Strings(members)
+	assert.Equal(t, members, group.Invocations)
+	assert.Equal(t, int32(len(members)), group.Progress[types.WorkflowInvocationStatus_SUCCEEDED.String()])
+	assert.True(t, group.Finished)
+	assert.True(t, group.Successful)
+
+	_, err = client.Invocation.GetGroup(ctx, &types.ObjectMetadata{Id: "nonExistentGroup"})
+	assert.Error(t, err)
+}
+
 func TestLongRunningWorkflowInvocation(t *testing.T) {
 	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
 	defer cancelFn()
@@ -470,7 +523,7 @@ func TestWorkflowCancellation(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, wfi.GetStatus().Successful())
 	assert.True(t, wfi.GetStatus().Finished())
-	assert.Equal(t, api.ErrInvocationCanceled, wfi.GetStatus().GetError().Error())
+	assert.Contains(t, wfi.GetStatus().GetError().Error(), api.ErrInvocationCallerCanceled)
 }
 
 func TestInvocationInvalid(t *testing.T) {